@@ -19,23 +19,29 @@ const (
 
 // ArgsWebServer holds the arguments needed for a webServer
 type ArgsWebServer struct {
-	Facade    shared.FacadeHandler
-	ApiConfig config.ApiRoutesConfig
+	Facade      shared.FacadeHandler
+	QueryFacade shared.QueryFacadeHandler
+	AdminFacade shared.AdminFacadeHandler
+	ApiConfig   config.ApiRoutesConfig
 }
 
 type webServer struct {
 	sync.RWMutex
-	facade     shared.FacadeHandler
-	apiConfig  config.ApiRoutesConfig
-	groups     map[string]shared.GroupHandler
-	httpServer shared.HttpServerCloser
+	facade      shared.FacadeHandler
+	queryFacade shared.QueryFacadeHandler
+	adminFacade shared.AdminFacadeHandler
+	apiConfig   config.ApiRoutesConfig
+	groups      map[string]shared.GroupHandler
+	httpServer  shared.HttpServerCloser
 }
 
 // NewWebServer will create a new instance of the webServer
 func NewWebServer(args ArgsWebServer) (*webServer, error) {
 	return &webServer{
-		facade:    args.Facade,
-		apiConfig: args.ApiConfig,
+		facade:      args.Facade,
+		queryFacade: args.QueryFacade,
+		adminFacade: args.AdminFacade,
+		apiConfig:   args.ApiConfig,
 	}, nil
 }
 
@@ -91,11 +97,55 @@ func (ws *webServer) createGroups() error {
 	}
 	groupsMap["status"] = statusGroup
 
+	// the query groups are available only when the indexer is connected to an Elasticsearch cluster
+	if !check.IfNil(ws.queryFacade) {
+		err = ws.createQueryGroups(groupsMap)
+		if err != nil {
+			return err
+		}
+	}
+
+	if ws.apiConfig.Admin.Enabled {
+		adminGroup, errCreate := groups.NewAdminGroup(ws.adminFacade, ws.apiConfig.Admin)
+		if errCreate != nil {
+			return errCreate
+		}
+		groupsMap["admin"] = adminGroup
+	}
+
 	ws.groups = groupsMap
 
 	return nil
 }
 
+func (ws *webServer) createQueryGroups(groupsMap map[string]shared.GroupHandler) error {
+	transactionsGroup, err := groups.NewTransactionsGroup(ws.queryFacade)
+	if err != nil {
+		return err
+	}
+	groupsMap["transactions"] = transactionsGroup
+
+	accountsGroup, err := groups.NewAccountsGroup(ws.queryFacade)
+	if err != nil {
+		return err
+	}
+	groupsMap["accounts"] = accountsGroup
+
+	tokensGroup, err := groups.NewTokensGroup(ws.queryFacade)
+	if err != nil {
+		return err
+	}
+	groupsMap["tokens"] = tokensGroup
+
+	blocksGroup, err := groups.NewBlocksGroup(ws.queryFacade)
+	if err != nil {
+		return err
+	}
+	groupsMap["blocks"] = blocksGroup
+
+	return nil
+}
+
 func (ws *webServer) registerRoutes(ginRouter *gin.Engine) {
 	for groupName, groupHandler := range ws.groups {
 		log.Debug("registering gin API group", "group name", groupName)
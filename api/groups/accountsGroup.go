@@ -0,0 +1,93 @@
+package groups
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kalyan3104/k-chain-core-go/core/check"
+	"github.com/kalyan3104/k-chain-es-indexer-go/api/shared"
+	"github.com/kalyan3104/k-chain-es-indexer-go/core"
+)
+
+const (
+	accountByAddressPath    = "/:address"
+	accountTokensPath       = "/:address/tokens"
+	accountTransactionsPath = "/:address/transactions"
+)
+
+type accountsGroup struct {
+	*baseGroup
+	facade shared.QueryFacadeHandler
+}
+
+// NewAccountsGroup returns a new instance of accounts group
+func NewAccountsGroup(facade shared.QueryFacadeHandler) (*accountsGroup, error) {
+	if check.IfNil(facade) {
+		return nil, fmt.Errorf("%w for accounts group", core.ErrNilFacadeHandler)
+	}
+
+	ag := &accountsGroup{
+		facade:    facade,
+		baseGroup: &baseGroup{},
+	}
+
+	endpoints := []*shared.EndpointHandlerData{
+		{
+			Path:    accountByAddressPath,
+			Handler: ag.getAccount,
+			Method:  http.MethodGet,
+		},
+		{
+			Path:    accountTokensPath,
+			Handler: ag.getAccountTokens,
+			Method:  http.MethodGet,
+		},
+		{
+			Path:    accountTransactionsPath,
+			Handler: ag.getAccountTransactions,
+			Method:  http.MethodGet,
+		},
+	}
+	ag.endpoints = endpoints
+
+	return ag, nil
+}
+
+// getAccount will return the account with the provided address
+func (ag *accountsGroup) getAccount(c *gin.Context) {
+	account, err := ag.facade.GetAccount(c.Request.Context(), c.Param("address"))
+
+	returnQueryResult(c, "account", account, err)
+}
+
+// getAccountTokens will return a page with the tokens of the provided address
+func (ag *accountsGroup) getAccountTokens(c *gin.Context) {
+	size, cursor, err := getPagingParams(c)
+	if err != nil {
+		returnQueryResult(c, "", nil, err)
+		return
+	}
+
+	page, err := ag.facade.GetAccountTokens(c.Request.Context(), c.Param("address"), size, cursor)
+
+	returnQueryResult(c, "page", page, err)
+}
+
+// getAccountTransactions will return a page with the transactions of the provided address
+func (ag *accountsGroup) getAccountTransactions(c *gin.Context) {
+	size, cursor, err := getPagingParams(c)
+	if err != nil {
+		returnQueryResult(c, "", nil, err)
+		return
+	}
+
+	page, err := ag.facade.GetAccountTransactions(c.Request.Context(), c.Param("address"), size, cursor)
+
+	returnQueryResult(c, "page", page, err)
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (ag *accountsGroup) IsInterfaceNil() bool {
+	return ag == nil
+}
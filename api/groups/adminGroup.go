@@ -0,0 +1,196 @@
+package groups
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kalyan3104/k-chain-core-go/core/check"
+	"github.com/kalyan3104/k-chain-es-indexer-go/api/shared"
+	"github.com/kalyan3104/k-chain-es-indexer-go/config"
+	"github.com/kalyan3104/k-chain-es-indexer-go/core"
+	"github.com/kalyan3104/k-chain-es-indexer-go/core/request"
+)
+
+const (
+	processingPath       = "/processing"
+	pauseProcessingPath  = "/processing/pause"
+	resumeProcessingPath = "/processing/resume"
+	indicesPath          = "/indices"
+	enableIndexPath      = "/indices/:index/enable"
+	disableIndexPath     = "/indices/:index/disable"
+	revertPath           = "/revert"
+	rollbackPath         = "/rollback"
+	configPath           = "/config"
+)
+
+type adminGroup struct {
+	*baseGroup
+	facade shared.AdminFacadeHandler
+}
+
+// NewAdminGroup returns a new instance of admin group. All its endpoints are protected with basic authentication
+func NewAdminGroup(facade shared.AdminFacadeHandler, adminConfig config.AdminConfig) (*adminGroup, error) {
+	if check.IfNil(facade) {
+		return nil, fmt.Errorf("%w for admin group", core.ErrNilFacadeHandler)
+	}
+	if adminConfig.Username == "" || adminConfig.Password == "" {
+		return nil, core.ErrEmptyAdminCredentials
+	}
+
+	ag := &adminGroup{
+		facade:    facade,
+		baseGroup: &baseGroup{},
+	}
+
+	authMiddleware := []shared.AdditionalMiddleware{
+		{
+			Middleware: gin.BasicAuth(gin.Accounts{adminConfig.Username: adminConfig.Password}),
+			Position:   shared.Before,
+		},
+	}
+
+	endpoints := []*shared.EndpointHandlerData{
+		{
+			Path:                  processingPath,
+			Handler:               ag.getProcessingStatus,
+			Method:                http.MethodGet,
+			AdditionalMiddlewares: authMiddleware,
+		},
+		{
+			Path:                  pauseProcessingPath,
+			Handler:               ag.pauseProcessing,
+			Method:                http.MethodPost,
+			AdditionalMiddlewares: authMiddleware,
+		},
+		{
+			Path:                  resumeProcessingPath,
+			Handler:               ag.resumeProcessing,
+			Method:                http.MethodPost,
+			AdditionalMiddlewares: authMiddleware,
+		},
+		{
+			Path:                  indicesPath,
+			Handler:               ag.getEnabledIndices,
+			Method:                http.MethodGet,
+			AdditionalMiddlewares: authMiddleware,
+		},
+		{
+			Path:                  enableIndexPath,
+			Handler:               ag.enableIndex,
+			Method:                http.MethodPost,
+			AdditionalMiddlewares: authMiddleware,
+		},
+		{
+			Path:                  disableIndexPath,
+			Handler:               ag.disableIndex,
+			Method:                http.MethodPost,
+			AdditionalMiddlewares: authMiddleware,
+		},
+		{
+			Path:                  revertPath,
+			Handler:               ag.revertBlocks,
+			Method:                http.MethodPost,
+			AdditionalMiddlewares: authMiddleware,
+		},
+		{
+			Path:                  rollbackPath,
+			Handler:               ag.rollbackToNonce,
+			Method:                http.MethodPost,
+			AdditionalMiddlewares: authMiddleware,
+		},
+		{
+			Path:                  configPath,
+			Handler:               ag.getConfig,
+			Method:                http.MethodGet,
+			AdditionalMiddlewares: authMiddleware,
+		},
+	}
+	ag.endpoints = endpoints
+
+	return ag, nil
+}
+
+// getProcessingStatus will return whether the processing of the payloads is paused
+func (ag *adminGroup) getProcessingStatus(c *gin.Context) {
+	returnQueryResult(c, "paused", ag.facade.IsProcessingPaused(), nil)
+}
+
+// pauseProcessing will pause the processing of the payloads. The observer does not receive acknowledges while paused
+func (ag *adminGroup) pauseProcessing(c *gin.Context) {
+	ag.facade.PauseProcessing()
+
+	returnQueryResult(c, "paused", ag.facade.IsProcessingPaused(), nil)
+}
+
+// resumeProcessing will resume the processing of the payloads
+func (ag *adminGroup) resumeProcessing(c *gin.Context) {
+	ag.facade.ResumeProcessing()
+
+	returnQueryResult(c, "paused", ag.facade.IsProcessingPaused(), nil)
+}
+
+// getEnabledIndices will return the indices that are currently enabled
+func (ag *adminGroup) getEnabledIndices(c *gin.Context) {
+	returnQueryResult(c, "indices", ag.facade.GetEnabledIndices(), nil)
+}
+
+// enableIndex will enable the indexing of the provided index
+func (ag *adminGroup) enableIndex(c *gin.Context) {
+	err := ag.facade.EnableIndex(c.Param("index"))
+	if err != nil {
+		returnQueryResult(c, "", nil, err)
+		return
+	}
+
+	returnQueryResult(c, "indices", ag.facade.GetEnabledIndices(), nil)
+}
+
+// disableIndex will disable the indexing of the provided index
+func (ag *adminGroup) disableIndex(c *gin.Context) {
+	err := ag.facade.DisableIndex(c.Param("index"))
+	if err != nil {
+		returnQueryResult(c, "", nil, err)
+		return
+	}
+
+	returnQueryResult(c, "indices", ag.facade.GetEnabledIndices(), nil)
+}
+
+// revertBlocks will remove the data indexed for the blocks of a shard with the nonce in the provided range
+func (ag *adminGroup) revertBlocks(c *gin.Context) {
+	revertRequest := request.RevertRangeRequest{}
+	err := c.ShouldBindJSON(&revertRequest)
+	if err != nil {
+		returnQueryResult(c, "", nil, fmt.Errorf("%w: %s", core.ErrInvalidQueryParameter, err.Error()))
+		return
+	}
+
+	numReverted, err := ag.facade.RevertBlocksInRange(revertRequest)
+
+	returnQueryResult(c, "reverted_blocks", numReverted, err)
+}
+
+// rollbackToNonce will remove the data indexed for the blocks of a shard with the nonce greater than the provided one
+func (ag *adminGroup) rollbackToNonce(c *gin.Context) {
+	rollbackRequest := request.RollbackRequest{}
+	err := c.ShouldBindJSON(&rollbackRequest)
+	if err != nil {
+		returnQueryResult(c, "", nil, fmt.Errorf("%w: %s", core.ErrInvalidQueryParameter, err.Error()))
+		return
+	}
+
+	response, err := ag.facade.RollbackToNonce(rollbackRequest)
+
+	returnQueryResult(c, "rollback", response, err)
+}
+
+// getConfig will return the configuration used at runtime
+func (ag *adminGroup) getConfig(c *gin.Context) {
+	returnQueryResult(c, "config", ag.facade.GetEffectiveConfig(), nil)
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (ag *adminGroup) IsInterfaceNil() bool {
+	return ag == nil
+}
@@ -0,0 +1,67 @@
+package groups
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kalyan3104/k-chain-core-go/core/check"
+	"github.com/kalyan3104/k-chain-es-indexer-go/api/shared"
+	"github.com/kalyan3104/k-chain-es-indexer-go/core"
+)
+
+const (
+	blockByShardAndNoncePath = "/:shard/:nonce"
+)
+
+type blocksGroup struct {
+	*baseGroup
+	facade shared.QueryFacadeHandler
+}
+
+// NewBlocksGroup returns a new instance of blocks group
+func NewBlocksGroup(facade shared.QueryFacadeHandler) (*blocksGroup, error) {
+	if check.IfNil(facade) {
+		return nil, fmt.Errorf("%w for blocks group", core.ErrNilFacadeHandler)
+	}
+
+	bg := &blocksGroup{
+		facade:    facade,
+		baseGroup: &baseGroup{},
+	}
+
+	endpoints := []*shared.EndpointHandlerData{
+		{
+			Path:    blockByShardAndNoncePath,
+			Handler: bg.getBlock,
+			Method:  http.MethodGet,
+		},
+	}
+	bg.endpoints = endpoints
+
+	return bg, nil
+}
+
+// getBlock will return the block with the provided nonce from the provided shard
+func (bg *blocksGroup) getBlock(c *gin.Context) {
+	shardID, err := strconv.ParseUint(c.Param("shard"), 10, 32)
+	if err != nil {
+		returnQueryResult(c, "", nil, fmt.Errorf("%w: shard", core.ErrInvalidQueryParameter))
+		return
+	}
+	nonce, err := strconv.ParseUint(c.Param("nonce"), 10, 64)
+	if err != nil {
+		returnQueryResult(c, "", nil, fmt.Errorf("%w: nonce", core.ErrInvalidQueryParameter))
+		return
+	}
+
+	block, err := bg.facade.GetBlock(c.Request.Context(), uint32(shardID), nonce)
+
+	returnQueryResult(c, "block", block, err)
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (bg *blocksGroup) IsInterfaceNil() bool {
+	return bg == nil
+}
@@ -0,0 +1,51 @@
+package groups
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kalyan3104/k-chain-es-indexer-go/api/shared"
+	"github.com/kalyan3104/k-chain-es-indexer-go/core"
+)
+
+const (
+	sizeParam   = "size"
+	cursorParam = "cursor"
+)
+
+// getPagingParams will return the page size and the cursor provided as query parameters
+func getPagingParams(c *gin.Context) (int, string, error) {
+	size := 0
+	sizeStr := c.Query(sizeParam)
+	if sizeStr != "" {
+		var err error
+		size, err = strconv.Atoi(sizeStr)
+		if err != nil || size < 0 {
+			return 0, "", fmt.Errorf("%w: %s", core.ErrInvalidQueryParameter, sizeParam)
+		}
+	}
+
+	return size, c.Query(cursorParam), nil
+}
+
+// returnQueryResult will write the result of a query or the error which occurred, using the http status that
+// corresponds to the error
+func returnQueryResult(c *gin.Context, key string, result interface{}, err error) {
+	if err == nil {
+		returnStatus(c, gin.H{key: result}, http.StatusOK, "", string(shared.ReturnCodeSuccess))
+		return
+	}
+
+	switch {
+	case errors.Is(err, core.ErrDocumentNotFound):
+		returnStatus(c, nil, http.StatusNotFound, err.Error(), string(shared.ReturnCodeNotFound))
+	case errors.Is(err, core.ErrInvalidQueryParameter):
+		returnStatus(c, nil, http.StatusBadRequest, err.Error(), string(shared.ReturnCodeRequestError))
+	default:
+		log.Debug("query request failed", "path", c.FullPath(), "error", err)
+		returnStatus(c, nil, http.StatusInternalServerError, err.Error(), string(shared.ReturnCodeInternalError))
+	}
+}
@@ -3,6 +3,7 @@ package groups
 import (
 	"fmt"
 	"net/http"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/kalyan3104/k-chain-core-go/core/check"
@@ -13,6 +14,8 @@ import (
 const (
 	metricsPath           = "/metrics"
 	prometheusMetricsPath = "/prometheus-metrics"
+	healthPath            = "/health"
+	readyPath             = "/ready"
 )
 
 type statusGroup struct {
@@ -42,6 +45,16 @@ func NewStatusGroup(facade shared.FacadeHandler) (*statusGroup, error) {
 			Handler: sg.getPrometheusMetrics,
 			Method:  http.MethodGet,
 		},
+		{
+			Path:    healthPath,
+			Handler: sg.getHealth,
+			Method:  http.MethodGet,
+		},
+		{
+			Path:    readyPath,
+			Handler: sg.getReadiness,
+			Method:  http.MethodGet,
+		},
 	}
 	sg.endpoints = endpoints
 
@@ -57,9 +70,27 @@ func (sg *statusGroup) getMetrics(c *gin.Context) {
 
 // getPrometheusMetrics will expose proxy metrics in prometheus format
 func (sg *statusGroup) getPrometheusMetrics(c *gin.Context) {
-	metricsResults := sg.facade.GetMetricsForPrometheus()
+	sg.facade.GetPrometheusHandler().ServeHTTP(c.Writer, c.Request)
+}
+
+// getHealth will expose the state of the indexer and of its dependencies. The endpoint answers with 200 as long as
+// the process is alive, so it can be used as a liveness probe
+func (sg *statusGroup) getHealth(c *gin.Context) {
+	health := sg.facade.GetHealth()
+
+	returnStatus(c, gin.H{"health": health}, http.StatusOK, "", string(shared.ReturnCodeSuccess))
+}
+
+// getReadiness will answer with 503 when the indexer is not able to serve up-to-date data, so it can be used as a
+// readiness probe
+func (sg *statusGroup) getReadiness(c *gin.Context) {
+	health := sg.facade.GetHealth()
+	if !health.Ready {
+		returnStatus(c, gin.H{"health": health}, http.StatusServiceUnavailable, strings.Join(health.Reasons, ", "), string(shared.ReturnCodeNotReady))
+		return
+	}
 
-	c.String(http.StatusOK, metricsResults)
+	returnStatus(c, gin.H{"health": health}, http.StatusOK, "", string(shared.ReturnCodeSuccess))
 }
 
 // IsInterfaceNil returns true if there is no value under the interface
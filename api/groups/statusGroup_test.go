@@ -0,0 +1,171 @@
+package groups
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kalyan3104/k-chain-es-indexer-go/api/shared"
+	"github.com/kalyan3104/k-chain-es-indexer-go/config"
+	"github.com/kalyan3104/k-chain-es-indexer-go/core"
+	"github.com/kalyan3104/k-chain-es-indexer-go/core/request"
+	"github.com/kalyan3104/k-chain-es-indexer-go/metrics"
+	"github.com/kalyan3104/k-chain-es-indexer-go/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type healthResponse struct {
+	Data struct {
+		Health *request.HealthResponse `json:"health"`
+	} `json:"data"`
+	Error string `json:"error"`
+	Code  string `json:"code"`
+}
+
+func createStatusRoutesConfig() config.ApiRoutesConfig {
+	return config.ApiRoutesConfig{
+		APIPackages: map[string]config.APIPackageConfig{
+			"status": {
+				Routes: []config.RouteConfig{
+					{Name: healthPath, Open: true},
+					{Name: readyPath, Open: true},
+					{Name: prometheusMetricsPath, Open: true},
+				},
+			},
+		},
+	}
+}
+
+func startStatusGroup(t *testing.T, health *request.HealthResponse) *gin.Engine {
+	statusGroupInstance, err := NewStatusGroup(&mock.FacadeStub{
+		GetHealthCalled: func() *request.HealthResponse {
+			return health
+		},
+	})
+	require.Nil(t, err)
+
+	gin.SetMode(gin.TestMode)
+	ws := gin.New()
+	statusGroupInstance.RegisterRoutes(ws.Group("/status"), createStatusRoutesConfig())
+
+	return ws
+}
+
+func doStatusRequest(t *testing.T, ws *gin.Engine, path string) (int, *healthResponse) {
+	req, _ := http.NewRequest(http.MethodGet, "/status"+path, nil)
+	resp := httptest.NewRecorder()
+	ws.ServeHTTP(resp, req)
+
+	response := &healthResponse{}
+	err := json.Unmarshal(resp.Body.Bytes(), response)
+	require.Nil(t, err)
+
+	return resp.Code, response
+}
+
+func TestNewStatusGroup(t *testing.T) {
+	t.Parallel()
+
+	statusGroupInstance, err := NewStatusGroup(nil)
+	require.Nil(t, statusGroupInstance)
+	require.True(t, errors.Is(err, core.ErrNilFacadeHandler))
+
+	statusGroupInstance, err = NewStatusGroup(&mock.FacadeStub{})
+	require.Nil(t, err)
+	require.NotNil(t, statusGroupInstance)
+}
+
+func TestStatusGroup_HealthAndReadiness(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name                  string
+		health                *request.HealthResponse
+		path                  string
+		expectedStatus        int
+		expectedCode          shared.ReturnCode
+		expectedError         string
+		expectedHealthyReport bool
+	}{
+		{
+			name:                  "health of a ready indexer",
+			health:                &request.HealthResponse{Ready: true},
+			path:                  healthPath,
+			expectedStatus:        http.StatusOK,
+			expectedCode:          shared.ReturnCodeSuccess,
+			expectedHealthyReport: true,
+		},
+		{
+			name:           "health of an indexer which is not ready should still answer with ok",
+			health:         &request.HealthResponse{Ready: false, Reasons: []string{"elasticsearch is not reachable"}},
+			path:           healthPath,
+			expectedStatus: http.StatusOK,
+			expectedCode:   shared.ReturnCodeSuccess,
+		},
+		{
+			name:                  "readiness of a ready indexer",
+			health:                &request.HealthResponse{Ready: true},
+			path:                  readyPath,
+			expectedStatus:        http.StatusOK,
+			expectedCode:          shared.ReturnCodeSuccess,
+			expectedHealthyReport: true,
+		},
+		{
+			name: "readiness of an indexer which is not ready",
+			health: &request.HealthResponse{
+				Ready:   false,
+				Reasons: []string{"elasticsearch cluster health is red", "shard 1 lags behind with 61 seconds"},
+			},
+			path:           readyPath,
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedCode:   shared.ReturnCodeNotReady,
+			expectedError:  "elasticsearch cluster health is red, shard 1 lags behind with 61 seconds",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ws := startStatusGroup(t, tt.health)
+
+			status, response := doStatusRequest(t, ws, tt.path)
+			require.Equal(t, tt.expectedStatus, status)
+			require.Equal(t, string(tt.expectedCode), response.Code)
+			require.Equal(t, tt.expectedError, response.Error)
+			require.Equal(t, tt.expectedHealthyReport, response.Data.Health.Ready)
+			require.Equal(t, tt.health.Reasons, response.Data.Health.Reasons)
+		})
+	}
+}
+
+func TestStatusGroup_PrometheusMetricsShouldBeWrittenInTheTextFormat(t *testing.T) {
+	t.Parallel()
+
+	statusMetrics := metrics.NewStatusMetrics()
+	statusMetrics.AddIndexingData(metrics.ArgsAddIndexingData{Topic: "req_bulk_0", MessageLen: 10})
+	statusMetrics.AddWrittenDocuments("transactions", 2)
+
+	statusGroupInstance, err := NewStatusGroup(&mock.FacadeStub{
+		GetPrometheusHandlerCalled: statusMetrics.GetPrometheusHandler,
+	})
+	require.Nil(t, err)
+
+	gin.SetMode(gin.TestMode)
+	ws := gin.New()
+	statusGroupInstance.RegisterRoutes(ws.Group("/status"), createStatusRoutesConfig())
+
+	req, _ := http.NewRequest(http.MethodGet, "/status"+prometheusMetricsPath, nil)
+	// the registry should not answer in the protobuf format, since the metrics of the topics are written as text
+	req.Header.Set("Accept", "application/vnd.google.protobuf;proto=io.prometheus.client.MetricFamily;encoding=delimited")
+	resp := httptest.NewRecorder()
+	ws.ServeHTTP(resp, req)
+
+	require.Equal(t, http.StatusOK, resp.Code)
+	require.Contains(t, resp.Body.String(), `req_bulk{operation="total_data",shardID="0"} 10`)
+	require.Contains(t, resp.Body.String(), `documents_written_total{index="transactions"} 2`)
+}
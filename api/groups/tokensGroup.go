@@ -0,0 +1,55 @@
+package groups
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kalyan3104/k-chain-core-go/core/check"
+	"github.com/kalyan3104/k-chain-es-indexer-go/api/shared"
+	"github.com/kalyan3104/k-chain-es-indexer-go/core"
+)
+
+const (
+	tokenByIdentifierPath = "/:identifier"
+)
+
+type tokensGroup struct {
+	*baseGroup
+	facade shared.QueryFacadeHandler
+}
+
+// NewTokensGroup returns a new instance of tokens group
+func NewTokensGroup(facade shared.QueryFacadeHandler) (*tokensGroup, error) {
+	if check.IfNil(facade) {
+		return nil, fmt.Errorf("%w for tokens group", core.ErrNilFacadeHandler)
+	}
+
+	tg := &tokensGroup{
+		facade:    facade,
+		baseGroup: &baseGroup{},
+	}
+
+	endpoints := []*shared.EndpointHandlerData{
+		{
+			Path:    tokenByIdentifierPath,
+			Handler: tg.getToken,
+			Method:  http.MethodGet,
+		},
+	}
+	tg.endpoints = endpoints
+
+	return tg, nil
+}
+
+// getToken will return the token with the provided identifier
+func (tg *tokensGroup) getToken(c *gin.Context) {
+	token, err := tg.facade.GetToken(c.Request.Context(), c.Param("identifier"))
+
+	returnQueryResult(c, "token", token, err)
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (tg *tokensGroup) IsInterfaceNil() bool {
+	return tg == nil
+}
@@ -1,9 +1,13 @@
 package shared
 
 import (
+	"context"
+	"net/http"
+
 	"github.com/gin-gonic/gin"
 	"github.com/kalyan3104/k-chain-es-indexer-go/config"
 	"github.com/kalyan3104/k-chain-es-indexer-go/core/request"
+	"github.com/kalyan3104/k-chain-es-indexer-go/data"
 )
 
 // GroupHandler defines the actions needed to be performed by a gin API group
@@ -18,7 +22,33 @@ type GroupHandler interface {
 // FacadeHandler defines all the methods that a facade should implement
 type FacadeHandler interface {
 	GetMetrics() map[string]*request.MetricsResponse
-	GetMetricsForPrometheus() string
+	GetPrometheusHandler() http.Handler
+	GetHealth() *request.HealthResponse
+	IsInterfaceNil() bool
+}
+
+// QueryFacadeHandler defines all the methods that a facade which reads the indexed data should implement
+type QueryFacadeHandler interface {
+	GetTransaction(ctx context.Context, hash string) (*data.ApiTransaction, error)
+	GetAccount(ctx context.Context, address string) (*data.AccountInfo, error)
+	GetAccountTokens(ctx context.Context, address string, size int, cursor string) (*data.ApiAccountTokensPage, error)
+	GetAccountTransactions(ctx context.Context, address string, size int, cursor string) (*data.ApiTransactionsPage, error)
+	GetToken(ctx context.Context, identifier string) (*data.TokenInfo, error)
+	GetBlock(ctx context.Context, shardID uint32, nonce uint64) (*data.ApiBlock, error)
+	IsInterfaceNil() bool
+}
+
+// AdminFacadeHandler defines all the methods that a facade which controls the indexer at runtime should implement
+type AdminFacadeHandler interface {
+	PauseProcessing()
+	ResumeProcessing()
+	IsProcessingPaused() bool
+	GetEnabledIndices() []string
+	EnableIndex(index string) error
+	DisableIndex(index string) error
+	RevertBlocksInRange(revertRequest request.RevertRangeRequest) (uint64, error)
+	RollbackToNonce(rollbackRequest request.RollbackRequest) (*request.RollbackResponse, error)
+	GetEffectiveConfig() *request.EffectiveConfigResponse
 	IsInterfaceNil() bool
 }
 
@@ -2,6 +2,26 @@ package shared
 
 import "github.com/gin-gonic/gin"
 
+// ReturnCode defines the type for the codes returned by the API endpoints
+type ReturnCode string
+
+const (
+	// ReturnCodeSuccess defines a successful request
+	ReturnCodeSuccess ReturnCode = "successful"
+
+	// ReturnCodeRequestError defines a request which hasn't been executed successfully due to a bad request received
+	ReturnCodeRequestError ReturnCode = "bad_request"
+
+	// ReturnCodeNotFound defines a request for a document that does not exist
+	ReturnCodeNotFound ReturnCode = "not_found"
+
+	// ReturnCodeInternalError defines a request which hasn't been executed successfully due to an internal error
+	ReturnCodeInternalError ReturnCode = "internal_issue"
+
+	// ReturnCodeNotReady defines a readiness request answered while the indexer is not able to serve traffic
+	ReturnCodeNotReady ReturnCode = "not_ready"
+)
+
 // MiddlewarePosition is the type that specifies the position of a middleware relative to the base endpoint handler
 type MiddlewarePosition bool
 
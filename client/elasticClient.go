@@ -10,6 +10,8 @@ import (
 
 	"github.com/elastic/go-elasticsearch/v7"
 	"github.com/elastic/go-elasticsearch/v7/esapi"
+	"github.com/kalyan3104/k-chain-core-go/core/check"
+	"github.com/kalyan3104/k-chain-es-indexer-go/core"
 	"github.com/kalyan3104/k-chain-es-indexer-go/data"
 	"github.com/kalyan3104/k-chain-es-indexer-go/process/dataindexer"
 	logger "github.com/kalyan3104/k-chain-logger-go"
@@ -30,8 +32,9 @@ type (
 )
 
 type elasticClient struct {
-	elasticBaseUrl string
-	client         *elasticsearch.Client
+	elasticBaseUrl          string
+	client                  *elasticsearch.Client
+	writtenDocumentsHandler WrittenDocumentsHandler
 
 	// countScroll is used to be incremented after each scroll so the scroll duration is different each time,
 	// bypassing any possible caching based on the same request
@@ -57,6 +60,18 @@ func NewElasticClient(cfg elasticsearch.Config) (*elasticClient, error) {
 	return ec, nil
 }
 
+// SetWrittenDocumentsHandler will set the component that records, for every index, the documents successfully
+// written by the bulk requests
+func (ec *elasticClient) SetWrittenDocumentsHandler(handler WrittenDocumentsHandler) error {
+	if check.IfNil(handler) {
+		return core.ErrNilMetricsHandler
+	}
+
+	ec.writtenDocumentsHandler = handler
+
+	return nil
+}
+
 // CheckAndCreateTemplate creates an index template if it does not already exist
 func (ec *elasticClient) CheckAndCreateTemplate(templateName string, template *bytes.Buffer) error {
 	if ec.templateExists(templateName) {
@@ -114,7 +129,7 @@ func (ec *elasticClient) DoBulkRequest(ctx context.Context, buff *bytes.Buffer,
 		return err
 	}
 
-	return elasticBulkRequestResponseHandler(res)
+	return elasticBulkRequestResponseHandler(res, ec.writtenDocumentsHandler)
 }
 
 // DoMultiGet wil do a multi get request to Elasticsearch server
@@ -146,11 +161,34 @@ func (ec *elasticClient) DoMultiGet(ctx context.Context, ids []string, index str
 	return nil
 }
 
+// DoSearchRequest will perform a search request and will decode the response in the provided structure
+func (ec *elasticClient) DoSearchRequest(ctx context.Context, index string, body []byte, resBody interface{}) error {
+	res, err := ec.client.Search(
+		ec.client.Search.WithIndex(index),
+		ec.client.Search.WithBody(bytes.NewBuffer(body)),
+		ec.client.Search.WithContext(ctx),
+	)
+	if err != nil {
+		log.Warn("elasticClient.DoSearchRequest",
+			"cannot do search request no response", err.Error())
+		return err
+	}
+
+	err = parseResponse(res, &resBody, elasticDefaultErrorResponseHandler)
+	if err != nil {
+		log.Warn("elasticClient.DoSearchRequest",
+			"error parsing response", err.Error())
+		return err
+	}
+
+	return nil
+}
+
 // DoQueryRemove will do a query remove to elasticsearch server
 func (ec *elasticClient) DoQueryRemove(ctx context.Context, index string, body *bytes.Buffer) error {
-	err := ec.doRefresh(index)
+	err := ec.DoRefreshRequest(ctx, index)
 	if err != nil {
-		log.Warn("elasticClient.doRefresh", "cannot do refresh", err)
+		log.Warn("elasticClient.DoRefreshRequest", "cannot do refresh", err)
 	}
 
 	res, err := ec.client.DeleteByQuery(
@@ -175,10 +213,12 @@ func (ec *elasticClient) DoQueryRemove(ctx context.Context, index string, body *
 	return nil
 }
 
-func (ec *elasticClient) doRefresh(index string) error {
+// DoRefreshRequest will refresh the provided index, so the documents written before become visible for searches and scrolls
+func (ec *elasticClient) DoRefreshRequest(ctx context.Context, index string) error {
 	res, err := ec.client.Indices.Refresh(
 		ec.client.Indices.Refresh.WithIndex(index),
 		ec.client.Indices.Refresh.WithIgnoreUnavailable(true),
+		ec.client.Indices.Refresh.WithContext(ctx),
 	)
 	if err != nil {
 		return err
@@ -341,6 +381,24 @@ func (ec *elasticClient) UpdateByQuery(ctx context.Context, index string, buff *
 	return parseResponse(res, nil, elasticDefaultErrorResponseHandler)
 }
 
+// GetClusterHealth will return the health status of the cluster: green, yellow or red
+func (ec *elasticClient) GetClusterHealth(ctx context.Context) (string, error) {
+	res, err := ec.client.Cluster.Health(
+		ec.client.Cluster.Health.WithContext(ctx),
+	)
+	if err != nil {
+		return "", err
+	}
+
+	clusterHealth := &data.ClusterHealth{}
+	err = parseResponse(res, clusterHealth, elasticDefaultErrorResponseHandler)
+	if err != nil {
+		return "", err
+	}
+
+	return clusterHealth.Status, nil
+}
+
 // IsInterfaceNil returns true if there is no value under the interface
 func (ec *elasticClient) IsInterfaceNil() bool {
 	return ec == nil
@@ -11,6 +11,7 @@ import (
 	"strings"
 
 	"github.com/elastic/go-elasticsearch/v7/esapi"
+	"github.com/kalyan3104/k-chain-core-go/core/check"
 	"github.com/kalyan3104/k-chain-es-indexer-go/data"
 	"github.com/kalyan3104/k-chain-es-indexer-go/process/dataindexer"
 )
@@ -91,7 +92,7 @@ func elasticDefaultErrorResponseHandler(res *esapi.Response) error {
 		res.StatusCode, responseBody, string(bodyBytes))
 }
 
-func elasticBulkRequestResponseHandler(res *esapi.Response) error {
+func elasticBulkRequestResponseHandler(res *esapi.Response, writtenDocumentsHandler WrittenDocumentsHandler) error {
 	if res.IsError() {
 		return fmt.Errorf("%s", res.String())
 	}
@@ -101,10 +102,12 @@ func elasticBulkRequestResponseHandler(res *esapi.Response) error {
 		return fmt.Errorf("%w cannot read elastic response body bytes", err)
 	}
 
-	return extractErrorFromBulkBodyResponseBytes(bodyBytes)
+	return extractErrorFromBulkBodyResponseBytes(bodyBytes, writtenDocumentsHandler)
 }
 
-func extractErrorFromBulkBodyResponseBytes(bodyBytes []byte) error {
+// extractErrorFromBulkBodyResponseBytes returns the errors of the first failed items from the bulk response and
+// records, for every index, the documents that were indexed or updated successfully
+func extractErrorFromBulkBodyResponseBytes(bodyBytes []byte, writtenDocumentsHandler WrittenDocumentsHandler) error {
 	response := BulkRequestResponse{}
 	err := json.Unmarshal(bodyBytes, &response)
 	if err != nil {
@@ -113,6 +116,7 @@ func extractErrorFromBulkBodyResponseBytes(bodyBytes []byte) error {
 
 	count := 0
 	errorsString := ""
+	writtenDocuments := make(map[string]uint64)
 	for _, item := range response.Items {
 		var selectedItem Item
 
@@ -121,6 +125,8 @@ func extractErrorFromBulkBodyResponseBytes(bodyBytes []byte) error {
 			selectedItem = *item.ItemIndex
 		case item.ItemUpdate != nil:
 			selectedItem = *item.ItemUpdate
+		default:
+			continue
 		}
 
 		log.Trace("worked on", "index", selectedItem.Index,
@@ -130,15 +136,21 @@ func extractErrorFromBulkBodyResponseBytes(bodyBytes []byte) error {
 		)
 
 		if selectedItem.Status < http.StatusBadRequest {
+			writtenDocuments[strings.TrimSuffix(selectedItem.Index, "-"+dataindexer.IndexSuffix)]++
+			continue
+		}
+		if count == numOfErrorsToExtractBulkResponse {
 			continue
 		}
 
 		count++
 		errorsString += fmt.Sprintf(`{ "index": "%s", "id": "%s", "statusCode": %d, "errorType": "%s", "reason": "%s", "causedBy": { "type": "%s", "reason": "%s" }}\n`,
 			selectedItem.Index, selectedItem.ID, selectedItem.Status, selectedItem.Error.Type, selectedItem.Error.Reason, selectedItem.Error.Cause.Type, selectedItem.Error.Cause.Reason)
+	}
 
-		if count == numOfErrorsToExtractBulkResponse {
-			break
+	if !check.IfNil(writtenDocumentsHandler) {
+		for index, numDocuments := range writtenDocuments {
+			writtenDocumentsHandler.AddWrittenDocuments(index, numDocuments)
 		}
 	}
 	if errorsString == "" {
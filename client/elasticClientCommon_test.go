@@ -5,10 +5,12 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/http/httptest"
 	"strings"
 	"testing"
 
 	"github.com/elastic/go-elasticsearch/v7/esapi"
+	"github.com/kalyan3104/k-chain-es-indexer-go/metrics"
 	"github.com/kalyan3104/k-chain-es-indexer-go/mock"
 	"github.com/kalyan3104/k-chain-es-indexer-go/process/dataindexer"
 	"github.com/stretchr/testify/assert"
@@ -144,13 +146,34 @@ func createMockEsapiResponseWithText(str string) *esapi.Response {
 func TestExtractErrorFromBulkBodyResponseBytesUpdate(t *testing.T) {
 	responseBytes := []byte(`{"took":39,"errors":true,"items":[{"update":{"_index":"transactions-000001","_type":"_doc","_id":"76c11e808085df75b21ae3196b9a7b533a15a346ab79346d81795f5131ae66fa","status":409,"error":{"type":"version_conflict_engine_exception","reason":"[76c11e808085df75b21ae3196b9a7b533a15a346ab79346d81795f5131ae66fa]: version conflict, required seqNo [1904], primary term [1]. current document has seqNo [1975] and primary term [1]","index_uuid":"_mEW9HB_QiSbIvkbythJ7Q","shard":"2","index":"transactions-000001"}}}]}`)
 
-	err := extractErrorFromBulkBodyResponseBytes(responseBytes)
+	err := extractErrorFromBulkBodyResponseBytes(responseBytes, nil)
 	require.NotNil(t, err)
 }
 
 func TestExtractErrorFromBulkBodyResponseBytesIndex(t *testing.T) {
 	responseBytes := []byte(`{"took":39,"errors":true,"items":[{"index":{"_index":"transactions-000001","_type":"_doc","_id":"76c11e808085df75b21ae3196b9a7b533a15a346ab79346d81795f5131ae66fa","status":409,"error":{"type":"version_conflict_engine_exception","reason":"[76c11e808085df75b21ae3196b9a7b533a15a346ab79346d81795f5131ae66fa]: version conflict, required seqNo [1904], primary term [1]. current document has seqNo [1975] and primary term [1]","index_uuid":"_mEW9HB_QiSbIvkbythJ7Q","shard":"2","index":"transactions-000001"}}}]}`)
 
-	err := extractErrorFromBulkBodyResponseBytes(responseBytes)
+	err := extractErrorFromBulkBodyResponseBytes(responseBytes, nil)
 	require.NotNil(t, err)
 }
+
+func TestExtractErrorFromBulkBodyResponseBytesShouldCountWrittenDocuments(t *testing.T) {
+	t.Parallel()
+
+	responseBytes := []byte(`{"errors":true,"items":[` +
+		`{"index":{"_index":"transactions-000001","status":201}},` +
+		`{"index":{"_index":"transactions-000001","status":400}},` +
+		`{"update":{"_index":"accounts-000001","status":200}},` +
+		`{"delete":{"_index":"logs-000001","status":200}}]}`)
+
+	statusMetrics := metrics.NewStatusMetrics()
+	err := extractErrorFromBulkBodyResponseBytes(responseBytes, statusMetrics)
+	require.NotNil(t, err)
+
+	recorder := httptest.NewRecorder()
+	statusMetrics.GetPrometheusHandler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/status/prometheus-metrics", nil))
+	prometheusMetrics := recorder.Body.String()
+	require.Contains(t, prometheusMetrics, `documents_written_total{index="accounts"} 1`)
+	require.Contains(t, prometheusMetrics, `documents_written_total{index="transactions"} 1`)
+	require.NotContains(t, prometheusMetrics, `documents_written_total{index="logs"}`)
+}
@@ -75,3 +75,76 @@ func TestElasticClient_DoMultiGet(t *testing.T) {
 	_, ok := resMap["docs"]
 	require.True(t, ok)
 }
+
+func TestElasticClient_DoSearchRequest(t *testing.T) {
+	handler := http.NotFound
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		handler(w, r)
+	}))
+	defer ts.Close()
+
+	handler = func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/transactions/_search", r.URL.Path)
+
+		jsonFile, err := os.Open("./testsData/response-search.json")
+		require.Nil(t, err)
+
+		byteValue, _ := ioutil.ReadAll(jsonFile)
+		_, _ = w.Write(byteValue)
+	}
+
+	esClient, _ := NewElasticClient(elasticsearch.Config{
+		Addresses: []string{ts.URL},
+		Logger:    &logging.CustomLogger{},
+	})
+
+	res := &data.ResponseSearch{}
+	err := esClient.DoSearchRequest(context.Background(), "transactions", []byte(`{"query":{"match_all":{}}}`), res)
+	require.Nil(t, err)
+	require.Len(t, res.Hits.Hits, 2)
+	require.Equal(t, "h1", res.Hits.Hits[0].ID)
+	require.Equal(t, `"erd1a"`, string(res.Hits.Hits[0].Sort[1]))
+}
+
+func TestElasticClient_DoRefreshRequest(t *testing.T) {
+	refreshed := false
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/journal/_refresh", r.URL.Path)
+		refreshed = true
+
+		_, _ = w.Write([]byte(`{"_shards":{"total":1,"successful":1,"failed":0}}`))
+	}))
+	defer ts.Close()
+
+	esClient, _ := NewElasticClient(elasticsearch.Config{
+		Addresses: []string{ts.URL},
+		Logger:    &logging.CustomLogger{},
+	})
+
+	err := esClient.DoRefreshRequest(context.Background(), "journal")
+	require.Nil(t, err)
+	require.True(t, refreshed)
+}
+
+func TestElasticClient_GetClusterHealth(t *testing.T) {
+	handler := http.NotFound
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		handler(w, r)
+	}))
+	defer ts.Close()
+
+	handler = func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/_cluster/health", r.URL.Path)
+
+		_, _ = w.Write([]byte(`{"cluster_name":"indexer","status":"yellow"}`))
+	}
+
+	esClient, _ := NewElasticClient(elasticsearch.Config{
+		Addresses: []string{ts.URL},
+		Logger:    &logging.CustomLogger{},
+	})
+
+	clusterHealth, err := esClient.GetClusterHealth(context.Background())
+	require.Nil(t, err)
+	require.Equal(t, "yellow", clusterHealth)
+}
@@ -0,0 +1,8 @@
+package client
+
+// WrittenDocumentsHandler defines the actions that a component which records the documents written by the bulk
+// requests should do
+type WrittenDocumentsHandler interface {
+	AddWrittenDocuments(index string, numDocuments uint64)
+	IsInterfaceNil() bool
+}
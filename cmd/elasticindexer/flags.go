@@ -47,4 +47,35 @@ var (
 		Name:  "disable-ansi-color",
 		Usage: "Boolean option for disabling ANSI colors in the logging system.",
 	}
+
+	// shardIDFlag defines the shard of the blocks handled by the maintenance commands
+	shardIDFlag = cli.Uint64Flag{
+		Name:  "shard",
+		Usage: "The shard of the blocks. The metachain is 4294967295",
+	}
+	backfillFromNonce = cli.Uint64Flag{
+		Name:  "from-nonce",
+		Usage: "The nonce of the first block to be indexed again",
+	}
+	backfillToNonce = cli.Uint64Flag{
+		Name:  "to-nonce",
+		Usage: "The nonce of the last block to be indexed again",
+	}
+	backfillSource = cli.StringFlag{
+		Name: "source",
+		Usage: "The directory with the block recordings or the base URL of an HTTP endpoint serving them. If not set, " +
+			"the source from the main configuration file is used",
+	}
+	rollbackNonce = cli.Uint64Flag{
+		Name:  "nonce",
+		Usage: "The nonce of the last block that is kept. The data indexed for the blocks of the shard with a greater nonce is removed",
+	}
+	verifyFromNonce = cli.Uint64Flag{
+		Name:  "from-nonce",
+		Usage: "The nonce of the first block to be verified",
+	}
+	verifyToNonce = cli.Uint64Flag{
+		Name:  "to-nonce",
+		Usage: "The nonce of the last block to be verified",
+	}
 )
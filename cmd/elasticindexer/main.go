@@ -25,8 +25,11 @@ var (
 	helpTemplate = `NAME:
    {{.Name}} - {{.Usage}}
 USAGE:
-   {{.HelpName}} {{if .VisibleFlags}}[global options]{{end}}
-   {{if len .Authors}}
+   {{.HelpName}} {{if .VisibleFlags}}[global options]{{end}}{{if .VisibleCommands}} [command [command options]]{{end}}
+   {{if .VisibleCommands}}
+COMMANDS:
+   {{range .VisibleCommands}}{{join .Names ", "}}{{"\t"}}{{.Usage}}
+   {{end}}{{end}}{{if len .Authors}}
 AUTHOR:
    {{range .Authors}}{{ . }}{{end}}
    {{end}}{{if .Commands}}
@@ -73,6 +76,38 @@ func main() {
 
 	app.Version = version
 	app.Action = startIndexer
+	app.Commands = []cli.Command{
+		{
+			Name:  "backfill",
+			Usage: "Indexes again, in import-db mode, the blocks of a shard from a range of nonces using the recorded blocks",
+			Flags: []cli.Flag{
+				shardIDFlag,
+				backfillFromNonce,
+				backfillToNonce,
+				backfillSource,
+			},
+			Action: backfillBlocks,
+		},
+		{
+			Name:  "rollback",
+			Usage: "Removes the data indexed for the blocks of a shard with a nonce greater than the provided one",
+			Flags: []cli.Flag{
+				shardIDFlag,
+				rollbackNonce,
+			},
+			Action: rollbackToNonce,
+		},
+		{
+			Name:  "verify",
+			Usage: "Checks the indexed blocks of a shard from a range of nonces against the rest of the indexed data and reports the incomplete ones",
+			Flags: []cli.Flag{
+				shardIDFlag,
+				verifyFromNonce,
+				verifyToNonce,
+			},
+			Action: verifyBlocks,
+		},
+	}
 
 	err := app.Run(os.Args)
 	if err != nil {
@@ -98,7 +133,7 @@ func startIndexer(ctx *cli.Context) error {
 	}
 
 	statusMetrics := metrics.NewStatusMetrics()
-	wsHost, err := factory.CreateWsIndexer(cfg, clusterCfg, statusMetrics, ctx.App.Version)
+	wsHost, indexerAdmin, err := factory.CreateWsIndexer(cfg, clusterCfg, statusMetrics, ctx.App.Version)
 	if err != nil {
 		return fmt.Errorf("%w while creating the indexer", err)
 	}
@@ -108,7 +143,13 @@ func startIndexer(ctx *cli.Context) error {
 		return fmt.Errorf("%w while loading the api config file", err)
 	}
 
-	webServer, err := factory.CreateWebServer(apiConfig, statusMetrics)
+	webServer, err := factory.CreateWebServer(factory.ArgsCreateWebServer{
+		ApiConfig:     apiConfig,
+		Config:        cfg,
+		ClusterConfig: clusterCfg,
+		StatusMetrics: statusMetrics,
+		AdminHandler:  indexerAdmin,
+	})
 	if err != nil {
 		return fmt.Errorf("%w while creating the web server", err)
 	}
@@ -118,6 +159,14 @@ func startIndexer(ctx *cli.Context) error {
 		return fmt.Errorf("%w while starting the web server", err)
 	}
 
+	periodicVerifier, err := factory.CreatePeriodicVerifier(cfg, clusterCfg, statusMetrics)
+	if err != nil {
+		return fmt.Errorf("%w while creating the completeness verifier", err)
+	}
+	if !check.IfNil(periodicVerifier) {
+		periodicVerifier.StartVerification()
+	}
+
 	interrupt := make(chan os.Signal, 1)
 	signal.Notify(interrupt, syscall.SIGINT, syscall.SIGTERM)
 
@@ -138,10 +187,11 @@ func startIndexer(ctx *cli.Context) error {
 		log.Error("cannot close web server", "error", err)
 	}
 
-	if !check.IfNilReflect(fileLogging) {
-		err = fileLogging.Close()
-		log.LogIfError(err)
+	if !check.IfNil(periodicVerifier) {
+		log.LogIfError(periodicVerifier.Close())
 	}
+
+	closeFileLogging(fileLogging)
 	return nil
 }
 
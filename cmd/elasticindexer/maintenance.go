@@ -0,0 +1,158 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/kalyan3104/k-chain-core-go/core/check"
+	"github.com/kalyan3104/k-chain-core-go/core/closing"
+	"github.com/kalyan3104/k-chain-es-indexer-go/config"
+	"github.com/kalyan3104/k-chain-es-indexer-go/factory"
+	"github.com/kalyan3104/k-chain-es-indexer-go/metrics"
+	"github.com/urfave/cli"
+)
+
+func backfillBlocks(ctx *cli.Context) error {
+	shardID, err := getShardID(ctx, backfillFromNonce, backfillToNonce)
+	if err != nil {
+		return err
+	}
+
+	cfg, clusterCfg, fileLogging, err := prepareMaintenanceCommand(ctx)
+	if err != nil {
+		return err
+	}
+	defer closeFileLogging(fileLogging)
+
+	backfiller, err := factory.CreateBackfiller(cfg, clusterCfg, metrics.NewStatusMetrics(), ctx.String(backfillSource.Name), ctx.App.Version)
+	if err != nil {
+		return fmt.Errorf("%w while creating the backfiller", err)
+	}
+
+	fromNonce := ctx.Uint64(backfillFromNonce.Name)
+	toNonce := ctx.Uint64(backfillToNonce.Name)
+	results, err := backfiller.Backfill(shardID, fromNonce, toNonce)
+	log.Info("backfill summary",
+		"shardID", shardID,
+		"from nonce", fromNonce,
+		"to nonce", toNonce,
+		"indexed blocks", results.NumIndexedBlocks,
+		"replaced blocks", results.NumReplacedBlocks,
+	)
+	if err != nil {
+		return fmt.Errorf("%w while backfilling", err)
+	}
+
+	return nil
+}
+
+func rollbackToNonce(ctx *cli.Context) error {
+	shardID, err := getShardID(ctx, rollbackNonce)
+	if err != nil {
+		return err
+	}
+
+	cfg, clusterCfg, fileLogging, err := prepareMaintenanceCommand(ctx)
+	if err != nil {
+		return err
+	}
+	defer closeFileLogging(fileLogging)
+
+	dataIndexer, err := factory.CreateDataIndexer(cfg, clusterCfg, metrics.NewStatusMetrics(), ctx.App.Version)
+	if err != nil {
+		return fmt.Errorf("%w while creating the data indexer", err)
+	}
+
+	nonce := ctx.Uint64(rollbackNonce.Name)
+	response, err := dataIndexer.RollbackToNonce(shardID, nonce)
+	log.Info("rollback summary",
+		"shardID", shardID,
+		"nonce", nonce,
+		"removed blocks", response.RemovedBlocks,
+		"last indexed nonce", response.LastIndexedNonce,
+		"last indexed hash", response.LastIndexedHash,
+		"last indexed timestamp", response.LastIndexedTimestamp,
+		"no indexed blocks", response.NoIndexedBlocks,
+	)
+	if err != nil {
+		return fmt.Errorf("%w while rolling back", err)
+	}
+
+	return nil
+}
+
+func verifyBlocks(ctx *cli.Context) error {
+	shardID, err := getShardID(ctx, verifyFromNonce, verifyToNonce)
+	if err != nil {
+		return err
+	}
+
+	cfg, clusterCfg, fileLogging, err := prepareMaintenanceCommand(ctx)
+	if err != nil {
+		return err
+	}
+	defer closeFileLogging(fileLogging)
+
+	blocksVerifier, err := factory.CreateCompletenessVerifier(cfg, clusterCfg, metrics.NewStatusMetrics())
+	if err != nil {
+		return fmt.Errorf("%w while creating the completeness verifier", err)
+	}
+
+	fromNonce := ctx.Uint64(verifyFromNonce.Name)
+	toNonce := ctx.Uint64(verifyToNonce.Name)
+	results, err := blocksVerifier.VerifyNoncesRange(shardID, fromNonce, toNonce)
+	log.Info("verification summary",
+		"shardID", shardID,
+		"from nonce", fromNonce,
+		"to nonce", toNonce,
+		"verified blocks", results.NumVerifiedBlocks,
+		"incomplete blocks", results.NumIncompleteBlocks,
+	)
+	if err != nil {
+		return fmt.Errorf("%w while verifying", err)
+	}
+
+	return nil
+}
+
+// getShardID checks that the shard flag and the provided required flags are set and returns the shard
+func getShardID(ctx *cli.Context, requiredFlags ...cli.Uint64Flag) (uint32, error) {
+	for _, flag := range append([]cli.Uint64Flag{shardIDFlag}, requiredFlags...) {
+		if !ctx.IsSet(flag.Name) {
+			return 0, fmt.Errorf("the --%s flag is required", flag.Name)
+		}
+	}
+
+	shardID := ctx.Uint64(shardIDFlag.Name)
+	if shardID > math.MaxUint32 {
+		return 0, errors.New("invalid shard")
+	}
+
+	return uint32(shardID), nil
+}
+
+func prepareMaintenanceCommand(ctx *cli.Context) (config.Config, config.ClusterConfig, closing.Closer, error) {
+	cfg, err := loadMainConfig(ctx.GlobalString(configurationFile.Name))
+	if err != nil {
+		return config.Config{}, config.ClusterConfig{}, nil, fmt.Errorf("%w while loading the config file", err)
+	}
+
+	clusterCfg, err := loadClusterConfig(ctx.GlobalString(configurationPreferencesFile.Name))
+	if err != nil {
+		return config.Config{}, config.ClusterConfig{}, nil, fmt.Errorf("%w while loading the preferences config file", err)
+	}
+
+	fileLogging, err := initializeLogger(ctx, cfg)
+	if err != nil {
+		return config.Config{}, config.ClusterConfig{}, nil, fmt.Errorf("%w while initializing the logger", err)
+	}
+
+	return cfg, clusterCfg, fileLogging, nil
+}
+
+func closeFileLogging(fileLogging closing.Closer) {
+	if !check.IfNilReflect(fileLogging) {
+		log.LogIfError(fileLogging.Close())
+	}
+}
@@ -28,6 +28,26 @@ type Config struct {
 			LogFilePrefix        string `toml:"log-file-prefix"`
 			LogsPath             string `toml:"logs-path"`
 		} `toml:"logs"`
+		StageTimings struct {
+			SlowBlockThresholdInMs uint64 `toml:"slow-block-threshold-in-ms"`
+			TraceFilePath          string `toml:"trace-file-path"`
+		} `toml:"stage-timings"`
+		BlockRecordings struct {
+			Enabled bool   `toml:"enabled"`
+			Path    string `toml:"path"`
+		} `toml:"block-recordings"`
+		Backfill struct {
+			Source                  string `toml:"source"`
+			RequestTimeoutInSeconds uint32 `toml:"request-timeout-in-seconds"`
+		} `toml:"backfill"`
+		CompletenessVerifier struct {
+			Enabled           bool   `toml:"enabled"`
+			IntervalInSeconds uint32 `toml:"interval-in-seconds"`
+			DelayInSeconds    uint32 `toml:"delay-in-seconds"`
+		} `toml:"completeness-verifier"`
+		StatsContributions struct {
+			RetentionInDays uint32 `toml:"retention-in-days"`
+		} `toml:"stats-contributions"`
 	} `toml:"config"`
 }
 
@@ -58,6 +78,22 @@ type ClusterConfig struct {
 type ApiRoutesConfig struct {
 	RestApiInterface string                      `toml:"rest-api-interface"`
 	APIPackages      map[string]APIPackageConfig `toml:"api-packages"`
+	Health           HealthConfig                `toml:"health"`
+	Admin            AdminConfig                 `toml:"admin"`
+}
+
+// AdminConfig holds the configuration for the admin routes, which are protected with basic authentication
+type AdminConfig struct {
+	Enabled  bool   `toml:"enabled"`
+	Username string `toml:"username"`
+	Password string `toml:"password"`
+}
+
+// HealthConfig holds the configuration for the health and readiness endpoints
+type HealthConfig struct {
+	MaxIndexingLagInSeconds       uint64 `toml:"max-indexing-lag-in-seconds"`
+	WebSocketIdleTimeoutInSeconds uint64 `toml:"websocket-idle-timeout-in-seconds"`
+	ElasticsearchTimeoutInSeconds uint64 `toml:"elasticsearch-timeout-in-seconds"`
 }
 
 // APIPackageConfig holds the configuration for the routes of each package
@@ -7,3 +7,21 @@ var ErrNilMetricsHandler = errors.New("nil metrics handler")
 
 // ErrNilFacadeHandler signal that a nil facade handler has been provided
 var ErrNilFacadeHandler = errors.New("nil facade handler")
+
+// ErrDocumentNotFound signals that the requested document was not found
+var ErrDocumentNotFound = errors.New("document not found")
+
+// ErrInvalidQueryParameter signals that an invalid query parameter has been provided
+var ErrInvalidQueryParameter = errors.New("invalid query parameter")
+
+// ErrNilDatabaseReader signals that a nil database reader has been provided
+var ErrNilDatabaseReader = errors.New("nil database reader")
+
+// ErrNilClusterHealthHandler signals that a nil cluster health handler has been provided
+var ErrNilClusterHealthHandler = errors.New("nil cluster health handler")
+
+// ErrNilAdminHandler signals that a nil admin handler has been provided
+var ErrNilAdminHandler = errors.New("nil admin handler")
+
+// ErrEmptyAdminCredentials signals that the admin routes are enabled without credentials
+var ErrEmptyAdminCredentials = errors.New("empty admin credentials")
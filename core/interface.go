@@ -1,6 +1,10 @@
 package core
 
 import (
+	"context"
+	"net/http"
+	"time"
+
 	"github.com/kalyan3104/k-chain-es-indexer-go/core/request"
 	"github.com/kalyan3104/k-chain-es-indexer-go/metrics"
 )
@@ -10,6 +14,16 @@ type StatusMetricsHandler interface {
 	AddIndexingData(args metrics.ArgsAddIndexingData)
 	GetMetrics() map[string]*request.MetricsResponse
 	GetMetricsForPrometheus() string
+	GetPrometheusHandler() http.Handler
+	SetLastIndexedBlock(shardID uint32, nonce uint64, timestamp uint64)
+	RemoveLastIndexedBlock(shardID uint32)
+	GetShardsIndexingStatus() map[uint32]*request.ShardIndexingStatus
+	SetLastPayloadTime(lastPayloadTime time.Time)
+	GetLastPayloadTime() time.Time
+	AddWrittenDocuments(index string, numDocuments uint64)
+	AddBlockStageDuration(stage string, shardID uint32, duration time.Duration)
+	IncrementPendingPayloads()
+	DecrementPendingPayloads()
 	IsInterfaceNil() bool
 }
 
@@ -18,3 +32,29 @@ type WebServerHandler interface {
 	StartHttpServer() error
 	Close() error
 }
+
+// DatabaseReaderHandler defines the actions that a component that reads documents from the database should do
+type DatabaseReaderHandler interface {
+	DoMultiGet(ctx context.Context, ids []string, index string, withSource bool, resBody interface{}) error
+	DoSearchRequest(ctx context.Context, index string, body []byte, resBody interface{}) error
+	IsInterfaceNil() bool
+}
+
+// IndexerAdminHandler defines the actions that an operator can trigger on the indexer at runtime
+type IndexerAdminHandler interface {
+	PauseProcessing()
+	ResumeProcessing()
+	IsProcessingPaused() bool
+	EnableIndex(index string) error
+	DisableIndex(index string) error
+	GetEnabledIndexes() []string
+	RevertBlocksInRange(shardID uint32, fromNonce uint64, toNonce uint64) (uint64, error)
+	RollbackToNonce(shardID uint32, nonce uint64) (*request.RollbackResponse, error)
+	IsInterfaceNil() bool
+}
+
+// ClusterHealthHandler defines the behavior of a component that can fetch the health of the database cluster
+type ClusterHealthHandler interface {
+	GetClusterHealth(ctx context.Context) (string, error)
+	IsInterfaceNil() bool
+}
@@ -0,0 +1,36 @@
+package request
+
+import "github.com/kalyan3104/k-chain-es-indexer-go/config"
+
+// EffectiveConfigResponse defines the response for the endpoint that exposes the configuration used at runtime
+type EffectiveConfigResponse struct {
+	Config           config.Config          `json:"config"`
+	ClusterConfig    config.ClusterConfig   `json:"cluster_config"`
+	ApiConfig        config.ApiRoutesConfig `json:"api_config"`
+	EnabledIndices   []string               `json:"enabled_indices"`
+	ProcessingPaused bool                   `json:"processing_paused"`
+}
+
+// RevertRangeRequest defines the request for reverting the blocks of a shard with the nonce in the provided range
+type RevertRangeRequest struct {
+	ShardID   uint32 `json:"shard_id"`
+	FromNonce uint64 `json:"from_nonce"`
+	ToNonce   uint64 `json:"to_nonce"`
+}
+
+// RollbackRequest defines the request for removing the blocks of a shard with the nonce greater than the provided one
+type RollbackRequest struct {
+	ShardID uint32 `json:"shard_id"`
+	Nonce   uint64 `json:"nonce"`
+}
+
+// RollbackResponse defines the outcome of a rollback: the number of removed blocks and the block the shard was reset to,
+// or the flag showing that no block of the shard remained
+type RollbackResponse struct {
+	ShardID              uint32 `json:"shard_id"`
+	RemovedBlocks        uint64 `json:"removed_blocks"`
+	LastIndexedNonce     uint64 `json:"last_indexed_nonce"`
+	LastIndexedHash      string `json:"last_indexed_hash"`
+	LastIndexedTimestamp uint64 `json:"last_indexed_timestamp"`
+	NoIndexedBlocks      bool   `json:"no_indexed_blocks"`
+}
@@ -0,0 +1,32 @@
+package request
+
+// ShardIndexingStatus holds the details about the last block indexed for a shard
+type ShardIndexingStatus struct {
+	LastIndexedNonce     uint64 `json:"last_indexed_nonce"`
+	LastIndexedTimestamp uint64 `json:"last_indexed_timestamp"`
+	IndexedAt            int64  `json:"indexed_at"`
+	LagInSeconds         int64  `json:"lag_in_seconds"`
+}
+
+// ElasticsearchStatus holds the details about the Elasticsearch cluster as seen by the indexer
+type ElasticsearchStatus struct {
+	Reachable     bool   `json:"reachable"`
+	ClusterHealth string `json:"cluster_health,omitempty"`
+	Error         string `json:"error,omitempty"`
+}
+
+// WebSocketStatus holds the details about the websocket connection with the observer
+type WebSocketStatus struct {
+	State           string `json:"state"`
+	LastPayloadTime int64  `json:"last_payload_time,omitempty"`
+}
+
+// HealthResponse defines the response for the health and readiness endpoints
+type HealthResponse struct {
+	Ready         bool                            `json:"ready"`
+	Reasons       []string                        `json:"reasons,omitempty"`
+	Elasticsearch *ElasticsearchStatus            `json:"elasticsearch"`
+	WebSocket     *WebSocketStatus                `json:"websocket"`
+	Shards        map[uint32]*ShardIndexingStatus `json:"shards"`
+	MaxLagAllowed uint64                          `json:"max_lag_allowed_in_seconds"`
+}
@@ -53,12 +53,39 @@ type AccountBalanceHistory struct {
 	Address         string        `json:"address"`
 	Timestamp       time.Duration `json:"timestamp"`
 	Balance         string        `json:"balance"`
+	Nonce           *uint64       `json:"nonce,omitempty"`
 	Token           string        `json:"token,omitempty"`
 	Identifier      string        `json:"identifier,omitempty"`
 	TokenNonce      uint64        `json:"tokenNonce,omitempty"`
 	IsSender        bool          `json:"isSender,omitempty"`
 	IsSmartContract bool          `json:"isSmartContract,omitempty"`
 	ShardID         uint32        `json:"shardID"`
+	// PreviousState holds the other fields of a regular account from before the block, kept only if the block changed
+	// them, so they can be restored when the block is reverted
+	PreviousState *AccountHistoryState `json:"previousState,omitempty"`
+	// Created is true if the account was not in the accounts index before the block which wrote the entry
+	Created bool `json:"created,omitempty"`
+}
+
+// AccountHistoryState holds the fields of a regular account, other than the balance and the nonce, which are restored
+// from the history entry of a block when the block is reverted
+type AccountHistoryState struct {
+	UserName            string  `json:"userName,omitempty"`
+	DeveloperRewards    string  `json:"developerRewards,omitempty"`
+	DeveloperRewardsNum float64 `json:"developerRewardsNum,omitempty"`
+	CurrentOwner        string  `json:"currentOwner,omitempty"`
+	RootHash            []byte  `json:"rootHash,omitempty"`
+	CodeHash            []byte  `json:"codeHash,omitempty"`
+	CodeMetadata        []byte  `json:"codeMetadata,omitempty"`
+}
+
+// ResponseAccountsHistory is the structure for the response of a search over the accounts history
+type ResponseAccountsHistory struct {
+	Hits struct {
+		Hits []struct {
+			Source *AccountBalanceHistory `json:"_source"`
+		} `json:"hits"`
+	} `json:"hits"`
 }
 
 // Account is a structure that is needed for regular accounts
@@ -110,3 +110,12 @@ type Miniblock struct {
 	Timestamp                   time.Duration `json:"timestamp"`
 	Reserved                    []byte        `json:"reserved,omitempty"`
 }
+
+// LastIndexedBlock is a structure containing the last block indexed for a shard. It is kept in the values index, so the
+// indexer knows where every shard is after a restart or after a rollback made by another process
+type LastIndexedBlock struct {
+	ShardID   uint32        `json:"shardID"`
+	Nonce     uint64        `json:"nonce"`
+	Hash      string        `json:"hash"`
+	Timestamp time.Duration `json:"timestamp"`
+}
@@ -0,0 +1,31 @@
+package data
+
+import "time"
+
+// CallTree is a structure containing the smart contract calls generated by a transaction. The executions are kept
+// flat in Nodes, as they arrive from the shards, and Tree holds the nested view rebuilt from them
+type CallTree struct {
+	OriginalTxHash string          `json:"originalTxHash"`
+	Nodes          []*CallTreeNode `json:"nodes"`
+	Tree           *CallTreeNode   `json:"tree,omitempty"`
+	NumCalls       uint64          `json:"numCalls"`
+	Status         string          `json:"status,omitempty"`
+	Timestamp      time.Duration   `json:"timestamp"`
+}
+
+// CallTreeNode is a structure containing the information about one execution of the call tree
+type CallTreeNode struct {
+	Hash          string          `json:"hash"`
+	PrevTxHash    string          `json:"prevTxHash,omitempty"`
+	ShardID       uint32          `json:"shardID"`
+	Sender        string          `json:"sender"`
+	Receiver      string          `json:"receiver"`
+	Value         string          `json:"value"`
+	Function      string          `json:"function,omitempty"`
+	CallType      string          `json:"callType,omitempty"`
+	ReturnMessage string          `json:"returnMessage,omitempty"`
+	Status        string          `json:"status"`
+	BlockHash     string          `json:"blockHash,omitempty"`
+	Timestamp     time.Duration   `json:"timestamp"`
+	Children      []*CallTreeNode `json:"children,omitempty"`
+}
@@ -0,0 +1,17 @@
+package data
+
+import "time"
+
+// ConsensusStats is a structure containing the consensus participation of a validator for an epoch or for a day
+type ConsensusStats struct {
+	PublicKey        string        `json:"publicKey"`
+	ShardID          uint32        `json:"shardID"`
+	Bucket           string        `json:"bucket"`
+	Epoch            *uint32       `json:"epoch,omitempty"`
+	Day              time.Duration `json:"day,omitempty"`
+	ProposedBlocks   uint64        `json:"proposedBlocks"`
+	MissedProposals  uint64        `json:"missedProposals"`
+	SignedBlocks     uint64        `json:"signedBlocks"`
+	MissedSignatures uint64        `json:"missedSignatures"`
+	Timestamp        time.Duration `json:"timestamp"`
+}
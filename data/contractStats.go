@@ -0,0 +1,38 @@
+package data
+
+import "time"
+
+// ContractStats is a structure containing the usage statistics of a smart contract for a day. The unique callers are
+// counted when the shard of the contract indexes a block of a later day
+type ContractStats struct {
+	Address         string            `json:"address"`
+	Day             time.Duration     `json:"day"`
+	Calls           uint64            `json:"calls"`
+	CallsByFunction map[string]uint64 `json:"callsByFunction"`
+	UniqueCallers   uint64            `json:"uniqueCallers"`
+	GasUsed         uint64            `json:"gasUsed"`
+	Fees            string            `json:"fees"`
+	FeesNum         float64           `json:"feesNum"`
+	FailedCalls     uint64            `json:"failedCalls"`
+	LastCalled      time.Duration     `json:"lastCalled"`
+}
+
+// ResponseContractsUniqueCallers is the structure for the response of the aggregation that counts the unique callers of
+// every contract called in a day
+type ResponseContractsUniqueCallers struct {
+	Aggregations struct {
+		Contracts struct {
+			AfterKey *struct {
+				Address string `json:"address"`
+			} `json:"after_key"`
+			Buckets []struct {
+				Key struct {
+					Address string `json:"address"`
+				} `json:"key"`
+				Callers struct {
+					Value uint64 `json:"value"`
+				} `json:"callers"`
+			} `json:"buckets"`
+		} `json:"contracts"`
+	} `json:"aggregations"`
+}
@@ -22,6 +22,43 @@ type ValidatorRatingInfo struct {
 	Rating    float32 `json:"rating"`
 }
 
+// ValidatorRatingHistory is a structure containing the rating of a validator in an epoch
+type ValidatorRatingHistory struct {
+	PublicKey string  `json:"publicKey"`
+	ShardID   uint32  `json:"shardID"`
+	Epoch     uint32  `json:"epoch"`
+	Rating    float32 `json:"rating"`
+}
+
+// ValidatorHistory is a structure containing the epoch when a validator joined or left the list of a shard
+type ValidatorHistory struct {
+	PublicKey string `json:"publicKey"`
+	ShardID   uint32 `json:"shardID"`
+	Epoch     uint32 `json:"epoch"`
+	Action    string `json:"action"`
+}
+
+// ResponseValidatorsHistory is the structure for the response of a search over the validators history
+type ResponseValidatorsHistory struct {
+	Hits struct {
+		Hits []struct {
+			Source *ValidatorHistory `json:"_source"`
+		} `json:"hits"`
+	} `json:"hits"`
+}
+
+// ResponseValidatorsPublicKeys is the structure for the validators public keys response
+type ResponseValidatorsPublicKeys struct {
+	Docs []*ResponseValidatorsPublicKeysDB `json:"docs"`
+}
+
+// ResponseValidatorsPublicKeysDB is the structure for the validators public keys of a shard response
+type ResponseValidatorsPublicKeysDB struct {
+	Found  bool                 `json:"found"`
+	ID     string               `json:"_id"`
+	Source ValidatorsPublicKeys `json:"_source"`
+}
+
 // RoundInfo is a structure containing block signers and shard id
 type RoundInfo struct {
 	Round            uint64        `json:"round"`
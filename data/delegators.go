@@ -1,6 +1,9 @@
 package data
 
-import "time"
+import (
+	"math/big"
+	"time"
+)
 
 // Delegator is a structure that is needed to store information about a delegator
 type Delegator struct {
@@ -21,3 +24,64 @@ type UnDelegate struct {
 	Value     string        `json:"value"`
 	ValueNum  float64       `json:"valueNum"`
 }
+
+// DelegationProvider is a structure that is needed to store the aggregated information about a staking provider
+type DelegationProvider struct {
+	Contract              string        `json:"contract"`
+	ActiveStake           string        `json:"activeStake"`
+	ActiveStakeNum        float64       `json:"activeStakeNum"`
+	NumDelegators         uint64        `json:"numDelegators"`
+	UnDelegatedPending    string        `json:"unDelegatedPending"`
+	UnDelegatedPendingNum float64       `json:"unDelegatedPendingNum"`
+	ClaimedRewards        string        `json:"claimedRewards"`
+	ClaimedRewardsNum     float64       `json:"claimedRewardsNum"`
+	Timestamp             time.Duration `json:"timestamp"`
+}
+
+// DelegationProviderUpdate holds the changes of a staking provider extracted from the events of a block
+type DelegationProviderUpdate struct {
+	Contract          string
+	HasStakeInfo      bool
+	ActiveStake       *big.Int
+	ActiveStakeNum    float64
+	NumDelegators     uint64
+	UnDelegated       *big.Int
+	UnDelegatedNum    float64
+	Withdrawn         *big.Int
+	WithdrawnNum      float64
+	ClaimedRewards    *big.Int
+	ClaimedRewardsNum float64
+	Timestamp         time.Duration
+}
+
+// NewDelegationProviderUpdate will create a new instance of DelegationProviderUpdate with all the values set to zero
+func NewDelegationProviderUpdate(contract string, timestamp time.Duration) *DelegationProviderUpdate {
+	return &DelegationProviderUpdate{
+		Contract:       contract,
+		ActiveStake:    big.NewInt(0),
+		UnDelegated:    big.NewInt(0),
+		Withdrawn:      big.NewInt(0),
+		ClaimedRewards: big.NewInt(0),
+		Timestamp:      timestamp,
+	}
+}
+
+// Merge will add the changes from the provided update. The stake information is taken from the most recent update
+func (dpu *DelegationProviderUpdate) Merge(update *DelegationProviderUpdate) {
+	if update.HasStakeInfo {
+		dpu.HasStakeInfo = true
+		dpu.ActiveStake = update.ActiveStake
+		dpu.ActiveStakeNum = update.ActiveStakeNum
+		dpu.NumDelegators = update.NumDelegators
+	}
+
+	dpu.UnDelegated.Add(dpu.UnDelegated, update.UnDelegated)
+	dpu.UnDelegatedNum += update.UnDelegatedNum
+	dpu.Withdrawn.Add(dpu.Withdrawn, update.Withdrawn)
+	dpu.WithdrawnNum += update.WithdrawnNum
+	dpu.ClaimedRewards.Add(dpu.ClaimedRewards, update.ClaimedRewards)
+	dpu.ClaimedRewardsNum += update.ClaimedRewardsNum
+	if update.Timestamp > dpu.Timestamp {
+		dpu.Timestamp = update.Timestamp
+	}
+}
@@ -0,0 +1,38 @@
+package data
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDelegationProviderUpdate_Merge(t *testing.T) {
+	t.Parallel()
+
+	update := NewDelegationProviderUpdate("contract", 10)
+	update.HasStakeInfo = true
+	update.ActiveStake = big.NewInt(100)
+	update.NumDelegators = 2
+	update.UnDelegated = big.NewInt(5)
+
+	claim := NewDelegationProviderUpdate("contract", 10)
+	claim.ClaimedRewards = big.NewInt(3)
+	update.Merge(claim)
+	require.Equal(t, big.NewInt(100), update.ActiveStake)
+	require.Equal(t, uint64(2), update.NumDelegators)
+	require.Equal(t, big.NewInt(3), update.ClaimedRewards)
+
+	withdraw := NewDelegationProviderUpdate("contract", 20)
+	withdraw.HasStakeInfo = true
+	withdraw.ActiveStake = big.NewInt(90)
+	withdraw.NumDelegators = 1
+	withdraw.Withdrawn = big.NewInt(5)
+	update.Merge(withdraw)
+	require.Equal(t, big.NewInt(90), update.ActiveStake)
+	require.Equal(t, uint64(1), update.NumDelegators)
+	require.Equal(t, big.NewInt(5), update.UnDelegated)
+	require.Equal(t, big.NewInt(5), update.Withdrawn)
+	require.Equal(t, big.NewInt(3), update.ClaimedRewards)
+	require.Equal(t, uint64(20), uint64(update.Timestamp))
+}
@@ -0,0 +1,45 @@
+package data
+
+import "time"
+
+// EpochStats is a structure containing the aggregated statistics of a shard for an epoch. The active senders and
+// receivers are counted when the shard starts the next epoch
+type EpochStats struct {
+	Epoch            uint32            `json:"epoch"`
+	ShardID          uint32            `json:"shardID"`
+	NumBlocks        uint64            `json:"numBlocks"`
+	TxCount          uint64            `json:"txCount"`
+	TxsByOperation   map[string]uint64 `json:"txsByOperation"`
+	TxsByStatus      map[string]uint64 `json:"txsByStatus"`
+	ScResultsCount   uint64            `json:"scResultsCount"`
+	Fees             string            `json:"fees"`
+	FeesNum          float64           `json:"feesNum"`
+	DeveloperFees    string            `json:"developerFees"`
+	DeveloperFeesNum float64           `json:"developerFeesNum"`
+	GasUsed          uint64            `json:"gasUsed"`
+	ActiveSenders    uint64            `json:"activeSenders"`
+	ActiveReceivers  uint64            `json:"activeReceivers"`
+	NewContracts     uint64            `json:"newContracts"`
+	NewAccounts      uint64            `json:"newAccounts"`
+	TokenTransfers   uint64            `json:"tokenTransfers"`
+	Finalized        bool              `json:"finalized"`
+	StartTimestamp   time.Duration     `json:"startTimestamp"`
+	Timestamp        time.Duration     `json:"timestamp"`
+}
+
+// ResponseEpochActiveAccounts is the structure for the response of the aggregations that count the accounts of a shard
+// that sent or received transactions in an epoch
+type ResponseEpochActiveAccounts struct {
+	Aggregations struct {
+		Senders struct {
+			Count struct {
+				Value uint64 `json:"value"`
+			} `json:"count"`
+		} `json:"senders"`
+		Receivers struct {
+			Count struct {
+				Value uint64 `json:"value"`
+			} `json:"count"`
+		} `json:"receivers"`
+	} `json:"aggregations"`
+}
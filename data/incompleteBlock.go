@@ -0,0 +1,21 @@
+package data
+
+import "time"
+
+// IncompleteBlock is a structure containing the data that is missing for an indexed block, as found by the completeness
+// verifier
+type IncompleteBlock struct {
+	Hash                string        `json:"-"`
+	ShardID             uint32        `json:"shardId"`
+	Nonce               uint64        `json:"nonce"`
+	Timestamp           time.Duration `json:"timestamp"`
+	TxCount             uint32        `json:"txCount"`
+	NumMiniblocksTxs    uint32        `json:"numMiniblocksTxs"`
+	Issues              []string      `json:"issues"`
+	MissingMiniblocks   []string      `json:"missingMiniblocks,omitempty"`
+	MissingTransactions []string      `json:"missingTransactions,omitempty"`
+	MissingScResults    []string      `json:"missingScResults,omitempty"`
+	MissingReceipts     []string      `json:"missingReceipts,omitempty"`
+	MissingLogs         []string      `json:"missingLogs,omitempty"`
+	VerifiedAt          time.Duration `json:"verifiedAt"`
+}
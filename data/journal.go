@@ -0,0 +1,41 @@
+package data
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// JournalEntry is a structure containing the version of a document before it was updated by a block, the version
+// written by the block and the increments added by the block to the counter fields of the document
+type JournalEntry struct {
+	BlockHash  string           `json:"blockHash"`
+	ShardID    uint32           `json:"shardID"`
+	Timestamp  time.Duration    `json:"timestamp"`
+	Index      string           `json:"index"`
+	DocID      string           `json:"docID"`
+	Existed    bool             `json:"existed"`
+	Source     json.RawMessage  `json:"source,omitempty"`
+	Written    json.RawMessage  `json:"written,omitempty"`
+	Increments map[string]int64 `json:"increments,omitempty"`
+}
+
+// ResponseRawDocuments is the structure for a multi get response that keeps the documents unparsed
+type ResponseRawDocuments struct {
+	Docs []ResponseRawDocument `json:"docs"`
+}
+
+// ResponseRawDocument is the structure for a document from a multi get response
+type ResponseRawDocument struct {
+	Found  bool            `json:"found"`
+	ID     string          `json:"_id"`
+	Source json.RawMessage `json:"_source"`
+}
+
+// ResponseJournalEntries is the structure for the response of a query over the journal
+type ResponseJournalEntries struct {
+	Hits struct {
+		Hits []struct {
+			Source *JournalEntry `json:"_source"`
+		} `json:"hits"`
+	} `json:"hits"`
+}
@@ -33,6 +33,7 @@ type PreparedLogsResults struct {
 	ScDeploys               map[string]*ScDeployInfo
 	ChangeOwnerOperations   map[string]*OwnerData
 	Delegators              map[string]*Delegator
+	DelegationProviders     map[string]*DelegationProviderUpdate
 	TxHashStatusInfo        map[string]*outport.StatusInfo
 	TokensInfo              []*TokenInfo
 	NFTsDataUpdates         []*NFTDataUpdate
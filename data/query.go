@@ -0,0 +1,59 @@
+package data
+
+import "encoding/json"
+
+// ResponseDocs is the structure for a multi get response with the raw sources of the documents
+type ResponseDocs struct {
+	Docs []*ResponseDoc `json:"docs"`
+}
+
+// ResponseDoc is the structure for a document from a multi get response
+type ResponseDoc struct {
+	Found  bool            `json:"found"`
+	ID     string          `json:"_id"`
+	Source json.RawMessage `json:"_source"`
+}
+
+// ResponseSearch is the structure for a search response
+type ResponseSearch struct {
+	Hits struct {
+		Hits []*ResponseSearchHit `json:"hits"`
+	} `json:"hits"`
+}
+
+// ResponseSearchHit is the structure for a document from a search response
+type ResponseSearchHit struct {
+	ID     string            `json:"_id"`
+	Source json.RawMessage   `json:"_source"`
+	Sort   []json.RawMessage `json:"sort"`
+}
+
+// ApiTransaction is the structure of a transaction returned by the API
+type ApiTransaction struct {
+	Hash string `json:"hash"`
+	*Transaction
+}
+
+// ApiBlock is the structure of a block returned by the API
+type ApiBlock struct {
+	Hash string `json:"hash"`
+	*Block
+}
+
+// ApiTransactionsPage is the structure of a page of transactions returned by the API
+type ApiTransactionsPage struct {
+	Transactions []*ApiTransaction `json:"transactions"`
+	NextCursor   string            `json:"nextCursor,omitempty"`
+}
+
+// ApiAccountTokensPage is the structure of a page of account tokens returned by the API
+type ApiAccountTokensPage struct {
+	Tokens     []*AccountInfo `json:"tokens"`
+	NextCursor string         `json:"nextCursor,omitempty"`
+}
+
+// ClusterHealth is the structure for the response of the cluster health request
+type ClusterHealth struct {
+	ClusterName string `json:"cluster_name"`
+	Status      string `json:"status"`
+}
@@ -0,0 +1,27 @@
+package data
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// StatsContribution is a structure containing what a block or a round added to the documents of a statistics index.
+// It is kept in order to subtract exactly the same values when the block is reverted or indexed again. An expired
+// contribution marks a removed block whose contribution was no longer kept, so its values are still counted
+type StatsContribution struct {
+	Index     string          `json:"index"`
+	Key       string          `json:"key"`
+	ShardID   uint32          `json:"shardID"`
+	Timestamp time.Duration   `json:"timestamp"`
+	Stats     json.RawMessage `json:"stats"`
+	Expired   bool            `json:"expired,omitempty"`
+}
+
+// ResponseStatsContributions is the structure for the response of a query over the statistics contributions
+type ResponseStatsContributions struct {
+	Hits struct {
+		Hits []struct {
+			Source *StatsContribution `json:"_source"`
+		} `json:"hits"`
+	} `json:"hits"`
+}
@@ -0,0 +1,30 @@
+package data
+
+import "time"
+
+// TxLifecycle is a structure containing the execution stages of a transaction across all the shards it touched
+type TxLifecycle struct {
+	Sender         string              `json:"sender,omitempty"`
+	Receiver       string              `json:"receiver,omitempty"`
+	SenderShard    *uint32             `json:"senderShard,omitempty"`
+	ReceiverShard  *uint32             `json:"receiverShard,omitempty"`
+	Stages         []*TxLifecycleStage `json:"stages"`
+	Status         string              `json:"status"`
+	StartTimestamp time.Duration       `json:"startTimestamp"`
+	EndTimestamp   time.Duration       `json:"endTimestamp"`
+	Latency        uint64              `json:"latency"`
+}
+
+// TxLifecycleStage is a structure containing the information about one execution of a transaction or of one of
+// its smart contract results
+type TxLifecycleStage struct {
+	Type          string        `json:"type"`
+	Hash          string        `json:"hash"`
+	ShardID       uint32        `json:"shardID"`
+	BlockHash     string        `json:"blockHash"`
+	BlockNonce    uint64        `json:"blockNonce"`
+	Round         uint64        `json:"round"`
+	MiniBlockHash string        `json:"miniBlockHash,omitempty"`
+	Status        string        `json:"status,omitempty"`
+	Timestamp     time.Duration `json:"timestamp"`
+}
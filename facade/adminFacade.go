@@ -0,0 +1,152 @@
+package facade
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/kalyan3104/k-chain-core-go/core/check"
+	"github.com/kalyan3104/k-chain-es-indexer-go/config"
+	"github.com/kalyan3104/k-chain-es-indexer-go/core"
+	"github.com/kalyan3104/k-chain-es-indexer-go/core/request"
+	"github.com/kalyan3104/k-chain-es-indexer-go/process/dataindexer"
+)
+
+const redactedValue = "<redacted>"
+
+// ArgsAdminFacade holds all the components needed to create a new instance of adminFacade
+type ArgsAdminFacade struct {
+	AdminHandler  core.IndexerAdminHandler
+	Config        config.Config
+	ClusterConfig config.ClusterConfig
+	ApiConfig     config.ApiRoutesConfig
+}
+
+type adminFacade struct {
+	adminHandler  core.IndexerAdminHandler
+	config        config.Config
+	clusterConfig config.ClusterConfig
+	apiConfig     config.ApiRoutesConfig
+}
+
+// NewAdminFacade will create a new instance of adminFacade
+func NewAdminFacade(args ArgsAdminFacade) (*adminFacade, error) {
+	if check.IfNil(args.AdminHandler) {
+		return nil, core.ErrNilAdminHandler
+	}
+
+	return &adminFacade{
+		adminHandler:  args.AdminHandler,
+		config:        args.Config,
+		clusterConfig: args.ClusterConfig,
+		apiConfig:     args.ApiConfig,
+	}, nil
+}
+
+// PauseProcessing will pause the processing of the payloads received from the observer
+func (af *adminFacade) PauseProcessing() {
+	af.adminHandler.PauseProcessing()
+}
+
+// ResumeProcessing will resume the processing of the payloads received from the observer
+func (af *adminFacade) ResumeProcessing() {
+	af.adminHandler.ResumeProcessing()
+}
+
+// IsProcessingPaused returns true if the processing of the payloads is paused
+func (af *adminFacade) IsProcessingPaused() bool {
+	return af.adminHandler.IsProcessingPaused()
+}
+
+// GetEnabledIndices returns the indices that are currently enabled
+func (af *adminFacade) GetEnabledIndices() []string {
+	return af.adminHandler.GetEnabledIndexes()
+}
+
+// EnableIndex will enable the indexing of the provided index
+func (af *adminFacade) EnableIndex(index string) error {
+	return convertAdminError(af.adminHandler.EnableIndex(index))
+}
+
+// DisableIndex will disable the indexing of the provided index
+func (af *adminFacade) DisableIndex(index string) error {
+	return convertAdminError(af.adminHandler.DisableIndex(index))
+}
+
+// RevertBlocksInRange will remove the data indexed for the blocks of the provided shard with the nonce in the provided
+// range and will return the number of reverted blocks
+func (af *adminFacade) RevertBlocksInRange(revertRequest request.RevertRangeRequest) (uint64, error) {
+	numReverted, err := af.adminHandler.RevertBlocksInRange(revertRequest.ShardID, revertRequest.FromNonce, revertRequest.ToNonce)
+
+	return numReverted, convertAdminError(err)
+}
+
+// RollbackToNonce will remove the data indexed for the blocks of the provided shard with a nonce greater than the provided
+// one and will return the number of removed blocks together with the block the shard was reset to
+func (af *adminFacade) RollbackToNonce(rollbackRequest request.RollbackRequest) (*request.RollbackResponse, error) {
+	response, err := af.adminHandler.RollbackToNonce(rollbackRequest.ShardID, rollbackRequest.Nonce)
+
+	return response, convertAdminError(err)
+}
+
+// GetEffectiveConfig returns the configuration used at runtime, with the credentials redacted: the user names and the
+// passwords of the Elasticsearch cluster and of the admin routes, and the user info of the configured URLs
+func (af *adminFacade) GetEffectiveConfig() *request.EffectiveConfigResponse {
+	cfg := af.config
+	cfg.Config.Backfill.Source = redactURLCredentials(cfg.Config.Backfill.Source)
+
+	clusterConfig := af.clusterConfig
+	clusterConfig.Config.ElasticCluster.URL = redactURLCredentials(clusterConfig.Config.ElasticCluster.URL)
+	clusterConfig.Config.ElasticCluster.UserName = redactValue(clusterConfig.Config.ElasticCluster.UserName)
+	clusterConfig.Config.ElasticCluster.Password = redactValue(clusterConfig.Config.ElasticCluster.Password)
+	clusterConfig.Config.WebSocket.URL = redactURLCredentials(clusterConfig.Config.WebSocket.URL)
+
+	apiConfig := af.apiConfig
+	apiConfig.Admin.Username = redactValue(apiConfig.Admin.Username)
+	apiConfig.Admin.Password = redactValue(apiConfig.Admin.Password)
+
+	return &request.EffectiveConfigResponse{
+		Config:           cfg,
+		ClusterConfig:    clusterConfig,
+		ApiConfig:        apiConfig,
+		EnabledIndices:   af.adminHandler.GetEnabledIndexes(),
+		ProcessingPaused: af.adminHandler.IsProcessingPaused(),
+	}
+}
+
+func redactValue(value string) string {
+	if value == "" {
+		return ""
+	}
+
+	return redactedValue
+}
+
+// redactURLCredentials replaces the user info of the provided URL, if any. A value that is not a URL is returned as it is
+func redactURLCredentials(value string) string {
+	parsedURL, err := url.Parse(value)
+	if err != nil || parsedURL.User == nil {
+		return value
+	}
+
+	parsedURL.User = nil
+	prefix := parsedURL.Scheme + "://"
+
+	return prefix + redactedValue + "@" + strings.TrimPrefix(parsedURL.String(), prefix)
+}
+
+// convertAdminError marks the errors caused by invalid input so they are reported as bad requests
+func convertAdminError(err error) error {
+	isRequestError := errors.Is(err, dataindexer.ErrUnknownIndex) || errors.Is(err, dataindexer.ErrInvalidNoncesRange)
+	if isRequestError {
+		return fmt.Errorf("%w: %s", core.ErrInvalidQueryParameter, err.Error())
+	}
+
+	return err
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (af *adminFacade) IsInterfaceNil() bool {
+	return af == nil
+}
@@ -1,23 +1,62 @@
 package facade
 
 import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
 	"github.com/kalyan3104/k-chain-core-go/core/check"
+	"github.com/kalyan3104/k-chain-es-indexer-go/config"
 	"github.com/kalyan3104/k-chain-es-indexer-go/core"
 	"github.com/kalyan3104/k-chain-es-indexer-go/core/request"
 )
 
+const (
+	defaultElasticsearchTimeout = 5 * time.Second
+	clusterHealthRed            = "red"
+
+	webSocketStateActive  = "active"
+	webSocketStateIdle    = "idle"
+	webSocketStateWaiting = "waiting"
+)
+
+// ArgsMetricsFacade holds all the components needed to create a new instance of metricsFacade
+type ArgsMetricsFacade struct {
+	StatusMetrics core.StatusMetricsHandler
+	ClusterHealth core.ClusterHealthHandler
+	HealthConfig  config.HealthConfig
+}
+
 type metricsFacade struct {
-	statusMetrics core.StatusMetricsHandler
+	statusMetrics        core.StatusMetricsHandler
+	clusterHealth        core.ClusterHealthHandler
+	healthConfig         config.HealthConfig
+	elasticsearchTimeout time.Duration
+	getNow               func() time.Time
 }
 
 // NewMetricsFacade will create a new instance of metricsFacade
-func NewMetricsFacade(statusMetrics core.StatusMetricsHandler) (*metricsFacade, error) {
-	if check.IfNil(statusMetrics) {
+func NewMetricsFacade(args ArgsMetricsFacade) (*metricsFacade, error) {
+	if check.IfNil(args.StatusMetrics) {
 		return nil, core.ErrNilMetricsHandler
 	}
+	if check.IfNil(args.ClusterHealth) {
+		return nil, core.ErrNilClusterHealthHandler
+	}
+
+	elasticsearchTimeout := defaultElasticsearchTimeout
+	if args.HealthConfig.ElasticsearchTimeoutInSeconds > 0 {
+		elasticsearchTimeout = time.Duration(args.HealthConfig.ElasticsearchTimeoutInSeconds) * time.Second
+	}
 
 	return &metricsFacade{
-		statusMetrics: statusMetrics,
+		statusMetrics:        args.StatusMetrics,
+		clusterHealth:        args.ClusterHealth,
+		healthConfig:         args.HealthConfig,
+		elasticsearchTimeout: elasticsearchTimeout,
+		getNow:               time.Now,
 	}, nil
 }
 
@@ -26,9 +65,82 @@ func (mf *metricsFacade) GetMetrics() map[string]*request.MetricsResponse {
 	return mf.statusMetrics.GetMetrics()
 }
 
-// GetMetricsForPrometheus will return metrics in prometheus format
-func (mf *metricsFacade) GetMetricsForPrometheus() string {
-	return mf.statusMetrics.GetMetricsForPrometheus()
+// GetPrometheusHandler will return the handler that exposes the metrics in prometheus format
+func (mf *metricsFacade) GetPrometheusHandler() http.Handler {
+	return mf.statusMetrics.GetPrometheusHandler()
+}
+
+// GetHealth will return the state of the Elasticsearch cluster, of the websocket connection and the indexing lag of
+// every shard. The indexer is considered ready when the cluster is usable and no shard lags behind more than allowed
+func (mf *metricsFacade) GetHealth() *request.HealthResponse {
+	now := mf.getNow()
+	health := &request.HealthResponse{
+		Elasticsearch: mf.getElasticsearchStatus(),
+		WebSocket:     mf.getWebSocketStatus(now),
+		Shards:        mf.statusMetrics.GetShardsIndexingStatus(),
+		MaxLagAllowed: mf.healthConfig.MaxIndexingLagInSeconds,
+	}
+
+	reasons := make([]string, 0)
+	if !health.Elasticsearch.Reachable {
+		reasons = append(reasons, "elasticsearch is not reachable")
+	} else if health.Elasticsearch.ClusterHealth == clusterHealthRed {
+		reasons = append(reasons, "elasticsearch cluster health is red")
+	}
+
+	for shardID, shardStatus := range health.Shards {
+		// the lag is computed at request time so an indexer that stopped receiving blocks is detected as well
+		shardStatus.LagInSeconds = now.Unix() - int64(shardStatus.LastIndexedTimestamp)
+
+		lagCheckEnabled := mf.healthConfig.MaxIndexingLagInSeconds > 0
+		if lagCheckEnabled && shardStatus.LagInSeconds > int64(mf.healthConfig.MaxIndexingLagInSeconds) {
+			reasons = append(reasons, fmt.Sprintf("shard %d lags behind with %d seconds", shardID, shardStatus.LagInSeconds))
+		}
+	}
+
+	sort.Strings(reasons)
+	health.Reasons = reasons
+	health.Ready = len(reasons) == 0
+
+	return health
+}
+
+func (mf *metricsFacade) getElasticsearchStatus() *request.ElasticsearchStatus {
+	ctx, cancel := context.WithTimeout(context.Background(), mf.elasticsearchTimeout)
+	defer cancel()
+
+	clusterHealth, err := mf.clusterHealth.GetClusterHealth(ctx)
+	if err != nil {
+		return &request.ElasticsearchStatus{
+			Reachable: false,
+			Error:     err.Error(),
+		}
+	}
+
+	return &request.ElasticsearchStatus{
+		Reachable:     true,
+		ClusterHealth: clusterHealth,
+	}
+}
+
+func (mf *metricsFacade) getWebSocketStatus(now time.Time) *request.WebSocketStatus {
+	lastPayloadTime := mf.statusMetrics.GetLastPayloadTime()
+	if lastPayloadTime.IsZero() {
+		return &request.WebSocketStatus{
+			State: webSocketStateWaiting,
+		}
+	}
+
+	state := webSocketStateActive
+	idleTimeout := time.Duration(mf.healthConfig.WebSocketIdleTimeoutInSeconds) * time.Second
+	if idleTimeout > 0 && now.Sub(lastPayloadTime) > idleTimeout {
+		state = webSocketStateIdle
+	}
+
+	return &request.WebSocketStatus{
+		State:           state,
+		LastPayloadTime: lastPayloadTime.Unix(),
+	}
 }
 
 // IsInterfaceNil returns true if there is no value under the interface
@@ -0,0 +1,236 @@
+package facade
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/kalyan3104/k-chain-core-go/core/check"
+	"github.com/kalyan3104/k-chain-es-indexer-go/config"
+	"github.com/kalyan3104/k-chain-es-indexer-go/core"
+	"github.com/kalyan3104/k-chain-es-indexer-go/metrics"
+	"github.com/kalyan3104/k-chain-es-indexer-go/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const testNowUnix = 1700000000
+
+func createMockArgsMetricsFacade() ArgsMetricsFacade {
+	return ArgsMetricsFacade{
+		StatusMetrics: metrics.NewStatusMetrics(),
+		ClusterHealth: &mock.ClusterHealthStub{},
+		HealthConfig: config.HealthConfig{
+			MaxIndexingLagInSeconds:       60,
+			WebSocketIdleTimeoutInSeconds: 30,
+		},
+	}
+}
+
+func createMetricsFacadeAtTestTime(t *testing.T, args ArgsMetricsFacade) *metricsFacade {
+	metricsFacadeInstance, err := NewMetricsFacade(args)
+	require.Nil(t, err)
+
+	metricsFacadeInstance.getNow = func() time.Time {
+		return time.Unix(testNowUnix, 0)
+	}
+
+	return metricsFacadeInstance
+}
+
+func TestNewMetricsFacade(t *testing.T) {
+	t.Parallel()
+
+	args := createMockArgsMetricsFacade()
+	args.StatusMetrics = nil
+	metricsFacadeInstance, err := NewMetricsFacade(args)
+	require.Nil(t, metricsFacadeInstance)
+	require.Equal(t, core.ErrNilMetricsHandler, err)
+
+	args = createMockArgsMetricsFacade()
+	args.ClusterHealth = nil
+	metricsFacadeInstance, err = NewMetricsFacade(args)
+	require.Nil(t, metricsFacadeInstance)
+	require.Equal(t, core.ErrNilClusterHealthHandler, err)
+
+	metricsFacadeInstance, err = NewMetricsFacade(createMockArgsMetricsFacade())
+	require.Nil(t, err)
+	require.False(t, check.IfNil(metricsFacadeInstance))
+	require.Equal(t, defaultElasticsearchTimeout, metricsFacadeInstance.elasticsearchTimeout)
+
+	args = createMockArgsMetricsFacade()
+	args.HealthConfig.ElasticsearchTimeoutInSeconds = 2
+	metricsFacadeInstance, _ = NewMetricsFacade(args)
+	require.Equal(t, 2*time.Second, metricsFacadeInstance.elasticsearchTimeout)
+}
+
+func TestMetricsFacade_GetHealthIndexingLag(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name            string
+		maxLag          uint64
+		lastIndexedAgo  uint64
+		expectedLag     int64
+		expectedReady   bool
+		expectedReasons []string
+	}{
+		{name: "no lag", maxLag: 60, lastIndexedAgo: 0, expectedLag: 0, expectedReady: true},
+		{name: "lag below the threshold", maxLag: 60, lastIndexedAgo: 59, expectedLag: 59, expectedReady: true},
+		{name: "lag at the threshold", maxLag: 60, lastIndexedAgo: 60, expectedLag: 60, expectedReady: true},
+		{
+			name:            "lag above the threshold",
+			maxLag:          60,
+			lastIndexedAgo:  61,
+			expectedLag:     61,
+			expectedReady:   false,
+			expectedReasons: []string{"shard 1 lags behind with 61 seconds"},
+		},
+		{name: "lag check disabled", maxLag: 0, lastIndexedAgo: 3600, expectedLag: 3600, expectedReady: true},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			args := createMockArgsMetricsFacade()
+			args.HealthConfig.MaxIndexingLagInSeconds = tt.maxLag
+			statusMetrics := metrics.NewStatusMetrics()
+			statusMetrics.SetLastIndexedBlock(1, 100, testNowUnix-tt.lastIndexedAgo)
+			args.StatusMetrics = statusMetrics
+			metricsFacadeInstance := createMetricsFacadeAtTestTime(t, args)
+
+			health := metricsFacadeInstance.GetHealth()
+			require.Equal(t, tt.expectedReady, health.Ready)
+			require.Equal(t, tt.expectedReasons, nilIfEmpty(health.Reasons))
+			require.Equal(t, tt.expectedLag, health.Shards[1].LagInSeconds)
+			require.Equal(t, uint64(100), health.Shards[1].LastIndexedNonce)
+			require.Equal(t, tt.maxLag, health.MaxLagAllowed)
+		})
+	}
+}
+
+func TestMetricsFacade_GetHealthReadiness(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name              string
+		clusterHealth     string
+		clusterHealthErr  error
+		shardsLagInSecond map[uint32]uint64
+		expectedReady     bool
+		expectedReasons   []string
+	}{
+		{name: "green cluster without shards", clusterHealth: "green", expectedReady: true},
+		{name: "yellow cluster", clusterHealth: "yellow", shardsLagInSecond: map[uint32]uint64{0: 10}, expectedReady: true},
+		{
+			name:            "red cluster",
+			clusterHealth:   "red",
+			expectedReady:   false,
+			expectedReasons: []string{"elasticsearch cluster health is red"},
+		},
+		{
+			name:             "unreachable cluster",
+			clusterHealthErr: errors.New("connection refused"),
+			expectedReady:    false,
+			expectedReasons:  []string{"elasticsearch is not reachable"},
+		},
+		{
+			name:              "every reason is reported, sorted",
+			clusterHealth:     "red",
+			shardsLagInSecond: map[uint32]uint64{0: 10, 1: 120, 2: 61},
+			expectedReady:     false,
+			expectedReasons: []string{
+				"elasticsearch cluster health is red",
+				"shard 1 lags behind with 120 seconds",
+				"shard 2 lags behind with 61 seconds",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			args := createMockArgsMetricsFacade()
+			args.ClusterHealth = &mock.ClusterHealthStub{
+				GetClusterHealthCalled: func(ctx context.Context) (string, error) {
+					_, hasDeadline := ctx.Deadline()
+					require.True(t, hasDeadline)
+
+					return tt.clusterHealth, tt.clusterHealthErr
+				},
+			}
+			statusMetrics := metrics.NewStatusMetrics()
+			for shardID, lag := range tt.shardsLagInSecond {
+				statusMetrics.SetLastIndexedBlock(shardID, 1, testNowUnix-lag)
+			}
+			args.StatusMetrics = statusMetrics
+			metricsFacadeInstance := createMetricsFacadeAtTestTime(t, args)
+
+			health := metricsFacadeInstance.GetHealth()
+			require.Equal(t, tt.expectedReady, health.Ready)
+			require.Equal(t, tt.expectedReasons, nilIfEmpty(health.Reasons))
+			require.Equal(t, tt.clusterHealthErr == nil, health.Elasticsearch.Reachable)
+			require.Equal(t, tt.clusterHealth, health.Elasticsearch.ClusterHealth)
+			if tt.clusterHealthErr != nil {
+				require.Equal(t, tt.clusterHealthErr.Error(), health.Elasticsearch.Error)
+			}
+		})
+	}
+}
+
+func TestMetricsFacade_GetHealthWebSocketState(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		idleTimeout    uint64
+		lastPayloadAgo time.Duration
+		noPayload      bool
+		expectedState  string
+	}{
+		{name: "no payload received", idleTimeout: 30, noPayload: true, expectedState: webSocketStateWaiting},
+		{name: "recent payload", idleTimeout: 30, lastPayloadAgo: time.Second, expectedState: webSocketStateActive},
+		{name: "payload at the idle timeout", idleTimeout: 30, lastPayloadAgo: 30 * time.Second, expectedState: webSocketStateActive},
+		{name: "payload older than the idle timeout", idleTimeout: 30, lastPayloadAgo: 31 * time.Second, expectedState: webSocketStateIdle},
+		{name: "idle check disabled", idleTimeout: 0, lastPayloadAgo: time.Hour, expectedState: webSocketStateActive},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			args := createMockArgsMetricsFacade()
+			args.HealthConfig.WebSocketIdleTimeoutInSeconds = tt.idleTimeout
+			statusMetrics := metrics.NewStatusMetrics()
+			lastPayloadTime := time.Unix(testNowUnix, 0).Add(-tt.lastPayloadAgo)
+			if !tt.noPayload {
+				statusMetrics.SetLastPayloadTime(lastPayloadTime)
+			}
+			args.StatusMetrics = statusMetrics
+			metricsFacadeInstance := createMetricsFacadeAtTestTime(t, args)
+
+			health := metricsFacadeInstance.GetHealth()
+			require.Equal(t, tt.expectedState, health.WebSocket.State)
+			if tt.noPayload {
+				require.Zero(t, health.WebSocket.LastPayloadTime)
+			} else {
+				require.Equal(t, lastPayloadTime.Unix(), health.WebSocket.LastPayloadTime)
+			}
+			// the websocket state is reported, but it does not change the readiness
+			require.True(t, health.Ready)
+		})
+	}
+}
+
+func nilIfEmpty(reasons []string) []string {
+	if len(reasons) == 0 {
+		return nil
+	}
+
+	return reasons
+}
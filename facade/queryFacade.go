@@ -0,0 +1,287 @@
+package facade
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+
+	"github.com/kalyan3104/k-chain-core-go/core/check"
+	"github.com/kalyan3104/k-chain-es-indexer-go/core"
+	"github.com/kalyan3104/k-chain-es-indexer-go/data"
+	"github.com/kalyan3104/k-chain-es-indexer-go/process/dataindexer"
+)
+
+const (
+	// DefaultPageSize is the number of documents returned on a page when no size is provided
+	DefaultPageSize = 25
+	// MaxPageSize is the maximum number of documents that can be requested on a page
+	MaxPageSize = 100
+)
+
+type objectsMap = map[string]interface{}
+
+type queryFacade struct {
+	databaseReader core.DatabaseReaderHandler
+}
+
+// NewQueryFacade will create a new instance of queryFacade
+func NewQueryFacade(databaseReader core.DatabaseReaderHandler) (*queryFacade, error) {
+	if check.IfNil(databaseReader) {
+		return nil, core.ErrNilDatabaseReader
+	}
+
+	return &queryFacade{
+		databaseReader: databaseReader,
+	}, nil
+}
+
+// GetTransaction will return the transaction with the provided hash
+func (qf *queryFacade) GetTransaction(ctx context.Context, hash string) (*data.ApiTransaction, error) {
+	tx := &data.Transaction{}
+	err := qf.getDocumentByID(ctx, dataindexer.TransactionsIndex, hash, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &data.ApiTransaction{
+		Hash:        hash,
+		Transaction: tx,
+	}, nil
+}
+
+// GetAccount will return the account with the provided address
+func (qf *queryFacade) GetAccount(ctx context.Context, address string) (*data.AccountInfo, error) {
+	account := &data.AccountInfo{}
+	err := qf.getDocumentByID(ctx, dataindexer.AccountsIndex, address, account)
+	if err != nil {
+		return nil, err
+	}
+
+	return account, nil
+}
+
+// GetToken will return the token with the provided identifier
+func (qf *queryFacade) GetToken(ctx context.Context, identifier string) (*data.TokenInfo, error) {
+	token := &data.TokenInfo{}
+	err := qf.getDocumentByID(ctx, dataindexer.TokensIndex, identifier, token)
+	if err != nil {
+		return nil, err
+	}
+
+	return token, nil
+}
+
+// GetBlock will return the block with the provided nonce from the provided shard
+func (qf *queryFacade) GetBlock(ctx context.Context, shardID uint32, nonce uint64) (*data.ApiBlock, error) {
+	query := objectsMap{
+		"query": objectsMap{
+			"bool": objectsMap{
+				"filter": []interface{}{
+					objectsMap{"term": objectsMap{"shardId": shardID}},
+					objectsMap{"term": objectsMap{"nonce": nonce}},
+				},
+			},
+		},
+		"size": 1,
+	}
+
+	hits, err := qf.search(ctx, dataindexer.BlockIndex, query)
+	if err != nil {
+		return nil, err
+	}
+	if len(hits) == 0 {
+		return nil, core.ErrDocumentNotFound
+	}
+
+	block := &data.Block{}
+	err = json.Unmarshal(hits[0].Source, block)
+	if err != nil {
+		return nil, err
+	}
+
+	return &data.ApiBlock{
+		Hash:  hits[0].ID,
+		Block: block,
+	}, nil
+}
+
+// GetAccountTokens will return a page with the tokens held by the provided address
+func (qf *queryFacade) GetAccountTokens(ctx context.Context, address string, size int, cursor string) (*data.ApiAccountTokensPage, error) {
+	query := objectsMap{
+		"query": objectsMap{
+			"term": objectsMap{"address": address},
+		},
+		"sort": []interface{}{
+			objectsMap{"token": objectsMap{"order": "asc"}},
+			objectsMap{"tokenNonce": objectsMap{"order": "asc"}},
+		},
+	}
+
+	hits, nextCursor, err := qf.searchPage(ctx, dataindexer.AccountsDCDTIndex, query, size, cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	tokens := make([]*data.AccountInfo, 0, len(hits))
+	for _, hit := range hits {
+		token := &data.AccountInfo{}
+		err = json.Unmarshal(hit.Source, token)
+		if err != nil {
+			return nil, err
+		}
+
+		tokens = append(tokens, token)
+	}
+
+	return &data.ApiAccountTokensPage{
+		Tokens:     tokens,
+		NextCursor: nextCursor,
+	}, nil
+}
+
+// GetAccountTransactions will return a page with the transactions sent or received by the provided address, the most
+// recent first
+func (qf *queryFacade) GetAccountTransactions(ctx context.Context, address string, size int, cursor string) (*data.ApiTransactionsPage, error) {
+	query := objectsMap{
+		"query": objectsMap{
+			"bool": objectsMap{
+				"should": []interface{}{
+					objectsMap{"term": objectsMap{"sender": address}},
+					objectsMap{"term": objectsMap{"receiver": address}},
+				},
+				"minimum_should_match": 1,
+			},
+		},
+		"sort": []interface{}{
+			objectsMap{"timestamp": objectsMap{"order": "desc"}},
+			objectsMap{"sender": objectsMap{"order": "asc"}},
+			objectsMap{"nonce": objectsMap{"order": "desc"}},
+			// the transactions with the same timestamp, sender and nonce are ordered by their position in the block,
+			// so no page skips or repeats them. The _id field is not used, as sorting on it loads it in memory
+			objectsMap{"searchOrder": objectsMap{"order": "asc"}},
+		},
+	}
+
+	hits, nextCursor, err := qf.searchPage(ctx, dataindexer.TransactionsIndex, query, size, cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	txs := make([]*data.ApiTransaction, 0, len(hits))
+	for _, hit := range hits {
+		tx := &data.Transaction{}
+		err = json.Unmarshal(hit.Source, tx)
+		if err != nil {
+			return nil, err
+		}
+
+		txs = append(txs, &data.ApiTransaction{
+			Hash:        hit.ID,
+			Transaction: tx,
+		})
+	}
+
+	return &data.ApiTransactionsPage{
+		Transactions: txs,
+		NextCursor:   nextCursor,
+	}, nil
+}
+
+func (qf *queryFacade) getDocumentByID(ctx context.Context, index string, id string, dest interface{}) error {
+	response := &data.ResponseDocs{}
+	err := qf.databaseReader.DoMultiGet(ctx, []string{id}, index, true, response)
+	if err != nil {
+		return err
+	}
+
+	if len(response.Docs) == 0 || !response.Docs[0].Found {
+		return core.ErrDocumentNotFound
+	}
+
+	return json.Unmarshal(response.Docs[0].Source, dest)
+}
+
+func (qf *queryFacade) search(ctx context.Context, index string, query objectsMap) ([]*data.ResponseSearchHit, error) {
+	body, err := json.Marshal(query)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &data.ResponseSearch{}
+	err = qf.databaseReader.DoSearchRequest(ctx, index, body, response)
+	if err != nil {
+		return nil, err
+	}
+
+	return response.Hits.Hits, nil
+}
+
+// searchPage will return a page of documents using search_after. The cursor holds the sort values of the last document
+// of the previous page, one for every sort key of the query, and the returned cursor is empty when there are no more
+// documents
+func (qf *queryFacade) searchPage(ctx context.Context, index string, query objectsMap, size int, cursor string) ([]*data.ResponseSearchHit, string, error) {
+	if size <= 0 {
+		size = DefaultPageSize
+	}
+	if size > MaxPageSize {
+		return nil, "", fmt.Errorf("%w: size cannot be greater than %d", core.ErrInvalidQueryParameter, MaxPageSize)
+	}
+
+	query["size"] = size
+	if cursor != "" {
+		searchAfter, err := decodeCursor(cursor)
+		if err != nil {
+			return nil, "", err
+		}
+		sortKeys, _ := query["sort"].([]interface{})
+		if len(searchAfter) != len(sortKeys) {
+			return nil, "", fmt.Errorf("%w: cursor", core.ErrInvalidQueryParameter)
+		}
+		query["search_after"] = searchAfter
+	}
+
+	hits, err := qf.search(ctx, index, query)
+	if err != nil {
+		return nil, "", err
+	}
+	if len(hits) < size {
+		return hits, "", nil
+	}
+
+	nextCursor, err := encodeCursor(hits[len(hits)-1].Sort)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return hits, nextCursor, nil
+}
+
+func encodeCursor(sortValues []json.RawMessage) (string, error) {
+	sortValuesBytes, err := json.Marshal(sortValues)
+	if err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(sortValuesBytes), nil
+}
+
+func decodeCursor(cursor string) ([]json.RawMessage, error) {
+	sortValuesBytes, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, fmt.Errorf("%w: cursor", core.ErrInvalidQueryParameter)
+	}
+
+	sortValues := make([]json.RawMessage, 0)
+	err = json.Unmarshal(sortValuesBytes, &sortValues)
+	if err != nil || len(sortValues) == 0 {
+		return nil, fmt.Errorf("%w: cursor", core.ErrInvalidQueryParameter)
+	}
+
+	return sortValues, nil
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (qf *queryFacade) IsInterfaceNil() bool {
+	return qf == nil
+}
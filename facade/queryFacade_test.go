@@ -0,0 +1,225 @@
+package facade
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/kalyan3104/k-chain-core-go/core/check"
+	"github.com/kalyan3104/k-chain-es-indexer-go/core"
+	"github.com/kalyan3104/k-chain-es-indexer-go/mock"
+	"github.com/kalyan3104/k-chain-es-indexer-go/process/dataindexer"
+	"github.com/stretchr/testify/require"
+)
+
+type searchRequestBody struct {
+	Size        int               `json:"size"`
+	Sort        []json.RawMessage `json:"sort"`
+	SearchAfter []json.RawMessage `json:"search_after"`
+}
+
+func TestNewQueryFacade(t *testing.T) {
+	t.Parallel()
+
+	queryFacadeInstance, err := NewQueryFacade(nil)
+	require.Nil(t, queryFacadeInstance)
+	require.Equal(t, core.ErrNilDatabaseReader, err)
+
+	queryFacadeInstance, err = NewQueryFacade(&mock.DatabaseWriterStub{})
+	require.Nil(t, err)
+	require.False(t, check.IfNil(queryFacadeInstance))
+}
+
+func TestQueryFacade_GetTransaction(t *testing.T) {
+	t.Parallel()
+
+	found := true
+	queryFacadeInstance, _ := NewQueryFacade(&mock.DatabaseWriterStub{
+		DoMultiGetCalled: func(ids []string, index string, withSource bool, response interface{}) error {
+			require.Equal(t, []string{"h1"}, ids)
+			require.Equal(t, dataindexer.TransactionsIndex, index)
+			require.True(t, withSource)
+
+			if !found {
+				return json.Unmarshal([]byte(`{"docs":[{"_id":"h1","found":false}]}`), response)
+			}
+			return json.Unmarshal([]byte(`{"docs":[{"_id":"h1","found":true,"_source":{"nonce":5,"sender":"alice"}}]}`), response)
+		},
+	})
+
+	tx, err := queryFacadeInstance.GetTransaction(context.Background(), "h1")
+	require.Nil(t, err)
+	require.Equal(t, "h1", tx.Hash)
+	require.Equal(t, uint64(5), tx.Nonce)
+	require.Equal(t, "alice", tx.Sender)
+
+	found = false
+	tx, err = queryFacadeInstance.GetTransaction(context.Background(), "h1")
+	require.Nil(t, tx)
+	require.Equal(t, core.ErrDocumentNotFound, err)
+}
+
+func TestQueryFacade_GetBlockWithoutHitsShouldErr(t *testing.T) {
+	t.Parallel()
+
+	queryFacadeInstance, _ := NewQueryFacade(&mock.DatabaseWriterStub{
+		DoSearchRequestCalled: func(index string, body []byte, resBody interface{}) error {
+			require.Equal(t, dataindexer.BlockIndex, index)
+			require.JSONEq(t, `{"query":{"bool":{"filter":[{"term":{"shardId":1}},{"term":{"nonce":10}}]}},"size":1}`, string(body))
+
+			return json.Unmarshal([]byte(`{"hits":{"hits":[]}}`), resBody)
+		},
+	})
+
+	block, err := queryFacadeInstance.GetBlock(context.Background(), 1, 10)
+	require.Nil(t, block)
+	require.Equal(t, core.ErrDocumentNotFound, err)
+}
+
+func TestQueryFacade_GetAccountTransactionsShouldPageWithSearchAfter(t *testing.T) {
+	t.Parallel()
+
+	pages := []string{
+		`{"hits":{"hits":[` +
+			`{"_id":"h1","_source":{"nonce":2,"sender":"alice"},"sort":[1700000010,"alice",2,0]},` +
+			`{"_id":"h2","_source":{"nonce":1,"sender":"alice"},"sort":[1700000005,"alice",1,3]}]}}`,
+		`{"hits":{"hits":[` +
+			`{"_id":"h3","_source":{"nonce":7,"sender":"bob"},"sort":[1700000001,"bob",7,1]}]}}`,
+	}
+	requests := make([]*searchRequestBody, 0)
+	queryFacadeInstance, _ := NewQueryFacade(&mock.DatabaseWriterStub{
+		DoSearchRequestCalled: func(index string, body []byte, resBody interface{}) error {
+			require.Equal(t, dataindexer.TransactionsIndex, index)
+
+			request := &searchRequestBody{}
+			err := json.Unmarshal(body, request)
+			require.Nil(t, err)
+			requests = append(requests, request)
+
+			return json.Unmarshal([]byte(pages[len(requests)-1]), resBody)
+		},
+	})
+
+	firstPage, err := queryFacadeInstance.GetAccountTransactions(context.Background(), "alice", 2, "")
+	require.Nil(t, err)
+	require.Len(t, firstPage.Transactions, 2)
+	require.Equal(t, "h1", firstPage.Transactions[0].Hash)
+	require.Equal(t, "h2", firstPage.Transactions[1].Hash)
+	require.NotEmpty(t, firstPage.NextCursor)
+	require.Equal(t, 2, requests[0].Size)
+	require.Nil(t, requests[0].SearchAfter)
+	require.Len(t, requests[0].Sort, 4)
+	require.JSONEq(t, `{"searchOrder":{"order":"asc"}}`, string(requests[0].Sort[3]))
+
+	// the cursor holds the sort values of the last transaction from the previous page
+	secondPage, err := queryFacadeInstance.GetAccountTransactions(context.Background(), "alice", 2, firstPage.NextCursor)
+	require.Nil(t, err)
+	require.Len(t, secondPage.Transactions, 1)
+	require.Equal(t, "h3", secondPage.Transactions[0].Hash)
+	require.Equal(t, uint64(7), secondPage.Transactions[0].Nonce)
+	require.Empty(t, secondPage.NextCursor)
+
+	searchAfter, err := json.Marshal(requests[1].SearchAfter)
+	require.Nil(t, err)
+	require.JSONEq(t, `[1700000005,"alice",1,3]`, string(searchAfter))
+}
+
+func TestQueryFacade_GetAccountTokensPageSize(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		size         int
+		expectedSize int
+		expectedErr  error
+	}{
+		{name: "no size should use the default", size: 0, expectedSize: DefaultPageSize},
+		{name: "negative size should use the default", size: -3, expectedSize: DefaultPageSize},
+		{name: "size below the maximum", size: 10, expectedSize: 10},
+		{name: "maximum size", size: MaxPageSize, expectedSize: MaxPageSize},
+		{name: "size above the maximum should err", size: MaxPageSize + 1, expectedErr: core.ErrInvalidQueryParameter},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			requestedSize := 0
+			queryFacadeInstance, _ := NewQueryFacade(&mock.DatabaseWriterStub{
+				DoSearchRequestCalled: func(index string, body []byte, resBody interface{}) error {
+					require.Equal(t, dataindexer.AccountsDCDTIndex, index)
+
+					request := &searchRequestBody{}
+					err := json.Unmarshal(body, request)
+					require.Nil(t, err)
+					requestedSize = request.Size
+
+					return json.Unmarshal([]byte(`{"hits":{"hits":[{"_id":"alice-TKN-abcdef","_source":{"address":"alice","token":"TKN-abcdef","balance":"10"},"sort":["TKN-abcdef",0]}]}}`), resBody)
+				},
+			})
+
+			page, err := queryFacadeInstance.GetAccountTokens(context.Background(), "alice", tt.size, "")
+			if tt.expectedErr != nil {
+				require.True(t, errors.Is(err, tt.expectedErr))
+				require.Nil(t, page)
+				require.Zero(t, requestedSize, "no search should be made")
+				return
+			}
+
+			require.Nil(t, err)
+			require.Equal(t, tt.expectedSize, requestedSize)
+			require.Len(t, page.Tokens, 1)
+			require.Equal(t, "TKN-abcdef", page.Tokens[0].TokenName)
+			require.Empty(t, page.NextCursor)
+		})
+	}
+}
+
+func TestQueryFacade_GetAccountTransactionsWithMalformedCursorShouldErr(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		cursor string
+	}{
+		{name: "not base64", cursor: "not a cursor!"},
+		{name: "padded base64", cursor: base64.URLEncoding.EncodeToString([]byte(`["a"]`))},
+		{name: "not json", cursor: base64.RawURLEncoding.EncodeToString([]byte("alice"))},
+		{name: "json object", cursor: base64.RawURLEncoding.EncodeToString([]byte(`{"timestamp":1}`))},
+		{name: "no sort values", cursor: base64.RawURLEncoding.EncodeToString([]byte(`[]`))},
+		{name: "no hash tiebreaker", cursor: base64.RawURLEncoding.EncodeToString([]byte(`[1700000005,"alice",1]`))},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			queryFacadeInstance, _ := NewQueryFacade(&mock.DatabaseWriterStub{
+				DoSearchRequestCalled: func(index string, body []byte, resBody interface{}) error {
+					require.Fail(t, "no search should be made with a malformed cursor")
+					return nil
+				},
+			})
+
+			page, err := queryFacadeInstance.GetAccountTransactions(context.Background(), "alice", 10, tt.cursor)
+			require.Nil(t, page)
+			require.True(t, errors.Is(err, core.ErrInvalidQueryParameter))
+		})
+	}
+}
+
+func TestQueryFacade_CursorRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	sortValues := []json.RawMessage{json.RawMessage(`1700000005`), json.RawMessage(`"alice"`), json.RawMessage(`1`), json.RawMessage(`3`)}
+	cursor, err := encodeCursor(sortValues)
+	require.Nil(t, err)
+
+	decoded, err := decodeCursor(cursor)
+	require.Nil(t, err)
+	require.Equal(t, sortValues, decoded)
+}
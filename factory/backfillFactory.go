@@ -0,0 +1,40 @@
+package factory
+
+import (
+	"time"
+
+	factoryMarshaller "github.com/kalyan3104/k-chain-core-go/marshal/factory"
+	"github.com/kalyan3104/k-chain-es-indexer-go/config"
+	"github.com/kalyan3104/k-chain-es-indexer-go/core"
+	"github.com/kalyan3104/k-chain-es-indexer-go/process/backfill"
+)
+
+// CreateBackfiller will create the component that indexes again a range of blocks from the provided source. When the
+// source is empty, the source from the configuration is used
+func CreateBackfiller(cfg config.Config, clusterCfg config.ClusterConfig, statusMetrics core.StatusMetricsHandler, source string, version string) (backfill.Backfiller, error) {
+	wsMarshaller, err := factoryMarshaller.NewMarshalizer(clusterCfg.Config.WebSocket.DataMarshallerType)
+	if err != nil {
+		return nil, err
+	}
+
+	if source == "" {
+		source = cfg.Config.Backfill.Source
+	}
+	requestTimeout := time.Duration(cfg.Config.Backfill.RequestTimeoutInSeconds) * time.Second
+	blockSource, err := backfill.CreateBlockSource(source, requestTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	// the backfilled blocks are not recorded again
+	dataIndexer, err := createDataIndexer(cfg, clusterCfg, wsMarshaller, statusMetrics, nil, version)
+	if err != nil {
+		return nil, err
+	}
+
+	return backfill.NewBackfiller(backfill.ArgsBackfiller{
+		Marshaller:  wsMarshaller,
+		DataIndexer: dataIndexer,
+		BlockSource: blockSource,
+	})
+}
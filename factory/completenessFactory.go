@@ -0,0 +1,64 @@
+package factory
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/elastic/go-elasticsearch/v7"
+	"github.com/kalyan3104/k-chain-core-go/core/check"
+	"github.com/kalyan3104/k-chain-es-indexer-go/client"
+	"github.com/kalyan3104/k-chain-es-indexer-go/client/logging"
+	"github.com/kalyan3104/k-chain-es-indexer-go/client/transport"
+	"github.com/kalyan3104/k-chain-es-indexer-go/config"
+	"github.com/kalyan3104/k-chain-es-indexer-go/core"
+	"github.com/kalyan3104/k-chain-es-indexer-go/process/completeness"
+)
+
+// CreateCompletenessVerifier will create the component that checks the indexed blocks against the rest of the indexed data
+func CreateCompletenessVerifier(cfg config.Config, clusterCfg config.ClusterConfig, statusMetrics core.StatusMetricsHandler) (completeness.BlocksVerifier, error) {
+	argsEsClient := elasticsearch.Config{
+		Addresses:     []string{clusterCfg.Config.ElasticCluster.URL},
+		Username:      clusterCfg.Config.ElasticCluster.UserName,
+		Password:      clusterCfg.Config.ElasticCluster.Password,
+		Logger:        &logging.CustomLogger{},
+		RetryOnStatus: []int{http.StatusConflict},
+	}
+	if !check.IfNil(statusMetrics) {
+		transportMetrics, err := transport.NewMetricsTransport(statusMetrics)
+		if err != nil {
+			return nil, err
+		}
+		argsEsClient.Transport = transportMetrics
+	}
+
+	databaseClient, err := client.NewElasticClient(argsEsClient)
+	if err != nil {
+		return nil, err
+	}
+
+	return completeness.NewCompletenessVerifier(completeness.ArgsCompletenessVerifier{
+		DBClient:           databaseClient,
+		EnabledIndices:     prepareIndices(cfg.Config.AvailableIndices, clusterCfg.Config.DisabledIndices),
+		BulkRequestMaxSize: clusterCfg.Config.ElasticCluster.BulkRequestMaxSizeInBytes,
+	})
+}
+
+// CreatePeriodicVerifier will create the background job that verifies the newly indexed blocks. It returns nil when the job
+// is disabled
+func CreatePeriodicVerifier(cfg config.Config, clusterCfg config.ClusterConfig, statusMetrics core.StatusMetricsHandler) (completeness.PeriodicVerifier, error) {
+	verifierCfg := cfg.Config.CompletenessVerifier
+	if !verifierCfg.Enabled {
+		return nil, nil
+	}
+
+	blocksVerifier, err := CreateCompletenessVerifier(cfg, clusterCfg, statusMetrics)
+	if err != nil {
+		return nil, err
+	}
+
+	return completeness.NewPeriodicVerifier(completeness.ArgsPeriodicVerifier{
+		BlocksVerifier: blocksVerifier,
+		Interval:       time.Duration(verifierCfg.IntervalInSeconds) * time.Second,
+		Delay:          time.Duration(verifierCfg.DelayInSeconds) * time.Second,
+	})
+}
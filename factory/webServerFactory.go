@@ -1,22 +1,66 @@
 package factory
 
 import (
+	"github.com/elastic/go-elasticsearch/v7"
 	"github.com/kalyan3104/k-chain-es-indexer-go/api/gin"
+	"github.com/kalyan3104/k-chain-es-indexer-go/client"
+	"github.com/kalyan3104/k-chain-es-indexer-go/client/logging"
 	"github.com/kalyan3104/k-chain-es-indexer-go/config"
 	"github.com/kalyan3104/k-chain-es-indexer-go/core"
 	"github.com/kalyan3104/k-chain-es-indexer-go/facade"
 )
 
+// ArgsCreateWebServer holds all the components and configs needed to create the web server
+type ArgsCreateWebServer struct {
+	ApiConfig     config.ApiRoutesConfig
+	Config        config.Config
+	ClusterConfig config.ClusterConfig
+	StatusMetrics core.StatusMetricsHandler
+	AdminHandler  core.IndexerAdminHandler
+}
+
 // CreateWebServer will create a new instance of core.WebServerHandler
-func CreateWebServer(apiConfig config.ApiRoutesConfig, statusMetricsHandler core.StatusMetricsHandler) (core.WebServerHandler, error) {
-	metricsFacade, err := facade.NewMetricsFacade(statusMetricsHandler)
+func CreateWebServer(args ArgsCreateWebServer) (core.WebServerHandler, error) {
+	apiConfig, clusterCfg := args.ApiConfig, args.ClusterConfig
+	databaseClient, err := client.NewElasticClient(elasticsearch.Config{
+		Addresses: []string{clusterCfg.Config.ElasticCluster.URL},
+		Username:  clusterCfg.Config.ElasticCluster.UserName,
+		Password:  clusterCfg.Config.ElasticCluster.Password,
+		Logger:    &logging.CustomLogger{},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	metricsFacade, err := facade.NewMetricsFacade(facade.ArgsMetricsFacade{
+		StatusMetrics: args.StatusMetrics,
+		ClusterHealth: databaseClient,
+		HealthConfig:  apiConfig.Health,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	queryFacade, err := facade.NewQueryFacade(databaseClient)
+	if err != nil {
+		return nil, err
+	}
+
+	adminFacade, err := facade.NewAdminFacade(facade.ArgsAdminFacade{
+		AdminHandler:  args.AdminHandler,
+		Config:        args.Config,
+		ClusterConfig: clusterCfg,
+		ApiConfig:     apiConfig,
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	args := gin.ArgsWebServer{
-		Facade:    metricsFacade,
-		ApiConfig: apiConfig,
+	argsWebServer := gin.ArgsWebServer{
+		Facade:      metricsFacade,
+		QueryFacade: queryFacade,
+		AdminFacade: adminFacade,
+		ApiConfig:   apiConfig,
 	}
-	return gin.NewWebServer(args)
+	return gin.NewWebServer(argsWebServer)
 }
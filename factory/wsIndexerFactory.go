@@ -1,6 +1,8 @@
 package factory
 
 import (
+	"time"
+
 	"github.com/kalyan3104/k-chain-communication-go/websocket/data"
 	factoryHost "github.com/kalyan3104/k-chain-communication-go/websocket/factory"
 	"github.com/kalyan3104/k-chain-core-go/core/pubkeyConverter"
@@ -9,6 +11,8 @@ import (
 	factoryMarshaller "github.com/kalyan3104/k-chain-core-go/marshal/factory"
 	"github.com/kalyan3104/k-chain-es-indexer-go/config"
 	"github.com/kalyan3104/k-chain-es-indexer-go/core"
+	"github.com/kalyan3104/k-chain-es-indexer-go/process/backfill"
+	"github.com/kalyan3104/k-chain-es-indexer-go/process/dataindexer"
 	"github.com/kalyan3104/k-chain-es-indexer-go/process/factory"
 	"github.com/kalyan3104/k-chain-es-indexer-go/process/wsindexer"
 	logger "github.com/kalyan3104/k-chain-logger-go"
@@ -16,16 +20,22 @@ import (
 
 var log = logger.GetOrCreate("elasticindexer")
 
-// CreateWsIndexer will create a new instance of wsindexer.WSClient
-func CreateWsIndexer(cfg config.Config, clusterCfg config.ClusterConfig, statusMetrics core.StatusMetricsHandler, version string) (wsindexer.WSClient, error) {
+// CreateWsIndexer will create a new instance of wsindexer.WSClient together with the component that allows the
+// operators to control the indexer at runtime
+func CreateWsIndexer(cfg config.Config, clusterCfg config.ClusterConfig, statusMetrics core.StatusMetricsHandler, version string) (wsindexer.WSClient, core.IndexerAdminHandler, error) {
 	wsMarshaller, err := factoryMarshaller.NewMarshalizer(clusterCfg.Config.WebSocket.DataMarshallerType)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	dataIndexer, err := createDataIndexer(cfg, clusterCfg, wsMarshaller, statusMetrics, version)
+	blockRecorder, err := createBlockRecorder(cfg, wsMarshaller)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
+	}
+
+	dataIndexer, err := createDataIndexer(cfg, clusterCfg, wsMarshaller, statusMetrics, blockRecorder, version)
+	if err != nil {
+		return nil, nil, err
 	}
 
 	args := wsindexer.ArgsIndexer{
@@ -35,20 +45,30 @@ func CreateWsIndexer(cfg config.Config, clusterCfg config.ClusterConfig, statusM
 	}
 	indexer, err := wsindexer.NewIndexer(args)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	host, err := createWsHost(clusterCfg, wsMarshaller)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	err = host.SetPayloadHandler(indexer)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return host, indexer, nil
+}
+
+// CreateDataIndexer will create a data indexer that is not connected to an observer, used by the maintenance commands
+func CreateDataIndexer(cfg config.Config, clusterCfg config.ClusterConfig, statusMetrics core.StatusMetricsHandler, version string) (wsindexer.DataIndexer, error) {
+	wsMarshaller, err := factoryMarshaller.NewMarshalizer(clusterCfg.Config.WebSocket.DataMarshallerType)
 	if err != nil {
 		return nil, err
 	}
 
-	return host, nil
+	return createDataIndexer(cfg, clusterCfg, wsMarshaller, statusMetrics, nil, version)
 }
 
 func createDataIndexer(
@@ -56,6 +76,7 @@ func createDataIndexer(
 	clusterCfg config.ClusterConfig,
 	wsMarshaller marshal.Marshalizer,
 	statusMetrics core.StatusMetricsHandler,
+	blockRecorder dataindexer.BlockRecorder,
 	version string,
 ) (wsindexer.DataIndexer, error) {
 	marshaller, err := factoryMarshaller.NewMarshalizer(cfg.Config.Marshaller.Type)
@@ -89,10 +110,26 @@ func createDataIndexer(
 		ValidatorPubkeyConverter: validatorPubkeyConverter,
 		HeaderMarshaller:         wsMarshaller,
 		StatusMetrics:            statusMetrics,
+		SlowBlockThreshold:       time.Duration(cfg.Config.StageTimings.SlowBlockThresholdInMs) * time.Millisecond,
+		StagesTraceFilePath:      cfg.Config.StageTimings.TraceFilePath,
+		ContributionsRetention:   time.Duration(cfg.Config.StatsContributions.RetentionInDays) * 24 * time.Hour,
+		BlockRecorder:            blockRecorder,
 		Version:                  version,
 	})
 }
 
+// createBlockRecorder returns nil when the recording of the blocks is disabled
+func createBlockRecorder(cfg config.Config, wsMarshaller marshal.Marshalizer) (dataindexer.BlockRecorder, error) {
+	if !cfg.Config.BlockRecordings.Enabled {
+		return nil, nil
+	}
+
+	return backfill.NewBlockRecorder(backfill.ArgsBlockRecorder{
+		Marshaller:    wsMarshaller,
+		DirectoryPath: cfg.Config.BlockRecordings.Path,
+	})
+}
+
 func prepareIndices(availableIndices, disabledIndices []string) []string {
 	indices := make([]string, 0)
 
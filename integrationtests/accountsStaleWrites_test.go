@@ -0,0 +1,92 @@
+//go:build integrationtests
+
+package integrationtests
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/kalyan3104/k-chain-core-go/data/alteredAccount"
+	dataBlock "github.com/kalyan3104/k-chain-core-go/data/block"
+	"github.com/kalyan3104/k-chain-core-go/data/outport"
+	"github.com/kalyan3104/k-chain-es-indexer-go/mock"
+	indexerdata "github.com/kalyan3104/k-chain-es-indexer-go/process/dataindexer"
+	"github.com/kalyan3104/k-chain-es-indexer-go/process/elasticproc/factory"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccountsOlderWriteAfterNewerShouldBeIgnored(t *testing.T) {
+	setLogLevelDebug()
+
+	esClient, err := createESClient(esURL)
+	require.Nil(t, err)
+
+	esProc, err := factory.CreateElasticProcessor(factory.ArgElasticProcessorFactory{
+		Marshalizer:              &mock.MarshalizerMock{},
+		Hasher:                   &mock.HasherMock{},
+		AddressPubkeyConverter:   pubKeyConverter,
+		ValidatorPubkeyConverter: mock.NewPubkeyConverterMock(32),
+		DBClient:                 esClient,
+		EnabledIndexes:           []string{indexerdata.AccountsIndex, indexerdata.AccountsHistoryIndex},
+		Denomination:             18,
+	})
+	require.Nil(t, err)
+
+	addr := "moa1wd6xzmr994mhy6t5v4ej6ctrvdhh2mn5qqqqqqqqqqqqqqqqqqqq6l55h4"
+	body := &dataBlock.Body{}
+	olderHeader := &dataBlock.Header{
+		Round:     60,
+		TimeStamp: 7000,
+		ShardID:   1,
+	}
+	newerHeader := &dataBlock.Header{
+		Round:     61,
+		TimeStamp: 7006,
+		ShardID:   1,
+	}
+	alteredAccounts := func(balance string) map[string]*alteredAccount.AlteredAccount {
+		return map[string]*alteredAccount.AlteredAccount{
+			addr: {
+				Address:        addr,
+				Balance:        balance,
+				AdditionalData: &alteredAccount.AdditionalAccountData{BalanceChanged: true},
+			},
+		}
+	}
+	requireAccount := func(expectedBalance string, expectedTimestamp uint64) {
+		genericResponse := &GenericResponse{}
+		err = esClient.DoMultiGet(context.Background(), []string{addr}, indexerdata.AccountsIndex, true, genericResponse)
+		require.Nil(t, err)
+
+		account := struct {
+			Balance   string `json:"balance"`
+			Timestamp uint64 `json:"timestamp"`
+		}{}
+		err = json.Unmarshal(genericResponse.Docs[0].Source, &account)
+		require.Nil(t, err)
+		require.Equal(t, expectedBalance, account.Balance)
+		require.Equal(t, expectedTimestamp, account.Timestamp)
+	}
+
+	err = esProc.SaveTransactions(createOutportBlockWithHeader(body, olderHeader, &outport.TransactionPool{}, alteredAccounts("1000"), testNumOfShards))
+	require.Nil(t, err)
+	err = esProc.SaveTransactions(createOutportBlockWithHeader(body, newerHeader, &outport.TransactionPool{}, alteredAccounts("2000"), testNumOfShards))
+	require.Nil(t, err)
+	requireAccount("2000", newerHeader.TimeStamp)
+
+	// the older block arrives again after the newer one, its write is ignored
+	err = esProc.SaveTransactions(createOutportBlockWithHeader(body, olderHeader, &outport.TransactionPool{}, alteredAccounts("1000"), testNumOfShards))
+	require.Nil(t, err)
+	requireAccount("2000", newerHeader.TimeStamp)
+
+	// the newer block is retried, the write with the same timestamp leaves the same account
+	err = esProc.SaveTransactions(createOutportBlockWithHeader(body, newerHeader, &outport.TransactionPool{}, alteredAccounts("2000"), testNumOfShards))
+	require.Nil(t, err)
+	requireAccount("2000", newerHeader.TimeStamp)
+
+	// reverting the older block does not restore the account, as it holds the version of the newer block
+	err = esProc.RemoveTransactions(olderHeader, body)
+	require.Nil(t, err)
+	requireAccount("2000", newerHeader.TimeStamp)
+}
@@ -0,0 +1,375 @@
+//go:build integrationtests
+
+package integrationtests
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/kalyan3104/k-chain-core-go/core"
+	dataBlock "github.com/kalyan3104/k-chain-core-go/data/block"
+	"github.com/kalyan3104/k-chain-core-go/data/outport"
+	"github.com/kalyan3104/k-chain-es-indexer-go/mock"
+	"github.com/kalyan3104/k-chain-es-indexer-go/process/dataindexer"
+	"github.com/stretchr/testify/require"
+)
+
+var errAssertionFailed = errors.New("assertion failed")
+
+// memoryDatabase holds the documents of the compared indices in memory, so the harness can run without Elasticsearch
+type memoryDatabase struct {
+	indices map[string]map[string]json.RawMessage
+}
+
+func newMemoryDatabase() *memoryDatabase {
+	return &memoryDatabase{
+		indices: make(map[string]map[string]json.RawMessage),
+	}
+}
+
+func (md *memoryDatabase) DoQueryRemove(_ context.Context, index string, _ *bytes.Buffer) error {
+	delete(md.indices, index)
+	return nil
+}
+
+func (md *memoryDatabase) DoRefreshRequest(_ context.Context, _ string) error {
+	return nil
+}
+
+func (md *memoryDatabase) DoScrollRequest(_ context.Context, index string, _ []byte, _ bool, handlerFunc func(responseBytes []byte) error) error {
+	type scrollHit struct {
+		ID     string          `json:"_id"`
+		Source json.RawMessage `json:"_source"`
+	}
+
+	hits := make([]scrollHit, 0, len(md.indices[index]))
+	for id, source := range md.indices[index] {
+		hits = append(hits, scrollHit{ID: id, Source: source})
+	}
+
+	responseBytes, err := json.Marshal(map[string]interface{}{
+		"hits": map[string]interface{}{
+			"hits": hits,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	return handlerFunc(responseBytes)
+}
+
+func (md *memoryDatabase) put(index string, id string, source interface{}) error {
+	sourceBytes, err := json.Marshal(source)
+	if err != nil {
+		return err
+	}
+
+	if md.indices[index] == nil {
+		md.indices[index] = make(map[string]json.RawMessage)
+	}
+	md.indices[index][id] = sourceBytes
+
+	return nil
+}
+
+func (md *memoryDatabase) remove(index string, id string) {
+	delete(md.indices[index], id)
+}
+
+// memoryIndexer saves the blocks and the altered accounts in a memory database. The previous versions of the accounts
+// are kept for every block, so a revert restores them unless skipAccountsRestore is set
+type memoryIndexer struct {
+	database            *memoryDatabase
+	previousAccounts    map[string]map[string]json.RawMessage
+	skipAccountsRestore bool
+}
+
+func newMemoryIndexer(database *memoryDatabase) *memoryIndexer {
+	return &memoryIndexer{
+		database:         database,
+		previousAccounts: make(map[string]map[string]json.RawMessage),
+	}
+}
+
+func (mi *memoryIndexer) SaveBlock(outportBlock *outport.OutportBlock) error {
+	header := &dataBlock.Header{}
+	err := (&mock.MarshalizerMock{}).Unmarshal(header, outportBlock.BlockData.HeaderBytes)
+	if err != nil {
+		return err
+	}
+
+	blockHash := hex.EncodeToString(outportBlock.BlockData.HeaderHash)
+	err = mi.database.put(dataindexer.BlockIndex, blockHash, map[string]interface{}{
+		"nonce": header.Nonce,
+		"round": header.Round,
+	})
+	if err != nil {
+		return err
+	}
+
+	previous := make(map[string]json.RawMessage, len(outportBlock.AlteredAccounts))
+	for address, account := range outportBlock.AlteredAccounts {
+		previous[address] = mi.database.indices[dataindexer.AccountsIndex][address]
+
+		err = mi.database.put(dataindexer.AccountsIndex, address, map[string]interface{}{
+			"balance": account.Balance,
+			"nonce":   account.Nonce,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	mi.previousAccounts[blockHash] = previous
+
+	return nil
+}
+
+func (mi *memoryIndexer) RevertIndexedBlock(blockData *outport.BlockData) error {
+	blockHash := hex.EncodeToString(blockData.HeaderHash)
+	mi.database.remove(dataindexer.BlockIndex, blockHash)
+	if mi.skipAccountsRestore {
+		return nil
+	}
+
+	for address, source := range mi.previousAccounts[blockHash] {
+		if source == nil {
+			mi.database.remove(dataindexer.AccountsIndex, address)
+			continue
+		}
+
+		err := mi.database.put(dataindexer.AccountsIndex, address, source)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (mi *memoryIndexer) FinalizedBlock(_ *outport.FinalizedBlock) error {
+	return nil
+}
+
+// failureRecorder records the failed assertions instead of failing the test. As FailNow does, a failed assertion stops
+// the checks, the recordFailures function recovering from it
+type failureRecorder struct {
+	*testing.T
+	failures []string
+}
+
+func (fr *failureRecorder) Errorf(format string, args ...interface{}) {
+	fr.failures = append(fr.failures, fmt.Sprintf(format, args...))
+}
+
+func (fr *failureRecorder) FailNow() {
+	panic(errAssertionFailed)
+}
+
+func recordFailures(t *testing.T, assertion func(recorder *failureRecorder)) []string {
+	recorder := &failureRecorder{T: t}
+	func() {
+		defer func() {
+			r := recover()
+			if r != nil && r != errAssertionFailed {
+				panic(r)
+			}
+		}()
+
+		assertion(recorder)
+	}()
+
+	return recorder.failures
+}
+
+func createForkScenario(t *testing.T) ([]reorgStep, []reorgStep) {
+	genesis := genesisBlock(0, map[string]int64{"alice": 1000, "bob": 1000})
+	first := nextBlock(t, "first", genesis, 1, transfer{sender: "alice", receiver: "bob", value: 10})
+	fork := nextBlock(t, "fork", first, 2, transfer{sender: "alice", receiver: "carol", value: 100})
+	second := nextBlock(t, "second", first, 3, transfer{sender: "alice", receiver: "bob", value: 20})
+
+	reorgSteps := []reorgStep{
+		saveBlock(first),
+		saveBlock(fork),
+		revertBlock(fork),
+		saveBlock(second),
+		finalizeBlock(first),
+		finalizeBlock(second),
+	}
+
+	return reorgSteps, canonicalSteps(first, second)
+}
+
+func TestReorgHarness_NextBlockShouldForkTheParentState(t *testing.T) {
+	t.Parallel()
+
+	genesis := genesisBlock(0, map[string]int64{"alice": 1000, "bob": 1000})
+	first := nextBlock(t, "first", genesis, 1, transfer{sender: "alice", receiver: "bob", value: 10})
+	fork := nextBlock(t, "fork", first, 2, transfer{sender: "alice", receiver: "carol", value: 100})
+	second := nextBlock(t, "second", first, 3, transfer{sender: "alice", receiver: "bob", value: 20})
+
+	require.Equal(t, first.hash, fork.parentHash)
+	require.Equal(t, first.hash, second.parentHash)
+	require.NotEqual(t, fork.hash, second.hash)
+	require.Equal(t, fork.nonce, second.nonce)
+
+	require.Equal(t, "990", first.balances["alice"].String())
+	require.Equal(t, "890", fork.balances["alice"].String())
+	require.Equal(t, "100", fork.balances["carol"].String())
+	require.Equal(t, "970", second.balances["alice"].String())
+	require.Nil(t, second.balances["carol"])
+	require.Equal(t, uint64(2), second.nonces["alice"])
+
+	outportBlock := second.outportBlock(t)
+	require.Equal(t, second.hash, outportBlock.BlockData.HeaderHash)
+	require.Equal(t, "970", outportBlock.AlteredAccounts[reorgAddress("alice")].Balance)
+	require.True(t, outportBlock.AlteredAccounts[reorgAddress("alice")].AdditionalData.IsSender)
+	require.Equal(t, "1030", outportBlock.AlteredAccounts[reorgAddress("bob")].Balance)
+	require.False(t, outportBlock.AlteredAccounts[reorgAddress("bob")].AdditionalData.IsSender)
+}
+
+func TestReorgHarness_OperationsShouldBuildTheirPayloads(t *testing.T) {
+	t.Parallel()
+
+	genesis := genesisBlock(0, map[string]int64{"alice": 1000}).
+		withTokenBalances("TKN-abcdef", map[string]int64{"alice": 500})
+	block := nextBlock(t, "block", genesis, 1,
+		scCall{sender: "alice", contract: "contract-adder", function: "add@01", value: 5},
+		dcdtTransfer{sender: "alice", receiver: "bob", token: "TKN-abcdef", value: 50},
+		nftCreate{creator: "alice", collection: "ART-abcdef", nonce: 1, tags: []string{"music", "art"}},
+	)
+
+	require.Equal(t, []uint64{0, 1, 2}, block.txsNonces)
+	require.Equal(t, "995", block.balances["alice"].String())
+	require.Equal(t, "5", block.balances["contract-adder"].String())
+	require.Equal(t, "450", block.tokens["alice"]["TKN-abcdef"].String())
+	require.Equal(t, "50", block.tokens["bob"]["TKN-abcdef"].String())
+	require.Equal(t, "1", block.tokens["alice"]["ART-abcdef-01"].String())
+	require.Equal(t, uint64(1), block.contractCalls["contract-adder"])
+	require.True(t, core.IsSmartContractAddress(reorgAddressBytes("contract-adder")))
+	require.False(t, core.IsSmartContractAddress(reorgAddressBytes("alice")))
+
+	outportBlock := block.outportBlock(t)
+	require.Len(t, outportBlock.TransactionPool.Transactions, 3)
+	require.Len(t, outportBlock.TransactionPool.SmartContractResults, 1)
+	require.Len(t, outportBlock.TransactionPool.Logs, 2)
+	require.Len(t, outportBlock.BlockData.Body.MiniBlocks, 2)
+	require.Equal(t, dataBlock.SmartContractResultBlock, outportBlock.BlockData.Body.MiniBlocks[1].Type)
+
+	contract := outportBlock.AlteredAccounts[reorgAddress("contract-adder")]
+	require.Equal(t, (&mock.HasherMock{}).Compute("root-contract-adder-1"), contract.AdditionalData.RootHash)
+
+	bob := outportBlock.AlteredAccounts[reorgAddress("bob")]
+	require.Equal(t, "0", bob.Balance)
+	require.False(t, bob.AdditionalData.BalanceChanged)
+	require.Equal(t, "50", bob.Tokens[0].Balance)
+
+	alice := outportBlock.AlteredAccounts[reorgAddress("alice")]
+	require.True(t, alice.AdditionalData.IsSender)
+	require.Len(t, alice.Tokens, 2)
+	require.Equal(t, "tags:music,art;metadata:ART-abcdef", string(alice.Tokens[1].MetaData.Attributes))
+	require.True(t, alice.Tokens[1].AdditionalData.IsNFTCreate)
+}
+
+func TestReorgHarness_RequireSameFinalStateShouldPassWhenTheRevertUndoesTheFork(t *testing.T) {
+	t.Parallel()
+
+	database := newMemoryDatabase()
+	harness := &reorgHarness{
+		t:        t,
+		database: database,
+		indexer:  newMemoryIndexer(database),
+	}
+
+	reorgSteps, canonical := createForkScenario(t)
+	harness.requireSameFinalState(reorgSteps, canonical)
+}
+
+func TestReorgHarness_RequireSameFinalStateShouldFailWhenTheRevertLeavesChanges(t *testing.T) {
+	t.Parallel()
+
+	database := newMemoryDatabase()
+	indexer := newMemoryIndexer(database)
+	indexer.skipAccountsRestore = true
+
+	reorgSteps, canonical := createForkScenario(t)
+	failures := recordFailures(t, func(recorder *failureRecorder) {
+		harness := &reorgHarness{
+			t:        recorder,
+			database: database,
+			indexer:  indexer,
+		}
+		harness.requireSameFinalState(reorgSteps, canonical)
+	})
+
+	require.Len(t, failures, 1)
+	require.Contains(t, failures[0], "documents from the accounts index")
+	require.Contains(t, failures[0], reorgAddress("carol"))
+}
+
+func TestReorgHarness_RequireSameDocuments(t *testing.T) {
+	t.Parallel()
+
+	expected := map[string]json.RawMessage{
+		"a": json.RawMessage(`{"balance":"10","nonce":1}`),
+		"b": json.RawMessage(`{"balance":"20"}`),
+	}
+
+	failures := recordFailures(t, func(recorder *failureRecorder) {
+		requireSameDocuments(recorder, "accounts", expected, map[string]json.RawMessage{
+			"a": json.RawMessage(`{"nonce":1,"balance":"10"}`),
+			"b": json.RawMessage(`{"balance": "20"}`),
+		})
+	})
+	require.Empty(t, failures)
+
+	failures = recordFailures(t, func(recorder *failureRecorder) {
+		requireSameDocuments(recorder, "accounts", expected, map[string]json.RawMessage{
+			"a": json.RawMessage(`{"balance":"10","nonce":1}`),
+		})
+	})
+	require.Len(t, failures, 1)
+	require.Contains(t, failures[0], "documents from the accounts index")
+
+	failures = recordFailures(t, func(recorder *failureRecorder) {
+		requireSameDocuments(recorder, "accounts", expected, map[string]json.RawMessage{
+			"a": json.RawMessage(`{"balance":"11","nonce":1}`),
+			"b": json.RawMessage(`{"balance":"20"}`),
+		})
+	})
+	require.Len(t, failures, 1)
+	require.Contains(t, failures[0], "document a from the accounts index")
+}
+
+func TestReorgHarness_DescribeChanges(t *testing.T) {
+	t.Parallel()
+
+	previous := indicesSnapshot{
+		dataindexer.BlockIndex: {
+			"h1": json.RawMessage(`{"nonce":1}`),
+		},
+		dataindexer.AccountsIndex: {
+			"a": json.RawMessage(`{"balance":"10"}`),
+			"b": json.RawMessage(`{"balance":"20"}`),
+		},
+	}
+	require.Equal(t, "no changes", describeChanges(previous, previous))
+
+	current := indicesSnapshot{
+		dataindexer.BlockIndex: {
+			"h1": json.RawMessage(`{"nonce":1}`),
+			"h2": json.RawMessage(`{"nonce":2}`),
+		},
+		dataindexer.AccountsIndex: {
+			"a": json.RawMessage(`{"balance":"15"}`),
+		},
+	}
+	changes := describeChanges(previous, current)
+	require.Contains(t, changes, fmt.Sprintf("%s +1 -0 ~0; ", dataindexer.BlockIndex))
+	require.Contains(t, changes, fmt.Sprintf("%s +0 -1 ~1; ", dataindexer.AccountsIndex))
+}
@@ -0,0 +1,772 @@
+//go:build integrationtests
+
+package integrationtests
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/kalyan3104/k-chain-core-go/core"
+	"github.com/kalyan3104/k-chain-core-go/data/alteredAccount"
+	dataBlock "github.com/kalyan3104/k-chain-core-go/data/block"
+	"github.com/kalyan3104/k-chain-core-go/data/dcdt"
+	"github.com/kalyan3104/k-chain-core-go/data/outport"
+	"github.com/kalyan3104/k-chain-core-go/data/smartContractResult"
+	"github.com/kalyan3104/k-chain-core-go/data/transaction"
+	"github.com/kalyan3104/k-chain-core-go/data/vm"
+	"github.com/kalyan3104/k-chain-es-indexer-go/data"
+	"github.com/kalyan3104/k-chain-es-indexer-go/mock"
+	"github.com/kalyan3104/k-chain-es-indexer-go/process/dataindexer"
+	"github.com/kalyan3104/k-chain-es-indexer-go/process/elasticproc/factory"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	reorgBaseTimestamp = 1650000000
+	reorgRoundDuration = 6
+	reorgGasUsed       = 50000
+	reorgGasPrice      = 1000000000
+)
+
+// reorgIndices are the indices written by the generated blocks, which are compared at the end of a scenario
+var reorgIndices = []string{
+	dataindexer.BlockIndex, dataindexer.MiniblocksIndex, dataindexer.TransactionsIndex, dataindexer.ScResultsIndex,
+	dataindexer.OperationsIndex, dataindexer.LogsIndex, dataindexer.EventsIndex, dataindexer.AccountsIndex,
+	dataindexer.AccountsHistoryIndex, dataindexer.AccountsDCDTIndex, dataindexer.TokensIndex, dataindexer.TagsIndex,
+	dataindexer.DCDTsIndex, dataindexer.EpochStatsIndex, dataindexer.ContractStatsIndex,
+	dataindexer.DelegationProvidersIndex, dataindexer.ConsensusStatsIndex, dataindexer.TxLifecycleIndex,
+	dataindexer.CallTreeIndex, dataindexer.StatsContributionsIndex, dataindexer.JournalIndex,
+}
+
+// reorgContractPrefix marks the names of the accounts which are smart contracts
+const reorgContractPrefix = "contract-"
+
+// reorgIndexer defines the calls of the data indexer that are scripted by the reorg scenarios
+type reorgIndexer interface {
+	SaveBlock(outportBlock *outport.OutportBlock) error
+	RevertIndexedBlock(blockData *outport.BlockData) error
+	FinalizedBlock(finalizedBlock *outport.FinalizedBlock) error
+}
+
+// reorgOperation is a transaction of a generated block. It changes the state of the accounts when the block is
+// generated, and adds the transaction with its results, logs and altered accounts to the payload of the block
+type reorgOperation interface {
+	senderName() string
+	apply(block *generatedBlock)
+	addToPayload(t require.TestingT, block *generatedBlock, txNonce uint64, payload *blockPayload)
+}
+
+// transfer is a move balance transaction between two named accounts
+type transfer struct {
+	sender   string
+	receiver string
+	value    int64
+}
+
+func (tr transfer) senderName() string {
+	return tr.sender
+}
+
+func (tr transfer) apply(block *generatedBlock) {
+	block.moveBalance(tr.sender, tr.receiver, tr.value)
+}
+
+func (tr transfer) addToPayload(t require.TestingT, block *generatedBlock, txNonce uint64, payload *blockPayload) {
+	payload.addTransaction(t, &transaction.Transaction{
+		Nonce:   txNonce,
+		SndAddr: reorgAddressBytes(tr.sender),
+		RcvAddr: reorgAddressBytes(tr.receiver),
+		Value:   big.NewInt(tr.value),
+	})
+
+	block.addAlteredAccount(payload, tr.sender, true, true)
+	block.addAlteredAccount(payload, tr.receiver, false, true)
+}
+
+// scCall calls a function of a named contract, which changes the state of the contract and returns a result to the
+// caller
+type scCall struct {
+	sender   string
+	contract string
+	function string
+	value    int64
+}
+
+func (sc scCall) senderName() string {
+	return sc.sender
+}
+
+func (sc scCall) apply(block *generatedBlock) {
+	block.moveBalance(sc.sender, sc.contract, sc.value)
+	block.contractCalls[sc.contract]++
+}
+
+func (sc scCall) addToPayload(t require.TestingT, block *generatedBlock, txNonce uint64, payload *blockPayload) {
+	txHash := payload.addTransaction(t, &transaction.Transaction{
+		Nonce:   txNonce,
+		SndAddr: reorgAddressBytes(sc.sender),
+		RcvAddr: reorgAddressBytes(sc.contract),
+		Value:   big.NewInt(sc.value),
+		Data:    []byte(sc.function),
+	})
+	payload.addSmartContractResult(t, &smartContractResult.SmartContractResult{
+		Nonce:          txNonce + 1,
+		Value:          big.NewInt(0),
+		SndAddr:        reorgAddressBytes(sc.contract),
+		RcvAddr:        reorgAddressBytes(sc.sender),
+		Data:           []byte("@6f6b"),
+		PrevTxHash:     txHash,
+		OriginalTxHash: txHash,
+		CallType:       vm.DirectCall,
+	})
+
+	block.addAlteredAccount(payload, sc.sender, true, true)
+	block.addAlteredAccount(payload, sc.contract, false, true)
+}
+
+// dcdtTransfer moves an amount of a fungible token between two named accounts
+type dcdtTransfer struct {
+	sender   string
+	receiver string
+	token    string
+	value    int64
+}
+
+func (dt dcdtTransfer) senderName() string {
+	return dt.sender
+}
+
+func (dt dcdtTransfer) apply(block *generatedBlock) {
+	block.tokenBalance(dt.sender, dt.token).Sub(block.tokenBalance(dt.sender, dt.token), big.NewInt(dt.value))
+	block.tokenBalance(dt.receiver, dt.token).Add(block.tokenBalance(dt.receiver, dt.token), big.NewInt(dt.value))
+}
+
+func (dt dcdtTransfer) addToPayload(t require.TestingT, block *generatedBlock, txNonce uint64, payload *blockPayload) {
+	value := big.NewInt(dt.value)
+	txHash := payload.addTransaction(t, &transaction.Transaction{
+		Nonce:   txNonce,
+		SndAddr: reorgAddressBytes(dt.sender),
+		RcvAddr: reorgAddressBytes(dt.receiver),
+		Value:   big.NewInt(0),
+		Data:    []byte(fmt.Sprintf("%s@%s@%s", core.BuiltInFunctionDCDTTransfer, hex.EncodeToString([]byte(dt.token)), hex.EncodeToString(value.Bytes()))),
+	})
+	payload.addLog(txHash, reorgAddressBytes(dt.sender), &transaction.Event{
+		Address:    reorgAddressBytes(dt.sender),
+		Identifier: []byte(core.BuiltInFunctionDCDTTransfer),
+		Topics:     [][]byte{[]byte(dt.token), big.NewInt(0).Bytes(), value.Bytes(), reorgAddressBytes(dt.receiver)},
+	})
+
+	block.addAlteredAccount(payload, dt.sender, true, true)
+	block.addAlteredAccount(payload, dt.receiver, false, false)
+	block.addAlteredToken(payload, dt.sender, &alteredAccount.AccountTokenData{
+		Identifier: dt.token,
+		Balance:    block.tokenBalance(dt.sender, dt.token).String(),
+	})
+	block.addAlteredToken(payload, dt.receiver, &alteredAccount.AccountTokenData{
+		Identifier: dt.token,
+		Balance:    block.tokenBalance(dt.receiver, dt.token).String(),
+	})
+}
+
+// nftCreate creates an NFT of a collection, holding the provided tags, for its named creator
+type nftCreate struct {
+	creator    string
+	collection string
+	nonce      uint64
+	tags       []string
+}
+
+func (nc nftCreate) senderName() string {
+	return nc.creator
+}
+
+func (nc nftCreate) apply(block *generatedBlock) {
+	identifier := fmt.Sprintf("%s-%s", nc.collection, hex.EncodeToString(big.NewInt(0).SetUint64(nc.nonce).Bytes()))
+	block.tokenBalance(nc.creator, identifier).SetInt64(1)
+}
+
+func (nc nftCreate) addToPayload(t require.TestingT, block *generatedBlock, txNonce uint64, payload *blockPayload) {
+	attributes := []byte(fmt.Sprintf("tags:%s;metadata:%s", strings.Join(nc.tags, ","), nc.collection))
+	nonce := big.NewInt(0).SetUint64(nc.nonce)
+	token := &dcdt.DCDigitalToken{
+		Value: big.NewInt(1),
+		TokenMetaData: &dcdt.MetaData{
+			Nonce:      nc.nonce,
+			Creator:    reorgAddressBytes(nc.creator),
+			Attributes: attributes,
+		},
+	}
+	tokenBytes, err := (&mock.MarshalizerMock{}).Marshal(token)
+	require.Nil(t, err)
+
+	txHash := payload.addTransaction(t, &transaction.Transaction{
+		Nonce:   txNonce,
+		SndAddr: reorgAddressBytes(nc.creator),
+		RcvAddr: reorgAddressBytes(nc.creator),
+		Value:   big.NewInt(0),
+		Data:    []byte(fmt.Sprintf("%s@%s@01", core.BuiltInFunctionDCDTNFTCreate, hex.EncodeToString([]byte(nc.collection)))),
+	})
+	payload.addLog(txHash, reorgAddressBytes(nc.creator), &transaction.Event{
+		Address:    reorgAddressBytes(nc.creator),
+		Identifier: []byte(core.BuiltInFunctionDCDTNFTCreate),
+		Topics:     [][]byte{[]byte(nc.collection), nonce.Bytes(), big.NewInt(1).Bytes(), tokenBytes},
+	})
+
+	block.addAlteredAccount(payload, nc.creator, true, true)
+	block.addAlteredToken(payload, nc.creator, &alteredAccount.AccountTokenData{
+		Identifier: nc.collection,
+		Nonce:      nc.nonce,
+		Balance:    "1",
+		MetaData: &alteredAccount.TokenMetaData{
+			Nonce:      nc.nonce,
+			Creator:    reorgAddress(nc.creator),
+			Attributes: attributes,
+		},
+		AdditionalData: &alteredAccount.AdditionalAccountTokenData{
+			IsNFTCreate: true,
+		},
+	})
+}
+
+// generatedBlock is a block of a generated chain together with the state of the accounts after it. The outport payload
+// is built again for every use, so a block saved by several scenarios is never altered by a previous indexing
+type generatedBlock struct {
+	name          string
+	shardID       uint32
+	nonce         uint64
+	round         uint64
+	parentHash    []byte
+	hash          []byte
+	operations    []reorgOperation
+	txsNonces     []uint64
+	balances      map[string]*big.Int
+	tokens        map[string]map[string]*big.Int
+	nonces        map[string]uint64
+	contractCalls map[string]uint64
+}
+
+// genesisBlock returns the parent of the first generated block, holding the initial balances. It is never indexed
+func genesisBlock(shardID uint32, balances map[string]int64) *generatedBlock {
+	genesis := &generatedBlock{
+		name:          "genesis",
+		shardID:       shardID,
+		hash:          (&mock.HasherMock{}).Compute("genesis"),
+		balances:      make(map[string]*big.Int, len(balances)),
+		tokens:        make(map[string]map[string]*big.Int),
+		nonces:        make(map[string]uint64),
+		contractCalls: make(map[string]uint64),
+	}
+	for name, balance := range balances {
+		genesis.balances[name] = big.NewInt(balance)
+	}
+
+	return genesis
+}
+
+// withTokenBalances sets the initial balances of a fungible token, so it can only be called for the genesis block
+func (gb *generatedBlock) withTokenBalances(token string, balances map[string]int64) *generatedBlock {
+	for name, balance := range balances {
+		gb.tokenBalance(name, token).SetInt64(balance)
+	}
+
+	return gb
+}
+
+// nextBlock generates the child of the provided block, proposed in the provided round. Two children of the same block
+// are the two sides of a fork
+func nextBlock(t *testing.T, name string, parent *generatedBlock, round uint64, operations ...reorgOperation) *generatedBlock {
+	block := &generatedBlock{
+		name:          name,
+		shardID:       parent.shardID,
+		nonce:         parent.nonce + 1,
+		round:         round,
+		parentHash:    parent.hash,
+		operations:    operations,
+		txsNonces:     make([]uint64, 0, len(operations)),
+		balances:      make(map[string]*big.Int, len(parent.balances)),
+		tokens:        make(map[string]map[string]*big.Int, len(parent.tokens)),
+		nonces:        make(map[string]uint64, len(parent.nonces)),
+		contractCalls: make(map[string]uint64, len(parent.contractCalls)),
+	}
+	for account, balance := range parent.balances {
+		block.balances[account] = big.NewInt(0).Set(balance)
+	}
+	for account, tokens := range parent.tokens {
+		for token, balance := range tokens {
+			block.tokenBalance(account, token).Set(balance)
+		}
+	}
+	for account, nonce := range parent.nonces {
+		block.nonces[account] = nonce
+	}
+	for contract, calls := range parent.contractCalls {
+		block.contractCalls[contract] = calls
+	}
+
+	for _, operation := range operations {
+		sender := operation.senderName()
+		block.txsNonces = append(block.txsNonces, block.nonces[sender])
+		block.nonces[sender]++
+
+		operation.apply(block)
+	}
+
+	outportBlock := block.outportBlock(t)
+	block.hash = outportBlock.BlockData.HeaderHash
+
+	return block
+}
+
+func (gb *generatedBlock) timestamp() uint64 {
+	return reorgBaseTimestamp + gb.round*reorgRoundDuration
+}
+
+func (gb *generatedBlock) balance(name string) *big.Int {
+	if gb.balances[name] == nil {
+		gb.balances[name] = big.NewInt(0)
+	}
+
+	return gb.balances[name]
+}
+
+func (gb *generatedBlock) tokenBalance(name string, token string) *big.Int {
+	if gb.tokens[name] == nil {
+		gb.tokens[name] = make(map[string]*big.Int)
+	}
+	if gb.tokens[name][token] == nil {
+		gb.tokens[name][token] = big.NewInt(0)
+	}
+
+	return gb.tokens[name][token]
+}
+
+func (gb *generatedBlock) moveBalance(sender string, receiver string, value int64) {
+	gb.balance(sender).Sub(gb.balance(sender), big.NewInt(value))
+	gb.balance(receiver).Add(gb.balance(receiver), big.NewInt(value))
+}
+
+// blockPayload gathers the transactions, the smart contract results, the logs and the altered accounts of a block
+type blockPayload struct {
+	pool            *outport.TransactionPool
+	txsHashes       [][]byte
+	scrsHashes      [][]byte
+	alteredAccounts map[string]*alteredAccount.AlteredAccount
+}
+
+func (bp *blockPayload) addTransaction(t require.TestingT, tx *transaction.Transaction) []byte {
+	tx.GasLimit = reorgGasUsed
+	tx.GasPrice = reorgGasPrice
+	txHash, err := core.CalculateHash(&mock.MarshalizerMock{}, &mock.HasherMock{}, tx)
+	require.Nil(t, err)
+
+	bp.pool.Transactions[hex.EncodeToString(txHash)] = &outport.TxInfo{
+		Transaction: tx,
+		FeeInfo: &outport.FeeInfo{
+			GasUsed:        reorgGasUsed,
+			Fee:            big.NewInt(reorgGasUsed * reorgGasPrice),
+			InitialPaidFee: big.NewInt(reorgGasUsed * reorgGasPrice),
+		},
+		ExecutionOrder: uint32(len(bp.txsHashes) + len(bp.scrsHashes)),
+	}
+	bp.txsHashes = append(bp.txsHashes, txHash)
+
+	return txHash
+}
+
+func (bp *blockPayload) addSmartContractResult(t require.TestingT, scr *smartContractResult.SmartContractResult) {
+	scrHash, err := core.CalculateHash(&mock.MarshalizerMock{}, &mock.HasherMock{}, scr)
+	require.Nil(t, err)
+
+	bp.pool.SmartContractResults[hex.EncodeToString(scrHash)] = &outport.SCRInfo{
+		SmartContractResult: scr,
+		FeeInfo: &outport.FeeInfo{
+			Fee:            big.NewInt(0),
+			InitialPaidFee: big.NewInt(0),
+		},
+		ExecutionOrder: uint32(len(bp.txsHashes) + len(bp.scrsHashes)),
+	}
+	bp.scrsHashes = append(bp.scrsHashes, scrHash)
+}
+
+func (bp *blockPayload) addLog(txHash []byte, address []byte, events ...*transaction.Event) {
+	bp.pool.Logs = append(bp.pool.Logs, &outport.LogData{
+		TxHash: hex.EncodeToString(txHash),
+		Log: &transaction.Log{
+			Address: address,
+			Events:  events,
+		},
+	})
+}
+
+// outportBlock builds the payload the observer sends for the block
+func (gb *generatedBlock) outportBlock(t require.TestingT) *outport.OutportBlock {
+	marshaller, hasher := &mock.MarshalizerMock{}, &mock.HasherMock{}
+
+	payload := &blockPayload{
+		pool: &outport.TransactionPool{
+			Transactions:         make(map[string]*outport.TxInfo),
+			SmartContractResults: make(map[string]*outport.SCRInfo),
+		},
+		alteredAccounts: make(map[string]*alteredAccount.AlteredAccount),
+	}
+	for idx, operation := range gb.operations {
+		operation.addToPayload(t, gb, gb.txsNonces[idx], payload)
+	}
+
+	body := &dataBlock.Body{}
+	header := &dataBlock.Header{
+		Nonce:     gb.nonce,
+		Round:     gb.round,
+		ShardID:   gb.shardID,
+		TimeStamp: gb.timestamp(),
+		PrevHash:  gb.parentHash,
+		TxCount:   uint32(len(payload.txsHashes) + len(payload.scrsHashes)),
+	}
+	gb.addMiniBlock(t, body, header, payload.txsHashes, dataBlock.TxBlock)
+	gb.addMiniBlock(t, body, header, payload.scrsHashes, dataBlock.SmartContractResultBlock)
+
+	headerBytes, err := marshaller.Marshal(header)
+	require.Nil(t, err)
+
+	return &outport.OutportBlock{
+		ShardID: gb.shardID,
+		BlockData: &outport.BlockData{
+			ShardID:     gb.shardID,
+			HeaderBytes: headerBytes,
+			HeaderType:  string(core.ShardHeaderV1),
+			HeaderHash:  hasher.Compute(string(headerBytes)),
+			Body:        body,
+		},
+		TransactionPool:      payload.pool,
+		HeaderGasConsumption: &outport.HeaderGasConsumption{},
+		AlteredAccounts:      payload.alteredAccounts,
+		NumberOfShards:       testNumOfShards,
+		SignersIndexes:       []uint64{0},
+	}
+}
+
+func (gb *generatedBlock) addMiniBlock(t require.TestingT, body *dataBlock.Body, header *dataBlock.Header, hashes [][]byte, mbType dataBlock.Type) {
+	if len(hashes) == 0 {
+		return
+	}
+
+	miniBlock := &dataBlock.MiniBlock{
+		TxHashes:        hashes,
+		SenderShardID:   gb.shardID,
+		ReceiverShardID: gb.shardID,
+		Type:            mbType,
+	}
+	miniBlockHash, err := core.CalculateHash(&mock.MarshalizerMock{}, &mock.HasherMock{}, miniBlock)
+	require.Nil(t, err)
+
+	body.MiniBlocks = append(body.MiniBlocks, miniBlock)
+	header.MiniBlockHeaders = append(header.MiniBlockHeaders, dataBlock.MiniBlockHeader{
+		Hash:            miniBlockHash,
+		SenderShardID:   gb.shardID,
+		ReceiverShardID: gb.shardID,
+		TxCount:         uint32(len(hashes)),
+		Type:            mbType,
+	})
+}
+
+// addAlteredAccount adds a named account to the altered accounts of the block. A contract also gets the root hash
+// computed from the number of its calls, so every call changes its state
+func (gb *generatedBlock) addAlteredAccount(payload *blockPayload, name string, isSender bool, balanceChanged bool) {
+	address := reorgAddress(name)
+	existing, found := payload.alteredAccounts[address]
+	if found {
+		existing.AdditionalData.IsSender = existing.AdditionalData.IsSender || isSender
+		existing.AdditionalData.BalanceChanged = existing.AdditionalData.BalanceChanged || balanceChanged
+		return
+	}
+
+	balance := big.NewInt(0)
+	if gb.balances[name] != nil {
+		balance = gb.balances[name]
+	}
+
+	account := &alteredAccount.AlteredAccount{
+		Address: address,
+		Balance: balance.String(),
+		Nonce:   gb.nonces[name],
+		AdditionalData: &alteredAccount.AdditionalAccountData{
+			IsSender:       isSender,
+			BalanceChanged: balanceChanged,
+		},
+	}
+	if isReorgContract(name) {
+		hasher := &mock.HasherMock{}
+		account.AdditionalData.CodeHash = hasher.Compute("code-" + name)
+		account.AdditionalData.RootHash = hasher.Compute(fmt.Sprintf("root-%s-%d", name, gb.contractCalls[name]))
+	}
+	payload.alteredAccounts[address] = account
+}
+
+func (gb *generatedBlock) addAlteredToken(payload *blockPayload, name string, token *alteredAccount.AccountTokenData) {
+	account := payload.alteredAccounts[reorgAddress(name)]
+	account.Tokens = append(account.Tokens, token)
+}
+
+func isReorgContract(name string) bool {
+	return strings.HasPrefix(name, reorgContractPrefix)
+}
+
+// reorgAddressBytes returns the address of a named account. The names with the contract prefix get smart contract
+// addresses
+func reorgAddressBytes(name string) []byte {
+	addressBytes := (&mock.HasherMock{}).Compute("reorg-account-" + name)
+	if isReorgContract(name) {
+		copy(addressBytes, make([]byte, core.NumInitCharactersForScAddress-core.VMTypeLen))
+	}
+
+	return addressBytes
+}
+
+func reorgAddress(name string) string {
+	return pubKeyConverter.SilentEncode(reorgAddressBytes(name), log)
+}
+
+type reorgAction string
+
+const (
+	saveAction     reorgAction = "save"
+	revertAction   reorgAction = "revert"
+	finalizeAction reorgAction = "finalize"
+)
+
+// reorgStep is a call of the indexer for a generated block, as the observer would do it
+type reorgStep struct {
+	action reorgAction
+	block  *generatedBlock
+}
+
+func saveBlock(block *generatedBlock) reorgStep {
+	return reorgStep{action: saveAction, block: block}
+}
+
+func revertBlock(block *generatedBlock) reorgStep {
+	return reorgStep{action: revertAction, block: block}
+}
+
+func finalizeBlock(block *generatedBlock) reorgStep {
+	return reorgStep{action: finalizeAction, block: block}
+}
+
+// canonicalSteps saves and then finalizes the provided blocks, as the indexer would see a chain without forks
+func canonicalSteps(blocks ...*generatedBlock) []reorgStep {
+	steps := make([]reorgStep, 0, 2*len(blocks))
+	for _, block := range blocks {
+		steps = append(steps, saveBlock(block))
+	}
+	for _, block := range blocks {
+		steps = append(steps, finalizeBlock(block))
+	}
+
+	return steps
+}
+
+// reorgDatabase defines the calls of the database client used to reset and read the compared indices
+type reorgDatabase interface {
+	DoQueryRemove(ctx context.Context, index string, buff *bytes.Buffer) error
+	DoRefreshRequest(ctx context.Context, index string) error
+	DoScrollRequest(ctx context.Context, index string, body []byte, withSource bool, handlerFunc func(responseBytes []byte) error) error
+}
+
+// reorgTestingT is the part of testing.T used by the harness, so the assertions of the harness can be checked as well
+type reorgTestingT interface {
+	require.TestingT
+	Log(args ...interface{})
+	Logf(format string, args ...interface{})
+}
+
+// indicesSnapshot holds the sources of the documents from every compared index, by document id
+type indicesSnapshot map[string]map[string]json.RawMessage
+
+// reorgHarness runs scripted chains of indexer calls against the Elasticsearch cluster. Every run starts from empty
+// indices, so the scenarios cannot be run in parallel with other tests writing the same indices
+type reorgHarness struct {
+	t        reorgTestingT
+	database reorgDatabase
+	indexer  reorgIndexer
+}
+
+func newReorgHarness(t *testing.T) *reorgHarness {
+	esClient, err := createESClient(esURL)
+	require.Nil(t, err)
+
+	elasticProcessor, err := factory.CreateElasticProcessor(factory.ArgElasticProcessorFactory{
+		Marshalizer:              &mock.MarshalizerMock{},
+		Hasher:                   &mock.HasherMock{},
+		AddressPubkeyConverter:   pubKeyConverter,
+		ValidatorPubkeyConverter: mock.NewPubkeyConverterMock(32),
+		DBClient:                 esClient,
+		EnabledIndexes:           reorgIndices,
+		Denomination:             18,
+	})
+	require.Nil(t, err)
+
+	blockContainer := dataBlock.NewEmptyBlockCreatorsContainer()
+	err = blockContainer.Add(core.ShardHeaderV1, dataBlock.NewEmptyHeaderCreator())
+	require.Nil(t, err)
+
+	indexer, err := dataindexer.NewDataIndexer(dataindexer.ArgDataIndexer{
+		HeaderMarshaller: &mock.MarshalizerMock{},
+		ElasticProcessor: elasticProcessor,
+		BlockContainer:   blockContainer,
+	})
+	require.Nil(t, err)
+
+	return &reorgHarness{
+		t:        t,
+		database: esClient,
+		indexer:  indexer,
+	}
+}
+
+// requireSameFinalState runs the scenario with forks and the canonical chain alone, and checks that both leave the
+// indices in the same state
+func (rh *reorgHarness) requireSameFinalState(reorgSteps []reorgStep, canonical []reorgStep) {
+	rh.t.Log("running the scenario with forks")
+	reorgState := rh.run(reorgSteps)
+
+	rh.t.Log("running the canonical chain")
+	canonicalState := rh.run(canonical)
+
+	for _, index := range reorgIndices {
+		requireSameDocuments(rh.t, index, canonicalState[index], reorgState[index])
+	}
+}
+
+// run applies the steps over empty indices and returns the final state. The indices are snapshot after every step, and
+// the changed documents are logged, so a failing scenario shows the step which diverged
+func (rh *reorgHarness) run(steps []reorgStep) indicesSnapshot {
+	rh.resetIndices()
+
+	previous := rh.snapshot()
+	for idx, step := range steps {
+		rh.apply(step)
+
+		current := rh.snapshot()
+		rh.t.Logf("step %d: %s %s (nonce %d, round %d): %s",
+			idx, step.action, step.block.name, step.block.nonce, step.block.round, describeChanges(previous, current))
+		previous = current
+	}
+
+	return previous
+}
+
+func (rh *reorgHarness) apply(step reorgStep) {
+	var err error
+	switch step.action {
+	case saveAction:
+		err = rh.indexer.SaveBlock(step.block.outportBlock(rh.t))
+	case revertAction:
+		err = rh.indexer.RevertIndexedBlock(step.block.outportBlock(rh.t).BlockData)
+	case finalizeAction:
+		err = rh.indexer.FinalizedBlock(&outport.FinalizedBlock{
+			ShardID:    step.block.shardID,
+			HeaderHash: step.block.hash,
+		})
+	default:
+		err = fmt.Errorf("unknown action %s", step.action)
+	}
+
+	require.Nil(rh.t, err, "%s %s", step.action, step.block.name)
+}
+
+func (rh *reorgHarness) resetIndices() {
+	for _, index := range reorgIndices {
+		err := rh.database.DoQueryRemove(context.Background(), index, matchAllQuery())
+		require.Nil(rh.t, err)
+	}
+}
+
+func (rh *reorgHarness) snapshot() indicesSnapshot {
+	snapshot := make(indicesSnapshot, len(reorgIndices))
+	for _, index := range reorgIndices {
+		documents := make(map[string]json.RawMessage)
+		handlerFunc := func(responseBytes []byte) error {
+			response := &data.ResponseScroll{}
+			err := json.Unmarshal(responseBytes, response)
+			if err != nil {
+				return err
+			}
+
+			for _, hit := range response.Hits.Hits {
+				documents[hit.ID] = hit.Source
+			}
+
+			return nil
+		}
+
+		err := rh.database.DoRefreshRequest(context.Background(), index)
+		require.Nil(rh.t, err)
+
+		err = rh.database.DoScrollRequest(context.Background(), index, matchAllQuery().Bytes(), true, handlerFunc)
+		require.Nil(rh.t, err)
+
+		snapshot[index] = documents
+	}
+
+	return snapshot
+}
+
+func matchAllQuery() *bytes.Buffer {
+	return bytes.NewBufferString(`{"query": {"match_all": {}}}`)
+}
+
+func describeChanges(previous indicesSnapshot, current indicesSnapshot) string {
+	changes := ""
+	for _, index := range reorgIndices {
+		added, removed, updated := 0, 0, 0
+		for id, source := range current[index] {
+			previousSource, found := previous[index][id]
+			switch {
+			case !found:
+				added++
+			case string(previousSource) != string(source):
+				updated++
+			}
+		}
+		for id := range previous[index] {
+			if _, found := current[index][id]; !found {
+				removed++
+			}
+		}
+
+		if added+removed+updated > 0 {
+			changes += fmt.Sprintf("%s +%d -%d ~%d; ", index, added, removed, updated)
+		}
+	}
+
+	if changes == "" {
+		return "no changes"
+	}
+
+	return changes
+}
+
+func requireSameDocuments(t require.TestingT, index string, expected map[string]json.RawMessage, actual map[string]json.RawMessage) {
+	require.Equal(t, sortedIDs(expected), sortedIDs(actual), "documents from the %s index", index)
+
+	for id, source := range expected {
+		require.JSONEq(t, string(source), string(actual[id]), "document %s from the %s index", id, index)
+	}
+}
+
+func sortedIDs(documents map[string]json.RawMessage) []string {
+	ids := make([]string, 0, len(documents))
+	for id := range documents {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	return ids
+}
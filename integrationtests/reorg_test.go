@@ -0,0 +1,134 @@
+//go:build integrationtests
+
+package integrationtests
+
+import (
+	"testing"
+)
+
+func TestReorgSaveRevertSaveAlternative(t *testing.T) {
+	setLogLevelDebug()
+
+	harness := newReorgHarness(t)
+
+	genesis := genesisBlock(0, map[string]int64{"alice": 1000, "bob": 1000})
+	first := nextBlock(t, "first", genesis, 1, transfer{sender: "alice", receiver: "bob", value: 10})
+
+	// the fork sends funds to an account which does not exist on the canonical chain
+	fork := nextBlock(t, "fork", first, 2,
+		transfer{sender: "alice", receiver: "carol", value: 100},
+		transfer{sender: "bob", receiver: "alice", value: 5},
+	)
+	second := nextBlock(t, "second", first, 3, transfer{sender: "alice", receiver: "bob", value: 20})
+	third := nextBlock(t, "third", second, 4, transfer{sender: "bob", receiver: "alice", value: 30})
+
+	harness.requireSameFinalState(
+		[]reorgStep{
+			saveBlock(first),
+			saveBlock(fork),
+			revertBlock(fork),
+			saveBlock(second),
+			saveBlock(third),
+			finalizeBlock(first),
+			finalizeBlock(second),
+			finalizeBlock(third),
+		},
+		canonicalSteps(first, second, third),
+	)
+}
+
+func TestReorgRevertTwoBlocks(t *testing.T) {
+	setLogLevelDebug()
+
+	harness := newReorgHarness(t)
+
+	genesis := genesisBlock(1, map[string]int64{"alice": 1000, "bob": 1000, "dave": 500})
+	first := nextBlock(t, "first", genesis, 1, transfer{sender: "dave", receiver: "bob", value: 50})
+
+	forkSecond := nextBlock(t, "fork-second", first, 2, transfer{sender: "alice", receiver: "bob", value: 100})
+	forkThird := nextBlock(t, "fork-third", forkSecond, 3,
+		transfer{sender: "bob", receiver: "erin", value: 70},
+		transfer{sender: "alice", receiver: "dave", value: 1},
+	)
+
+	second := nextBlock(t, "second", first, 4, transfer{sender: "bob", receiver: "alice", value: 40})
+	third := nextBlock(t, "third", second, 5, transfer{sender: "alice", receiver: "dave", value: 15})
+
+	harness.requireSameFinalState(
+		[]reorgStep{
+			saveBlock(first),
+			finalizeBlock(first),
+			saveBlock(forkSecond),
+			saveBlock(forkThird),
+			revertBlock(forkThird),
+			revertBlock(forkSecond),
+			saveBlock(second),
+			saveBlock(third),
+			finalizeBlock(second),
+			finalizeBlock(third),
+		},
+		canonicalSteps(first, second, third),
+	)
+}
+
+func TestReorgSameTransactionOnBothSides(t *testing.T) {
+	setLogLevelDebug()
+
+	harness := newReorgHarness(t)
+
+	// the transaction from the fork is included again by the canonical block, with the same hash
+	genesis := genesisBlock(2, map[string]int64{"alice": 1000, "bob": 0})
+	fork := nextBlock(t, "fork", genesis, 1, transfer{sender: "alice", receiver: "bob", value: 300})
+	first := nextBlock(t, "first", genesis, 2,
+		transfer{sender: "alice", receiver: "bob", value: 300},
+		transfer{sender: "bob", receiver: "alice", value: 1},
+	)
+
+	harness.requireSameFinalState(
+		[]reorgStep{
+			saveBlock(fork),
+			revertBlock(fork),
+			saveBlock(first),
+			finalizeBlock(first),
+		},
+		canonicalSteps(first),
+	)
+}
+
+func TestReorgSmartContractCallsTokensAndTags(t *testing.T) {
+	setLogLevelDebug()
+
+	harness := newReorgHarness(t)
+
+	genesis := genesisBlock(0, map[string]int64{"alice": 1000, "bob": 1000}).
+		withTokenBalances("TKN-abcdef", map[string]int64{"alice": 500, "bob": 100})
+	first := nextBlock(t, "first", genesis, 1,
+		scCall{sender: "alice", contract: "contract-adder", function: "add@01", value: 5},
+		dcdtTransfer{sender: "alice", receiver: "bob", token: "TKN-abcdef", value: 50},
+		nftCreate{creator: "alice", collection: "ART-abcdef", nonce: 1, tags: []string{"music", "art"}},
+	)
+
+	// the fork calls the contract again, moves tokens to an account which does not exist on the canonical chain and
+	// creates an NFT whose tags are counted with the ones of the first block
+	fork := nextBlock(t, "fork", first, 2,
+		scCall{sender: "bob", contract: "contract-adder", function: "add@02"},
+		dcdtTransfer{sender: "bob", receiver: "carol", token: "TKN-abcdef", value: 20},
+		nftCreate{creator: "alice", collection: "ART-abcdef", nonce: 2, tags: []string{"art", "gallery"}},
+	)
+	second := nextBlock(t, "second", first, 3,
+		scCall{sender: "alice", contract: "contract-adder", function: "add@03", value: 1},
+		nftCreate{creator: "alice", collection: "ART-abcdef", nonce: 2, tags: []string{"music"}},
+	)
+
+	harness.requireSameFinalState(
+		[]reorgStep{
+			saveBlock(first),
+			saveBlock(fork),
+			revertBlock(fork),
+			saveBlock(second),
+			finalizeBlock(first),
+			finalizeBlock(second),
+		},
+		canonicalSteps(first, second),
+	)
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"strconv"
 
+	"github.com/prometheus/client_golang/prometheus"
 	dto "github.com/prometheus/client_model/go"
 	"github.com/prometheus/common/expfmt"
 	"google.golang.org/protobuf/proto"
@@ -13,8 +14,79 @@ const (
 	operationName = "operation"
 	shardIDName   = "shardID"
 	errorCodeName = "errorCode"
+	topicName     = "topic"
+	indexName     = "index"
+	stageName     = "stage"
 )
 
+// durationBuckets are the upper bounds, in seconds, of the buckets used by the latency histograms
+var durationBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30}
+
+// promMetrics holds the metrics registered in the prometheus registry of the indexer
+type promMetrics struct {
+	payloadDuration      *prometheus.HistogramVec
+	requestDuration      *prometheus.HistogramVec
+	blockStageDuration   *prometheus.HistogramVec
+	documentsWritten     *prometheus.CounterVec
+	lastIndexedNonce     *prometheus.GaugeVec
+	lastIndexedTimestamp *prometheus.GaugeVec
+	indexingLag          *prometheus.GaugeVec
+	queueDepth           prometheus.Gauge
+}
+
+func newPromMetrics(registry *prometheus.Registry) *promMetrics {
+	pm := &promMetrics{
+		payloadDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    payloadDurationMetric,
+			Help:    "Duration of the processing of the payloads received from the observer",
+			Buckets: durationBuckets,
+		}, []string{topicName, shardIDName}),
+		requestDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    requestDurationMetric,
+			Help:    "Duration of the requests sent to Elasticsearch",
+			Buckets: durationBuckets,
+		}, []string{operationName, shardIDName}),
+		blockStageDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    blockStageDurationMetric,
+			Help:    "Duration of the stages from the processing of a block",
+			Buckets: durationBuckets,
+		}, []string{stageName, shardIDName}),
+		documentsWritten: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: documentsWrittenMetric,
+			Help: "Number of documents written in Elasticsearch",
+		}, []string{indexName}),
+		lastIndexedNonce: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: lastIndexedNonceMetric,
+			Help: "Nonce of the last indexed block",
+		}, []string{shardIDName}),
+		lastIndexedTimestamp: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: lastIndexedTimestampMetric,
+			Help: "Timestamp of the last indexed block",
+		}, []string{shardIDName}),
+		indexingLag: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: indexingLagMetric,
+			Help: "Difference between the wall clock and the timestamp of the last indexed block",
+		}, []string{shardIDName}),
+		queueDepth: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: queueDepthMetric,
+			Help: "Number of payloads received from the observer and not yet processed",
+		}),
+	}
+
+	registry.MustRegister(
+		pm.payloadDuration,
+		pm.requestDuration,
+		pm.blockStageDuration,
+		pm.documentsWritten,
+		pm.lastIndexedNonce,
+		pm.lastIndexedTimestamp,
+		pm.indexingLag,
+		pm.queueDepth,
+	)
+
+	return pm
+}
+
 func counterMetric(metricName, operation string, shardIDStr string, count uint64) string {
 	metricFamily := &dto.MetricFamily{
 		Name: proto.String(metricName),
@@ -3,11 +3,17 @@ package metrics
 import (
 	"bytes"
 	"net/http"
+	"strconv"
 	"strings"
 	"sync"
+	"time"
 	"unicode"
 
 	"github.com/kalyan3104/k-chain-es-indexer-go/core/request"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
 )
 
 const (
@@ -16,18 +22,52 @@ const (
 	totalTime      = "total_time"
 	totalData      = "total_data"
 	requestsErrors = "requests_errors"
+
+	// the topics of the requests sent to Elasticsearch start with this prefix, the other topics being the ones of the
+	// payloads received from the observer
+	databaseRequestPrefix = "req_"
+
+	payloadDurationMetric      = "payload_processing_duration_seconds"
+	requestDurationMetric      = "elasticsearch_request_duration_seconds"
+	blockStageDurationMetric   = "block_stage_duration_seconds"
+	documentsWrittenMetric     = "documents_written_total"
+	lastIndexedNonceMetric     = "last_indexed_nonce"
+	lastIndexedTimestampMetric = "last_indexed_block_timestamp_seconds"
+	indexingLagMetric          = "indexing_lag_seconds"
+	queueDepthMetric           = "payloads_queue_depth"
 )
 
 type statusMetrics struct {
-	metrics map[string]*request.MetricsResponse
-	mut     sync.RWMutex
+	metrics         map[string]*request.MetricsResponse
+	indexedBlocks   map[uint32]*request.ShardIndexingStatus
+	lastPayloadTime time.Time
+	pendingPayloads int64
+	promMetrics     *promMetrics
+	registryHandler http.Handler
+	mut             sync.RWMutex
 }
 
 // NewStatusMetrics will return an instance of the statusMetrics
 func NewStatusMetrics() *statusMetrics {
-	return &statusMetrics{
-		metrics: make(map[string]*request.MetricsResponse),
+	registry := prometheus.NewRegistry()
+	sm := &statusMetrics{
+		metrics:       make(map[string]*request.MetricsResponse),
+		indexedBlocks: make(map[uint32]*request.ShardIndexingStatus),
+		promMetrics:   newPromMetrics(registry),
 	}
+
+	// the gauges are computed at scrape time, so the lag keeps growing when no block is indexed
+	gatherer := prometheus.GathererFunc(func() ([]*dto.MetricFamily, error) {
+		sm.updateGauges(time.Now())
+		return registry.Gather()
+	})
+	// the response is appended to the metrics of the topics, which are written in the text format and not compressed
+	sm.registryHandler = promhttp.HandlerFor(gatherer, promhttp.HandlerOpts{
+		ErrorHandling:      promhttp.ContinueOnError,
+		DisableCompression: true,
+	})
+
+	return sm
 }
 
 // AddIndexingData will add the indexing data for the give topic
@@ -54,6 +94,42 @@ func (sm *statusMetrics) AddIndexingData(args ArgsAddIndexingData) {
 	if isErrorCode {
 		sm.metrics[topic].ErrorsCount[args.StatusCode]++
 	}
+
+	sm.observeDuration(topic, args.Duration)
+}
+
+func (sm *statusMetrics) observeDuration(topicWithShardID string, duration time.Duration) {
+	topic, shardIDStr := request.SplitTopicAndShardID(topicWithShardID)
+	if strings.HasPrefix(topic, databaseRequestPrefix) {
+		sm.promMetrics.requestDuration.WithLabelValues(strings.TrimPrefix(topic, databaseRequestPrefix), shardIDStr).Observe(duration.Seconds())
+		return
+	}
+
+	sm.promMetrics.payloadDuration.WithLabelValues(topic, shardIDStr).Observe(duration.Seconds())
+}
+
+// AddBlockStageDuration will record the duration of a stage from the processing of a block
+func (sm *statusMetrics) AddBlockStageDuration(stage string, shardID uint32, duration time.Duration) {
+	sm.promMetrics.blockStageDuration.WithLabelValues(stage, strconv.FormatUint(uint64(shardID), 10)).Observe(duration.Seconds())
+}
+
+// AddWrittenDocuments will increase the number of documents successfully written in the provided index
+func (sm *statusMetrics) AddWrittenDocuments(index string, numDocuments uint64) {
+	sm.promMetrics.documentsWritten.WithLabelValues(index).Add(float64(numDocuments))
+}
+
+// IncrementPendingPayloads will increase the number of payloads received and not yet processed
+func (sm *statusMetrics) IncrementPendingPayloads() {
+	sm.mut.Lock()
+	sm.pendingPayloads++
+	sm.mut.Unlock()
+}
+
+// DecrementPendingPayloads will decrease the number of payloads received and not yet processed
+func (sm *statusMetrics) DecrementPendingPayloads() {
+	sm.mut.Lock()
+	sm.pendingPayloads--
+	sm.mut.Unlock()
 }
 
 // GetMetrics returns the metrics map
@@ -64,7 +140,7 @@ func (sm *statusMetrics) GetMetrics() map[string]*request.MetricsResponse {
 	return sm.getAllUnprotected()
 }
 
-// GetMetricsForPrometheus returns the metrics in a prometheus format
+// GetMetricsForPrometheus returns the metrics of the topics in a prometheus format
 func (sm *statusMetrics) GetMetricsForPrometheus() string {
 	sm.mut.RLock()
 	metrics := sm.getAllUnprotected()
@@ -81,9 +157,91 @@ func (sm *statusMetrics) GetMetricsForPrometheus() string {
 		stringBuilder.WriteString(errorsMetric(topic, requestsErrors, shardIDStr, metricsData.ErrorsCount))
 	}
 
-	promMetricsOutput := stringBuilder.String()
+	return stringBuilder.String()
+}
+
+// GetPrometheusHandler returns the handler that exposes the metrics of the topics, followed by the ones of the
+// prometheus registry, in the prometheus text format
+func (sm *statusMetrics) GetPrometheusHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", string(expfmt.NewFormat(expfmt.TypeTextPlain)))
+		_, _ = w.Write([]byte(sm.GetMetricsForPrometheus()))
+
+		// without the accept header, the registry is written in the text format as well
+		textRequest := r.Clone(r.Context())
+		textRequest.Header.Del("Accept")
+		sm.registryHandler.ServeHTTP(w, textRequest)
+	})
+}
+
+func (sm *statusMetrics) updateGauges(now time.Time) {
+	sm.mut.RLock()
+	defer sm.mut.RUnlock()
+
+	sm.promMetrics.queueDepth.Set(float64(sm.pendingPayloads))
+
+	for shardID, shardStatus := range sm.indexedBlocks {
+		shardIDStr := strconv.FormatUint(uint64(shardID), 10)
+		lag := now.Unix() - int64(shardStatus.LastIndexedTimestamp)
+
+		sm.promMetrics.lastIndexedNonce.WithLabelValues(shardIDStr).Set(float64(shardStatus.LastIndexedNonce))
+		sm.promMetrics.lastIndexedTimestamp.WithLabelValues(shardIDStr).Set(float64(shardStatus.LastIndexedTimestamp))
+		sm.promMetrics.indexingLag.WithLabelValues(shardIDStr).Set(float64(lag))
+	}
+}
+
+// SetLastIndexedBlock will store the nonce and the timestamp of the last block indexed for the provided shard
+func (sm *statusMetrics) SetLastIndexedBlock(shardID uint32, nonce uint64, timestamp uint64) {
+	sm.mut.Lock()
+	defer sm.mut.Unlock()
+
+	sm.indexedBlocks[shardID] = &request.ShardIndexingStatus{
+		LastIndexedNonce:     nonce,
+		LastIndexedTimestamp: timestamp,
+		IndexedAt:            time.Now().Unix(),
+	}
+}
+
+// RemoveLastIndexedBlock will remove the last indexed block of the provided shard, which has no indexed blocks anymore
+func (sm *statusMetrics) RemoveLastIndexedBlock(shardID uint32) {
+	sm.mut.Lock()
+	defer sm.mut.Unlock()
+
+	delete(sm.indexedBlocks, shardID)
+
+	shardIDStr := strconv.FormatUint(uint64(shardID), 10)
+	sm.promMetrics.lastIndexedNonce.DeleteLabelValues(shardIDStr)
+	sm.promMetrics.lastIndexedTimestamp.DeleteLabelValues(shardIDStr)
+	sm.promMetrics.indexingLag.DeleteLabelValues(shardIDStr)
+}
+
+// GetShardsIndexingStatus returns a copy of the last indexed block details of every shard
+func (sm *statusMetrics) GetShardsIndexingStatus() map[uint32]*request.ShardIndexingStatus {
+	sm.mut.RLock()
+	defer sm.mut.RUnlock()
+
+	shardsStatus := make(map[uint32]*request.ShardIndexingStatus, len(sm.indexedBlocks))
+	for shardID, shardStatus := range sm.indexedBlocks {
+		shardStatusCopy := *shardStatus
+		shardsStatus[shardID] = &shardStatusCopy
+	}
+
+	return shardsStatus
+}
+
+// SetLastPayloadTime will store the moment when the last payload was received through the websocket connection
+func (sm *statusMetrics) SetLastPayloadTime(lastPayloadTime time.Time) {
+	sm.mut.Lock()
+	sm.lastPayloadTime = lastPayloadTime
+	sm.mut.Unlock()
+}
+
+// GetLastPayloadTime returns the moment when the last payload was received through the websocket connection
+func (sm *statusMetrics) GetLastPayloadTime() time.Time {
+	sm.mut.RLock()
+	defer sm.mut.RUnlock()
 
-	return promMetricsOutput
+	return sm.lastPayloadTime
 }
 
 func (sm *statusMetrics) getAllUnprotected() map[string]*request.MetricsResponse {
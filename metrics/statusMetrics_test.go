@@ -2,13 +2,22 @@ package metrics
 
 import (
 	"net/http"
+	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/kalyan3104/k-chain-core-go/data/outport"
 	"github.com/kalyan3104/k-chain-es-indexer-go/core/request"
 	"github.com/stretchr/testify/require"
 )
 
+func getPrometheusMetrics(sm *statusMetrics) string {
+	recorder := httptest.NewRecorder()
+	sm.GetPrometheusHandler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/status/prometheus-metrics", nil))
+
+	return recorder.Body.String()
+}
+
 func TestStatusMetrics_AddIndexingDataAndGetMetrics(t *testing.T) {
 	t.Parallel()
 
@@ -41,7 +50,7 @@ func TestStatusMetrics_AddIndexingDataAndGetMetrics(t *testing.T) {
 		},
 	}, metrics[topic1])
 
-	prometheusMetrics := statusMetricsHandler.GetMetricsForPrometheus()
+	prometheusMetrics := getPrometheusMetrics(statusMetricsHandler)
 	require.Equal(t, `# TYPE test1 counter
 test1{operation="total_data",shardID="0"} 322
 
@@ -57,9 +66,78 @@ test1{operation="total_time",shardID="0"} 0
 # TYPE test1 gauge
 test1{operation="requests_errors",shardID="0",errorCode="400"} 1
 
+# HELP payload_processing_duration_seconds Duration of the processing of the payloads received from the observer
+# TYPE payload_processing_duration_seconds histogram
+payload_processing_duration_seconds_bucket{shardID="0",topic="test1",le="0.005"} 2
+payload_processing_duration_seconds_bucket{shardID="0",topic="test1",le="0.01"} 2
+payload_processing_duration_seconds_bucket{shardID="0",topic="test1",le="0.025"} 2
+payload_processing_duration_seconds_bucket{shardID="0",topic="test1",le="0.05"} 2
+payload_processing_duration_seconds_bucket{shardID="0",topic="test1",le="0.1"} 2
+payload_processing_duration_seconds_bucket{shardID="0",topic="test1",le="0.25"} 2
+payload_processing_duration_seconds_bucket{shardID="0",topic="test1",le="0.5"} 2
+payload_processing_duration_seconds_bucket{shardID="0",topic="test1",le="1"} 2
+payload_processing_duration_seconds_bucket{shardID="0",topic="test1",le="2.5"} 2
+payload_processing_duration_seconds_bucket{shardID="0",topic="test1",le="5"} 2
+payload_processing_duration_seconds_bucket{shardID="0",topic="test1",le="10"} 2
+payload_processing_duration_seconds_bucket{shardID="0",topic="test1",le="30"} 2
+payload_processing_duration_seconds_bucket{shardID="0",topic="test1",le="+Inf"} 2
+payload_processing_duration_seconds_sum{shardID="0",topic="test1"} 2.6999999999999997e-08
+payload_processing_duration_seconds_count{shardID="0",topic="test1"} 2
+# HELP payloads_queue_depth Number of payloads received from the observer and not yet processed
+# TYPE payloads_queue_depth gauge
+payloads_queue_depth 0
 `, prometheusMetrics)
 }
 
+func TestStatusMetrics_GetMetricsForPrometheusHistograms(t *testing.T) {
+	t.Parallel()
+
+	statusMetricsHandler := NewStatusMetrics()
+	statusMetricsHandler.AddIndexingData(ArgsAddIndexingData{
+		MessageLen: 10,
+		Topic:      "req_bulk_1",
+		Duration:   20 * time.Millisecond,
+	})
+	statusMetricsHandler.AddIndexingData(ArgsAddIndexingData{
+		MessageLen: 10,
+		Topic:      "req_bulk_1",
+		Duration:   3 * time.Second,
+	})
+
+	prometheusMetrics := getPrometheusMetrics(statusMetricsHandler)
+	require.Contains(t, prometheusMetrics, "# TYPE elasticsearch_request_duration_seconds histogram\n")
+	require.Contains(t, prometheusMetrics, `elasticsearch_request_duration_seconds_bucket{operation="bulk",shardID="1",le="0.01"} 0`)
+	require.Contains(t, prometheusMetrics, `elasticsearch_request_duration_seconds_bucket{operation="bulk",shardID="1",le="0.025"} 1`)
+	require.Contains(t, prometheusMetrics, `elasticsearch_request_duration_seconds_bucket{operation="bulk",shardID="1",le="5"} 2`)
+	require.Contains(t, prometheusMetrics, `elasticsearch_request_duration_seconds_bucket{operation="bulk",shardID="1",le="+Inf"} 2`)
+	require.Contains(t, prometheusMetrics, `elasticsearch_request_duration_seconds_sum{operation="bulk",shardID="1"} 3.02`)
+	require.Contains(t, prometheusMetrics, `elasticsearch_request_duration_seconds_count{operation="bulk",shardID="1"} 2`)
+	require.NotContains(t, prometheusMetrics, "payload_processing_duration_seconds")
+}
+
+func TestStatusMetrics_GetMetricsForPrometheusGaugesAndDocuments(t *testing.T) {
+	t.Parallel()
+
+	statusMetricsHandler := NewStatusMetrics()
+	statusMetricsHandler.SetLastIndexedBlock(2, 100, 5000)
+	statusMetricsHandler.AddWrittenDocuments("transactions", 3)
+	statusMetricsHandler.AddWrittenDocuments("transactions", 2)
+	statusMetricsHandler.AddWrittenDocuments("blocks", 1)
+	statusMetricsHandler.IncrementPendingPayloads()
+	statusMetricsHandler.IncrementPendingPayloads()
+	statusMetricsHandler.DecrementPendingPayloads()
+
+	prometheusMetrics := getPrometheusMetrics(statusMetricsHandler)
+	require.Contains(t, prometheusMetrics, `# TYPE documents_written_total counter
+documents_written_total{index="blocks"} 1
+documents_written_total{index="transactions"} 5
+`)
+	require.Contains(t, prometheusMetrics, `last_indexed_nonce{shardID="2"} 100`)
+	require.Contains(t, prometheusMetrics, `last_indexed_block_timestamp_seconds{shardID="2"} 5000`)
+	require.Contains(t, prometheusMetrics, `indexing_lag_seconds{shardID="2"} `)
+	require.Contains(t, prometheusMetrics, "payloads_queue_depth 1\n")
+}
+
 func TestCamelCaseToSnakeCase(t *testing.T) {
 	t.Parallel()
 
@@ -69,3 +147,52 @@ func TestCamelCaseToSnakeCase(t *testing.T) {
 	require.Equal(t, "one_one_one", camelToSnake("One_One_One"))
 	require.Equal(t, "req_block", camelToSnake("req_block"))
 }
+
+func TestStatusMetrics_SetLastIndexedBlockAndGetShardsIndexingStatus(t *testing.T) {
+	t.Parallel()
+
+	statusMetricsHandler := NewStatusMetrics()
+	statusMetricsHandler.SetLastIndexedBlock(0, 10, 1000)
+	statusMetricsHandler.SetLastIndexedBlock(1, 20, 2000)
+	statusMetricsHandler.SetLastIndexedBlock(0, 11, 1006)
+
+	shardsStatus := statusMetricsHandler.GetShardsIndexingStatus()
+	require.Len(t, shardsStatus, 2)
+	require.Equal(t, uint64(11), shardsStatus[0].LastIndexedNonce)
+	require.Equal(t, uint64(1006), shardsStatus[0].LastIndexedTimestamp)
+	require.Equal(t, uint64(20), shardsStatus[1].LastIndexedNonce)
+	require.NotZero(t, shardsStatus[1].IndexedAt)
+
+	// the returned values are copies, so changing them should not alter the stored ones
+	shardsStatus[0].LagInSeconds = 100
+	require.Zero(t, statusMetricsHandler.GetShardsIndexingStatus()[0].LagInSeconds)
+}
+
+func TestStatusMetrics_RemoveLastIndexedBlock(t *testing.T) {
+	t.Parallel()
+
+	statusMetricsHandler := NewStatusMetrics()
+	statusMetricsHandler.SetLastIndexedBlock(0, 10, 1000)
+	statusMetricsHandler.SetLastIndexedBlock(1, 20, 2000)
+	_ = getPrometheusMetrics(statusMetricsHandler)
+
+	statusMetricsHandler.RemoveLastIndexedBlock(0)
+	shardsStatus := statusMetricsHandler.GetShardsIndexingStatus()
+	require.Len(t, shardsStatus, 1)
+	require.Equal(t, uint64(20), shardsStatus[1].LastIndexedNonce)
+
+	prometheusMetrics := getPrometheusMetrics(statusMetricsHandler)
+	require.NotContains(t, prometheusMetrics, `last_indexed_nonce{shardID="0"}`)
+	require.Contains(t, prometheusMetrics, `last_indexed_nonce{shardID="1"} 20`)
+}
+
+func TestStatusMetrics_SetLastPayloadTime(t *testing.T) {
+	t.Parallel()
+
+	statusMetricsHandler := NewStatusMetrics()
+	require.True(t, statusMetricsHandler.GetLastPayloadTime().IsZero())
+
+	now := time.Now()
+	statusMetricsHandler.SetLastPayloadTime(now)
+	require.Equal(t, now, statusMetricsHandler.GetLastPayloadTime())
+}
@@ -0,0 +1,20 @@
+package mock
+
+import "github.com/kalyan3104/k-chain-es-indexer-go/process/elasticproc/stagetimings"
+
+// BlockStagesRecorderStub -
+type BlockStagesRecorderStub struct {
+	RecordBlockStagesCalled func(timer *stagetimings.BlockTimer)
+}
+
+// RecordBlockStages -
+func (bsrs *BlockStagesRecorderStub) RecordBlockStages(timer *stagetimings.BlockTimer) {
+	if bsrs.RecordBlockStagesCalled != nil {
+		bsrs.RecordBlockStagesCalled(timer)
+	}
+}
+
+// IsInterfaceNil -
+func (bsrs *BlockStagesRecorderStub) IsInterfaceNil() bool {
+	return bsrs == nil
+}
@@ -0,0 +1,22 @@
+package mock
+
+import "context"
+
+// ClusterHealthStub -
+type ClusterHealthStub struct {
+	GetClusterHealthCalled func(ctx context.Context) (string, error)
+}
+
+// GetClusterHealth -
+func (chs *ClusterHealthStub) GetClusterHealth(ctx context.Context) (string, error) {
+	if chs.GetClusterHealthCalled != nil {
+		return chs.GetClusterHealthCalled(ctx)
+	}
+
+	return "green", nil
+}
+
+// IsInterfaceNil -
+func (chs *ClusterHealthStub) IsInterfaceNil() bool {
+	return chs == nil
+}
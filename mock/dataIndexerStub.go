@@ -0,0 +1,120 @@
+package mock
+
+import (
+	"github.com/kalyan3104/k-chain-core-go/data/outport"
+	"github.com/kalyan3104/k-chain-es-indexer-go/core/request"
+)
+
+// DataIndexerStub -
+type DataIndexerStub struct {
+	SaveBlockCalled           func(outportBlock *outport.OutportBlock) error
+	RevertIndexedBlockCalled  func(blockData *outport.BlockData) error
+	FinalizedBlockCalled      func(finalizedBlock *outport.FinalizedBlock) error
+	SetCurrentSettingsCalled  func(cfg outport.OutportConfig) error
+	RevertBlocksInRangeCalled func(shardID uint32, fromNonce uint64, toNonce uint64) (uint64, error)
+	RollbackToNonceCalled     func(shardID uint32, nonce uint64) (*request.RollbackResponse, error)
+	CloseCalled               func() error
+}
+
+// SaveBlock -
+func (dis *DataIndexerStub) SaveBlock(outportBlock *outport.OutportBlock) error {
+	if dis.SaveBlockCalled != nil {
+		return dis.SaveBlockCalled(outportBlock)
+	}
+
+	return nil
+}
+
+// RevertIndexedBlock -
+func (dis *DataIndexerStub) RevertIndexedBlock(blockData *outport.BlockData) error {
+	if dis.RevertIndexedBlockCalled != nil {
+		return dis.RevertIndexedBlockCalled(blockData)
+	}
+
+	return nil
+}
+
+// SaveRoundsInfo -
+func (dis *DataIndexerStub) SaveRoundsInfo(_ *outport.RoundsInfo) error {
+	return nil
+}
+
+// SaveValidatorsPubKeys -
+func (dis *DataIndexerStub) SaveValidatorsPubKeys(_ *outport.ValidatorsPubKeys) error {
+	return nil
+}
+
+// SaveValidatorsRating -
+func (dis *DataIndexerStub) SaveValidatorsRating(_ *outport.ValidatorsRating) error {
+	return nil
+}
+
+// SaveAccounts -
+func (dis *DataIndexerStub) SaveAccounts(_ *outport.Accounts) error {
+	return nil
+}
+
+// FinalizedBlock -
+func (dis *DataIndexerStub) FinalizedBlock(finalizedBlock *outport.FinalizedBlock) error {
+	if dis.FinalizedBlockCalled != nil {
+		return dis.FinalizedBlockCalled(finalizedBlock)
+	}
+
+	return nil
+}
+
+// SetCurrentSettings -
+func (dis *DataIndexerStub) SetCurrentSettings(cfg outport.OutportConfig) error {
+	if dis.SetCurrentSettingsCalled != nil {
+		return dis.SetCurrentSettingsCalled(cfg)
+	}
+
+	return nil
+}
+
+// RevertBlocksInRange -
+func (dis *DataIndexerStub) RevertBlocksInRange(shardID uint32, fromNonce uint64, toNonce uint64) (uint64, error) {
+	if dis.RevertBlocksInRangeCalled != nil {
+		return dis.RevertBlocksInRangeCalled(shardID, fromNonce, toNonce)
+	}
+
+	return 0, nil
+}
+
+// RollbackToNonce -
+func (dis *DataIndexerStub) RollbackToNonce(shardID uint32, nonce uint64) (*request.RollbackResponse, error) {
+	if dis.RollbackToNonceCalled != nil {
+		return dis.RollbackToNonceCalled(shardID, nonce)
+	}
+
+	return &request.RollbackResponse{}, nil
+}
+
+// EnableIndex -
+func (dis *DataIndexerStub) EnableIndex(_ string) error {
+	return nil
+}
+
+// DisableIndex -
+func (dis *DataIndexerStub) DisableIndex(_ string) error {
+	return nil
+}
+
+// GetEnabledIndexes -
+func (dis *DataIndexerStub) GetEnabledIndexes() []string {
+	return nil
+}
+
+// Close -
+func (dis *DataIndexerStub) Close() error {
+	if dis.CloseCalled != nil {
+		return dis.CloseCalled()
+	}
+
+	return nil
+}
+
+// IsInterfaceNil -
+func (dis *DataIndexerStub) IsInterfaceNil() bool {
+	return dis == nil
+}
@@ -12,10 +12,32 @@ type DatabaseWriterStub struct {
 	DoMultiGetCalled          func(ids []string, index string, withSource bool, response interface{}) error
 	CheckAndCreateIndexCalled func(index string) error
 	DoScrollRequestCalled     func(index string, body []byte, withSource bool, handlerFunc func(responseBytes []byte) error) error
+	UpdateByQueryCalled       func(index string, buff *bytes.Buffer) error
+	DoSearchRequestCalled     func(index string, body []byte, resBody interface{}) error
+	DoRefreshRequestCalled    func(index string) error
+}
+
+// DoRefreshRequest -
+func (dwm *DatabaseWriterStub) DoRefreshRequest(_ context.Context, index string) error {
+	if dwm.DoRefreshRequestCalled != nil {
+		return dwm.DoRefreshRequestCalled(index)
+	}
+	return nil
+}
+
+// DoSearchRequest -
+func (dwm *DatabaseWriterStub) DoSearchRequest(_ context.Context, index string, body []byte, resBody interface{}) error {
+	if dwm.DoSearchRequestCalled != nil {
+		return dwm.DoSearchRequestCalled(index, body, resBody)
+	}
+	return nil
 }
 
 // UpdateByQuery -
-func (dwm *DatabaseWriterStub) UpdateByQuery(_ context.Context, _ string, _ *bytes.Buffer) error {
+func (dwm *DatabaseWriterStub) UpdateByQuery(_ context.Context, index string, buff *bytes.Buffer) error {
+	if dwm.UpdateByQueryCalled != nil {
+		return dwm.UpdateByQueryCalled(index, buff)
+	}
 	return nil
 }
 
@@ -1,6 +1,8 @@
 package mock
 
 import (
+	"bytes"
+
 	"github.com/kalyan3104/k-chain-core-go/data/alteredAccount"
 	"github.com/kalyan3104/k-chain-es-indexer-go/data"
 )
@@ -66,3 +68,18 @@ func (dba *DBAccountsHandlerStub) PutTokenMedataDataInTokens(_ []*data.TokenInfo
 func (dba *DBAccountsHandlerStub) SerializeTypeForProvidedIDs(_ []string, _ string, _ *data.BufferSlice, _ string) error {
 	return nil
 }
+
+// PrepareAccountsHistoryQueryInCaseOfRevert -
+func (dba *DBAccountsHandlerStub) PrepareAccountsHistoryQueryInCaseOfRevert(_ uint64, _ uint32) *bytes.Buffer {
+	return &bytes.Buffer{}
+}
+
+// PrepareLatestAccountsHistoryQuery -
+func (dba *DBAccountsHandlerStub) PrepareLatestAccountsHistoryQuery(_ []string, _ uint64) (*bytes.Buffer, error) {
+	return &bytes.Buffer{}, nil
+}
+
+// SerializeAccountsRestore -
+func (dba *DBAccountsHandlerStub) SerializeAccountsRestore(_ uint64, _ []*data.AccountBalanceHistory, _ map[string]*data.AccountBalanceHistory, _ *data.BufferSlice, _ string) error {
+	return nil
+}
@@ -4,6 +4,7 @@ import (
 	coreData "github.com/kalyan3104/k-chain-core-go/data"
 	"github.com/kalyan3104/k-chain-core-go/data/block"
 	"github.com/kalyan3104/k-chain-core-go/data/outport"
+	"github.com/kalyan3104/k-chain-es-indexer-go/data"
 )
 
 // ElasticProcessorStub -
@@ -19,6 +20,15 @@ type ElasticProcessorStub struct {
 	SaveShardValidatorsPubKeysCalled func(validators *outport.ValidatorsPubKeys) error
 	SaveAccountsCalled               func(accountsData *outport.Accounts) error
 	RemoveAccountsDCDTCalled         func(headerTimestamp uint64) error
+	RemoveBlocksInRangeCalled        func(shardID uint32, fromNonce uint64, toNonce uint64) (uint64, error)
+	GetLastIndexedBlockCalled        func(shardID uint32, maxNonce uint64) (*data.Block, error)
+	SaveLastIndexedBlockCalled       func(lastIndexedBlock *data.LastIndexedBlock) error
+	RemoveLastIndexedBlockCalled     func(shardID uint32) error
+	GetLastIndexedBlocksCalled       func() (map[uint32]*data.LastIndexedBlock, error)
+	RemoveJournalEntriesCalled       func(headerHash []byte, shardID uint32) error
+	EnableIndexCalled                func(index string) error
+	DisableIndexCalled               func(index string) error
+	GetEnabledIndexesCalled          func() []string
 }
 
 // RemoveAccountsDCDT -
@@ -116,6 +126,86 @@ func (eim *ElasticProcessorStub) SetOutportConfig(_ outport.OutportConfig) error
 	return nil
 }
 
+// RemoveJournalEntries -
+func (eim *ElasticProcessorStub) RemoveJournalEntries(headerHash []byte, shardID uint32) error {
+	if eim.RemoveJournalEntriesCalled != nil {
+		return eim.RemoveJournalEntriesCalled(headerHash, shardID)
+	}
+	return nil
+}
+
+// RemoveBlocksInRange -
+func (eim *ElasticProcessorStub) RemoveBlocksInRange(shardID uint32, fromNonce uint64, toNonce uint64) (uint64, error) {
+	if eim.RemoveBlocksInRangeCalled != nil {
+		return eim.RemoveBlocksInRangeCalled(shardID, fromNonce, toNonce)
+	}
+
+	return 0, nil
+}
+
+// GetLastIndexedBlock -
+func (eim *ElasticProcessorStub) GetLastIndexedBlock(shardID uint32, maxNonce uint64) (*data.Block, error) {
+	if eim.GetLastIndexedBlockCalled != nil {
+		return eim.GetLastIndexedBlockCalled(shardID, maxNonce)
+	}
+
+	return nil, nil
+}
+
+// SaveLastIndexedBlock -
+func (eim *ElasticProcessorStub) SaveLastIndexedBlock(lastIndexedBlock *data.LastIndexedBlock) error {
+	if eim.SaveLastIndexedBlockCalled != nil {
+		return eim.SaveLastIndexedBlockCalled(lastIndexedBlock)
+	}
+
+	return nil
+}
+
+// RemoveLastIndexedBlock -
+func (eim *ElasticProcessorStub) RemoveLastIndexedBlock(shardID uint32) error {
+	if eim.RemoveLastIndexedBlockCalled != nil {
+		return eim.RemoveLastIndexedBlockCalled(shardID)
+	}
+
+	return nil
+}
+
+// GetLastIndexedBlocks -
+func (eim *ElasticProcessorStub) GetLastIndexedBlocks() (map[uint32]*data.LastIndexedBlock, error) {
+	if eim.GetLastIndexedBlocksCalled != nil {
+		return eim.GetLastIndexedBlocksCalled()
+	}
+
+	return make(map[uint32]*data.LastIndexedBlock), nil
+}
+
+// EnableIndex -
+func (eim *ElasticProcessorStub) EnableIndex(index string) error {
+	if eim.EnableIndexCalled != nil {
+		return eim.EnableIndexCalled(index)
+	}
+
+	return nil
+}
+
+// DisableIndex -
+func (eim *ElasticProcessorStub) DisableIndex(index string) error {
+	if eim.DisableIndexCalled != nil {
+		return eim.DisableIndexCalled(index)
+	}
+
+	return nil
+}
+
+// GetEnabledIndexes -
+func (eim *ElasticProcessorStub) GetEnabledIndexes() []string {
+	if eim.GetEnabledIndexesCalled != nil {
+		return eim.GetEnabledIndexesCalled()
+	}
+
+	return nil
+}
+
 // IsInterfaceNil returns true if there is no value under the interface
 func (eim *ElasticProcessorStub) IsInterfaceNil() bool {
 	return eim == nil
@@ -0,0 +1,46 @@
+package mock
+
+import (
+	"net/http"
+
+	"github.com/kalyan3104/k-chain-es-indexer-go/core/request"
+)
+
+// FacadeStub -
+type FacadeStub struct {
+	GetMetricsCalled           func() map[string]*request.MetricsResponse
+	GetPrometheusHandlerCalled func() http.Handler
+	GetHealthCalled            func() *request.HealthResponse
+}
+
+// GetMetrics -
+func (fs *FacadeStub) GetMetrics() map[string]*request.MetricsResponse {
+	if fs.GetMetricsCalled != nil {
+		return fs.GetMetricsCalled()
+	}
+
+	return nil
+}
+
+// GetPrometheusHandler -
+func (fs *FacadeStub) GetPrometheusHandler() http.Handler {
+	if fs.GetPrometheusHandlerCalled != nil {
+		return fs.GetPrometheusHandlerCalled()
+	}
+
+	return http.NotFoundHandler()
+}
+
+// GetHealth -
+func (fs *FacadeStub) GetHealth() *request.HealthResponse {
+	if fs.GetHealthCalled != nil {
+		return fs.GetHealthCalled()
+	}
+
+	return &request.HealthResponse{Ready: true}
+}
+
+// IsInterfaceNil -
+func (fs *FacadeStub) IsInterfaceNil() bool {
+	return fs == nil
+}
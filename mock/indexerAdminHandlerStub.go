@@ -0,0 +1,88 @@
+package mock
+
+import "github.com/kalyan3104/k-chain-es-indexer-go/core/request"
+
+// IndexerAdminHandlerStub -
+type IndexerAdminHandlerStub struct {
+	PauseProcessingCalled     func()
+	ResumeProcessingCalled    func()
+	IsProcessingPausedCalled  func() bool
+	EnableIndexCalled         func(index string) error
+	DisableIndexCalled        func(index string) error
+	GetEnabledIndexesCalled   func() []string
+	RevertBlocksInRangeCalled func(shardID uint32, fromNonce uint64, toNonce uint64) (uint64, error)
+	RollbackToNonceCalled     func(shardID uint32, nonce uint64) (*request.RollbackResponse, error)
+}
+
+// PauseProcessing -
+func (iahs *IndexerAdminHandlerStub) PauseProcessing() {
+	if iahs.PauseProcessingCalled != nil {
+		iahs.PauseProcessingCalled()
+	}
+}
+
+// ResumeProcessing -
+func (iahs *IndexerAdminHandlerStub) ResumeProcessing() {
+	if iahs.ResumeProcessingCalled != nil {
+		iahs.ResumeProcessingCalled()
+	}
+}
+
+// IsProcessingPaused -
+func (iahs *IndexerAdminHandlerStub) IsProcessingPaused() bool {
+	if iahs.IsProcessingPausedCalled != nil {
+		return iahs.IsProcessingPausedCalled()
+	}
+
+	return false
+}
+
+// EnableIndex -
+func (iahs *IndexerAdminHandlerStub) EnableIndex(index string) error {
+	if iahs.EnableIndexCalled != nil {
+		return iahs.EnableIndexCalled(index)
+	}
+
+	return nil
+}
+
+// DisableIndex -
+func (iahs *IndexerAdminHandlerStub) DisableIndex(index string) error {
+	if iahs.DisableIndexCalled != nil {
+		return iahs.DisableIndexCalled(index)
+	}
+
+	return nil
+}
+
+// GetEnabledIndexes -
+func (iahs *IndexerAdminHandlerStub) GetEnabledIndexes() []string {
+	if iahs.GetEnabledIndexesCalled != nil {
+		return iahs.GetEnabledIndexesCalled()
+	}
+
+	return nil
+}
+
+// RevertBlocksInRange -
+func (iahs *IndexerAdminHandlerStub) RevertBlocksInRange(shardID uint32, fromNonce uint64, toNonce uint64) (uint64, error) {
+	if iahs.RevertBlocksInRangeCalled != nil {
+		return iahs.RevertBlocksInRangeCalled(shardID, fromNonce, toNonce)
+	}
+
+	return 0, nil
+}
+
+// RollbackToNonce -
+func (iahs *IndexerAdminHandlerStub) RollbackToNonce(shardID uint32, nonce uint64) (*request.RollbackResponse, error) {
+	if iahs.RollbackToNonceCalled != nil {
+		return iahs.RollbackToNonceCalled(shardID, nonce)
+	}
+
+	return &request.RollbackResponse{}, nil
+}
+
+// IsInterfaceNil -
+func (iahs *IndexerAdminHandlerStub) IsInterfaceNil() bool {
+	return iahs == nil
+}
@@ -0,0 +1,139 @@
+package backfill
+
+import (
+	"fmt"
+
+	"github.com/kalyan3104/k-chain-core-go/core/check"
+	"github.com/kalyan3104/k-chain-core-go/data/outport"
+	"github.com/kalyan3104/k-chain-core-go/marshal"
+	"github.com/kalyan3104/k-chain-es-indexer-go/process/dataindexer"
+	logger "github.com/kalyan3104/k-chain-logger-go"
+)
+
+var log = logger.GetOrCreate("process/backfill")
+
+// ArgsBackfiller holds the arguments needed to create a new instance of backfiller
+type ArgsBackfiller struct {
+	Marshaller  marshal.Marshalizer
+	DataIndexer DataIndexer
+	BlockSource BlockSource
+}
+
+// BackfillResults holds the outcome of a backfill
+type BackfillResults struct {
+	NumIndexedBlocks  uint64
+	NumReplacedBlocks uint64
+	LastIndexedNonce  uint64
+}
+
+type backfiller struct {
+	marshaller  marshal.Marshalizer
+	dataIndexer DataIndexer
+	blockSource BlockSource
+}
+
+// NewBackfiller will create a new instance of backfiller
+func NewBackfiller(args ArgsBackfiller) (*backfiller, error) {
+	if check.IfNil(args.Marshaller) {
+		return nil, dataindexer.ErrNilMarshalizer
+	}
+	if check.IfNil(args.DataIndexer) {
+		return nil, ErrNilDataIndexer
+	}
+	if check.IfNil(args.BlockSource) {
+		return nil, ErrNilBlockSource
+	}
+
+	return &backfiller{
+		marshaller:  args.Marshaller,
+		dataIndexer: args.DataIndexer,
+		blockSource: args.BlockSource,
+	}, nil
+}
+
+// Backfill will index again the blocks of the provided shard with the nonce in the [fromNonce, toNonce] interval. The
+// blocks are indexed in import-db mode, one by one: the data indexed for a nonce is removed right before its recorded
+// block is saved, so documents left by a block that is not part of the chain anymore are removed as well. A block that
+// is missing from the source stops the backfill before anything is removed for it, and running the backfill again for
+// the same range gives the same result
+func (b *backfiller) Backfill(shardID uint32, fromNonce uint64, toNonce uint64) (*BackfillResults, error) {
+	results := &BackfillResults{}
+	if fromNonce > toNonce {
+		return results, fmt.Errorf("%w: from nonce %d is greater than to nonce %d", dataindexer.ErrInvalidNoncesRange, fromNonce, toNonce)
+	}
+
+	err := b.dataIndexer.SetCurrentSettings(outport.OutportConfig{IsInImportDBMode: true})
+	if err != nil {
+		return results, err
+	}
+
+	for nonce := fromNonce; nonce <= toNonce; nonce++ {
+		numReplaced, errBackfill := b.backfillBlock(shardID, nonce)
+		if errBackfill != nil {
+			return results, fmt.Errorf("%w when backfilling block with nonce %d from shard %d", errBackfill, nonce, shardID)
+		}
+
+		results.NumIndexedBlocks++
+		results.NumReplacedBlocks += numReplaced
+		results.LastIndexedNonce = nonce
+
+		// avoids the overflow of the nonce when the range ends with the maximum value
+		if nonce == toNonce {
+			break
+		}
+	}
+
+	return results, nil
+}
+
+func (b *backfiller) backfillBlock(shardID uint32, nonce uint64) (uint64, error) {
+	outportBlock, err := b.getOutportBlock(shardID, nonce)
+	if err != nil {
+		return 0, err
+	}
+
+	numReplaced, err := b.dataIndexer.RevertBlocksInRange(shardID, nonce, nonce)
+	if err != nil {
+		return 0, err
+	}
+
+	err = b.dataIndexer.SaveBlock(outportBlock)
+	if err != nil {
+		return numReplaced, err
+	}
+
+	// the backfilled blocks are final, so the journal entries needed to revert them are not kept
+	err = b.dataIndexer.FinalizedBlock(&outport.FinalizedBlock{
+		ShardID:    shardID,
+		HeaderHash: outportBlock.BlockData.HeaderHash,
+	})
+	if err != nil {
+		return numReplaced, err
+	}
+
+	log.Info("backfiller: indexed block", "shardID", shardID, "nonce", nonce, "replaced", numReplaced > 0)
+
+	return numReplaced, nil
+}
+
+func (b *backfiller) getOutportBlock(shardID uint32, nonce uint64) (*outport.OutportBlock, error) {
+	payload, err := b.blockSource.GetBlockPayload(shardID, nonce)
+	if err != nil {
+		return nil, err
+	}
+
+	outportBlock := &outport.OutportBlock{}
+	err = b.marshaller.Unmarshal(outportBlock, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	if outportBlock.BlockData == nil {
+		return nil, ErrNilBlockData
+	}
+	if outportBlock.ShardID != shardID {
+		return nil, fmt.Errorf("%w: the recorded block belongs to shard %d", ErrShardMismatch, outportBlock.ShardID)
+	}
+
+	return outportBlock, nil
+}
@@ -0,0 +1,165 @@
+package backfill
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/kalyan3104/k-chain-core-go/data/outport"
+	"github.com/kalyan3104/k-chain-es-indexer-go/mock"
+	"github.com/kalyan3104/k-chain-es-indexer-go/process/dataindexer"
+	"github.com/stretchr/testify/require"
+)
+
+type blockSourceStub struct {
+	payloads map[string][]byte
+}
+
+func (bss *blockSourceStub) GetBlockPayload(shardID uint32, nonce uint64) ([]byte, error) {
+	payload, found := bss.payloads[relativePayloadPath(shardID, nonce)]
+	if !found {
+		return nil, ErrBlockNotFound
+	}
+
+	return payload, nil
+}
+
+func (bss *blockSourceStub) IsInterfaceNil() bool {
+	return bss == nil
+}
+
+func createBlockSourceStub(t *testing.T, shardID uint32, nonces ...uint64) *blockSourceStub {
+	source := &blockSourceStub{payloads: make(map[string][]byte)}
+	for _, nonce := range nonces {
+		payload, err := (&mock.MarshalizerMock{}).Marshal(&outport.OutportBlock{
+			ShardID:   shardID,
+			BlockData: &outport.BlockData{HeaderHash: []byte(fmt.Sprintf("hash%d", nonce))},
+		})
+		require.Nil(t, err)
+		source.payloads[relativePayloadPath(shardID, nonce)] = payload
+	}
+
+	return source
+}
+
+func TestNewBackfiller(t *testing.T) {
+	t.Parallel()
+
+	args := ArgsBackfiller{
+		Marshaller:  &mock.MarshalizerMock{},
+		DataIndexer: &mock.DataIndexerStub{},
+		BlockSource: &blockSourceStub{},
+	}
+
+	argsNilMarshaller := args
+	argsNilMarshaller.Marshaller = nil
+	_, err := NewBackfiller(argsNilMarshaller)
+	require.Equal(t, dataindexer.ErrNilMarshalizer, err)
+
+	argsNilDataIndexer := args
+	argsNilDataIndexer.DataIndexer = nil
+	_, err = NewBackfiller(argsNilDataIndexer)
+	require.Equal(t, ErrNilDataIndexer, err)
+
+	argsNilSource := args
+	argsNilSource.BlockSource = nil
+	_, err = NewBackfiller(argsNilSource)
+	require.Equal(t, ErrNilBlockSource, err)
+
+	b, err := NewBackfiller(args)
+	require.Nil(t, err)
+	require.NotNil(t, b)
+}
+
+func TestBackfiller_BackfillShouldReplaceEveryBlockInImportDBMode(t *testing.T) {
+	t.Parallel()
+
+	calls := make([]string, 0)
+	isInImportDBMode := false
+	dataIndexer := &mock.DataIndexerStub{
+		SetCurrentSettingsCalled: func(cfg outport.OutportConfig) error {
+			isInImportDBMode = cfg.IsInImportDBMode
+			return nil
+		},
+		RevertBlocksInRangeCalled: func(shardID uint32, fromNonce uint64, toNonce uint64) (uint64, error) {
+			require.Equal(t, fromNonce, toNonce)
+			calls = append(calls, fmt.Sprintf("revert %d", fromNonce))
+			if fromNonce == 11 {
+				return 0, nil
+			}
+			return 1, nil
+		},
+		SaveBlockCalled: func(outportBlock *outport.OutportBlock) error {
+			require.True(t, isInImportDBMode)
+			calls = append(calls, "save "+string(outportBlock.BlockData.HeaderHash))
+			return nil
+		},
+		FinalizedBlockCalled: func(finalizedBlock *outport.FinalizedBlock) error {
+			require.Equal(t, uint32(2), finalizedBlock.ShardID)
+			calls = append(calls, "finalize "+string(finalizedBlock.HeaderHash))
+			return nil
+		},
+	}
+
+	b, _ := NewBackfiller(ArgsBackfiller{
+		Marshaller:  &mock.MarshalizerMock{},
+		DataIndexer: dataIndexer,
+		BlockSource: createBlockSourceStub(t, 2, 10, 11, 12),
+	})
+
+	results, err := b.Backfill(2, 10, 12)
+	require.Nil(t, err)
+	require.Equal(t, &BackfillResults{NumIndexedBlocks: 3, NumReplacedBlocks: 2, LastIndexedNonce: 12}, results)
+	require.Equal(t, []string{
+		"revert 10", "save hash10", "finalize hash10",
+		"revert 11", "save hash11", "finalize hash11",
+		"revert 12", "save hash12", "finalize hash12",
+	}, calls)
+}
+
+func TestBackfiller_BackfillMissingBlockShouldStopBeforeRemovingIt(t *testing.T) {
+	t.Parallel()
+
+	revertedNonces := make([]uint64, 0)
+	dataIndexer := &mock.DataIndexerStub{
+		RevertBlocksInRangeCalled: func(shardID uint32, fromNonce uint64, toNonce uint64) (uint64, error) {
+			revertedNonces = append(revertedNonces, fromNonce)
+			return 1, nil
+		},
+	}
+
+	b, _ := NewBackfiller(ArgsBackfiller{
+		Marshaller:  &mock.MarshalizerMock{},
+		DataIndexer: dataIndexer,
+		BlockSource: createBlockSourceStub(t, 0, 10, 12),
+	})
+
+	results, err := b.Backfill(0, 10, 12)
+	require.True(t, errors.Is(err, ErrBlockNotFound))
+	require.Equal(t, &BackfillResults{NumIndexedBlocks: 1, NumReplacedBlocks: 1, LastIndexedNonce: 10}, results)
+	require.Equal(t, []uint64{10}, revertedNonces)
+}
+
+func TestBackfiller_BackfillShouldCheckTheRangeAndTheShard(t *testing.T) {
+	t.Parallel()
+
+	b, _ := NewBackfiller(ArgsBackfiller{
+		Marshaller:  &mock.MarshalizerMock{},
+		DataIndexer: &mock.DataIndexerStub{},
+		BlockSource: createBlockSourceStub(t, 1, 5),
+	})
+
+	_, err := b.Backfill(1, 6, 5)
+	require.True(t, errors.Is(err, dataindexer.ErrInvalidNoncesRange))
+
+	source := createBlockSourceStub(t, 1, 5)
+	source.payloads[relativePayloadPath(0, 5)] = source.payloads[relativePayloadPath(1, 5)]
+	b, _ = NewBackfiller(ArgsBackfiller{
+		Marshaller:  &mock.MarshalizerMock{},
+		DataIndexer: &mock.DataIndexerStub{},
+		BlockSource: source,
+	})
+
+	_, err = b.Backfill(0, 5, 5)
+	require.True(t, errors.Is(err, ErrShardMismatch))
+}
@@ -0,0 +1,71 @@
+package backfill
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/kalyan3104/k-chain-core-go/core/check"
+	"github.com/kalyan3104/k-chain-core-go/data/outport"
+	"github.com/kalyan3104/k-chain-core-go/marshal"
+	"github.com/kalyan3104/k-chain-es-indexer-go/process/dataindexer"
+)
+
+const (
+	directoryPermissions = 0755
+	filePermissions      = 0644
+)
+
+// ArgsBlockRecorder holds the arguments needed to create a new instance of blockRecorder
+type ArgsBlockRecorder struct {
+	Marshaller    marshal.Marshalizer
+	DirectoryPath string
+}
+
+type blockRecorder struct {
+	marshaller    marshal.Marshalizer
+	directoryPath string
+}
+
+// NewBlockRecorder will create a new instance of blockRecorder, which saves the payloads of the indexed blocks in the
+// layout expected by the file source. The payloads are marshalled with the marshaller of the websocket data
+func NewBlockRecorder(args ArgsBlockRecorder) (*blockRecorder, error) {
+	if check.IfNil(args.Marshaller) {
+		return nil, dataindexer.ErrNilMarshalizer
+	}
+	if args.DirectoryPath == "" {
+		return nil, ErrEmptyDirectoryPath
+	}
+
+	return &blockRecorder{
+		marshaller:    args.Marshaller,
+		directoryPath: args.DirectoryPath,
+	}, nil
+}
+
+// RecordBlock will save the payload of the provided block. The payload is written in a temporary file that is renamed
+// afterwards, so a source never serves a partially written payload
+func (br *blockRecorder) RecordBlock(shardID uint32, nonce uint64, outportBlock *outport.OutportBlock) error {
+	payload, err := br.marshaller.Marshal(outportBlock)
+	if err != nil {
+		return err
+	}
+
+	filePath := filepath.Join(br.directoryPath, filepath.FromSlash(relativePayloadPath(shardID, nonce)))
+	err = os.MkdirAll(filepath.Dir(filePath), directoryPermissions)
+	if err != nil {
+		return err
+	}
+
+	tmpFilePath := filePath + ".tmp"
+	err = os.WriteFile(tmpFilePath, payload, filePermissions)
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(tmpFilePath, filePath)
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (br *blockRecorder) IsInterfaceNil() bool {
+	return br == nil
+}
@@ -0,0 +1,60 @@
+package backfill
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/kalyan3104/k-chain-core-go/data/outport"
+	"github.com/kalyan3104/k-chain-es-indexer-go/mock"
+	"github.com/kalyan3104/k-chain-es-indexer-go/process/dataindexer"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewBlockRecorder(t *testing.T) {
+	t.Parallel()
+
+	recorder, err := NewBlockRecorder(ArgsBlockRecorder{DirectoryPath: t.TempDir()})
+	require.Nil(t, recorder)
+	require.Equal(t, dataindexer.ErrNilMarshalizer, err)
+
+	recorder, err = NewBlockRecorder(ArgsBlockRecorder{Marshaller: &mock.MarshalizerMock{}})
+	require.Nil(t, recorder)
+	require.Equal(t, ErrEmptyDirectoryPath, err)
+
+	recorder, err = NewBlockRecorder(ArgsBlockRecorder{Marshaller: &mock.MarshalizerMock{}, DirectoryPath: t.TempDir()})
+	require.Nil(t, err)
+	require.False(t, recorder.IsInterfaceNil())
+}
+
+func TestBlockRecorder_RecordedBlockCanBeReadByTheFileSource(t *testing.T) {
+	t.Parallel()
+
+	marshaller := &mock.MarshalizerMock{}
+	directoryPath := t.TempDir()
+	recorder, _ := NewBlockRecorder(ArgsBlockRecorder{Marshaller: marshaller, DirectoryPath: directoryPath})
+
+	outportBlock := &outport.OutportBlock{
+		ShardID:   1,
+		BlockData: &outport.BlockData{HeaderHash: []byte("hash")},
+	}
+	err := recorder.RecordBlock(1, 15, outportBlock)
+	require.Nil(t, err)
+	require.FileExists(t, filepath.Join(directoryPath, "1", "15.payload"))
+	require.NoFileExists(t, filepath.Join(directoryPath, "1", "15.payload.tmp"))
+
+	source, _ := NewFileBlockSource(directoryPath)
+	payload, err := source.GetBlockPayload(1, 15)
+	require.Nil(t, err)
+
+	readBlock := &outport.OutportBlock{}
+	err = marshaller.Unmarshal(readBlock, payload)
+	require.Nil(t, err)
+	require.Equal(t, outportBlock, readBlock)
+
+	_, err = source.GetBlockPayload(1, 16)
+	require.True(t, errors.Is(err, ErrBlockNotFound))
+
+	_, err = source.GetBlockPayload(0, 15)
+	require.True(t, errors.Is(err, ErrBlockNotFound))
+}
@@ -0,0 +1,21 @@
+package backfill
+
+import (
+	"strings"
+	"time"
+)
+
+// CreateBlockSource will create the block source for the provided source: the base URL of an HTTP endpoint if the
+// source starts with http:// or https://, a recordings directory otherwise
+func CreateBlockSource(source string, requestTimeout time.Duration) (BlockSource, error) {
+	if source == "" {
+		return nil, ErrEmptySource
+	}
+
+	isURL := strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://")
+	if isURL {
+		return NewHTTPBlockSource(source, requestTimeout)
+	}
+
+	return NewFileBlockSource(source)
+}
@@ -0,0 +1,24 @@
+package backfill
+
+import "errors"
+
+// ErrBlockNotFound signals that the source has no recorded payload for the requested block
+var ErrBlockNotFound = errors.New("block not found")
+
+// ErrNilBlockSource signals that a nil block source has been provided
+var ErrNilBlockSource = errors.New("nil block source")
+
+// ErrNilDataIndexer signals that a nil data indexer has been provided
+var ErrNilDataIndexer = errors.New("nil data indexer")
+
+// ErrEmptySource signals that no source for the recorded blocks has been provided
+var ErrEmptySource = errors.New("empty source")
+
+// ErrEmptyDirectoryPath signals that an empty directory path has been provided
+var ErrEmptyDirectoryPath = errors.New("empty directory path")
+
+// ErrNilBlockData signals that a recorded payload does not contain the block data
+var ErrNilBlockData = errors.New("nil block data")
+
+// ErrShardMismatch signals that a recorded payload belongs to another shard than the requested one
+var ErrShardMismatch = errors.New("shard mismatch")
@@ -0,0 +1,38 @@
+package backfill
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+type fileBlockSource struct {
+	directoryPath string
+}
+
+// NewFileBlockSource will create a new instance of fileBlockSource, which reads the payloads saved by the block recorder
+func NewFileBlockSource(directoryPath string) (*fileBlockSource, error) {
+	if directoryPath == "" {
+		return nil, ErrEmptyDirectoryPath
+	}
+
+	return &fileBlockSource{
+		directoryPath: directoryPath,
+	}, nil
+}
+
+// GetBlockPayload returns the recorded payload of the block with the provided nonce from the provided shard
+func (fbs *fileBlockSource) GetBlockPayload(shardID uint32, nonce uint64) ([]byte, error) {
+	filePath := filepath.Join(fbs.directoryPath, filepath.FromSlash(relativePayloadPath(shardID, nonce)))
+	payload, err := os.ReadFile(filePath)
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("%w: shard %d, nonce %d, file %s", ErrBlockNotFound, shardID, nonce, filePath)
+	}
+
+	return payload, err
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (fbs *fileBlockSource) IsInterfaceNil() bool {
+	return fbs == nil
+}
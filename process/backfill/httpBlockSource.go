@@ -0,0 +1,53 @@
+package backfill
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+type httpBlockSource struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+// NewHTTPBlockSource will create a new instance of httpBlockSource, which fetches the payloads from an endpoint that
+// serves them in the layout of the recordings directory
+func NewHTTPBlockSource(baseURL string, requestTimeout time.Duration) (*httpBlockSource, error) {
+	if baseURL == "" {
+		return nil, ErrEmptySource
+	}
+
+	return &httpBlockSource{
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		httpClient: &http.Client{Timeout: requestTimeout},
+	}, nil
+}
+
+// GetBlockPayload returns the payload of the block with the provided nonce from the provided shard
+func (hbs *httpBlockSource) GetBlockPayload(shardID uint32, nonce uint64) ([]byte, error) {
+	url := hbs.baseURL + "/" + relativePayloadPath(shardID, nonce)
+	response, err := hbs.httpClient.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = response.Body.Close()
+	}()
+
+	if response.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("%w: shard %d, nonce %d, url %s", ErrBlockNotFound, shardID, nonce, url)
+	}
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("cannot get the payload from %s, status code %d", url, response.StatusCode)
+	}
+
+	return io.ReadAll(response.Body)
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (hbs *httpBlockSource) IsInterfaceNil() bool {
+	return hbs == nil
+}
@@ -0,0 +1,66 @@
+package backfill
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTPBlockSource_GetBlockPayloadFromServedRecordings(t *testing.T) {
+	t.Parallel()
+
+	directoryPath := t.TempDir()
+	require.Nil(t, os.MkdirAll(filepath.Join(directoryPath, "0"), directoryPermissions))
+	require.Nil(t, os.WriteFile(filepath.Join(directoryPath, "0", "7.payload"), []byte("payload"), filePermissions))
+
+	server := httptest.NewServer(http.FileServer(http.Dir(directoryPath)))
+	defer server.Close()
+
+	source, err := CreateBlockSource(server.URL+"/", time.Second)
+	require.Nil(t, err)
+
+	payload, err := source.GetBlockPayload(0, 7)
+	require.Nil(t, err)
+	require.Equal(t, []byte("payload"), payload)
+
+	_, err = source.GetBlockPayload(0, 8)
+	require.True(t, errors.Is(err, ErrBlockNotFound))
+}
+
+func TestHTTPBlockSource_GetBlockPayloadServerErrorShouldErr(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/4294967295/3.payload", r.URL.Path)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	source, _ := NewHTTPBlockSource(server.URL, time.Second)
+	payload, err := source.GetBlockPayload(4294967295, 3)
+	require.Nil(t, payload)
+	require.NotNil(t, err)
+	require.False(t, errors.Is(err, ErrBlockNotFound))
+}
+
+func TestCreateBlockSource(t *testing.T) {
+	t.Parallel()
+
+	source, err := CreateBlockSource("", time.Second)
+	require.Nil(t, source)
+	require.Equal(t, ErrEmptySource, err)
+
+	source, _ = CreateBlockSource("https://recordings.local", time.Second)
+	_, isHTTPSource := source.(*httpBlockSource)
+	require.True(t, isHTTPSource)
+
+	source, _ = CreateBlockSource("./recordings", time.Second)
+	_, isFileSource := source.(*fileBlockSource)
+	require.True(t, isFileSource)
+}
@@ -0,0 +1,23 @@
+package backfill
+
+import "github.com/kalyan3104/k-chain-core-go/data/outport"
+
+// BlockSource defines what a component that provides the recorded payloads of the blocks should be able to do
+type BlockSource interface {
+	GetBlockPayload(shardID uint32, nonce uint64) ([]byte, error)
+	IsInterfaceNil() bool
+}
+
+// DataIndexer defines what the data indexer used to index again the recorded blocks should be able to do
+type DataIndexer interface {
+	SaveBlock(outportBlock *outport.OutportBlock) error
+	FinalizedBlock(finalizedBlock *outport.FinalizedBlock) error
+	SetCurrentSettings(cfg outport.OutportConfig) error
+	RevertBlocksInRange(shardID uint32, fromNonce uint64, toNonce uint64) (uint64, error)
+	IsInterfaceNil() bool
+}
+
+// Backfiller defines what a component that indexes again a range of blocks should be able to do
+type Backfiller interface {
+	Backfill(shardID uint32, fromNonce uint64, toNonce uint64) (*BackfillResults, error)
+}
@@ -0,0 +1,15 @@
+package backfill
+
+import (
+	"path"
+	"strconv"
+)
+
+const payloadFileExtension = ".payload"
+
+// relativePayloadPath returns the location of the payload of a block relative to the root of the recordings. The same
+// layout is used on disk and by the HTTP source, so a static file server on top of the recordings directory can be used
+// as a source
+func relativePayloadPath(shardID uint32, nonce uint64) string {
+	return path.Join(strconv.FormatUint(uint64(shardID), 10), strconv.FormatUint(nonce, 10)+payloadFileExtension)
+}
@@ -0,0 +1,12 @@
+package completeness
+
+import "errors"
+
+// ErrNilDatabaseClient signals that a nil database client has been provided
+var ErrNilDatabaseClient = errors.New("nil database client")
+
+// ErrNilBlocksVerifier signals that a nil blocks verifier has been provided
+var ErrNilBlocksVerifier = errors.New("nil blocks verifier")
+
+// ErrInvalidVerificationInterval signals that an invalid verification interval has been provided
+var ErrInvalidVerificationInterval = errors.New("invalid verification interval")
@@ -0,0 +1,148 @@
+package completeness
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/kalyan3104/k-chain-es-indexer-go/core/request"
+	"github.com/kalyan3104/k-chain-es-indexer-go/data"
+	"github.com/kalyan3104/k-chain-es-indexer-go/process/dataindexer"
+)
+
+type docWithLogs struct {
+	HasLogs bool `json:"hasLogs"`
+}
+
+// getExistingDocs looks up the documents of the enabled indices. The logs are looked up only for the transactions and the
+// smart contract results that are flagged with hasLogs
+func (cv *completenessVerifier) getExistingDocs(hashes *blockDocsHashes) (*existingDocs, error) {
+	existing := &existingDocs{
+		withLogs: make(map[string]struct{}),
+	}
+
+	var err error
+	existing.miniblocks, err = cv.getFoundIDs(dataindexer.MiniblocksIndex, hashes.miniblocks)
+	if err != nil {
+		return nil, err
+	}
+	existing.transactions, err = cv.getFoundIDsWithLogs(dataindexer.TransactionsIndex, hashes.transactions, existing.withLogs)
+	if err != nil {
+		return nil, err
+	}
+	existing.scresults, err = cv.getFoundIDsWithLogs(dataindexer.ScResultsIndex, hashes.scresults, existing.withLogs)
+	if err != nil {
+		return nil, err
+	}
+	existing.receipts, err = cv.getFoundIDs(dataindexer.ReceiptsIndex, hashes.receipts)
+	if err != nil {
+		return nil, err
+	}
+
+	hashesWithLogs := make([]string, 0, len(existing.withLogs))
+	for hash := range existing.withLogs {
+		hashesWithLogs = append(hashesWithLogs, hash)
+	}
+	existing.logs, err = cv.getFoundIDs(dataindexer.LogsIndex, hashesWithLogs)
+	if err != nil {
+		return nil, err
+	}
+
+	return existing, nil
+}
+
+func (cv *completenessVerifier) getFoundIDs(index string, ids []string) (map[string]struct{}, error) {
+	found := make(map[string]struct{})
+	err := cv.multiGet(index, ids, false, func(doc data.ResponseRawDocument) error {
+		found[doc.ID] = struct{}{}
+		return nil
+	})
+
+	return found, err
+}
+
+func (cv *completenessVerifier) getFoundIDsWithLogs(index string, ids []string, withLogs map[string]struct{}) (map[string]struct{}, error) {
+	found := make(map[string]struct{})
+	err := cv.multiGet(index, ids, true, func(doc data.ResponseRawDocument) error {
+		found[doc.ID] = struct{}{}
+
+		source := &docWithLogs{}
+		err := json.Unmarshal(doc.Source, source)
+		if err != nil {
+			return err
+		}
+		if source.HasLogs {
+			withLogs[doc.ID] = struct{}{}
+		}
+
+		return nil
+	})
+
+	return found, err
+}
+
+// multiGet calls the handler for every found document. Nothing is requested for a disabled index
+func (cv *completenessVerifier) multiGet(index string, ids []string, withSource bool, handler func(doc data.ResponseRawDocument) error) error {
+	if !cv.isIndexEnabled(index) {
+		return nil
+	}
+
+	ids = removeDuplicates(ids)
+	for start := 0; start < len(ids); start += maxDocsPerMultiGet {
+		end := start + maxDocsPerMultiGet
+		if end > len(ids) {
+			end = len(ids)
+		}
+
+		response := &data.ResponseRawDocuments{}
+		ctxWithValue := context.WithValue(context.Background(), request.ContextKey, request.GetTopic)
+		err := cv.dbClient.DoMultiGet(ctxWithValue, ids[start:end], index, withSource, response)
+		if err != nil {
+			return err
+		}
+
+		for _, doc := range response.Docs {
+			if !doc.Found {
+				continue
+			}
+
+			err = handler(doc)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (cv *completenessVerifier) writeReport(buffSlice *data.BufferSlice) error {
+	if !cv.isIndexEnabled(dataindexer.IncompleteBlocksIndex) {
+		return nil
+	}
+
+	for _, buff := range buffSlice.Buffers() {
+		ctxWithValue := context.WithValue(context.Background(), request.ContextKey, request.BulkTopic)
+		err := cv.dbClient.DoBulkRequest(ctxWithValue, buff, dataindexer.IncompleteBlocksIndex)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func removeDuplicates(ids []string) []string {
+	seen := make(map[string]struct{}, len(ids))
+	uniqueIDs := make([]string, 0, len(ids))
+	for _, id := range ids {
+		_, isDuplicate := seen[id]
+		if isDuplicate {
+			continue
+		}
+
+		seen[id] = struct{}{}
+		uniqueIDs = append(uniqueIDs, id)
+	}
+
+	return uniqueIDs
+}
@@ -0,0 +1,28 @@
+package completeness
+
+import (
+	"bytes"
+	"context"
+)
+
+// DatabaseClientHandler defines the actions that the database client used by the verifier should be able to do
+type DatabaseClientHandler interface {
+	DoScrollRequest(ctx context.Context, index string, body []byte, withSource bool, handlerFunc func(responseBytes []byte) error) error
+	DoMultiGet(ctx context.Context, ids []string, index string, withSource bool, res interface{}) error
+	DoBulkRequest(ctx context.Context, buff *bytes.Buffer, index string) error
+	IsInterfaceNil() bool
+}
+
+// BlocksVerifier defines what a component that verifies the completeness of the indexed blocks should be able to do
+type BlocksVerifier interface {
+	VerifyNoncesRange(shardID uint32, fromNonce uint64, toNonce uint64) (*VerificationResults, error)
+	VerifyTimestampRange(fromTimestamp uint64, toTimestamp uint64) (*VerificationResults, error)
+	IsInterfaceNil() bool
+}
+
+// PeriodicVerifier defines what a component that verifies the newly indexed blocks in the background should be able to do
+type PeriodicVerifier interface {
+	StartVerification()
+	Close() error
+	IsInterfaceNil() bool
+}
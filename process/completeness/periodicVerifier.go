@@ -0,0 +1,104 @@
+package completeness
+
+import (
+	"context"
+	"time"
+
+	"github.com/kalyan3104/k-chain-core-go/core/check"
+)
+
+// ArgsPeriodicVerifier holds the arguments needed to create a new instance of periodic verifier
+type ArgsPeriodicVerifier struct {
+	BlocksVerifier BlocksVerifier
+	Interval       time.Duration
+	// Delay keeps the verification behind the indexing, so the blocks whose data is still being indexed are not reported
+	Delay time.Duration
+}
+
+type periodicVerifier struct {
+	blocksVerifier BlocksVerifier
+	interval       time.Duration
+	delay          time.Duration
+	lastTimestamp  uint64
+	cancelFunc     context.CancelFunc
+	getNow         func() time.Time
+}
+
+// NewPeriodicVerifier will create a new instance of periodic verifier
+func NewPeriodicVerifier(args ArgsPeriodicVerifier) (*periodicVerifier, error) {
+	if check.IfNil(args.BlocksVerifier) {
+		return nil, ErrNilBlocksVerifier
+	}
+	if args.Interval <= 0 {
+		return nil, ErrInvalidVerificationInterval
+	}
+
+	return &periodicVerifier{
+		blocksVerifier: args.BlocksVerifier,
+		interval:       args.Interval,
+		delay:          args.Delay,
+		getNow:         time.Now,
+	}, nil
+}
+
+// StartVerification will start verifying, once every interval, the blocks indexed since the previous verification
+func (pv *periodicVerifier) StartVerification() {
+	ctx, cancel := context.WithCancel(context.Background())
+	pv.cancelFunc = cancel
+
+	pv.lastTimestamp = uint64(pv.getNow().Add(-pv.delay).Unix())
+	go pv.verificationLoop(ctx)
+}
+
+func (pv *periodicVerifier) verificationLoop(ctx context.Context) {
+	timer := time.NewTimer(pv.interval)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Debug("periodicVerifier: verification loop is closing")
+			return
+		case <-timer.C:
+			pv.verify()
+			timer.Reset(pv.interval)
+		}
+	}
+}
+
+// verify checks the blocks with the timestamp in the (last verified timestamp, now - delay] interval. The interval is
+// verified again on the next run if the verification fails
+func (pv *periodicVerifier) verify() {
+	toTimestamp := uint64(pv.getNow().Add(-pv.delay).Unix())
+	if toTimestamp <= pv.lastTimestamp {
+		return
+	}
+
+	results, err := pv.blocksVerifier.VerifyTimestampRange(pv.lastTimestamp+1, toTimestamp)
+	if err != nil {
+		log.Warn("periodicVerifier.verify", "from timestamp", pv.lastTimestamp+1, "to timestamp", toTimestamp, "error", err)
+		return
+	}
+
+	log.Debug("periodicVerifier.verify", "from timestamp", pv.lastTimestamp+1, "to timestamp", toTimestamp,
+		"num verified blocks", results.NumVerifiedBlocks, "num incomplete blocks", results.NumIncompleteBlocks)
+	if results.NumIncompleteBlocks > 0 {
+		log.Warn("periodicVerifier.verify found incomplete blocks", "num incomplete blocks", results.NumIncompleteBlocks)
+	}
+
+	pv.lastTimestamp = toTimestamp
+}
+
+// Close will stop the verification
+func (pv *periodicVerifier) Close() error {
+	if pv.cancelFunc != nil {
+		pv.cancelFunc()
+	}
+
+	return nil
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (pv *periodicVerifier) IsInterfaceNil() bool {
+	return pv == nil
+}
@@ -0,0 +1,104 @@
+package completeness
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type blocksVerifierStub struct {
+	VerifyTimestampRangeCalled func(fromTimestamp uint64, toTimestamp uint64) (*VerificationResults, error)
+}
+
+func (bvs *blocksVerifierStub) VerifyNoncesRange(_ uint32, _ uint64, _ uint64) (*VerificationResults, error) {
+	return &VerificationResults{}, nil
+}
+
+func (bvs *blocksVerifierStub) VerifyTimestampRange(fromTimestamp uint64, toTimestamp uint64) (*VerificationResults, error) {
+	if bvs.VerifyTimestampRangeCalled != nil {
+		return bvs.VerifyTimestampRangeCalled(fromTimestamp, toTimestamp)
+	}
+
+	return &VerificationResults{}, nil
+}
+
+func (bvs *blocksVerifierStub) IsInterfaceNil() bool {
+	return bvs == nil
+}
+
+func TestNewPeriodicVerifier(t *testing.T) {
+	t.Parallel()
+
+	_, err := NewPeriodicVerifier(ArgsPeriodicVerifier{Interval: time.Second})
+	require.Equal(t, ErrNilBlocksVerifier, err)
+
+	_, err = NewPeriodicVerifier(ArgsPeriodicVerifier{BlocksVerifier: &blocksVerifierStub{}})
+	require.Equal(t, ErrInvalidVerificationInterval, err)
+
+	pv, err := NewPeriodicVerifier(ArgsPeriodicVerifier{BlocksVerifier: &blocksVerifierStub{}, Interval: time.Second})
+	require.Nil(t, err)
+	require.False(t, pv.IsInterfaceNil())
+	require.Nil(t, pv.Close())
+}
+
+func TestPeriodicVerifier_VerifyShouldVerifyConsecutiveRanges(t *testing.T) {
+	t.Parallel()
+
+	verifiedRanges := make([][2]uint64, 0)
+	pv, _ := NewPeriodicVerifier(ArgsPeriodicVerifier{
+		BlocksVerifier: &blocksVerifierStub{
+			VerifyTimestampRangeCalled: func(fromTimestamp uint64, toTimestamp uint64) (*VerificationResults, error) {
+				verifiedRanges = append(verifiedRanges, [2]uint64{fromTimestamp, toTimestamp})
+				return &VerificationResults{}, nil
+			},
+		},
+		Interval: time.Second,
+		Delay:    10 * time.Second,
+	})
+
+	now := time.Unix(1000, 0)
+	pv.getNow = func() time.Time {
+		return now
+	}
+	pv.lastTimestamp = 900
+
+	pv.verify()
+	// nothing new to verify
+	pv.verify()
+	now = time.Unix(1050, 0)
+	pv.verify()
+
+	require.Equal(t, [][2]uint64{{901, 990}, {991, 1040}}, verifiedRanges)
+}
+
+func TestPeriodicVerifier_StartVerificationAndClose(t *testing.T) {
+	t.Parallel()
+
+	called := make(chan struct{}, 1)
+	pv, _ := NewPeriodicVerifier(ArgsPeriodicVerifier{
+		BlocksVerifier: &blocksVerifierStub{
+			VerifyTimestampRangeCalled: func(fromTimestamp uint64, toTimestamp uint64) (*VerificationResults, error) {
+				select {
+				case called <- struct{}{}:
+				default:
+				}
+				return &VerificationResults{}, nil
+			},
+		},
+		Interval: 10 * time.Millisecond,
+	})
+	now := time.Unix(1000, 0)
+	pv.getNow = func() time.Time {
+		now = now.Add(time.Second)
+		return now
+	}
+
+	pv.StartVerification()
+	select {
+	case <-called:
+	case <-time.After(time.Second):
+		require.Fail(t, "verification was not started")
+	}
+	require.Nil(t, pv.Close())
+}
@@ -0,0 +1,343 @@
+package completeness
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/kalyan3104/k-chain-core-go/core/check"
+	"github.com/kalyan3104/k-chain-core-go/data/block"
+	"github.com/kalyan3104/k-chain-es-indexer-go/core/request"
+	"github.com/kalyan3104/k-chain-es-indexer-go/data"
+	"github.com/kalyan3104/k-chain-es-indexer-go/process/dataindexer"
+	"github.com/kalyan3104/k-chain-es-indexer-go/process/elasticproc/converters"
+	logger "github.com/kalyan3104/k-chain-logger-go"
+)
+
+var log = logger.GetOrCreate("process/completeness")
+
+const (
+	maxDocsPerMultiGet = 1000
+
+	issueMissingMiniblocks   = "missing_miniblocks"
+	issueMissingTransactions = "missing_transactions"
+	issueMissingScResults    = "missing_scresults"
+	issueMissingReceipts     = "missing_receipts"
+	issueMissingLogs         = "missing_logs"
+	issueTxCountMismatch     = "tx_count_mismatch"
+)
+
+// ArgsCompletenessVerifier holds the arguments needed to create a new instance of completeness verifier
+type ArgsCompletenessVerifier struct {
+	DBClient           DatabaseClientHandler
+	EnabledIndices     []string
+	BulkRequestMaxSize int
+}
+
+// VerificationResults holds the outcome of a verification
+type VerificationResults struct {
+	NumVerifiedBlocks   uint64
+	NumIncompleteBlocks uint64
+}
+
+type completenessVerifier struct {
+	dbClient           DatabaseClientHandler
+	enabledIndices     map[string]struct{}
+	bulkRequestMaxSize int
+}
+
+// blockDocsHashes holds the hashes of the documents that should exist for a block, grouped by index
+type blockDocsHashes struct {
+	miniblocks   []string
+	transactions []string
+	scresults    []string
+	receipts     []string
+}
+
+// existingDocs holds the hashes of the documents found in the database for a page of blocks
+type existingDocs struct {
+	miniblocks   map[string]struct{}
+	transactions map[string]struct{}
+	scresults    map[string]struct{}
+	receipts     map[string]struct{}
+	logs         map[string]struct{}
+	withLogs     map[string]struct{}
+}
+
+// NewCompletenessVerifier will create a new instance of completeness verifier
+func NewCompletenessVerifier(args ArgsCompletenessVerifier) (*completenessVerifier, error) {
+	if check.IfNil(args.DBClient) {
+		return nil, ErrNilDatabaseClient
+	}
+
+	enabledIndices := make(map[string]struct{}, len(args.EnabledIndices))
+	for _, index := range args.EnabledIndices {
+		enabledIndices[index] = struct{}{}
+	}
+
+	return &completenessVerifier{
+		dbClient:           args.DBClient,
+		enabledIndices:     enabledIndices,
+		bulkRequestMaxSize: args.BulkRequestMaxSize,
+	}, nil
+}
+
+// VerifyNoncesRange will verify the indexed blocks of the provided shard with the nonce in the [fromNonce, toNonce] interval
+func (cv *completenessVerifier) VerifyNoncesRange(shardID uint32, fromNonce uint64, toNonce uint64) (*VerificationResults, error) {
+	if fromNonce > toNonce {
+		return &VerificationResults{}, fmt.Errorf("%w: from nonce %d is greater than to nonce %d", dataindexer.ErrInvalidNoncesRange, fromNonce, toNonce)
+	}
+
+	query := fmt.Sprintf(`{"query": {"bool": {"must": [{"match": {"shardId": {"query": %d,"operator": "AND"}}},{"range": {"nonce": {"gte": %d,"lte": %d}}}]}}}`, shardID, fromNonce, toNonce)
+	return cv.verifyBlocks(query)
+}
+
+// VerifyTimestampRange will verify the indexed blocks of all the shards with the timestamp in the [fromTimestamp, toTimestamp]
+// interval
+func (cv *completenessVerifier) VerifyTimestampRange(fromTimestamp uint64, toTimestamp uint64) (*VerificationResults, error) {
+	if fromTimestamp > toTimestamp {
+		return &VerificationResults{}, nil
+	}
+
+	query := fmt.Sprintf(`{"query": {"range": {"timestamp": {"gte": %d,"lte": %d}}}}`, fromTimestamp, toTimestamp)
+	return cv.verifyBlocks(query)
+}
+
+// verifyBlocks verifies the blocks page by page, as they are returned by the scroll request, and writes the report of
+// every page before moving to the next one
+func (cv *completenessVerifier) verifyBlocks(query string) (*VerificationResults, error) {
+	results := &VerificationResults{}
+	handlerFunc := func(responseBytes []byte) error {
+		responseScroll := &data.ResponseScroll{}
+		err := json.Unmarshal(responseBytes, responseScroll)
+		if err != nil {
+			return err
+		}
+
+		indexedBlocks := make([]*data.Block, 0, len(responseScroll.Hits.Hits))
+		for _, hit := range responseScroll.Hits.Hits {
+			indexedBlock := &data.Block{}
+			err = json.Unmarshal(hit.Source, indexedBlock)
+			if err != nil {
+				return err
+			}
+
+			indexedBlock.Hash = hit.ID
+			indexedBlocks = append(indexedBlocks, indexedBlock)
+		}
+
+		numIncomplete, err := cv.verifyPage(indexedBlocks)
+		if err != nil {
+			return err
+		}
+
+		results.NumVerifiedBlocks += uint64(len(indexedBlocks))
+		results.NumIncompleteBlocks += numIncomplete
+
+		return nil
+	}
+
+	ctxWithValue := context.WithValue(context.Background(), request.ContextKey, request.ScrollTopic)
+	err := cv.dbClient.DoScrollRequest(ctxWithValue, dataindexer.BlockIndex, []byte(query), true, handlerFunc)
+
+	return results, err
+}
+
+func (cv *completenessVerifier) verifyPage(indexedBlocks []*data.Block) (uint64, error) {
+	if len(indexedBlocks) == 0 {
+		return 0, nil
+	}
+
+	blocksHashes := make([]*blockDocsHashes, 0, len(indexedBlocks))
+	allHashes := &blockDocsHashes{}
+	for _, indexedBlock := range indexedBlocks {
+		hashes := getExpectedDocsHashes(indexedBlock)
+		blocksHashes = append(blocksHashes, hashes)
+
+		allHashes.miniblocks = append(allHashes.miniblocks, hashes.miniblocks...)
+		allHashes.transactions = append(allHashes.transactions, hashes.transactions...)
+		allHashes.scresults = append(allHashes.scresults, hashes.scresults...)
+		allHashes.receipts = append(allHashes.receipts, hashes.receipts...)
+	}
+
+	existing, err := cv.getExistingDocs(allHashes)
+	if err != nil {
+		return 0, err
+	}
+
+	verifiedAt := time.Duration(time.Now().Unix())
+	buffSlice := data.NewBufferSlice(cv.bulkRequestMaxSize)
+	numIncomplete := uint64(0)
+	for idx, indexedBlock := range indexedBlocks {
+		incompleteBlock := cv.checkBlock(indexedBlock, blocksHashes[idx], existing)
+		if incompleteBlock == nil {
+			err = serializeCompleteBlock(indexedBlock.Hash, buffSlice)
+			if err != nil {
+				return 0, err
+			}
+			continue
+		}
+
+		numIncomplete++
+		incompleteBlock.VerifiedAt = verifiedAt
+		log.Debug("completenessVerifier: incomplete block", "shardID", indexedBlock.ShardID, "nonce", indexedBlock.Nonce,
+			"hash", indexedBlock.Hash, "issues", incompleteBlock.Issues)
+
+		err = serializeIncompleteBlock(incompleteBlock, buffSlice)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	return numIncomplete, cv.writeReport(buffSlice)
+}
+
+// getExpectedDocsHashes returns the hashes of the documents that should have been indexed for the provided block. Only the
+// transactions processed in the block are expected, the rest of a partially executed miniblock belongs to other blocks
+func getExpectedDocsHashes(indexedBlock *data.Block) *blockDocsHashes {
+	hashes := &blockDocsHashes{
+		miniblocks: indexedBlock.MiniBlocksHashes,
+	}
+
+	for _, mbDetails := range indexedBlock.MiniBlocksDetails {
+		processedTxsHashes := getProcessedTxsHashes(mbDetails)
+		switch mbDetails.Type {
+		case block.TxBlock.String(), block.RewardsBlock.String(), block.InvalidBlock.String():
+			hashes.transactions = append(hashes.transactions, processedTxsHashes...)
+		case block.SmartContractResultBlock.String():
+			hashes.scresults = append(hashes.scresults, processedTxsHashes...)
+		case block.ReceiptBlock.String():
+			hashes.receipts = append(hashes.receipts, processedTxsHashes...)
+		}
+	}
+
+	return hashes
+}
+
+func getProcessedTxsHashes(mbDetails *data.MiniBlocksDetails) []string {
+	processedTxsHashes := make([]string, 0, len(mbDetails.TxsHashes))
+	for idx, txHash := range mbDetails.TxsHashes {
+		isProcessed := int32(idx) >= mbDetails.IndexFirstProcessedTx && int32(idx) <= mbDetails.IndexLastProcessedTx
+		if !isProcessed {
+			continue
+		}
+
+		processedTxsHashes = append(processedTxsHashes, txHash)
+	}
+
+	return processedTxsHashes
+}
+
+// checkBlock returns nil if all the documents expected for the provided block were found
+func (cv *completenessVerifier) checkBlock(indexedBlock *data.Block, hashes *blockDocsHashes, existing *existingDocs) *data.IncompleteBlock {
+	incompleteBlock := &data.IncompleteBlock{
+		Hash:             indexedBlock.Hash,
+		ShardID:          indexedBlock.ShardID,
+		Nonce:            indexedBlock.Nonce,
+		Timestamp:        indexedBlock.Timestamp,
+		TxCount:          indexedBlock.TxCount,
+		NumMiniblocksTxs: getNumMiniblocksTxs(indexedBlock),
+	}
+
+	if incompleteBlock.NumMiniblocksTxs < incompleteBlock.TxCount {
+		incompleteBlock.Issues = append(incompleteBlock.Issues, issueTxCountMismatch)
+	}
+
+	if cv.isIndexEnabled(dataindexer.MiniblocksIndex) {
+		incompleteBlock.MissingMiniblocks = getMissingHashes(hashes.miniblocks, existing.miniblocks)
+		incompleteBlock.Issues = appendIssueIfNeeded(incompleteBlock.Issues, issueMissingMiniblocks, incompleteBlock.MissingMiniblocks)
+	}
+	if cv.isIndexEnabled(dataindexer.TransactionsIndex) {
+		incompleteBlock.MissingTransactions = getMissingHashes(hashes.transactions, existing.transactions)
+		incompleteBlock.Issues = appendIssueIfNeeded(incompleteBlock.Issues, issueMissingTransactions, incompleteBlock.MissingTransactions)
+	}
+	if cv.isIndexEnabled(dataindexer.ScResultsIndex) {
+		incompleteBlock.MissingScResults = getMissingHashes(hashes.scresults, existing.scresults)
+		incompleteBlock.Issues = appendIssueIfNeeded(incompleteBlock.Issues, issueMissingScResults, incompleteBlock.MissingScResults)
+	}
+	if cv.isIndexEnabled(dataindexer.ReceiptsIndex) {
+		incompleteBlock.MissingReceipts = getMissingHashes(hashes.receipts, existing.receipts)
+		incompleteBlock.Issues = appendIssueIfNeeded(incompleteBlock.Issues, issueMissingReceipts, incompleteBlock.MissingReceipts)
+	}
+	if cv.isIndexEnabled(dataindexer.LogsIndex) {
+		hashesWithLogs := getHashesWithLogs(append(hashes.transactions, hashes.scresults...), existing.withLogs)
+		incompleteBlock.MissingLogs = getMissingHashes(hashesWithLogs, existing.logs)
+		incompleteBlock.Issues = appendIssueIfNeeded(incompleteBlock.Issues, issueMissingLogs, incompleteBlock.MissingLogs)
+	}
+
+	if len(incompleteBlock.Issues) == 0 {
+		return nil
+	}
+
+	return incompleteBlock
+}
+
+func getNumMiniblocksTxs(indexedBlock *data.Block) uint32 {
+	numTxs := uint32(0)
+	for _, mbDetails := range indexedBlock.MiniBlocksDetails {
+		numTxs += uint32(len(mbDetails.TxsHashes))
+	}
+
+	return numTxs
+}
+
+func getMissingHashes(hashes []string, existing map[string]struct{}) []string {
+	missingHashes := make([]string, 0)
+	for _, hash := range hashes {
+		_, found := existing[hash]
+		if !found {
+			missingHashes = append(missingHashes, hash)
+		}
+	}
+
+	return missingHashes
+}
+
+func getHashesWithLogs(hashes []string, withLogs map[string]struct{}) []string {
+	hashesWithLogs := make([]string, 0)
+	for _, hash := range hashes {
+		_, hasLogs := withLogs[hash]
+		if hasLogs {
+			hashesWithLogs = append(hashesWithLogs, hash)
+		}
+	}
+
+	return hashesWithLogs
+}
+
+func appendIssueIfNeeded(issues []string, issue string, missingHashes []string) []string {
+	if len(missingHashes) == 0 {
+		return issues
+	}
+
+	return append(issues, issue)
+}
+
+func (cv *completenessVerifier) isIndexEnabled(index string) bool {
+	_, isEnabled := cv.enabledIndices[index]
+	return isEnabled
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (cv *completenessVerifier) IsInterfaceNil() bool {
+	return cv == nil
+}
+
+// serializeIncompleteBlock overwrites the previous report of the block, if any
+func serializeIncompleteBlock(incompleteBlock *data.IncompleteBlock, buffSlice *data.BufferSlice) error {
+	meta := []byte(fmt.Sprintf(`{ "index" : { "_id" : "%s" } }%s`, converters.JsonEscape(incompleteBlock.Hash), "\n"))
+	serializedData, err := json.Marshal(incompleteBlock)
+	if err != nil {
+		return err
+	}
+
+	return buffSlice.PutData(meta, serializedData)
+}
+
+// serializeCompleteBlock removes the previous report of the block, so the blocks that were backfilled drop out of the
+// report index
+func serializeCompleteBlock(blockHash string, buffSlice *data.BufferSlice) error {
+	meta := []byte(fmt.Sprintf(`{ "delete" : { "_id" : "%s" } }%s`, converters.JsonEscape(blockHash), "\n"))
+	return buffSlice.PutData(meta, nil)
+}
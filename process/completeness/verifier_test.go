@@ -0,0 +1,252 @@
+package completeness
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/kalyan3104/k-chain-core-go/data/block"
+	"github.com/kalyan3104/k-chain-es-indexer-go/data"
+	"github.com/kalyan3104/k-chain-es-indexer-go/mock"
+	"github.com/kalyan3104/k-chain-es-indexer-go/process/dataindexer"
+	"github.com/stretchr/testify/require"
+)
+
+var allIndices = []string{
+	dataindexer.MiniblocksIndex,
+	dataindexer.TransactionsIndex,
+	dataindexer.ScResultsIndex,
+	dataindexer.ReceiptsIndex,
+	dataindexer.LogsIndex,
+	dataindexer.IncompleteBlocksIndex,
+}
+
+// createDatabaseStub serves the provided blocks from the blocks index and the provided documents from the rest of them
+func createDatabaseStub(t *testing.T, blocks []*data.Block, docs map[string]map[string]string, bulkBuffers *[]string) *mock.DatabaseWriterStub {
+	return &mock.DatabaseWriterStub{
+		DoScrollRequestCalled: func(index string, body []byte, withSource bool, handlerFunc func(responseBytes []byte) error) error {
+			require.Equal(t, dataindexer.BlockIndex, index)
+
+			responseScroll := &data.ResponseScroll{}
+			for _, indexedBlock := range blocks {
+				source, err := json.Marshal(indexedBlock)
+				require.Nil(t, err)
+
+				responseScroll.Hits.Hits = append(responseScroll.Hits.Hits, struct {
+					ID     string          `json:"_id"`
+					Source json.RawMessage `json:"_source"`
+				}{ID: indexedBlock.Hash, Source: source})
+			}
+
+			responseBytes, err := json.Marshal(responseScroll)
+			require.Nil(t, err)
+
+			return handlerFunc(responseBytes)
+		},
+		DoMultiGetCalled: func(ids []string, index string, withSource bool, response interface{}) error {
+			responseDocs := response.(*data.ResponseRawDocuments)
+			for _, id := range ids {
+				source, found := docs[index][id]
+				responseDocs.Docs = append(responseDocs.Docs, data.ResponseRawDocument{
+					Found:  found,
+					ID:     id,
+					Source: json.RawMessage(source),
+				})
+			}
+
+			return nil
+		},
+		DoBulkRequestCalled: func(buff *bytes.Buffer, index string) error {
+			require.Equal(t, dataindexer.IncompleteBlocksIndex, index)
+			*bulkBuffers = append(*bulkBuffers, buff.String())
+			return nil
+		},
+	}
+}
+
+func createIndexedBlock() *data.Block {
+	return &data.Block{
+		Hash:             "blockHash",
+		Nonce:            10,
+		ShardID:          1,
+		TxCount:          3,
+		MiniBlocksHashes: []string{"mb1", "mb2"},
+		MiniBlocksDetails: []*data.MiniBlocksDetails{
+			{
+				MBIndex:              0,
+				Type:                 block.TxBlock.String(),
+				TxsHashes:            []string{"tx1", "tx2"},
+				IndexLastProcessedTx: 1,
+			},
+			{
+				MBIndex:              1,
+				Type:                 block.SmartContractResultBlock.String(),
+				TxsHashes:            []string{"scr1"},
+				IndexLastProcessedTx: 0,
+			},
+		},
+	}
+}
+
+func createCompleteDocs() map[string]map[string]string {
+	return map[string]map[string]string{
+		dataindexer.MiniblocksIndex:   {"mb1": `{}`, "mb2": `{}`},
+		dataindexer.TransactionsIndex: {"tx1": `{"hasLogs":true}`, "tx2": `{}`},
+		dataindexer.ScResultsIndex:    {"scr1": `{}`},
+		dataindexer.LogsIndex:         {"tx1": `{}`},
+	}
+}
+
+func TestNewCompletenessVerifier(t *testing.T) {
+	t.Parallel()
+
+	_, err := NewCompletenessVerifier(ArgsCompletenessVerifier{})
+	require.Equal(t, ErrNilDatabaseClient, err)
+
+	cv, err := NewCompletenessVerifier(ArgsCompletenessVerifier{DBClient: &mock.DatabaseWriterStub{}})
+	require.Nil(t, err)
+	require.False(t, cv.IsInterfaceNil())
+}
+
+func TestCompletenessVerifier_VerifyNoncesRangeInvalidRange(t *testing.T) {
+	t.Parallel()
+
+	cv, _ := NewCompletenessVerifier(ArgsCompletenessVerifier{DBClient: &mock.DatabaseWriterStub{}})
+	_, err := cv.VerifyNoncesRange(0, 5, 4)
+	require.True(t, errors.Is(err, dataindexer.ErrInvalidNoncesRange))
+}
+
+func TestCompletenessVerifier_VerifyNoncesRangeCompleteBlockShouldRemoveItFromReport(t *testing.T) {
+	t.Parallel()
+
+	bulkBuffers := make([]string, 0)
+	cv, _ := NewCompletenessVerifier(ArgsCompletenessVerifier{
+		DBClient:           createDatabaseStub(t, []*data.Block{createIndexedBlock()}, createCompleteDocs(), &bulkBuffers),
+		EnabledIndices:     allIndices,
+		BulkRequestMaxSize: 1000,
+	})
+
+	results, err := cv.VerifyNoncesRange(1, 10, 10)
+	require.Nil(t, err)
+	require.Equal(t, &VerificationResults{NumVerifiedBlocks: 1}, results)
+	require.Equal(t, []string{`{ "delete" : { "_id" : "blockHash" } }` + "\n"}, bulkBuffers)
+}
+
+func TestCompletenessVerifier_VerifyNoncesRangeShouldReportMissingDocs(t *testing.T) {
+	t.Parallel()
+
+	docs := createCompleteDocs()
+	delete(docs[dataindexer.MiniblocksIndex], "mb2")
+	delete(docs[dataindexer.ScResultsIndex], "scr1")
+	delete(docs[dataindexer.LogsIndex], "tx1")
+
+	bulkBuffers := make([]string, 0)
+	cv, _ := NewCompletenessVerifier(ArgsCompletenessVerifier{
+		DBClient:           createDatabaseStub(t, []*data.Block{createIndexedBlock()}, docs, &bulkBuffers),
+		EnabledIndices:     allIndices,
+		BulkRequestMaxSize: 1000,
+	})
+
+	results, err := cv.VerifyNoncesRange(1, 10, 10)
+	require.Nil(t, err)
+	require.Equal(t, &VerificationResults{NumVerifiedBlocks: 1, NumIncompleteBlocks: 1}, results)
+	require.Len(t, bulkBuffers, 1)
+
+	lines := strings.Split(strings.TrimSpace(bulkBuffers[0]), "\n")
+	require.Len(t, lines, 2)
+	require.Equal(t, `{ "index" : { "_id" : "blockHash" } }`, lines[0])
+
+	incompleteBlock := &data.IncompleteBlock{}
+	err = json.Unmarshal([]byte(lines[1]), incompleteBlock)
+	require.Nil(t, err)
+	require.Equal(t, []string{issueMissingMiniblocks, issueMissingScResults, issueMissingLogs}, incompleteBlock.Issues)
+	require.Equal(t, []string{"mb2"}, incompleteBlock.MissingMiniblocks)
+	require.Equal(t, []string{"scr1"}, incompleteBlock.MissingScResults)
+	require.Equal(t, []string{"tx1"}, incompleteBlock.MissingLogs)
+	require.Empty(t, incompleteBlock.MissingTransactions)
+	require.Equal(t, uint64(10), incompleteBlock.Nonce)
+	require.Equal(t, uint32(1), incompleteBlock.ShardID)
+}
+
+func TestCompletenessVerifier_VerifyNoncesRangeTxCountMismatch(t *testing.T) {
+	t.Parallel()
+
+	indexedBlock := createIndexedBlock()
+	indexedBlock.TxCount = 4
+
+	bulkBuffers := make([]string, 0)
+	cv, _ := NewCompletenessVerifier(ArgsCompletenessVerifier{
+		DBClient:           createDatabaseStub(t, []*data.Block{indexedBlock}, createCompleteDocs(), &bulkBuffers),
+		EnabledIndices:     allIndices,
+		BulkRequestMaxSize: 1000,
+	})
+
+	results, err := cv.VerifyNoncesRange(1, 10, 10)
+	require.Nil(t, err)
+	require.Equal(t, uint64(1), results.NumIncompleteBlocks)
+	require.Contains(t, bulkBuffers[0], `"issues":["tx_count_mismatch"]`)
+	require.Contains(t, bulkBuffers[0], `"numMiniblocksTxs":3`)
+}
+
+func TestCompletenessVerifier_VerifyNoncesRangeShouldSkipDisabledIndices(t *testing.T) {
+	t.Parallel()
+
+	requestedIndices := make(map[string]struct{})
+	bulkBuffers := make([]string, 0)
+	dbClient := createDatabaseStub(t, []*data.Block{createIndexedBlock()}, map[string]map[string]string{}, &bulkBuffers)
+	doMultiGet := dbClient.DoMultiGetCalled
+	dbClient.DoMultiGetCalled = func(ids []string, index string, withSource bool, response interface{}) error {
+		requestedIndices[index] = struct{}{}
+		return doMultiGet(ids, index, withSource, response)
+	}
+
+	cv, _ := NewCompletenessVerifier(ArgsCompletenessVerifier{
+		DBClient:           dbClient,
+		EnabledIndices:     []string{dataindexer.MiniblocksIndex},
+		BulkRequestMaxSize: 1000,
+	})
+
+	results, err := cv.VerifyNoncesRange(1, 10, 10)
+	require.Nil(t, err)
+	require.Equal(t, &VerificationResults{NumVerifiedBlocks: 1, NumIncompleteBlocks: 1}, results)
+	require.Equal(t, map[string]struct{}{dataindexer.MiniblocksIndex: {}}, requestedIndices)
+	// the report index is disabled
+	require.Empty(t, bulkBuffers)
+}
+
+func TestCompletenessVerifier_VerifyNoncesRangeShouldIgnoreNotProcessedTxs(t *testing.T) {
+	t.Parallel()
+
+	indexedBlock := createIndexedBlock()
+	indexedBlock.MiniBlocksDetails[0].TxsHashes = append(indexedBlock.MiniBlocksDetails[0].TxsHashes, "txProcessedLater")
+
+	bulkBuffers := make([]string, 0)
+	cv, _ := NewCompletenessVerifier(ArgsCompletenessVerifier{
+		DBClient:           createDatabaseStub(t, []*data.Block{indexedBlock}, createCompleteDocs(), &bulkBuffers),
+		EnabledIndices:     allIndices,
+		BulkRequestMaxSize: 1000,
+	})
+
+	results, err := cv.VerifyNoncesRange(1, 10, 10)
+	require.Nil(t, err)
+	require.Equal(t, uint64(0), results.NumIncompleteBlocks)
+}
+
+func TestCompletenessVerifier_VerifyTimestampRangeShouldQueryTimestamps(t *testing.T) {
+	t.Parallel()
+
+	expectedErr := errors.New("expected error")
+	cv, _ := NewCompletenessVerifier(ArgsCompletenessVerifier{
+		DBClient: &mock.DatabaseWriterStub{
+			DoScrollRequestCalled: func(index string, body []byte, withSource bool, handlerFunc func(responseBytes []byte) error) error {
+				require.Equal(t, `{"query": {"range": {"timestamp": {"gte": 100,"lte": 200}}}}`, string(body))
+				return expectedErr
+			},
+		},
+	})
+
+	_, err := cv.VerifyTimestampRange(100, 200)
+	require.Equal(t, expectedErr, err)
+}
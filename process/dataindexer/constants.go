@@ -49,6 +49,28 @@ const (
 	ValuesIndex = "values"
 	// EventsIndex is the Elasticsearch index for log events
 	EventsIndex = "events"
+	// EpochStatsIndex is the Elasticsearch index for the per shard and per epoch aggregated statistics
+	EpochStatsIndex = "epochstats"
+	// ContractStatsIndex is the Elasticsearch index for the daily usage statistics of the smart contracts
+	ContractStatsIndex = "contractstats"
+	// DelegationProvidersIndex is the Elasticsearch index for the aggregated information about the staking providers
+	DelegationProvidersIndex = "delegationproviders"
+	// RatingHistoryIndex is the Elasticsearch index for the validators rating of every epoch
+	RatingHistoryIndex = "ratinghistory"
+	// ValidatorsHistoryIndex is the Elasticsearch index for the validators that joined or left the list of a shard
+	ValidatorsHistoryIndex = "validatorshistory"
+	// ConsensusStatsIndex is the Elasticsearch index for the consensus participation of the validators
+	ConsensusStatsIndex = "consensusstats"
+	// TxLifecycleIndex is the Elasticsearch index for the cross-shard execution stages of the transactions
+	TxLifecycleIndex = "txlifecycle"
+	// CallTreeIndex is the Elasticsearch index for the smart contract call trees of the transactions
+	CallTreeIndex = "calltree"
+	// JournalIndex is the Elasticsearch index for the previous versions of the documents updated by the blocks
+	JournalIndex = "journal"
+	// IncompleteBlocksIndex is the Elasticsearch index for the indexed blocks whose data was found incomplete
+	IncompleteBlocksIndex = "incompleteblocks"
+	// StatsContributionsIndex is the Elasticsearch index for the values added by every block to the statistics indices
+	StatsContributionsIndex = "statscontributions"
 
 	// TransactionsPolicy is the Elasticsearch policy for the transactions
 	TransactionsPolicy = "transactions_policy"
@@ -3,6 +3,7 @@ package dataindexer
 import (
 	"encoding/hex"
 	"fmt"
+	"math"
 	"time"
 
 	"github.com/kalyan3104/k-chain-core-go/core"
@@ -11,6 +12,8 @@ import (
 	"github.com/kalyan3104/k-chain-core-go/data/block"
 	"github.com/kalyan3104/k-chain-core-go/data/outport"
 	"github.com/kalyan3104/k-chain-core-go/marshal"
+	"github.com/kalyan3104/k-chain-es-indexer-go/core/request"
+	indexerData "github.com/kalyan3104/k-chain-es-indexer-go/data"
 	logger "github.com/kalyan3104/k-chain-logger-go"
 )
 
@@ -21,12 +24,19 @@ type ArgDataIndexer struct {
 	HeaderMarshaller marshal.Marshalizer
 	ElasticProcessor ElasticProcessor
 	BlockContainer   BlockContainerHandler
+	// IndexingStatus is optional, when provided it is notified about every block that was successfully indexed and it
+	// starts from the last indexed blocks saved in the values index
+	IndexingStatus IndexingStatusHandler
+	// BlockRecorder is optional, when provided it saves every block that was successfully indexed
+	BlockRecorder BlockRecorder
 }
 
 type dataIndexer struct {
 	elasticProcessor ElasticProcessor
 	headerMarshaller marshal.Marshalizer
 	blockContainer   BlockContainerHandler
+	indexingStatus   IndexingStatusHandler
+	blockRecorder    BlockRecorder
 }
 
 // NewDataIndexer will create a new data indexer
@@ -40,11 +50,31 @@ func NewDataIndexer(arguments ArgDataIndexer) (*dataIndexer, error) {
 		elasticProcessor: arguments.ElasticProcessor,
 		headerMarshaller: arguments.HeaderMarshaller,
 		blockContainer:   arguments.BlockContainer,
+		indexingStatus:   arguments.IndexingStatus,
+		blockRecorder:    arguments.BlockRecorder,
 	}
+	dataIndexerObj.loadLastIndexedBlocks()
 
 	return dataIndexerObj, nil
 }
 
+// loadLastIndexedBlocks only logs the error, the status is filled anyway by the next indexed blocks
+func (di *dataIndexer) loadLastIndexedBlocks() {
+	if check.IfNil(di.indexingStatus) {
+		return
+	}
+
+	lastIndexedBlocks, err := di.elasticProcessor.GetLastIndexedBlocks()
+	if err != nil {
+		log.Warn("dataIndexer.loadLastIndexedBlocks", "error", err)
+		return
+	}
+
+	for shardID, lastIndexedBlock := range lastIndexedBlocks {
+		di.indexingStatus.SetLastIndexedBlock(shardID, lastIndexedBlock.Nonce, uint64(lastIndexedBlock.Timestamp))
+	}
+}
+
 func checkIndexerArgs(arguments ArgDataIndexer) error {
 	if check.IfNil(arguments.ElasticProcessor) {
 		return ErrNilElasticProcessor
@@ -93,7 +123,29 @@ func (di *dataIndexer) SaveBlock(outportBlock *outport.OutportBlock) error {
 		outportBlock.TransactionPool = &outport.TransactionPool{}
 	}
 
-	return di.saveBlockData(outportBlock, header)
+	err = di.saveBlockData(outportBlock, header)
+	if err != nil {
+		return err
+	}
+
+	if !check.IfNil(di.indexingStatus) {
+		di.indexingStatus.SetLastIndexedBlock(shardID, headerNonce, header.GetTimeStamp())
+	}
+	di.recordBlock(shardID, headerNonce, outportBlock)
+
+	return nil
+}
+
+// recordBlock only logs the errors, a block that cannot be recorded does not stop the indexing
+func (di *dataIndexer) recordBlock(shardID uint32, nonce uint64, outportBlock *outport.OutportBlock) {
+	if check.IfNil(di.blockRecorder) {
+		return
+	}
+
+	err := di.blockRecorder.RecordBlock(shardID, nonce, outportBlock)
+	if err != nil {
+		log.Warn("dataIndexer.recordBlock: cannot record block", "shardID", shardID, "nonce", nonce, "error", err)
+	}
 }
 
 func (di *dataIndexer) saveBlockData(outportBlock *outport.OutportBlock, header data.HeaderHandler) error {
@@ -160,6 +212,85 @@ func (di *dataIndexer) RevertIndexedBlock(blockData *outport.BlockData) error {
 	return di.elasticProcessor.RemoveAccountsDCDT(header.GetTimeStamp(), header.GetShardID())
 }
 
+// RevertBlocksInRange will remove the data indexed for the blocks of the provided shard with the nonce in the provided
+// range. It returns the number of reverted blocks
+func (di *dataIndexer) RevertBlocksInRange(shardID uint32, fromNonce uint64, toNonce uint64) (uint64, error) {
+	return di.elasticProcessor.RemoveBlocksInRange(shardID, fromNonce, toNonce)
+}
+
+// RollbackToNonce will remove the data indexed for the blocks of the provided shard with a nonce greater than the provided
+// one, and will reset the last indexed block of the shard to the highest remaining block. The last indexed block is
+// saved in the values index as well, so an indexer that runs in another process reads it when it starts. When no block
+// remains, the last indexed block of the shard is removed
+func (di *dataIndexer) RollbackToNonce(shardID uint32, nonce uint64) (*request.RollbackResponse, error) {
+	response := &request.RollbackResponse{
+		ShardID: shardID,
+	}
+	if nonce < math.MaxUint64 {
+		numRemoved, err := di.elasticProcessor.RemoveBlocksInRange(shardID, nonce+1, math.MaxUint64)
+		response.RemovedBlocks = numRemoved
+		if err != nil {
+			return response, err
+		}
+	}
+
+	lastBlock, err := di.elasticProcessor.GetLastIndexedBlock(shardID, nonce)
+	if err != nil {
+		return response, err
+	}
+	if lastBlock == nil {
+		return di.removeLastIndexedBlock(response)
+	}
+
+	response.LastIndexedNonce = lastBlock.Nonce
+	response.LastIndexedHash = lastBlock.Hash
+	response.LastIndexedTimestamp = uint64(lastBlock.Timestamp)
+	err = di.elasticProcessor.SaveLastIndexedBlock(&indexerData.LastIndexedBlock{
+		ShardID:   shardID,
+		Nonce:     response.LastIndexedNonce,
+		Hash:      response.LastIndexedHash,
+		Timestamp: time.Duration(response.LastIndexedTimestamp),
+	})
+	if err != nil {
+		return response, err
+	}
+
+	if !check.IfNil(di.indexingStatus) {
+		di.indexingStatus.SetLastIndexedBlock(shardID, response.LastIndexedNonce, response.LastIndexedTimestamp)
+	}
+
+	return response, nil
+}
+
+func (di *dataIndexer) removeLastIndexedBlock(response *request.RollbackResponse) (*request.RollbackResponse, error) {
+	response.NoIndexedBlocks = true
+	err := di.elasticProcessor.RemoveLastIndexedBlock(response.ShardID)
+	if err != nil {
+		return response, err
+	}
+
+	if !check.IfNil(di.indexingStatus) {
+		di.indexingStatus.RemoveLastIndexedBlock(response.ShardID)
+	}
+
+	return response, nil
+}
+
+// EnableIndex will enable the indexing of the provided index
+func (di *dataIndexer) EnableIndex(index string) error {
+	return di.elasticProcessor.EnableIndex(index)
+}
+
+// DisableIndex will disable the indexing of the provided index
+func (di *dataIndexer) DisableIndex(index string) error {
+	return di.elasticProcessor.DisableIndex(index)
+}
+
+// GetEnabledIndexes returns the indexes that are currently enabled
+func (di *dataIndexer) GetEnabledIndexes() []string {
+	return di.elasticProcessor.GetEnabledIndexes()
+}
+
 // SaveRoundsInfo will save data about a slice of rounds in elasticsearch
 func (di *dataIndexer) SaveRoundsInfo(rounds *outport.RoundsInfo) error {
 	return di.elasticProcessor.SaveRoundsInfo(rounds)
@@ -180,9 +311,10 @@ func (di *dataIndexer) SaveAccounts(accounts *outport.Accounts) error {
 	return di.elasticProcessor.SaveAccounts(accounts)
 }
 
-// FinalizedBlock returns nil
-func (di *dataIndexer) FinalizedBlock(_ *outport.FinalizedBlock) error {
-	return nil
+// FinalizedBlock will remove the journal entries of the finalized block, which cannot be reverted anymore. The
+// statistics contributions of the block are kept until their retention expires
+func (di *dataIndexer) FinalizedBlock(finalizedBlock *outport.FinalizedBlock) error {
+	return di.elasticProcessor.RemoveJournalEntries(finalizedBlock.HeaderHash, finalizedBlock.ShardID)
 }
 
 // GetMarshaller return the marshaller
@@ -1,6 +1,9 @@
 package dataindexer
 
 import (
+	"errors"
+	"fmt"
+	"math"
 	"testing"
 
 	"github.com/kalyan3104/k-chain-core-go/core"
@@ -8,6 +11,9 @@ import (
 	coreData "github.com/kalyan3104/k-chain-core-go/data"
 	dataBlock "github.com/kalyan3104/k-chain-core-go/data/block"
 	"github.com/kalyan3104/k-chain-core-go/data/outport"
+	"github.com/kalyan3104/k-chain-es-indexer-go/core/request"
+	"github.com/kalyan3104/k-chain-es-indexer-go/data"
+	"github.com/kalyan3104/k-chain-es-indexer-go/metrics"
 	"github.com/kalyan3104/k-chain-es-indexer-go/mock"
 	"github.com/stretchr/testify/require"
 )
@@ -71,13 +77,15 @@ func TestDataIndexer_SaveBlock(t *testing.T) {
 			return nil
 		},
 	}
+	statusMetrics := metrics.NewStatusMetrics()
+	arguments.IndexingStatus = statusMetrics
 	ei, _ := NewDataIndexer(arguments)
 
 	args := &outport.OutportBlock{
 		BlockData: &outport.BlockData{
 			HeaderType:  string(core.ShardHeaderV2),
 			Body:        &dataBlock.Body{MiniBlocks: []*dataBlock.MiniBlock{{}}},
-			HeaderBytes: []byte("{}"),
+			HeaderBytes: []byte(`{"Header":{"Nonce":10,"TimeStamp":5000}}`),
 		},
 	}
 	err := ei.SaveBlock(args)
@@ -85,6 +93,11 @@ func TestDataIndexer_SaveBlock(t *testing.T) {
 	require.Equal(t, 1, countMap[0])
 	require.Equal(t, 1, countMap[1])
 	require.Equal(t, 1, countMap[2])
+
+	shardsStatus := statusMetrics.GetShardsIndexingStatus()
+	require.Len(t, shardsStatus, 1)
+	require.Equal(t, uint64(10), shardsStatus[0].LastIndexedNonce)
+	require.Equal(t, uint64(5000), shardsStatus[0].LastIndexedTimestamp)
 }
 
 func TestDataIndexer_SaveRoundInfo(t *testing.T) {
@@ -185,3 +198,239 @@ func TestDataIndexer_RevertIndexedBlock(t *testing.T) {
 	require.Equal(t, 1, countMap[2])
 	require.Equal(t, 1, countMap[3])
 }
+
+func TestDataIndexer_FinalizedBlockShouldRemoveJournalEntries(t *testing.T) {
+	t.Parallel()
+
+	called := false
+	arguments := NewDataIndexerArguments()
+	arguments.ElasticProcessor = &mock.ElasticProcessorStub{
+		RemoveJournalEntriesCalled: func(headerHash []byte, shardID uint32) error {
+			require.Equal(t, []byte("hash"), headerHash)
+			require.Equal(t, uint32(2), shardID)
+			called = true
+			return nil
+		},
+	}
+	ei, _ := NewDataIndexer(arguments)
+
+	err := ei.FinalizedBlock(&outport.FinalizedBlock{ShardID: 2, HeaderHash: []byte("hash")})
+	require.Nil(t, err)
+	require.True(t, called)
+}
+
+type blockRecorderStub struct {
+	recordBlockCalled func(shardID uint32, nonce uint64, outportBlock *outport.OutportBlock) error
+}
+
+func (brs *blockRecorderStub) RecordBlock(shardID uint32, nonce uint64, outportBlock *outport.OutportBlock) error {
+	return brs.recordBlockCalled(shardID, nonce, outportBlock)
+}
+
+func (brs *blockRecorderStub) IsInterfaceNil() bool {
+	return brs == nil
+}
+
+func TestDataIndexer_SaveBlockShouldRecordTheIndexedBlocks(t *testing.T) {
+	t.Parallel()
+
+	saveHeaderErr := errors.New("save header error")
+	recordedNonces := make([]uint64, 0)
+	arguments := NewDataIndexerArguments()
+	arguments.BlockContainer = &mock.BlockContainerStub{
+		GetCalled: func(headerType core.HeaderType) (dataBlock.EmptyBlockCreator, error) {
+			return dataBlock.NewEmptyHeaderV2Creator(), nil
+		},
+	}
+	arguments.ElasticProcessor = &mock.ElasticProcessorStub{
+		SaveHeaderCalled: func(outportBlockWithHeader *outport.OutportBlockWithHeader) error {
+			if outportBlockWithHeader.Header.GetNonce() == 12 {
+				return saveHeaderErr
+			}
+			return nil
+		},
+	}
+	arguments.BlockRecorder = &blockRecorderStub{
+		recordBlockCalled: func(shardID uint32, nonce uint64, outportBlock *outport.OutportBlock) error {
+			require.Equal(t, uint32(1), shardID)
+			recordedNonces = append(recordedNonces, nonce)
+			return errors.New("the recording errors are only logged")
+		},
+	}
+	ei, _ := NewDataIndexer(arguments)
+
+	for _, nonce := range []uint64{10, 11, 12} {
+		err := ei.SaveBlock(&outport.OutportBlock{
+			ShardID: 1,
+			BlockData: &outport.BlockData{
+				HeaderType:  string(core.ShardHeaderV2),
+				Body:        &dataBlock.Body{},
+				HeaderBytes: []byte(fmt.Sprintf(`{"Header":{"Nonce":%d,"ShardID":1}}`, nonce)),
+			},
+		})
+		if nonce == 12 {
+			require.True(t, errors.Is(err, saveHeaderErr))
+			continue
+		}
+		require.Nil(t, err)
+	}
+
+	require.Equal(t, []uint64{10, 11}, recordedNonces)
+}
+
+func TestDataIndexer_RollbackToNonceShouldResetTheLastIndexedBlock(t *testing.T) {
+	t.Parallel()
+
+	var savedLastIndexedBlock *data.LastIndexedBlock
+	arguments := NewDataIndexerArguments()
+	arguments.ElasticProcessor = &mock.ElasticProcessorStub{
+		RemoveBlocksInRangeCalled: func(shardID uint32, fromNonce uint64, toNonce uint64) (uint64, error) {
+			require.Equal(t, uint32(2), shardID)
+			require.Equal(t, uint64(101), fromNonce)
+			require.Equal(t, uint64(math.MaxUint64), toNonce)
+			return 3, nil
+		},
+		GetLastIndexedBlockCalled: func(shardID uint32, maxNonce uint64) (*data.Block, error) {
+			require.Equal(t, uint32(2), shardID)
+			require.Equal(t, uint64(100), maxNonce)
+			return &data.Block{Hash: "h98", Nonce: 98, Timestamp: 6000}, nil
+		},
+		SaveLastIndexedBlockCalled: func(lastIndexedBlock *data.LastIndexedBlock) error {
+			savedLastIndexedBlock = lastIndexedBlock
+			return nil
+		},
+	}
+	statusMetrics := metrics.NewStatusMetrics()
+	statusMetrics.SetLastIndexedBlock(2, 103, 6030)
+	arguments.IndexingStatus = statusMetrics
+	ei, _ := NewDataIndexer(arguments)
+
+	response, err := ei.RollbackToNonce(2, 100)
+	require.Nil(t, err)
+	require.Equal(t, &request.RollbackResponse{
+		ShardID:              2,
+		RemovedBlocks:        3,
+		LastIndexedNonce:     98,
+		LastIndexedHash:      "h98",
+		LastIndexedTimestamp: 6000,
+	}, response)
+
+	shardsStatus := statusMetrics.GetShardsIndexingStatus()
+	require.Equal(t, uint64(98), shardsStatus[2].LastIndexedNonce)
+	require.Equal(t, uint64(6000), shardsStatus[2].LastIndexedTimestamp)
+	require.Equal(t, &data.LastIndexedBlock{ShardID: 2, Nonce: 98, Hash: "h98", Timestamp: 6000}, savedLastIndexedBlock)
+}
+
+func TestDataIndexer_RollbackToNonceWithoutRemainingBlocksShouldRemoveTheLastIndexedBlock(t *testing.T) {
+	t.Parallel()
+
+	removedShardID := uint32(math.MaxUint32)
+	arguments := NewDataIndexerArguments()
+	arguments.ElasticProcessor = &mock.ElasticProcessorStub{
+		RemoveBlocksInRangeCalled: func(shardID uint32, fromNonce uint64, toNonce uint64) (uint64, error) {
+			return 2, nil
+		},
+		GetLastIndexedBlockCalled: func(shardID uint32, maxNonce uint64) (*data.Block, error) {
+			return nil, nil
+		},
+		SaveLastIndexedBlockCalled: func(lastIndexedBlock *data.LastIndexedBlock) error {
+			require.Fail(t, "should not have been called")
+			return nil
+		},
+		RemoveLastIndexedBlockCalled: func(shardID uint32) error {
+			removedShardID = shardID
+			return nil
+		},
+	}
+	statusMetrics := metrics.NewStatusMetrics()
+	statusMetrics.SetLastIndexedBlock(2, 1, 6000)
+	arguments.IndexingStatus = statusMetrics
+	ei, _ := NewDataIndexer(arguments)
+
+	response, err := ei.RollbackToNonce(2, 0)
+	require.Nil(t, err)
+	require.Equal(t, &request.RollbackResponse{ShardID: 2, RemovedBlocks: 2, NoIndexedBlocks: true}, response)
+	require.Equal(t, uint32(2), removedShardID)
+	require.NotContains(t, statusMetrics.GetShardsIndexingStatus(), uint32(2))
+}
+
+func TestDataIndexer_RollbackToNonceShouldBeReadByANewIndexer(t *testing.T) {
+	t.Parallel()
+
+	// the rollback runs in a maintenance process, the values index is shared with the indexer started afterwards
+	savedLastIndexedBlocks := map[uint32]*data.LastIndexedBlock{
+		2: {ShardID: 2, Nonce: 103, Hash: "h103", Timestamp: 6030},
+	}
+	elasticProcessor := &mock.ElasticProcessorStub{
+		GetLastIndexedBlockCalled: func(shardID uint32, maxNonce uint64) (*data.Block, error) {
+			return &data.Block{Hash: "h98", Nonce: 98, Timestamp: 6000}, nil
+		},
+		SaveLastIndexedBlockCalled: func(lastIndexedBlock *data.LastIndexedBlock) error {
+			savedLastIndexedBlocks[lastIndexedBlock.ShardID] = lastIndexedBlock
+			return nil
+		},
+		GetLastIndexedBlocksCalled: func() (map[uint32]*data.LastIndexedBlock, error) {
+			return savedLastIndexedBlocks, nil
+		},
+	}
+
+	maintenanceArguments := NewDataIndexerArguments()
+	maintenanceArguments.ElasticProcessor = elasticProcessor
+	maintenanceIndexer, _ := NewDataIndexer(maintenanceArguments)
+	_, err := maintenanceIndexer.RollbackToNonce(2, 100)
+	require.Nil(t, err)
+
+	statusMetrics := metrics.NewStatusMetrics()
+	arguments := NewDataIndexerArguments()
+	arguments.ElasticProcessor = elasticProcessor
+	arguments.IndexingStatus = statusMetrics
+	_, _ = NewDataIndexer(arguments)
+
+	shardsStatus := statusMetrics.GetShardsIndexingStatus()
+	require.Equal(t, uint64(98), shardsStatus[2].LastIndexedNonce)
+	require.Equal(t, uint64(6000), shardsStatus[2].LastIndexedTimestamp)
+}
+
+func TestDataIndexer_RollbackToNonceSaveErrorShouldNotResetTheLastIndexedBlock(t *testing.T) {
+	t.Parallel()
+
+	expectedErr := errors.New("save error")
+	arguments := NewDataIndexerArguments()
+	arguments.ElasticProcessor = &mock.ElasticProcessorStub{
+		GetLastIndexedBlockCalled: func(shardID uint32, maxNonce uint64) (*data.Block, error) {
+			return &data.Block{Hash: "h8", Nonce: 8, Timestamp: 6000}, nil
+		},
+		SaveLastIndexedBlockCalled: func(lastIndexedBlock *data.LastIndexedBlock) error {
+			return expectedErr
+		},
+	}
+	statusMetrics := metrics.NewStatusMetrics()
+	statusMetrics.SetLastIndexedBlock(0, 12, 6030)
+	arguments.IndexingStatus = statusMetrics
+	ei, _ := NewDataIndexer(arguments)
+
+	_, err := ei.RollbackToNonce(0, 10)
+	require.Equal(t, expectedErr, err)
+	require.Equal(t, uint64(12), statusMetrics.GetShardsIndexingStatus()[0].LastIndexedNonce)
+}
+
+func TestDataIndexer_RollbackToNonceRemoveErrorShouldNotResetTheLastIndexedBlock(t *testing.T) {
+	t.Parallel()
+
+	expectedErr := errors.New("remove error")
+	arguments := NewDataIndexerArguments()
+	arguments.ElasticProcessor = &mock.ElasticProcessorStub{
+		RemoveBlocksInRangeCalled: func(shardID uint32, fromNonce uint64, toNonce uint64) (uint64, error) {
+			return 1, expectedErr
+		},
+		GetLastIndexedBlockCalled: func(shardID uint32, maxNonce uint64) (*data.Block, error) {
+			require.Fail(t, "should not have been called")
+			return nil, nil
+		},
+	}
+	ei, _ := NewDataIndexer(arguments)
+
+	response, err := ei.RollbackToNonce(0, 10)
+	require.Equal(t, expectedErr, err)
+	require.Equal(t, uint64(1), response.RemovedBlocks)
+}
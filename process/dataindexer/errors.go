@@ -88,3 +88,33 @@ var ErrNilOperationsHandler = errors.New("nil operations handler")
 
 // ErrNilBlockContainerHandler signals that a nil block container handler has been provided
 var ErrNilBlockContainerHandler = errors.New("nil bock container handler")
+
+// ErrNilEpochStatsHandler signals that a nil epoch statistics handler has been provided
+var ErrNilEpochStatsHandler = errors.New("nil epoch statistics handler")
+
+// ErrNilContractStatsHandler signals that a nil contract statistics handler has been provided
+var ErrNilContractStatsHandler = errors.New("nil contract statistics handler")
+
+// ErrNilConsensusStatsHandler signals that a nil consensus statistics handler has been provided
+var ErrNilConsensusStatsHandler = errors.New("nil consensus statistics handler")
+
+// ErrNilTxLifecycleHandler signals that a nil transactions lifecycle handler has been provided
+var ErrNilTxLifecycleHandler = errors.New("nil transactions lifecycle handler")
+
+// ErrNilCallTreeHandler signals that a nil call trees handler has been provided
+var ErrNilCallTreeHandler = errors.New("nil call trees handler")
+
+// ErrNilJournalHandler signals that a nil journal handler has been provided
+var ErrNilJournalHandler = errors.New("nil journal handler")
+
+// ErrNilStatsContributionsHandler signals that a nil statistics contributions handler has been provided
+var ErrNilStatsContributionsHandler = errors.New("nil statistics contributions handler")
+
+// ErrUnknownStatsContributionIndex signals that a statistics contribution belongs to an index that cannot be reverted
+var ErrUnknownStatsContributionIndex = errors.New("unknown statistics contribution index")
+
+// ErrUnknownIndex signals that the provided index is not handled by the indexer
+var ErrUnknownIndex = errors.New("unknown index")
+
+// ErrInvalidNoncesRange signals that an invalid range of nonces has been provided
+var ErrInvalidNoncesRange = errors.New("invalid nonces range")
@@ -8,6 +8,8 @@ import (
 	"github.com/kalyan3104/k-chain-core-go/data/block"
 	"github.com/kalyan3104/k-chain-core-go/data/outport"
 	"github.com/kalyan3104/k-chain-core-go/marshal"
+	"github.com/kalyan3104/k-chain-es-indexer-go/core/request"
+	"github.com/kalyan3104/k-chain-es-indexer-go/data"
 )
 
 // ElasticProcessor defines the interface for the elastic search indexer
@@ -24,6 +26,15 @@ type ElasticProcessor interface {
 	SaveShardValidatorsPubKeys(validatorsPubKeys *outport.ValidatorsPubKeys) error
 	SaveAccounts(accounts *outport.Accounts) error
 	SetOutportConfig(cfg outport.OutportConfig) error
+	RemoveBlocksInRange(shardID uint32, fromNonce uint64, toNonce uint64) (uint64, error)
+	GetLastIndexedBlock(shardID uint32, maxNonce uint64) (*data.Block, error)
+	SaveLastIndexedBlock(lastIndexedBlock *data.LastIndexedBlock) error
+	RemoveLastIndexedBlock(shardID uint32) error
+	GetLastIndexedBlocks() (map[uint32]*data.LastIndexedBlock, error)
+	RemoveJournalEntries(headerHash []byte, shardID uint32) error
+	EnableIndex(index string) error
+	DisableIndex(index string) error
+	GetEnabledIndexes() []string
 	IsInterfaceNil() bool
 }
 
@@ -58,6 +69,11 @@ type Indexer interface {
 	GetMarshaller() marshal.Marshalizer
 	RegisterHandler(handler func() error, topic string) error
 	SetCurrentSettings(cfg outport.OutportConfig) error
+	RevertBlocksInRange(shardID uint32, fromNonce uint64, toNonce uint64) (uint64, error)
+	RollbackToNonce(shardID uint32, nonce uint64) (*request.RollbackResponse, error)
+	EnableIndex(index string) error
+	DisableIndex(index string) error
+	GetEnabledIndexes() []string
 	Close() error
 	IsInterfaceNil() bool
 }
@@ -74,3 +90,17 @@ type BalanceConverter interface {
 type BlockContainerHandler interface {
 	Get(headerType core.HeaderType) (block.EmptyBlockCreator, error)
 }
+
+// IndexingStatusHandler defines what a component that keeps track of the last indexed blocks should be able to do
+type IndexingStatusHandler interface {
+	SetLastIndexedBlock(shardID uint32, nonce uint64, timestamp uint64)
+	RemoveLastIndexedBlock(shardID uint32)
+	IsInterfaceNil() bool
+}
+
+// BlockRecorder defines what a component that saves the indexed blocks, so they can be indexed again later, should be
+// able to do
+type BlockRecorder interface {
+	RecordBlock(shardID uint32, nonce uint64, outportBlock *outport.OutportBlock) error
+	IsInterfaceNil() bool
+}
@@ -267,6 +267,12 @@ func (ap *accountsProcessor) PrepareAccountsHistory(
 			Identifier:      converters.ComputeTokenIdentifier(userAccount.TokenName, userAccount.TokenNonce),
 			ShardID:         shardID,
 		}
+		// the nonce of the regular accounts is kept, so it can be restored when a block is reverted
+		if userAccount.TokenName == "" {
+			nonce := userAccount.Nonce
+			acc.Nonce = &nonce
+		}
+
 		keyInMap := fmt.Sprintf("%s-%s-%d", acc.Address, acc.Token, acc.TokenNonce)
 		accountsMap[keyInMap] = acc
 	}
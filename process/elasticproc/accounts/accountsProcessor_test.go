@@ -400,6 +400,37 @@ func TestAccountsProcessor_PrepareAccountsHistory(t *testing.T) {
 	}, accountBalanceHistory)
 }
 
+func TestAccountsProcessor_PrepareAccountsHistoryRegularAccountShouldKeepTheNonce(t *testing.T) {
+	t.Parallel()
+
+	accounts := map[string]*data.AccountInfo{
+		"addr1": {
+			Address:             "addr1",
+			Balance:             "112",
+			Nonce:               7,
+			UserName:            "alice",
+			DeveloperRewards:    "30",
+			DeveloperRewardsNum: 3e-9,
+			CurrentOwner:        "owner",
+			RootHash:            []byte("root"),
+			CodeHash:            []byte("code"),
+			CodeMetadata:        []byte("metadata"),
+		},
+	}
+
+	ap, _ := NewAccountsProcessor(mock.NewPubkeyConverterMock(32), balanceConverter)
+
+	res := ap.PrepareAccountsHistory(100, accounts, 1)
+	nonce := uint64(7)
+	require.Equal(t, &data.AccountBalanceHistory{
+		Address:   "addr1",
+		Timestamp: 100,
+		Balance:   "112",
+		Nonce:     &nonce,
+		ShardID:   1,
+	}, res["addr1--0"])
+}
+
 func TestAccountsProcessor_PutTokenMedataDataInTokens(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1,172 @@
+package accounts
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/big"
+
+	"github.com/kalyan3104/k-chain-es-indexer-go/data"
+	"github.com/kalyan3104/k-chain-es-indexer-go/process/elasticproc/converters"
+)
+
+// PrepareAccountsHistoryQueryInCaseOfRevert will prepare the query that selects the accounts history entries written
+// by the reverted block
+func (ap *accountsProcessor) PrepareAccountsHistoryQueryInCaseOfRevert(timestamp uint64, shardID uint32) *bytes.Buffer {
+	query := fmt.Sprintf(`{"query": {"bool": {"filter": [{"term": {"timestamp": {"value": %d}}}, {"term": {"shardID": {"value": %d}}}]}}}`, timestamp, shardID)
+
+	return bytes.NewBuffer([]byte(query))
+}
+
+// PrepareLatestAccountsHistoryQuery will prepare the query that returns, for every one of the provided addresses, the
+// latest history entry older than the reverted block. The entries of the reverted block are filtered out explicitly,
+// since their removal might not be visible yet
+func (ap *accountsProcessor) PrepareLatestAccountsHistoryQuery(addresses []string, revertedTimestamp uint64) (*bytes.Buffer, error) {
+	addressesSerialized, err := json.Marshal(addresses)
+	if err != nil {
+		return nil, err
+	}
+
+	query := fmt.Sprintf(`{"query": {"bool": {"filter": [{"terms": {"address": %s}}, {"range": {"timestamp": {"lt": %d}}}]}},`+
+		`"collapse": {"field": "address"}, "sort": [{"timestamp": {"order": "desc"}}], "size": %d}`,
+		string(addressesSerialized), revertedTimestamp, len(addresses))
+
+	return bytes.NewBuffer([]byte(query)), nil
+}
+
+// SerializeAccountsRestore will serialize the updates that bring the accounts written by the reverted block to the state
+// from their latest remaining history entries. An account without such an entry is deleted only if the entry of the
+// reverted block shows it was created by the block. Otherwise, its history is incomplete, for example because the
+// history index was enabled after the account was indexed, and the account is left unchanged. The other fields of the
+// account are restored from the entry of the reverted block, which keeps them only if the block changed them. An account
+// is changed only if it still holds the version written by the reverted block, which keeps the writes of the blocks
+// indexed meanwhile
+func (ap *accountsProcessor) SerializeAccountsRestore(
+	revertedTimestamp uint64,
+	revertedEntries []*data.AccountBalanceHistory,
+	latestEntries map[string]*data.AccountBalanceHistory,
+	buffSlice *data.BufferSlice,
+	index string,
+) error {
+	codeToExecute := `
+		if ('create' == ctx.op) {
+			ctx.op = 'noop';
+			return;
+		}
+		if (!ctx._source.containsKey('timestamp') || ctx._source.timestamp != params.revertedTimestamp) {
+			ctx.op = 'noop';
+			return;
+		}
+		if (params.account == null) {
+			ctx.op = 'delete';
+			return;
+		}
+		params.account.forEach((key, value) -> {
+			if (value == null) {
+				ctx._source.remove(key);
+			} else {
+				ctx._source[key] = value;
+			}
+		});
+`
+
+	for _, revertedEntry := range revertedEntries {
+		address := revertedEntry.Address
+		latestEntry := latestEntries[address]
+		if latestEntry == nil && !revertedEntry.Created {
+			log.Warn("accountsProcessor.SerializeAccountsRestore: cannot restore an account without history, it is left unchanged",
+				"address", address, "reverted timestamp", revertedTimestamp)
+			continue
+		}
+
+		restoredAccount, err := ap.prepareRestoredAccount(latestEntry, revertedEntry.PreviousState)
+		if err != nil {
+			return err
+		}
+
+		meta := []byte(fmt.Sprintf(`{ "update" : {"_index": "%s", "_id" : "%s" } }%s`, index, converters.JsonEscape(address), "\n"))
+		serializedDataStr := fmt.Sprintf(`{"scripted_upsert": true, "script": {`+
+			`"source": "%s",`+
+			`"lang": "painless",`+
+			`"params": { "revertedTimestamp": %d, "account": %s }},`+
+			`"upsert": {}}`,
+			converters.FormatPainlessSource(codeToExecute), revertedTimestamp, restoredAccount,
+		)
+
+		err = buffSlice.PutData(meta, []byte(serializedDataStr))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// prepareRestoredAccount returns the fields of the account that can be recovered from the history entry and from the
+// previous state kept by the reverted block. The nonce is kept unchanged for the entries written before it was saved in
+// the history, and the other fields are kept unchanged if the reverted block did not change them. A zero nonce and the
+// empty fields are removed from the account, the same as they are omitted when the account is indexed
+func (ap *accountsProcessor) prepareRestoredAccount(entry *data.AccountBalanceHistory, previousState *data.AccountHistoryState) ([]byte, error) {
+	if entry == nil {
+		return []byte("null"), nil
+	}
+
+	restoredAccount := map[string]interface{}{
+		"balance":   entry.Balance,
+		"timestamp": entry.Timestamp,
+	}
+
+	balance, ok := big.NewInt(0).SetString(entry.Balance, 10)
+	if ok {
+		balanceAsFloat, err := ap.balanceConverter.ComputeBalanceAsFloat(balance)
+		if err != nil {
+			log.Warn("accountsProcessor.prepareRestoredAccount: cannot compute balance as num",
+				"balance", balance, "address", entry.Address, "error", err)
+		}
+		restoredAccount["balanceNum"] = balanceAsFloat
+	}
+
+	switch {
+	case entry.Nonce == nil:
+	case *entry.Nonce == 0:
+		restoredAccount["nonce"] = nil
+	default:
+		restoredAccount["nonce"] = *entry.Nonce
+	}
+
+	if previousState != nil {
+		restoredAccount["userName"] = stringOrNil(previousState.UserName)
+		restoredAccount["developerRewards"] = stringOrNil(previousState.DeveloperRewards)
+		restoredAccount["developerRewardsNum"] = floatOrNil(previousState.DeveloperRewardsNum)
+		restoredAccount["currentOwner"] = stringOrNil(previousState.CurrentOwner)
+		restoredAccount["rootHash"] = bytesOrNil(previousState.RootHash)
+		restoredAccount["codeHash"] = bytesOrNil(previousState.CodeHash)
+		restoredAccount["codeMetadata"] = bytesOrNil(previousState.CodeMetadata)
+	}
+
+	return json.Marshal(restoredAccount)
+}
+
+func stringOrNil(value string) interface{} {
+	if value == "" {
+		return nil
+	}
+
+	return value
+}
+
+func floatOrNil(value float64) interface{} {
+	if value == 0 {
+		return nil
+	}
+
+	return value
+}
+
+func bytesOrNil(value []byte) interface{} {
+	if len(value) == 0 {
+		return nil
+	}
+
+	return value
+}
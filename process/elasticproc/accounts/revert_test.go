@@ -0,0 +1,111 @@
+package accounts
+
+import (
+	"testing"
+
+	"github.com/kalyan3104/k-chain-es-indexer-go/data"
+	"github.com/kalyan3104/k-chain-es-indexer-go/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccountsProcessor_PrepareAccountsHistoryQueryInCaseOfRevert(t *testing.T) {
+	t.Parallel()
+
+	ap, _ := NewAccountsProcessor(mock.NewPubkeyConverterMock(32), balanceConverter)
+
+	query := ap.PrepareAccountsHistoryQueryInCaseOfRevert(5000, 1)
+	require.Equal(t, `{"query": {"bool": {"filter": [{"term": {"timestamp": {"value": 5000}}}, {"term": {"shardID": {"value": 1}}}]}}}`, query.String())
+}
+
+func TestAccountsProcessor_PrepareLatestAccountsHistoryQuery(t *testing.T) {
+	t.Parallel()
+
+	ap, _ := NewAccountsProcessor(mock.NewPubkeyConverterMock(32), balanceConverter)
+
+	query, err := ap.PrepareLatestAccountsHistoryQuery([]string{"addr1", "addr2"}, 5000)
+	require.Nil(t, err)
+	require.Equal(t, `{"query": {"bool": {"filter": [{"terms": {"address": ["addr1","addr2"]}}, {"range": {"timestamp": {"lt": 5000}}}]}},`+
+		`"collapse": {"field": "address"}, "sort": [{"timestamp": {"order": "desc"}}], "size": 2}`, query.String())
+}
+
+func TestAccountsProcessor_SerializeAccountsRestore(t *testing.T) {
+	t.Parallel()
+
+	ap, _ := NewAccountsProcessor(mock.NewPubkeyConverterMock(32), balanceConverter)
+
+	nonce, zeroNonce := uint64(3), uint64(0)
+	latestEntries := map[string]*data.AccountBalanceHistory{
+		"addr1": {Address: "addr1", Timestamp: 4000, Balance: "1000", Nonce: &nonce},
+		"addr2": {Address: "addr2", Timestamp: 3000, Balance: "20", Nonce: &zeroNonce},
+		"addr3": {Address: "addr3", Timestamp: 2000, Balance: "1"},
+	}
+
+	revertedEntries := []*data.AccountBalanceHistory{
+		{Address: "addr1"}, {Address: "addr2"}, {Address: "addr3"}, {Address: "addr4", Created: true},
+	}
+
+	buffSlice := data.NewBufferSlice(data.DefaultMaxBulkSize)
+	err := ap.SerializeAccountsRestore(5000, revertedEntries, latestEntries, buffSlice, "accounts")
+	require.Nil(t, err)
+	require.Equal(t, 1, len(buffSlice.Buffers()))
+
+	expectedRes := `{ "update" : {"_index": "accounts", "_id" : "addr1" } }
+{"scripted_upsert": true, "script": {"source": "if ('create' == ctx.op) {ctx.op = 'noop';return;}if (!ctx._source.containsKey('timestamp') || ctx._source.timestamp != params.revertedTimestamp) {ctx.op = 'noop';return;}if (params.account == null) {ctx.op = 'delete';return;}params.account.forEach((key, value) -> {if (value == null) {ctx._source.remove(key);} else {ctx._source[key] = value;}});","lang": "painless","params": { "revertedTimestamp": 5000, "account": {"balance":"1000","balanceNum":1e-7,"nonce":3,"timestamp":4000} }},"upsert": {}}
+{ "update" : {"_index": "accounts", "_id" : "addr2" } }
+{"scripted_upsert": true, "script": {"source": "if ('create' == ctx.op) {ctx.op = 'noop';return;}if (!ctx._source.containsKey('timestamp') || ctx._source.timestamp != params.revertedTimestamp) {ctx.op = 'noop';return;}if (params.account == null) {ctx.op = 'delete';return;}params.account.forEach((key, value) -> {if (value == null) {ctx._source.remove(key);} else {ctx._source[key] = value;}});","lang": "painless","params": { "revertedTimestamp": 5000, "account": {"balance":"20","balanceNum":2e-9,"nonce":null,"timestamp":3000} }},"upsert": {}}
+{ "update" : {"_index": "accounts", "_id" : "addr3" } }
+{"scripted_upsert": true, "script": {"source": "if ('create' == ctx.op) {ctx.op = 'noop';return;}if (!ctx._source.containsKey('timestamp') || ctx._source.timestamp != params.revertedTimestamp) {ctx.op = 'noop';return;}if (params.account == null) {ctx.op = 'delete';return;}params.account.forEach((key, value) -> {if (value == null) {ctx._source.remove(key);} else {ctx._source[key] = value;}});","lang": "painless","params": { "revertedTimestamp": 5000, "account": {"balance":"1","balanceNum":1e-10,"timestamp":2000} }},"upsert": {}}
+{ "update" : {"_index": "accounts", "_id" : "addr4" } }
+{"scripted_upsert": true, "script": {"source": "if ('create' == ctx.op) {ctx.op = 'noop';return;}if (!ctx._source.containsKey('timestamp') || ctx._source.timestamp != params.revertedTimestamp) {ctx.op = 'noop';return;}if (params.account == null) {ctx.op = 'delete';return;}params.account.forEach((key, value) -> {if (value == null) {ctx._source.remove(key);} else {ctx._source[key] = value;}});","lang": "painless","params": { "revertedTimestamp": 5000, "account": null }},"upsert": {}}
+`
+	require.Equal(t, expectedRes, buffSlice.Buffers()[0].String())
+}
+
+func TestAccountsProcessor_SerializeAccountsRestoreShouldRestoreTheAccountState(t *testing.T) {
+	t.Parallel()
+
+	ap, _ := NewAccountsProcessor(mock.NewPubkeyConverterMock(32), balanceConverter)
+
+	nonce := uint64(7)
+	latestEntries := map[string]*data.AccountBalanceHistory{
+		"addr1": {
+			Address:   "addr1",
+			Timestamp: 4000,
+			Balance:   "1000",
+			Nonce:     &nonce,
+		},
+	}
+	revertedEntries := []*data.AccountBalanceHistory{
+		{
+			Address: "addr1",
+			PreviousState: &data.AccountHistoryState{
+				DeveloperRewards:    "30",
+				DeveloperRewardsNum: 3e-9,
+				CurrentOwner:        "owner",
+				CodeHash:            []byte("code"),
+			},
+		},
+	}
+
+	buffSlice := data.NewBufferSlice(data.DefaultMaxBulkSize)
+	err := ap.SerializeAccountsRestore(5000, revertedEntries, latestEntries, buffSlice, "accounts")
+	require.Nil(t, err)
+	require.Contains(t, buffSlice.Buffers()[0].String(), `"account": {"balance":"1000","balanceNum":1e-7,"codeHash":"Y29kZQ==","codeMetadata":null,`+
+		`"currentOwner":"owner","developerRewards":"30","developerRewardsNum":3e-9,"nonce":7,"rootHash":null,"timestamp":4000,"userName":null}`)
+}
+
+func TestAccountsProcessor_SerializeAccountsRestoreShouldSkipTheAccountsWithoutHistory(t *testing.T) {
+	t.Parallel()
+
+	ap, _ := NewAccountsProcessor(mock.NewPubkeyConverterMock(32), balanceConverter)
+
+	// the account was indexed before the history, so it is neither created by the block nor restorable
+	buffSlice := data.NewBufferSlice(data.DefaultMaxBulkSize)
+	err := ap.SerializeAccountsRestore(5000, []*data.AccountBalanceHistory{{Address: "addr1"}}, map[string]*data.AccountBalanceHistory{}, buffSlice, "accounts")
+	require.Nil(t, err)
+	require.Equal(t, 0, len(buffSlice.Buffers()))
+
+	err = ap.SerializeAccountsRestore(5000, []*data.AccountBalanceHistory{{Address: "addr1", Created: true}}, map[string]*data.AccountBalanceHistory{}, buffSlice, "accounts")
+	require.Nil(t, err)
+	require.Contains(t, buffSlice.Buffers()[0].String(), `"account": null`)
+}
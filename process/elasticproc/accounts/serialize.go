@@ -128,6 +128,9 @@ func prepareSerializedAccountInfo(
 		return nil, nil, err
 	}
 
+	// the timestamp of the block is the version of the document. External versioning cannot be used, as update
+	// requests accept only the internal one, so a write older than the indexed document is ignored by the script. A
+	// write with the same timestamp is applied, so that a retried block leaves the same document
 	meta := []byte(fmt.Sprintf(`{ "update" : {"_index": "%s", "_id" : "%s" } }%s`, index, converters.JsonEscape(id), "\n"))
 	codeToExecute := `
 		if ('create' == ctx.op) {
@@ -0,0 +1,80 @@
+package elasticproc
+
+import (
+	"context"
+	"encoding/json"
+
+	coreData "github.com/kalyan3104/k-chain-core-go/data"
+	"github.com/kalyan3104/k-chain-es-indexer-go/core/request"
+	"github.com/kalyan3104/k-chain-es-indexer-go/data"
+	elasticIndexer "github.com/kalyan3104/k-chain-es-indexer-go/process/dataindexer"
+	"github.com/kalyan3104/k-chain-es-indexer-go/process/elasticproc/epochstats"
+)
+
+// indexEpochActiveAccounts counts, when a shard starts a new epoch, the accounts of the shard that sent or received
+// transactions in the previous epoch and sets them in the statistics of that epoch. The accounts are counted with
+// cardinality aggregations over the transactions of the epoch, so they are not kept for every block. All the blocks of
+// the previous epoch were already indexed for the shard, and counting them again gives the same result
+func (ei *elasticProcessor) indexEpochActiveAccounts(header coreData.HeaderHandler, buffSlice *data.BufferSlice) error {
+	shouldCount := ei.isIndexEnabled(elasticIndexer.EpochStatsIndex) &&
+		ei.isIndexEnabled(elasticIndexer.TransactionsIndex) &&
+		header.IsStartOfEpochBlock() &&
+		header.GetEpoch() > 0
+	if !shouldCount {
+		return nil
+	}
+
+	shardID := header.GetShardID()
+	epoch := header.GetEpoch() - 1
+	startTimestamp, err := ei.getEpochStartTimestamp(shardID, epoch)
+	if err != nil || startTimestamp == 0 {
+		return err
+	}
+
+	ctxWithValue := context.WithValue(context.Background(), request.ContextKey, request.ExtendTopicWithShardID(request.GetTopic, shardID))
+	// the transactions of the last blocks of the epoch might not be visible yet for searches
+	err = ei.elasticClient.DoRefreshRequest(ctxWithValue, elasticIndexer.TransactionsIndex)
+	if err != nil {
+		return err
+	}
+
+	query := ei.epochStatsProc.PrepareActiveAccountsQuery(shardID, startTimestamp, header.GetTimeStamp())
+	response := &data.ResponseEpochActiveAccounts{}
+	err = ei.elasticClient.DoSearchRequest(ctxWithValue, elasticIndexer.TransactionsIndex, query.Bytes(), response)
+	if err != nil {
+		return err
+	}
+
+	activeSenders := response.Aggregations.Senders.Count.Value
+	activeReceivers := response.Aggregations.Receivers.Count.Value
+
+	return ei.epochStatsProc.SerializeEpochActiveAccounts(shardID, epoch, activeSenders, activeReceivers, buffSlice, elasticIndexer.EpochStatsIndex)
+}
+
+// getEpochStartTimestamp returns the timestamp of the first block of the shard indexed in the provided epoch, or 0 if
+// the statistics of the epoch do not have it
+func (ei *elasticProcessor) getEpochStartTimestamp(shardID uint32, epoch uint32) (uint64, error) {
+	id := epochstats.ComputeEpochStatsID(shardID, epoch)
+	response := &data.ResponseRawDocuments{}
+	ctxWithValue := context.WithValue(context.Background(), request.ContextKey, request.ExtendTopicWithShardID(request.GetTopic, shardID))
+	err := ei.elasticClient.DoMultiGet(ctxWithValue, []string{id}, elasticIndexer.EpochStatsIndex, true, response)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, doc := range response.Docs {
+		if !doc.Found {
+			continue
+		}
+
+		stats := &data.EpochStats{}
+		err = json.Unmarshal(doc.Source, stats)
+		if err != nil {
+			return 0, err
+		}
+
+		return uint64(stats.StartTimestamp), nil
+	}
+
+	return 0, nil
+}
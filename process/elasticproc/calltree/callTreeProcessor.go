@@ -0,0 +1,128 @@
+package calltree
+
+import (
+	"strconv"
+
+	"github.com/kalyan3104/k-chain-core-go/core"
+	coreData "github.com/kalyan3104/k-chain-core-go/data"
+	"github.com/kalyan3104/k-chain-core-go/data/outport"
+	"github.com/kalyan3104/k-chain-core-go/data/transaction"
+	"github.com/kalyan3104/k-chain-core-go/data/vm"
+	"github.com/kalyan3104/k-chain-es-indexer-go/data"
+)
+
+type callTreeProcessor struct{}
+
+// NewCallTreeProcessor will create a new instance of callTreeProcessor
+func NewCallTreeProcessor() *callTreeProcessor {
+	return &callTreeProcessor{}
+}
+
+// PrepareCallTrees will prepare the call tree nodes executed in the provided block, grouped by the hash of the original
+// transaction. Only the transactions that interact with smart contracts are added, and the smart contract results are
+// added on the shard where they are executed
+func (ctp *callTreeProcessor) PrepareCallTrees(
+	header coreData.HeaderHandler,
+	blockHash string,
+	txs []*data.Transaction,
+	scrs []*data.ScResult,
+	logs []*outport.LogData,
+) map[string]*data.CallTree {
+	shardID := header.GetShardID()
+	failedHashes := getFailedHashes(logs)
+	callTrees := make(map[string]*data.CallTree)
+
+	for _, tx := range txs {
+		if !tx.IsScCall && !tx.HasSCR {
+			continue
+		}
+
+		node := &data.CallTreeNode{
+			Hash:      tx.Hash,
+			ShardID:   shardID,
+			Sender:    tx.Sender,
+			Receiver:  tx.Receiver,
+			Value:     tx.Value,
+			Function:  tx.Function,
+			Status:    tx.Status,
+			BlockHash: blockHash,
+			Timestamp: tx.Timestamp,
+		}
+		addNode(callTrees, tx.Hash, node)
+	}
+
+	for _, scr := range scrs {
+		if scr.OriginalTxHash == "" || scr.ReceiverShard != shardID {
+			continue
+		}
+
+		status := transaction.TxStatusSuccess.String()
+		_, failed := failedHashes[scr.Hash]
+		if failed {
+			status = transaction.TxStatusFail.String()
+		}
+
+		node := &data.CallTreeNode{
+			Hash:          scr.Hash,
+			PrevTxHash:    scr.PrevTxHash,
+			ShardID:       shardID,
+			Sender:        scr.Sender,
+			Receiver:      scr.Receiver,
+			Value:         scr.Value,
+			Function:      scr.Function,
+			CallType:      getCallTypeName(scr.CallType),
+			ReturnMessage: scr.ReturnMessage,
+			Status:        status,
+			BlockHash:     blockHash,
+			Timestamp:     scr.Timestamp,
+		}
+		addNode(callTrees, scr.OriginalTxHash, node)
+	}
+
+	return callTrees
+}
+
+func addNode(callTrees map[string]*data.CallTree, originalTxHash string, node *data.CallTreeNode) {
+	callTree, found := callTrees[originalTxHash]
+	if !found {
+		callTree = &data.CallTree{
+			OriginalTxHash: originalTxHash,
+			Nodes:          make([]*data.CallTreeNode, 0),
+		}
+		callTrees[originalTxHash] = callTree
+	}
+
+	callTree.Nodes = append(callTree.Nodes, node)
+}
+
+func getFailedHashes(logs []*outport.LogData) map[string]struct{} {
+	failedHashes := make(map[string]struct{})
+	for _, logData := range logs {
+		if logData == nil || logData.Log == nil {
+			continue
+		}
+
+		for _, event := range logData.Log.Events {
+			if event == nil {
+				continue
+			}
+
+			identifier := string(event.Identifier)
+			if identifier == core.SignalErrorOperation || identifier == core.InternalVMErrorsOperation {
+				failedHashes[logData.TxHash] = struct{}{}
+				break
+			}
+		}
+	}
+
+	return failedHashes
+}
+
+func getCallTypeName(callType string) string {
+	callTypeValue, err := strconv.Atoi(callType)
+	if err != nil {
+		return callType
+	}
+
+	return vm.CallType(callTypeValue).ToString()
+}
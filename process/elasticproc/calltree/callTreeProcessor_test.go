@@ -0,0 +1,57 @@
+package calltree
+
+import (
+	"testing"
+
+	"github.com/kalyan3104/k-chain-core-go/core"
+	"github.com/kalyan3104/k-chain-core-go/data/block"
+	"github.com/kalyan3104/k-chain-core-go/data/outport"
+	"github.com/kalyan3104/k-chain-core-go/data/transaction"
+	"github.com/kalyan3104/k-chain-core-go/data/vm"
+	"github.com/kalyan3104/k-chain-es-indexer-go/data"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCallTreeProcessor_PrepareCallTrees(t *testing.T) {
+	t.Parallel()
+
+	ctp := NewCallTreeProcessor()
+
+	header := &block.Header{ShardID: 1, TimeStamp: 100}
+	txs := []*data.Transaction{
+		{Hash: "h1", Sender: "user", Receiver: "sc1", Value: "0", Function: "swap", IsScCall: true, Status: "success", Timestamp: 100},
+		{Hash: "h2", Sender: "user", Receiver: "user2", Value: "1", Status: "success", Timestamp: 100},
+	}
+	scrs := []*data.ScResult{
+		{Hash: "scr1", OriginalTxHash: "h1", PrevTxHash: "h1", Sender: "sc1", Receiver: "sc2", Value: "0", Function: "call", CallType: "1", ReceiverShard: 1, Timestamp: 100},
+		{Hash: "scr2", OriginalTxHash: "h1", PrevTxHash: "scr1", Sender: "sc2", Receiver: "sc1", Value: "0", CallType: "2", ReturnMessage: "error", ReceiverShard: 1, Timestamp: 100},
+		{Hash: "scr3", OriginalTxHash: "h1", PrevTxHash: "scr1", ReceiverShard: 0, Timestamp: 100},
+	}
+	logs := []*outport.LogData{
+		{
+			TxHash: "scr2",
+			Log: &transaction.Log{Events: []*transaction.Event{
+				{Identifier: []byte(core.SignalErrorOperation)},
+			}},
+		},
+	}
+
+	callTrees := ctp.PrepareCallTrees(header, "bh", txs, scrs, logs)
+	require.Len(t, callTrees, 1)
+
+	callTree := callTrees["h1"]
+	require.Equal(t, "h1", callTree.OriginalTxHash)
+	require.Equal(t, []*data.CallTreeNode{
+		{Hash: "h1", ShardID: 1, Sender: "user", Receiver: "sc1", Value: "0", Function: "swap", Status: "success", BlockHash: "bh", Timestamp: 100},
+		{Hash: "scr1", PrevTxHash: "h1", ShardID: 1, Sender: "sc1", Receiver: "sc2", Value: "0", Function: "call", CallType: vm.AsynchronousCallStr, Status: "success", BlockHash: "bh", Timestamp: 100},
+		{Hash: "scr2", PrevTxHash: "scr1", ShardID: 1, Sender: "sc2", Receiver: "sc1", Value: "0", CallType: vm.AsynchronousCallBackStr, ReturnMessage: "error", Status: "fail", BlockHash: "bh", Timestamp: 100},
+	}, callTree.Nodes)
+}
+
+func TestGetCallTypeName(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, vm.DirectCallStr, getCallTypeName("0"))
+	require.Equal(t, vm.DCDTTransferAndExecuteStr, getCallTypeName("3"))
+	require.Equal(t, "abc", getCallTypeName("abc"))
+}
@@ -0,0 +1,130 @@
+package calltree
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"github.com/kalyan3104/k-chain-es-indexer-go/data"
+	"github.com/kalyan3104/k-chain-es-indexer-go/process/elasticproc/converters"
+)
+
+// buildTreeCode rebuilds the nested tree from the stored nodes. When a node was executed on more than one shard, the
+// execution with a final status is preferred. The nodes whose parent was not indexed yet are attached to the root
+// until the parent arrives
+const buildTreeCode = `
+	Map chosen = new HashMap();
+	for (def node : ctx._source.nodes) {
+		def current = chosen.get(node.hash);
+		if (current == null || current.status == 'pending' || (node.status != 'pending' && node.timestamp > current.timestamp)) {
+			chosen.put(node.hash, node);
+		}
+	}
+	List ordered = new ArrayList(chosen.values());
+	ordered.sort((a, b) -> a.timestamp == b.timestamp ? a.hash.compareTo(b.hash) : (a.timestamp < b.timestamp ? -1 : 1));
+	Map treeNodes = new HashMap();
+	for (def node : ordered) {
+		Map treeNode = new HashMap(node);
+		treeNode.remove('blockHash');
+		treeNode.put('children', new ArrayList());
+		treeNodes.put(node.hash, treeNode);
+	}
+	def root = treeNodes.get(ctx._source.originalTxHash);
+	if (root == null) {
+		root = new HashMap();
+		root.put('hash', ctx._source.originalTxHash);
+		root.put('children', new ArrayList());
+	}
+	String status = root.containsKey('status') ? root.status : '';
+	long timestamp = 0;
+	for (def node : ordered) {
+		long nodeTimestamp = node.timestamp;
+		timestamp = Math.max(timestamp, nodeTimestamp);
+		if (node.hash == ctx._source.originalTxHash) {
+			continue;
+		}
+		def parent = treeNodes.get(node.prevTxHash);
+		if (parent == null || node.prevTxHash == node.hash) {
+			parent = root;
+		}
+		parent.children.add(treeNodes.get(node.hash));
+		if (node.status == 'fail') {
+			status = 'fail';
+		}
+	}
+	ctx._source.tree = root;
+	ctx._source.numCalls = ordered.size();
+	ctx._source.status = status;
+	ctx._source.timestamp = timestamp;
+`
+
+// SerializeCallTrees will serialize the provided call trees in a way that Elasticsearch expects a bulk request. The
+// nodes are merged with the ones indexed by the other shards and the nested tree is rebuilt
+func (ctp *callTreeProcessor) SerializeCallTrees(callTrees map[string]*data.CallTree, buffSlice *data.BufferSlice, index string) error {
+	codeToExecute := `
+		if ('create' == ctx.op) {
+			ctx._source = params.callTree;
+		} else {
+			for (def node : params.callTree.nodes) {
+				ctx._source.nodes.removeIf(n -> n.hash == node.hash && n.shardID == node.shardID);
+				ctx._source.nodes.add(node);
+			}
+		}
+` + buildTreeCode
+
+	for originalTxHash, callTree := range callTrees {
+		callTreeSerialized, err := json.Marshal(callTree)
+		if err != nil {
+			return err
+		}
+
+		serializedDataStr := fmt.Sprintf(`{"scripted_upsert": true, "script": {`+
+			`"source": "%s",`+
+			`"lang": "painless",`+
+			`"params": { "callTree": %s }},`+
+			`"upsert": {}}`,
+			converters.FormatPainlessSource(codeToExecute), string(callTreeSerialized),
+		)
+
+		meta := []byte(fmt.Sprintf(`{ "update" : { "_index":"%s", "_id" : "%s" } }%s`, index, converters.JsonEscape(originalTxHash), "\n"))
+		err = buffSlice.PutData(meta, []byte(serializedDataStr))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// PrepareCallTreesQueryInCaseOfRevert will prepare the query that removes the nodes of the reverted block and rebuilds
+// the trees. The documents that remain without nodes are deleted
+func (ctp *callTreeProcessor) PrepareCallTreesQueryInCaseOfRevert(blockHash string) *bytes.Buffer {
+	codeToExecute := `
+		ctx._source.nodes.removeIf(n -> n.blockHash == params.blockHash);
+		if (ctx._source.nodes.isEmpty()) {
+			ctx.op = 'delete';
+			return;
+		}
+` + buildTreeCode
+
+	query := fmt.Sprintf(`
+	{
+	  "query": {
+		"nested": {
+		  "path": "nodes",
+		  "query": {
+			"match": {
+			  "nodes.blockHash": "%s"
+			}
+		  }
+		}
+	  },
+	  "script": {
+		"source": "%s",
+		"lang": "painless",
+		"params": {"blockHash": "%s"}
+	  }
+	}`, converters.JsonEscape(blockHash), converters.FormatPainlessSource(codeToExecute), converters.JsonEscape(blockHash))
+
+	return bytes.NewBuffer([]byte(query))
+}
@@ -0,0 +1,45 @@
+package calltree
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/kalyan3104/k-chain-es-indexer-go/data"
+	"github.com/kalyan3104/k-chain-es-indexer-go/process/elasticproc/converters"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCallTreeProcessor_SerializeCallTrees(t *testing.T) {
+	t.Parallel()
+
+	ctp := NewCallTreeProcessor()
+
+	callTrees := map[string]*data.CallTree{
+		"h1": {
+			OriginalTxHash: "h1",
+			Nodes: []*data.CallTreeNode{
+				{Hash: "scr1", PrevTxHash: "h1", ShardID: 1, Sender: "sc1", Receiver: "sc2", Value: "0", Status: "success", BlockHash: "bh", Timestamp: 100},
+			},
+		},
+	}
+
+	buffSlice := data.NewBufferSlice(data.DefaultMaxBulkSize)
+	err := ctp.SerializeCallTrees(callTrees, buffSlice, "calltree")
+	require.Nil(t, err)
+
+	expectedRes := `{ "update" : { "_index":"calltree", "_id" : "h1" } }
+{"scripted_upsert": true, "script": {"source": "if ('create' == ctx.op) {ctx._source = params.callTree;} else {for (def node : params.callTree.nodes) {ctx._source.nodes.removeIf(n -> n.hash == node.hash && n.shardID == node.shardID);ctx._source.nodes.add(node);}}` + converters.FormatPainlessSource(buildTreeCode) + `","lang": "painless","params": { "callTree": {"originalTxHash":"h1","nodes":[{"hash":"scr1","prevTxHash":"h1","shardID":1,"sender":"sc1","receiver":"sc2","value":"0","status":"success","blockHash":"bh","timestamp":100}],"numCalls":0,"timestamp":0} }},"upsert": {}}
+`
+	require.Equal(t, expectedRes, buffSlice.Buffers()[0].String())
+}
+
+func TestCallTreeProcessor_PrepareCallTreesQueryInCaseOfRevert(t *testing.T) {
+	t.Parallel()
+
+	ctp := NewCallTreeProcessor()
+
+	query := ctp.PrepareCallTreesQueryInCaseOfRevert("bh").String()
+	require.True(t, strings.Contains(query, `"nodes.blockHash": "bh"`))
+	require.True(t, strings.Contains(query, `"source": "ctx._source.nodes.removeIf(n -> n.blockHash == params.blockHash);if (ctx._source.nodes.isEmpty()) {ctx.op = 'delete';return;}`))
+	require.True(t, strings.Contains(query, `"params": {"blockHash": "bh"}`))
+}
@@ -39,6 +39,27 @@ func checkArguments(arguments *ArgElasticProcessor) error {
 	if check.IfNilReflect(arguments.OperationsProc) {
 		return elasticIndexer.ErrNilOperationsHandler
 	}
+	if check.IfNilReflect(arguments.EpochStatsProc) {
+		return elasticIndexer.ErrNilEpochStatsHandler
+	}
+	if check.IfNilReflect(arguments.ContractStatsProc) {
+		return elasticIndexer.ErrNilContractStatsHandler
+	}
+	if check.IfNilReflect(arguments.ConsensusStatsProc) {
+		return elasticIndexer.ErrNilConsensusStatsHandler
+	}
+	if check.IfNilReflect(arguments.TxLifecycleProc) {
+		return elasticIndexer.ErrNilTxLifecycleHandler
+	}
+	if check.IfNilReflect(arguments.CallTreeProc) {
+		return elasticIndexer.ErrNilCallTreeHandler
+	}
+	if check.IfNilReflect(arguments.JournalProc) {
+		return elasticIndexer.ErrNilJournalHandler
+	}
+	if check.IfNilReflect(arguments.StatsContributionsProc) {
+		return elasticIndexer.ErrNilStatsContributionsHandler
+	}
 
 	return nil
 }
@@ -0,0 +1,209 @@
+package consensusstats
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	coreData "github.com/kalyan3104/k-chain-core-go/data"
+	"github.com/kalyan3104/k-chain-core-go/data/outport"
+	"github.com/kalyan3104/k-chain-es-indexer-go/data"
+	logger "github.com/kalyan3104/k-chain-logger-go"
+)
+
+const (
+	epochBucket  = "epoch"
+	dayBucket    = "day"
+	secondsInDay = 24 * 60 * 60
+
+	maxCachedValidatorsLists = 100
+)
+
+var log = logger.GetOrCreate("indexer/process/consensusstats")
+
+type consensusStatsProcessor struct {
+	validatorsCache map[string][]string
+	mutex           sync.Mutex
+}
+
+// NewConsensusStatsProcessor will create a new instance of consensusStatsProcessor
+func NewConsensusStatsProcessor() *consensusStatsProcessor {
+	return &consensusStatsProcessor{
+		validatorsCache: make(map[string][]string),
+	}
+}
+
+// ComputeValidatorsListID will return the id of the document from the validators index that holds the public keys of
+// the provided shard and epoch
+func ComputeValidatorsListID(shardID uint32, epoch uint32) string {
+	return fmt.Sprintf("%d_%d", shardID, epoch)
+}
+
+// ComputeRoundContributionKey will return the key of the contribution of a round to the consensus statistics
+func ComputeRoundContributionKey(shardID uint32, round uint64) string {
+	return fmt.Sprintf("round_%d_%d", shardID, round)
+}
+
+// ResolveValidatorsPubKeys will return the validators public keys lists for the provided ids. The lists that are not
+// cached are fetched with the provided handler
+func (csp *consensusStatsProcessor) ResolveValidatorsPubKeys(
+	ids []string,
+	fetchHandler func(ids []string) (map[string][]string, error),
+) (map[string][]string, error) {
+	csp.mutex.Lock()
+	defer csp.mutex.Unlock()
+
+	validatorsLists := make(map[string][]string, len(ids))
+	missingIDs := make([]string, 0)
+	for _, id := range ids {
+		publicKeys, found := csp.validatorsCache[id]
+		if found {
+			validatorsLists[id] = publicKeys
+			continue
+		}
+		missingIDs = append(missingIDs, id)
+	}
+	if len(missingIDs) == 0 {
+		return validatorsLists, nil
+	}
+
+	fetchedLists, err := fetchHandler(missingIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(csp.validatorsCache)+len(fetchedLists) > maxCachedValidatorsLists {
+		csp.validatorsCache = make(map[string][]string)
+	}
+	for id, publicKeys := range fetchedLists {
+		csp.validatorsCache[id] = publicKeys
+		validatorsLists[id] = publicKeys
+	}
+
+	return validatorsLists, nil
+}
+
+// PrepareConsensusStatsFromRounds will compute the proposed blocks and the missed proposals of the leaders of the
+// provided rounds. The leader of a round is the first member of the consensus group
+func (csp *consensusStatsProcessor) PrepareConsensusStatsFromRounds(roundsInfo []*outport.RoundInfo, validatorsLists map[string][]string) map[string]*data.ConsensusStats {
+	statsMap := make(map[string]*data.ConsensusStats)
+
+	for _, roundInfo := range roundsInfo {
+		if len(roundInfo.SignersIndexes) == 0 {
+			continue
+		}
+
+		leader, ok := getPublicKey(validatorsLists, roundInfo.ShardId, roundInfo.Epoch, roundInfo.SignersIndexes[0])
+		if !ok {
+			continue
+		}
+
+		timestamp := time.Duration(roundInfo.Timestamp)
+		for _, stats := range getOrCreateBuckets(statsMap, leader, roundInfo.ShardId, roundInfo.Epoch, timestamp) {
+			if roundInfo.BlockWasProposed {
+				stats.ProposedBlocks++
+			} else {
+				stats.MissedProposals++
+			}
+		}
+	}
+
+	return statsMap
+}
+
+// PrepareConsensusStatsFromBlock will compute the signed blocks and the missed signatures of the consensus group
+// members of the provided block, based on the public keys bitmap of the header
+func (csp *consensusStatsProcessor) PrepareConsensusStatsFromBlock(
+	header coreData.HeaderHandler,
+	signersIndexes []uint64,
+	validatorsLists map[string][]string,
+) map[string]*data.ConsensusStats {
+	statsMap := make(map[string]*data.ConsensusStats)
+	pubKeysBitmap := header.GetPubKeysBitmap()
+	timestamp := time.Duration(header.GetTimeStamp())
+
+	for idx, validatorIndex := range signersIndexes {
+		publicKey, ok := getPublicKey(validatorsLists, header.GetShardID(), header.GetEpoch(), validatorIndex)
+		if !ok {
+			continue
+		}
+
+		hasSigned := isBitSet(pubKeysBitmap, idx)
+		for _, stats := range getOrCreateBuckets(statsMap, publicKey, header.GetShardID(), header.GetEpoch(), timestamp) {
+			if hasSigned {
+				stats.SignedBlocks++
+			} else {
+				stats.MissedSignatures++
+			}
+		}
+	}
+
+	return statsMap
+}
+
+func getPublicKey(validatorsLists map[string][]string, shardID uint32, epoch uint32, index uint64) (string, bool) {
+	publicKeys, found := validatorsLists[ComputeValidatorsListID(shardID, epoch)]
+	if !found {
+		log.Debug("consensusStatsProcessor: validators list not found", "shard", shardID, "epoch", epoch)
+		return "", false
+	}
+	if index >= uint64(len(publicKeys)) {
+		log.Debug("consensusStatsProcessor: validator index out of range", "shard", shardID, "epoch", epoch, "index", index)
+		return "", false
+	}
+
+	return publicKeys[index], true
+}
+
+func getOrCreateBuckets(
+	statsMap map[string]*data.ConsensusStats,
+	publicKey string,
+	shardID uint32,
+	epoch uint32,
+	timestamp time.Duration,
+) []*data.ConsensusStats {
+	day := timestamp - timestamp%secondsInDay
+
+	epochID := fmt.Sprintf("%s_%s_%d", publicKey, epochBucket, epoch)
+	epochStats, found := statsMap[epochID]
+	if !found {
+		epochValue := epoch
+		epochStats = &data.ConsensusStats{
+			PublicKey: publicKey,
+			ShardID:   shardID,
+			Bucket:    epochBucket,
+			Epoch:     &epochValue,
+		}
+		statsMap[epochID] = epochStats
+	}
+
+	dayID := fmt.Sprintf("%s_%s_%d", publicKey, dayBucket, day)
+	dayStats, found := statsMap[dayID]
+	if !found {
+		dayStats = &data.ConsensusStats{
+			PublicKey: publicKey,
+			ShardID:   shardID,
+			Bucket:    dayBucket,
+			Day:       day,
+		}
+		statsMap[dayID] = dayStats
+	}
+
+	buckets := []*data.ConsensusStats{epochStats, dayStats}
+	for _, stats := range buckets {
+		if timestamp > stats.Timestamp {
+			stats.Timestamp = timestamp
+		}
+	}
+
+	return buckets
+}
+
+func isBitSet(bitmap []byte, index int) bool {
+	byteIndex := index / 8
+	if byteIndex >= len(bitmap) {
+		return false
+	}
+
+	return bitmap[byteIndex]&(1<<uint8(index%8)) != 0
+}
@@ -0,0 +1,119 @@
+package consensusstats
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/kalyan3104/k-chain-core-go/data/block"
+	"github.com/kalyan3104/k-chain-core-go/data/outport"
+	"github.com/stretchr/testify/require"
+)
+
+func createValidatorsLists() map[string][]string {
+	return map[string][]string{
+		"0_1": {"pk0", "pk1", "pk2"},
+	}
+}
+
+func TestComputeValidatorsListID(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, "1_20", ComputeValidatorsListID(1, 20))
+}
+
+func TestConsensusStatsProcessor_ResolveValidatorsPubKeysShouldUseCache(t *testing.T) {
+	t.Parallel()
+
+	csp := NewConsensusStatsProcessor()
+
+	numCalls := 0
+	fetchHandler := func(ids []string) (map[string][]string, error) {
+		numCalls++
+		return map[string][]string{
+			"0_1": {"pk0"},
+		}, nil
+	}
+
+	res, err := csp.ResolveValidatorsPubKeys([]string{"0_1"}, fetchHandler)
+	require.Nil(t, err)
+	require.Equal(t, map[string][]string{"0_1": {"pk0"}}, res)
+
+	res, err = csp.ResolveValidatorsPubKeys([]string{"0_1"}, fetchHandler)
+	require.Nil(t, err)
+	require.Equal(t, map[string][]string{"0_1": {"pk0"}}, res)
+	require.Equal(t, 1, numCalls)
+}
+
+func TestConsensusStatsProcessor_ResolveValidatorsPubKeysShouldErr(t *testing.T) {
+	t.Parallel()
+
+	csp := NewConsensusStatsProcessor()
+
+	localErr := errors.New("local error")
+	res, err := csp.ResolveValidatorsPubKeys([]string{"0_1"}, func(ids []string) (map[string][]string, error) {
+		return nil, localErr
+	})
+	require.Equal(t, localErr, err)
+	require.Nil(t, res)
+}
+
+func TestConsensusStatsProcessor_PrepareConsensusStatsFromRounds(t *testing.T) {
+	t.Parallel()
+
+	csp := NewConsensusStatsProcessor()
+
+	roundsInfo := []*outport.RoundInfo{
+		{Round: 1, SignersIndexes: []uint64{1, 0, 2}, BlockWasProposed: true, ShardId: 0, Epoch: 1, Timestamp: 86401},
+		{Round: 2, SignersIndexes: []uint64{1, 2, 0}, BlockWasProposed: false, ShardId: 0, Epoch: 1, Timestamp: 86407},
+		{Round: 3, SignersIndexes: []uint64{2}, BlockWasProposed: true, ShardId: 0, Epoch: 2, Timestamp: 86413},
+		{Round: 4, SignersIndexes: []uint64{}, BlockWasProposed: true, ShardId: 0, Epoch: 1, Timestamp: 86419},
+	}
+
+	statsMap := csp.PrepareConsensusStatsFromRounds(roundsInfo, createValidatorsLists())
+	require.Len(t, statsMap, 2)
+
+	epochStats := statsMap["pk1_epoch_1"]
+	require.Equal(t, uint64(1), epochStats.ProposedBlocks)
+	require.Equal(t, uint64(1), epochStats.MissedProposals)
+	require.Equal(t, uint32(1), *epochStats.Epoch)
+	require.Equal(t, epochBucket, epochStats.Bucket)
+
+	dayStats := statsMap["pk1_day_86400"]
+	require.Equal(t, uint64(1), dayStats.ProposedBlocks)
+	require.Equal(t, uint64(1), dayStats.MissedProposals)
+	require.Nil(t, dayStats.Epoch)
+	require.Equal(t, dayBucket, dayStats.Bucket)
+	require.Equal(t, 86407, int(dayStats.Timestamp))
+}
+
+func TestConsensusStatsProcessor_PrepareConsensusStatsFromBlock(t *testing.T) {
+	t.Parallel()
+
+	csp := NewConsensusStatsProcessor()
+
+	header := &block.Header{
+		ShardID:       0,
+		Epoch:         1,
+		TimeStamp:     100,
+		PubKeysBitmap: []byte{5},
+	}
+
+	statsMap := csp.PrepareConsensusStatsFromBlock(header, []uint64{2, 0, 1, 5}, createValidatorsLists())
+	require.Len(t, statsMap, 6)
+
+	require.Equal(t, uint64(1), statsMap["pk2_epoch_1"].SignedBlocks)
+	require.Equal(t, uint64(0), statsMap["pk2_epoch_1"].MissedSignatures)
+	require.Equal(t, uint64(1), statsMap["pk0_epoch_1"].MissedSignatures)
+	require.Equal(t, uint64(1), statsMap["pk1_day_0"].SignedBlocks)
+	require.Equal(t, uint64(0), statsMap["pk1_day_0"].MissedSignatures)
+}
+
+func TestIsBitSet(t *testing.T) {
+	t.Parallel()
+
+	bitmap := []byte{1, 128}
+	require.True(t, isBitSet(bitmap, 0))
+	require.False(t, isBitSet(bitmap, 1))
+	require.True(t, isBitSet(bitmap, 15))
+	require.False(t, isBitSet(bitmap, 16))
+}
@@ -0,0 +1,75 @@
+package consensusstats
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/kalyan3104/k-chain-es-indexer-go/data"
+	"github.com/kalyan3104/k-chain-es-indexer-go/process/elasticproc/converters"
+)
+
+// SerializeConsensusStats will serialize the provided consensus statistics in a way that Elasticsearch expects a bulk request
+func (csp *consensusStatsProcessor) SerializeConsensusStats(statsMap map[string]*data.ConsensusStats, buffSlice *data.BufferSlice, index string) error {
+	codeToExecute := `
+		if ('create' == ctx.op) {
+			ctx._source = params.stats
+		} else {
+			ctx._source.proposedBlocks += params.stats.proposedBlocks;
+			ctx._source.missedProposals += params.stats.missedProposals;
+			ctx._source.signedBlocks += params.stats.signedBlocks;
+			ctx._source.missedSignatures += params.stats.missedSignatures;
+			ctx._source.shardID = params.stats.shardID;
+			if (params.stats.timestamp > ctx._source.timestamp) {
+				ctx._source.timestamp = params.stats.timestamp;
+			}
+		}
+`
+
+	return serializeStatsWithScript(statsMap, codeToExecute, buffSlice, index)
+}
+
+// SerializeConsensusStatsRevert will serialize the requests that subtract the provided statistics. The documents that
+// remain without any proposal or signature are deleted
+func (csp *consensusStatsProcessor) SerializeConsensusStatsRevert(statsMap map[string]*data.ConsensusStats, buffSlice *data.BufferSlice, index string) error {
+	// the previous value of the timestamp cannot be computed, so it is kept as it is
+	codeToExecute := `
+		if ('create' == ctx.op) {
+			ctx.op = 'noop'
+		} else {
+			ctx._source.proposedBlocks -= params.stats.proposedBlocks;
+			ctx._source.missedProposals -= params.stats.missedProposals;
+			ctx._source.signedBlocks -= params.stats.signedBlocks;
+			ctx._source.missedSignatures -= params.stats.missedSignatures;
+			if (ctx._source.proposedBlocks <= 0 && ctx._source.missedProposals <= 0 && ctx._source.signedBlocks <= 0 && ctx._source.missedSignatures <= 0) {
+				ctx.op = 'delete';
+			}
+		}
+`
+
+	return serializeStatsWithScript(statsMap, codeToExecute, buffSlice, index)
+}
+
+func serializeStatsWithScript(statsMap map[string]*data.ConsensusStats, codeToExecute string, buffSlice *data.BufferSlice, index string) error {
+	for id, stats := range statsMap {
+		statsSerialized, err := json.Marshal(stats)
+		if err != nil {
+			return err
+		}
+
+		serializedDataStr := fmt.Sprintf(`{"scripted_upsert": true, "script": {`+
+			`"source": "%s",`+
+			`"lang": "painless",`+
+			`"params": { "stats": %s }},`+
+			`"upsert": {}}`,
+			converters.FormatPainlessSource(codeToExecute), string(statsSerialized),
+		)
+
+		meta := []byte(fmt.Sprintf(`{ "update" : { "_index":"%s", "_id" : "%s" } }%s`, index, converters.JsonEscape(id), "\n"))
+		err = buffSlice.PutData(meta, []byte(serializedDataStr))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
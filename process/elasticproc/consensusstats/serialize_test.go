@@ -0,0 +1,63 @@
+package consensusstats
+
+import (
+	"testing"
+
+	"github.com/kalyan3104/k-chain-es-indexer-go/data"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConsensusStatsProcessor_SerializeConsensusStats(t *testing.T) {
+	t.Parallel()
+
+	csp := NewConsensusStatsProcessor()
+
+	epoch := uint32(1)
+	statsMap := map[string]*data.ConsensusStats{
+		"pk_epoch_1": {
+			PublicKey:        "pk",
+			ShardID:          0,
+			Bucket:           epochBucket,
+			Epoch:            &epoch,
+			ProposedBlocks:   1,
+			SignedBlocks:     2,
+			MissedSignatures: 1,
+			Timestamp:        100,
+		},
+	}
+
+	buffSlice := data.NewBufferSlice(data.DefaultMaxBulkSize)
+	err := csp.SerializeConsensusStats(statsMap, buffSlice, "consensusstats")
+	require.Nil(t, err)
+
+	expectedRes := `{ "update" : { "_index":"consensusstats", "_id" : "pk_epoch_1" } }
+{"scripted_upsert": true, "script": {"source": "if ('create' == ctx.op) {ctx._source = params.stats} else {ctx._source.proposedBlocks += params.stats.proposedBlocks;ctx._source.missedProposals += params.stats.missedProposals;ctx._source.signedBlocks += params.stats.signedBlocks;ctx._source.missedSignatures += params.stats.missedSignatures;ctx._source.shardID = params.stats.shardID;if (params.stats.timestamp > ctx._source.timestamp) {ctx._source.timestamp = params.stats.timestamp;}}","lang": "painless","params": { "stats": {"publicKey":"pk","shardID":0,"bucket":"epoch","epoch":1,"proposedBlocks":1,"missedProposals":0,"signedBlocks":2,"missedSignatures":1,"timestamp":100} }},"upsert": {}}
+`
+	require.Equal(t, expectedRes, buffSlice.Buffers()[0].String())
+}
+
+func TestConsensusStatsProcessor_SerializeConsensusStatsRevert(t *testing.T) {
+	t.Parallel()
+
+	csp := NewConsensusStatsProcessor()
+
+	statsMap := map[string]*data.ConsensusStats{
+		"pk_day_86400": {
+			PublicKey:    "pk",
+			ShardID:      0,
+			Bucket:       dayBucket,
+			Day:          86400,
+			SignedBlocks: 1,
+			Timestamp:    86500,
+		},
+	}
+
+	buffSlice := data.NewBufferSlice(data.DefaultMaxBulkSize)
+	err := csp.SerializeConsensusStatsRevert(statsMap, buffSlice, "consensusstats")
+	require.Nil(t, err)
+
+	expectedRes := `{ "update" : { "_index":"consensusstats", "_id" : "pk_day_86400" } }
+{"scripted_upsert": true, "script": {"source": "if ('create' == ctx.op) {ctx.op = 'noop'} else {ctx._source.proposedBlocks -= params.stats.proposedBlocks;ctx._source.missedProposals -= params.stats.missedProposals;ctx._source.signedBlocks -= params.stats.signedBlocks;ctx._source.missedSignatures -= params.stats.missedSignatures;if (ctx._source.proposedBlocks <= 0 && ctx._source.missedProposals <= 0 && ctx._source.signedBlocks <= 0 && ctx._source.missedSignatures <= 0) {ctx.op = 'delete';}}","lang": "painless","params": { "stats": {"publicKey":"pk","shardID":0,"bucket":"day","day":86400,"proposedBlocks":0,"missedProposals":0,"signedBlocks":1,"missedSignatures":0,"timestamp":86500} }},"upsert": {}}
+`
+	require.Equal(t, expectedRes, buffSlice.Buffers()[0].String())
+}
@@ -0,0 +1,111 @@
+package elasticproc
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	coreData "github.com/kalyan3104/k-chain-core-go/data"
+	"github.com/kalyan3104/k-chain-es-indexer-go/core/request"
+	"github.com/kalyan3104/k-chain-es-indexer-go/data"
+	elasticIndexer "github.com/kalyan3104/k-chain-es-indexer-go/process/dataindexer"
+	"github.com/kalyan3104/k-chain-es-indexer-go/process/elasticproc/contractstats"
+)
+
+// indexContractsUniqueCallers counts, when a shard indexes its first block of a day, the callers of every smart contract
+// of the shard called in the previous day and sets them in the statistics of that day. The callers are counted with
+// cardinality aggregations over the transactions and the smart contract results of the day, so they are not kept for
+// every contract. The previous day is counted again once after a restart, which gives the same result
+func (ei *elasticProcessor) indexContractsUniqueCallers(header coreData.HeaderHandler, buffSlice *data.BufferSlice) error {
+	previousDay, shouldCount := ei.getCallersDayToCount(header)
+	if !shouldCount || ei.isCallersDayCounted(header.GetShardID(), previousDay) {
+		return nil
+	}
+
+	indices := strings.Join(ei.getContractCallsIndices(), ",")
+	uniqueCallers, err := ei.getContractsUniqueCallers(header.GetShardID(), previousDay, indices)
+	if err != nil {
+		return err
+	}
+
+	return ei.contractStatsProc.SerializeContractsUniqueCallers(uniqueCallers, previousDay, buffSlice, elasticIndexer.ContractStatsIndex)
+}
+
+// getCallersDayToCount returns the day before the day of the provided header, if the unique callers are counted
+func (ei *elasticProcessor) getCallersDayToCount(header coreData.HeaderHandler) (time.Duration, bool) {
+	shouldCount := ei.isIndexEnabled(elasticIndexer.ContractStatsIndex) && len(ei.getContractCallsIndices()) > 0
+	day := contractstats.ComputeDay(time.Duration(header.GetTimeStamp()))
+	if !shouldCount || day == 0 {
+		return 0, false
+	}
+
+	return contractstats.ComputeDay(day - 1), true
+}
+
+func (ei *elasticProcessor) getContractCallsIndices() []string {
+	indices := make([]string, 0, 2)
+	if ei.isIndexEnabled(elasticIndexer.TransactionsIndex) {
+		indices = append(indices, elasticIndexer.TransactionsIndex)
+	}
+	if ei.isIndexEnabled(elasticIndexer.ScResultsIndex) {
+		indices = append(indices, elasticIndexer.ScResultsIndex)
+	}
+
+	return indices
+}
+
+func (ei *elasticProcessor) getContractsUniqueCallers(shardID uint32, day time.Duration, index string) (map[string]uint64, error) {
+	ctxWithValue := context.WithValue(context.Background(), request.ContextKey, request.ExtendTopicWithShardID(request.GetTopic, shardID))
+	// the calls of the last blocks of the day might not be visible yet for searches
+	err := ei.elasticClient.DoRefreshRequest(ctxWithValue, index)
+	if err != nil {
+		return nil, err
+	}
+
+	uniqueCallers := make(map[string]uint64)
+	afterAddress := ""
+	for {
+		query := ei.contractStatsProc.PrepareUniqueCallersQuery(shardID, day, afterAddress)
+		response := &data.ResponseContractsUniqueCallers{}
+		err = ei.elasticClient.DoSearchRequest(ctxWithValue, index, query.Bytes(), response)
+		if err != nil {
+			return nil, err
+		}
+
+		contracts := response.Aggregations.Contracts
+		for _, bucket := range contracts.Buckets {
+			uniqueCallers[bucket.Key.Address] = bucket.Callers.Value
+		}
+
+		if len(contracts.Buckets) == 0 || contracts.AfterKey == nil {
+			return uniqueCallers, nil
+		}
+		afterAddress = contracts.AfterKey.Address
+	}
+}
+
+func (ei *elasticProcessor) isCallersDayCounted(shardID uint32, day time.Duration) bool {
+	ei.mutCountedCallersDays.Lock()
+	defer ei.mutCountedCallersDays.Unlock()
+
+	countedDay, found := ei.countedCallersDays[shardID]
+
+	return found && countedDay >= day
+}
+
+// markContractsCallersDayAsCounted is called after the header was written, so the callers of the previous day are
+// counted again if the request fails
+func (ei *elasticProcessor) markContractsCallersDayAsCounted(header coreData.HeaderHandler) {
+	previousDay, shouldCount := ei.getCallersDayToCount(header)
+	if !shouldCount {
+		return
+	}
+
+	ei.mutCountedCallersDays.Lock()
+	defer ei.mutCountedCallersDays.Unlock()
+
+	countedDay, found := ei.countedCallersDays[header.GetShardID()]
+	if !found || countedDay < previousDay {
+		ei.countedCallersDays[header.GetShardID()] = previousDay
+	}
+}
@@ -0,0 +1,110 @@
+package elasticproc
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	dataBlock "github.com/kalyan3104/k-chain-core-go/data/block"
+	"github.com/kalyan3104/k-chain-es-indexer-go/mock"
+	"github.com/kalyan3104/k-chain-es-indexer-go/process/dataindexer"
+	"github.com/stretchr/testify/require"
+)
+
+const secondsInDay = 24 * 60 * 60
+
+func TestElasticProcessor_SaveHeaderOfNewDayShouldCountTheUniqueCallersOfThePreviousDay(t *testing.T) {
+	t.Parallel()
+
+	refreshedIndex := ""
+	searchedIndex := ""
+	searchQueries := make([]string, 0)
+	bulkBody := ""
+	dbWriter := &mock.DatabaseWriterStub{
+		DoRefreshRequestCalled: func(index string) error {
+			refreshedIndex = index
+			return nil
+		},
+		DoSearchRequestCalled: func(index string, body []byte, resBody interface{}) error {
+			searchedIndex = index
+			searchQueries = append(searchQueries, string(body))
+			if len(searchQueries) > 1 {
+				return json.Unmarshal([]byte(`{"aggregations":{"contracts":{"buckets":[]}}}`), resBody)
+			}
+
+			return json.Unmarshal([]byte(`{"aggregations":{"contracts":{"after_key":{"address":"contract"},`+
+				`"buckets":[{"key":{"address":"contract"},"doc_count":10,"callers":{"value":3}}]}}}`), resBody)
+		},
+		DoBulkRequestCalled: func(buff *bytes.Buffer, index string) error {
+			bulkBody += buff.String()
+			return nil
+		},
+	}
+
+	elasticProc := newElasticsearchProcessor(dbWriter, createMockElasticProcessorArgs())
+	elasticProc.enabledIndexes = map[string]struct{}{
+		dataindexer.ContractStatsIndex: {},
+		dataindexer.TransactionsIndex:  {},
+		dataindexer.ScResultsIndex:     {},
+	}
+
+	outportBlock := createEmptyOutportBlockWithHeader()
+	outportBlock.ShardID = 1
+	outportBlock.Header = &dataBlock.Header{ShardID: 1, TimeStamp: 3*secondsInDay + 6}
+	outportBlock.BlockData.HeaderHash = []byte("hash")
+
+	err := elasticProc.SaveHeader(outportBlock)
+	require.Nil(t, err)
+	require.Equal(t, "transactions,scresults", refreshedIndex)
+	require.Equal(t, "transactions,scresults", searchedIndex)
+	require.Len(t, searchQueries, 2)
+	require.Contains(t, searchQueries[0], `{"range": {"timestamp": {"gte": 172800, "lt": 259200}}}`)
+	require.Contains(t, searchQueries[1], `"after": {"address": "contract"}`)
+	require.Contains(t, bulkBody, `{ "update" : { "_index":"contractstats", "_id" : "contract_172800" } }`)
+	require.Contains(t, bulkBody, `"params": { "uniqueCallers": 3 }`)
+
+	// the unique callers are counted only once, when the shard starts the next day
+	searchQueries = searchQueries[:0]
+	outportBlock.Header = &dataBlock.Header{ShardID: 1, TimeStamp: 3*secondsInDay + 12}
+	err = elasticProc.SaveHeader(outportBlock)
+	require.Nil(t, err)
+	require.Empty(t, searchQueries)
+}
+
+func TestElasticProcessor_SaveHeaderShouldCountTheUniqueCallersAgainIfTheBulkRequestFails(t *testing.T) {
+	t.Parallel()
+
+	numSearches := 0
+	expectedErr := errors.New("expected error")
+	bulkErr := expectedErr
+	dbWriter := &mock.DatabaseWriterStub{
+		DoSearchRequestCalled: func(index string, body []byte, resBody interface{}) error {
+			numSearches++
+			return json.Unmarshal([]byte(`{"aggregations":{"contracts":{"buckets":[]}}}`), resBody)
+		},
+		DoBulkRequestCalled: func(buff *bytes.Buffer, index string) error {
+			return bulkErr
+		},
+	}
+
+	elasticProc := newElasticsearchProcessor(dbWriter, createMockElasticProcessorArgs())
+	elasticProc.enabledIndexes = map[string]struct{}{
+		dataindexer.ContractStatsIndex: {},
+		dataindexer.TransactionsIndex:  {},
+		dataindexer.BlockIndex:         {},
+	}
+
+	outportBlock := createEmptyOutportBlockWithHeader()
+	outportBlock.Header = &dataBlock.Header{TimeStamp: 3*secondsInDay + 6}
+	outportBlock.BlockData.HeaderHash = []byte("hash")
+
+	err := elasticProc.SaveHeader(outportBlock)
+	require.Equal(t, expectedErr, err)
+	require.Equal(t, 1, numSearches)
+
+	bulkErr = nil
+	err = elasticProc.SaveHeader(outportBlock)
+	require.Nil(t, err)
+	require.Equal(t, 2, numSearches)
+}
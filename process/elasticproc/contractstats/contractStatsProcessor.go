@@ -0,0 +1,172 @@
+package contractstats
+
+import (
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/kalyan3104/k-chain-core-go/core"
+	"github.com/kalyan3104/k-chain-core-go/core/check"
+	"github.com/kalyan3104/k-chain-core-go/data/outport"
+	"github.com/kalyan3104/k-chain-core-go/data/transaction"
+	"github.com/kalyan3104/k-chain-es-indexer-go/data"
+	"github.com/kalyan3104/k-chain-es-indexer-go/process/dataindexer"
+	logger "github.com/kalyan3104/k-chain-logger-go"
+)
+
+const (
+	secondsInDay = 24 * 60 * 60
+
+	// the callers of a contract are counted exactly up to this number, which is the maximum supported by the
+	// cardinality aggregation, and approximately above it
+	uniqueCallersPrecisionThreshold = 40000
+	// the number of contracts whose unique callers are returned by a search request
+	uniqueCallersPageSize = 1000
+)
+
+var log = logger.GetOrCreate("indexer/process/contractstats")
+
+type contractStatsProcessor struct {
+	pubKeyConverter  core.PubkeyConverter
+	balanceConverter dataindexer.BalanceConverter
+}
+
+// NewContractStatsProcessor will create a new instance of contractStatsProcessor
+func NewContractStatsProcessor(pubKeyConverter core.PubkeyConverter, balanceConverter dataindexer.BalanceConverter) (*contractStatsProcessor, error) {
+	if check.IfNil(pubKeyConverter) {
+		return nil, dataindexer.ErrNilPubkeyConverter
+	}
+	if check.IfNil(balanceConverter) {
+		return nil, dataindexer.ErrNilBalanceConverter
+	}
+
+	return &contractStatsProcessor{
+		pubKeyConverter:  pubKeyConverter,
+		balanceConverter: balanceConverter,
+	}, nil
+}
+
+// PrepareContractStats will group the smart contract calls from the provided transactions and smart contract results
+// by contract and by day. Only the calls executed on the provided shard are taken into account
+func (csp *contractStatsProcessor) PrepareContractStats(
+	txs []*data.Transaction,
+	scrs []*data.ScResult,
+	txHashStatusInfo map[string]*outport.StatusInfo,
+	shardID uint32,
+) map[string]*data.ContractStats {
+	statsMap := make(map[string]*data.ContractStats)
+	feesMap := make(map[string]*big.Int)
+
+	for _, tx := range txs {
+		shouldSkip := !tx.IsScCall || tx.ReceiverShard != shardID || tx.Status == transaction.TxStatusInvalid.String()
+		if shouldSkip {
+			continue
+		}
+
+		stats := getOrCreateStats(statsMap, tx.Receiver, tx.Timestamp)
+		isFailed := getStatus(tx.Hash, tx.Status, txHashStatusInfo) == transaction.TxStatusFail.String()
+		addCall(stats, tx.Function, tx.Timestamp, isFailed)
+		stats.GasUsed += tx.GasUsed
+
+		fee, ok := big.NewInt(0).SetString(tx.Fee, 10)
+		if !ok {
+			continue
+		}
+		id := computeContractStatsID(stats.Address, stats.Day)
+		if _, found := feesMap[id]; !found {
+			feesMap[id] = big.NewInt(0)
+		}
+		feesMap[id].Add(feesMap[id], fee)
+	}
+
+	for _, scr := range scrs {
+		// smart contract results without a function are value transfers or refunds, not calls
+		shouldSkip := scr.Function == "" || scr.ReceiverShard != shardID || !csp.isSmartContract(scr.Receiver)
+		if shouldSkip {
+			continue
+		}
+
+		stats := getOrCreateStats(statsMap, scr.Receiver, scr.Timestamp)
+		isFailed := getStatus(scr.Hash, scr.Status, txHashStatusInfo) == transaction.TxStatusFail.String()
+		addCall(stats, scr.Function, scr.Timestamp, isFailed)
+	}
+
+	for id, stats := range statsMap {
+		fees, found := feesMap[id]
+		if !found {
+			fees = big.NewInt(0)
+		}
+		stats.Fees = fees.String()
+		stats.FeesNum = csp.computeValueAsFloat(fees)
+	}
+
+	return statsMap
+}
+
+func (csp *contractStatsProcessor) isSmartContract(address string) bool {
+	addressBytes, err := csp.pubKeyConverter.Decode(address)
+	if err != nil {
+		return false
+	}
+
+	return core.IsSmartContractAddress(addressBytes)
+}
+
+func (csp *contractStatsProcessor) computeValueAsFloat(value *big.Int) float64 {
+	valueNum, err := csp.balanceConverter.ConvertBigValueToFloat(value)
+	if err != nil {
+		log.Warn("contractStatsProcessor.computeValueAsFloat cannot compute value as num", "value", value, "error", err)
+	}
+
+	return valueNum
+}
+
+func getOrCreateStats(statsMap map[string]*data.ContractStats, address string, timestamp time.Duration) *data.ContractStats {
+	day := ComputeDay(timestamp)
+	id := computeContractStatsID(address, day)
+
+	stats, found := statsMap[id]
+	if found {
+		return stats
+	}
+
+	stats = &data.ContractStats{
+		Address:         address,
+		Day:             day,
+		CallsByFunction: make(map[string]uint64),
+	}
+	statsMap[id] = stats
+
+	return stats
+}
+
+func addCall(stats *data.ContractStats, function string, timestamp time.Duration, isFailed bool) {
+	stats.Calls++
+	if function != "" {
+		stats.CallsByFunction[function]++
+	}
+	if isFailed {
+		stats.FailedCalls++
+	}
+	if timestamp > stats.LastCalled {
+		stats.LastCalled = timestamp
+	}
+}
+
+func getStatus(hash string, status string, txHashStatusInfo map[string]*outport.StatusInfo) string {
+	statusInfo, found := txHashStatusInfo[hash]
+	if found && statusInfo.Status != "" {
+		return statusInfo.Status
+	}
+
+	return status
+}
+
+// ComputeDay will compute the timestamp of the start of the day of the provided timestamp
+func ComputeDay(timestamp time.Duration) time.Duration {
+	return timestamp - timestamp%secondsInDay
+}
+
+func computeContractStatsID(address string, day time.Duration) string {
+	return fmt.Sprintf("%s_%d", address, day)
+}
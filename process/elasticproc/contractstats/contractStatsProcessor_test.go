@@ -0,0 +1,101 @@
+package contractstats
+
+import (
+	"encoding/hex"
+	"math/big"
+	"testing"
+
+	"github.com/kalyan3104/k-chain-core-go/data/outport"
+	"github.com/kalyan3104/k-chain-core-go/data/transaction"
+	"github.com/kalyan3104/k-chain-es-indexer-go/data"
+	"github.com/kalyan3104/k-chain-es-indexer-go/mock"
+	"github.com/kalyan3104/k-chain-es-indexer-go/process/dataindexer"
+	"github.com/kalyan3104/k-chain-es-indexer-go/process/elasticproc/converters"
+	"github.com/stretchr/testify/require"
+)
+
+var contractAddress = hex.EncodeToString(append(make([]byte, 10), []byte("contract-address-bytes")...))
+
+func createContractStatsProcessor() *contractStatsProcessor {
+	balanceConverter, _ := converters.NewBalanceConverter(18)
+	csp, _ := NewContractStatsProcessor(mock.NewPubkeyConverterMock(32), balanceConverter)
+
+	return csp
+}
+
+func TestNewContractStatsProcessor(t *testing.T) {
+	t.Parallel()
+
+	balanceConverter, _ := converters.NewBalanceConverter(18)
+
+	csp, err := NewContractStatsProcessor(nil, balanceConverter)
+	require.Nil(t, csp)
+	require.Equal(t, dataindexer.ErrNilPubkeyConverter, err)
+
+	csp, err = NewContractStatsProcessor(mock.NewPubkeyConverterMock(32), nil)
+	require.Nil(t, csp)
+	require.Equal(t, dataindexer.ErrNilBalanceConverter, err)
+
+	csp, err = NewContractStatsProcessor(mock.NewPubkeyConverterMock(32), balanceConverter)
+	require.Nil(t, err)
+	require.NotNil(t, csp)
+}
+
+func TestContractStatsProcessor_PrepareContractStats(t *testing.T) {
+	t.Parallel()
+
+	csp := createContractStatsProcessor()
+
+	txs := []*data.Transaction{
+		{Hash: "h1", Sender: "aa", Receiver: contractAddress, IsScCall: true, Function: "claim", GasUsed: 10, Fee: "100", Status: "success", Timestamp: 10 + 3*secondsInDay},
+		{Hash: "h2", Sender: "bb", Receiver: contractAddress, IsScCall: true, Function: "claim", GasUsed: 20, Fee: "200", Status: "success", Timestamp: 20 + 3*secondsInDay},
+		{Hash: "h3", Sender: "aa", Receiver: contractAddress, IsScCall: true, Function: "stake", GasUsed: 30, Fee: "300", Status: "success", Timestamp: 30 + 3*secondsInDay},
+		{Hash: "h4", Sender: "aa", Receiver: contractAddress, IsScCall: true, Function: "stake", ReceiverShard: 1, Status: "success"},
+		{Hash: "h5", Sender: "aa", Receiver: "bb", Status: "success"},
+	}
+	scrs := []*data.ScResult{
+		{Hash: "s1", Sender: "cc", Receiver: contractAddress, Function: "callback", Timestamp: 40 + 3*secondsInDay},
+		{Hash: "s2", Sender: contractAddress, Receiver: "aa", Function: "refund", Timestamp: 40 + 3*secondsInDay},
+		{Hash: "s3", Sender: "cc", Receiver: contractAddress, Timestamp: 40 + 3*secondsInDay},
+	}
+	statusInfo := map[string]*outport.StatusInfo{
+		"h3": {Status: transaction.TxStatusFail.String()},
+	}
+
+	statsMap := csp.PrepareContractStats(txs, scrs, statusInfo, 0)
+	require.Len(t, statsMap, 1)
+
+	balanceConverter, _ := converters.NewBalanceConverter(18)
+	expectedFeesNum, _ := balanceConverter.ConvertBigValueToFloat(big.NewInt(600))
+	require.Equal(t, &data.ContractStats{
+		Address: contractAddress,
+		Day:     3 * secondsInDay,
+		Calls:   4,
+		CallsByFunction: map[string]uint64{
+			"claim":    2,
+			"stake":    1,
+			"callback": 1,
+		},
+		GasUsed:     60,
+		Fees:        "600",
+		FeesNum:     expectedFeesNum,
+		FailedCalls: 1,
+		LastCalled:  40 + 3*secondsInDay,
+	}, statsMap[computeContractStatsID(contractAddress, 3*secondsInDay)])
+}
+
+func TestContractStatsProcessor_PrepareContractStatsShouldBucketByDay(t *testing.T) {
+	t.Parallel()
+
+	csp := createContractStatsProcessor()
+
+	txs := []*data.Transaction{
+		{Sender: "aa", Receiver: contractAddress, IsScCall: true, Fee: "1", Timestamp: secondsInDay - 1},
+		{Sender: "aa", Receiver: contractAddress, IsScCall: true, Fee: "1", Timestamp: secondsInDay},
+	}
+
+	statsMap := csp.PrepareContractStats(txs, nil, nil, 0)
+	require.Len(t, statsMap, 2)
+	require.Equal(t, uint64(1), statsMap[computeContractStatsID(contractAddress, 0)].Calls)
+	require.Equal(t, uint64(1), statsMap[computeContractStatsID(contractAddress, secondsInDay)].Calls)
+}
@@ -0,0 +1,140 @@
+package contractstats
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/kalyan3104/k-chain-core-go/data/transaction"
+	"github.com/kalyan3104/k-chain-es-indexer-go/data"
+	"github.com/kalyan3104/k-chain-es-indexer-go/process/elasticproc/converters"
+)
+
+// SerializeContractStats will serialize the provided statistics in a way that Elasticsearch expects a bulk request
+func (csp *contractStatsProcessor) SerializeContractStats(statsMap map[string]*data.ContractStats, buffSlice *data.BufferSlice, index string) error {
+	codeToExecute := `
+		if ('create' == ctx.op) {
+			ctx._source = params.stats
+		} else {
+			ctx._source.calls += params.stats.calls;
+			ctx._source.gasUsed += params.stats.gasUsed;
+			ctx._source.failedCalls += params.stats.failedCalls;
+			ctx._source.feesNum += params.stats.feesNum;
+			ctx._source.fees = new BigInteger(ctx._source.fees).add(new BigInteger(params.stats.fees)).toString();
+			for (String key : params.stats.callsByFunction.keySet()) {
+				ctx._source.callsByFunction[key] = ctx._source.callsByFunction.getOrDefault(key, 0) + params.stats.callsByFunction[key];
+			}
+			if (params.stats.lastCalled > ctx._source.lastCalled) {
+				ctx._source.lastCalled = params.stats.lastCalled;
+			}
+		}
+`
+
+	return serializeStatsWithScript(statsMap, codeToExecute, buffSlice, index)
+}
+
+// SerializeContractStatsRevert will serialize the requests that subtract the provided statistics. The documents that
+// remain without calls are deleted
+func (csp *contractStatsProcessor) SerializeContractStatsRevert(statsMap map[string]*data.ContractStats, buffSlice *data.BufferSlice, index string) error {
+	// the previous value of the last called timestamp cannot be computed, so it is kept as it is
+	codeToExecute := `
+		if ('create' == ctx.op) {
+			ctx.op = 'noop'
+		} else {
+			ctx._source.calls -= params.stats.calls;
+			ctx._source.gasUsed -= params.stats.gasUsed;
+			ctx._source.failedCalls -= params.stats.failedCalls;
+			ctx._source.feesNum -= params.stats.feesNum;
+			ctx._source.fees = new BigInteger(ctx._source.fees).subtract(new BigInteger(params.stats.fees)).toString();
+			for (String key : params.stats.callsByFunction.keySet()) {
+				long value = ctx._source.callsByFunction.getOrDefault(key, 0) - params.stats.callsByFunction[key];
+				if (value <= 0) {
+					ctx._source.callsByFunction.remove(key);
+				} else {
+					ctx._source.callsByFunction[key] = value;
+				}
+			}
+			if (ctx._source.calls <= 0) {
+				ctx.op = 'delete';
+			}
+		}
+`
+
+	return serializeStatsWithScript(statsMap, codeToExecute, buffSlice, index)
+}
+
+func serializeStatsWithScript(statsMap map[string]*data.ContractStats, codeToExecute string, buffSlice *data.BufferSlice, index string) error {
+	for id, stats := range statsMap {
+		statsSerialized, err := json.Marshal(stats)
+		if err != nil {
+			return err
+		}
+
+		serializedDataStr := fmt.Sprintf(`{"scripted_upsert": true, "script": {`+
+			`"source": "%s",`+
+			`"lang": "painless",`+
+			`"params": { "stats": %s }},`+
+			`"upsert": {}}`,
+			converters.FormatPainlessSource(codeToExecute), string(statsSerialized),
+		)
+
+		meta := []byte(fmt.Sprintf(`{ "update" : { "_index":"%s", "_id" : "%s" } }%s`, index, converters.JsonEscape(id), "\n"))
+		err = buffSlice.PutData(meta, []byte(serializedDataStr))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// PrepareUniqueCallersQuery will prepare the query that counts the callers of every smart contract of the provided shard
+// called in the provided day. The contracts are returned in pages, starting after the provided address
+func (csp *contractStatsProcessor) PrepareUniqueCallersQuery(shardID uint32, day time.Duration, afterAddress string) *bytes.Buffer {
+	after := ""
+	if afterAddress != "" {
+		after = fmt.Sprintf(`, "after": {"address": "%s"}`, converters.JsonEscape(afterAddress))
+	}
+
+	// the transactions are calls when they are flagged as such and the smart contract results when they have a function
+	query := fmt.Sprintf(`{"size": 0, "query": {"bool": {"filter": [{"range": {"timestamp": {"gte": %d, "lt": %d}}}, {"term": {"receiverShard": %d}}], `+
+		`"should": [`+
+		`{"bool": {"filter": [{"term": {"isScCall": true}}], "must_not": [{"term": {"status": "%s"}}]}}, `+
+		`{"bool": {"filter": [{"exists": {"field": "function"}}, {"exists": {"field": "originalTxHash"}}]}}], `+
+		`"minimum_should_match": 1}}, `+
+		`"aggs": {"contracts": {"composite": {"size": %d, "sources": [{"address": {"terms": {"field": "receiver"}}}]%s}, `+
+		`"aggs": {"callers": {"cardinality": {"field": "sender", "precision_threshold": %d}}}}}}`,
+		day, day+secondsInDay, shardID, transaction.TxStatusInvalid.String(), uniqueCallersPageSize, after, uniqueCallersPrecisionThreshold)
+
+	return bytes.NewBuffer([]byte(query))
+}
+
+// SerializeContractsUniqueCallers will serialize the requests that set the number of unique callers of the provided
+// smart contracts in the provided day. The statistics of a contract without calls in the day are not created
+func (csp *contractStatsProcessor) SerializeContractsUniqueCallers(uniqueCallers map[string]uint64, day time.Duration, buffSlice *data.BufferSlice, index string) error {
+	codeToExecute := `
+		if ('create' == ctx.op) {
+			ctx.op = 'noop'
+		} else {
+			ctx._source.uniqueCallers = params.uniqueCallers;
+		}
+`
+	for address, callers := range uniqueCallers {
+		serializedDataStr := fmt.Sprintf(`{"scripted_upsert": true, "script": {`+
+			`"source": "%s",`+
+			`"lang": "painless",`+
+			`"params": { "uniqueCallers": %d }},`+
+			`"upsert": {}}`,
+			converters.FormatPainlessSource(codeToExecute), callers,
+		)
+
+		meta := []byte(fmt.Sprintf(`{ "update" : { "_index":"%s", "_id" : "%s" } }%s`, index, converters.JsonEscape(computeContractStatsID(address, day)), "\n"))
+		err := buffSlice.PutData(meta, []byte(serializedDataStr))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
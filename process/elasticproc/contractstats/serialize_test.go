@@ -0,0 +1,83 @@
+package contractstats
+
+import (
+	"testing"
+
+	"github.com/kalyan3104/k-chain-es-indexer-go/data"
+	"github.com/stretchr/testify/require"
+)
+
+func createStatsMap() map[string]*data.ContractStats {
+	return map[string]*data.ContractStats{
+		"contract_0": {
+			Address:         "contract",
+			Calls:           1,
+			CallsByFunction: map[string]uint64{"claim": 1},
+			GasUsed:         10,
+			Fees:            "100",
+			LastCalled:      5,
+		},
+	}
+}
+
+func TestContractStatsProcessor_SerializeContractStats(t *testing.T) {
+	t.Parallel()
+
+	csp := createContractStatsProcessor()
+
+	buffSlice := data.NewBufferSlice(data.DefaultMaxBulkSize)
+	err := csp.SerializeContractStats(createStatsMap(), buffSlice, "contractstats")
+	require.Nil(t, err)
+
+	expectedRes := `{ "update" : { "_index":"contractstats", "_id" : "contract_0" } }
+{"scripted_upsert": true, "script": {"source": "if ('create' == ctx.op) {ctx._source = params.stats} else {ctx._source.calls += params.stats.calls;ctx._source.gasUsed += params.stats.gasUsed;ctx._source.failedCalls += params.stats.failedCalls;ctx._source.feesNum += params.stats.feesNum;ctx._source.fees = new BigInteger(ctx._source.fees).add(new BigInteger(params.stats.fees)).toString();for (String key : params.stats.callsByFunction.keySet()) {ctx._source.callsByFunction[key] = ctx._source.callsByFunction.getOrDefault(key, 0) + params.stats.callsByFunction[key];}if (params.stats.lastCalled > ctx._source.lastCalled) {ctx._source.lastCalled = params.stats.lastCalled;}}","lang": "painless","params": { "stats": {"address":"contract","day":0,"calls":1,"callsByFunction":{"claim":1},"uniqueCallers":0,"gasUsed":10,"fees":"100","feesNum":0,"failedCalls":0,"lastCalled":5} }},"upsert": {}}
+`
+	require.Equal(t, expectedRes, buffSlice.Buffers()[0].String())
+}
+
+func TestContractStatsProcessor_SerializeContractStatsRevert(t *testing.T) {
+	t.Parallel()
+
+	csp := createContractStatsProcessor()
+
+	buffSlice := data.NewBufferSlice(data.DefaultMaxBulkSize)
+	err := csp.SerializeContractStatsRevert(createStatsMap(), buffSlice, "contractstats")
+	require.Nil(t, err)
+
+	expectedRes := `{ "update" : { "_index":"contractstats", "_id" : "contract_0" } }
+{"scripted_upsert": true, "script": {"source": "if ('create' == ctx.op) {ctx.op = 'noop'} else {ctx._source.calls -= params.stats.calls;ctx._source.gasUsed -= params.stats.gasUsed;ctx._source.failedCalls -= params.stats.failedCalls;ctx._source.feesNum -= params.stats.feesNum;ctx._source.fees = new BigInteger(ctx._source.fees).subtract(new BigInteger(params.stats.fees)).toString();for (String key : params.stats.callsByFunction.keySet()) {long value = ctx._source.callsByFunction.getOrDefault(key, 0) - params.stats.callsByFunction[key];if (value <= 0) {ctx._source.callsByFunction.remove(key);} else {ctx._source.callsByFunction[key] = value;}}if (ctx._source.calls <= 0) {ctx.op = 'delete';}}","lang": "painless","params": { "stats": {"address":"contract","day":0,"calls":1,"callsByFunction":{"claim":1},"uniqueCallers":0,"gasUsed":10,"fees":"100","feesNum":0,"failedCalls":0,"lastCalled":5} }},"upsert": {}}
+`
+	require.Equal(t, expectedRes, buffSlice.Buffers()[0].String())
+}
+
+func TestContractStatsProcessor_PrepareUniqueCallersQuery(t *testing.T) {
+	t.Parallel()
+
+	csp := createContractStatsProcessor()
+
+	query := csp.PrepareUniqueCallersQuery(1, 2*secondsInDay, "")
+	expectedQuery := `{"size": 0, "query": {"bool": {"filter": [{"range": {"timestamp": {"gte": 172800, "lt": 259200}}}, {"term": {"receiverShard": 1}}], ` +
+		`"should": [{"bool": {"filter": [{"term": {"isScCall": true}}], "must_not": [{"term": {"status": "invalid"}}]}}, ` +
+		`{"bool": {"filter": [{"exists": {"field": "function"}}, {"exists": {"field": "originalTxHash"}}]}}], "minimum_should_match": 1}}, ` +
+		`"aggs": {"contracts": {"composite": {"size": 1000, "sources": [{"address": {"terms": {"field": "receiver"}}}]}, ` +
+		`"aggs": {"callers": {"cardinality": {"field": "sender", "precision_threshold": 40000}}}}}}`
+	require.Equal(t, expectedQuery, query.String())
+
+	query = csp.PrepareUniqueCallersQuery(1, 2*secondsInDay, "contract")
+	require.Contains(t, query.String(), `"sources": [{"address": {"terms": {"field": "receiver"}}}], "after": {"address": "contract"}}`)
+}
+
+func TestContractStatsProcessor_SerializeContractsUniqueCallers(t *testing.T) {
+	t.Parallel()
+
+	csp := createContractStatsProcessor()
+
+	buffSlice := data.NewBufferSlice(data.DefaultMaxBulkSize)
+	err := csp.SerializeContractsUniqueCallers(map[string]uint64{"contract": 3}, secondsInDay, buffSlice, "contractstats")
+	require.Nil(t, err)
+
+	expectedRes := `{ "update" : { "_index":"contractstats", "_id" : "contract_86400" } }
+{"scripted_upsert": true, "script": {"source": "if ('create' == ctx.op) {ctx.op = 'noop'} else {ctx._source.uniqueCallers = params.uniqueCallers;}","lang": "painless","params": { "uniqueCallers": 3 }},"upsert": {}}
+`
+	require.Equal(t, expectedRes, buffSlice.Buffers()[0].String())
+}
@@ -6,7 +6,9 @@ import (
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"sort"
 	"sync"
+	"time"
 
 	"github.com/kalyan3104/k-chain-core-go/core"
 	"github.com/kalyan3104/k-chain-core-go/core/check"
@@ -17,7 +19,10 @@ import (
 	"github.com/kalyan3104/k-chain-es-indexer-go/core/request"
 	"github.com/kalyan3104/k-chain-es-indexer-go/data"
 	elasticIndexer "github.com/kalyan3104/k-chain-es-indexer-go/process/dataindexer"
+	"github.com/kalyan3104/k-chain-es-indexer-go/process/elasticproc/consensusstats"
 	"github.com/kalyan3104/k-chain-es-indexer-go/process/elasticproc/converters"
+	"github.com/kalyan3104/k-chain-es-indexer-go/process/elasticproc/stagetimings"
+	"github.com/kalyan3104/k-chain-es-indexer-go/process/elasticproc/statscontributions"
 	"github.com/kalyan3104/k-chain-es-indexer-go/process/elasticproc/tags"
 	"github.com/kalyan3104/k-chain-es-indexer-go/process/elasticproc/tokeninfo"
 	logger "github.com/kalyan3104/k-chain-logger-go"
@@ -30,47 +35,91 @@ var (
 		elasticIndexer.TransactionsIndex, elasticIndexer.BlockIndex, elasticIndexer.MiniblocksIndex, elasticIndexer.RatingIndex, elasticIndexer.RoundsIndex, elasticIndexer.ValidatorsIndex,
 		elasticIndexer.AccountsIndex, elasticIndexer.AccountsHistoryIndex, elasticIndexer.ReceiptsIndex, elasticIndexer.ScResultsIndex, elasticIndexer.AccountsDCDTHistoryIndex, elasticIndexer.AccountsDCDTIndex,
 		elasticIndexer.EpochInfoIndex, elasticIndexer.SCDeploysIndex, elasticIndexer.TokensIndex, elasticIndexer.TagsIndex, elasticIndexer.LogsIndex, elasticIndexer.DelegatorsIndex, elasticIndexer.OperationsIndex,
-		elasticIndexer.DCDTsIndex, elasticIndexer.ValuesIndex, elasticIndexer.EventsIndex,
+		elasticIndexer.DCDTsIndex, elasticIndexer.ValuesIndex, elasticIndexer.EventsIndex, elasticIndexer.EpochStatsIndex,
+		elasticIndexer.ContractStatsIndex, elasticIndexer.DelegationProvidersIndex,
+		elasticIndexer.RatingHistoryIndex, elasticIndexer.ValidatorsHistoryIndex, elasticIndexer.ConsensusStatsIndex,
+		elasticIndexer.TxLifecycleIndex, elasticIndexer.CallTreeIndex, elasticIndexer.JournalIndex, elasticIndexer.IncompleteBlocksIndex,
+		elasticIndexer.StatsContributionsIndex,
+	}
+
+	// the indices whose documents are updated in place by the blocks, so their previous versions are kept in the
+	// journal in order to be restored when a block is reverted
+	journaledIndexes = map[string]struct{}{
+		elasticIndexer.TokensIndex: {}, elasticIndexer.DCDTsIndex: {}, elasticIndexer.SCDeploysIndex: {},
+		elasticIndexer.TagsIndex: {}, elasticIndexer.AccountsDCDTIndex: {},
+	}
+
+	// the counter fields of the journaled indices, which are incremented by the blocks of all shards, so the journal
+	// keeps the increments of a block instead of the values written by it
+	journaledCounterFields = map[string][]string{
+		elasticIndexer.TagsIndex: {"count"},
 	}
 )
 
-const versionStr = "indexer-version"
+const (
+	versionStr = "indexer-version"
+
+	// accountsRestoreBatchSize is the number of addresses whose latest history entries are fetched with a search request
+	accountsRestoreBatchSize = 1000
+)
 
 // ArgElasticProcessor holds all dependencies required by the elasticProcessor in order to create
 // new instances
 type ArgElasticProcessor struct {
-	BulkRequestMaxSize int
-	UseKibana          bool
-	ImportDB           bool
-	IndexTemplates     map[string]*bytes.Buffer
-	IndexPolicies      map[string]*bytes.Buffer
-	EnabledIndexes     map[string]struct{}
-	TransactionsProc   DBTransactionsHandler
-	AccountsProc       DBAccountHandler
-	BlockProc          DBBlockHandler
-	MiniblocksProc     DBMiniblocksHandler
-	StatisticsProc     DBStatisticsHandler
-	ValidatorsProc     DBValidatorsHandler
-	DBClient           DatabaseClientHandler
-	LogsAndEventsProc  DBLogsAndEventsHandler
-	OperationsProc     OperationsHandler
-	Version            string
+	BulkRequestMaxSize     int
+	UseKibana              bool
+	ImportDB               bool
+	IndexTemplates         map[string]*bytes.Buffer
+	IndexPolicies          map[string]*bytes.Buffer
+	EnabledIndexes         map[string]struct{}
+	TransactionsProc       DBTransactionsHandler
+	AccountsProc           DBAccountHandler
+	BlockProc              DBBlockHandler
+	MiniblocksProc         DBMiniblocksHandler
+	StatisticsProc         DBStatisticsHandler
+	ValidatorsProc         DBValidatorsHandler
+	DBClient               DatabaseClientHandler
+	LogsAndEventsProc      DBLogsAndEventsHandler
+	OperationsProc         OperationsHandler
+	EpochStatsProc         DBEpochStatsHandler
+	ContractStatsProc      DBContractStatsHandler
+	ConsensusStatsProc     DBConsensusStatsHandler
+	TxLifecycleProc        DBTxLifecycleHandler
+	CallTreeProc           DBCallTreeHandler
+	JournalProc            DBJournalHandler
+	StatsContributionsProc DBStatsContributionsHandler
+	StagesRecorder         BlockStagesRecorder
+	Version                string
+	ContributionsRetention time.Duration
 }
 
 type elasticProcessor struct {
-	bulkRequestMaxSize int
-	importDB           bool
-	enabledIndexes     map[string]struct{}
-	mutex              sync.RWMutex
-	elasticClient      DatabaseClientHandler
-	accountsProc       DBAccountHandler
-	blockProc          DBBlockHandler
-	transactionsProc   DBTransactionsHandler
-	miniblocksProc     DBMiniblocksHandler
-	statisticsProc     DBStatisticsHandler
-	validatorsProc     DBValidatorsHandler
-	logsAndEventsProc  DBLogsAndEventsHandler
-	operationsProc     OperationsHandler
+	bulkRequestMaxSize     int
+	importDB               bool
+	enabledIndexes         map[string]struct{}
+	mutex                  sync.RWMutex
+	elasticClient          DatabaseClientHandler
+	accountsProc           DBAccountHandler
+	blockProc              DBBlockHandler
+	transactionsProc       DBTransactionsHandler
+	miniblocksProc         DBMiniblocksHandler
+	statisticsProc         DBStatisticsHandler
+	validatorsProc         DBValidatorsHandler
+	logsAndEventsProc      DBLogsAndEventsHandler
+	operationsProc         OperationsHandler
+	epochStatsProc         DBEpochStatsHandler
+	contractStatsProc      DBContractStatsHandler
+	consensusStatsProc     DBConsensusStatsHandler
+	txLifecycleProc        DBTxLifecycleHandler
+	callTreeProc           DBCallTreeHandler
+	journalProc            DBJournalHandler
+	statsContributionsProc DBStatsContributionsHandler
+	stagesRecorder         BlockStagesRecorder
+	contributionsRetention time.Duration
+	mutLastWrittenBlocks   sync.Mutex
+	lastWrittenBlocks      map[uint32]string
+	mutCountedCallersDays  sync.Mutex
+	countedCallersDays     map[uint32]time.Duration
 }
 
 // NewElasticProcessor handles Elasticsearch operations such as initialization, adding, modifying or removing data
@@ -81,17 +130,28 @@ func NewElasticProcessor(arguments *ArgElasticProcessor) (*elasticProcessor, err
 	}
 
 	ei := &elasticProcessor{
-		elasticClient:      arguments.DBClient,
-		enabledIndexes:     arguments.EnabledIndexes,
-		accountsProc:       arguments.AccountsProc,
-		blockProc:          arguments.BlockProc,
-		miniblocksProc:     arguments.MiniblocksProc,
-		transactionsProc:   arguments.TransactionsProc,
-		statisticsProc:     arguments.StatisticsProc,
-		validatorsProc:     arguments.ValidatorsProc,
-		logsAndEventsProc:  arguments.LogsAndEventsProc,
-		operationsProc:     arguments.OperationsProc,
-		bulkRequestMaxSize: arguments.BulkRequestMaxSize,
+		elasticClient:          arguments.DBClient,
+		enabledIndexes:         arguments.EnabledIndexes,
+		accountsProc:           arguments.AccountsProc,
+		blockProc:              arguments.BlockProc,
+		miniblocksProc:         arguments.MiniblocksProc,
+		transactionsProc:       arguments.TransactionsProc,
+		statisticsProc:         arguments.StatisticsProc,
+		validatorsProc:         arguments.ValidatorsProc,
+		logsAndEventsProc:      arguments.LogsAndEventsProc,
+		operationsProc:         arguments.OperationsProc,
+		epochStatsProc:         arguments.EpochStatsProc,
+		contractStatsProc:      arguments.ContractStatsProc,
+		consensusStatsProc:     arguments.ConsensusStatsProc,
+		txLifecycleProc:        arguments.TxLifecycleProc,
+		callTreeProc:           arguments.CallTreeProc,
+		journalProc:            arguments.JournalProc,
+		statsContributionsProc: arguments.StatsContributionsProc,
+		stagesRecorder:         arguments.StagesRecorder,
+		contributionsRetention: arguments.ContributionsRetention,
+		bulkRequestMaxSize:     arguments.BulkRequestMaxSize,
+		lastWrittenBlocks:      make(map[uint32]string),
+		countedCallersDays:     make(map[uint32]time.Duration),
 	}
 
 	err = ei.init(arguments.UseKibana, arguments.IndexTemplates, arguments.IndexPolicies)
@@ -241,27 +301,197 @@ func getTemplateByName(templateName string, templateList map[string]*bytes.Buffe
 
 // SaveHeader will prepare and save information about a header in elasticsearch server
 func (ei *elasticProcessor) SaveHeader(outportBlockWithHeader *outport.OutportBlockWithHeader) error {
+	buffSlice := data.NewBufferSlice(ei.bulkRequestMaxSize)
+	err := ei.indexBlock(outportBlockWithHeader, buffSlice)
+	if err != nil {
+		return err
+	}
+
+	err = ei.indexEpochInfoData(outportBlockWithHeader.Header, buffSlice)
+	if err != nil {
+		return err
+	}
+
+	err = ei.indexEpochStatsFinalization(outportBlockWithHeader.Header, outportBlockWithHeader.NumberOfShards, buffSlice)
+	if err != nil {
+		return err
+	}
+
+	err = ei.indexEpochActiveAccounts(outportBlockWithHeader.Header, buffSlice)
+	if err != nil {
+		return err
+	}
+
+	err = ei.indexContractsUniqueCallers(outportBlockWithHeader.Header, buffSlice)
+	if err != nil {
+		return err
+	}
+
+	err = ei.indexEpochStatsOfEmptyBlock(outportBlockWithHeader, buffSlice)
+	if err != nil {
+		return err
+	}
+
+	err = ei.indexConsensusStatsFromBlock(outportBlockWithHeader, buffSlice)
+	if err != nil {
+		return err
+	}
+
+	err = ei.indexLastIndexedBlock(outportBlockWithHeader, buffSlice)
+	if err != nil {
+		return err
+	}
+
+	err = ei.doBulkRequests("", buffSlice.Buffers(), outportBlockWithHeader.ShardID)
+	if err != nil {
+		return err
+	}
+
+	ei.markContractsCallersDayAsCounted(outportBlockWithHeader.Header)
+
+	return ei.removeExpiredStatsContributions(outportBlockWithHeader.Header)
+}
+
+func (ei *elasticProcessor) indexBlock(obh *outport.OutportBlockWithHeader, buffSlice *data.BufferSlice) error {
 	if !ei.isIndexEnabled(elasticIndexer.BlockIndex) {
 		return nil
 	}
 
-	elasticBlock, err := ei.blockProc.PrepareBlockForDB(outportBlockWithHeader)
+	elasticBlock, err := ei.blockProc.PrepareBlockForDB(obh)
+	if err != nil {
+		return err
+	}
+
+	return ei.blockProc.SerializeBlock(elasticBlock, buffSlice, elasticIndexer.BlockIndex)
+}
+
+func (ei *elasticProcessor) indexConsensusStatsFromBlock(obh *outport.OutportBlockWithHeader, buffSlice *data.BufferSlice) error {
+	if !ei.isIndexEnabled(elasticIndexer.ConsensusStatsIndex) || len(obh.SignersIndexes) == 0 {
+		return nil
+	}
+
+	header := obh.Header
+	ids := []string{consensusstats.ComputeValidatorsListID(header.GetShardID(), header.GetEpoch())}
+	validatorsLists, err := ei.consensusStatsProc.ResolveValidatorsPubKeys(ids, ei.createValidatorsPubKeysFetcher(header.GetShardID()))
+	if err != nil {
+		return err
+	}
+
+	blockHash := hex.EncodeToString(obh.BlockData.HeaderHash)
+	previousContribution, err := ei.getStatsContribution(elasticIndexer.ConsensusStatsIndex, blockHash, header.GetShardID())
+	if err != nil {
+		return err
+	}
+
+	statsMap := ei.consensusStatsProc.PrepareConsensusStatsFromBlock(header, obh.SignersIndexes, validatorsLists)
+	contribution, err := ei.statsContributionsProc.PrepareStatsContribution(elasticIndexer.ConsensusStatsIndex, blockHash, header.GetShardID(), header.GetTimeStamp(), statsMap)
+	if err != nil {
+		return err
+	}
+
+	return ei.serializeStatsWithContribution(contribution, previousContribution, func() error {
+		return ei.consensusStatsProc.SerializeConsensusStats(statsMap, buffSlice, elasticIndexer.ConsensusStatsIndex)
+	}, buffSlice)
+}
+
+func (ei *elasticProcessor) indexConsensusStatsFromRounds(rounds *outport.RoundsInfo) error {
+	if !ei.isIndexEnabled(elasticIndexer.ConsensusStatsIndex) {
+		return nil
+	}
+
+	ids := make([]string, 0)
+	idsMap := make(map[string]struct{})
+	for _, roundInfo := range rounds.RoundsInfo {
+		id := consensusstats.ComputeValidatorsListID(roundInfo.ShardId, roundInfo.Epoch)
+		if _, found := idsMap[id]; found {
+			continue
+		}
+		idsMap[id] = struct{}{}
+		ids = append(ids, id)
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	validatorsLists, err := ei.consensusStatsProc.ResolveValidatorsPubKeys(ids, ei.createValidatorsPubKeysFetcher(rounds.ShardID))
 	if err != nil {
 		return err
 	}
 
 	buffSlice := data.NewBufferSlice(ei.bulkRequestMaxSize)
-	err = ei.blockProc.SerializeBlock(elasticBlock, buffSlice, elasticIndexer.BlockIndex)
+	err = ei.serializeConsensusStatsFromRounds(rounds, validatorsLists, buffSlice)
 	if err != nil {
 		return err
 	}
 
-	err = ei.indexEpochInfoData(outportBlockWithHeader.Header, buffSlice)
+	return ei.doBulkRequests("", buffSlice.Buffers(), rounds.ShardID)
+}
+
+// serializeConsensusStatsFromRounds serializes the statistics of every round together with its contribution, which has
+// the shard and the round as key, so a round that is saved again does not count twice. The contribution keeps the
+// timestamp of the round, so it is removed with the contributions of the blocks once its retention expires
+func (ei *elasticProcessor) serializeConsensusStatsFromRounds(rounds *outport.RoundsInfo, validatorsLists map[string][]string, buffSlice *data.BufferSlice) error {
+	keys := make([]string, 0, len(rounds.RoundsInfo))
+	ids := make([]string, 0, len(rounds.RoundsInfo))
+	for _, roundInfo := range rounds.RoundsInfo {
+		key := consensusstats.ComputeRoundContributionKey(roundInfo.ShardId, roundInfo.Round)
+		keys = append(keys, key)
+		ids = append(ids, statscontributions.ComputeStatsContributionID(elasticIndexer.ConsensusStatsIndex, key))
+	}
+
+	previousContributions, err := ei.getStatsContributions(ids, rounds.ShardID)
 	if err != nil {
 		return err
 	}
 
-	return ei.doBulkRequests("", buffSlice.Buffers(), outportBlockWithHeader.ShardID)
+	for idx, roundInfo := range rounds.RoundsInfo {
+		statsMap := ei.consensusStatsProc.PrepareConsensusStatsFromRounds([]*outport.RoundInfo{roundInfo}, validatorsLists)
+		previousContribution := previousContributions[ids[idx]]
+		if len(statsMap) == 0 && previousContribution == nil {
+			continue
+		}
+
+		contribution, errPrepare := ei.statsContributionsProc.PrepareStatsContribution(elasticIndexer.ConsensusStatsIndex, keys[idx], roundInfo.ShardId, roundInfo.Timestamp, statsMap)
+		if errPrepare != nil {
+			return errPrepare
+		}
+
+		err = ei.serializeStatsWithContribution(contribution, previousContribution, func() error {
+			return ei.consensusStatsProc.SerializeConsensusStats(statsMap, buffSlice, elasticIndexer.ConsensusStatsIndex)
+		}, buffSlice)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (ei *elasticProcessor) createValidatorsPubKeysFetcher(shardID uint32) func(ids []string) (map[string][]string, error) {
+	return func(ids []string) (map[string][]string, error) {
+		return ei.getValidatorsPubKeys(ids, shardID)
+	}
+}
+
+// getValidatorsPubKeys will return the public keys lists from the validators index, mapped by document id
+func (ei *elasticProcessor) getValidatorsPubKeys(ids []string, shardID uint32) (map[string][]string, error) {
+	ctxWithValue := context.WithValue(context.Background(), request.ContextKey, request.ExtendTopicWithShardID(request.GetTopic, shardID))
+	response := &data.ResponseValidatorsPublicKeys{}
+	err := ei.elasticClient.DoMultiGet(ctxWithValue, ids, elasticIndexer.ValidatorsIndex, true, response)
+	if err != nil {
+		return nil, err
+	}
+
+	validatorsLists := make(map[string][]string, len(response.Docs))
+	for _, doc := range response.Docs {
+		if !doc.Found {
+			continue
+		}
+
+		validatorsLists[doc.ID] = doc.Source.PublicKeys
+	}
+
+	return validatorsLists, nil
 }
 
 func (ei *elasticProcessor) indexEpochInfoData(header coreData.HeaderHandler, buffSlice *data.BufferSlice) error {
@@ -273,6 +503,18 @@ func (ei *elasticProcessor) indexEpochInfoData(header coreData.HeaderHandler, bu
 	return ei.blockProc.SerializeEpochInfoData(header, buffSlice, elasticIndexer.EpochInfoIndex)
 }
 
+func (ei *elasticProcessor) indexEpochStatsFinalization(header coreData.HeaderHandler, numOfShards uint32, buffSlice *data.BufferSlice) error {
+	shouldFinalize := ei.isIndexEnabled(elasticIndexer.EpochStatsIndex) &&
+		header.GetShardID() == core.MetachainShardId &&
+		header.IsStartOfEpochBlock() &&
+		header.GetEpoch() > 0
+	if !shouldFinalize {
+		return nil
+	}
+
+	return ei.epochStatsProc.SerializeEpochStatsFinalization(header.GetEpoch()-1, numOfShards, header.GetTimeStamp(), buffSlice, elasticIndexer.EpochStatsIndex)
+}
+
 // RemoveHeader will remove a block from elasticsearch server
 func (ei *elasticProcessor) RemoveHeader(header coreData.HeaderHandler) error {
 	headerHash, err := ei.blockProc.ComputeHeaderHash(header)
@@ -305,8 +547,26 @@ func (ei *elasticProcessor) RemoveMiniblocks(header coreData.HeaderHandler, body
 
 // RemoveTransactions will remove transaction that are in miniblock from the elasticsearch server
 func (ei *elasticProcessor) RemoveTransactions(header coreData.HeaderHandler, body *block.Body) error {
+	headerHash, err := ei.blockProc.ComputeHeaderHash(header)
+	if err != nil {
+		return err
+	}
+
 	encodedTxsHashes, encodedScrsHashes := ei.transactionsProc.GetHexEncodedHashesForRemove(header, body)
-	shardID := header.GetShardID()
+
+	return ei.removeTransactionsData(&revertedBlockData{
+		hash:              hex.EncodeToString(headerHash),
+		shardID:           header.GetShardID(),
+		timestamp:         header.GetTimeStamp(),
+		hasMiniblocks:     len(body.GetMiniBlocks()) > 0,
+		encodedTxsHashes:  encodedTxsHashes,
+		encodedScrsHashes: encodedScrsHashes,
+	})
+}
+
+func (ei *elasticProcessor) removeTransactionsData(revertedBlock *revertedBlockData) error {
+	encodedTxsHashes, encodedScrsHashes := revertedBlock.encodedTxsHashes, revertedBlock.encodedScrsHashes
+	shardID := revertedBlock.shardID
 
 	err := ei.removeIfHashesNotEmpty(elasticIndexer.TransactionsIndex, encodedTxsHashes, shardID)
 	if err != nil {
@@ -328,164 +588,773 @@ func (ei *elasticProcessor) RemoveTransactions(header coreData.HeaderHandler, bo
 		return err
 	}
 
-	err = ei.removeFromIndexByTimestampAndShardID(header.GetTimeStamp(), header.GetShardID(), elasticIndexer.EventsIndex)
-	if err != nil {
-		return err
-	}
+	err = ei.removeFromIndexByTimestampAndShardID(revertedBlock.timestamp, shardID, elasticIndexer.EventsIndex)
+	if err != nil {
+		return err
+	}
+
+	err = ei.removeTxLifecycleStagesInCaseOfRevert(revertedBlock.hash, shardID)
+	if err != nil {
+		return err
+	}
+
+	err = ei.removeCallTreeNodesInCaseOfRevert(revertedBlock.hash, shardID)
+	if err != nil {
+		return err
+	}
+
+	err = ei.updateDelegatorsInCaseOfRevert(revertedBlock)
+	if err != nil {
+		return err
+	}
+
+	err = ei.revertStatsContributionsInCaseOfRevert(revertedBlock)
+	if err != nil {
+		return err
+	}
+
+	err = ei.restoreAccountsInCaseOfRevert(revertedBlock)
+	if err != nil {
+		return err
+	}
+
+	err = ei.restoreJournaledDocumentsInCaseOfRevert(revertedBlock)
+	if err != nil {
+		return err
+	}
+
+	ei.removeLastWrittenBlock(revertedBlock)
+
+	return nil
+}
+
+// restoreAccountsInCaseOfRevert removes the accounts history entries written by the reverted block and brings the
+// accounts updated by it to the state from their latest remaining history entries
+func (ei *elasticProcessor) restoreAccountsInCaseOfRevert(revertedBlock *revertedBlockData) error {
+	if !ei.isIndexEnabled(elasticIndexer.AccountsHistoryIndex) {
+		if ei.isIndexEnabled(elasticIndexer.AccountsIndex) {
+			log.Warn("elasticProcessor.restoreAccountsInCaseOfRevert: the accounts updated by the reverted block are not restored without the accounts history index",
+				"shard", revertedBlock.shardID, "timestamp", revertedBlock.timestamp)
+		}
+		return nil
+	}
+
+	revertedEntries := make([]*data.AccountBalanceHistory, 0)
+	addressesMap := make(map[string]struct{})
+	handlerFunc := func(responseBytes []byte) error {
+		response := &data.ResponseAccountsHistory{}
+		err := json.Unmarshal(responseBytes, response)
+		if err != nil {
+			return err
+		}
+
+		for _, hit := range response.Hits.Hits {
+			if hit.Source == nil || hit.Source.Token != "" {
+				continue
+			}
+			if _, found := addressesMap[hit.Source.Address]; found {
+				continue
+			}
+
+			addressesMap[hit.Source.Address] = struct{}{}
+			revertedEntries = append(revertedEntries, hit.Source)
+		}
+
+		return nil
+	}
+
+	shardID := revertedBlock.shardID
+	query := ei.accountsProc.PrepareAccountsHistoryQueryInCaseOfRevert(revertedBlock.timestamp, shardID)
+	ctxWithValue := context.WithValue(context.Background(), request.ContextKey, request.ExtendTopicWithShardID(request.ScrollTopic, shardID))
+	err := ei.refreshIndexOfLastWrittenBlock(ctxWithValue, revertedBlock, elasticIndexer.AccountsHistoryIndex)
+	if err != nil {
+		return err
+	}
+
+	err = ei.elasticClient.DoScrollRequest(ctxWithValue, elasticIndexer.AccountsHistoryIndex, query.Bytes(), true, handlerFunc)
+	if err != nil {
+		return err
+	}
+
+	ctxWithValue = context.WithValue(context.Background(), request.ContextKey, request.ExtendTopicWithShardID(request.RemoveTopic, shardID))
+	err = ei.elasticClient.DoQueryRemove(ctxWithValue, elasticIndexer.AccountsHistoryIndex, query)
+	if err != nil {
+		return err
+	}
+
+	if !ei.isIndexEnabled(elasticIndexer.AccountsIndex) || len(revertedEntries) == 0 {
+		return nil
+	}
+
+	buffSlice := data.NewBufferSlice(ei.bulkRequestMaxSize)
+	for start := 0; start < len(revertedEntries); start += accountsRestoreBatchSize {
+		end := start + accountsRestoreBatchSize
+		if end > len(revertedEntries) {
+			end = len(revertedEntries)
+		}
+
+		err = ei.serializeAccountsRestore(revertedBlock, revertedEntries[start:end], buffSlice)
+		if err != nil {
+			return err
+		}
+	}
+
+	return ei.doBulkRequests(elasticIndexer.AccountsIndex, buffSlice.Buffers(), shardID)
+}
+
+// refreshIndexOfLastWrittenBlock makes the documents written by the reverted block in the provided index visible for
+// scrolls when it is the last block written for its shard, since it might be reverted right after it was indexed. A
+// refresh writes a new segment for every shard of the index and waits for it, so the blocks written before, whose
+// documents were already made visible by the refresh interval of the index, are not refreshed again
+func (ei *elasticProcessor) refreshIndexOfLastWrittenBlock(ctx context.Context, revertedBlock *revertedBlockData, index string) error {
+	ei.mutLastWrittenBlocks.Lock()
+	defer ei.mutLastWrittenBlocks.Unlock()
+
+	if ei.lastWrittenBlocks[revertedBlock.shardID] != revertedBlock.hash {
+		return nil
+	}
+
+	return ei.elasticClient.DoRefreshRequest(ctx, index)
+}
+
+// removeLastWrittenBlock is called once the reverted block was fully removed, so its indices are not refreshed again
+// if the block is reverted one more time
+func (ei *elasticProcessor) removeLastWrittenBlock(revertedBlock *revertedBlockData) {
+	ei.mutLastWrittenBlocks.Lock()
+	defer ei.mutLastWrittenBlocks.Unlock()
+
+	if ei.lastWrittenBlocks[revertedBlock.shardID] == revertedBlock.hash {
+		delete(ei.lastWrittenBlocks, revertedBlock.shardID)
+	}
+}
+
+func (ei *elasticProcessor) setLastWrittenBlock(shardID uint32, headerHash string) {
+	ei.mutLastWrittenBlocks.Lock()
+	ei.lastWrittenBlocks[shardID] = headerHash
+	ei.mutLastWrittenBlocks.Unlock()
+}
+
+func (ei *elasticProcessor) serializeAccountsRestore(revertedBlock *revertedBlockData, revertedEntries []*data.AccountBalanceHistory, buffSlice *data.BufferSlice) error {
+	addresses := make([]string, 0, len(revertedEntries))
+	for _, entry := range revertedEntries {
+		addresses = append(addresses, entry.Address)
+	}
+
+	query, err := ei.accountsProc.PrepareLatestAccountsHistoryQuery(addresses, revertedBlock.timestamp)
+	if err != nil {
+		return err
+	}
+
+	response := &data.ResponseAccountsHistory{}
+	ctxWithValue := context.WithValue(context.Background(), request.ContextKey, request.ExtendTopicWithShardID(request.GetTopic, revertedBlock.shardID))
+	err = ei.elasticClient.DoSearchRequest(ctxWithValue, elasticIndexer.AccountsHistoryIndex, query.Bytes(), response)
+	if err != nil {
+		return err
+	}
+
+	latestEntries := make(map[string]*data.AccountBalanceHistory, len(response.Hits.Hits))
+	for _, hit := range response.Hits.Hits {
+		if hit.Source != nil {
+			latestEntries[hit.Source.Address] = hit.Source
+		}
+	}
+
+	return ei.accountsProc.SerializeAccountsRestore(revertedBlock.timestamp, revertedEntries, latestEntries, buffSlice, elasticIndexer.AccountsIndex)
+}
+
+// restoreJournaledDocumentsInCaseOfRevert brings the documents updated by the reverted block to their previous versions
+func (ei *elasticProcessor) restoreJournaledDocumentsInCaseOfRevert(revertedBlock *revertedBlockData) error {
+	if !ei.isIndexEnabled(elasticIndexer.JournalIndex) {
+		return nil
+	}
+
+	entries := make([]*data.JournalEntry, 0)
+	handlerFunc := func(responseBytes []byte) error {
+		response := &data.ResponseJournalEntries{}
+		err := json.Unmarshal(responseBytes, response)
+		if err != nil {
+			return err
+		}
+
+		for _, hit := range response.Hits.Hits {
+			if hit.Source != nil {
+				entries = append(entries, hit.Source)
+			}
+		}
+
+		return nil
+	}
+
+	blockHash, shardID := revertedBlock.hash, revertedBlock.shardID
+	query := ei.journalProc.PrepareJournalQuery(blockHash)
+	ctxWithValue := context.WithValue(context.Background(), request.ContextKey, request.ExtendTopicWithShardID(request.ScrollTopic, shardID))
+	err := ei.refreshIndexOfLastWrittenBlock(ctxWithValue, revertedBlock, elasticIndexer.JournalIndex)
+	if err != nil {
+		return err
+	}
+
+	err = ei.elasticClient.DoScrollRequest(ctxWithValue, elasticIndexer.JournalIndex, query.Bytes(), true, handlerFunc)
+	if err != nil {
+		return err
+	}
+
+	buffSlice := data.NewBufferSlice(ei.bulkRequestMaxSize)
+	err = ei.journalProc.SerializeRestoreOperations(entries, buffSlice)
+	if err != nil {
+		return err
+	}
+
+	err = ei.doBulkRequests("", buffSlice.Buffers(), shardID)
+	if err != nil {
+		return err
+	}
+
+	return ei.removeJournalEntries(blockHash, shardID)
+}
+
+// RemoveJournalEntries will remove the journal entries of a block that became final, since it cannot be reverted anymore
+func (ei *elasticProcessor) RemoveJournalEntries(headerHash []byte, shardID uint32) error {
+	if !ei.isIndexEnabled(elasticIndexer.JournalIndex) {
+		return nil
+	}
+
+	return ei.removeJournalEntries(hex.EncodeToString(headerHash), shardID)
+}
+
+func (ei *elasticProcessor) removeJournalEntries(blockHash string, shardID uint32) error {
+	ctxWithValue := context.WithValue(context.Background(), request.ContextKey, request.ExtendTopicWithShardID(request.RemoveTopic, shardID))
+
+	return ei.elasticClient.DoQueryRemove(ctxWithValue, elasticIndexer.JournalIndex, ei.journalProc.PrepareJournalQuery(blockHash))
+}
+
+func (ei *elasticProcessor) removeTxLifecycleStagesInCaseOfRevert(blockHash string, shardID uint32) error {
+	if !ei.isIndexEnabled(elasticIndexer.TxLifecycleIndex) {
+		return nil
+	}
+
+	ctxWithValue := context.WithValue(context.Background(), request.ContextKey, request.ExtendTopicWithShardID(request.UpdateTopic, shardID))
+	query := ei.txLifecycleProc.PrepareTxLifecyclesQueryInCaseOfRevert(blockHash)
+
+	return ei.elasticClient.UpdateByQuery(ctxWithValue, elasticIndexer.TxLifecycleIndex, query)
+}
+
+func (ei *elasticProcessor) removeCallTreeNodesInCaseOfRevert(blockHash string, shardID uint32) error {
+	if !ei.isIndexEnabled(elasticIndexer.CallTreeIndex) {
+		return nil
+	}
+
+	ctxWithValue := context.WithValue(context.Background(), request.ContextKey, request.ExtendTopicWithShardID(request.UpdateTopic, shardID))
+	query := ei.callTreeProc.PrepareCallTreesQueryInCaseOfRevert(blockHash)
+
+	return ei.elasticClient.UpdateByQuery(ctxWithValue, elasticIndexer.CallTreeIndex, query)
+}
+
+func (ei *elasticProcessor) updateDelegatorsInCaseOfRevert(revertedBlock *revertedBlockData) error {
+	// delegators index should be updated in case of revert only if the observer is in Metachain and the reverted block has miniblocks
+	isMeta := revertedBlock.shardID == core.MetachainShardId
+	shouldUpdate := isMeta && revertedBlock.hasMiniblocks
+	if !shouldUpdate {
+		return nil
+	}
+
+	ctxWithValue := context.WithValue(context.Background(), request.ContextKey, request.ExtendTopicWithShardID(request.UpdateTopic, revertedBlock.shardID))
+	delegatorsQuery := ei.logsAndEventsProc.PrepareDelegatorsQueryInCaseOfRevert(revertedBlock.timestamp)
+	err := ei.elasticClient.UpdateByQuery(ctxWithValue, elasticIndexer.DelegatorsIndex, delegatorsQuery)
+	if err != nil {
+		return err
+	}
+
+	if !ei.isIndexEnabled(elasticIndexer.DelegationProvidersIndex) {
+		return nil
+	}
+
+	providersQuery := ei.logsAndEventsProc.PrepareDelegationProvidersQueryInCaseOfRevert(revertedBlock.timestamp)
+	return ei.elasticClient.UpdateByQuery(ctxWithValue, elasticIndexer.DelegationProvidersIndex, providersQuery)
+}
+
+func (ei *elasticProcessor) removeIfHashesNotEmpty(index string, hashes []string, shardID uint32) error {
+	if len(hashes) == 0 {
+		return nil
+	}
+
+	ctxWithValue := context.WithValue(context.Background(), request.ContextKey, request.ExtendTopicWithShardID(request.RemoveTopic, shardID))
+	return ei.elasticClient.DoQueryRemove(
+		ctxWithValue,
+		index,
+		converters.PrepareHashesForQueryRemove(hashes),
+	)
+}
+
+// RemoveAccountsDCDT will remove data from accountsdcdt index and accountsdcdthistory
+func (ei *elasticProcessor) RemoveAccountsDCDT(headerTimestamp uint64, shardID uint32) error {
+	err := ei.removeFromIndexByTimestampAndShardID(headerTimestamp, shardID, elasticIndexer.AccountsDCDTIndex)
+	if err != nil {
+		return err
+	}
+
+	return ei.removeFromIndexByTimestampAndShardID(headerTimestamp, shardID, elasticIndexer.AccountsDCDTHistoryIndex)
+}
+
+func (ei *elasticProcessor) removeFromIndexByTimestampAndShardID(headerTimestamp uint64, shardID uint32, index string) error {
+	ctxWithValue := context.WithValue(context.Background(), request.ContextKey, request.ExtendTopicWithShardID(request.RemoveTopic, shardID))
+	query := fmt.Sprintf(`{"query": {"bool": {"must": [{"match": {"shardID": {"query": %d,"operator": "AND"}}},{"match": {"timestamp": {"query": "%d","operator": "AND"}}}]}}}`, shardID, headerTimestamp)
+
+	return ei.elasticClient.DoQueryRemove(
+		ctxWithValue,
+		index,
+		bytes.NewBuffer([]byte(query)),
+	)
+}
+
+// SaveMiniblocks will prepare and save information about miniblocks in elasticsearch server
+func (ei *elasticProcessor) SaveMiniblocks(header coreData.HeaderHandler, miniBlocks []*block.MiniBlock) error {
+	if !ei.isIndexEnabled(elasticIndexer.MiniblocksIndex) {
+		return nil
+	}
+
+	mbs := ei.miniblocksProc.PrepareDBMiniblocks(header, miniBlocks)
+	if len(mbs) == 0 {
+		return nil
+	}
+
+	buffSlice := data.NewBufferSlice(ei.bulkRequestMaxSize)
+	ei.miniblocksProc.SerializeBulkMiniBlocks(mbs, buffSlice, elasticIndexer.MiniblocksIndex, header.GetShardID())
+
+	return ei.doBulkRequests("", buffSlice.Buffers(), header.GetShardID())
+}
+
+// SaveTransactions will prepare and save information about a transactions in elasticsearch server
+func (ei *elasticProcessor) SaveTransactions(obh *outport.OutportBlockWithHeader) error {
+	timer := stagetimings.NewBlockTimer(obh.ShardID, obh.Header.GetNonce(), hex.EncodeToString(obh.BlockData.HeaderHash))
+	err := ei.saveTransactions(obh, timer)
+	if err != nil {
+		return err
+	}
+
+	ei.setLastWrittenBlock(obh.ShardID, hex.EncodeToString(obh.BlockData.HeaderHash))
+	timer.Finish()
+	if !check.IfNil(ei.stagesRecorder) {
+		ei.stagesRecorder.RecordBlockStages(timer)
+	}
+
+	return nil
+}
+
+func (ei *elasticProcessor) saveTransactions(obh *outport.OutportBlockWithHeader, timer *stagetimings.BlockTimer) error {
+	headerTimestamp := obh.Header.GetTimeStamp()
+
+	miniBlocks := append(obh.BlockData.Body.MiniBlocks, obh.BlockData.IntraShardMiniBlocks...)
+	stopStage := timer.StartStage(stagetimings.PrepareTransactionsStage)
+	preparedResults := ei.transactionsProc.PrepareTransactionsForDatabase(miniBlocks, obh.Header, obh.TransactionPool, ei.isImportDB(), obh.NumberOfShards)
+	stopStage()
+
+	stopStage = timer.StartStage(stagetimings.ExtractDataFromLogsStage)
+	logsData := ei.logsAndEventsProc.ExtractDataFromLogs(obh.TransactionPool.Logs, preparedResults, headerTimestamp, obh.Header.GetShardID(), obh.NumberOfShards)
+	stopStage()
+
+	buffers := data.NewBufferSlice(ei.bulkRequestMaxSize)
+	err := ei.indexTransactions(preparedResults.Transactions, logsData.TxHashStatusInfo, obh.Header, buffers)
+	if err != nil {
+		return err
+	}
+
+	err = ei.prepareAndIndexOperations(preparedResults.Transactions, logsData.TxHashStatusInfo, obh.Header, preparedResults.ScResults, buffers, ei.isImportDB())
+	if err != nil {
+		return err
+	}
+
+	err = ei.indexTransactionsFeeData(preparedResults.TxHashFee, buffers)
+	if err != nil {
+		return err
+	}
+
+	stopStage = timer.StartStage(stagetimings.NFTCreateInfoStage)
+	err = ei.indexNFTCreateInfo(logsData.Tokens, obh.AlteredAccounts, buffers, obh.ShardID)
+	stopStage()
+	if err != nil {
+		return err
+	}
+
+	err = ei.prepareAndIndexLogs(obh.TransactionPool.Logs, headerTimestamp, buffers, obh.ShardID)
+	if err != nil {
+		return err
+	}
+
+	err = ei.indexScResults(preparedResults.ScResults, buffers)
+	if err != nil {
+		return err
+	}
+
+	err = ei.indexReceipts(preparedResults.Receipts, buffers)
+	if err != nil {
+		return err
+	}
+
+	// the accounts history needs the indexed accounts, and the epoch statistics reuse them instead of looking them up
+	var indexedAccounts map[string]*data.AccountInfo
+	if ei.isAccountsHistoryRestorable() {
+		indexedAccounts, err = ei.getIndexedAlteredAccounts(obh)
+		if err != nil {
+			return err
+		}
+	}
+
+	tagsCount := tags.NewTagsCount()
+	stopStage = timer.StartStage(stagetimings.AlteredAccountsStage)
+	err = ei.indexAlteredAccounts(headerTimestamp, logsData.NFTsDataUpdates, obh.AlteredAccounts, indexedAccounts, buffers, tagsCount, obh.Header.GetShardID(), timer)
+	stopStage()
+	if err != nil {
+		return err
+	}
+
+	err = ei.prepareAndIndexTagsCount(tagsCount, buffers)
+	if err != nil {
+		return err
+	}
+
+	err = ei.indexTokens(logsData.TokensInfo, logsData.NFTsDataUpdates, buffers, obh.ShardID, timer)
+	if err != nil {
+		return err
+	}
+
+	err = ei.prepareAndIndexDelegators(logsData.Delegators, buffers)
+	if err != nil {
+		return err
+	}
+
+	err = ei.prepareAndIndexDelegationProviders(logsData.DelegationProviders, buffers)
+	if err != nil {
+		return err
+	}
+
+	stopStage = timer.StartStage(stagetimings.NFTBurnInfoStage)
+	err = ei.indexNFTBurnInfo(logsData.TokensSupply, buffers, obh.ShardID)
+	stopStage()
+	if err != nil {
+		return err
+	}
+
+	err = ei.prepareAndIndexRolesData(logsData.TokenRolesAndProperties, buffers, elasticIndexer.TokensIndex)
+	if err != nil {
+		return err
+	}
+	err = ei.prepareAndIndexRolesData(logsData.TokenRolesAndProperties, buffers, elasticIndexer.DCDTsIndex)
+	if err != nil {
+		return err
+	}
+
+	err = ei.indexScDeploys(logsData.ScDeploys, logsData.ChangeOwnerOperations, buffers)
+	if err != nil {
+		return err
+	}
+
+	err = ei.prepareAndIndexEpochStats(obh, preparedResults, logsData, indexedAccounts, buffers)
+	if err != nil {
+		return err
+	}
+
+	err = ei.prepareAndIndexContractStats(obh, preparedResults, logsData.TxHashStatusInfo, buffers)
+	if err != nil {
+		return err
+	}
+
+	err = ei.prepareAndIndexTxLifecycles(obh, preparedResults, logsData.TxHashStatusInfo, buffers)
+	if err != nil {
+		return err
+	}
+
+	err = ei.prepareAndIndexCallTrees(obh, preparedResults, buffers)
+	if err != nil {
+		return err
+	}
+
+	stopStage = timer.StartStage(stagetimings.JournalStage)
+	journaledDocsIDs, err := ei.journalPreviousVersions(obh, buffers.Buffers())
+	stopStage()
+	if err != nil {
+		return err
+	}
+
+	stopStage = timer.StartStage(stagetimings.BulkRequestsStage)
+	err = ei.doBulkRequests("", buffers.Buffers(), obh.ShardID)
+	stopStage()
+	if err != nil {
+		return err
+	}
+
+	stopStage = timer.StartStage(stagetimings.JournalStage)
+	defer stopStage()
+
+	return ei.journalWrittenVersions(obh, journaledDocsIDs)
+}
+
+// journalPreviousVersions saves the current versions of the documents that are about to be updated by the block, so
+// they can be restored if the block is reverted. It returns the identifiers of the journaled documents, grouped by index
+func (ei *elasticProcessor) journalPreviousVersions(obh *outport.OutportBlockWithHeader, buffers []*bytes.Buffer) (map[string][]string, error) {
+	// the blocks are never reverted in import-db mode, so there is nothing to journal
+	if !ei.isIndexEnabled(elasticIndexer.JournalIndex) || ei.isImportDB() {
+		return nil, nil
+	}
+
+	docsIDs, err := ei.journalProc.ExtractDocumentsIDs(buffers, journaledIndexes)
+	if err != nil {
+		return nil, err
+	}
+	increments, err := ei.journalProc.ExtractCountersIncrements(buffers, journaledCounterFields)
+	if err != nil {
+		return nil, err
+	}
+
+	blockHash := hex.EncodeToString(obh.BlockData.HeaderHash)
+	buffSlice := data.NewBufferSlice(ei.bulkRequestMaxSize)
+	for _, index := range sortedJournaledIndices(docsIDs) {
+		response := &data.ResponseRawDocuments{}
+		err = ei.multiGetJournaledDocuments(obh.ShardID, index, docsIDs[index], response)
+		if err != nil {
+			return nil, err
+		}
+
+		entries := ei.journalProc.PrepareJournalEntries(blockHash, obh.ShardID, obh.Header.GetTimeStamp(), index, docsIDs[index], response)
+		for _, entry := range entries {
+			entry.Increments = increments[index][entry.DocID]
+		}
+		err = ei.journalProc.SerializeJournalEntries(entries, buffSlice, elasticIndexer.JournalIndex)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return docsIDs, ei.doBulkRequests("", buffSlice.Buffers(), obh.ShardID)
+}
+
+// journalWrittenVersions adds to the journal entries of the block the versions of the documents written by it. On
+// revert, they tell which fields were changed by the block and whether other shards changed them afterwards
+func (ei *elasticProcessor) journalWrittenVersions(obh *outport.OutportBlockWithHeader, docsIDs map[string][]string) error {
+	if len(docsIDs) == 0 {
+		return nil
+	}
+
+	blockHash := hex.EncodeToString(obh.BlockData.HeaderHash)
+	buffSlice := data.NewBufferSlice(ei.bulkRequestMaxSize)
+	for _, index := range sortedJournaledIndices(docsIDs) {
+		response := &data.ResponseRawDocuments{}
+		err := ei.multiGetJournaledDocuments(obh.ShardID, index, docsIDs[index], response)
+		if err != nil {
+			return err
+		}
+
+		err = ei.journalProc.SerializeWrittenVersions(blockHash, index, response, buffSlice, elasticIndexer.JournalIndex)
+		if err != nil {
+			return err
+		}
+	}
+
+	return ei.doBulkRequests("", buffSlice.Buffers(), obh.ShardID)
+}
 
-	return ei.updateDelegatorsInCaseOfRevert(header, body)
+func (ei *elasticProcessor) multiGetJournaledDocuments(shardID uint32, index string, ids []string, response *data.ResponseRawDocuments) error {
+	ctxWithValue := context.WithValue(context.Background(), request.ContextKey, request.ExtendTopicWithShardID(request.GetTopic, shardID))
+	return ei.elasticClient.DoMultiGet(ctxWithValue, ids, index, true, response)
 }
 
-func (ei *elasticProcessor) updateDelegatorsInCaseOfRevert(header coreData.HeaderHandler, body *block.Body) error {
-	// delegators index should be updated in case of revert only if the observer is in Metachain and the reverted block has miniblocks
-	isMeta := header.GetShardID() == core.MetachainShardId
-	hasMiniblocks := len(body.MiniBlocks) > 0
-	shouldUpdate := isMeta && hasMiniblocks
-	if !shouldUpdate {
-		return nil
+func sortedJournaledIndices(docsIDs map[string][]string) []string {
+	journaledIndices := make([]string, 0, len(docsIDs))
+	for index := range docsIDs {
+		journaledIndices = append(journaledIndices, index)
 	}
+	sort.Strings(journaledIndices)
 
-	ctxWithValue := context.WithValue(context.Background(), request.ContextKey, request.ExtendTopicWithShardID(request.UpdateTopic, header.GetShardID()))
-	delegatorsQuery := ei.logsAndEventsProc.PrepareDelegatorsQueryInCaseOfRevert(header.GetTimeStamp())
-	return ei.elasticClient.UpdateByQuery(ctxWithValue, elasticIndexer.DelegatorsIndex, delegatorsQuery)
+	return journaledIndices
 }
 
-func (ei *elasticProcessor) removeIfHashesNotEmpty(index string, hashes []string, shardID uint32) error {
-	if len(hashes) == 0 {
+func (ei *elasticProcessor) prepareAndIndexTxLifecycles(
+	obh *outport.OutportBlockWithHeader,
+	preparedResults *data.PreparedResults,
+	txHashStatusInfo map[string]*outport.StatusInfo,
+	buffSlice *data.BufferSlice,
+) error {
+	if !ei.isIndexEnabled(elasticIndexer.TxLifecycleIndex) {
 		return nil
 	}
 
-	ctxWithValue := context.WithValue(context.Background(), request.ContextKey, request.ExtendTopicWithShardID(request.RemoveTopic, shardID))
-	return ei.elasticClient.DoQueryRemove(
-		ctxWithValue,
-		index,
-		converters.PrepareHashesForQueryRemove(hashes),
-	)
+	blockHash := hex.EncodeToString(obh.BlockData.HeaderHash)
+	lifecycles := ei.txLifecycleProc.PrepareTxLifecycles(obh.Header, blockHash, preparedResults.Transactions, preparedResults.ScResults, txHashStatusInfo)
+
+	return ei.txLifecycleProc.SerializeTxLifecycles(lifecycles, buffSlice, elasticIndexer.TxLifecycleIndex)
 }
 
-// RemoveAccountsDCDT will remove data from accountsdcdt index and accountsdcdthistory
-func (ei *elasticProcessor) RemoveAccountsDCDT(headerTimestamp uint64, shardID uint32) error {
-	err := ei.removeFromIndexByTimestampAndShardID(headerTimestamp, shardID, elasticIndexer.AccountsDCDTIndex)
-	if err != nil {
-		return err
+func (ei *elasticProcessor) prepareAndIndexCallTrees(
+	obh *outport.OutportBlockWithHeader,
+	preparedResults *data.PreparedResults,
+	buffSlice *data.BufferSlice,
+) error {
+	if !ei.isIndexEnabled(elasticIndexer.CallTreeIndex) {
+		return nil
 	}
 
-	return ei.removeFromIndexByTimestampAndShardID(headerTimestamp, shardID, elasticIndexer.AccountsDCDTHistoryIndex)
-}
-
-func (ei *elasticProcessor) removeFromIndexByTimestampAndShardID(headerTimestamp uint64, shardID uint32, index string) error {
-	ctxWithValue := context.WithValue(context.Background(), request.ContextKey, request.ExtendTopicWithShardID(request.RemoveTopic, shardID))
-	query := fmt.Sprintf(`{"query": {"bool": {"must": [{"match": {"shardID": {"query": %d,"operator": "AND"}}},{"match": {"timestamp": {"query": "%d","operator": "AND"}}}]}}}`, shardID, headerTimestamp)
+	blockHash := hex.EncodeToString(obh.BlockData.HeaderHash)
+	callTrees := ei.callTreeProc.PrepareCallTrees(obh.Header, blockHash, preparedResults.Transactions, preparedResults.ScResults, obh.TransactionPool.Logs)
 
-	return ei.elasticClient.DoQueryRemove(
-		ctxWithValue,
-		index,
-		bytes.NewBuffer([]byte(query)),
-	)
+	return ei.callTreeProc.SerializeCallTrees(callTrees, buffSlice, elasticIndexer.CallTreeIndex)
 }
 
-// SaveMiniblocks will prepare and save information about miniblocks in elasticsearch server
-func (ei *elasticProcessor) SaveMiniblocks(header coreData.HeaderHandler, miniBlocks []*block.MiniBlock) error {
-	if !ei.isIndexEnabled(elasticIndexer.MiniblocksIndex) {
+// indexEpochStatsOfEmptyBlock adds to the epoch statistics the blocks without miniblocks, since their transactions are
+// not saved. The statistics of the other blocks are added together with their transactions
+func (ei *elasticProcessor) indexEpochStatsOfEmptyBlock(obh *outport.OutportBlockWithHeader, buffSlice *data.BufferSlice) error {
+	if len(obh.BlockData.Body.GetMiniBlocks()) > 0 {
 		return nil
 	}
 
-	mbs := ei.miniblocksProc.PrepareDBMiniblocks(header, miniBlocks)
-	if len(mbs) == 0 {
+	if !ei.isIndexEnabled(elasticIndexer.EpochStatsIndex) {
 		return nil
 	}
 
-	buffSlice := data.NewBufferSlice(ei.bulkRequestMaxSize)
-	ei.miniblocksProc.SerializeBulkMiniBlocks(mbs, buffSlice, elasticIndexer.MiniblocksIndex, header.GetShardID())
-
-	return ei.doBulkRequests("", buffSlice.Buffers(), header.GetShardID())
+	return ei.prepareAndIndexEpochStats(obh, &data.PreparedResults{}, nil, nil, buffSlice)
 }
 
-// SaveTransactions will prepare and save information about a transactions in elasticsearch server
-func (ei *elasticProcessor) SaveTransactions(obh *outport.OutportBlockWithHeader) error {
-	headerTimestamp := obh.Header.GetTimeStamp()
-
-	miniBlocks := append(obh.BlockData.Body.MiniBlocks, obh.BlockData.IntraShardMiniBlocks...)
-	preparedResults := ei.transactionsProc.PrepareTransactionsForDatabase(miniBlocks, obh.Header, obh.TransactionPool, ei.isImportDB(), obh.NumberOfShards)
-	logsData := ei.logsAndEventsProc.ExtractDataFromLogs(obh.TransactionPool.Logs, preparedResults, headerTimestamp, obh.Header.GetShardID(), obh.NumberOfShards)
-
-	buffers := data.NewBufferSlice(ei.bulkRequestMaxSize)
-	err := ei.indexTransactions(preparedResults.Transactions, logsData.TxHashStatusInfo, obh.Header, buffers)
-	if err != nil {
-		return err
+func (ei *elasticProcessor) prepareAndIndexEpochStats(
+	obh *outport.OutportBlockWithHeader,
+	preparedResults *data.PreparedResults,
+	logsData *data.PreparedLogsResults,
+	indexedAccounts map[string]*data.AccountInfo,
+	buffSlice *data.BufferSlice,
+) error {
+	if !ei.isIndexEnabled(elasticIndexer.EpochStatsIndex) {
+		return nil
 	}
 
-	err = ei.prepareAndIndexOperations(preparedResults.Transactions, logsData.TxHashStatusInfo, obh.Header, preparedResults.ScResults, buffers, ei.isImportDB())
+	blockHash := hex.EncodeToString(obh.BlockData.HeaderHash)
+	previousContribution, err := ei.getStatsContribution(elasticIndexer.EpochStatsIndex, blockHash, obh.ShardID)
 	if err != nil {
 		return err
 	}
 
-	err = ei.indexTransactionsFeeData(preparedResults.TxHashFee, buffers)
+	newAccounts, err := ei.computeNewAccounts(obh, previousContribution, indexedAccounts)
 	if err != nil {
 		return err
 	}
 
-	err = ei.indexNFTCreateInfo(logsData.Tokens, obh.AlteredAccounts, buffers, obh.ShardID)
+	epochStats := ei.epochStatsProc.PrepareEpochStats(obh.Header, preparedResults, logsData, newAccounts)
+	contribution, err := ei.statsContributionsProc.PrepareStatsContribution(elasticIndexer.EpochStatsIndex, blockHash, obh.ShardID, obh.Header.GetTimeStamp(), epochStats)
 	if err != nil {
 		return err
 	}
 
-	err = ei.prepareAndIndexLogs(obh.TransactionPool.Logs, headerTimestamp, buffers, obh.ShardID)
-	if err != nil {
-		return err
-	}
+	return ei.serializeStatsWithContribution(contribution, previousContribution, func() error {
+		return ei.epochStatsProc.SerializeEpochStats(epochStats, buffSlice, elasticIndexer.EpochStatsIndex)
+	}, buffSlice)
+}
 
-	err = ei.indexScResults(preparedResults.ScResults, buffers)
-	if err != nil {
-		return err
+// computeNewAccounts returns the number of altered accounts that are not in the accounts index yet. When the block was
+// already indexed and not reverted, the accounts it created exist, so the number from its previous contribution is kept
+// and the accounts are not looked up
+func (ei *elasticProcessor) computeNewAccounts(
+	obh *outport.OutportBlockWithHeader,
+	previousContribution *data.StatsContribution,
+	indexedAccounts map[string]*data.AccountInfo,
+) (uint64, error) {
+	if previousContribution != nil {
+		previousStats := &data.EpochStats{}
+		err := json.Unmarshal(previousContribution.Stats, previousStats)
+
+		return previousStats.NewAccounts, err
+	}
+	if !ei.isIndexEnabled(elasticIndexer.AccountsIndex) {
+		return 0, nil
 	}
 
-	err = ei.indexReceipts(preparedResults.Receipts, buffers)
-	if err != nil {
-		return err
+	if indexedAccounts == nil {
+		var err error
+		indexedAccounts, err = ei.getIndexedAlteredAccounts(obh)
+		if err != nil {
+			return 0, err
+		}
 	}
 
-	tagsCount := tags.NewTagsCount()
-	err = ei.indexAlteredAccounts(headerTimestamp, logsData.NFTsDataUpdates, obh.AlteredAccounts, buffers, tagsCount, obh.Header.GetShardID())
-	if err != nil {
-		return err
+	newAccounts := uint64(0)
+	for address := range obh.AlteredAccounts {
+		if _, found := indexedAccounts[address]; !found {
+			newAccounts++
+		}
 	}
 
-	err = ei.prepareAndIndexTagsCount(tagsCount, buffers)
-	if err != nil {
-		return err
+	return newAccounts, nil
+}
+
+// isAccountsHistoryRestorable returns true if the accounts can be restored from their history when a block is reverted,
+// so the history entries need the accounts as they were indexed before the block
+func (ei *elasticProcessor) isAccountsHistoryRestorable() bool {
+	return ei.isIndexEnabled(elasticIndexer.AccountsHistoryIndex) && ei.isIndexEnabled(elasticIndexer.AccountsIndex)
+}
+
+// getIndexedAlteredAccounts returns the altered accounts of the block that are in the accounts index, as they were
+// indexed before the block
+func (ei *elasticProcessor) getIndexedAlteredAccounts(obh *outport.OutportBlockWithHeader) (map[string]*data.AccountInfo, error) {
+	addresses := make([]string, 0, len(obh.AlteredAccounts))
+	for address := range obh.AlteredAccounts {
+		addresses = append(addresses, address)
 	}
 
-	err = ei.indexTokens(logsData.TokensInfo, logsData.NFTsDataUpdates, buffers, obh.ShardID)
-	if err != nil {
-		return err
+	return ei.getIndexedAccounts(addresses, obh.ShardID)
+}
+
+// getIndexedAccounts returns the provided addresses that are in the accounts index, together with their documents
+func (ei *elasticProcessor) getIndexedAccounts(addresses []string, shardID uint32) (map[string]*data.AccountInfo, error) {
+	indexedAccounts := make(map[string]*data.AccountInfo)
+	if len(addresses) == 0 {
+		return indexedAccounts, nil
 	}
 
-	err = ei.prepareAndIndexDelegators(logsData.Delegators, buffers)
+	response := &data.ResponseRawDocuments{}
+	ctxWithValue := context.WithValue(context.Background(), request.ContextKey, request.ExtendTopicWithShardID(request.GetTopic, shardID))
+	err := ei.elasticClient.DoMultiGet(ctxWithValue, addresses, elasticIndexer.AccountsIndex, true, response)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	err = ei.indexNFTBurnInfo(logsData.TokensSupply, buffers, obh.ShardID)
-	if err != nil {
-		return err
+	for _, doc := range response.Docs {
+		if !doc.Found {
+			continue
+		}
+
+		account := &data.AccountInfo{}
+		if len(doc.Source) > 0 {
+			err = json.Unmarshal(doc.Source, account)
+			if err != nil {
+				return nil, err
+			}
+		}
+		indexedAccounts[doc.ID] = account
 	}
 
-	err = ei.prepareAndIndexRolesData(logsData.TokenRolesAndProperties, buffers, elasticIndexer.TokensIndex)
-	if err != nil {
-		return err
+	return indexedAccounts, nil
+}
+
+func (ei *elasticProcessor) prepareAndIndexContractStats(
+	obh *outport.OutportBlockWithHeader,
+	preparedResults *data.PreparedResults,
+	txHashStatusInfo map[string]*outport.StatusInfo,
+	buffSlice *data.BufferSlice,
+) error {
+	if !ei.isIndexEnabled(elasticIndexer.ContractStatsIndex) {
+		return nil
 	}
-	err = ei.prepareAndIndexRolesData(logsData.TokenRolesAndProperties, buffers, elasticIndexer.DCDTsIndex)
+
+	blockHash := hex.EncodeToString(obh.BlockData.HeaderHash)
+	previousContribution, err := ei.getStatsContribution(elasticIndexer.ContractStatsIndex, blockHash, obh.ShardID)
 	if err != nil {
 		return err
 	}
 
-	err = ei.indexScDeploys(logsData.ScDeploys, logsData.ChangeOwnerOperations, buffers)
+	statsMap := ei.contractStatsProc.PrepareContractStats(preparedResults.Transactions, preparedResults.ScResults, txHashStatusInfo, obh.ShardID)
+	contribution, err := ei.statsContributionsProc.PrepareStatsContribution(elasticIndexer.ContractStatsIndex, blockHash, obh.ShardID, obh.Header.GetTimeStamp(), statsMap)
 	if err != nil {
 		return err
 	}
 
-	return ei.doBulkRequests("", buffers.Buffers(), obh.ShardID)
+	return ei.serializeStatsWithContribution(contribution, previousContribution, func() error {
+		return ei.contractStatsProc.SerializeContractStats(statsMap, buffSlice, elasticIndexer.ContractStatsIndex)
+	}, buffSlice)
 }
 
 func (ei *elasticProcessor) prepareAndIndexRolesData(tokenRolesAndProperties *tokeninfo.TokenRolesAndProperties, buffSlice *data.BufferSlice, index string) error {
@@ -496,6 +1365,14 @@ func (ei *elasticProcessor) prepareAndIndexRolesData(tokenRolesAndProperties *to
 	return ei.logsAndEventsProc.SerializeRolesData(tokenRolesAndProperties, buffSlice, index)
 }
 
+func (ei *elasticProcessor) prepareAndIndexDelegationProviders(updates map[string]*data.DelegationProviderUpdate, buffSlice *data.BufferSlice) error {
+	if !ei.isIndexEnabled(elasticIndexer.DelegationProvidersIndex) {
+		return nil
+	}
+
+	return ei.logsAndEventsProc.SerializeDelegationProviders(updates, buffSlice, elasticIndexer.DelegationProvidersIndex)
+}
+
 func (ei *elasticProcessor) prepareAndIndexDelegators(delegators map[string]*data.Delegator, buffSlice *data.BufferSlice) error {
 	if !ei.isIndexEnabled(elasticIndexer.DelegatorsIndex) {
 		return nil
@@ -577,6 +1454,11 @@ func (ei *elasticProcessor) prepareAndIndexOperations(
 
 // SaveValidatorsRating will save validators rating
 func (ei *elasticProcessor) SaveValidatorsRating(ratingData *outport.ValidatorsRating) error {
+	err := ei.indexValidatorsRatingHistory(ratingData)
+	if err != nil {
+		return err
+	}
+
 	if !ei.isIndexEnabled(elasticIndexer.RatingIndex) {
 		return nil
 	}
@@ -591,6 +1473,12 @@ func (ei *elasticProcessor) SaveValidatorsRating(ratingData *outport.ValidatorsR
 
 // SaveShardValidatorsPubKeys will prepare and save information about a shard validators public keys in elasticsearch server
 func (ei *elasticProcessor) SaveShardValidatorsPubKeys(validatorsPubKeys *outport.ValidatorsPubKeys) error {
+	// the history is computed before saving the new keys, against the keys of the previous epoch from the validators index
+	err := ei.indexValidatorsHistory(validatorsPubKeys)
+	if err != nil {
+		return err
+	}
+
 	if !ei.isIndexEnabled(elasticIndexer.ValidatorsIndex) {
 		return nil
 	}
@@ -603,8 +1491,132 @@ func (ei *elasticProcessor) SaveShardValidatorsPubKeys(validatorsPubKeys *outpor
 	return ei.doBulkRequests(elasticIndexer.ValidatorsIndex, buffSlice, validatorsPubKeys.ShardID)
 }
 
+func (ei *elasticProcessor) indexValidatorsRatingHistory(ratingData *outport.ValidatorsRating) error {
+	if !ei.isIndexEnabled(elasticIndexer.RatingHistoryIndex) {
+		return nil
+	}
+
+	buffSlice, err := ei.validatorsProc.SerializeValidatorsRatingHistory(ratingData)
+	if err != nil {
+		return err
+	}
+
+	return ei.doBulkRequests(elasticIndexer.RatingHistoryIndex, buffSlice, ratingData.ShardID)
+}
+
+func (ei *elasticProcessor) indexValidatorsHistory(validatorsPubKeys *outport.ValidatorsPubKeys) error {
+	if !ei.isIndexEnabled(elasticIndexer.ValidatorsHistoryIndex) {
+		return nil
+	}
+
+	previousPubKeys, err := ei.getPreviousEpochValidatorsPubKeys(validatorsPubKeys)
+	if err != nil {
+		return err
+	}
+
+	validatorsHistory := ei.validatorsProc.PrepareValidatorsHistory(validatorsPubKeys, previousPubKeys)
+	if len(validatorsHistory) == 0 {
+		return nil
+	}
+
+	buffSlice, err := ei.validatorsProc.SerializeValidatorsHistory(validatorsHistory)
+	if err != nil {
+		return err
+	}
+
+	return ei.doBulkRequests(elasticIndexer.ValidatorsHistoryIndex, buffSlice, validatorsPubKeys.ShardID)
+}
+
+func (ei *elasticProcessor) getPreviousEpochValidatorsPubKeys(validatorsPubKeys *outport.ValidatorsPubKeys) (map[uint32][]string, error) {
+	previousPubKeys := make(map[uint32][]string)
+	if validatorsPubKeys.Epoch == 0 {
+		return previousPubKeys, nil
+	}
+
+	ids := make([]string, 0, len(validatorsPubKeys.ShardValidatorsPubKeys))
+	shardIDByDocID := make(map[string]uint32, len(validatorsPubKeys.ShardValidatorsPubKeys))
+	for shardID := range validatorsPubKeys.ShardValidatorsPubKeys {
+		id := consensusstats.ComputeValidatorsListID(shardID, validatorsPubKeys.Epoch-1)
+		ids = append(ids, id)
+		shardIDByDocID[id] = shardID
+	}
+
+	validatorsLists, err := ei.getValidatorsPubKeys(ids, validatorsPubKeys.ShardID)
+	if err != nil {
+		return nil, err
+	}
+
+	for id, publicKeys := range validatorsLists {
+		shardID, ok := shardIDByDocID[id]
+		if !ok {
+			continue
+		}
+
+		previousPubKeys[shardID] = publicKeys
+	}
+
+	missingShardIDs := make([]uint32, 0)
+	for shardID := range validatorsPubKeys.ShardValidatorsPubKeys {
+		if _, found := previousPubKeys[shardID]; !found {
+			missingShardIDs = append(missingShardIDs, shardID)
+		}
+	}
+	if len(missingShardIDs) == 0 {
+		return previousPubKeys, nil
+	}
+
+	// the list of the previous epoch was not indexed, so it is computed from the history recorded before this epoch
+	pubKeysFromHistory, err := ei.getValidatorsPubKeysFromHistory(missingShardIDs, validatorsPubKeys.Epoch, validatorsPubKeys.ShardID)
+	if err != nil {
+		return nil, err
+	}
+
+	for shardID, publicKeys := range pubKeysFromHistory {
+		previousPubKeys[shardID] = publicKeys
+	}
+
+	return previousPubKeys, nil
+}
+
+func (ei *elasticProcessor) getValidatorsPubKeysFromHistory(shardIDs []uint32, epoch uint32, shardID uint32) (map[uint32][]string, error) {
+	sort.Slice(shardIDs, func(i, j int) bool {
+		return shardIDs[i] < shardIDs[j]
+	})
+
+	validatorsHistory := make([]*data.ValidatorHistory, 0)
+	handlerFunc := func(responseBytes []byte) error {
+		response := &data.ResponseValidatorsHistory{}
+		err := json.Unmarshal(responseBytes, response)
+		if err != nil {
+			return err
+		}
+
+		for _, hit := range response.Hits.Hits {
+			if hit.Source != nil {
+				validatorsHistory = append(validatorsHistory, hit.Source)
+			}
+		}
+
+		return nil
+	}
+
+	query := ei.validatorsProc.PrepareValidatorsHistoryQuery(shardIDs, epoch)
+	ctxWithValue := context.WithValue(context.Background(), request.ContextKey, request.ExtendTopicWithShardID(request.ScrollTopic, shardID))
+	err := ei.elasticClient.DoScrollRequest(ctxWithValue, elasticIndexer.ValidatorsHistoryIndex, query.Bytes(), true, handlerFunc)
+	if err != nil {
+		return nil, err
+	}
+
+	return ei.validatorsProc.ComputePubKeysFromHistory(validatorsHistory), nil
+}
+
 // SaveRoundsInfo will prepare and save information about a slice of rounds in elasticsearch server
 func (ei *elasticProcessor) SaveRoundsInfo(rounds *outport.RoundsInfo) error {
+	err := ei.indexConsensusStatsFromRounds(rounds)
+	if err != nil {
+		return err
+	}
+
 	if !ei.isIndexEnabled(elasticIndexer.RoundsIndex) {
 		return nil
 	}
@@ -619,18 +1631,20 @@ func (ei *elasticProcessor) indexAlteredAccounts(
 	timestamp uint64,
 	updatesNFTsData []*data.NFTDataUpdate,
 	coreAlteredAccounts map[string]*alteredAccount.AlteredAccount,
+	indexedAccounts map[string]*data.AccountInfo,
 	buffSlice *data.BufferSlice,
 	tagsCount data.CountTags,
 	shardID uint32,
+	timer *stagetimings.BlockTimer,
 ) error {
 	regularAccountsToIndex, accountsToIndexDCDT := ei.accountsProc.GetAccounts(coreAlteredAccounts)
 
-	err := ei.saveAccounts(timestamp, regularAccountsToIndex, buffSlice, shardID)
+	err := ei.saveAccounts(timestamp, regularAccountsToIndex, indexedAccounts, buffSlice, shardID)
 	if err != nil {
 		return err
 	}
 
-	return ei.saveAccountsDCDT(timestamp, accountsToIndexDCDT, updatesNFTsData, buffSlice, tagsCount, shardID)
+	return ei.saveAccountsDCDT(timestamp, accountsToIndexDCDT, updatesNFTsData, buffSlice, tagsCount, shardID, timer)
 }
 
 func (ei *elasticProcessor) saveAccountsDCDT(
@@ -640,9 +1654,12 @@ func (ei *elasticProcessor) saveAccountsDCDT(
 	buffSlice *data.BufferSlice,
 	tagsCount data.CountTags,
 	shardID uint32,
+	timer *stagetimings.BlockTimer,
 ) error {
 	accountsDCDTMap, tokensData := ei.accountsProc.PrepareAccountsMapDCDT(timestamp, wrappedAccounts, tagsCount, shardID)
+	stopStage := timer.StartStage(stagetimings.AccountsDCDTTokensLookupStage)
 	err := ei.addTokenTypeAndCurrentOwnerInAccountsDCDT(tokensData, accountsDCDTMap, shardID)
+	stopStage()
 	if err != nil {
 		return err
 	}
@@ -738,24 +1755,35 @@ func (ei *elasticProcessor) SaveAccounts(accountsData *outport.Accounts) error {
 	buffSlice := data.NewBufferSlice(ei.bulkRequestMaxSize)
 
 	accounts := make([]*data.Account, 0, len(accountsData.AlteredAccounts))
+	addresses := make([]string, 0, len(accountsData.AlteredAccounts))
 	for _, account := range accountsData.AlteredAccounts {
 		accounts = append(accounts, &data.Account{
 			UserAccount: account,
 			IsSender:    false,
 		})
+		addresses = append(addresses, account.Address)
+	}
+
+	var indexedAccounts map[string]*data.AccountInfo
+	if ei.isAccountsHistoryRestorable() {
+		var err error
+		indexedAccounts, err = ei.getIndexedAccounts(addresses, accountsData.ShardID)
+		if err != nil {
+			return err
+		}
 	}
 
-	return ei.saveAccounts(accountsData.BlockTimestamp, accounts, buffSlice, accountsData.ShardID)
+	return ei.saveAccounts(accountsData.BlockTimestamp, accounts, indexedAccounts, buffSlice, accountsData.ShardID)
 }
 
-func (ei *elasticProcessor) saveAccounts(timestamp uint64, accts []*data.Account, buffSlice *data.BufferSlice, shardID uint32) error {
+func (ei *elasticProcessor) saveAccounts(timestamp uint64, accts []*data.Account, indexedAccounts map[string]*data.AccountInfo, buffSlice *data.BufferSlice, shardID uint32) error {
 	accountsMap := ei.accountsProc.PrepareRegularAccountsMap(timestamp, accts, shardID)
 	err := ei.indexAccounts(accountsMap, elasticIndexer.AccountsIndex, buffSlice)
 	if err != nil {
 		return err
 	}
 
-	return ei.saveAccountsHistory(timestamp, accountsMap, buffSlice, shardID)
+	return ei.saveAccountsHistory(timestamp, accountsMap, indexedAccounts, buffSlice, shardID)
 }
 
 func (ei *elasticProcessor) indexAccounts(accountsMap map[string]*data.AccountInfo, index string, buffSlice *data.BufferSlice) error {
@@ -780,16 +1808,74 @@ func (ei *elasticProcessor) saveAccountsDCDTHistory(timestamp uint64, accountsIn
 	return ei.serializeAndIndexAccountsHistory(accountsMap, elasticIndexer.AccountsDCDTHistoryIndex, buffSlice)
 }
 
-func (ei *elasticProcessor) saveAccountsHistory(timestamp uint64, accountsInfoMap map[string]*data.AccountInfo, buffSlice *data.BufferSlice, shardID uint32) error {
+func (ei *elasticProcessor) saveAccountsHistory(
+	timestamp uint64,
+	accountsInfoMap map[string]*data.AccountInfo,
+	indexedAccounts map[string]*data.AccountInfo,
+	buffSlice *data.BufferSlice,
+	shardID uint32,
+) error {
 	if !ei.isIndexEnabled(elasticIndexer.AccountsHistoryIndex) {
 		return nil
 	}
 
 	accountsMap := ei.accountsProc.PrepareAccountsHistory(timestamp, accountsInfoMap, shardID)
+	if indexedAccounts != nil {
+		setPreviousVersionsInHistory(accountsMap, accountsInfoMap, indexedAccounts)
+	}
 
 	return ei.serializeAndIndexAccountsHistory(accountsMap, elasticIndexer.AccountsHistoryIndex, buffSlice)
 }
 
+// setPreviousVersionsInHistory flags the history entries of the accounts which are not in the accounts index yet, so a
+// revert of the block deletes only the accounts it created. The entries of the other accounts keep their previous state
+// only if the block changes it, so a revert restores the state without saving it for every block
+func setPreviousVersionsInHistory(
+	historyMap map[string]*data.AccountBalanceHistory,
+	accountsMap map[string]*data.AccountInfo,
+	indexedAccounts map[string]*data.AccountInfo,
+) {
+	for _, entry := range historyMap {
+		indexedAccount, found := indexedAccounts[entry.Address]
+		if !found {
+			entry.Created = true
+			continue
+		}
+
+		account, ok := accountsMap[entry.Address]
+		if !ok {
+			continue
+		}
+
+		previousState := accountHistoryState(indexedAccount)
+		if !isSameAccountHistoryState(previousState, accountHistoryState(account)) {
+			entry.PreviousState = previousState
+		}
+	}
+}
+
+func accountHistoryState(account *data.AccountInfo) *data.AccountHistoryState {
+	return &data.AccountHistoryState{
+		UserName:            account.UserName,
+		DeveloperRewards:    account.DeveloperRewards,
+		DeveloperRewardsNum: account.DeveloperRewardsNum,
+		CurrentOwner:        account.CurrentOwner,
+		RootHash:            account.RootHash,
+		CodeHash:            account.CodeHash,
+		CodeMetadata:        account.CodeMetadata,
+	}
+}
+
+func isSameAccountHistoryState(first *data.AccountHistoryState, second *data.AccountHistoryState) bool {
+	return first.UserName == second.UserName &&
+		first.DeveloperRewards == second.DeveloperRewards &&
+		first.DeveloperRewardsNum == second.DeveloperRewardsNum &&
+		first.CurrentOwner == second.CurrentOwner &&
+		bytes.Equal(first.RootHash, second.RootHash) &&
+		bytes.Equal(first.CodeHash, second.CodeHash) &&
+		bytes.Equal(first.CodeMetadata, second.CodeMetadata)
+}
+
 func (ei *elasticProcessor) serializeAndIndexAccountsHistory(accountsMap map[string]*data.AccountBalanceHistory, index string, buffSlice *data.BufferSlice) error {
 	return ei.accountsProc.SerializeAccountsHistory(accountsMap, buffSlice, index)
 }
@@ -811,10 +1897,67 @@ func (ei *elasticProcessor) indexReceipts(receipts []*data.Receipt, buffSlice *d
 }
 
 func (ei *elasticProcessor) isIndexEnabled(index string) bool {
+	ei.mutex.RLock()
+	defer ei.mutex.RUnlock()
+
 	_, isEnabled := ei.enabledIndexes[index]
 	return isEnabled
 }
 
+// EnableIndex will enable at runtime the indexing of the provided index
+func (ei *elasticProcessor) EnableIndex(index string) error {
+	if !isKnownIndex(index) {
+		return fmt.Errorf("%w: %s", elasticIndexer.ErrUnknownIndex, index)
+	}
+
+	ei.mutex.Lock()
+	ei.enabledIndexes[index] = struct{}{}
+	ei.mutex.Unlock()
+
+	log.Info("elasticProcessor.EnableIndex", "index", index)
+
+	return nil
+}
+
+// DisableIndex will disable at runtime the indexing of the provided index
+func (ei *elasticProcessor) DisableIndex(index string) error {
+	if !isKnownIndex(index) {
+		return fmt.Errorf("%w: %s", elasticIndexer.ErrUnknownIndex, index)
+	}
+
+	ei.mutex.Lock()
+	delete(ei.enabledIndexes, index)
+	ei.mutex.Unlock()
+
+	log.Info("elasticProcessor.DisableIndex", "index", index)
+
+	return nil
+}
+
+// GetEnabledIndexes returns the sorted list of the indexes that are currently enabled
+func (ei *elasticProcessor) GetEnabledIndexes() []string {
+	ei.mutex.RLock()
+	enabledIndexes := make([]string, 0, len(ei.enabledIndexes))
+	for index := range ei.enabledIndexes {
+		enabledIndexes = append(enabledIndexes, index)
+	}
+	ei.mutex.RUnlock()
+
+	sort.Strings(enabledIndexes)
+
+	return enabledIndexes
+}
+
+func isKnownIndex(index string) bool {
+	for _, knownIndex := range indexes {
+		if knownIndex == index {
+			return true
+		}
+	}
+
+	return false
+}
+
 func (ei *elasticProcessor) doBulkRequests(index string, buffSlice []*bytes.Buffer, shardID uint32) error {
 	var err error
 	for idx := range buffSlice {
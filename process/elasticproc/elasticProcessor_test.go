@@ -3,12 +3,16 @@ package elasticproc
 import (
 	"bytes"
 	"encoding/hex"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/kalyan3104/k-chain-core-go/core"
 	coreData "github.com/kalyan3104/k-chain-core-go/data"
+	"github.com/kalyan3104/k-chain-core-go/data/alteredAccount"
 	dataBlock "github.com/kalyan3104/k-chain-core-go/data/block"
 	"github.com/kalyan3104/k-chain-core-go/data/outport"
 	"github.com/kalyan3104/k-chain-core-go/data/transaction"
@@ -17,28 +21,47 @@ import (
 	"github.com/kalyan3104/k-chain-es-indexer-go/process/dataindexer"
 	"github.com/kalyan3104/k-chain-es-indexer-go/process/elasticproc/accounts"
 	"github.com/kalyan3104/k-chain-es-indexer-go/process/elasticproc/block"
+	"github.com/kalyan3104/k-chain-es-indexer-go/process/elasticproc/calltree"
+	"github.com/kalyan3104/k-chain-es-indexer-go/process/elasticproc/consensusstats"
+	"github.com/kalyan3104/k-chain-es-indexer-go/process/elasticproc/contractstats"
 	"github.com/kalyan3104/k-chain-es-indexer-go/process/elasticproc/converters"
+	"github.com/kalyan3104/k-chain-es-indexer-go/process/elasticproc/epochstats"
+	"github.com/kalyan3104/k-chain-es-indexer-go/process/elasticproc/journal"
 	"github.com/kalyan3104/k-chain-es-indexer-go/process/elasticproc/logsevents"
 	"github.com/kalyan3104/k-chain-es-indexer-go/process/elasticproc/miniblocks"
 	"github.com/kalyan3104/k-chain-es-indexer-go/process/elasticproc/operations"
+	"github.com/kalyan3104/k-chain-es-indexer-go/process/elasticproc/stagetimings"
 	"github.com/kalyan3104/k-chain-es-indexer-go/process/elasticproc/statistics"
+	"github.com/kalyan3104/k-chain-es-indexer-go/process/elasticproc/statscontributions"
 	"github.com/kalyan3104/k-chain-es-indexer-go/process/elasticproc/tags"
 	"github.com/kalyan3104/k-chain-es-indexer-go/process/elasticproc/transactions"
+	"github.com/kalyan3104/k-chain-es-indexer-go/process/elasticproc/txlifecycle"
 	"github.com/kalyan3104/k-chain-es-indexer-go/process/elasticproc/validators"
 	"github.com/stretchr/testify/require"
 )
 
 func newElasticsearchProcessor(elasticsearchWriter DatabaseClientHandler, arguments *ArgElasticProcessor) *elasticProcessor {
 	return &elasticProcessor{
-		elasticClient:     elasticsearchWriter,
-		enabledIndexes:    arguments.EnabledIndexes,
-		blockProc:         arguments.BlockProc,
-		transactionsProc:  arguments.TransactionsProc,
-		miniblocksProc:    arguments.MiniblocksProc,
-		accountsProc:      arguments.AccountsProc,
-		validatorsProc:    arguments.ValidatorsProc,
-		statisticsProc:    arguments.StatisticsProc,
-		logsAndEventsProc: arguments.LogsAndEventsProc,
+		elasticClient:          elasticsearchWriter,
+		enabledIndexes:         arguments.EnabledIndexes,
+		blockProc:              arguments.BlockProc,
+		transactionsProc:       arguments.TransactionsProc,
+		miniblocksProc:         arguments.MiniblocksProc,
+		accountsProc:           arguments.AccountsProc,
+		validatorsProc:         arguments.ValidatorsProc,
+		statisticsProc:         arguments.StatisticsProc,
+		logsAndEventsProc:      arguments.LogsAndEventsProc,
+		epochStatsProc:         arguments.EpochStatsProc,
+		contractStatsProc:      arguments.ContractStatsProc,
+		consensusStatsProc:     arguments.ConsensusStatsProc,
+		txLifecycleProc:        arguments.TxLifecycleProc,
+		callTreeProc:           arguments.CallTreeProc,
+		journalProc:            arguments.JournalProc,
+		statsContributionsProc: arguments.StatsContributionsProc,
+		stagesRecorder:         arguments.StagesRecorder,
+		contributionsRetention: arguments.ContributionsRetention,
+		lastWrittenBlocks:      make(map[uint32]string),
+		countedCallersDays:     make(map[uint32]time.Duration),
 	}
 }
 
@@ -73,20 +96,29 @@ func createMockElasticProcessorArgs() *ArgElasticProcessor {
 	}
 	lp, _ := logsevents.NewLogsAndEventsProcessor(args)
 	op, _ := operations.NewOperationsProcessor()
+	esp, _ := epochstats.NewEpochStatsProcessor(balanceConverter)
+	csp, _ := contractstats.NewContractStatsProcessor(&mock.PubkeyConverterMock{}, balanceConverter)
 
 	return &ArgElasticProcessor{
 		DBClient: &mock.DatabaseWriterStub{},
 		EnabledIndexes: map[string]struct{}{
 			dataindexer.BlockIndex: {}, dataindexer.TransactionsIndex: {}, dataindexer.MiniblocksIndex: {}, dataindexer.ValidatorsIndex: {}, dataindexer.RoundsIndex: {}, dataindexer.AccountsIndex: {}, dataindexer.RatingIndex: {}, dataindexer.AccountsHistoryIndex: {},
 		},
-		ValidatorsProc:    vp,
-		StatisticsProc:    statistics.NewStatisticsProcessor(),
-		TransactionsProc:  &mock.DBTransactionProcessorStub{},
-		MiniblocksProc:    mp,
-		AccountsProc:      acp,
-		BlockProc:         bp,
-		LogsAndEventsProc: lp,
-		OperationsProc:    op,
+		ValidatorsProc:         vp,
+		StatisticsProc:         statistics.NewStatisticsProcessor(),
+		TransactionsProc:       &mock.DBTransactionProcessorStub{},
+		MiniblocksProc:         mp,
+		AccountsProc:           acp,
+		BlockProc:              bp,
+		LogsAndEventsProc:      lp,
+		OperationsProc:         op,
+		EpochStatsProc:         esp,
+		ContractStatsProc:      csp,
+		ConsensusStatsProc:     consensusstats.NewConsensusStatsProcessor(),
+		TxLifecycleProc:        txlifecycle.NewTxLifecycleProcessor(),
+		CallTreeProc:           calltree.NewCallTreeProcessor(),
+		JournalProc:            journal.NewJournalProcessor(),
+		StatsContributionsProc: statscontributions.NewStatsContributionsProcessor(),
 	}
 }
 
@@ -191,6 +223,69 @@ func TestNewElasticProcessor(t *testing.T) {
 			},
 			exErr: dataindexer.ErrNilValidatorsHandler,
 		},
+		{
+			name: "NilEpochStatsProc",
+			args: func() *ArgElasticProcessor {
+				arguments := createMockElasticProcessorArgs()
+				arguments.EpochStatsProc = nil
+				return arguments
+			},
+			exErr: dataindexer.ErrNilEpochStatsHandler,
+		},
+		{
+			name: "NilContractStatsProc",
+			args: func() *ArgElasticProcessor {
+				arguments := createMockElasticProcessorArgs()
+				arguments.ContractStatsProc = nil
+				return arguments
+			},
+			exErr: dataindexer.ErrNilContractStatsHandler,
+		},
+		{
+			name: "NilConsensusStatsProc",
+			args: func() *ArgElasticProcessor {
+				arguments := createMockElasticProcessorArgs()
+				arguments.ConsensusStatsProc = nil
+				return arguments
+			},
+			exErr: dataindexer.ErrNilConsensusStatsHandler,
+		},
+		{
+			name: "NilTxLifecycleProc",
+			args: func() *ArgElasticProcessor {
+				arguments := createMockElasticProcessorArgs()
+				arguments.TxLifecycleProc = nil
+				return arguments
+			},
+			exErr: dataindexer.ErrNilTxLifecycleHandler,
+		},
+		{
+			name: "NilCallTreeProc",
+			args: func() *ArgElasticProcessor {
+				arguments := createMockElasticProcessorArgs()
+				arguments.CallTreeProc = nil
+				return arguments
+			},
+			exErr: dataindexer.ErrNilCallTreeHandler,
+		},
+		{
+			name: "NilJournalProc",
+			args: func() *ArgElasticProcessor {
+				arguments := createMockElasticProcessorArgs()
+				arguments.JournalProc = nil
+				return arguments
+			},
+			exErr: dataindexer.ErrNilJournalHandler,
+		},
+		{
+			name: "NilStatsContributionsProc",
+			args: func() *ArgElasticProcessor {
+				arguments := createMockElasticProcessorArgs()
+				arguments.StatsContributionsProc = nil
+				return arguments
+			},
+			exErr: dataindexer.ErrNilStatsContributionsHandler,
+		},
 		{
 			name: "NilTxsProc",
 			args: func() *ArgElasticProcessor {
@@ -336,6 +431,41 @@ func TestElasticseachDatabaseSaveHeader_RequestError(t *testing.T) {
 	require.Equal(t, localErr, err)
 }
 
+func TestElasticProcessor_SaveHeaderWithBlocksIndexDisabledShouldFinalizeTheEpochStats(t *testing.T) {
+	t.Parallel()
+
+	bulkBody := ""
+	dbWriter := &mock.DatabaseWriterStub{
+		DoBulkRequestCalled: func(buff *bytes.Buffer, index string) error {
+			bulkBody += buff.String()
+			return nil
+		},
+	}
+
+	elasticProc := newElasticsearchProcessor(dbWriter, createMockElasticProcessorArgs())
+	elasticProc.enabledIndexes = map[string]struct{}{
+		dataindexer.EpochStatsIndex: {},
+	}
+
+	outportBlock := createEmptyOutportBlockWithHeader()
+	outportBlock.ShardID = core.MetachainShardId
+	outportBlock.NumberOfShards = 1
+	outportBlock.Header = &dataBlock.MetaBlock{
+		Epoch:     3,
+		TimeStamp: 5000,
+		EpochStart: dataBlock.EpochStart{
+			LastFinalizedHeaders: []dataBlock.EpochStartShardData{{ShardID: 0}},
+		},
+	}
+	outportBlock.BlockData.HeaderHash = []byte("hash")
+
+	err := elasticProc.SaveHeader(outportBlock)
+	require.Nil(t, err)
+	require.NotContains(t, bulkBody, `"_index":"blocks"`)
+	require.Contains(t, bulkBody, `{ "update" : { "_index":"epochstats", "_id" : "0_2" } }`)
+	require.Contains(t, bulkBody, `{ "update" : { "_index":"epochstats", "_id" : "4294967295_2" } }`)
+}
+
 func TestElasticseachSaveTransactions(t *testing.T) {
 	localErr := errors.New("localErr")
 	arguments := createMockElasticProcessorArgs()
@@ -370,6 +500,46 @@ func TestElasticseachSaveTransactions(t *testing.T) {
 	require.Equal(t, localErr, err)
 }
 
+func TestElasticProcessor_SaveTransactionsShouldRecordStages(t *testing.T) {
+	t.Parallel()
+
+	var recordedTimer *stagetimings.BlockTimer
+	arguments := createMockElasticProcessorArgs()
+	arguments.StagesRecorder = &mock.BlockStagesRecorderStub{
+		RecordBlockStagesCalled: func(timer *stagetimings.BlockTimer) {
+			recordedTimer = timer
+		},
+	}
+	bc, _ := converters.NewBalanceConverter(18)
+	arguments.TransactionsProc, _ = transactions.NewTransactionsProcessor(&transactions.ArgsTransactionProcessor{
+		AddressPubkeyConverter: mock.NewPubkeyConverterMock(32),
+		Hasher:                 &mock.HasherMock{},
+		Marshalizer:            &mock.MarshalizerMock{},
+		BalanceConverter:       bc,
+	})
+	elasticDatabase := newElasticsearchProcessor(&mock.DatabaseWriterStub{}, arguments)
+
+	outportBlock := createEmptyOutportBlockWithHeader()
+	outportBlock.ShardID = 1
+	outportBlock.Header = &dataBlock.Header{Nonce: 5, ShardID: 1}
+	outportBlock.BlockData.HeaderHash = []byte("hash")
+	outportBlock.BlockData.Body = newTestBlockBody()
+
+	err := elasticDatabase.SaveTransactions(outportBlock)
+	require.Nil(t, err)
+	require.NotNil(t, recordedTimer)
+	require.Equal(t, uint32(1), recordedTimer.ShardID)
+	require.Equal(t, uint64(5), recordedTimer.Nonce)
+	require.Equal(t, hex.EncodeToString([]byte("hash")), recordedTimer.Hash)
+
+	stagesDurations := recordedTimer.StagesDurations()
+	require.Contains(t, stagesDurations, stagetimings.PrepareTransactionsStage)
+	require.Contains(t, stagesDurations, stagetimings.ExtractDataFromLogsStage)
+	require.Contains(t, stagesDurations, stagetimings.AlteredAccountsStage)
+	require.Contains(t, stagesDurations, stagetimings.AccountsDCDTTokensLookupStage)
+	require.Contains(t, stagesDurations, stagetimings.BulkRequestsStage)
+}
+
 func TestElasticProcessor_SaveValidatorsRating(t *testing.T) {
 	localErr := errors.New("localErr")
 
@@ -454,6 +624,49 @@ func TestElasticsearch_saveRoundInfoRequestError(t *testing.T) {
 
 }
 
+func TestElasticProcessor_SaveRoundsInfoShouldIndexConsensusStats(t *testing.T) {
+	arguments := createMockElasticProcessorArgs()
+
+	numMultiGetCalls := 0
+	called := false
+	dbWriter := &mock.DatabaseWriterStub{
+		DoMultiGetCalled: func(ids []string, index string, withSource bool, response interface{}) error {
+			numMultiGetCalls++
+			require.Equal(t, dataindexer.ValidatorsIndex, index)
+			require.Equal(t, []string{"0_1"}, ids)
+
+			validatorsResponse := response.(*data.ResponseValidatorsPublicKeys)
+			validatorsResponse.Docs = []*data.ResponseValidatorsPublicKeysDB{{
+				Found:  true,
+				ID:     "0_1",
+				Source: data.ValidatorsPublicKeys{PublicKeys: []string{"6b31", "6b32"}},
+			}}
+			return nil
+		},
+		DoBulkRequestCalled: func(buff *bytes.Buffer, index string) error {
+			called = true
+			require.Contains(t, buff.String(), `{ "update" : { "_index":"consensusstats", "_id" : "6b32_epoch_1" } }`)
+			return nil
+		},
+	}
+
+	elasticProc := newElasticsearchProcessor(dbWriter, arguments)
+	elasticProc.enabledIndexes = map[string]struct{}{dataindexer.ConsensusStatsIndex: {}}
+
+	rounds := &outport.RoundsInfo{RoundsInfo: []*outport.RoundInfo{
+		{Round: 1, SignersIndexes: []uint64{1, 0}, BlockWasProposed: true, ShardId: 0, Epoch: 1, Timestamp: 10},
+		{Round: 2, SignersIndexes: []uint64{1, 0}, BlockWasProposed: false, ShardId: 0, Epoch: 1, Timestamp: 16},
+	}}
+	err := elasticProc.SaveRoundsInfo(rounds)
+	require.Nil(t, err)
+	require.True(t, called)
+
+	// the validators list is cached, so it should not be requested again
+	err = elasticProc.SaveRoundsInfo(rounds)
+	require.Nil(t, err)
+	require.Equal(t, 1, numMultiGetCalls)
+}
+
 func TestElasticProcessor_RemoveTransactions(t *testing.T) {
 	arguments := createMockElasticProcessorArgs()
 
@@ -463,8 +676,13 @@ func TestElasticProcessor_RemoveTransactions(t *testing.T) {
 	dbWriter := &mock.DatabaseWriterStub{
 		DoQueryRemoveCalled: func(index string, body *bytes.Buffer) error {
 			bodyStr := body.String()
-			require.Contains(t, []string{dataindexer.TransactionsIndex, dataindexer.OperationsIndex, dataindexer.LogsIndex, dataindexer.EventsIndex}, index)
-			if index != dataindexer.EventsIndex {
+			require.Contains(t, []string{dataindexer.TransactionsIndex, dataindexer.OperationsIndex, dataindexer.LogsIndex, dataindexer.EventsIndex, dataindexer.AccountsHistoryIndex}, index)
+			if index == dataindexer.AccountsHistoryIndex {
+				require.Equal(t,
+					`{"query": {"bool": {"filter": [{"term": {"timestamp": {"value": 0}}}, {"term": {"shardID": {"value": 4294967295}}}]}}}`,
+					body.String(),
+				)
+			} else if index != dataindexer.EventsIndex {
 				require.True(t, strings.Contains(bodyStr, expectedHashes[0]))
 				require.True(t, strings.Contains(bodyStr, expectedHashes[1]))
 				called = true
@@ -510,6 +728,531 @@ func TestElasticProcessor_RemoveTransactions(t *testing.T) {
 	require.True(t, called)
 }
 
+func TestElasticProcessor_RemoveTransactionsShouldSubtractContractStats(t *testing.T) {
+	arguments := createMockElasticProcessorArgs()
+
+	header := &dataBlock.Header{ShardID: 0, MiniBlockHeaders: []dataBlock.MiniBlockHeader{{}}}
+	headerHash, _ := arguments.BlockProc.ComputeHeaderHash(header)
+	contributionID := "contractstats_" + hex.EncodeToString(headerHash)
+	contractAddress := hex.EncodeToString(append(make([]byte, 10), bytes.Repeat([]byte{1}, 22)...))
+	bulkCalled := false
+	dbWriter := &mock.DatabaseWriterStub{
+		DoMultiGetCalled: func(ids []string, index string, withSource bool, response interface{}) error {
+			// the statistics are subtracted from the saved contribution, the reverted documents are not read
+			require.Equal(t, dataindexer.StatsContributionsIndex, index)
+			require.Contains(t, ids, contributionID)
+			return json.Unmarshal([]byte(fmt.Sprintf(`{"docs":[{"found":true,"_id":"%s","_source":`+
+				`{"index":"contractstats","key":"%s","shardID":0,"stats":{"%s_0":{"address":"%s","calls":1,"callsByFunction":{"claim":1},"fees":"100"}}}}]}`,
+				contributionID, hex.EncodeToString(headerHash), contractAddress, contractAddress)), response)
+		},
+		DoBulkRequestCalled: func(buff *bytes.Buffer, index string) error {
+			require.True(t, strings.Contains(buff.String(), fmt.Sprintf(`"_id" : "%s_0"`, contractAddress)))
+			require.True(t, strings.Contains(buff.String(), "ctx.op = 'delete'"))
+			require.True(t, strings.Contains(buff.String(), fmt.Sprintf(`{ "delete" : { "_index": "statscontributions", "_id" : "%s" } }`, contributionID)))
+			bulkCalled = true
+			return nil
+		},
+	}
+
+	args := &transactions.ArgsTransactionProcessor{
+		AddressPubkeyConverter: mock.NewPubkeyConverterMock(32),
+		Hasher:                 &mock.HasherMock{},
+		Marshalizer:            &mock.MarshalizerMock{},
+	}
+	arguments.TransactionsProc, _ = transactions.NewTransactionsProcessor(args)
+
+	elasticSearchProc := newElasticsearchProcessor(dbWriter, arguments)
+	elasticSearchProc.enabledIndexes[dataindexer.ContractStatsIndex] = struct{}{}
+	elasticSearchProc.enabledIndexes[dataindexer.StatsContributionsIndex] = struct{}{}
+
+	blk := &dataBlock.Body{
+		MiniBlocks: dataBlock.MiniBlockSlice{
+			{
+				TxHashes: [][]byte{[]byte("txHash1")},
+				Type:     dataBlock.TxBlock,
+			},
+		},
+	}
+
+	err := elasticSearchProc.RemoveTransactions(header, blk)
+	require.Nil(t, err)
+	require.True(t, bulkCalled)
+}
+
+func TestElasticProcessor_RemoveTransactionsShouldRevertDelegationProviders(t *testing.T) {
+	arguments := createMockElasticProcessorArgs()
+
+	updatedIndices := make([]string, 0)
+	dbWriter := &mock.DatabaseWriterStub{
+		UpdateByQueryCalled: func(index string, buff *bytes.Buffer) error {
+			updatedIndices = append(updatedIndices, index)
+			require.True(t, strings.Contains(buff.String(), `"timestamp": "1234"`))
+			return nil
+		},
+	}
+
+	args := &transactions.ArgsTransactionProcessor{
+		AddressPubkeyConverter: mock.NewPubkeyConverterMock(32),
+		Hasher:                 &mock.HasherMock{},
+		Marshalizer:            &mock.MarshalizerMock{},
+	}
+	arguments.TransactionsProc, _ = transactions.NewTransactionsProcessor(args)
+
+	elasticSearchProc := newElasticsearchProcessor(dbWriter, arguments)
+	elasticSearchProc.enabledIndexes[dataindexer.DelegationProvidersIndex] = struct{}{}
+
+	header := &dataBlock.Header{ShardID: core.MetachainShardId, TimeStamp: 1234}
+	blk := &dataBlock.Body{
+		MiniBlocks: dataBlock.MiniBlockSlice{
+			{
+				Type: dataBlock.TxBlock,
+			},
+		},
+	}
+
+	err := elasticSearchProc.RemoveTransactions(header, blk)
+	require.Nil(t, err)
+	require.Equal(t, []string{dataindexer.DelegatorsIndex, dataindexer.DelegationProvidersIndex}, updatedIndices)
+}
+
+func TestElasticProcessor_RemoveTransactionsShouldRemoveTxLifecycleStagesAndCallTreeNodes(t *testing.T) {
+	arguments := createMockElasticProcessorArgs()
+
+	header := &dataBlock.Header{ShardID: 1, TimeStamp: 1234}
+	headerHash, _ := arguments.BlockProc.ComputeHeaderHash(header)
+
+	updatedIndices := make([]string, 0)
+	dbWriter := &mock.DatabaseWriterStub{
+		UpdateByQueryCalled: func(index string, buff *bytes.Buffer) error {
+			updatedIndices = append(updatedIndices, index)
+			require.True(t, strings.Contains(buff.String(), fmt.Sprintf(`.blockHash": "%s"`, hex.EncodeToString(headerHash))))
+			return nil
+		},
+	}
+
+	args := &transactions.ArgsTransactionProcessor{
+		AddressPubkeyConverter: mock.NewPubkeyConverterMock(32),
+		Hasher:                 &mock.HasherMock{},
+		Marshalizer:            &mock.MarshalizerMock{},
+	}
+	arguments.TransactionsProc, _ = transactions.NewTransactionsProcessor(args)
+
+	elasticSearchProc := newElasticsearchProcessor(dbWriter, arguments)
+	elasticSearchProc.enabledIndexes[dataindexer.TxLifecycleIndex] = struct{}{}
+	elasticSearchProc.enabledIndexes[dataindexer.CallTreeIndex] = struct{}{}
+
+	err := elasticSearchProc.RemoveTransactions(header, &dataBlock.Body{})
+	require.Nil(t, err)
+	require.Equal(t, []string{dataindexer.TxLifecycleIndex, dataindexer.CallTreeIndex}, updatedIndices)
+}
+
+func TestElasticProcessor_JournalPreviousVersions(t *testing.T) {
+	t.Parallel()
+
+	arguments := createMockElasticProcessorArgs()
+
+	journalBody := ""
+	dbWriter := &mock.DatabaseWriterStub{
+		DoMultiGetCalled: func(ids []string, index string, withSource bool, response interface{}) error {
+			require.Equal(t, dataindexer.SCDeploysIndex, index)
+			require.Equal(t, []string{"contract", "newContract"}, ids)
+			return json.Unmarshal([]byte(`{"docs":[{"found":true,"_id":"contract","_source":{"deployer":"old"}},{"found":false,"_id":"newContract"}]}`), response)
+		},
+		DoBulkRequestCalled: func(buff *bytes.Buffer, index string) error {
+			journalBody = buff.String()
+			return nil
+		},
+	}
+
+	elasticSearchProc := newElasticsearchProcessor(dbWriter, arguments)
+	elasticSearchProc.enabledIndexes[dataindexer.JournalIndex] = struct{}{}
+
+	buffSlice := data.NewBufferSlice(data.DefaultMaxBulkSize)
+	_ = buffSlice.PutData([]byte(`{ "update" : { "_index":"scdeploys", "_id" : "contract" } }`+"\n"), []byte(`{"doc": {}}`))
+	_ = buffSlice.PutData([]byte(`{ "update" : { "_index":"scdeploys", "_id" : "newContract" } }`+"\n"), []byte(`{"doc": {}}`))
+	_ = buffSlice.PutData([]byte(`{ "index" : { "_index":"transactions", "_id" : "aabb" } }`+"\n"), []byte(`{}`))
+
+	outportBlock := createEmptyOutportBlockWithHeader()
+	outportBlock.ShardID = 1
+	outportBlock.Header = &dataBlock.Header{ShardID: 1, TimeStamp: 5000}
+	outportBlock.BlockData.HeaderHash = []byte("hash")
+
+	docsIDs, err := elasticSearchProc.journalPreviousVersions(outportBlock, buffSlice.Buffers())
+	require.Nil(t, err)
+	require.Equal(t, map[string][]string{dataindexer.SCDeploysIndex: {"contract", "newContract"}}, docsIDs)
+
+	blockHash := hex.EncodeToString([]byte("hash"))
+	require.Equal(t, fmt.Sprintf(`{ "update" : { "_index":"journal", "_id" : "%s_scdeploys_contract" } }
+{"script": {"source": "ctx.op = 'noop'", "lang": "painless"}, "upsert": {"blockHash":"%s","shardID":1,"timestamp":5000,"index":"scdeploys","docID":"contract","existed":true,"source":{"deployer":"old"}}}
+{ "update" : { "_index":"journal", "_id" : "%s_scdeploys_newContract" } }
+{"script": {"source": "ctx.op = 'noop'", "lang": "painless"}, "upsert": {"blockHash":"%s","shardID":1,"timestamp":5000,"index":"scdeploys","docID":"newContract","existed":false}}
+`, blockHash, blockHash, blockHash, blockHash), journalBody)
+}
+
+func TestElasticProcessor_JournalPreviousVersionsShouldKeepTheIncrementsOfTheCounters(t *testing.T) {
+	t.Parallel()
+
+	arguments := createMockElasticProcessorArgs()
+
+	journalBody := ""
+	dbWriter := &mock.DatabaseWriterStub{
+		DoMultiGetCalled: func(ids []string, index string, withSource bool, response interface{}) error {
+			require.Equal(t, dataindexer.TagsIndex, index)
+			return json.Unmarshal([]byte(`{"docs":[{"found":true,"_id":"dGFn","_source":{"count":4,"tag":"tag"}}]}`), response)
+		},
+		DoBulkRequestCalled: func(buff *bytes.Buffer, index string) error {
+			journalBody = buff.String()
+			return nil
+		},
+	}
+
+	elasticSearchProc := newElasticsearchProcessor(dbWriter, arguments)
+	elasticSearchProc.enabledIndexes[dataindexer.JournalIndex] = struct{}{}
+
+	buffSlice := data.NewBufferSlice(data.DefaultMaxBulkSize)
+	_ = buffSlice.PutData([]byte(`{ "update" : {"_index":"tags", "_id" : "dGFn" } }`+"\n"), []byte(`{"script": {"source": "ctx._source.count += params.count","lang": "painless","params": {"count": 2, "tag": "tag"}},"upsert": {"count": 2, "tag":"tag"}}`))
+
+	outportBlock := createEmptyOutportBlockWithHeader()
+	outportBlock.BlockData.HeaderHash = []byte("hash")
+
+	_, err := elasticSearchProc.journalPreviousVersions(outportBlock, buffSlice.Buffers())
+	require.Nil(t, err)
+	require.Contains(t, journalBody, `"source":{"count":4,"tag":"tag"},"increments":{"count":2}}`)
+}
+
+func TestElasticProcessor_JournalPreviousVersionsInImportDBMode(t *testing.T) {
+	t.Parallel()
+
+	arguments := createMockElasticProcessorArgs()
+	dbWriter := &mock.DatabaseWriterStub{
+		DoMultiGetCalled: func(ids []string, index string, withSource bool, response interface{}) error {
+			require.Fail(t, "the previous versions should not be fetched in import-db mode")
+			return nil
+		},
+		DoBulkRequestCalled: func(buff *bytes.Buffer, index string) error {
+			require.Fail(t, "the journal should not be written in import-db mode")
+			return nil
+		},
+	}
+
+	elasticSearchProc := newElasticsearchProcessor(dbWriter, arguments)
+	elasticSearchProc.enabledIndexes[dataindexer.JournalIndex] = struct{}{}
+	_ = elasticSearchProc.SetOutportConfig(outport.OutportConfig{IsInImportDBMode: true})
+
+	buffSlice := data.NewBufferSlice(data.DefaultMaxBulkSize)
+	_ = buffSlice.PutData([]byte(`{ "update" : { "_index":"scdeploys", "_id" : "contract" } }`+"\n"), []byte(`{"doc": {}}`))
+
+	outportBlock := createEmptyOutportBlockWithHeader()
+	outportBlock.BlockData.HeaderHash = []byte("hash")
+
+	docsIDs, err := elasticSearchProc.journalPreviousVersions(outportBlock, buffSlice.Buffers())
+	require.Nil(t, err)
+	require.Empty(t, docsIDs)
+}
+
+func TestElasticProcessor_JournalWrittenVersions(t *testing.T) {
+	t.Parallel()
+
+	arguments := createMockElasticProcessorArgs()
+
+	journalBody := ""
+	dbWriter := &mock.DatabaseWriterStub{
+		DoMultiGetCalled: func(ids []string, index string, withSource bool, response interface{}) error {
+			require.Equal(t, dataindexer.SCDeploysIndex, index)
+			require.Equal(t, []string{"contract", "removedContract"}, ids)
+			return json.Unmarshal([]byte(`{"docs":[{"found":true,"_id":"contract","_source":{"deployer":"new"}},{"found":false,"_id":"removedContract"}]}`), response)
+		},
+		DoBulkRequestCalled: func(buff *bytes.Buffer, index string) error {
+			journalBody = buff.String()
+			return nil
+		},
+	}
+
+	elasticSearchProc := newElasticsearchProcessor(dbWriter, arguments)
+	elasticSearchProc.enabledIndexes[dataindexer.JournalIndex] = struct{}{}
+
+	outportBlock := createEmptyOutportBlockWithHeader()
+	outportBlock.BlockData.HeaderHash = []byte("hash")
+
+	err := elasticSearchProc.journalWrittenVersions(outportBlock, map[string][]string{dataindexer.SCDeploysIndex: {"contract", "removedContract"}})
+	require.Nil(t, err)
+
+	blockHash := hex.EncodeToString([]byte("hash"))
+	require.Equal(t, fmt.Sprintf(`{ "update" : { "_index":"journal", "_id" : "%s_scdeploys_contract" } }
+{"doc": {"written": {"deployer":"new"}}}
+`, blockHash), journalBody)
+}
+
+func TestElasticProcessor_RemoveTransactionsShouldRestoreJournaledDocuments(t *testing.T) {
+	t.Parallel()
+
+	arguments := createMockElasticProcessorArgs()
+	arguments.TransactionsProc, _ = transactions.NewTransactionsProcessor(&transactions.ArgsTransactionProcessor{
+		AddressPubkeyConverter: mock.NewPubkeyConverterMock(32),
+		Hasher:                 &mock.HasherMock{},
+		Marshalizer:            &mock.MarshalizerMock{},
+	})
+
+	header := &dataBlock.Header{ShardID: 1, TimeStamp: 1234}
+	headerHash, _ := arguments.BlockProc.ComputeHeaderHash(header)
+	journalQuery := fmt.Sprintf(`{"query": {"term": {"blockHash": "%s"}}}`, hex.EncodeToString(headerHash))
+
+	restoreBody := ""
+	journalRemoved := false
+	dbWriter := &mock.DatabaseWriterStub{
+		DoScrollRequestCalled: func(index string, body []byte, withSource bool, handlerFunc func(responseBytes []byte) error) error {
+			if index != dataindexer.JournalIndex {
+				return nil
+			}
+
+			require.Equal(t, journalQuery, string(body))
+			return handlerFunc([]byte(`{"hits":{"hits":[` +
+				`{"_source":{"index":"tokens","docID":"TKN-abcd","existed":true,"source":{"type":"NonFungibleDCDT"}}},` +
+				`{"_source":{"index":"tags","docID":"dGFn","existed":false}}]}}`))
+		},
+		DoBulkRequestCalled: func(buff *bytes.Buffer, index string) error {
+			restoreBody = buff.String()
+			return nil
+		},
+		DoQueryRemoveCalled: func(index string, body *bytes.Buffer) error {
+			if index == dataindexer.JournalIndex {
+				require.Equal(t, journalQuery, body.String())
+				journalRemoved = true
+			}
+			return nil
+		},
+	}
+
+	elasticSearchProc := newElasticsearchProcessor(dbWriter, arguments)
+	elasticSearchProc.enabledIndexes[dataindexer.JournalIndex] = struct{}{}
+
+	err := elasticSearchProc.RemoveTransactions(header, &dataBlock.Body{})
+	require.Nil(t, err)
+	require.True(t, journalRemoved)
+	require.Equal(t, `{ "index" : { "_index":"tokens", "_id" : "TKN-abcd" } }
+{"type":"NonFungibleDCDT"}
+{ "delete" : { "_index": "tags", "_id" : "dGFn" } }
+`, restoreBody)
+}
+
+func TestElasticProcessor_RemoveTransactionsShouldRestoreAccountsFromHistory(t *testing.T) {
+	t.Parallel()
+
+	arguments := createMockElasticProcessorArgs()
+	arguments.TransactionsProc, _ = transactions.NewTransactionsProcessor(&transactions.ArgsTransactionProcessor{
+		AddressPubkeyConverter: mock.NewPubkeyConverterMock(32),
+		Hasher:                 &mock.HasherMock{},
+		Marshalizer:            &mock.MarshalizerMock{},
+	})
+
+	header := &dataBlock.Header{ShardID: 1, TimeStamp: 5000}
+	revertQuery := `{"query": {"bool": {"filter": [{"term": {"timestamp": {"value": 5000}}}, {"term": {"shardID": {"value": 1}}}]}}}`
+
+	historyRemoved := false
+	restoreBody := ""
+	dbWriter := &mock.DatabaseWriterStub{
+		DoScrollRequestCalled: func(index string, body []byte, withSource bool, handlerFunc func(responseBytes []byte) error) error {
+			if index != dataindexer.AccountsHistoryIndex {
+				return nil
+			}
+
+			require.Equal(t, revertQuery, string(body))
+			return handlerFunc([]byte(`{"hits":{"hits":[` +
+				`{"_source":{"address":"addr1","timestamp":5000,"balance":"5","shardID":1}},` +
+				`{"_source":{"address":"addr2","timestamp":5000,"balance":"7","shardID":1,"created":true}},` +
+				`{"_source":{"address":"addr3","timestamp":5000,"balance":"9","shardID":1}},` +
+				`{"_source":{"address":"addr1","timestamp":5000,"balance":"5","shardID":1}}]}}`))
+		},
+		DoQueryRemoveCalled: func(index string, body *bytes.Buffer) error {
+			if index == dataindexer.AccountsHistoryIndex {
+				require.Equal(t, revertQuery, body.String())
+				historyRemoved = true
+			}
+			return nil
+		},
+		DoSearchRequestCalled: func(index string, body []byte, resBody interface{}) error {
+			require.Equal(t, dataindexer.AccountsHistoryIndex, index)
+			require.Contains(t, string(body), `{"terms": {"address": ["addr1","addr2","addr3"]}}`)
+
+			return json.Unmarshal([]byte(`{"hits":{"hits":[{"_source":{"address":"addr1","timestamp":4000,"balance":"1000","nonce":3,"shardID":1}}]}}`), resBody)
+		},
+		DoBulkRequestCalled: func(buff *bytes.Buffer, index string) error {
+			if index == dataindexer.AccountsIndex {
+				restoreBody = buff.String()
+			}
+			return nil
+		},
+	}
+
+	elasticSearchProc := newElasticsearchProcessor(dbWriter, arguments)
+
+	err := elasticSearchProc.RemoveTransactions(header, &dataBlock.Body{})
+	require.Nil(t, err)
+	require.True(t, historyRemoved)
+	require.Equal(t, 2, strings.Count(restoreBody, `"update"`))
+	require.Contains(t, restoreBody, `"revertedTimestamp": 5000, "account": {"balance":"1000","balanceNum":1e-7,"nonce":3,"timestamp":4000}`)
+	require.Contains(t, restoreBody, `"_id" : "addr2"`)
+	require.Contains(t, restoreBody, `"revertedTimestamp": 5000, "account": null`)
+	// the account without history which was not created by the block is left unchanged
+	require.NotContains(t, restoreBody, `"_id" : "addr3"`)
+}
+
+func TestElasticProcessor_RemoveTransactionsWithAccountsHistoryDisabledShouldNotChangeTheAccounts(t *testing.T) {
+	t.Parallel()
+
+	arguments := createMockElasticProcessorArgs()
+	arguments.TransactionsProc, _ = transactions.NewTransactionsProcessor(&transactions.ArgsTransactionProcessor{
+		AddressPubkeyConverter: mock.NewPubkeyConverterMock(32),
+		Hasher:                 &mock.HasherMock{},
+		Marshalizer:            &mock.MarshalizerMock{},
+	})
+	delete(arguments.EnabledIndexes, dataindexer.AccountsHistoryIndex)
+
+	dbWriter := &mock.DatabaseWriterStub{
+		DoScrollRequestCalled: func(index string, _ []byte, _ bool, _ func(responseBytes []byte) error) error {
+			require.NotEqual(t, dataindexer.AccountsHistoryIndex, index)
+			return nil
+		},
+		DoSearchRequestCalled: func(index string, _ []byte, _ interface{}) error {
+			require.Fail(t, "no history should be searched", index)
+			return nil
+		},
+		DoBulkRequestCalled: func(_ *bytes.Buffer, index string) error {
+			require.NotEqual(t, dataindexer.AccountsIndex, index)
+			return nil
+		},
+	}
+
+	elasticSearchProc := newElasticsearchProcessor(dbWriter, arguments)
+
+	err := elasticSearchProc.RemoveTransactions(&dataBlock.Header{ShardID: 1, TimeStamp: 5000}, &dataBlock.Body{})
+	require.Nil(t, err)
+}
+
+func TestElasticProcessor_RemoveTransactionsShouldRefreshOnlyForTheLastWrittenBlock(t *testing.T) {
+	t.Parallel()
+
+	refreshes := make(map[string]int)
+	dbWriter := &mock.DatabaseWriterStub{
+		DoRefreshRequestCalled: func(index string) error {
+			refreshes[index]++
+			return nil
+		},
+	}
+
+	arguments := createMockElasticProcessorArgs()
+	arguments.EnabledIndexes = map[string]struct{}{
+		dataindexer.AccountsHistoryIndex: {},
+		dataindexer.JournalIndex:         {},
+	}
+	elasticSearchProc := newElasticsearchProcessor(dbWriter, arguments)
+
+	header := &dataBlock.Header{ShardID: 1, TimeStamp: 5000}
+	headerHash, err := elasticSearchProc.blockProc.ComputeHeaderHash(header)
+	require.Nil(t, err)
+
+	// the block was written before the last one, so its entries are already visible
+	elasticSearchProc.setLastWrittenBlock(1, "otherHash")
+	err = elasticSearchProc.RemoveTransactions(header, &dataBlock.Body{})
+	require.Nil(t, err)
+	require.Empty(t, refreshes)
+
+	elasticSearchProc.setLastWrittenBlock(1, hex.EncodeToString(headerHash))
+	err = elasticSearchProc.RemoveTransactions(header, &dataBlock.Body{})
+	require.Nil(t, err)
+	require.Equal(t, map[string]int{dataindexer.AccountsHistoryIndex: 1, dataindexer.JournalIndex: 1}, refreshes)
+
+	// the documents were refreshed with the first revert
+	err = elasticSearchProc.RemoveTransactions(header, &dataBlock.Body{})
+	require.Nil(t, err)
+	require.Equal(t, map[string]int{dataindexer.AccountsHistoryIndex: 1, dataindexer.JournalIndex: 1}, refreshes)
+}
+
+func TestElasticProcessor_SaveShardValidatorsPubKeysShouldIndexValidatorsHistory(t *testing.T) {
+	arguments := createMockElasticProcessorArgs()
+
+	bulkIndices := make([]string, 0)
+	dbWriter := &mock.DatabaseWriterStub{
+		DoMultiGetCalled: func(ids []string, index string, withSource bool, response interface{}) error {
+			require.Equal(t, dataindexer.ValidatorsIndex, index)
+			require.Equal(t, []string{"0_9"}, ids)
+
+			validatorsResponse := response.(*data.ResponseValidatorsPublicKeys)
+			validatorsResponse.Docs = []*data.ResponseValidatorsPublicKeysDB{{
+				Found:  true,
+				ID:     "0_9",
+				Source: data.ValidatorsPublicKeys{PublicKeys: []string{"6b31"}},
+			}}
+			return nil
+		},
+		DoBulkRequestCalled: func(buff *bytes.Buffer, index string) error {
+			bulkIndices = append(bulkIndices, index)
+			if index == dataindexer.ValidatorsHistoryIndex {
+				require.Equal(t, `{ "index" : { "_id" : "6b31_0_10" } }
+{"publicKey":"6b31","shardID":0,"epoch":10,"action":"left"}
+{ "index" : { "_id" : "6b32_0_10" } }
+{"publicKey":"6b32","shardID":0,"epoch":10,"action":"joined"}
+`, buff.String())
+			}
+			return nil
+		},
+	}
+
+	elasticProc := newElasticsearchProcessor(dbWriter, arguments)
+	elasticProc.enabledIndexes[dataindexer.ValidatorsHistoryIndex] = struct{}{}
+
+	err := elasticProc.SaveShardValidatorsPubKeys(&outport.ValidatorsPubKeys{
+		Epoch: 10,
+		ShardValidatorsPubKeys: map[uint32]*outport.PubKeys{
+			0: {Keys: [][]byte{[]byte("k2")}},
+		},
+	})
+	require.Nil(t, err)
+	require.Equal(t, []string{dataindexer.ValidatorsHistoryIndex, dataindexer.ValidatorsIndex}, bulkIndices)
+}
+
+func TestElasticProcessor_SaveShardValidatorsPubKeysShouldComputePreviousListFromHistory(t *testing.T) {
+	arguments := createMockElasticProcessorArgs()
+
+	historyBody := ""
+	dbWriter := &mock.DatabaseWriterStub{
+		DoMultiGetCalled: func(ids []string, index string, withSource bool, response interface{}) error {
+			return nil
+		},
+		DoScrollRequestCalled: func(index string, body []byte, withSource bool, handlerFunc func(responseBytes []byte) error) error {
+			require.Equal(t, dataindexer.ValidatorsHistoryIndex, index)
+			require.Equal(t, `{"query": {"bool": {"filter": [{"terms": {"shardID": [0]}}, {"range": {"epoch": {"lt": 10}}}]}}}`, string(body))
+
+			return handlerFunc([]byte(`{"hits":{"hits":[` +
+				`{"_source":{"publicKey":"6b31","shardID":0,"epoch":5,"action":"joined"}},` +
+				`{"_source":{"publicKey":"6b33","shardID":0,"epoch":5,"action":"joined"}},` +
+				`{"_source":{"publicKey":"6b33","shardID":0,"epoch":7,"action":"left"}}]}}`))
+		},
+		DoBulkRequestCalled: func(buff *bytes.Buffer, index string) error {
+			if index == dataindexer.ValidatorsHistoryIndex {
+				historyBody = buff.String()
+			}
+			return nil
+		},
+	}
+
+	elasticProc := newElasticsearchProcessor(dbWriter, arguments)
+	elasticProc.enabledIndexes[dataindexer.ValidatorsHistoryIndex] = struct{}{}
+
+	err := elasticProc.SaveShardValidatorsPubKeys(&outport.ValidatorsPubKeys{
+		Epoch: 10,
+		ShardValidatorsPubKeys: map[uint32]*outport.PubKeys{
+			0: {Keys: [][]byte{[]byte("k2")}},
+		},
+	})
+	require.Nil(t, err)
+	require.Equal(t, `{ "index" : { "_id" : "6b31_0_10" } }
+{"publicKey":"6b31","shardID":0,"epoch":10,"action":"left"}
+{ "index" : { "_id" : "6b32_0_10" } }
+{"publicKey":"6b32","shardID":0,"epoch":10,"action":"joined"}
+`, historyBody)
+}
+
 func TestElasticProcessor_IndexEpochInfoData(t *testing.T) {
 	called := false
 	arguments := createMockElasticProcessorArgs()
@@ -588,7 +1331,178 @@ func TestElasticProcessor_IndexAlteredAccounts(t *testing.T) {
 
 	buffSlice := data.NewBufferSlice(data.DefaultMaxBulkSize)
 	tagsCount := tags.NewTagsCount()
-	err := elasticSearchProc.indexAlteredAccounts(100, nil, nil, buffSlice, tagsCount, 0)
+	err := elasticSearchProc.indexAlteredAccounts(100, nil, nil, nil, buffSlice, tagsCount, 0, stagetimings.NewBlockTimer(0, 0, ""))
 	require.Nil(t, err)
 	require.True(t, called)
 }
+
+func TestElasticProcessor_SaveAccountsHistoryShouldMarkTheCreatedAccountsAndKeepTheChangedState(t *testing.T) {
+	t.Parallel()
+
+	elasticSearchProc := newElasticsearchProcessor(&mock.DatabaseWriterStub{}, createMockElasticProcessorArgs())
+
+	accountsMap := map[string]*data.AccountInfo{
+		"addr1": {Address: "addr1", Balance: "10", CurrentOwner: "owner"},
+		"addr2": {Address: "addr2", Balance: "20"},
+		"addr3": {Address: "addr3", Balance: "30", CodeHash: []byte("new")},
+	}
+	indexedAccounts := map[string]*data.AccountInfo{
+		"addr1": {Address: "addr1", Balance: "5", CurrentOwner: "owner"},
+		"addr3": {Address: "addr3", Balance: "30", CodeHash: []byte("old"), CurrentOwner: "owner"},
+	}
+	buffSlice := data.NewBufferSlice(data.DefaultMaxBulkSize)
+	err := elasticSearchProc.saveAccountsHistory(5000, accountsMap, indexedAccounts, buffSlice, 1)
+	require.Nil(t, err)
+
+	history := buffSlice.Buffers()[0].String()
+	require.Contains(t, history, `{"address":"addr1","timestamp":5000,"balance":"10","nonce":0,"shardID":1}`)
+	require.Contains(t, history, `{"address":"addr2","timestamp":5000,"balance":"20","nonce":0,"shardID":1,"created":true}`)
+	require.Contains(t, history, `{"address":"addr3","timestamp":5000,"balance":"30","nonce":0,"shardID":1,"previousState":{"currentOwner":"owner","codeHash":"b2xk"}}`)
+}
+
+func TestElasticProcessor_SaveAccountsHistoryWithoutTheIndexedAccountsShouldNotMarkTheAccounts(t *testing.T) {
+	t.Parallel()
+
+	elasticSearchProc := newElasticsearchProcessor(&mock.DatabaseWriterStub{}, createMockElasticProcessorArgs())
+
+	accountsMap := map[string]*data.AccountInfo{
+		"addr1": {Address: "addr1", Balance: "10", CurrentOwner: "owner"},
+	}
+	buffSlice := data.NewBufferSlice(data.DefaultMaxBulkSize)
+	err := elasticSearchProc.saveAccountsHistory(5000, accountsMap, nil, buffSlice, 1)
+	require.Nil(t, err)
+	require.Contains(t, buffSlice.Buffers()[0].String(), `{"address":"addr1","timestamp":5000,"balance":"10","nonce":0,"shardID":1}`)
+}
+
+func TestElasticProcessor_SaveTransactionsShouldLookUpTheMissingAccountsOnce(t *testing.T) {
+	t.Parallel()
+
+	numAccountsGets := 0
+	bulkBody := ""
+	dbWriter := &mock.DatabaseWriterStub{
+		DoMultiGetCalled: func(ids []string, index string, withSource bool, response interface{}) error {
+			if index != dataindexer.AccountsIndex {
+				return json.Unmarshal([]byte(`{"docs":[]}`), response)
+			}
+
+			numAccountsGets++
+			require.ElementsMatch(t, []string{"aa", "bb"}, ids)
+			return json.Unmarshal([]byte(`{"docs":[{"_id":"aa","found":true},{"_id":"bb","found":false}]}`), response)
+		},
+		DoBulkRequestCalled: func(buff *bytes.Buffer, index string) error {
+			bulkBody += buff.String()
+			return nil
+		},
+	}
+
+	arguments := createMockElasticProcessorArgs()
+	arguments.EnabledIndexes = map[string]struct{}{
+		dataindexer.AccountsIndex:        {},
+		dataindexer.AccountsHistoryIndex: {},
+		dataindexer.EpochStatsIndex:      {},
+	}
+	arguments.TransactionsProc = &mock.DBTransactionProcessorStub{
+		PrepareTransactionsForDatabaseCalled: func(mbs []*dataBlock.MiniBlock, header coreData.HeaderHandler, pool *outport.TransactionPool) *data.PreparedResults {
+			return &data.PreparedResults{}
+		},
+	}
+	elasticSearchProc := newElasticsearchProcessor(dbWriter, arguments)
+
+	outportBlock := createEmptyOutportBlockWithHeader()
+	outportBlock.ShardID = 1
+	outportBlock.Header = &dataBlock.Header{ShardID: 1, Epoch: 2, TimeStamp: 5000}
+	outportBlock.BlockData.HeaderHash = []byte("hash")
+	outportBlock.AlteredAccounts = map[string]*alteredAccount.AlteredAccount{
+		"aa": {Address: "aa", Balance: "10", AdditionalData: &alteredAccount.AdditionalAccountData{BalanceChanged: true}},
+		"bb": {Address: "bb", Balance: "20", AdditionalData: &alteredAccount.AdditionalAccountData{BalanceChanged: true}},
+	}
+
+	err := elasticSearchProc.SaveTransactions(outportBlock)
+	require.Nil(t, err)
+	require.Equal(t, 1, numAccountsGets)
+	require.Contains(t, bulkBody, `"newAccounts":1`)
+	require.Contains(t, bulkBody, `"address":"bb","timestamp":5000,"balance":"20"`)
+	require.Contains(t, bulkBody, `"created":true`)
+}
+
+func TestElasticProcessor_SaveTransactionsWithoutAccountsHistoryShouldLookUpTheAccountsOnlyForTheNewBlocks(t *testing.T) {
+	t.Parallel()
+
+	numAccountsGets := 0
+	previousContribution := `{"docs":[]}`
+	bulkBody := ""
+	dbWriter := &mock.DatabaseWriterStub{
+		DoMultiGetCalled: func(ids []string, index string, withSource bool, response interface{}) error {
+			if index == dataindexer.StatsContributionsIndex {
+				return json.Unmarshal([]byte(previousContribution), response)
+			}
+			if index != dataindexer.AccountsIndex {
+				return json.Unmarshal([]byte(`{"docs":[]}`), response)
+			}
+
+			numAccountsGets++
+			return json.Unmarshal([]byte(`{"docs":[{"_id":"aa","found":true},{"_id":"bb","found":false}]}`), response)
+		},
+		DoBulkRequestCalled: func(buff *bytes.Buffer, index string) error {
+			bulkBody += buff.String()
+			return nil
+		},
+	}
+
+	arguments := createMockElasticProcessorArgs()
+	arguments.EnabledIndexes = map[string]struct{}{
+		dataindexer.AccountsIndex:           {},
+		dataindexer.EpochStatsIndex:         {},
+		dataindexer.StatsContributionsIndex: {},
+	}
+	arguments.TransactionsProc = &mock.DBTransactionProcessorStub{
+		PrepareTransactionsForDatabaseCalled: func(mbs []*dataBlock.MiniBlock, header coreData.HeaderHandler, pool *outport.TransactionPool) *data.PreparedResults {
+			return &data.PreparedResults{}
+		},
+	}
+	elasticSearchProc := newElasticsearchProcessor(dbWriter, arguments)
+
+	outportBlock := createEmptyOutportBlockWithHeader()
+	outportBlock.ShardID = 1
+	outportBlock.Header = &dataBlock.Header{ShardID: 1, Epoch: 2, TimeStamp: 5000}
+	outportBlock.BlockData.HeaderHash = []byte("hash")
+	outportBlock.AlteredAccounts = map[string]*alteredAccount.AlteredAccount{
+		"aa": {Address: "aa", Balance: "10", AdditionalData: &alteredAccount.AdditionalAccountData{BalanceChanged: true}},
+		"bb": {Address: "bb", Balance: "20", AdditionalData: &alteredAccount.AdditionalAccountData{BalanceChanged: true}},
+	}
+
+	err := elasticSearchProc.SaveTransactions(outportBlock)
+	require.Nil(t, err)
+	require.Equal(t, 1, numAccountsGets)
+	require.Contains(t, bulkBody, `"newAccounts":1`)
+
+	// the block was indexed before, so the number of the new accounts is taken from its contribution
+	previousContribution = `{"docs":[{"found":true,"_id":"epochstats_68617368","_source":{"index":"epochstats","key":"68617368","shardID":1,"timestamp":5000,` +
+		`"stats":{"epoch":2,"shardID":1,"numBlocks":1,"txsByOperation":{},"txsByStatus":{},"fees":"0","developerFees":"0","newAccounts":1}}}]}`
+	err = elasticSearchProc.SaveTransactions(outportBlock)
+	require.Nil(t, err)
+	require.Equal(t, 1, numAccountsGets)
+}
+
+func TestElasticProcessor_EnableAndDisableIndex(t *testing.T) {
+	t.Parallel()
+
+	arguments := createMockElasticProcessorArgs()
+	arguments.EnabledIndexes = map[string]struct{}{
+		dataindexer.BlockIndex: {},
+	}
+	elasticProc := newElasticsearchProcessor(&mock.DatabaseWriterStub{}, arguments)
+
+	err := elasticProc.EnableIndex("unknown")
+	require.True(t, errors.Is(err, dataindexer.ErrUnknownIndex))
+
+	err = elasticProc.EnableIndex(dataindexer.TransactionsIndex)
+	require.Nil(t, err)
+	require.True(t, elasticProc.isIndexEnabled(dataindexer.TransactionsIndex))
+	require.Equal(t, []string{dataindexer.BlockIndex, dataindexer.TransactionsIndex}, elasticProc.GetEnabledIndexes())
+
+	err = elasticProc.DisableIndex(dataindexer.BlockIndex)
+	require.Nil(t, err)
+	require.False(t, elasticProc.isIndexEnabled(dataindexer.BlockIndex))
+	require.Equal(t, []string{dataindexer.TransactionsIndex}, elasticProc.GetEnabledIndexes())
+}
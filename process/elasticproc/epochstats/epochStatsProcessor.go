@@ -0,0 +1,127 @@
+package epochstats
+
+import (
+	"math/big"
+	"time"
+
+	"github.com/kalyan3104/k-chain-core-go/core/check"
+	coreData "github.com/kalyan3104/k-chain-core-go/data"
+	"github.com/kalyan3104/k-chain-core-go/data/transaction"
+	"github.com/kalyan3104/k-chain-es-indexer-go/data"
+	"github.com/kalyan3104/k-chain-es-indexer-go/process/dataindexer"
+	"github.com/kalyan3104/k-chain-es-indexer-go/process/elasticproc/converters"
+	logger "github.com/kalyan3104/k-chain-logger-go"
+)
+
+const (
+	rewardsOperation = "reward"
+
+	// the active accounts are counted exactly up to this number, which is the maximum supported by the cardinality
+	// aggregation, and approximately above it
+	activeAccountsPrecisionThreshold = 40000
+)
+
+var log = logger.GetOrCreate("indexer/process/epochstats")
+
+type epochStatsProcessor struct {
+	balanceConverter dataindexer.BalanceConverter
+}
+
+// NewEpochStatsProcessor will create a new instance of epochStatsProcessor
+func NewEpochStatsProcessor(balanceConverter dataindexer.BalanceConverter) (*epochStatsProcessor, error) {
+	if check.IfNil(balanceConverter) {
+		return nil, dataindexer.ErrNilBalanceConverter
+	}
+
+	return &epochStatsProcessor{
+		balanceConverter: balanceConverter,
+	}, nil
+}
+
+// PrepareEpochStats will prepare the statistics of the provided block that have to be added to the epoch statistics. The
+// number of accounts created by the block is provided by the caller, since it is found by looking up the accounts index
+func (esp *epochStatsProcessor) PrepareEpochStats(
+	header coreData.HeaderHandler,
+	preparedResults *data.PreparedResults,
+	logsResults *data.PreparedLogsResults,
+	newAccounts uint64,
+) *data.EpochStats {
+	shardID := header.GetShardID()
+	fees := converters.BigIntToString(header.GetAccumulatedFees())
+	developerFees := converters.BigIntToString(header.GetDeveloperFees())
+
+	stats := &data.EpochStats{
+		Epoch:            header.GetEpoch(),
+		ShardID:          shardID,
+		NumBlocks:        1,
+		NewAccounts:      newAccounts,
+		TxsByOperation:   make(map[string]uint64),
+		TxsByStatus:      make(map[string]uint64),
+		Fees:             fees,
+		FeesNum:          esp.computeValueAsFloat(header.GetAccumulatedFees()),
+		DeveloperFees:    developerFees,
+		DeveloperFeesNum: esp.computeValueAsFloat(header.GetDeveloperFees()),
+		StartTimestamp:   time.Duration(header.GetTimeStamp()),
+		Timestamp:        time.Duration(header.GetTimeStamp()),
+	}
+
+	for _, tx := range preparedResults.Transactions {
+		esp.addTransaction(stats, tx, logsResults)
+	}
+
+	for _, scr := range preparedResults.ScResults {
+		// a smart contract result is counted only on the shard where it is executed
+		if scr.ReceiverShard != shardID {
+			continue
+		}
+
+		stats.ScResultsCount++
+		stats.TokenTransfers += uint64(len(scr.Tokens))
+	}
+
+	if logsResults != nil {
+		stats.NewContracts = uint64(len(logsResults.ScDeploys))
+	}
+
+	return stats
+}
+
+func (esp *epochStatsProcessor) addTransaction(stats *data.EpochStats, tx *data.Transaction, logsResults *data.PreparedLogsResults) {
+	// a transaction is counted only once, on the shard where it has its final status: the destination shard for the
+	// executed transactions and the source shard for the invalid ones
+	isInvalid := tx.Status == transaction.TxStatusInvalid.String()
+	executedOnShard := !isInvalid && tx.ReceiverShard == stats.ShardID
+	invalidOnShard := isInvalid && tx.SenderShard == stats.ShardID
+	if !executedOnShard && !invalidOnShard {
+		return
+	}
+
+	status := tx.Status
+	if logsResults != nil {
+		statusInfo, found := logsResults.TxHashStatusInfo[tx.Hash]
+		if found && statusInfo.Status != "" {
+			status = statusInfo.Status
+		}
+	}
+
+	stats.TxCount++
+	stats.TxsByStatus[status]++
+	if tx.Operation != "" {
+		stats.TxsByOperation[tx.Operation]++
+	}
+	stats.GasUsed += tx.GasUsed
+	stats.TokenTransfers += uint64(len(tx.Tokens))
+}
+
+func (esp *epochStatsProcessor) computeValueAsFloat(value *big.Int) float64 {
+	if value == nil {
+		return 0
+	}
+
+	valueNum, err := esp.balanceConverter.ConvertBigValueToFloat(value)
+	if err != nil {
+		log.Warn("epochStatsProcessor.computeValueAsFloat cannot compute value as num", "value", value, "error", err)
+	}
+
+	return valueNum
+}
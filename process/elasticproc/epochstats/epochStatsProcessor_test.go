@@ -0,0 +1,94 @@
+package epochstats
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/kalyan3104/k-chain-core-go/data/block"
+	"github.com/kalyan3104/k-chain-core-go/data/outport"
+	"github.com/kalyan3104/k-chain-core-go/data/transaction"
+	"github.com/kalyan3104/k-chain-es-indexer-go/data"
+	"github.com/kalyan3104/k-chain-es-indexer-go/process/dataindexer"
+	"github.com/kalyan3104/k-chain-es-indexer-go/process/elasticproc/converters"
+	"github.com/stretchr/testify/require"
+)
+
+func createEpochStatsProcessor() *epochStatsProcessor {
+	balanceConverter, _ := converters.NewBalanceConverter(18)
+	esp, _ := NewEpochStatsProcessor(balanceConverter)
+
+	return esp
+}
+
+func TestNewEpochStatsProcessor(t *testing.T) {
+	t.Parallel()
+
+	esp, err := NewEpochStatsProcessor(nil)
+	require.Nil(t, esp)
+	require.Equal(t, dataindexer.ErrNilBalanceConverter, err)
+
+	balanceConverter, _ := converters.NewBalanceConverter(18)
+	esp, err = NewEpochStatsProcessor(balanceConverter)
+	require.Nil(t, err)
+	require.NotNil(t, esp)
+}
+
+func TestEpochStatsProcessor_PrepareEpochStats(t *testing.T) {
+	t.Parallel()
+
+	esp := createEpochStatsProcessor()
+
+	header := &block.Header{
+		ShardID:         1,
+		Epoch:           3,
+		TimeStamp:       5040,
+		AccumulatedFees: big.NewInt(1000),
+		DeveloperFees:   big.NewInt(100),
+	}
+	preparedResults := &data.PreparedResults{
+		Transactions: []*data.Transaction{
+			{Hash: "h1", Sender: "a", Receiver: "b", SenderShard: 1, ReceiverShard: 1, Status: transaction.TxStatusSuccess.String(), Operation: "transfer", GasUsed: 50},
+			{Hash: "h2", Sender: "a", Receiver: "c", SenderShard: 1, ReceiverShard: 1, Status: transaction.TxStatusSuccess.String(), Operation: "DCDTTransfer", GasUsed: 70, Tokens: []string{"TKN-abcd"}},
+			{Hash: "h3", Sender: "d", Receiver: "e", SenderShard: 1, ReceiverShard: 0, Status: transaction.TxStatusPending.String(), Operation: "transfer", GasUsed: 50},
+			{Hash: "h4", Sender: "f", Receiver: "g", SenderShard: 1, ReceiverShard: 0, Status: transaction.TxStatusInvalid.String(), Operation: "transfer", GasUsed: 50},
+		},
+		ScResults: []*data.ScResult{
+			{ReceiverShard: 1, Tokens: []string{"TKN-abcd"}},
+			{ReceiverShard: 0},
+		},
+	}
+	logsResults := &data.PreparedLogsResults{
+		ScDeploys: map[string]*data.ScDeployInfo{"sc": {}},
+		TxHashStatusInfo: map[string]*outport.StatusInfo{
+			"h1": {Status: transaction.TxStatusFail.String()},
+		},
+	}
+
+	stats := esp.PrepareEpochStats(header, preparedResults, logsResults, 2)
+	require.Equal(t, &data.EpochStats{
+		Epoch:     3,
+		ShardID:   1,
+		NumBlocks: 1,
+		TxCount:   3,
+		TxsByOperation: map[string]uint64{
+			"transfer":     2,
+			"DCDTTransfer": 1,
+		},
+		TxsByStatus: map[string]uint64{
+			transaction.TxStatusFail.String():    1,
+			transaction.TxStatusSuccess.String(): 1,
+			transaction.TxStatusInvalid.String(): 1,
+		},
+		ScResultsCount:   1,
+		Fees:             "1000",
+		FeesNum:          1e-15,
+		DeveloperFees:    "100",
+		DeveloperFeesNum: 1e-16,
+		GasUsed:          170,
+		NewContracts:     1,
+		NewAccounts:      2,
+		TokenTransfers:   2,
+		StartTimestamp:   5040,
+		Timestamp:        5040,
+	}, stats)
+}
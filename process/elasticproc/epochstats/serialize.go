@@ -0,0 +1,219 @@
+package epochstats
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/kalyan3104/k-chain-core-go/core"
+	"github.com/kalyan3104/k-chain-es-indexer-go/data"
+	"github.com/kalyan3104/k-chain-es-indexer-go/process/elasticproc/converters"
+)
+
+// SerializeEpochStats will serialize the provided statistics in a way that Elasticsearch expects a bulk request
+func (esp *epochStatsProcessor) SerializeEpochStats(stats *data.EpochStats, buffSlice *data.BufferSlice, index string) error {
+	if stats == nil {
+		return nil
+	}
+
+	statsSerialized, err := json.Marshal(stats)
+	if err != nil {
+		return err
+	}
+
+	codeToExecute := `
+		if ('create' == ctx.op) {
+			ctx._source = params.stats
+		} else {
+			ctx._source.numBlocks += params.stats.numBlocks;
+			ctx._source.txCount += params.stats.txCount;
+			ctx._source.scResultsCount += params.stats.scResultsCount;
+			ctx._source.gasUsed += params.stats.gasUsed;
+			ctx._source.newContracts += params.stats.newContracts;
+			ctx._source.newAccounts = ctx._source.getOrDefault('newAccounts', 0) + params.stats.newAccounts;
+			ctx._source.tokenTransfers += params.stats.tokenTransfers;
+			ctx._source.feesNum += params.stats.feesNum;
+			ctx._source.developerFeesNum += params.stats.developerFeesNum;
+			ctx._source.fees = new BigInteger(ctx._source.fees).add(new BigInteger(params.stats.fees)).toString();
+			ctx._source.developerFees = new BigInteger(ctx._source.developerFees).add(new BigInteger(params.stats.developerFees)).toString();
+			for (String key : params.stats.txsByOperation.keySet()) {
+				ctx._source.txsByOperation[key] = ctx._source.txsByOperation.getOrDefault(key, 0) + params.stats.txsByOperation[key];
+			}
+			for (String key : params.stats.txsByStatus.keySet()) {
+				ctx._source.txsByStatus[key] = ctx._source.txsByStatus.getOrDefault(key, 0) + params.stats.txsByStatus[key];
+			}
+			long startTimestamp = ctx._source.getOrDefault('startTimestamp', 0);
+			if (startTimestamp == 0 || params.stats.startTimestamp < startTimestamp) {
+				ctx._source.startTimestamp = params.stats.startTimestamp;
+			}
+			if (params.stats.timestamp > ctx._source.timestamp) {
+				ctx._source.timestamp = params.stats.timestamp;
+			}
+		}
+`
+	serializedDataStr := fmt.Sprintf(`{"scripted_upsert": true, "script": {`+
+		`"source": "%s",`+
+		`"lang": "painless",`+
+		`"params": { "stats": %s }},`+
+		`"upsert": {}}`,
+		converters.FormatPainlessSource(codeToExecute), string(statsSerialized),
+	)
+
+	meta := []byte(fmt.Sprintf(`{ "update" : { "_index":"%s", "_id" : "%s" } }%s`, index, ComputeEpochStatsID(stats.ShardID, stats.Epoch), "\n"))
+
+	return buffSlice.PutData(meta, []byte(serializedDataStr))
+}
+
+// SerializeEpochStatsRevert will serialize the request that subtracts the provided statistics of a block. A document
+// that remains without blocks is deleted, unless the epoch was finalized
+func (esp *epochStatsProcessor) SerializeEpochStatsRevert(stats *data.EpochStats, buffSlice *data.BufferSlice, index string) error {
+	if stats == nil {
+		return nil
+	}
+
+	statsSerialized, err := json.Marshal(stats)
+	if err != nil {
+		return err
+	}
+
+	// the timestamps cannot be computed again without the reverted block, so they are kept as they are
+	codeToExecute := `
+		if ('create' == ctx.op) {
+			ctx.op = 'noop'
+		} else {
+			ctx._source.numBlocks -= params.stats.numBlocks;
+			ctx._source.txCount -= params.stats.txCount;
+			ctx._source.scResultsCount -= params.stats.scResultsCount;
+			ctx._source.gasUsed -= params.stats.gasUsed;
+			ctx._source.newContracts -= params.stats.newContracts;
+			ctx._source.newAccounts = ctx._source.getOrDefault('newAccounts', 0) - params.stats.newAccounts;
+			ctx._source.tokenTransfers -= params.stats.tokenTransfers;
+			ctx._source.feesNum -= params.stats.feesNum;
+			ctx._source.developerFeesNum -= params.stats.developerFeesNum;
+			ctx._source.fees = new BigInteger(ctx._source.fees).subtract(new BigInteger(params.stats.fees)).toString();
+			ctx._source.developerFees = new BigInteger(ctx._source.developerFees).subtract(new BigInteger(params.stats.developerFees)).toString();
+			for (String key : params.stats.txsByOperation.keySet()) {
+				long value = ctx._source.txsByOperation.getOrDefault(key, 0) - params.stats.txsByOperation[key];
+				if (value <= 0) {
+					ctx._source.txsByOperation.remove(key);
+				} else {
+					ctx._source.txsByOperation[key] = value;
+				}
+			}
+			for (String key : params.stats.txsByStatus.keySet()) {
+				long value = ctx._source.txsByStatus.getOrDefault(key, 0) - params.stats.txsByStatus[key];
+				if (value <= 0) {
+					ctx._source.txsByStatus.remove(key);
+				} else {
+					ctx._source.txsByStatus[key] = value;
+				}
+			}
+			if (ctx._source.numBlocks <= 0 && !ctx._source.finalized) {
+				ctx.op = 'delete';
+			}
+		}
+`
+	serializedDataStr := fmt.Sprintf(`{"scripted_upsert": true, "script": {`+
+		`"source": "%s",`+
+		`"lang": "painless",`+
+		`"params": { "stats": %s }},`+
+		`"upsert": {}}`,
+		converters.FormatPainlessSource(codeToExecute), string(statsSerialized),
+	)
+
+	meta := []byte(fmt.Sprintf(`{ "update" : { "_index":"%s", "_id" : "%s" } }%s`, index, ComputeEpochStatsID(stats.ShardID, stats.Epoch), "\n"))
+
+	return buffSlice.PutData(meta, []byte(serializedDataStr))
+}
+
+// SerializeEpochStatsFinalization will serialize the requests that mark as finalized the statistics of all the shards
+// for the provided epoch
+func (esp *epochStatsProcessor) SerializeEpochStatsFinalization(epoch uint32, numOfShards uint32, timestamp uint64, buffSlice *data.BufferSlice, index string) error {
+	shardIDs := make([]uint32, 0, numOfShards+1)
+	for shardID := uint32(0); shardID < numOfShards; shardID++ {
+		shardIDs = append(shardIDs, shardID)
+	}
+	shardIDs = append(shardIDs, core.MetachainShardId)
+
+	for _, shardID := range shardIDs {
+		emptyStats := &data.EpochStats{
+			Epoch:          epoch,
+			ShardID:        shardID,
+			TxsByOperation: make(map[string]uint64),
+			TxsByStatus:    make(map[string]uint64),
+			Fees:           "0",
+			DeveloperFees:  "0",
+			Finalized:      true,
+			Timestamp:      time.Duration(timestamp),
+		}
+		emptyStatsSerialized, err := json.Marshal(emptyStats)
+		if err != nil {
+			return err
+		}
+
+		codeToExecute := `
+			if ('create' == ctx.op) {
+				ctx._source = params.stats
+			} else {
+				ctx._source.finalized = true
+			}
+`
+		serializedDataStr := fmt.Sprintf(`{"scripted_upsert": true, "script": {`+
+			`"source": "%s",`+
+			`"lang": "painless",`+
+			`"params": { "stats": %s }},`+
+			`"upsert": {}}`,
+			converters.FormatPainlessSource(codeToExecute), string(emptyStatsSerialized),
+		)
+
+		meta := []byte(fmt.Sprintf(`{ "update" : { "_index":"%s", "_id" : "%s" } }%s`, index, ComputeEpochStatsID(shardID, epoch), "\n"))
+		err = buffSlice.PutData(meta, []byte(serializedDataStr))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// PrepareActiveAccountsQuery will prepare the query that counts the accounts of the provided shard that sent or received
+// transactions in the provided interval. The rewards are not counted
+func (esp *epochStatsProcessor) PrepareActiveAccountsQuery(shardID uint32, startTimestamp uint64, endTimestamp uint64) *bytes.Buffer {
+	query := fmt.Sprintf(`{"size": 0, "query": {"bool": {"filter": [{"range": {"timestamp": {"gte": %d, "lt": %d}}}], "must_not": [{"term": {"operation": "%s"}}]}}, `+
+		`"aggs": {`+
+		`"senders": {"filter": {"term": {"senderShard": %d}}, "aggs": {"count": {"cardinality": {"field": "sender", "precision_threshold": %d}}}}, `+
+		`"receivers": {"filter": {"term": {"receiverShard": %d}}, "aggs": {"count": {"cardinality": {"field": "receiver", "precision_threshold": %d}}}}}}`,
+		startTimestamp, endTimestamp, rewardsOperation, shardID, activeAccountsPrecisionThreshold, shardID, activeAccountsPrecisionThreshold)
+
+	return bytes.NewBuffer([]byte(query))
+}
+
+// SerializeEpochActiveAccounts will serialize the request that sets the number of accounts of the provided shard that
+// sent or received transactions in the provided epoch. The statistics of an epoch without indexed blocks are not created
+func (esp *epochStatsProcessor) SerializeEpochActiveAccounts(shardID uint32, epoch uint32, activeSenders uint64, activeReceivers uint64, buffSlice *data.BufferSlice, index string) error {
+	codeToExecute := `
+		if ('create' == ctx.op) {
+			ctx.op = 'noop'
+		} else {
+			ctx._source.activeSenders = params.activeSenders;
+			ctx._source.activeReceivers = params.activeReceivers;
+		}
+`
+	serializedDataStr := fmt.Sprintf(`{"scripted_upsert": true, "script": {`+
+		`"source": "%s",`+
+		`"lang": "painless",`+
+		`"params": { "activeSenders": %d, "activeReceivers": %d }},`+
+		`"upsert": {}}`,
+		converters.FormatPainlessSource(codeToExecute), activeSenders, activeReceivers,
+	)
+
+	meta := []byte(fmt.Sprintf(`{ "update" : { "_index":"%s", "_id" : "%s" } }%s`, index, ComputeEpochStatsID(shardID, epoch), "\n"))
+
+	return buffSlice.PutData(meta, []byte(serializedDataStr))
+}
+
+// ComputeEpochStatsID will compute the id of the statistics of the provided shard for the provided epoch
+func ComputeEpochStatsID(shardID uint32, epoch uint32) string {
+	return fmt.Sprintf("%d_%d", shardID, epoch)
+}
@@ -0,0 +1,114 @@
+package epochstats
+
+import (
+	"testing"
+
+	"github.com/kalyan3104/k-chain-es-indexer-go/data"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEpochStatsProcessor_SerializeEpochStats(t *testing.T) {
+	t.Parallel()
+
+	esp := createEpochStatsProcessor()
+
+	buffSlice := data.NewBufferSlice(data.DefaultMaxBulkSize)
+	err := esp.SerializeEpochStats(nil, buffSlice, "epochstats")
+	require.Nil(t, err)
+	require.Equal(t, 0, len(buffSlice.Buffers()))
+
+	stats := &data.EpochStats{
+		Epoch:          2,
+		ShardID:        1,
+		NumBlocks:      1,
+		TxCount:        1,
+		TxsByOperation: map[string]uint64{"transfer": 1},
+		TxsByStatus:    map[string]uint64{"success": 1},
+		Fees:           "10",
+		DeveloperFees:  "1",
+		Timestamp:      100,
+	}
+	err = esp.SerializeEpochStats(stats, buffSlice, "epochstats")
+	require.Nil(t, err)
+
+	expectedRes := `{ "update" : { "_index":"epochstats", "_id" : "1_2" } }
+{"scripted_upsert": true, "script": {"source": "if ('create' == ctx.op) {ctx._source = params.stats} else {ctx._source.numBlocks += params.stats.numBlocks;ctx._source.txCount += params.stats.txCount;ctx._source.scResultsCount += params.stats.scResultsCount;ctx._source.gasUsed += params.stats.gasUsed;ctx._source.newContracts += params.stats.newContracts;ctx._source.newAccounts = ctx._source.getOrDefault('newAccounts', 0) + params.stats.newAccounts;ctx._source.tokenTransfers += params.stats.tokenTransfers;ctx._source.feesNum += params.stats.feesNum;ctx._source.developerFeesNum += params.stats.developerFeesNum;ctx._source.fees = new BigInteger(ctx._source.fees).add(new BigInteger(params.stats.fees)).toString();ctx._source.developerFees = new BigInteger(ctx._source.developerFees).add(new BigInteger(params.stats.developerFees)).toString();for (String key : params.stats.txsByOperation.keySet()) {ctx._source.txsByOperation[key] = ctx._source.txsByOperation.getOrDefault(key, 0) + params.stats.txsByOperation[key];}for (String key : params.stats.txsByStatus.keySet()) {ctx._source.txsByStatus[key] = ctx._source.txsByStatus.getOrDefault(key, 0) + params.stats.txsByStatus[key];}long startTimestamp = ctx._source.getOrDefault('startTimestamp', 0);if (startTimestamp == 0 || params.stats.startTimestamp < startTimestamp) {ctx._source.startTimestamp = params.stats.startTimestamp;}if (params.stats.timestamp > ctx._source.timestamp) {ctx._source.timestamp = params.stats.timestamp;}}","lang": "painless","params": { "stats": {"epoch":2,"shardID":1,"numBlocks":1,"txCount":1,"txsByOperation":{"transfer":1},"txsByStatus":{"success":1},"scResultsCount":0,"fees":"10","feesNum":0,"developerFees":"1","developerFeesNum":0,"gasUsed":0,"activeSenders":0,"activeReceivers":0,"newContracts":0,"newAccounts":0,"tokenTransfers":0,"finalized":false,"startTimestamp":0,"timestamp":100} }},"upsert": {}}
+`
+	require.Equal(t, expectedRes, buffSlice.Buffers()[0].String())
+}
+
+func TestEpochStatsProcessor_SerializeEpochStatsFinalization(t *testing.T) {
+	t.Parallel()
+
+	esp := createEpochStatsProcessor()
+
+	buffSlice := data.NewBufferSlice(data.DefaultMaxBulkSize)
+	err := esp.SerializeEpochStatsFinalization(4, 1, 200, buffSlice, "epochstats")
+	require.Nil(t, err)
+
+	expectedRes := `{ "update" : { "_index":"epochstats", "_id" : "0_4" } }
+{"scripted_upsert": true, "script": {"source": "if ('create' == ctx.op) {ctx._source = params.stats} else {ctx._source.finalized = true}","lang": "painless","params": { "stats": {"epoch":4,"shardID":0,"numBlocks":0,"txCount":0,"txsByOperation":{},"txsByStatus":{},"scResultsCount":0,"fees":"0","feesNum":0,"developerFees":"0","developerFeesNum":0,"gasUsed":0,"activeSenders":0,"activeReceivers":0,"newContracts":0,"newAccounts":0,"tokenTransfers":0,"finalized":true,"startTimestamp":0,"timestamp":200} }},"upsert": {}}
+{ "update" : { "_index":"epochstats", "_id" : "4294967295_4" } }
+{"scripted_upsert": true, "script": {"source": "if ('create' == ctx.op) {ctx._source = params.stats} else {ctx._source.finalized = true}","lang": "painless","params": { "stats": {"epoch":4,"shardID":4294967295,"numBlocks":0,"txCount":0,"txsByOperation":{},"txsByStatus":{},"scResultsCount":0,"fees":"0","feesNum":0,"developerFees":"0","developerFeesNum":0,"gasUsed":0,"activeSenders":0,"activeReceivers":0,"newContracts":0,"newAccounts":0,"tokenTransfers":0,"finalized":true,"startTimestamp":0,"timestamp":200} }},"upsert": {}}
+`
+	require.Equal(t, expectedRes, buffSlice.Buffers()[0].String())
+}
+
+func TestEpochStatsProcessor_SerializeEpochStatsRevert(t *testing.T) {
+	t.Parallel()
+
+	esp := createEpochStatsProcessor()
+
+	buffSlice := data.NewBufferSlice(data.DefaultMaxBulkSize)
+	err := esp.SerializeEpochStatsRevert(nil, buffSlice, "epochstats")
+	require.Nil(t, err)
+	require.Equal(t, 0, len(buffSlice.Buffers()))
+
+	stats := &data.EpochStats{
+		Epoch:          2,
+		ShardID:        1,
+		NumBlocks:      1,
+		TxCount:        1,
+		TxsByOperation: map[string]uint64{"transfer": 1},
+		TxsByStatus:    map[string]uint64{"success": 1},
+		Fees:           "10",
+		DeveloperFees:  "1",
+		NewAccounts:    2,
+		Timestamp:      100,
+	}
+	err = esp.SerializeEpochStatsRevert(stats, buffSlice, "epochstats")
+	require.Nil(t, err)
+
+	expectedRes := `{ "update" : { "_index":"epochstats", "_id" : "1_2" } }
+{"scripted_upsert": true, "script": {"source": "if ('create' == ctx.op) {ctx.op = 'noop'} else {ctx._source.numBlocks -= params.stats.numBlocks;ctx._source.txCount -= params.stats.txCount;ctx._source.scResultsCount -= params.stats.scResultsCount;ctx._source.gasUsed -= params.stats.gasUsed;ctx._source.newContracts -= params.stats.newContracts;ctx._source.newAccounts = ctx._source.getOrDefault('newAccounts', 0) - params.stats.newAccounts;ctx._source.tokenTransfers -= params.stats.tokenTransfers;ctx._source.feesNum -= params.stats.feesNum;ctx._source.developerFeesNum -= params.stats.developerFeesNum;ctx._source.fees = new BigInteger(ctx._source.fees).subtract(new BigInteger(params.stats.fees)).toString();ctx._source.developerFees = new BigInteger(ctx._source.developerFees).subtract(new BigInteger(params.stats.developerFees)).toString();for (String key : params.stats.txsByOperation.keySet()) {long value = ctx._source.txsByOperation.getOrDefault(key, 0) - params.stats.txsByOperation[key];if (value <= 0) {ctx._source.txsByOperation.remove(key);} else {ctx._source.txsByOperation[key] = value;}}for (String key : params.stats.txsByStatus.keySet()) {long value = ctx._source.txsByStatus.getOrDefault(key, 0) - params.stats.txsByStatus[key];if (value <= 0) {ctx._source.txsByStatus.remove(key);} else {ctx._source.txsByStatus[key] = value;}}if (ctx._source.numBlocks <= 0 && !ctx._source.finalized) {ctx.op = 'delete';}}","lang": "painless","params": { "stats": {"epoch":2,"shardID":1,"numBlocks":1,"txCount":1,"txsByOperation":{"transfer":1},"txsByStatus":{"success":1},"scResultsCount":0,"fees":"10","feesNum":0,"developerFees":"1","developerFeesNum":0,"gasUsed":0,"activeSenders":0,"activeReceivers":0,"newContracts":0,"newAccounts":2,"tokenTransfers":0,"finalized":false,"startTimestamp":0,"timestamp":100} }},"upsert": {}}
+`
+	require.Equal(t, expectedRes, buffSlice.Buffers()[0].String())
+}
+
+func TestEpochStatsProcessor_PrepareActiveAccountsQuery(t *testing.T) {
+	t.Parallel()
+
+	esp := createEpochStatsProcessor()
+
+	query := esp.PrepareActiveAccountsQuery(1, 100, 200)
+	expectedQuery := `{"size": 0, "query": {"bool": {"filter": [{"range": {"timestamp": {"gte": 100, "lt": 200}}}], "must_not": [{"term": {"operation": "reward"}}]}}, ` +
+		`"aggs": {` +
+		`"senders": {"filter": {"term": {"senderShard": 1}}, "aggs": {"count": {"cardinality": {"field": "sender", "precision_threshold": 40000}}}}, ` +
+		`"receivers": {"filter": {"term": {"receiverShard": 1}}, "aggs": {"count": {"cardinality": {"field": "receiver", "precision_threshold": 40000}}}}}}`
+	require.Equal(t, expectedQuery, query.String())
+}
+
+func TestEpochStatsProcessor_SerializeEpochActiveAccounts(t *testing.T) {
+	t.Parallel()
+
+	esp := createEpochStatsProcessor()
+
+	buffSlice := data.NewBufferSlice(data.DefaultMaxBulkSize)
+	err := esp.SerializeEpochActiveAccounts(1, 2, 30, 40, buffSlice, "epochstats")
+	require.Nil(t, err)
+
+	expectedRequest := `{ "update" : { "_index":"epochstats", "_id" : "1_2" } }
+{"scripted_upsert": true, "script": {"source": "if ('create' == ctx.op) {ctx.op = 'noop'} else {ctx._source.activeSenders = params.activeSenders;ctx._source.activeReceivers = params.activeReceivers;}","lang": "painless","params": { "activeSenders": 30, "activeReceivers": 40 }},"upsert": {}}
+`
+	require.Equal(t, expectedRequest, buffSlice.Buffers()[0].String())
+}
@@ -1,6 +1,8 @@
 package factory
 
 import (
+	"time"
+
 	"github.com/kalyan3104/k-chain-core-go/core"
 	"github.com/kalyan3104/k-chain-core-go/hashing"
 	"github.com/kalyan3104/k-chain-core-go/marshal"
@@ -8,13 +10,20 @@ import (
 	"github.com/kalyan3104/k-chain-es-indexer-go/process/elasticproc"
 	"github.com/kalyan3104/k-chain-es-indexer-go/process/elasticproc/accounts"
 	blockProc "github.com/kalyan3104/k-chain-es-indexer-go/process/elasticproc/block"
+	"github.com/kalyan3104/k-chain-es-indexer-go/process/elasticproc/calltree"
+	"github.com/kalyan3104/k-chain-es-indexer-go/process/elasticproc/consensusstats"
+	"github.com/kalyan3104/k-chain-es-indexer-go/process/elasticproc/contractstats"
 	"github.com/kalyan3104/k-chain-es-indexer-go/process/elasticproc/converters"
+	"github.com/kalyan3104/k-chain-es-indexer-go/process/elasticproc/epochstats"
+	"github.com/kalyan3104/k-chain-es-indexer-go/process/elasticproc/journal"
 	"github.com/kalyan3104/k-chain-es-indexer-go/process/elasticproc/logsevents"
 	"github.com/kalyan3104/k-chain-es-indexer-go/process/elasticproc/miniblocks"
 	"github.com/kalyan3104/k-chain-es-indexer-go/process/elasticproc/operations"
 	"github.com/kalyan3104/k-chain-es-indexer-go/process/elasticproc/statistics"
+	"github.com/kalyan3104/k-chain-es-indexer-go/process/elasticproc/statscontributions"
 	"github.com/kalyan3104/k-chain-es-indexer-go/process/elasticproc/templatesAndPolicies"
 	"github.com/kalyan3104/k-chain-es-indexer-go/process/elasticproc/transactions"
+	"github.com/kalyan3104/k-chain-es-indexer-go/process/elasticproc/txlifecycle"
 	"github.com/kalyan3104/k-chain-es-indexer-go/process/elasticproc/validators"
 )
 
@@ -25,8 +34,10 @@ type ArgElasticProcessorFactory struct {
 	AddressPubkeyConverter   core.PubkeyConverter
 	ValidatorPubkeyConverter core.PubkeyConverter
 	DBClient                 elasticproc.DatabaseClientHandler
+	StagesRecorder           elasticproc.BlockStagesRecorder
 	EnabledIndexes           []string
 	Version                  string
+	ContributionsRetention   time.Duration
 	Denomination             int
 	BulkRequestMaxSize       int
 	UseKibana                bool
@@ -105,23 +116,42 @@ func CreateElasticProcessor(arguments ArgElasticProcessorFactory) (dataindexer.E
 		return nil, err
 	}
 
+	epochStatsProc, err := epochstats.NewEpochStatsProcessor(balanceConverter)
+	if err != nil {
+		return nil, err
+	}
+
+	contractStatsProc, err := contractstats.NewContractStatsProcessor(arguments.AddressPubkeyConverter, balanceConverter)
+	if err != nil {
+		return nil, err
+	}
+
 	args := &elasticproc.ArgElasticProcessor{
-		BulkRequestMaxSize: arguments.BulkRequestMaxSize,
-		TransactionsProc:   txsProc,
-		AccountsProc:       accountsProc,
-		BlockProc:          blockProcHandler,
-		MiniblocksProc:     miniblocksProc,
-		ValidatorsProc:     validatorsProc,
-		StatisticsProc:     generalInfoProc,
-		LogsAndEventsProc:  logsAndEventsProc,
-		DBClient:           arguments.DBClient,
-		EnabledIndexes:     enabledIndexesMap,
-		UseKibana:          arguments.UseKibana,
-		IndexTemplates:     indexTemplates,
-		IndexPolicies:      indexPolicies,
-		OperationsProc:     operationsProc,
-		ImportDB:           arguments.ImportDB,
-		Version:            arguments.Version,
+		BulkRequestMaxSize:     arguments.BulkRequestMaxSize,
+		TransactionsProc:       txsProc,
+		AccountsProc:           accountsProc,
+		BlockProc:              blockProcHandler,
+		MiniblocksProc:         miniblocksProc,
+		ValidatorsProc:         validatorsProc,
+		StatisticsProc:         generalInfoProc,
+		LogsAndEventsProc:      logsAndEventsProc,
+		DBClient:               arguments.DBClient,
+		EnabledIndexes:         enabledIndexesMap,
+		UseKibana:              arguments.UseKibana,
+		IndexTemplates:         indexTemplates,
+		IndexPolicies:          indexPolicies,
+		OperationsProc:         operationsProc,
+		EpochStatsProc:         epochStatsProc,
+		ContractStatsProc:      contractStatsProc,
+		ConsensusStatsProc:     consensusstats.NewConsensusStatsProcessor(),
+		TxLifecycleProc:        txlifecycle.NewTxLifecycleProcessor(),
+		CallTreeProc:           calltree.NewCallTreeProcessor(),
+		JournalProc:            journal.NewJournalProcessor(),
+		StatsContributionsProc: statscontributions.NewStatsContributionsProcessor(),
+		StagesRecorder:         arguments.StagesRecorder,
+		ImportDB:               arguments.ImportDB,
+		Version:                arguments.Version,
+		ContributionsRetention: arguments.ContributionsRetention,
 	}
 
 	return elasticproc.NewElasticProcessor(args)
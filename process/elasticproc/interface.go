@@ -3,12 +3,14 @@ package elasticproc
 import (
 	"bytes"
 	"context"
+	"time"
 
 	coreData "github.com/kalyan3104/k-chain-core-go/data"
 	"github.com/kalyan3104/k-chain-core-go/data/alteredAccount"
 	"github.com/kalyan3104/k-chain-core-go/data/block"
 	"github.com/kalyan3104/k-chain-core-go/data/outport"
 	"github.com/kalyan3104/k-chain-es-indexer-go/data"
+	"github.com/kalyan3104/k-chain-es-indexer-go/process/elasticproc/stagetimings"
 	"github.com/kalyan3104/k-chain-es-indexer-go/process/elasticproc/tokeninfo"
 )
 
@@ -19,6 +21,8 @@ type DatabaseClientHandler interface {
 	DoMultiGet(ctx context.Context, ids []string, index string, withSource bool, res interface{}) error
 	DoScrollRequest(ctx context.Context, index string, body []byte, withSource bool, handlerFunc func(responseBytes []byte) error) error
 	DoCountRequest(ctx context.Context, index string, body []byte) (uint64, error)
+	DoSearchRequest(ctx context.Context, index string, body []byte, resBody interface{}) error
+	DoRefreshRequest(ctx context.Context, index string) error
 	UpdateByQuery(ctx context.Context, index string, buff *bytes.Buffer) error
 
 	CheckAndCreateIndex(index string) error
@@ -38,6 +42,15 @@ type DBAccountHandler interface {
 	PutTokenMedataDataInTokens(tokensData []*data.TokenInfo, coreAlteredAccounts map[string]*alteredAccount.AlteredAccount)
 
 	SerializeAccountsHistory(accounts map[string]*data.AccountBalanceHistory, buffSlice *data.BufferSlice, index string) error
+	PrepareAccountsHistoryQueryInCaseOfRevert(timestamp uint64, shardID uint32) *bytes.Buffer
+	PrepareLatestAccountsHistoryQuery(addresses []string, revertedTimestamp uint64) (*bytes.Buffer, error)
+	SerializeAccountsRestore(
+		revertedTimestamp uint64,
+		revertedEntries []*data.AccountBalanceHistory,
+		latestEntries map[string]*data.AccountBalanceHistory,
+		buffSlice *data.BufferSlice,
+		index string,
+	) error
 	SerializeAccounts(accounts map[string]*data.AccountInfo, buffSlice *data.BufferSlice, index string) error
 	SerializeAccountsDCDT(accounts map[string]*data.AccountInfo, updateNFTData []*data.NFTDataUpdate, buffSlice *data.BufferSlice, index string) error
 	SerializeNFTCreateInfo(tokensInfo []*data.TokenInfo, buffSlice *data.BufferSlice, index string) error
@@ -87,6 +100,11 @@ type DBStatisticsHandler interface {
 type DBValidatorsHandler interface {
 	PrepareAnSerializeValidatorsPubKeys(validatorsPubKeys *outport.ValidatorsPubKeys) ([]*bytes.Buffer, error)
 	SerializeValidatorsRating(ratingData *outport.ValidatorsRating) ([]*bytes.Buffer, error)
+	SerializeValidatorsRatingHistory(ratingData *outport.ValidatorsRating) ([]*bytes.Buffer, error)
+	PrepareValidatorsHistory(validatorsPubKeys *outport.ValidatorsPubKeys, previousPubKeys map[uint32][]string) []*data.ValidatorHistory
+	SerializeValidatorsHistory(validatorsHistory []*data.ValidatorHistory) ([]*bytes.Buffer, error)
+	PrepareValidatorsHistoryQuery(shardIDs []uint32, epoch uint32) *bytes.Buffer
+	ComputePubKeysFromHistory(validatorsHistory []*data.ValidatorHistory) map[uint32][]string
 }
 
 // DBLogsAndEventsHandler defines the actions that a logs and events handler should do
@@ -106,6 +124,7 @@ type DBLogsAndEventsHandler interface {
 	SerializeChangeOwnerOperations(changeOwnerOperations map[string]*data.OwnerData, buffSlice *data.BufferSlice, index string) error
 	SerializeTokens(tokens []*data.TokenInfo, updateNFTData []*data.NFTDataUpdate, buffSlice *data.BufferSlice, index string) error
 	SerializeDelegators(delegators map[string]*data.Delegator, buffSlice *data.BufferSlice, index string) error
+	SerializeDelegationProviders(updates map[string]*data.DelegationProviderUpdate, buffSlice *data.BufferSlice, index string) error
 	SerializeSupplyData(tokensSupply data.TokensHandler, buffSlice *data.BufferSlice, index string) error
 	SerializeRolesData(
 		tokenRolesAndProperties *tokeninfo.TokenRolesAndProperties,
@@ -113,6 +132,98 @@ type DBLogsAndEventsHandler interface {
 		index string,
 	) error
 	PrepareDelegatorsQueryInCaseOfRevert(timestamp uint64) *bytes.Buffer
+	PrepareDelegationProvidersQueryInCaseOfRevert(timestamp uint64) *bytes.Buffer
+}
+
+// DBEpochStatsHandler defines the actions that an epoch statistics handler should do
+type DBEpochStatsHandler interface {
+	PrepareEpochStats(
+		header coreData.HeaderHandler,
+		preparedResults *data.PreparedResults,
+		logsResults *data.PreparedLogsResults,
+		newAccounts uint64,
+	) *data.EpochStats
+	SerializeEpochStats(stats *data.EpochStats, buffSlice *data.BufferSlice, index string) error
+	SerializeEpochStatsRevert(stats *data.EpochStats, buffSlice *data.BufferSlice, index string) error
+	SerializeEpochStatsFinalization(epoch uint32, numOfShards uint32, timestamp uint64, buffSlice *data.BufferSlice, index string) error
+	PrepareActiveAccountsQuery(shardID uint32, startTimestamp uint64, endTimestamp uint64) *bytes.Buffer
+	SerializeEpochActiveAccounts(shardID uint32, epoch uint32, activeSenders uint64, activeReceivers uint64, buffSlice *data.BufferSlice, index string) error
+}
+
+// DBContractStatsHandler defines the actions that a smart contracts statistics handler should do
+type DBContractStatsHandler interface {
+	PrepareContractStats(
+		txs []*data.Transaction,
+		scrs []*data.ScResult,
+		txHashStatusInfo map[string]*outport.StatusInfo,
+		shardID uint32,
+	) map[string]*data.ContractStats
+	SerializeContractStats(statsMap map[string]*data.ContractStats, buffSlice *data.BufferSlice, index string) error
+	SerializeContractStatsRevert(statsMap map[string]*data.ContractStats, buffSlice *data.BufferSlice, index string) error
+	PrepareUniqueCallersQuery(shardID uint32, day time.Duration, afterAddress string) *bytes.Buffer
+	SerializeContractsUniqueCallers(uniqueCallers map[string]uint64, day time.Duration, buffSlice *data.BufferSlice, index string) error
+}
+
+// DBStatsContributionsHandler defines the actions that a statistics contributions handler should do
+type DBStatsContributionsHandler interface {
+	PrepareStatsContribution(index string, key string, shardID uint32, timestamp uint64, stats interface{}) (*data.StatsContribution, error)
+	ExtractStatsContributions(response *data.ResponseRawDocuments) (map[string]*data.StatsContribution, error)
+	SerializeStatsContributions(contributions []*data.StatsContribution, buffSlice *data.BufferSlice, index string) error
+	SerializeStatsContributionsRemoval(contributions []*data.StatsContribution, buffSlice *data.BufferSlice, index string) error
+	PrepareExpiredStatsContributionsQuery(shardID uint32, timestamp uint64) *bytes.Buffer
+}
+
+// DBConsensusStatsHandler defines the actions that a consensus statistics handler should do
+type DBConsensusStatsHandler interface {
+	ResolveValidatorsPubKeys(ids []string, fetchHandler func(ids []string) (map[string][]string, error)) (map[string][]string, error)
+	PrepareConsensusStatsFromRounds(roundsInfo []*outport.RoundInfo, validatorsLists map[string][]string) map[string]*data.ConsensusStats
+	PrepareConsensusStatsFromBlock(header coreData.HeaderHandler, signersIndexes []uint64, validatorsLists map[string][]string) map[string]*data.ConsensusStats
+	SerializeConsensusStats(statsMap map[string]*data.ConsensusStats, buffSlice *data.BufferSlice, index string) error
+	SerializeConsensusStatsRevert(statsMap map[string]*data.ConsensusStats, buffSlice *data.BufferSlice, index string) error
+}
+
+// DBTxLifecycleHandler defines the actions that a transactions lifecycle handler should do
+type DBTxLifecycleHandler interface {
+	PrepareTxLifecycles(
+		header coreData.HeaderHandler,
+		blockHash string,
+		txs []*data.Transaction,
+		scrs []*data.ScResult,
+		txHashStatusInfo map[string]*outport.StatusInfo,
+	) map[string]*data.TxLifecycle
+	SerializeTxLifecycles(lifecycles map[string]*data.TxLifecycle, buffSlice *data.BufferSlice, index string) error
+	PrepareTxLifecyclesQueryInCaseOfRevert(blockHash string) *bytes.Buffer
+}
+
+// DBCallTreeHandler defines the actions that a call trees handler should do
+type DBCallTreeHandler interface {
+	PrepareCallTrees(
+		header coreData.HeaderHandler,
+		blockHash string,
+		txs []*data.Transaction,
+		scrs []*data.ScResult,
+		logs []*outport.LogData,
+	) map[string]*data.CallTree
+	SerializeCallTrees(callTrees map[string]*data.CallTree, buffSlice *data.BufferSlice, index string) error
+	PrepareCallTreesQueryInCaseOfRevert(blockHash string) *bytes.Buffer
+}
+
+// DBJournalHandler defines the actions that a journal handler should do
+type DBJournalHandler interface {
+	ExtractDocumentsIDs(buffers []*bytes.Buffer, indices map[string]struct{}) (map[string][]string, error)
+	ExtractCountersIncrements(buffers []*bytes.Buffer, counterFields map[string][]string) (map[string]map[string]map[string]int64, error)
+	PrepareJournalEntries(
+		blockHash string,
+		shardID uint32,
+		timestamp uint64,
+		index string,
+		ids []string,
+		response *data.ResponseRawDocuments,
+	) []*data.JournalEntry
+	SerializeJournalEntries(entries []*data.JournalEntry, buffSlice *data.BufferSlice, index string) error
+	SerializeWrittenVersions(blockHash string, index string, response *data.ResponseRawDocuments, buffSlice *data.BufferSlice, journalIndex string) error
+	SerializeRestoreOperations(entries []*data.JournalEntry, buffSlice *data.BufferSlice) error
+	PrepareJournalQuery(blockHash string) *bytes.Buffer
 }
 
 // OperationsHandler defines the actions that an operations' handler should do
@@ -120,3 +231,9 @@ type OperationsHandler interface {
 	ProcessTransactionsAndSCRs(txs []*data.Transaction, scrs []*data.ScResult, isImportDB bool, shardID uint32) ([]*data.Transaction, []*data.ScResult)
 	SerializeSCRs(scrs []*data.ScResult, buffSlice *data.BufferSlice, index string, shardID uint32) error
 }
+
+// BlockStagesRecorder defines the actions that a component which exports the stage timings of the blocks should do
+type BlockStagesRecorder interface {
+	RecordBlockStages(timer *stagetimings.BlockTimer)
+	IsInterfaceNil() bool
+}
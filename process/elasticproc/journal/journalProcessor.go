@@ -0,0 +1,194 @@
+package journal
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"time"
+
+	"github.com/kalyan3104/k-chain-es-indexer-go/data"
+)
+
+const maxBulkLineSize = 64 * 1024 * 1024
+
+type bulkItemMetadata struct {
+	Index string `json:"_index"`
+	ID    string `json:"_id"`
+}
+
+type bulkUpdateBody struct {
+	Script *struct {
+		Params map[string]json.RawMessage `json:"params"`
+	} `json:"script"`
+}
+
+type journalProcessor struct{}
+
+// NewJournalProcessor creates a new instance of the journal processor, which keeps the previous versions of the
+// documents updated by a block so they can be restored when the block is reverted
+func NewJournalProcessor() *journalProcessor {
+	return &journalProcessor{}
+}
+
+// ExtractDocumentsIDs returns, for every one of the provided indices, the ids of the documents written by the provided
+// bulk request bodies. The ids are returned in the order in which they are first written
+func (jp *journalProcessor) ExtractDocumentsIDs(buffers []*bytes.Buffer, indices map[string]struct{}) (map[string][]string, error) {
+	docsIDs := make(map[string][]string)
+	seenIDs := make(map[string]map[string]struct{})
+
+	err := iterateBulkItems(buffers, func(_ string, metadata *bulkItemMetadata, _ []byte) error {
+		_, shouldJournal := indices[metadata.Index]
+		if !shouldJournal {
+			return nil
+		}
+		if seenIDs[metadata.Index] == nil {
+			seenIDs[metadata.Index] = make(map[string]struct{})
+		}
+		_, alreadySeen := seenIDs[metadata.Index][metadata.ID]
+		if alreadySeen {
+			return nil
+		}
+
+		seenIDs[metadata.Index][metadata.ID] = struct{}{}
+		docsIDs[metadata.Index] = append(docsIDs[metadata.Index], metadata.ID)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return docsIDs, nil
+}
+
+// ExtractCountersIncrements returns, for every one of the provided indices, the increments added by the update scripts
+// of the provided bulk request bodies to the counter fields of the documents, grouped by document id. The increment of
+// a counter field is read from the script parameter with the same name
+func (jp *journalProcessor) ExtractCountersIncrements(buffers []*bytes.Buffer, counterFields map[string][]string) (map[string]map[string]map[string]int64, error) {
+	increments := make(map[string]map[string]map[string]int64)
+
+	err := iterateBulkItems(buffers, func(action string, metadata *bulkItemMetadata, body []byte) error {
+		fields, hasCounters := counterFields[metadata.Index]
+		if !hasCounters || action != "update" {
+			return nil
+		}
+
+		update := &bulkUpdateBody{}
+		err := json.Unmarshal(body, update)
+		if err != nil {
+			return err
+		}
+		if update.Script == nil {
+			return nil
+		}
+
+		for _, field := range fields {
+			param, found := update.Script.Params[field]
+			if !found {
+				continue
+			}
+
+			var increment int64
+			err = json.Unmarshal(param, &increment)
+			if err != nil {
+				return err
+			}
+
+			if increments[metadata.Index] == nil {
+				increments[metadata.Index] = make(map[string]map[string]int64)
+			}
+			if increments[metadata.Index][metadata.ID] == nil {
+				increments[metadata.Index][metadata.ID] = make(map[string]int64)
+			}
+			increments[metadata.Index][metadata.ID][field] += increment
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return increments, nil
+}
+
+func iterateBulkItems(buffers []*bytes.Buffer, handler func(action string, metadata *bulkItemMetadata, body []byte) error) error {
+	for _, buff := range buffers {
+		scanner := bufio.NewScanner(bytes.NewReader(buff.Bytes()))
+		scanner.Buffer(make([]byte, 0, bufio.MaxScanTokenSize), maxBulkLineSize)
+
+		for scanner.Scan() {
+			action, metadata, err := parseActionLine(scanner.Bytes())
+			if err != nil {
+				return err
+			}
+			// all the actions, except the delete, are followed by the document or by the update script
+			var body []byte
+			if action != "delete" {
+				scanner.Scan()
+				body = scanner.Bytes()
+			}
+
+			err = handler(action, metadata, body)
+			if err != nil {
+				return err
+			}
+		}
+
+		err := scanner.Err()
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func parseActionLine(line []byte) (string, *bulkItemMetadata, error) {
+	actionLine := make(map[string]*bulkItemMetadata)
+	err := json.Unmarshal(line, &actionLine)
+	if err != nil {
+		return "", nil, err
+	}
+
+	for action, metadata := range actionLine {
+		if metadata != nil {
+			return action, metadata, nil
+		}
+	}
+
+	return "", &bulkItemMetadata{}, nil
+}
+
+// PrepareJournalEntries will prepare the journal entries of the provided documents from the multi get response, a
+// document that is not found being recorded as one that has to be deleted on revert
+func (jp *journalProcessor) PrepareJournalEntries(
+	blockHash string,
+	shardID uint32,
+	timestamp uint64,
+	index string,
+	ids []string,
+	response *data.ResponseRawDocuments,
+) []*data.JournalEntry {
+	foundDocs := make(map[string]json.RawMessage)
+	for _, doc := range response.Docs {
+		if doc.Found {
+			foundDocs[doc.ID] = doc.Source
+		}
+	}
+
+	entries := make([]*data.JournalEntry, 0, len(ids))
+	for _, id := range ids {
+		source, found := foundDocs[id]
+		entries = append(entries, &data.JournalEntry{
+			BlockHash: blockHash,
+			ShardID:   shardID,
+			Timestamp: time.Duration(timestamp),
+			Index:     index,
+			DocID:     id,
+			Existed:   found,
+			Source:    source,
+		})
+	}
+
+	return entries
+}
@@ -0,0 +1,87 @@
+package journal
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/kalyan3104/k-chain-es-indexer-go/data"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJournalProcessor_ExtractDocumentsIDs(t *testing.T) {
+	t.Parallel()
+
+	buffSlice := data.NewBufferSlice(data.DefaultMaxBulkSize)
+	_ = buffSlice.PutData([]byte(`{ "update" : { "_index":"tokens", "_id" : "TKN-abcd" } }`+"\n"), []byte(`{"doc": {"type": "NonFungibleDCDT"}}`))
+	_ = buffSlice.PutData([]byte(`{ "index" : { "_index":"transactions", "_id" : "aabb" } }`+"\n"), []byte(`{"nonce": 1}`))
+	_ = buffSlice.PutData([]byte(`{ "delete" : { "_index": "tags", "_id" : "dGFn" } }`+"\n"), nil)
+	_ = buffSlice.PutData([]byte(`{ "update" : {"_index": "tokens", "_id" : "TKN-abcd" } }`+"\n"), []byte(`{"script": {"source": "ctx._source.owner = params.owner"}}`))
+	_ = buffSlice.PutData([]byte(`{"update":{ "_index":"dcdts","_id":"DCT-1234"}}`+"\n"), []byte(`{"doc": {}, "doc_as_upsert": true}`))
+
+	jp := NewJournalProcessor()
+	docsIDs, err := jp.ExtractDocumentsIDs(buffSlice.Buffers(), map[string]struct{}{"tokens": {}, "tags": {}, "dcdts": {}})
+	require.Nil(t, err)
+	require.Equal(t, map[string][]string{
+		"tokens": {"TKN-abcd"},
+		"tags":   {"dGFn"},
+		"dcdts":  {"DCT-1234"},
+	}, docsIDs)
+
+	_, err = jp.ExtractDocumentsIDs([]*bytes.Buffer{bytes.NewBufferString("not json\n")}, map[string]struct{}{})
+	require.NotNil(t, err)
+}
+
+func TestJournalProcessor_ExtractCountersIncrements(t *testing.T) {
+	t.Parallel()
+
+	buffSlice := data.NewBufferSlice(data.DefaultMaxBulkSize)
+	_ = buffSlice.PutData([]byte(`{ "update" : {"_index":"tags", "_id" : "dGFn" } }`+"\n"), []byte(`{"script": {"source": "ctx._source.count += params.count","lang": "painless","params": {"count": 2, "tag": "tag"}},"upsert": {"count": 2, "tag":"tag"}}`))
+	_ = buffSlice.PutData([]byte(`{ "update" : {"_index":"tokens", "_id" : "TKN-abcd" } }`+"\n"), []byte(`{"script": {"source": "ctx._source.count += params.count","params": {"count": 5}}}`))
+	_ = buffSlice.PutData([]byte(`{ "delete" : { "_index": "tags", "_id" : "b3RoZXI=" } }`+"\n"), nil)
+	_ = buffSlice.PutData([]byte(`{ "update" : {"_index":"tags", "_id" : "dGFn" } }`+"\n"), []byte(`{"script": {"source": "ctx._source.count += params.count","lang": "painless","params": {"count": 3, "tag": "tag"}},"upsert": {"count": 3, "tag":"tag"}}`))
+	_ = buffSlice.PutData([]byte(`{ "update" : {"_index":"tags", "_id" : "bmV3" } }`+"\n"), []byte(`{"doc": {"tag": "new"}}`))
+
+	jp := NewJournalProcessor()
+	increments, err := jp.ExtractCountersIncrements(buffSlice.Buffers(), map[string][]string{"tags": {"count"}})
+	require.Nil(t, err)
+	require.Equal(t, map[string]map[string]map[string]int64{
+		"tags": {"dGFn": {"count": 5}},
+	}, increments)
+
+	invalidBuffer := bytes.NewBufferString(`{ "update" : {"_index":"tags", "_id" : "dGFn" } }` + "\n" + `{"script": {"params": {"count": "one"}}}` + "\n")
+	_, err = jp.ExtractCountersIncrements([]*bytes.Buffer{invalidBuffer}, map[string][]string{"tags": {"count"}})
+	require.NotNil(t, err)
+}
+
+func TestJournalProcessor_PrepareJournalEntries(t *testing.T) {
+	t.Parallel()
+
+	response := &data.ResponseRawDocuments{
+		Docs: []data.ResponseRawDocument{
+			{Found: true, ID: "TKN-abcd", Source: json.RawMessage(`{"type":"NonFungibleDCDT"}`)},
+			{Found: false, ID: "TKN-new"},
+		},
+	}
+
+	jp := NewJournalProcessor()
+	entries := jp.PrepareJournalEntries("hash", 1, 5000, "tokens", []string{"TKN-abcd", "TKN-new"}, response)
+	require.Equal(t, []*data.JournalEntry{
+		{
+			BlockHash: "hash",
+			ShardID:   1,
+			Timestamp: 5000,
+			Index:     "tokens",
+			DocID:     "TKN-abcd",
+			Existed:   true,
+			Source:    json.RawMessage(`{"type":"NonFungibleDCDT"}`),
+		},
+		{
+			BlockHash: "hash",
+			ShardID:   1,
+			Timestamp: 5000,
+			Index:     "tokens",
+			DocID:     "TKN-new",
+		},
+	}, entries)
+}
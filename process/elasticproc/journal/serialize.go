@@ -0,0 +1,233 @@
+package journal
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/kalyan3104/k-chain-es-indexer-go/data"
+	"github.com/kalyan3104/k-chain-es-indexer-go/process/elasticproc/converters"
+)
+
+// SerializeJournalEntries will serialize the provided journal entries in a way that Elasticsearch expects a bulk
+// request. An entry that already exists is not overwritten, so when a block is indexed again the journal keeps the
+// versions from before its first indexing
+func (jp *journalProcessor) SerializeJournalEntries(entries []*data.JournalEntry, buffSlice *data.BufferSlice, index string) error {
+	for _, entry := range entries {
+		entrySerialized, err := json.Marshal(entry)
+		if err != nil {
+			return err
+		}
+
+		serializedDataStr := fmt.Sprintf(`{"script": {"source": "ctx.op = 'noop'", "lang": "painless"}, "upsert": %s}`, string(entrySerialized))
+
+		id := computeJournalEntryID(entry.BlockHash, entry.Index, entry.DocID)
+		meta := []byte(fmt.Sprintf(`{ "update" : { "_index":"%s", "_id" : "%s" } }%s`, index, converters.JsonEscape(id), "\n"))
+		err = buffSlice.PutData(meta, []byte(serializedDataStr))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// SerializeWrittenVersions will serialize the requests that add to the journal entries of the provided block the
+// versions of the documents written by it, read after the block was indexed. The documents that are not found are skipped
+func (jp *journalProcessor) SerializeWrittenVersions(
+	blockHash string,
+	index string,
+	response *data.ResponseRawDocuments,
+	buffSlice *data.BufferSlice,
+	journalIndex string,
+) error {
+	for _, doc := range response.Docs {
+		if !doc.Found {
+			continue
+		}
+
+		serializedDataStr := fmt.Sprintf(`{"doc": {"written": %s}}`, string(doc.Source))
+		id := computeJournalEntryID(blockHash, index, doc.ID)
+		meta := []byte(fmt.Sprintf(`{ "update" : { "_index":"%s", "_id" : "%s" } }%s`, journalIndex, converters.JsonEscape(id), "\n"))
+		err := buffSlice.PutData(meta, []byte(serializedDataStr))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// SerializeRestoreOperations will serialize the operations that bring the journaled documents to their previous
+// versions. The documents are updated by many shards, so only the fields changed by the block are restored, and only
+// if they still hold the values written by the block. The fields changed afterwards by other blocks are kept. The
+// counter fields get the increments of the block subtracted, since other blocks might have incremented them as well. A
+// document created by the block is deleted if no other block changed it. The entries without the written version,
+// journaled by an indexing that did not finish, restore or delete the whole document
+func (jp *journalProcessor) SerializeRestoreOperations(entries []*data.JournalEntry, buffSlice *data.BufferSlice) error {
+	for _, entry := range entries {
+		if len(entry.Written) > 0 {
+			err := serializeFieldsRestore(entry, buffSlice)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		if !entry.Existed {
+			meta := []byte(fmt.Sprintf(`{ "delete" : { "_index": "%s", "_id" : "%s" } }%s`, entry.Index, converters.JsonEscape(entry.DocID), "\n"))
+			err := buffSlice.PutData(meta, nil)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		meta := []byte(fmt.Sprintf(`{ "index" : { "_index":"%s", "_id" : "%s" } }%s`, entry.Index, converters.JsonEscape(entry.DocID), "\n"))
+		err := buffSlice.PutData(meta, entry.Source)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// PrepareJournalQuery will prepare the query that selects the journal entries of the provided block
+func (jp *journalProcessor) PrepareJournalQuery(blockHash string) *bytes.Buffer {
+	query := fmt.Sprintf(`{"query": {"term": {"blockHash": "%s"}}}`, converters.JsonEscape(blockHash))
+
+	return bytes.NewBuffer([]byte(query))
+}
+
+func serializeFieldsRestore(entry *data.JournalEntry, buffSlice *data.BufferSlice) error {
+	written := make(map[string]json.RawMessage)
+	err := json.Unmarshal(entry.Written, &written)
+	if err != nil {
+		return err
+	}
+
+	previous := make(map[string]json.RawMessage)
+	if entry.Existed {
+		err = json.Unmarshal(entry.Source, &previous)
+		if err != nil {
+			return err
+		}
+	}
+
+	changedFields := computeChangedFields(previous, written, entry.Increments)
+	if len(changedFields) == 0 && len(entry.Increments) == 0 {
+		return nil
+	}
+
+	previousChanged := make(map[string]json.RawMessage, len(changedFields))
+	writtenChanged := make(map[string]json.RawMessage, len(changedFields))
+	for _, field := range changedFields {
+		if value, found := previous[field]; found {
+			previousChanged[field] = value
+		}
+		if value, found := written[field]; found {
+			writtenChanged[field] = value
+		}
+	}
+
+	serializedFields, err := json.Marshal(changedFields)
+	if err != nil {
+		return err
+	}
+	serializedPrevious, err := json.Marshal(previousChanged)
+	if err != nil {
+		return err
+	}
+	serializedWritten, err := json.Marshal(writtenChanged)
+	if err != nil {
+		return err
+	}
+	increments := entry.Increments
+	if increments == nil {
+		increments = make(map[string]int64)
+	}
+	serializedIncrements, err := json.Marshal(increments)
+	if err != nil {
+		return err
+	}
+
+	// the counter fields get the increments of the block subtracted. A counter of a document created by the block that
+	// does not reach zero was incremented by other blocks as well, so the document keeps its other fields. A field that
+	// still holds the value written by the block gets its previous value back, or is removed if the document did not
+	// have it. A document without fields after the restore was created by the block, so it is deleted
+	codeToExecute := `
+		if ('create' == ctx.op) {
+			ctx.op = 'noop'
+		} else {
+			boolean countedByOtherBlocks = false;
+			for (String field : params.increments.keySet()) {
+				if (!ctx._source.containsKey(field)) {
+					continue;
+				}
+				def value = ctx._source.get(field) - params.increments.get(field);
+				if (!params.existed && value <= 0) {
+					ctx._source.remove(field);
+				} else {
+					ctx._source.put(field, value);
+					countedByOtherBlocks = countedByOtherBlocks || !params.existed;
+				}
+			}
+			if (!countedByOtherBlocks) {
+				for (String field : params.fields) {
+					if (ctx._source.get(field) == params.written.get(field)) {
+						if (params.previous.containsKey(field)) {
+							ctx._source.put(field, params.previous.get(field));
+						} else {
+							ctx._source.remove(field);
+						}
+					}
+				}
+			}
+			if (ctx._source.isEmpty()) {
+				ctx.op = 'delete';
+			}
+		}
+`
+	serializedDataStr := fmt.Sprintf(`{"scripted_upsert": true, "script": {`+
+		`"source": "%s",`+
+		`"lang": "painless",`+
+		`"params": { "fields": %s, "previous": %s, "written": %s, "increments": %s, "existed": %t }},`+
+		`"upsert": {}}`,
+		converters.FormatPainlessSource(codeToExecute), serializedFields, serializedPrevious, serializedWritten, serializedIncrements, entry.Existed,
+	)
+
+	meta := []byte(fmt.Sprintf(`{ "update" : { "_index":"%s", "_id" : "%s" } }%s`, entry.Index, converters.JsonEscape(entry.DocID), "\n"))
+	return buffSlice.PutData(meta, []byte(serializedDataStr))
+}
+
+// computeChangedFields returns, sorted, the top level fields that have different values in the provided versions,
+// except for the counter fields, which are restored from their increments
+func computeChangedFields(previous map[string]json.RawMessage, written map[string]json.RawMessage, increments map[string]int64) []string {
+	changedFields := make([]string, 0)
+	for field, writtenValue := range written {
+		if _, isCounter := increments[field]; isCounter {
+			continue
+		}
+		previousValue, found := previous[field]
+		if !found || !bytes.Equal(previousValue, writtenValue) {
+			changedFields = append(changedFields, field)
+		}
+	}
+	for field := range previous {
+		if _, isCounter := increments[field]; isCounter {
+			continue
+		}
+		if _, found := written[field]; !found {
+			changedFields = append(changedFields, field)
+		}
+	}
+	sort.Strings(changedFields)
+
+	return changedFields
+}
+
+func computeJournalEntryID(blockHash string, index string, docID string) string {
+	return fmt.Sprintf("%s_%s_%s", blockHash, index, docID)
+}
@@ -0,0 +1,126 @@
+package journal
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/kalyan3104/k-chain-es-indexer-go/data"
+	"github.com/stretchr/testify/require"
+)
+
+func createJournalEntries() []*data.JournalEntry {
+	return []*data.JournalEntry{
+		{
+			BlockHash: "hash",
+			ShardID:   1,
+			Timestamp: 5000,
+			Index:     "tokens",
+			DocID:     "TKN-abcd",
+			Existed:   true,
+			Source:    json.RawMessage(`{"type":"NonFungibleDCDT"}`),
+		},
+		{
+			BlockHash: "hash",
+			ShardID:   1,
+			Timestamp: 5000,
+			Index:     "tags",
+			DocID:     "dGFn",
+		},
+	}
+}
+
+func TestJournalProcessor_SerializeJournalEntries(t *testing.T) {
+	t.Parallel()
+
+	buffSlice := data.NewBufferSlice(data.DefaultMaxBulkSize)
+	err := NewJournalProcessor().SerializeJournalEntries(createJournalEntries(), buffSlice, "journal")
+	require.Nil(t, err)
+
+	expected := `{ "update" : { "_index":"journal", "_id" : "hash_tokens_TKN-abcd" } }
+{"script": {"source": "ctx.op = 'noop'", "lang": "painless"}, "upsert": {"blockHash":"hash","shardID":1,"timestamp":5000,"index":"tokens","docID":"TKN-abcd","existed":true,"source":{"type":"NonFungibleDCDT"}}}
+{ "update" : { "_index":"journal", "_id" : "hash_tags_dGFn" } }
+{"script": {"source": "ctx.op = 'noop'", "lang": "painless"}, "upsert": {"blockHash":"hash","shardID":1,"timestamp":5000,"index":"tags","docID":"dGFn","existed":false}}
+`
+	require.Equal(t, expected, buffSlice.Buffers()[0].String())
+}
+
+func TestJournalProcessor_SerializeRestoreOperations(t *testing.T) {
+	t.Parallel()
+
+	buffSlice := data.NewBufferSlice(data.DefaultMaxBulkSize)
+	err := NewJournalProcessor().SerializeRestoreOperations(createJournalEntries(), buffSlice)
+	require.Nil(t, err)
+
+	expected := `{ "index" : { "_index":"tokens", "_id" : "TKN-abcd" } }
+{"type":"NonFungibleDCDT"}
+{ "delete" : { "_index": "tags", "_id" : "dGFn" } }
+`
+	require.Equal(t, expected, buffSlice.Buffers()[0].String())
+}
+
+func TestJournalProcessor_SerializeWrittenVersions(t *testing.T) {
+	t.Parallel()
+
+	response := &data.ResponseRawDocuments{
+		Docs: []data.ResponseRawDocument{
+			{ID: "TKN-abcd", Found: true, Source: json.RawMessage(`{"type":"NonFungibleDCDT","currentOwner":"erd1"}`)},
+			{ID: "TKN-0000", Found: false},
+		},
+	}
+
+	buffSlice := data.NewBufferSlice(data.DefaultMaxBulkSize)
+	err := NewJournalProcessor().SerializeWrittenVersions("hash", "tokens", response, buffSlice, "journal")
+	require.Nil(t, err)
+
+	expected := `{ "update" : { "_index":"journal", "_id" : "hash_tokens_TKN-abcd" } }
+{"doc": {"written": {"type":"NonFungibleDCDT","currentOwner":"erd1"}}}
+`
+	require.Equal(t, expected, buffSlice.Buffers()[0].String())
+}
+
+func TestJournalProcessor_SerializeRestoreOperationsShouldRestoreTheChangedFieldsAndSubtractTheIncrements(t *testing.T) {
+	t.Parallel()
+
+	entries := []*data.JournalEntry{
+		{
+			Index:   "tokens",
+			DocID:   "TKN-abcd",
+			Existed: true,
+			Source:  json.RawMessage(`{"type":"NonFungibleDCDT","currentOwner":"erd1","paused":false}`),
+			Written: json.RawMessage(`{"type":"NonFungibleDCDT","currentOwner":"erd2","frozen":true}`),
+		},
+		{
+			Index:      "tags",
+			DocID:      "dGFn",
+			Written:    json.RawMessage(`{"count":3,"tag":"tag"}`),
+			Increments: map[string]int64{"count": 1},
+		},
+		{
+			Index:   "dcdts",
+			DocID:   "unchanged",
+			Existed: true,
+			Source:  json.RawMessage(`{"supply":"10"}`),
+			Written: json.RawMessage(`{"supply":"10"}`),
+		},
+	}
+
+	buffSlice := data.NewBufferSlice(data.DefaultMaxBulkSize)
+	err := NewJournalProcessor().SerializeRestoreOperations(entries, buffSlice)
+	require.Nil(t, err)
+
+	restoreScript := "if ('create' == ctx.op) {ctx.op = 'noop'} else {boolean countedByOtherBlocks = false;for (String field : params.increments.keySet()) {if (!ctx._source.containsKey(field)) {continue;}def value = ctx._source.get(field) - params.increments.get(field);if (!params.existed && value <= 0) {ctx._source.remove(field);} else {ctx._source.put(field, value);countedByOtherBlocks = countedByOtherBlocks || !params.existed;}}if (!countedByOtherBlocks) {for (String field : params.fields) {if (ctx._source.get(field) == params.written.get(field)) {if (params.previous.containsKey(field)) {ctx._source.put(field, params.previous.get(field));} else {ctx._source.remove(field);}}}}if (ctx._source.isEmpty()) {ctx.op = 'delete';}}"
+	expected := fmt.Sprintf(`{ "update" : { "_index":"tokens", "_id" : "TKN-abcd" } }
+{"scripted_upsert": true, "script": {"source": "%s","lang": "painless","params": { "fields": ["currentOwner","frozen","paused"], "previous": {"currentOwner":"erd1","paused":false}, "written": {"currentOwner":"erd2","frozen":true}, "increments": {}, "existed": true }},"upsert": {}}
+{ "update" : { "_index":"tags", "_id" : "dGFn" } }
+{"scripted_upsert": true, "script": {"source": "%s","lang": "painless","params": { "fields": ["tag"], "previous": {}, "written": {"tag":"tag"}, "increments": {"count":1}, "existed": false }},"upsert": {}}
+`, restoreScript, restoreScript)
+	require.Equal(t, expected, buffSlice.Buffers()[0].String())
+}
+
+func TestJournalProcessor_PrepareJournalQuery(t *testing.T) {
+	t.Parallel()
+
+	query := NewJournalProcessor().PrepareJournalQuery("hash")
+	require.Equal(t, `{"query": {"term": {"blockHash": "hash"}}}`, query.String())
+}
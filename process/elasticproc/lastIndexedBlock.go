@@ -0,0 +1,126 @@
+package elasticproc
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kalyan3104/k-chain-core-go/data/outport"
+	"github.com/kalyan3104/k-chain-es-indexer-go/core/request"
+	"github.com/kalyan3104/k-chain-es-indexer-go/data"
+	elasticIndexer "github.com/kalyan3104/k-chain-es-indexer-go/process/dataindexer"
+)
+
+const lastIndexedBlockKeyPrefix = "lastIndexedBlock_"
+
+func computeLastIndexedBlockKey(shardID uint32) string {
+	return fmt.Sprintf("%s%d", lastIndexedBlockKeyPrefix, shardID)
+}
+
+// indexLastIndexedBlock saves the block as the last indexed one of its shard. The blocks indexed in import-db mode, as
+// the ones of a backfill, can be older than the last indexed block, so they do not change it
+func (ei *elasticProcessor) indexLastIndexedBlock(obh *outport.OutportBlockWithHeader, buffSlice *data.BufferSlice) error {
+	if !ei.isIndexEnabled(elasticIndexer.ValuesIndex) || ei.isImportDB() {
+		return nil
+	}
+
+	return serializeLastIndexedBlock(&data.LastIndexedBlock{
+		ShardID:   obh.Header.GetShardID(),
+		Nonce:     obh.Header.GetNonce(),
+		Hash:      hex.EncodeToString(obh.BlockData.HeaderHash),
+		Timestamp: time.Duration(obh.Header.GetTimeStamp()),
+	}, buffSlice)
+}
+
+// SaveLastIndexedBlock will overwrite the last indexed block of a shard from the values index
+func (ei *elasticProcessor) SaveLastIndexedBlock(lastIndexedBlock *data.LastIndexedBlock) error {
+	if !ei.isIndexEnabled(elasticIndexer.ValuesIndex) {
+		return nil
+	}
+
+	buffSlice := data.NewBufferSlice(ei.bulkRequestMaxSize)
+	err := serializeLastIndexedBlock(lastIndexedBlock, buffSlice)
+	if err != nil {
+		return err
+	}
+
+	return ei.doBulkRequests("", buffSlice.Buffers(), lastIndexedBlock.ShardID)
+}
+
+// RemoveLastIndexedBlock will remove the last indexed block of a shard from the values index
+func (ei *elasticProcessor) RemoveLastIndexedBlock(shardID uint32) error {
+	if !ei.isIndexEnabled(elasticIndexer.ValuesIndex) {
+		return nil
+	}
+
+	return ei.removeIfHashesNotEmpty(elasticIndexer.ValuesIndex, []string{computeLastIndexedBlockKey(shardID)}, shardID)
+}
+
+// GetLastIndexedBlocks will return the last indexed block of every shard from the values index
+func (ei *elasticProcessor) GetLastIndexedBlocks() (map[uint32]*data.LastIndexedBlock, error) {
+	lastIndexedBlocks := make(map[uint32]*data.LastIndexedBlock)
+	if !ei.isIndexEnabled(elasticIndexer.ValuesIndex) {
+		return lastIndexedBlocks, nil
+	}
+
+	handlerFunc := func(responseBytes []byte) error {
+		response := &data.ResponseScroll{}
+		err := json.Unmarshal(responseBytes, response)
+		if err != nil {
+			return err
+		}
+
+		for _, hit := range response.Hits.Hits {
+			keyValueObj := &data.KeyValueObj{}
+			err = json.Unmarshal(hit.Source, keyValueObj)
+			if err != nil {
+				return err
+			}
+			if !strings.HasPrefix(keyValueObj.Key, lastIndexedBlockKeyPrefix) {
+				continue
+			}
+
+			lastIndexedBlock := &data.LastIndexedBlock{}
+			err = json.Unmarshal([]byte(keyValueObj.Value), lastIndexedBlock)
+			if err != nil {
+				return err
+			}
+
+			lastIndexedBlocks[lastIndexedBlock.ShardID] = lastIndexedBlock
+		}
+
+		return nil
+	}
+
+	query := fmt.Sprintf(`{"query": {"prefix": {"key": %s}}}`, strconv.Quote(lastIndexedBlockKeyPrefix))
+	ctxWithValue := context.WithValue(context.Background(), request.ContextKey, request.ScrollTopic)
+	err := ei.elasticClient.DoScrollRequest(ctxWithValue, elasticIndexer.ValuesIndex, []byte(query), true, handlerFunc)
+	if err != nil {
+		return nil, err
+	}
+
+	return lastIndexedBlocks, nil
+}
+
+func serializeLastIndexedBlock(lastIndexedBlock *data.LastIndexedBlock, buffSlice *data.BufferSlice) error {
+	lastIndexedBlockBytes, err := json.Marshal(lastIndexedBlock)
+	if err != nil {
+		return err
+	}
+
+	key := computeLastIndexedBlockKey(lastIndexedBlock.ShardID)
+	keyValueObjBytes, err := json.Marshal(&data.KeyValueObj{
+		Key:   key,
+		Value: string(lastIndexedBlockBytes),
+	})
+	if err != nil {
+		return err
+	}
+
+	meta := []byte(fmt.Sprintf(`{ "index" : { "_index":"%s", "_id" : "%s" } }%s`, elasticIndexer.ValuesIndex, key, "\n"))
+	return buffSlice.PutData(meta, keyValueObjBytes)
+}
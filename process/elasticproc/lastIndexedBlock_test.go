@@ -0,0 +1,147 @@
+package elasticproc
+
+import (
+	"bytes"
+	"testing"
+
+	dataBlock "github.com/kalyan3104/k-chain-core-go/data/block"
+	"github.com/kalyan3104/k-chain-core-go/data/outport"
+	"github.com/kalyan3104/k-chain-es-indexer-go/data"
+	"github.com/kalyan3104/k-chain-es-indexer-go/mock"
+	"github.com/kalyan3104/k-chain-es-indexer-go/process/dataindexer"
+	"github.com/stretchr/testify/require"
+)
+
+func TestElasticProcessor_SaveHeaderShouldSaveTheLastIndexedBlock(t *testing.T) {
+	t.Parallel()
+
+	bulkBody := ""
+	dbWriter := &mock.DatabaseWriterStub{
+		DoBulkRequestCalled: func(buff *bytes.Buffer, index string) error {
+			bulkBody += buff.String()
+			return nil
+		},
+	}
+
+	elasticProc := newElasticsearchProcessor(dbWriter, createMockElasticProcessorArgs())
+	elasticProc.enabledIndexes = map[string]struct{}{
+		dataindexer.BlockIndex:  {},
+		dataindexer.ValuesIndex: {},
+	}
+
+	outportBlock := createEmptyOutportBlockWithHeader()
+	outportBlock.Header = &dataBlock.Header{ShardID: 1, Nonce: 12, TimeStamp: 5000}
+	outportBlock.BlockData.HeaderHash = []byte("hash")
+
+	err := elasticProc.SaveHeader(outportBlock)
+	require.Nil(t, err)
+	require.Contains(t, bulkBody, `{ "index" : { "_index":"values", "_id" : "lastIndexedBlock_1" } }
+{"key":"lastIndexedBlock_1","value":"{\"shardID\":1,\"nonce\":12,\"hash\":\"68617368\",\"timestamp\":5000}"}
+`)
+}
+
+func TestElasticProcessor_SaveHeaderInImportDBModeShouldNotSaveTheLastIndexedBlock(t *testing.T) {
+	t.Parallel()
+
+	bulkBody := ""
+	dbWriter := &mock.DatabaseWriterStub{
+		DoBulkRequestCalled: func(buff *bytes.Buffer, index string) error {
+			bulkBody += buff.String()
+			return nil
+		},
+	}
+
+	elasticProc := newElasticsearchProcessor(dbWriter, createMockElasticProcessorArgs())
+	elasticProc.enabledIndexes = map[string]struct{}{
+		dataindexer.BlockIndex:  {},
+		dataindexer.ValuesIndex: {},
+	}
+	err := elasticProc.SetOutportConfig(outport.OutportConfig{IsInImportDBMode: true})
+	require.Nil(t, err)
+
+	outportBlock := createEmptyOutportBlockWithHeader()
+	outportBlock.Header = &dataBlock.Header{ShardID: 1, Nonce: 12, TimeStamp: 5000}
+	outportBlock.BlockData.HeaderHash = []byte("hash")
+
+	err = elasticProc.SaveHeader(outportBlock)
+	require.Nil(t, err)
+	require.Contains(t, bulkBody, `"_index":"blocks"`)
+	require.NotContains(t, bulkBody, "lastIndexedBlock_1")
+
+	// an explicit save, as the one of a rollback, still overwrites it
+	bulkBody = ""
+	err = elasticProc.SaveLastIndexedBlock(&data.LastIndexedBlock{ShardID: 1, Nonce: 10, Hash: "h10", Timestamp: 4988})
+	require.Nil(t, err)
+	require.Contains(t, bulkBody, `"_id" : "lastIndexedBlock_1"`)
+}
+
+func TestElasticProcessor_SaveLastIndexedBlock(t *testing.T) {
+	t.Parallel()
+
+	bulkBody := ""
+	dbWriter := &mock.DatabaseWriterStub{
+		DoBulkRequestCalled: func(buff *bytes.Buffer, index string) error {
+			bulkBody += buff.String()
+			return nil
+		},
+	}
+
+	elasticProc := newElasticsearchProcessor(dbWriter, createMockElasticProcessorArgs())
+	err := elasticProc.SaveLastIndexedBlock(&data.LastIndexedBlock{ShardID: 0, Nonce: 98, Hash: "h98", Timestamp: 6000})
+	require.Nil(t, err)
+	require.Empty(t, bulkBody)
+
+	elasticProc.enabledIndexes[dataindexer.ValuesIndex] = struct{}{}
+	err = elasticProc.SaveLastIndexedBlock(&data.LastIndexedBlock{ShardID: 0, Nonce: 98, Hash: "h98", Timestamp: 6000})
+	require.Nil(t, err)
+	require.Equal(t, `{ "index" : { "_index":"values", "_id" : "lastIndexedBlock_0" } }
+{"key":"lastIndexedBlock_0","value":"{\"shardID\":0,\"nonce\":98,\"hash\":\"h98\",\"timestamp\":6000}"}
+`, bulkBody)
+}
+
+func TestElasticProcessor_RemoveLastIndexedBlock(t *testing.T) {
+	t.Parallel()
+
+	removeQuery := ""
+	dbWriter := &mock.DatabaseWriterStub{
+		DoQueryRemoveCalled: func(index string, body *bytes.Buffer) error {
+			require.Equal(t, dataindexer.ValuesIndex, index)
+			removeQuery = body.String()
+			return nil
+		},
+	}
+
+	elasticProc := newElasticsearchProcessor(dbWriter, createMockElasticProcessorArgs())
+	err := elasticProc.RemoveLastIndexedBlock(2)
+	require.Nil(t, err)
+	require.Empty(t, removeQuery)
+
+	elasticProc.enabledIndexes[dataindexer.ValuesIndex] = struct{}{}
+	err = elasticProc.RemoveLastIndexedBlock(2)
+	require.Nil(t, err)
+	require.Contains(t, removeQuery, "lastIndexedBlock_2")
+}
+
+func TestElasticProcessor_GetLastIndexedBlocks(t *testing.T) {
+	t.Parallel()
+
+	dbWriter := &mock.DatabaseWriterStub{
+		DoScrollRequestCalled: func(index string, body []byte, withSource bool, handlerFunc func(responseBytes []byte) error) error {
+			require.Equal(t, dataindexer.ValuesIndex, index)
+			require.Equal(t, `{"query": {"prefix": {"key": "lastIndexedBlock_"}}}`, string(body))
+
+			return handlerFunc([]byte(`{"hits":{"hits":[` +
+				`{"_id":"lastIndexedBlock_0","_source":{"key":"lastIndexedBlock_0","value":"{\"shardID\":0,\"nonce\":98,\"hash\":\"h98\",\"timestamp\":6000}"}},` +
+				`{"_id":"indexer-version","_source":{"key":"indexer-version","value":"v1.0.0"}}]}}`))
+		},
+	}
+
+	elasticProc := newElasticsearchProcessor(dbWriter, createMockElasticProcessorArgs())
+	elasticProc.enabledIndexes[dataindexer.ValuesIndex] = struct{}{}
+
+	lastIndexedBlocks, err := elasticProc.GetLastIndexedBlocks()
+	require.Nil(t, err)
+	require.Equal(t, map[uint32]*data.LastIndexedBlock{
+		0: {ShardID: 0, Nonce: 98, Hash: "h98", Timestamp: 6000},
+	}, lastIndexedBlocks)
+}
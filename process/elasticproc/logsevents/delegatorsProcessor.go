@@ -56,8 +56,9 @@ func (dp *delegatorsProc) processEvent(args *argsProcessEvent) argOutputProcessE
 
 	if eventIdentifierStr == claimRewardsFunc {
 		return argOutputProcessEvent{
-			delegator: dp.getDelegatorFromClaimRewardsEvent(args),
-			processed: true,
+			delegator:          dp.getDelegatorFromClaimRewardsEvent(args),
+			delegationProvider: dp.getDelegationProviderFromClaimRewardsEvent(args),
+			processed:          true,
 		}
 	}
 
@@ -126,9 +127,52 @@ func (dp *delegatorsProc) processEvent(args *argsProcessEvent) argOutputProcessE
 	}
 
 	return argOutputProcessEvent{
-		delegator: delegator,
-		processed: true,
+		delegator:          delegator,
+		delegationProvider: dp.getDelegationProviderUpdate(eventIdentifierStr, contractAddr, topics, args.timestamp),
+		processed:          true,
+	}
+}
+
+func (dp *delegatorsProc) getDelegationProviderUpdate(eventIdentifier string, contractAddr string, topics [][]byte, timestamp uint64) *data.DelegationProviderUpdate {
+	update := data.NewDelegationProviderUpdate(contractAddr, time.Duration(timestamp))
+	update.HasStakeInfo = true
+	update.NumDelegators = big.NewInt(0).SetBytes(topics[2]).Uint64()
+	update.ActiveStake = big.NewInt(0).SetBytes(topics[3])
+	update.ActiveStakeNum = dp.computeBalanceAsFloat(update.ActiveStake)
+
+	switch eventIdentifier {
+	case unDelegateFunc:
+		update.UnDelegated = big.NewInt(0).SetBytes(topics[0])
+		update.UnDelegatedNum = dp.computeBalanceAsFloat(update.UnDelegated)
+	case withdrawFunc:
+		update.Withdrawn = big.NewInt(0).SetBytes(topics[0])
+		update.WithdrawnNum = dp.computeBalanceAsFloat(update.Withdrawn)
+	}
+
+	return update
+}
+
+func (dp *delegatorsProc) getDelegationProviderFromClaimRewardsEvent(args *argsProcessEvent) *data.DelegationProviderUpdate {
+	topics := args.event.GetTopics()
+	if len(topics) < 1 {
+		return nil
 	}
+
+	encodedContractAddr := dp.pubkeyConverter.SilentEncode(args.logAddress, log)
+	update := data.NewDelegationProviderUpdate(encodedContractAddr, time.Duration(args.timestamp))
+	update.ClaimedRewards = big.NewInt(0).SetBytes(topics[0])
+	update.ClaimedRewardsNum = dp.computeBalanceAsFloat(update.ClaimedRewards)
+
+	return update
+}
+
+func (dp *delegatorsProc) computeBalanceAsFloat(value *big.Int) float64 {
+	valueNum, err := dp.balanceConverter.ComputeBalanceAsFloat(value)
+	if err != nil {
+		log.Warn("delegatorsProc.computeBalanceAsFloat cannot compute value as num", "value", value, "error", err)
+	}
+
+	return valueNum
 }
 
 func (dp *delegatorsProc) getDelegatorFromClaimRewardsEvent(args *argsProcessEvent) *data.Delegator {
@@ -170,3 +170,62 @@ func TestDelegatorsProcessor_WithdrawalShouldWorkWithNewTopics(t *testing.T) {
 	require.True(t, res.delegator.ShouldDelete)
 	require.Equal(t, []string{"696431", "696432"}, res.delegator.WithdrawFundIDs)
 }
+
+func TestDelegatorsProcessor_ProcessEventShouldReturnDelegationProviderUpdate(t *testing.T) {
+	t.Parallel()
+
+	event := &transaction.Event{
+		Address:    []byte("addr"),
+		Identifier: []byte(unDelegateFunc),
+		Topics:     [][]byte{big.NewInt(1000).Bytes(), big.NewInt(2000).Bytes(), big.NewInt(10).Bytes(), big.NewInt(1000000000).Bytes(), []byte("id")},
+	}
+	args := &argsProcessEvent{
+		timestamp:   1234,
+		event:       event,
+		logAddress:  []byte("contract"),
+		selfShardID: core.MetachainShardId,
+	}
+
+	balanceConverter, _ := converters.NewBalanceConverter(10)
+	delegatorsProcessor := newDelegatorsProcessor(&mock.PubkeyConverterMock{}, balanceConverter)
+
+	res := delegatorsProcessor.processEvent(args)
+	require.True(t, res.processed)
+	require.Equal(t, &data.DelegationProviderUpdate{
+		Contract:          "636f6e7472616374",
+		HasStakeInfo:      true,
+		ActiveStake:       big.NewInt(1000000000),
+		ActiveStakeNum:    0.1,
+		NumDelegators:     10,
+		UnDelegated:       big.NewInt(1000),
+		UnDelegatedNum:    0.0000001,
+		Withdrawn:         big.NewInt(0),
+		ClaimedRewards:    big.NewInt(0),
+		ClaimedRewardsNum: 0,
+		Timestamp:         1234,
+	}, res.delegationProvider)
+}
+
+func TestDelegatorProcessor_ClaimRewardsShouldReturnDelegationProviderUpdate(t *testing.T) {
+	t.Parallel()
+
+	event := &transaction.Event{
+		Address:    []byte("addr"),
+		Identifier: []byte(claimRewardsFunc),
+		Topics:     [][]byte{big.NewInt(1000).Bytes(), []byte(strconv.FormatBool(false))},
+	}
+	args := &argsProcessEvent{
+		timestamp:   1234,
+		event:       event,
+		logAddress:  []byte("contract"),
+		selfShardID: core.MetachainShardId,
+	}
+
+	balanceConverter, _ := converters.NewBalanceConverter(10)
+	delegatorsProcessor := newDelegatorsProcessor(&mock.PubkeyConverterMock{}, balanceConverter)
+
+	res := delegatorsProcessor.processEvent(args)
+	require.False(t, res.delegationProvider.HasStakeInfo)
+	require.Equal(t, "636f6e7472616374", res.delegationProvider.Contract)
+	require.Equal(t, big.NewInt(1000), res.delegationProvider.ClaimedRewards)
+}
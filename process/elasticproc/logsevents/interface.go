@@ -25,10 +25,11 @@ type argsProcessEvent struct {
 }
 
 type argOutputProcessEvent struct {
-	tokenInfo     *data.TokenInfo
-	delegator     *data.Delegator
-	updatePropNFT *data.NFTDataUpdate
-	processed     bool
+	tokenInfo          *data.TokenInfo
+	delegator          *data.Delegator
+	delegationProvider *data.DelegationProviderUpdate
+	updatePropNFT      *data.NFTDataUpdate
+	processed          bool
 }
 
 type eventsProcessor interface {
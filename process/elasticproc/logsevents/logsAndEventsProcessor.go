@@ -125,6 +125,7 @@ func (lep *logsAndEventsProcessor) ExtractDataFromLogs(
 		TokensInfo:              lep.logsData.tokensInfo,
 		TokensSupply:            lep.logsData.tokensSupply,
 		Delegators:              lep.logsData.delegators,
+		DelegationProviders:     lep.logsData.delegationProviders,
 		NFTsDataUpdates:         lep.logsData.nftsDataUpdates,
 		TokenRolesAndProperties: lep.logsData.tokenRolesAndProperties,
 		TxHashStatusInfo:        lep.logsData.txHashStatusInfoProc.getAllRecords(),
@@ -166,6 +167,9 @@ func (lep *logsAndEventsProcessor) processEvent(logHashHexEncoded string, logAdd
 		if res.delegator != nil {
 			lep.logsData.delegators[res.delegator.Address+res.delegator.Contract] = res.delegator
 		}
+		if res.delegationProvider != nil {
+			lep.logsData.addDelegationProviderUpdate(res.delegationProvider)
+		}
 		if res.updatePropNFT != nil {
 			lep.logsData.nftsDataUpdates = append(lep.logsData.nftsDataUpdates, res.updatePropNFT)
 		}
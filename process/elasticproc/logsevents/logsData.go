@@ -16,6 +16,7 @@ type logsData struct {
 	scDeploys               map[string]*data.ScDeployInfo
 	changeOwnerOperations   map[string]*data.OwnerData
 	delegators              map[string]*data.Delegator
+	delegationProviders     map[string]*data.DelegationProviderUpdate
 	tokensInfo              []*data.TokenInfo
 	nftsDataUpdates         []*data.NFTDataUpdate
 	tokenRolesAndProperties *tokeninfo.TokenRolesAndProperties
@@ -36,6 +37,7 @@ func newLogsData(
 	ld.scDeploys = make(map[string]*data.ScDeployInfo)
 	ld.tokensInfo = make([]*data.TokenInfo, 0)
 	ld.delegators = make(map[string]*data.Delegator)
+	ld.delegationProviders = make(map[string]*data.DelegationProviderUpdate)
 	ld.changeOwnerOperations = make(map[string]*data.OwnerData)
 	ld.nftsDataUpdates = make([]*data.NFTDataUpdate, 0)
 	ld.tokenRolesAndProperties = tokeninfo.NewTokenRolesAndProperties()
@@ -43,3 +45,13 @@ func newLogsData(
 
 	return ld
 }
+
+func (ld *logsData) addDelegationProviderUpdate(update *data.DelegationProviderUpdate) {
+	existing, found := ld.delegationProviders[update.Contract]
+	if !found {
+		ld.delegationProviders[update.Contract] = update
+		return
+	}
+
+	existing.Merge(update)
+}
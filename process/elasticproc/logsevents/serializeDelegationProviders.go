@@ -0,0 +1,130 @@
+package logsevents
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/big"
+
+	"github.com/kalyan3104/k-chain-es-indexer-go/data"
+	"github.com/kalyan3104/k-chain-es-indexer-go/process/elasticproc/converters"
+)
+
+// maxDelegationProviderChanges is the number of changes kept in every delegation provider document in order to be able
+// to revert the blocks that are not final
+const maxDelegationProviderChanges = 50
+
+// SerializeDelegationProviders will serialize the provided staking providers updates in a way that Elasticsearch expects a bulk request
+func (lep *logsAndEventsProcessor) SerializeDelegationProviders(updates map[string]*data.DelegationProviderUpdate, buffSlice *data.BufferSlice, index string) error {
+	for _, update := range updates {
+		meta, serializedData, err := prepareSerializedDelegationProvider(update, index)
+		if err != nil {
+			return err
+		}
+
+		err = buffSlice.PutData(meta, serializedData)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// PrepareDelegationProvidersQueryInCaseOfRevert will prepare the staking providers query in case of revert. The values
+// stored before the reverted block are restored from the last change of every document
+func (lep *logsAndEventsProcessor) PrepareDelegationProvidersQueryInCaseOfRevert(timestamp uint64) *bytes.Buffer {
+	codeToExecute := `
+	if ( !ctx._source.containsKey('changes') ) { return }
+	if ( ctx._source.changes.length == 0 ) { return }
+	def change = ctx._source.changes[ctx._source.changes.length - 1];
+	if ( !change.timestamp.equals(params.timestamp) ) { return }
+	if ( change.created ) { ctx.op = 'delete'; return }
+	for (String key : change.previous.keySet()) { ctx._source[key] = change.previous[key]; }
+	ctx._source.changes.remove(ctx._source.changes.length - 1);
+`
+
+	query := fmt.Sprintf(`
+	{
+	  "query": {
+		"match": {
+		  "timestamp": "%d"
+		}
+	  },
+	  "script": {
+		"source": "%s",
+		"lang": "painless",
+		"params": {"timestamp": %d}
+	  }
+	}`, timestamp, converters.FormatPainlessSource(codeToExecute), timestamp)
+
+	return bytes.NewBuffer([]byte(query))
+}
+
+func prepareSerializedDelegationProvider(update *data.DelegationProviderUpdate, index string) ([]byte, []byte, error) {
+	unDelegatedPending := big.NewInt(0).Sub(update.UnDelegated, update.Withdrawn)
+	provider := &data.DelegationProvider{
+		Contract:              update.Contract,
+		ActiveStake:           update.ActiveStake.String(),
+		ActiveStakeNum:        update.ActiveStakeNum,
+		NumDelegators:         update.NumDelegators,
+		UnDelegatedPending:    unDelegatedPending.String(),
+		UnDelegatedPendingNum: update.UnDelegatedNum - update.WithdrawnNum,
+		ClaimedRewards:        update.ClaimedRewards.String(),
+		ClaimedRewardsNum:     update.ClaimedRewardsNum,
+		Timestamp:             update.Timestamp,
+	}
+
+	providerSerialized, err := json.Marshal(provider)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// a processed block is ignored if it is the last one applied on the document, so indexing the same block twice
+	// does not change the aggregated values
+	codeToExecute := `
+		if ('create' == ctx.op) {
+			ctx._source = params.provider;
+			ctx._source.changes = [['timestamp': params.provider.timestamp, 'created': true]];
+		} else {
+			if (ctx._source.timestamp.equals(params.provider.timestamp)) {
+				ctx.op = 'noop';
+				return;
+			}
+
+			HashMap previous = new HashMap();
+			for (String key : ['activeStake', 'activeStakeNum', 'numDelegators', 'unDelegatedPending', 'unDelegatedPendingNum', 'claimedRewards', 'claimedRewardsNum', 'timestamp']) {
+				previous[key] = ctx._source[key];
+			}
+			if (!ctx._source.containsKey('changes')) {
+				ctx._source.changes = [];
+			}
+			ctx._source.changes.add(['timestamp': params.provider.timestamp, 'created': false, 'previous': previous]);
+			if (ctx._source.changes.length > params.maxChanges) {
+				ctx._source.changes.remove(0);
+			}
+
+			if (params.hasStakeInfo) {
+				ctx._source.activeStake = params.provider.activeStake;
+				ctx._source.activeStakeNum = params.provider.activeStakeNum;
+				ctx._source.numDelegators = params.provider.numDelegators;
+			}
+			ctx._source.unDelegatedPending = new BigInteger(ctx._source.unDelegatedPending).add(new BigInteger(params.provider.unDelegatedPending)).toString();
+			ctx._source.unDelegatedPendingNum += params.provider.unDelegatedPendingNum;
+			ctx._source.claimedRewards = new BigInteger(ctx._source.claimedRewards).add(new BigInteger(params.provider.claimedRewards)).toString();
+			ctx._source.claimedRewardsNum += params.provider.claimedRewardsNum;
+			ctx._source.timestamp = params.provider.timestamp;
+		}
+`
+	serializedDataStr := fmt.Sprintf(`{"scripted_upsert": true, "script": {`+
+		`"source": "%s",`+
+		`"lang": "painless",`+
+		`"params": { "provider": %s, "hasStakeInfo": %t, "maxChanges": %d }},`+
+		`"upsert": {}}`,
+		converters.FormatPainlessSource(codeToExecute), string(providerSerialized), update.HasStakeInfo, maxDelegationProviderChanges,
+	)
+
+	meta := []byte(fmt.Sprintf(`{ "update" : { "_index":"%s", "_id" : "%s" } }%s`, index, converters.JsonEscape(update.Contract), "\n"))
+
+	return meta, []byte(serializedDataStr), nil
+}
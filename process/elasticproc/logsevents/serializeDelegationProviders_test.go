@@ -0,0 +1,39 @@
+package logsevents
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/kalyan3104/k-chain-es-indexer-go/data"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogsAndEventsProcessor_SerializeDelegationProviders(t *testing.T) {
+	t.Parallel()
+
+	update := data.NewDelegationProviderUpdate("contract1", 5000)
+	update.HasStakeInfo = true
+	update.ActiveStake = big.NewInt(1000)
+	update.NumDelegators = 3
+	update.UnDelegated = big.NewInt(100)
+	update.Withdrawn = big.NewInt(40)
+	update.ClaimedRewards = big.NewInt(7)
+
+	buffSlice := data.NewBufferSlice(data.DefaultMaxBulkSize)
+	err := (&logsAndEventsProcessor{}).SerializeDelegationProviders(map[string]*data.DelegationProviderUpdate{"contract1": update}, buffSlice, "delegationproviders")
+	require.Nil(t, err)
+
+	expectedRes := `{ "update" : { "_index":"delegationproviders", "_id" : "contract1" } }
+{"scripted_upsert": true, "script": {"source": "if ('create' == ctx.op) {ctx._source = params.provider;ctx._source.changes = [['timestamp': params.provider.timestamp, 'created': true]];} else {if (ctx._source.timestamp.equals(params.provider.timestamp)) {ctx.op = 'noop';return;}HashMap previous = new HashMap();for (String key : ['activeStake', 'activeStakeNum', 'numDelegators', 'unDelegatedPending', 'unDelegatedPendingNum', 'claimedRewards', 'claimedRewardsNum', 'timestamp']) {previous[key] = ctx._source[key];}if (!ctx._source.containsKey('changes')) {ctx._source.changes = [];}ctx._source.changes.add(['timestamp': params.provider.timestamp, 'created': false, 'previous': previous]);if (ctx._source.changes.length > params.maxChanges) {ctx._source.changes.remove(0);}if (params.hasStakeInfo) {ctx._source.activeStake = params.provider.activeStake;ctx._source.activeStakeNum = params.provider.activeStakeNum;ctx._source.numDelegators = params.provider.numDelegators;}ctx._source.unDelegatedPending = new BigInteger(ctx._source.unDelegatedPending).add(new BigInteger(params.provider.unDelegatedPending)).toString();ctx._source.unDelegatedPendingNum += params.provider.unDelegatedPendingNum;ctx._source.claimedRewards = new BigInteger(ctx._source.claimedRewards).add(new BigInteger(params.provider.claimedRewards)).toString();ctx._source.claimedRewardsNum += params.provider.claimedRewardsNum;ctx._source.timestamp = params.provider.timestamp;}","lang": "painless","params": { "provider": {"contract":"contract1","activeStake":"1000","activeStakeNum":0,"numDelegators":3,"unDelegatedPending":"60","unDelegatedPendingNum":0,"claimedRewards":"7","claimedRewardsNum":0,"timestamp":5000}, "hasStakeInfo": true, "maxChanges": 50 }},"upsert": {}}
+`
+	require.Equal(t, expectedRes, buffSlice.Buffers()[0].String())
+}
+
+func TestLogsAndEventsProcessor_PrepareDelegationProvidersQueryInCaseOfRevert(t *testing.T) {
+	t.Parallel()
+
+	query := (&logsAndEventsProcessor{}).PrepareDelegationProvidersQueryInCaseOfRevert(5000)
+	require.Contains(t, query.String(), `"timestamp": "5000"`)
+	require.Contains(t, query.String(), `"params": {"timestamp": 5000}`)
+	require.Contains(t, query.String(), "for (String key : change.previous.keySet()) { ctx._source[key] = change.previous[key]; }")
+}
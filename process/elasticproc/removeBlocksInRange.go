@@ -0,0 +1,211 @@
+package elasticproc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/kalyan3104/k-chain-core-go/data/block"
+	"github.com/kalyan3104/k-chain-es-indexer-go/core/request"
+	"github.com/kalyan3104/k-chain-es-indexer-go/data"
+	elasticIndexer "github.com/kalyan3104/k-chain-es-indexer-go/process/dataindexer"
+)
+
+// revertedBlockData holds the details of a block, needed in order to remove the data that was indexed for it
+type revertedBlockData struct {
+	hash              string
+	shardID           uint32
+	timestamp         uint64
+	hasMiniblocks     bool
+	encodedTxsHashes  []string
+	encodedScrsHashes []string
+}
+
+// RemoveBlocksInRange will remove all the data indexed for the blocks of the provided shard that have the nonce in the
+// [fromNonce, toNonce] interval. The details of the blocks are read from the blocks index, so neither the headers nor the
+// bodies are needed. The blocks are removed in descending order of their nonces and the number of removed blocks is returned
+func (ei *elasticProcessor) RemoveBlocksInRange(shardID uint32, fromNonce uint64, toNonce uint64) (uint64, error) {
+	if fromNonce > toNonce {
+		return 0, fmt.Errorf("%w: from nonce %d is greater than to nonce %d", elasticIndexer.ErrInvalidNoncesRange, fromNonce, toNonce)
+	}
+
+	indexedBlocks, err := ei.getIndexedBlocksInRange(shardID, fromNonce, toNonce)
+	if err != nil {
+		return 0, err
+	}
+
+	sort.Slice(indexedBlocks, func(i, j int) bool {
+		return indexedBlocks[i].Nonce > indexedBlocks[j].Nonce
+	})
+
+	for idx, indexedBlock := range indexedBlocks {
+		err = ei.removeIndexedBlock(indexedBlock)
+		if err != nil {
+			return uint64(idx), fmt.Errorf("%w when removing block with nonce %d from shard %d", err, indexedBlock.Nonce, shardID)
+		}
+
+		log.Info("elasticProcessor.RemoveBlocksInRange removed block", "shardID", shardID, "nonce", indexedBlock.Nonce, "hash", indexedBlock.Hash)
+	}
+
+	return uint64(len(indexedBlocks)), nil
+}
+
+func (ei *elasticProcessor) getIndexedBlocksInRange(shardID uint32, fromNonce uint64, toNonce uint64) ([]*data.Block, error) {
+	indexedBlocks := make([]*data.Block, 0)
+	handlerFunc := func(responseBytes []byte) error {
+		responseScroll := &data.ResponseScroll{}
+		err := json.Unmarshal(responseBytes, responseScroll)
+		if err != nil {
+			return err
+		}
+
+		for _, hit := range responseScroll.Hits.Hits {
+			indexedBlock := &data.Block{}
+			err = json.Unmarshal(hit.Source, indexedBlock)
+			if err != nil {
+				return err
+			}
+
+			indexedBlock.Hash = hit.ID
+			indexedBlocks = append(indexedBlocks, indexedBlock)
+		}
+
+		return nil
+	}
+
+	ctxWithValue := context.WithValue(context.Background(), request.ContextKey, request.ExtendTopicWithShardID(request.ScrollTopic, shardID))
+	query := fmt.Sprintf(`{"query": {"bool": {"must": [{"match": {"shardId": {"query": %d,"operator": "AND"}}},{"range": {"nonce": {"gte": %d,"lte": %d}}}]}}}`, shardID, fromNonce, toNonce)
+	err := ei.elasticClient.DoScrollRequest(ctxWithValue, elasticIndexer.BlockIndex, []byte(query), true, handlerFunc)
+	if err != nil {
+		return nil, err
+	}
+
+	return indexedBlocks, nil
+}
+
+// removeIndexedBlock removes the block document last, so a removal that failed midway can be resumed by running it again
+func (ei *elasticProcessor) removeIndexedBlock(indexedBlock *data.Block) error {
+	encodedTxsHashes, encodedScrsHashes := getHashesForRemoveFromIndexedBlock(indexedBlock)
+	revertedBlock := &revertedBlockData{
+		hash:              indexedBlock.Hash,
+		shardID:           indexedBlock.ShardID,
+		timestamp:         uint64(indexedBlock.Timestamp),
+		hasMiniblocks:     len(indexedBlock.MiniBlocksHashes) > 0,
+		encodedTxsHashes:  encodedTxsHashes,
+		encodedScrsHashes: encodedScrsHashes,
+	}
+
+	err := ei.removeTransactionsData(revertedBlock)
+	if err != nil {
+		return err
+	}
+
+	// the revert payloads of the observer do not remove the receipts, a rollback removes everything the block indexed
+	err = ei.removeReceiptsInCaseOfRollback(append(encodedTxsHashes, encodedScrsHashes...), revertedBlock.timestamp, revertedBlock.shardID)
+	if err != nil {
+		return err
+	}
+
+	err = ei.removeIfHashesNotEmpty(elasticIndexer.MiniblocksIndex, getMiniblocksHashesForRemoveFromIndexedBlock(indexedBlock), indexedBlock.ShardID)
+	if err != nil {
+		return err
+	}
+
+	err = ei.RemoveAccountsDCDT(revertedBlock.timestamp, revertedBlock.shardID)
+	if err != nil {
+		return err
+	}
+
+	return ei.removeIfHashesNotEmpty(elasticIndexer.BlockIndex, []string{indexedBlock.Hash}, indexedBlock.ShardID)
+}
+
+// getHashesForRemoveFromIndexedBlock mirrors the selection done by GetHexEncodedHashesForRemove, based on the miniblocks
+// details stored in the block document
+func getHashesForRemoveFromIndexedBlock(indexedBlock *data.Block) ([]string, []string) {
+	encodedTxsHashes := make([]string, 0)
+	encodedScrsHashes := make([]string, 0)
+	for _, mbDetails := range indexedBlock.MiniBlocksDetails {
+		isCrossShard := mbDetails.SenderShardID != mbDetails.ReceiverShardID
+		isAtSource := mbDetails.SenderShardID == indexedBlock.ShardID
+		if isCrossShard && isAtSource && mbDetails.Type == block.TxBlock.String() {
+			continue
+		}
+
+		if mbDetails.Type == block.SmartContractResultBlock.String() {
+			encodedScrsHashes = append(encodedScrsHashes, mbDetails.TxsHashes...)
+			continue
+		}
+		encodedTxsHashes = append(encodedTxsHashes, mbDetails.TxsHashes...)
+	}
+
+	return encodedTxsHashes, encodedScrsHashes
+}
+
+// getMiniblocksHashesForRemoveFromIndexedBlock mirrors the selection done by GetMiniblocksHashesHexEncoded: the cross
+// shard miniblocks are kept at destination because they were indexed by the source shard
+func getMiniblocksHashesForRemoveFromIndexedBlock(indexedBlock *data.Block) []string {
+	encodedMiniblocksHashes := make([]string, 0)
+	for _, mbDetails := range indexedBlock.MiniBlocksDetails {
+		if mbDetails.MBIndex < 0 || mbDetails.MBIndex >= len(indexedBlock.MiniBlocksHashes) {
+			continue
+		}
+
+		isCrossShard := mbDetails.SenderShardID != mbDetails.ReceiverShardID
+		isCrossShardOnDestination := isCrossShard && mbDetails.ReceiverShardID == indexedBlock.ShardID
+		if isCrossShardOnDestination {
+			continue
+		}
+
+		encodedMiniblocksHashes = append(encodedMiniblocksHashes, indexedBlock.MiniBlocksHashes[mbDetails.MBIndex])
+	}
+
+	return encodedMiniblocksHashes
+}
+
+// GetLastIndexedBlock returns the block of the provided shard with the highest nonce that is not greater than maxNonce, or
+// nil if there is no such block
+func (ei *elasticProcessor) GetLastIndexedBlock(shardID uint32, maxNonce uint64) (*data.Block, error) {
+	response := &data.ResponseScroll{}
+	ctxWithValue := context.WithValue(context.Background(), request.ContextKey, request.ExtendTopicWithShardID(request.GetTopic, shardID))
+	query := fmt.Sprintf(`{"size": 1, "sort": [{"nonce": {"order": "desc"}}], "query": {"bool": {"must": [{"match": {"shardId": {"query": %d,"operator": "AND"}}},{"range": {"nonce": {"lte": %d}}}]}}}`, shardID, maxNonce)
+	err := ei.elasticClient.DoSearchRequest(ctxWithValue, elasticIndexer.BlockIndex, []byte(query), response)
+	if err != nil {
+		return nil, err
+	}
+	if len(response.Hits.Hits) == 0 {
+		return nil, nil
+	}
+
+	lastBlock := &data.Block{}
+	err = json.Unmarshal(response.Hits.Hits[0].Source, lastBlock)
+	if err != nil {
+		return nil, err
+	}
+	lastBlock.Hash = response.Hits.Hits[0].ID
+
+	return lastBlock, nil
+}
+
+// removeReceiptsInCaseOfRollback removes the receipts generated by a rolled back block. The receipts do not hold the shard, so
+// they are matched by the hash of the transaction that generated them and by the timestamp of the block
+func (ei *elasticProcessor) removeReceiptsInCaseOfRollback(encodedHashes []string, timestamp uint64, shardID uint32) error {
+	if len(encodedHashes) == 0 {
+		return nil
+	}
+
+	serializedHashes, err := json.Marshal(encodedHashes)
+	if err != nil {
+		return err
+	}
+
+	ctxWithValue := context.WithValue(context.Background(), request.ContextKey, request.ExtendTopicWithShardID(request.RemoveTopic, shardID))
+	query := fmt.Sprintf(`{"query": {"bool": {"must": [{"terms": {"txHash": %s}},{"match": {"timestamp": {"query": "%d","operator": "AND"}}}]}}}`, serializedHashes, timestamp)
+
+	return ei.elasticClient.DoQueryRemove(
+		ctxWithValue,
+		elasticIndexer.ReceiptsIndex,
+		bytes.NewBuffer([]byte(query)),
+	)
+}
@@ -0,0 +1,166 @@
+package elasticproc
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	dataBlock "github.com/kalyan3104/k-chain-core-go/data/block"
+	"github.com/kalyan3104/k-chain-es-indexer-go/data"
+	"github.com/kalyan3104/k-chain-es-indexer-go/mock"
+	"github.com/kalyan3104/k-chain-es-indexer-go/process/dataindexer"
+	"github.com/stretchr/testify/require"
+)
+
+func TestElasticProcessor_RemoveBlocksInRangeInvalidRange(t *testing.T) {
+	t.Parallel()
+
+	elasticProc := newElasticsearchProcessor(&mock.DatabaseWriterStub{}, createMockElasticProcessorArgs())
+
+	numRemoved, err := elasticProc.RemoveBlocksInRange(0, 10, 5)
+	require.True(t, errors.Is(err, dataindexer.ErrInvalidNoncesRange))
+	require.Zero(t, numRemoved)
+}
+
+func TestElasticProcessor_RemoveBlocksInRange(t *testing.T) {
+	t.Parallel()
+
+	indexedBlocks := map[string]*data.Block{
+		"h5": {
+			Nonce:            5,
+			ShardID:          0,
+			Timestamp:        5000,
+			MiniBlocksHashes: []string{"mb1", "mb2", "mb3"},
+			MiniBlocksDetails: []*data.MiniBlocksDetails{
+				{MBIndex: 0, SenderShardID: 0, ReceiverShardID: 1, Type: dataBlock.TxBlock.String(), TxsHashes: []string{"tx1"}},
+				{MBIndex: 1, SenderShardID: 0, ReceiverShardID: 0, Type: dataBlock.SmartContractResultBlock.String(), TxsHashes: []string{"scr1"}},
+				{MBIndex: 2, SenderShardID: 1, ReceiverShardID: 0, Type: dataBlock.TxBlock.String(), TxsHashes: []string{"tx2"}},
+			},
+		},
+		"h6": {
+			Nonce:     6,
+			ShardID:   0,
+			Timestamp: 5006,
+		},
+	}
+
+	removedIDs := make(map[string][]string)
+	receiptsQueries := make([]string, 0)
+	dbWriter := &mock.DatabaseWriterStub{
+		DoScrollRequestCalled: func(index string, body []byte, withSource bool, handlerFunc func(responseBytes []byte) error) error {
+			if index == dataindexer.AccountsHistoryIndex {
+				return nil
+			}
+			require.Equal(t, dataindexer.BlockIndex, index)
+			require.True(t, withSource)
+
+			responseScroll := &data.ResponseScroll{}
+			for _, hash := range []string{"h5", "h6"} {
+				source, _ := json.Marshal(indexedBlocks[hash])
+				responseScroll.Hits.Hits = append(responseScroll.Hits.Hits, struct {
+					ID     string          `json:"_id"`
+					Source json.RawMessage `json:"_source"`
+				}{ID: hash, Source: source})
+			}
+			responseBytes, _ := json.Marshal(responseScroll)
+
+			return handlerFunc(responseBytes)
+		},
+		DoQueryRemoveCalled: func(index string, body *bytes.Buffer) error {
+			if index == dataindexer.ReceiptsIndex {
+				receiptsQueries = append(receiptsQueries, body.String())
+				return nil
+			}
+
+			query := &struct {
+				Query struct {
+					IDs struct {
+						Values []string `json:"values"`
+					} `json:"ids"`
+				} `json:"query"`
+			}{}
+			_ = json.Unmarshal(body.Bytes(), query)
+			removedIDs[index] = append(removedIDs[index], query.Query.IDs.Values...)
+
+			return nil
+		},
+	}
+
+	elasticProc := newElasticsearchProcessor(dbWriter, createMockElasticProcessorArgs())
+	numRemoved, err := elasticProc.RemoveBlocksInRange(0, 5, 6)
+	require.Nil(t, err)
+	require.Equal(t, uint64(2), numRemoved)
+
+	// the blocks are removed starting with the highest nonce
+	require.Equal(t, []string{"h6", "h5"}, removedIDs[dataindexer.BlockIndex])
+	require.Equal(t, []string{"mb1", "mb2"}, removedIDs[dataindexer.MiniblocksIndex])
+	require.Equal(t, []string{"tx2"}, removedIDs[dataindexer.TransactionsIndex])
+	require.Equal(t, []string{"scr1"}, removedIDs[dataindexer.ScResultsIndex])
+	require.Equal(t, []string{
+		`{"query": {"bool": {"must": [{"terms": {"txHash": ["tx2","scr1"]}},{"match": {"timestamp": {"query": "5000","operator": "AND"}}}]}}}`,
+	}, receiptsQueries)
+}
+
+func TestElasticProcessor_RemoveBlocksInRangeShouldSubtractTheStatsContributions(t *testing.T) {
+	t.Parallel()
+
+	bulkBody := ""
+	dbWriter := &mock.DatabaseWriterStub{
+		DoScrollRequestCalled: func(index string, body []byte, withSource bool, handlerFunc func(responseBytes []byte) error) error {
+			return handlerFunc([]byte(`{"hits":{"hits":[{"_id":"h7","_source":{"nonce":7,"shardId":1,"timestamp":5000}}]}}`))
+		},
+		DoMultiGetCalled: func(ids []string, index string, withSource bool, response interface{}) error {
+			require.Equal(t, dataindexer.StatsContributionsIndex, index)
+			return json.Unmarshal([]byte(`{"docs":[{"found":true,"_id":"epochstats_h7","_source":`+
+				`{"index":"epochstats","key":"h7","shardID":1,"timestamp":5000,"stats":`+
+				`{"epoch":2,"shardID":1,"numBlocks":1,"txCount":4,"txsByOperation":{},"txsByStatus":{},"fees":"10","developerFees":"1"}}}]}`), response)
+		},
+		DoBulkRequestCalled: func(buff *bytes.Buffer, index string) error {
+			bulkBody += buff.String()
+			return nil
+		},
+	}
+
+	elasticProc := newElasticsearchProcessor(dbWriter, createMockElasticProcessorArgs())
+	elasticProc.enabledIndexes[dataindexer.EpochStatsIndex] = struct{}{}
+	elasticProc.enabledIndexes[dataindexer.StatsContributionsIndex] = struct{}{}
+
+	numRemoved, err := elasticProc.RemoveBlocksInRange(1, 7, 7)
+	require.Nil(t, err)
+	require.Equal(t, uint64(1), numRemoved)
+	require.Contains(t, bulkBody, "ctx._source.txCount -= params.stats.txCount")
+	require.Contains(t, bulkBody, `{ "delete" : { "_index": "statscontributions", "_id" : "epochstats_h7" } }`)
+}
+
+func TestElasticProcessor_GetLastIndexedBlock(t *testing.T) {
+	t.Parallel()
+
+	lastBlock := &data.Block{Nonce: 7, ShardID: 1, Timestamp: 5042}
+	dbWriter := &mock.DatabaseWriterStub{
+		DoSearchRequestCalled: func(index string, body []byte, resBody interface{}) error {
+			require.Equal(t, dataindexer.BlockIndex, index)
+			require.Equal(t,
+				`{"size": 1, "sort": [{"nonce": {"order": "desc"}}], "query": {"bool": {"must": [{"match": {"shardId": {"query": 1,"operator": "AND"}}},{"range": {"nonce": {"lte": 9}}}]}}}`,
+				string(body),
+			)
+
+			source, _ := json.Marshal(lastBlock)
+			response := fmt.Sprintf(`{"hits": {"hits": [{"_id": "h7", "_source": %s}]}}`, source)
+			return json.Unmarshal([]byte(response), resBody)
+		},
+	}
+
+	elasticProc := newElasticsearchProcessor(dbWriter, createMockElasticProcessorArgs())
+	indexedBlock, err := elasticProc.GetLastIndexedBlock(1, 9)
+	require.Nil(t, err)
+	require.Equal(t, "h7", indexedBlock.Hash)
+	require.Equal(t, uint64(7), indexedBlock.Nonce)
+	require.Equal(t, uint64(5042), uint64(indexedBlock.Timestamp))
+
+	elasticProc = newElasticsearchProcessor(&mock.DatabaseWriterStub{}, createMockElasticProcessorArgs())
+	indexedBlock, err = elasticProc.GetLastIndexedBlock(1, 9)
+	require.Nil(t, err)
+	require.Nil(t, indexedBlock)
+}
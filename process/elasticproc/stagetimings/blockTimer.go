@@ -0,0 +1,97 @@
+package stagetimings
+
+import (
+	"time"
+)
+
+const (
+	// PrepareTransactionsStage is the conversion of the transactions pool in the database structures
+	PrepareTransactionsStage = "prepare_transactions"
+	// ExtractDataFromLogsStage is the processing of the logs and events
+	ExtractDataFromLogsStage = "extract_data_from_logs"
+	// NFTCreateInfoStage is the tokens lookup and the serialization of the created NFTs
+	NFTCreateInfoStage = "nft_create_info"
+	// AlteredAccountsStage is the preparation and the serialization of the altered accounts
+	AlteredAccountsStage = "altered_accounts"
+	// AccountsDCDTTokensLookupStage is the tokens lookup used to set the type and the owner in the accounts DCDT
+	AccountsDCDTTokensLookupStage = "accounts_dcdt_tokens_lookup"
+	// TokenTypeScrollsStage is the per token scrolls which set the type of the already indexed documents
+	TokenTypeScrollsStage = "token_type_scrolls"
+	// NFTBurnInfoStage is the tokens lookup and the serialization of the tokens supply
+	NFTBurnInfoStage = "nft_burn_info"
+	// JournalStage is the lookup and the write of the previous versions of the documents updated by the block
+	JournalStage = "journal"
+	// BulkRequestsStage is the write of the prepared documents
+	BulkRequestsStage = "bulk_requests"
+	// TotalStage is the whole processing of the block
+	TotalStage = "total"
+)
+
+const noParent = -1
+
+// Stage holds the timing of a step from the processing of a block
+type Stage struct {
+	Name     string
+	Parent   int
+	Start    time.Time
+	Duration time.Duration
+}
+
+// BlockTimer measures the duration of the stages from the processing of a block. A stage started while another one
+// is running is recorded as its child. The timer is not concurrent safe, it is meant to be used by a single block
+type BlockTimer struct {
+	ShardID    uint32
+	Nonce      uint64
+	Hash       string
+	Start      time.Time
+	Duration   time.Duration
+	Stages     []*Stage
+	openStages []int
+}
+
+// NewBlockTimer creates a timer for the block with the provided identifiers and starts measuring the total duration
+func NewBlockTimer(shardID uint32, nonce uint64, hash string) *BlockTimer {
+	return &BlockTimer{
+		ShardID: shardID,
+		Nonce:   nonce,
+		Hash:    hash,
+		Start:   time.Now(),
+		Stages:  make([]*Stage, 0),
+	}
+}
+
+// StartStage starts measuring the stage with the provided name and returns the function that stops it
+func (bt *BlockTimer) StartStage(name string) func() {
+	parent := noParent
+	if len(bt.openStages) > 0 {
+		parent = bt.openStages[len(bt.openStages)-1]
+	}
+
+	stage := &Stage{
+		Name:   name,
+		Parent: parent,
+		Start:  time.Now(),
+	}
+	bt.Stages = append(bt.Stages, stage)
+	bt.openStages = append(bt.openStages, len(bt.Stages)-1)
+
+	return func() {
+		stage.Duration = time.Since(stage.Start)
+		bt.openStages = bt.openStages[:len(bt.openStages)-1]
+	}
+}
+
+// Finish stops measuring the total duration of the block
+func (bt *BlockTimer) Finish() {
+	bt.Duration = time.Since(bt.Start)
+}
+
+// StagesDurations returns the duration of every stage, the durations of the stages with the same name being summed
+func (bt *BlockTimer) StagesDurations() map[string]time.Duration {
+	durations := make(map[string]time.Duration, len(bt.Stages))
+	for _, stage := range bt.Stages {
+		durations[stage.Name] += stage.Duration
+	}
+
+	return durations
+}
@@ -0,0 +1,48 @@
+package stagetimings
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBlockTimer_NestedStages(t *testing.T) {
+	t.Parallel()
+
+	timer := NewBlockTimer(1, 10, "aabb")
+
+	stopAccounts := timer.StartStage(AlteredAccountsStage)
+	stopLookup := timer.StartStage(AccountsDCDTTokensLookupStage)
+	time.Sleep(time.Millisecond)
+	stopLookup()
+	stopAccounts()
+
+	stopBulk := timer.StartStage(BulkRequestsStage)
+	stopBulk()
+	timer.Finish()
+
+	require.Len(t, timer.Stages, 3)
+	require.Equal(t, noParent, timer.Stages[0].Parent)
+	require.Equal(t, 0, timer.Stages[1].Parent)
+	require.Equal(t, noParent, timer.Stages[2].Parent)
+	require.GreaterOrEqual(t, timer.Stages[0].Duration, timer.Stages[1].Duration)
+	require.GreaterOrEqual(t, timer.Duration, timer.Stages[0].Duration)
+}
+
+func TestBlockTimer_StagesDurationsShouldSumStagesWithTheSameName(t *testing.T) {
+	t.Parallel()
+
+	timer := &BlockTimer{
+		Stages: []*Stage{
+			{Name: TokenTypeScrollsStage, Duration: time.Second},
+			{Name: BulkRequestsStage, Duration: 2 * time.Second},
+			{Name: TokenTypeScrollsStage, Duration: 3 * time.Second},
+		},
+	}
+
+	require.Equal(t, map[string]time.Duration{
+		TokenTypeScrollsStage: 4 * time.Second,
+		BulkRequestsStage:     2 * time.Second,
+	}, timer.StagesDurations())
+}
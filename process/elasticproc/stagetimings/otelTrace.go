@@ -0,0 +1,165 @@
+package stagetimings
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"strconv"
+)
+
+const (
+	serviceName   = "elastic-indexer"
+	scopeName     = "indexer/process"
+	rootSpanName  = "save_block"
+	traceIDLength = 16
+	spanIDLength  = 8
+
+	// spanKindInternal is the value of SPAN_KIND_INTERNAL from the OpenTelemetry protocol
+	spanKindInternal = 1
+)
+
+// the structures below follow the JSON encoding of the OpenTelemetry protocol (an ExportTraceServiceRequest), which is
+// the format of the file exporter and of the file receiver from the OpenTelemetry collector
+
+type otelTrace struct {
+	ResourceSpans []*otelResourceSpans `json:"resourceSpans"`
+}
+
+type otelResourceSpans struct {
+	Resource   otelResource      `json:"resource"`
+	ScopeSpans []*otelScopeSpans `json:"scopeSpans"`
+}
+
+type otelResource struct {
+	Attributes []*otelAttribute `json:"attributes"`
+}
+
+type otelScopeSpans struct {
+	Scope otelScope   `json:"scope"`
+	Spans []*otelSpan `json:"spans"`
+}
+
+type otelScope struct {
+	Name string `json:"name"`
+}
+
+type otelSpan struct {
+	TraceID           string           `json:"traceId"`
+	SpanID            string           `json:"spanId"`
+	ParentSpanID      string           `json:"parentSpanId,omitempty"`
+	Name              string           `json:"name"`
+	Kind              int              `json:"kind"`
+	StartTimeUnixNano string           `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string           `json:"endTimeUnixNano"`
+	Attributes        []*otelAttribute `json:"attributes,omitempty"`
+}
+
+type otelAttribute struct {
+	Key   string       `json:"key"`
+	Value otelAnyValue `json:"value"`
+}
+
+type otelAnyValue struct {
+	StringValue *string `json:"stringValue,omitempty"`
+	IntValue    *string `json:"intValue,omitempty"`
+}
+
+func stringAttribute(key string, value string) *otelAttribute {
+	return &otelAttribute{Key: key, Value: otelAnyValue{StringValue: &value}}
+}
+
+// the protocol encodes the 64 bits integers as strings
+func intAttribute(key string, value uint64) *otelAttribute {
+	intValue := strconv.FormatUint(value, 10)
+	return &otelAttribute{Key: key, Value: otelAnyValue{IntValue: &intValue}}
+}
+
+func prepareOTelTrace(timer *BlockTimer) ([]byte, error) {
+	traceID, err := traceIDFromBlockHash(timer.Hash)
+	if err != nil {
+		return nil, err
+	}
+
+	rootSpanID, err := randomHex(spanIDLength)
+	if err != nil {
+		return nil, err
+	}
+
+	spans := make([]*otelSpan, 0, len(timer.Stages)+1)
+	spans = append(spans, &otelSpan{
+		TraceID:           traceID,
+		SpanID:            rootSpanID,
+		Name:              rootSpanName,
+		Kind:              spanKindInternal,
+		StartTimeUnixNano: strconv.FormatInt(timer.Start.UnixNano(), 10),
+		EndTimeUnixNano:   strconv.FormatInt(timer.Start.Add(timer.Duration).UnixNano(), 10),
+		Attributes: []*otelAttribute{
+			intAttribute("block.shard_id", uint64(timer.ShardID)),
+			intAttribute("block.nonce", timer.Nonce),
+			stringAttribute("block.hash", timer.Hash),
+		},
+	})
+
+	spansIDs := make([]string, len(timer.Stages))
+	for idx, stage := range timer.Stages {
+		spansIDs[idx], err = randomHex(spanIDLength)
+		if err != nil {
+			return nil, err
+		}
+
+		// the parent is always started before the child, so its id is already generated
+		parentSpanID := rootSpanID
+		if stage.Parent != noParent {
+			parentSpanID = spansIDs[stage.Parent]
+		}
+
+		spans = append(spans, &otelSpan{
+			TraceID:           traceID,
+			SpanID:            spansIDs[idx],
+			ParentSpanID:      parentSpanID,
+			Name:              stage.Name,
+			Kind:              spanKindInternal,
+			StartTimeUnixNano: strconv.FormatInt(stage.Start.UnixNano(), 10),
+			EndTimeUnixNano:   strconv.FormatInt(stage.Start.Add(stage.Duration).UnixNano(), 10),
+		})
+	}
+
+	trace := &otelTrace{
+		ResourceSpans: []*otelResourceSpans{
+			{
+				Resource: otelResource{
+					Attributes: []*otelAttribute{stringAttribute("service.name", serviceName)},
+				},
+				ScopeSpans: []*otelScopeSpans{
+					{
+						Scope: otelScope{Name: scopeName},
+						Spans: spans,
+					},
+				},
+			},
+		},
+	}
+
+	return json.Marshal(trace)
+}
+
+// traceIDFromBlockHash uses the beginning of the block hash as trace id, so the trace of a block can be found by its
+// hash. A random id is generated if the hash is too short
+func traceIDFromBlockHash(blockHash string) (string, error) {
+	hashBytes, err := hex.DecodeString(blockHash)
+	if err == nil && len(hashBytes) >= traceIDLength {
+		return hex.EncodeToString(hashBytes[:traceIDLength]), nil
+	}
+
+	return randomHex(traceIDLength)
+}
+
+func randomHex(numBytes int) (string, error) {
+	buff := make([]byte, numBytes)
+	_, err := rand.Read(buff)
+	if err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(buff), nil
+}
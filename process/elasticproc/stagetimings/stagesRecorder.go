@@ -0,0 +1,118 @@
+package stagetimings
+
+import (
+	"os"
+	"sync"
+	"time"
+
+	"github.com/kalyan3104/k-chain-core-go/core/check"
+	"github.com/kalyan3104/k-chain-es-indexer-go/core"
+	logger "github.com/kalyan3104/k-chain-logger-go"
+)
+
+const traceFilePermissions = 0644
+
+var log = logger.GetOrCreate("indexer/process/stagetimings")
+
+// StageMetricsHandler defines what a component that exports the durations of the block processing stages should do
+type StageMetricsHandler interface {
+	AddBlockStageDuration(stage string, shardID uint32, duration time.Duration)
+	IsInterfaceNil() bool
+}
+
+// ArgsStagesRecorder holds the arguments needed to create a stages recorder
+type ArgsStagesRecorder struct {
+	MetricsHandler     StageMetricsHandler
+	SlowBlockThreshold time.Duration
+	TraceFilePath      string
+}
+
+type stagesRecorder struct {
+	metricsHandler     StageMetricsHandler
+	slowBlockThreshold time.Duration
+	traceFilePath      string
+	mutTraceFile       sync.Mutex
+}
+
+// NewStagesRecorder creates a component that exports the stage timings of every block as metrics. A block slower than
+// the threshold is logged with its stages breakdown, a zero threshold disabling the log. If a trace file path is
+// provided, every block is also appended to it as a trace in the OpenTelemetry JSON format
+func NewStagesRecorder(args ArgsStagesRecorder) (*stagesRecorder, error) {
+	if check.IfNil(args.MetricsHandler) {
+		return nil, core.ErrNilMetricsHandler
+	}
+
+	if args.TraceFilePath != "" {
+		// make sure the file can be written before the first block arrives
+		file, err := os.OpenFile(args.TraceFilePath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, traceFilePermissions)
+		if err != nil {
+			return nil, err
+		}
+		_ = file.Close()
+	}
+
+	return &stagesRecorder{
+		metricsHandler:     args.MetricsHandler,
+		slowBlockThreshold: args.SlowBlockThreshold,
+		traceFilePath:      args.TraceFilePath,
+	}, nil
+}
+
+// RecordBlockStages will export the stage timings of the provided block
+func (sr *stagesRecorder) RecordBlockStages(timer *BlockTimer) {
+	if timer == nil {
+		return
+	}
+
+	for stage, duration := range timer.StagesDurations() {
+		sr.metricsHandler.AddBlockStageDuration(stage, timer.ShardID, duration)
+	}
+	sr.metricsHandler.AddBlockStageDuration(TotalStage, timer.ShardID, timer.Duration)
+
+	if sr.slowBlockThreshold > 0 && timer.Duration >= sr.slowBlockThreshold {
+		logSlowBlock(timer)
+	}
+
+	if sr.traceFilePath != "" {
+		sr.appendTrace(timer)
+	}
+}
+
+func logSlowBlock(timer *BlockTimer) {
+	logArgs := []interface{}{"shardID", timer.ShardID, "nonce", timer.Nonce, "hash", timer.Hash, TotalStage, timer.Duration}
+	for _, stage := range timer.Stages {
+		logArgs = append(logArgs, stage.Name, stage.Duration)
+	}
+
+	log.Warn("stagesRecorder: slow block", logArgs...)
+}
+
+func (sr *stagesRecorder) appendTrace(timer *BlockTimer) {
+	traceBytes, err := prepareOTelTrace(timer)
+	if err != nil {
+		log.Warn("stagesRecorder.appendTrace: cannot prepare trace", "nonce", timer.Nonce, "error", err)
+		return
+	}
+
+	sr.mutTraceFile.Lock()
+	defer sr.mutTraceFile.Unlock()
+
+	file, err := os.OpenFile(sr.traceFilePath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, traceFilePermissions)
+	if err != nil {
+		log.Warn("stagesRecorder.appendTrace: cannot open trace file", "path", sr.traceFilePath, "error", err)
+		return
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	_, err = file.Write(append(traceBytes, '\n'))
+	if err != nil {
+		log.Warn("stagesRecorder.appendTrace: cannot write trace", "path", sr.traceFilePath, "error", err)
+	}
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (sr *stagesRecorder) IsInterfaceNil() bool {
+	return sr == nil
+}
@@ -0,0 +1,116 @@
+package stagetimings
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kalyan3104/k-chain-es-indexer-go/core"
+	"github.com/kalyan3104/k-chain-es-indexer-go/metrics"
+	"github.com/stretchr/testify/require"
+)
+
+func createBlockTimer() *BlockTimer {
+	start := time.Unix(100, 0)
+	return &BlockTimer{
+		ShardID:  2,
+		Nonce:    50,
+		Hash:     "0102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f20",
+		Start:    start,
+		Duration: 3 * time.Second,
+		Stages: []*Stage{
+			{Name: AlteredAccountsStage, Parent: noParent, Start: start, Duration: 2 * time.Second},
+			{Name: AccountsDCDTTokensLookupStage, Parent: 0, Start: start, Duration: time.Second},
+			{Name: BulkRequestsStage, Parent: noParent, Start: start.Add(2 * time.Second), Duration: time.Second},
+		},
+	}
+}
+
+func TestNewStagesRecorder(t *testing.T) {
+	t.Parallel()
+
+	recorder, err := NewStagesRecorder(ArgsStagesRecorder{})
+	require.Nil(t, recorder)
+	require.Equal(t, core.ErrNilMetricsHandler, err)
+
+	recorder, err = NewStagesRecorder(ArgsStagesRecorder{
+		MetricsHandler: metrics.NewStatusMetrics(),
+		TraceFilePath:  filepath.Join(t.TempDir(), "missing", "trace.json"),
+	})
+	require.Nil(t, recorder)
+	require.NotNil(t, err)
+
+	recorder, err = NewStagesRecorder(ArgsStagesRecorder{MetricsHandler: metrics.NewStatusMetrics()})
+	require.Nil(t, err)
+	require.False(t, recorder.IsInterfaceNil())
+}
+
+func TestStagesRecorder_RecordBlockStagesShouldExportMetrics(t *testing.T) {
+	t.Parallel()
+
+	statusMetrics := metrics.NewStatusMetrics()
+	recorder, _ := NewStagesRecorder(ArgsStagesRecorder{
+		MetricsHandler:     statusMetrics,
+		SlowBlockThreshold: time.Second,
+	})
+
+	recorder.RecordBlockStages(nil)
+	recorder.RecordBlockStages(createBlockTimer())
+
+	metricsRecorder := httptest.NewRecorder()
+	statusMetrics.GetPrometheusHandler().ServeHTTP(metricsRecorder, httptest.NewRequest(http.MethodGet, "/status/prometheus-metrics", nil))
+	prometheusMetrics := metricsRecorder.Body.String()
+	require.Contains(t, prometheusMetrics, `block_stage_duration_seconds_sum{shardID="2",stage="altered_accounts"} 2`)
+	require.Contains(t, prometheusMetrics, `block_stage_duration_seconds_sum{shardID="2",stage="accounts_dcdt_tokens_lookup"} 1`)
+	require.Contains(t, prometheusMetrics, `block_stage_duration_seconds_sum{shardID="2",stage="bulk_requests"} 1`)
+	require.Contains(t, prometheusMetrics, `block_stage_duration_seconds_sum{shardID="2",stage="total"} 3`)
+}
+
+func TestStagesRecorder_RecordBlockStagesShouldAppendTraces(t *testing.T) {
+	t.Parallel()
+
+	traceFilePath := filepath.Join(t.TempDir(), "trace.json")
+	recorder, _ := NewStagesRecorder(ArgsStagesRecorder{
+		MetricsHandler: metrics.NewStatusMetrics(),
+		TraceFilePath:  traceFilePath,
+	})
+
+	recorder.RecordBlockStages(createBlockTimer())
+	recorder.RecordBlockStages(createBlockTimer())
+
+	fileContent, err := os.ReadFile(traceFilePath)
+	require.Nil(t, err)
+
+	lines := strings.Split(strings.TrimSpace(string(fileContent)), "\n")
+	require.Len(t, lines, 2)
+
+	trace := &otelTrace{}
+	err = json.Unmarshal([]byte(lines[0]), trace)
+	require.Nil(t, err)
+
+	spans := trace.ResourceSpans[0].ScopeSpans[0].Spans
+	require.Len(t, spans, 4)
+
+	rootSpan := spans[0]
+	require.Equal(t, rootSpanName, rootSpan.Name)
+	require.Equal(t, "0102030405060708090a0b0c0d0e0f10", rootSpan.TraceID)
+	require.Empty(t, rootSpan.ParentSpanID)
+	require.Equal(t, "100000000000", rootSpan.StartTimeUnixNano)
+	require.Equal(t, "103000000000", rootSpan.EndTimeUnixNano)
+
+	require.Equal(t, AlteredAccountsStage, spans[1].Name)
+	require.Equal(t, rootSpan.SpanID, spans[1].ParentSpanID)
+	require.Equal(t, AccountsDCDTTokensLookupStage, spans[2].Name)
+	require.Equal(t, spans[1].SpanID, spans[2].ParentSpanID)
+	require.Equal(t, BulkRequestsStage, spans[3].Name)
+	require.Equal(t, rootSpan.SpanID, spans[3].ParentSpanID)
+	for _, span := range spans {
+		require.Equal(t, rootSpan.TraceID, span.TraceID)
+		require.Len(t, span.SpanID, 2*spanIDLength)
+	}
+}
@@ -0,0 +1,214 @@
+package elasticproc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	coreData "github.com/kalyan3104/k-chain-core-go/data"
+	"github.com/kalyan3104/k-chain-es-indexer-go/core/request"
+	"github.com/kalyan3104/k-chain-es-indexer-go/data"
+	elasticIndexer "github.com/kalyan3104/k-chain-es-indexer-go/process/dataindexer"
+	"github.com/kalyan3104/k-chain-es-indexer-go/process/elasticproc/statscontributions"
+)
+
+// the statistics indices whose documents add up the values of many blocks. What a block added to them is kept in the
+// statistics contributions index with the block hash as key, so it can be subtracted when the block is reverted. The
+// consensus statistics of the rounds are kept with the shard and the round as key, they are not reverted with a block
+var blockStatsContributionsIndexes = []string{elasticIndexer.EpochStatsIndex, elasticIndexer.ContractStatsIndex, elasticIndexer.ConsensusStatsIndex}
+
+// getStatsContribution returns the stored contribution to the provided index identified by the provided key, or nil if
+// there is no such contribution
+func (ei *elasticProcessor) getStatsContribution(index string, key string, shardID uint32) (*data.StatsContribution, error) {
+	id := statscontributions.ComputeStatsContributionID(index, key)
+	contributions, err := ei.getStatsContributions([]string{id}, shardID)
+	if err != nil {
+		return nil, err
+	}
+
+	return contributions[id], nil
+}
+
+func (ei *elasticProcessor) getStatsContributions(ids []string, shardID uint32) (map[string]*data.StatsContribution, error) {
+	if !ei.isIndexEnabled(elasticIndexer.StatsContributionsIndex) || len(ids) == 0 {
+		return make(map[string]*data.StatsContribution), nil
+	}
+
+	response := &data.ResponseRawDocuments{}
+	ctxWithValue := context.WithValue(context.Background(), request.ContextKey, request.ExtendTopicWithShardID(request.GetTopic, shardID))
+	err := ei.elasticClient.DoMultiGet(ctxWithValue, ids, elasticIndexer.StatsContributionsIndex, true, response)
+	if err != nil {
+		return nil, err
+	}
+
+	return ei.statsContributionsProc.ExtractStatsContributions(response)
+}
+
+// serializeStatsWithContribution serializes the statistics added by a block together with its contribution. When the
+// block was already indexed, the previous contribution is subtracted first, so indexing it again does not count it twice.
+// When the previous contribution is expired, the values of the block are still counted, so only the contribution is saved
+func (ei *elasticProcessor) serializeStatsWithContribution(
+	contribution *data.StatsContribution,
+	previousContribution *data.StatsContribution,
+	serializeStats func() error,
+	buffSlice *data.BufferSlice,
+) error {
+	if !ei.isIndexEnabled(elasticIndexer.StatsContributionsIndex) {
+		return serializeStats()
+	}
+
+	isExpired := previousContribution != nil && previousContribution.Expired
+	if previousContribution != nil && !isExpired {
+		err := ei.serializeStatsContributionRevert(previousContribution, buffSlice)
+		if err != nil {
+			return err
+		}
+	}
+
+	if !isExpired {
+		err := serializeStats()
+		if err != nil {
+			return err
+		}
+	}
+
+	return ei.statsContributionsProc.SerializeStatsContributions([]*data.StatsContribution{contribution}, buffSlice, elasticIndexer.StatsContributionsIndex)
+}
+
+// serializeStatsContributionRevert serializes the requests that subtract the provided contribution from the documents of
+// its statistics index
+func (ei *elasticProcessor) serializeStatsContributionRevert(contribution *data.StatsContribution, buffSlice *data.BufferSlice) error {
+	switch contribution.Index {
+	case elasticIndexer.EpochStatsIndex:
+		stats := &data.EpochStats{}
+		err := json.Unmarshal(contribution.Stats, stats)
+		if err != nil {
+			return err
+		}
+
+		return ei.epochStatsProc.SerializeEpochStatsRevert(stats, buffSlice, elasticIndexer.EpochStatsIndex)
+	case elasticIndexer.ContractStatsIndex:
+		statsMap := make(map[string]*data.ContractStats)
+		err := json.Unmarshal(contribution.Stats, &statsMap)
+		if err != nil {
+			return err
+		}
+
+		return ei.contractStatsProc.SerializeContractStatsRevert(statsMap, buffSlice, elasticIndexer.ContractStatsIndex)
+	case elasticIndexer.ConsensusStatsIndex:
+		statsMap := make(map[string]*data.ConsensusStats)
+		err := json.Unmarshal(contribution.Stats, &statsMap)
+		if err != nil {
+			return err
+		}
+
+		return ei.consensusStatsProc.SerializeConsensusStatsRevert(statsMap, buffSlice, elasticIndexer.ConsensusStatsIndex)
+	default:
+		return fmt.Errorf("%w: %s", elasticIndexer.ErrUnknownStatsContributionIndex, contribution.Index)
+	}
+}
+
+// revertStatsContributionsInCaseOfRevert subtracts what the reverted block added to the statistics indices and removes
+// its contributions
+func (ei *elasticProcessor) revertStatsContributionsInCaseOfRevert(revertedBlock *revertedBlockData) error {
+	ids := make([]string, 0, len(blockStatsContributionsIndexes))
+	for _, index := range blockStatsContributionsIndexes {
+		ids = append(ids, statscontributions.ComputeStatsContributionID(index, revertedBlock.hash))
+	}
+
+	contributionsMap, err := ei.getStatsContributions(ids, revertedBlock.shardID)
+	if err != nil {
+		return err
+	}
+	if len(contributionsMap) == 0 {
+		return ei.saveExpiredStatsContributions(revertedBlock)
+	}
+
+	contributions := make([]*data.StatsContribution, 0, len(contributionsMap))
+	buffSlice := data.NewBufferSlice(ei.bulkRequestMaxSize)
+	for _, id := range ids {
+		contribution, found := contributionsMap[id]
+		if !found || contribution.Expired {
+			continue
+		}
+
+		err = ei.serializeStatsContributionRevert(contribution, buffSlice)
+		if err != nil {
+			return err
+		}
+		contributions = append(contributions, contribution)
+	}
+
+	err = ei.statsContributionsProc.SerializeStatsContributionsRemoval(contributions, buffSlice, elasticIndexer.StatsContributionsIndex)
+	if err != nil {
+		return err
+	}
+
+	return ei.doBulkRequests("", buffSlice.Buffers(), revertedBlock.shardID)
+}
+
+// saveExpiredStatsContributions marks the contributions of a removed block older than the retention as expired. Its
+// values cannot be subtracted anymore, so they stay counted, and the marks keep them from being added again when the
+// same block is indexed again, for example by the backfill command. A block without contributions that is newer than the
+// retention was indexed without them, so it is not marked. The marks have the time they are saved as timestamp, so they
+// are kept for the retention as well
+func (ei *elasticProcessor) saveExpiredStatsContributions(revertedBlock *revertedBlockData) error {
+	retentionInSeconds := uint64(ei.contributionsRetention.Seconds())
+	now := uint64(time.Now().Unix())
+	shouldMark := ei.isIndexEnabled(elasticIndexer.StatsContributionsIndex) &&
+		retentionInSeconds > 0 &&
+		revertedBlock.timestamp+retentionInSeconds < now
+	if !shouldMark {
+		return nil
+	}
+
+	contributions := make([]*data.StatsContribution, 0, len(blockStatsContributionsIndexes))
+	for _, index := range blockStatsContributionsIndexes {
+		if !ei.isIndexEnabled(index) {
+			continue
+		}
+
+		contributions = append(contributions, &data.StatsContribution{
+			Index:     index,
+			Key:       revertedBlock.hash,
+			ShardID:   revertedBlock.shardID,
+			Timestamp: time.Duration(now),
+			Expired:   true,
+		})
+	}
+	if len(contributions) == 0 {
+		return nil
+	}
+
+	log.Warn("elasticProcessor.saveExpiredStatsContributions: the statistics of the removed block are still counted, since its contributions expired",
+		"shard", revertedBlock.shardID, "hash", revertedBlock.hash, "timestamp", revertedBlock.timestamp)
+
+	buffSlice := data.NewBufferSlice(ei.bulkRequestMaxSize)
+	err := ei.statsContributionsProc.SerializeStatsContributions(contributions, buffSlice, elasticIndexer.StatsContributionsIndex)
+	if err != nil {
+		return err
+	}
+
+	return ei.doBulkRequests("", buffSlice.Buffers(), revertedBlock.shardID)
+}
+
+// removeExpiredStatsContributions removes, when a shard starts a new epoch, the contributions of the shard older than the
+// retention. The contributions are kept after the blocks become final, since a final block can still be indexed again,
+// for example by the backfill command, and it is counted twice if its contribution was removed
+func (ei *elasticProcessor) removeExpiredStatsContributions(header coreData.HeaderHandler) error {
+	retentionInSeconds := uint64(ei.contributionsRetention.Seconds())
+	shouldRemove := ei.isIndexEnabled(elasticIndexer.StatsContributionsIndex) &&
+		retentionInSeconds > 0 &&
+		header.IsStartOfEpochBlock() &&
+		header.GetTimeStamp() > retentionInSeconds
+	if !shouldRemove {
+		return nil
+	}
+
+	shardID := header.GetShardID()
+	ctxWithValue := context.WithValue(context.Background(), request.ContextKey, request.ExtendTopicWithShardID(request.RemoveTopic, shardID))
+	query := ei.statsContributionsProc.PrepareExpiredStatsContributionsQuery(shardID, header.GetTimeStamp()-retentionInSeconds)
+
+	return ei.elasticClient.DoQueryRemove(ctxWithValue, elasticIndexer.StatsContributionsIndex, query)
+}
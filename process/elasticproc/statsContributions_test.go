@@ -0,0 +1,478 @@
+package elasticproc
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kalyan3104/k-chain-core-go/data/alteredAccount"
+	dataBlock "github.com/kalyan3104/k-chain-core-go/data/block"
+	"github.com/kalyan3104/k-chain-core-go/data/outport"
+	"github.com/kalyan3104/k-chain-es-indexer-go/data"
+	"github.com/kalyan3104/k-chain-es-indexer-go/mock"
+	"github.com/kalyan3104/k-chain-es-indexer-go/process/dataindexer"
+	"github.com/stretchr/testify/require"
+)
+
+func TestElasticProcessor_PrepareAndIndexEpochStatsShouldCountNewAccounts(t *testing.T) {
+	t.Parallel()
+
+	dbWriter := &mock.DatabaseWriterStub{
+		DoMultiGetCalled: func(ids []string, index string, withSource bool, response interface{}) error {
+			require.Equal(t, dataindexer.StatsContributionsIndex, index)
+			return json.Unmarshal([]byte(`{"docs":[{"found":false,"_id":"epochstats_68617368"}]}`), response)
+		},
+	}
+
+	elasticProc := newElasticsearchProcessor(dbWriter, createMockElasticProcessorArgs())
+	elasticProc.enabledIndexes[dataindexer.EpochStatsIndex] = struct{}{}
+	elasticProc.enabledIndexes[dataindexer.StatsContributionsIndex] = struct{}{}
+	elasticProc.enabledIndexes[dataindexer.AccountsIndex] = struct{}{}
+
+	outportBlock := createEmptyOutportBlockWithHeader()
+	outportBlock.ShardID = 1
+	outportBlock.Header = &dataBlock.Header{ShardID: 1, Epoch: 2, TimeStamp: 5000}
+	outportBlock.BlockData.HeaderHash = []byte("hash")
+	outportBlock.AlteredAccounts = map[string]*alteredAccount.AlteredAccount{
+		"addr1": {Address: "addr1"},
+		"addr2": {Address: "addr2"},
+		"addr3": {Address: "addr3"},
+	}
+	indexedAccounts := map[string]*data.AccountInfo{"addr1": {Address: "addr1"}}
+
+	buffSlice := data.NewBufferSlice(data.DefaultMaxBulkSize)
+	err := elasticProc.prepareAndIndexEpochStats(outportBlock, &data.PreparedResults{}, nil, indexedAccounts, buffSlice)
+	require.Nil(t, err)
+
+	body := buffSlice.Buffers()[0].String()
+	require.False(t, strings.Contains(body, "ctx._source.numBlocks -= params.stats.numBlocks"))
+	require.True(t, strings.Contains(body, `"newAccounts":2`))
+	require.True(t, strings.Contains(body, fmt.Sprintf(`{ "index" : { "_index":"statscontributions", "_id" : "epochstats_%s" } }`, hex.EncodeToString([]byte("hash")))))
+}
+
+func TestElasticProcessor_PrepareAndIndexEpochStatsAgainShouldSubtractThePreviousContribution(t *testing.T) {
+	t.Parallel()
+
+	contributionID := "epochstats_" + hex.EncodeToString([]byte("hash"))
+	dbWriter := &mock.DatabaseWriterStub{
+		DoMultiGetCalled: func(ids []string, index string, withSource bool, response interface{}) error {
+			require.Equal(t, dataindexer.StatsContributionsIndex, index)
+			require.Equal(t, []string{contributionID}, ids)
+			return json.Unmarshal([]byte(fmt.Sprintf(`{"docs":[{"found":true,"_id":"%s","_source":`+
+				`{"index":"epochstats","key":"68617368","shardID":1,"timestamp":5000,"stats":`+
+				`{"epoch":2,"shardID":1,"numBlocks":1,"txsByOperation":{},"txsByStatus":{},"fees":"0","developerFees":"0","newAccounts":3}}}]}`, contributionID)), response)
+		},
+	}
+
+	elasticProc := newElasticsearchProcessor(dbWriter, createMockElasticProcessorArgs())
+	elasticProc.enabledIndexes[dataindexer.EpochStatsIndex] = struct{}{}
+	elasticProc.enabledIndexes[dataindexer.StatsContributionsIndex] = struct{}{}
+
+	outportBlock := createEmptyOutportBlockWithHeader()
+	outportBlock.ShardID = 1
+	outportBlock.Header = &dataBlock.Header{ShardID: 1, Epoch: 2, TimeStamp: 5000}
+	outportBlock.BlockData.HeaderHash = []byte("hash")
+	outportBlock.AlteredAccounts = map[string]*alteredAccount.AlteredAccount{
+		"addr1": {Address: "addr1"},
+	}
+
+	buffSlice := data.NewBufferSlice(data.DefaultMaxBulkSize)
+	err := elasticProc.prepareAndIndexEpochStats(outportBlock, &data.PreparedResults{}, nil, nil, buffSlice)
+	require.Nil(t, err)
+
+	// the accounts created by the block were indexed the first time, so their number is taken from the previous contribution
+	body := buffSlice.Buffers()[0].String()
+	subtractIdx := strings.Index(body, "ctx._source.numBlocks -= params.stats.numBlocks")
+	addIdx := strings.Index(body, "ctx._source.numBlocks += params.stats.numBlocks")
+	contributionIdx := strings.Index(body, `"_index":"statscontributions"`)
+	require.True(t, subtractIdx >= 0)
+	require.True(t, subtractIdx < addIdx)
+	require.True(t, addIdx < contributionIdx)
+	require.Equal(t, 3, strings.Count(body, `"newAccounts":3`))
+}
+
+func TestElasticProcessor_SaveHeaderShouldAddTheEmptyBlocksToTheEpochStats(t *testing.T) {
+	t.Parallel()
+
+	bulkBody := ""
+	dbWriter := &mock.DatabaseWriterStub{
+		DoBulkRequestCalled: func(buff *bytes.Buffer, index string) error {
+			bulkBody += buff.String()
+			return nil
+		},
+	}
+
+	elasticProc := newElasticsearchProcessor(dbWriter, createMockElasticProcessorArgs())
+	elasticProc.enabledIndexes = map[string]struct{}{
+		dataindexer.EpochStatsIndex: {},
+	}
+
+	outportBlock := createEmptyOutportBlockWithHeader()
+	outportBlock.ShardID = 1
+	outportBlock.Header = &dataBlock.Header{ShardID: 1, Epoch: 2, TimeStamp: 5000}
+	outportBlock.BlockData.HeaderHash = []byte("hash")
+
+	err := elasticProc.SaveHeader(outportBlock)
+	require.Nil(t, err)
+	require.Contains(t, bulkBody, `{ "update" : { "_index":"epochstats", "_id" : "1_2" } }`)
+	require.Contains(t, bulkBody, `"epoch":2,"shardID":1,"numBlocks":1,"txCount":0`)
+	require.Contains(t, bulkBody, `"timestamp":5000`)
+
+	// the statistics of a block with miniblocks are added together with its transactions
+	bulkBody = ""
+	outportBlock.BlockData.Body = &dataBlock.Body{MiniBlocks: []*dataBlock.MiniBlock{{}}}
+	err = elasticProc.SaveHeader(outportBlock)
+	require.Nil(t, err)
+	require.NotContains(t, bulkBody, `"_index":"epochstats"`)
+}
+
+func TestElasticProcessor_SaveHeaderOfStartOfEpochBlockShouldCountTheActiveAccountsOfThePreviousEpoch(t *testing.T) {
+	t.Parallel()
+
+	refreshedIndex := ""
+	searchQuery := ""
+	bulkBody := ""
+	dbWriter := &mock.DatabaseWriterStub{
+		DoMultiGetCalled: func(ids []string, index string, withSource bool, response interface{}) error {
+			if index != dataindexer.EpochStatsIndex {
+				return json.Unmarshal([]byte(`{"docs":[]}`), response)
+			}
+
+			require.Equal(t, []string{"1_2"}, ids)
+			return json.Unmarshal([]byte(`{"docs":[{"found":true,"_id":"1_2","_source":{"epoch":2,"shardID":1,"startTimestamp":1000,"timestamp":4994}}]}`), response)
+		},
+		DoRefreshRequestCalled: func(index string) error {
+			refreshedIndex = index
+			return nil
+		},
+		DoSearchRequestCalled: func(index string, body []byte, resBody interface{}) error {
+			require.Equal(t, dataindexer.TransactionsIndex, index)
+			searchQuery = string(body)
+			return json.Unmarshal([]byte(`{"aggregations":{"senders":{"doc_count":10,"count":{"value":3}},"receivers":{"doc_count":12,"count":{"value":5}}}}`), resBody)
+		},
+		DoBulkRequestCalled: func(buff *bytes.Buffer, index string) error {
+			bulkBody += buff.String()
+			return nil
+		},
+	}
+
+	elasticProc := newElasticsearchProcessor(dbWriter, createMockElasticProcessorArgs())
+	elasticProc.enabledIndexes = map[string]struct{}{
+		dataindexer.EpochStatsIndex:   {},
+		dataindexer.TransactionsIndex: {},
+	}
+
+	outportBlock := createEmptyOutportBlockWithHeader()
+	outportBlock.ShardID = 1
+	outportBlock.Header = &dataBlock.Header{ShardID: 1, Epoch: 3, TimeStamp: 5000, EpochStartMetaHash: []byte("metaHash")}
+	outportBlock.BlockData.HeaderHash = []byte("hash")
+
+	err := elasticProc.SaveHeader(outportBlock)
+	require.Nil(t, err)
+	require.Equal(t, dataindexer.TransactionsIndex, refreshedIndex)
+	require.Contains(t, searchQuery, `{"range": {"timestamp": {"gte": 1000, "lt": 5000}}}`)
+	require.Contains(t, bulkBody, `{ "update" : { "_index":"epochstats", "_id" : "1_2" } }`)
+	require.Contains(t, bulkBody, `"params": { "activeSenders": 3, "activeReceivers": 5 }`)
+
+	// the active accounts are counted only once, when the shard starts the next epoch
+	searchQuery = ""
+	outportBlock.Header = &dataBlock.Header{ShardID: 1, Epoch: 3, TimeStamp: 5006}
+	err = elasticProc.SaveHeader(outportBlock)
+	require.Nil(t, err)
+	require.Empty(t, searchQuery)
+}
+
+func TestElasticProcessor_RemoveTransactionsShouldRevertStatsContributions(t *testing.T) {
+	t.Parallel()
+
+	arguments := createMockElasticProcessorArgs()
+	header := &dataBlock.Header{ShardID: 1, Epoch: 2, TimeStamp: 5000}
+	headerHash, _ := arguments.BlockProc.ComputeHeaderHash(header)
+	contributionID := "epochstats_" + hex.EncodeToString(headerHash)
+
+	revertBody := ""
+	dbWriter := &mock.DatabaseWriterStub{
+		DoMultiGetCalled: func(ids []string, index string, withSource bool, response interface{}) error {
+			require.Equal(t, dataindexer.StatsContributionsIndex, index)
+			require.Contains(t, ids, contributionID)
+			return json.Unmarshal([]byte(fmt.Sprintf(`{"docs":[{"found":true,"_id":"%s","_source":`+
+				`{"index":"epochstats","key":"%s","shardID":1,"timestamp":5000,"stats":`+
+				`{"epoch":2,"shardID":1,"numBlocks":1,"txCount":4,"txsByOperation":{},"txsByStatus":{},"fees":"10","developerFees":"1"}}}]}`,
+				contributionID, hex.EncodeToString(headerHash))), response)
+		},
+		DoBulkRequestCalled: func(buff *bytes.Buffer, index string) error {
+			revertBody += buff.String()
+			return nil
+		},
+	}
+
+	elasticProc := newElasticsearchProcessor(dbWriter, arguments)
+	elasticProc.enabledIndexes[dataindexer.EpochStatsIndex] = struct{}{}
+	elasticProc.enabledIndexes[dataindexer.StatsContributionsIndex] = struct{}{}
+
+	err := elasticProc.RemoveTransactions(header, &dataBlock.Body{})
+	require.Nil(t, err)
+	require.True(t, strings.Contains(revertBody, `{ "update" : { "_index":"epochstats", "_id" : "1_2" } }`))
+	require.True(t, strings.Contains(revertBody, "ctx._source.txCount -= params.stats.txCount"))
+	require.True(t, strings.Contains(revertBody, `"txCount":4`))
+	require.True(t, strings.Contains(revertBody, fmt.Sprintf(`{ "delete" : { "_index": "statscontributions", "_id" : "%s" } }`, contributionID)))
+}
+
+func TestElasticProcessor_RemoveTransactionsOfBlockWithExpiredContributionsShouldMarkThem(t *testing.T) {
+	t.Parallel()
+
+	arguments := createMockElasticProcessorArgs()
+	arguments.ContributionsRetention = 10 * 24 * time.Hour
+	header := &dataBlock.Header{ShardID: 1, Epoch: 2, TimeStamp: 5000}
+	headerHash, _ := arguments.BlockProc.ComputeHeaderHash(header)
+
+	revertBody := ""
+	dbWriter := &mock.DatabaseWriterStub{
+		DoMultiGetCalled: func(ids []string, index string, withSource bool, response interface{}) error {
+			require.Equal(t, dataindexer.StatsContributionsIndex, index)
+			return json.Unmarshal([]byte(`{"docs":[]}`), response)
+		},
+		DoBulkRequestCalled: func(buff *bytes.Buffer, index string) error {
+			revertBody += buff.String()
+			return nil
+		},
+	}
+
+	elasticProc := newElasticsearchProcessor(dbWriter, arguments)
+	elasticProc.enabledIndexes[dataindexer.EpochStatsIndex] = struct{}{}
+	elasticProc.enabledIndexes[dataindexer.StatsContributionsIndex] = struct{}{}
+
+	err := elasticProc.RemoveTransactions(header, &dataBlock.Body{})
+	require.Nil(t, err)
+	require.False(t, strings.Contains(revertBody, "ctx._source.txCount -= params.stats.txCount"))
+	require.True(t, strings.Contains(revertBody, fmt.Sprintf(`{ "index" : { "_index":"statscontributions", "_id" : "epochstats_%s" } }`, hex.EncodeToString(headerHash))))
+	require.True(t, strings.Contains(revertBody, `"expired":true`))
+	require.False(t, strings.Contains(revertBody, `"_id" : "contractstats_`))
+
+	// a block newer than the retention was indexed without contributions, so it is not marked
+	revertBody = ""
+	header.TimeStamp = uint64(time.Now().Unix())
+	err = elasticProc.RemoveTransactions(header, &dataBlock.Body{})
+	require.Nil(t, err)
+	require.Empty(t, revertBody)
+}
+
+func TestElasticProcessor_PrepareAndIndexEpochStatsOverExpiredContributionShouldNotCountTheBlockAgain(t *testing.T) {
+	t.Parallel()
+
+	contributionID := "epochstats_" + hex.EncodeToString([]byte("hash"))
+	dbWriter := &mock.DatabaseWriterStub{
+		DoMultiGetCalled: func(ids []string, index string, withSource bool, response interface{}) error {
+			require.Equal(t, dataindexer.StatsContributionsIndex, index)
+			return json.Unmarshal([]byte(fmt.Sprintf(`{"docs":[{"found":true,"_id":"%s","_source":`+
+				`{"index":"epochstats","key":"68617368","shardID":1,"timestamp":9000,"stats":null,"expired":true}}]}`, contributionID)), response)
+		},
+	}
+
+	elasticProc := newElasticsearchProcessor(dbWriter, createMockElasticProcessorArgs())
+	elasticProc.enabledIndexes[dataindexer.EpochStatsIndex] = struct{}{}
+	elasticProc.enabledIndexes[dataindexer.StatsContributionsIndex] = struct{}{}
+
+	outportBlock := createEmptyOutportBlockWithHeader()
+	outportBlock.ShardID = 1
+	outportBlock.Header = &dataBlock.Header{ShardID: 1, Epoch: 2, TimeStamp: 5000}
+	outportBlock.BlockData.HeaderHash = []byte("hash")
+
+	buffSlice := data.NewBufferSlice(data.DefaultMaxBulkSize)
+	err := elasticProc.prepareAndIndexEpochStats(outportBlock, &data.PreparedResults{}, nil, nil, buffSlice)
+	require.Nil(t, err)
+
+	// the values of the block are still counted, only the new contribution is saved so the block can be reverted
+	body := buffSlice.Buffers()[0].String()
+	require.False(t, strings.Contains(body, `"_index":"epochstats"`))
+	require.True(t, strings.Contains(body, fmt.Sprintf(`{ "index" : { "_index":"statscontributions", "_id" : "%s" } }`, contributionID)))
+	require.False(t, strings.Contains(body, `"expired"`))
+}
+
+func TestElasticProcessor_SaveHeaderOfStartOfEpochBlockShouldRemoveExpiredStatsContributions(t *testing.T) {
+	t.Parallel()
+
+	removeQuery := ""
+	dbWriter := &mock.DatabaseWriterStub{
+		DoQueryRemoveCalled: func(index string, body *bytes.Buffer) error {
+			require.Equal(t, dataindexer.StatsContributionsIndex, index)
+			removeQuery = body.String()
+			return nil
+		},
+	}
+
+	arguments := createMockElasticProcessorArgs()
+	arguments.ContributionsRetention = 10 * 24 * time.Hour
+	elasticProc := newElasticsearchProcessor(dbWriter, arguments)
+
+	outportBlock := createEmptyOutportBlockWithHeader()
+	outportBlock.ShardID = 1
+	outportBlock.Header = &dataBlock.Header{ShardID: 1, Epoch: 3, TimeStamp: 10*24*3600 + 5000, EpochStartMetaHash: []byte("metaHash")}
+	outportBlock.BlockData.HeaderHash = []byte("hash")
+
+	err := elasticProc.SaveHeader(outportBlock)
+	require.Nil(t, err)
+	require.Empty(t, removeQuery)
+
+	elasticProc.enabledIndexes[dataindexer.StatsContributionsIndex] = struct{}{}
+	err = elasticProc.SaveHeader(outportBlock)
+	require.Nil(t, err)
+	require.Equal(t, `{"query": {"bool": {"filter": [{"term": {"shardID": 1}}, {"range": {"timestamp": {"lt": 5000}}}]}}}`, removeQuery)
+
+	// the contributions are removed only when the shard starts a new epoch
+	removeQuery = ""
+	outportBlock.Header = &dataBlock.Header{ShardID: 1, Epoch: 3, TimeStamp: 10*24*3600 + 5006}
+	err = elasticProc.SaveHeader(outportBlock)
+	require.Nil(t, err)
+	require.Empty(t, removeQuery)
+
+	// the contributions are kept forever without a retention
+	elasticProc.contributionsRetention = 0
+	outportBlock.Header = &dataBlock.Header{ShardID: 1, Epoch: 4, TimeStamp: 10*24*3600 + 9000, EpochStartMetaHash: []byte("metaHash")}
+	err = elasticProc.SaveHeader(outportBlock)
+	require.Nil(t, err)
+	require.Empty(t, removeQuery)
+}
+
+func validatorsListsResponse(response interface{}, id string, publicKeys ...string) error {
+	validatorsResponse := response.(*data.ResponseValidatorsPublicKeys)
+	validatorsResponse.Docs = []*data.ResponseValidatorsPublicKeysDB{{
+		Found:  true,
+		ID:     id,
+		Source: data.ValidatorsPublicKeys{PublicKeys: publicKeys},
+	}}
+
+	return nil
+}
+
+func TestElasticProcessor_IndexConsensusStatsFromBlockAgainShouldSubtractThePreviousContribution(t *testing.T) {
+	t.Parallel()
+
+	contributionID := "consensusstats_" + hex.EncodeToString([]byte("hash"))
+	dbWriter := &mock.DatabaseWriterStub{
+		DoMultiGetCalled: func(ids []string, index string, withSource bool, response interface{}) error {
+			switch index {
+			case dataindexer.ValidatorsIndex:
+				return validatorsListsResponse(response, "1_2", "pk1", "pk2")
+			case dataindexer.StatsContributionsIndex:
+				require.Equal(t, []string{contributionID}, ids)
+				return json.Unmarshal([]byte(fmt.Sprintf(`{"docs":[{"found":true,"_id":"%s","_source":`+
+					`{"index":"consensusstats","key":"68617368","shardID":1,"timestamp":5000,"stats":`+
+					`{"pk1_epoch_2":{"publicKey":"pk1","shardID":1,"bucket":"epoch","epoch":2,"signedBlocks":1,"timestamp":5000}}}}]}`, contributionID)), response)
+			default:
+				require.Fail(t, "unexpected multi get on "+index)
+				return nil
+			}
+		},
+	}
+
+	elasticProc := newElasticsearchProcessor(dbWriter, createMockElasticProcessorArgs())
+	elasticProc.enabledIndexes[dataindexer.ConsensusStatsIndex] = struct{}{}
+	elasticProc.enabledIndexes[dataindexer.StatsContributionsIndex] = struct{}{}
+
+	outportBlock := createEmptyOutportBlockWithHeader()
+	outportBlock.ShardID = 1
+	outportBlock.Header = &dataBlock.Header{ShardID: 1, Epoch: 2, TimeStamp: 5000, PubKeysBitmap: []byte{1}}
+	outportBlock.BlockData.HeaderHash = []byte("hash")
+
+	buffSlice := data.NewBufferSlice(data.DefaultMaxBulkSize)
+	err := elasticProc.indexConsensusStatsFromBlock(outportBlock, buffSlice)
+	require.Nil(t, err)
+
+	body := buffSlice.Buffers()[0].String()
+	subtractIdx := strings.Index(body, "ctx._source.signedBlocks -= params.stats.signedBlocks")
+	addIdx := strings.Index(body, "ctx._source.signedBlocks += params.stats.signedBlocks")
+	contributionIdx := strings.Index(body, fmt.Sprintf(`{ "index" : { "_index":"statscontributions", "_id" : "%s" } }`, contributionID))
+	require.True(t, subtractIdx >= 0)
+	require.True(t, subtractIdx < addIdx)
+	require.True(t, addIdx < contributionIdx)
+	require.True(t, strings.Contains(body[contributionIdx:], `"pk2_epoch_2":{"publicKey":"pk2","shardID":1,"bucket":"epoch","epoch":2,"proposedBlocks":0,"missedProposals":0,"signedBlocks":0,"missedSignatures":1`))
+}
+
+func TestElasticProcessor_RemoveTransactionsShouldRevertConsensusStats(t *testing.T) {
+	t.Parallel()
+
+	arguments := createMockElasticProcessorArgs()
+	header := &dataBlock.Header{ShardID: 1, Epoch: 2, TimeStamp: 5000}
+	headerHash, _ := arguments.BlockProc.ComputeHeaderHash(header)
+	contributionID := "consensusstats_" + hex.EncodeToString(headerHash)
+
+	revertBody := ""
+	dbWriter := &mock.DatabaseWriterStub{
+		DoMultiGetCalled: func(ids []string, index string, withSource bool, response interface{}) error {
+			require.Equal(t, dataindexer.StatsContributionsIndex, index)
+			require.Contains(t, ids, contributionID)
+			return json.Unmarshal([]byte(fmt.Sprintf(`{"docs":[{"found":true,"_id":"%s","_source":`+
+				`{"index":"consensusstats","key":"%s","shardID":1,"timestamp":5000,"stats":`+
+				`{"pk1_epoch_2":{"publicKey":"pk1","shardID":1,"bucket":"epoch","epoch":2,"signedBlocks":1,"timestamp":5000}}}}]}`,
+				contributionID, hex.EncodeToString(headerHash))), response)
+		},
+		DoBulkRequestCalled: func(buff *bytes.Buffer, index string) error {
+			revertBody += buff.String()
+			return nil
+		},
+	}
+
+	elasticProc := newElasticsearchProcessor(dbWriter, arguments)
+	elasticProc.enabledIndexes[dataindexer.ConsensusStatsIndex] = struct{}{}
+	elasticProc.enabledIndexes[dataindexer.StatsContributionsIndex] = struct{}{}
+
+	err := elasticProc.RemoveTransactions(header, &dataBlock.Body{})
+	require.Nil(t, err)
+	require.True(t, strings.Contains(revertBody, `{ "update" : { "_index":"consensusstats", "_id" : "pk1_epoch_2" } }`))
+	require.True(t, strings.Contains(revertBody, "ctx._source.signedBlocks -= params.stats.signedBlocks"))
+	require.False(t, strings.Contains(revertBody, "ctx._source.signedBlocks += params.stats.signedBlocks"))
+	require.True(t, strings.Contains(revertBody, fmt.Sprintf(`{ "delete" : { "_index": "statscontributions", "_id" : "%s" } }`, contributionID)))
+}
+
+func TestElasticProcessor_SaveRoundsInfoAgainShouldSubtractThePreviousContribution(t *testing.T) {
+	t.Parallel()
+
+	bulkBody := ""
+	dbWriter := &mock.DatabaseWriterStub{
+		DoMultiGetCalled: func(ids []string, index string, withSource bool, response interface{}) error {
+			switch index {
+			case dataindexer.ValidatorsIndex:
+				return validatorsListsResponse(response, "0_1", "pk1", "pk2")
+			case dataindexer.StatsContributionsIndex:
+				require.Equal(t, []string{"consensusstats_round_0_1", "consensusstats_round_0_2"}, ids)
+				// only the first round was saved before
+				return json.Unmarshal([]byte(`{"docs":[{"found":true,"_id":"consensusstats_round_0_1","_source":`+
+					`{"index":"consensusstats","key":"round_0_1","shardID":0,"timestamp":10,"stats":`+
+					`{"pk2_epoch_1":{"publicKey":"pk2","shardID":0,"bucket":"epoch","epoch":1,"proposedBlocks":1,"timestamp":10}}}}`+
+					`,{"found":false,"_id":"consensusstats_round_0_2"}]}`), response)
+			default:
+				require.Fail(t, "unexpected multi get on "+index)
+				return nil
+			}
+		},
+		DoBulkRequestCalled: func(buff *bytes.Buffer, index string) error {
+			bulkBody += buff.String()
+			return nil
+		},
+	}
+
+	elasticProc := newElasticsearchProcessor(dbWriter, createMockElasticProcessorArgs())
+	elasticProc.enabledIndexes = map[string]struct{}{
+		dataindexer.ConsensusStatsIndex:     {},
+		dataindexer.StatsContributionsIndex: {},
+	}
+
+	rounds := &outport.RoundsInfo{RoundsInfo: []*outport.RoundInfo{
+		{Round: 1, SignersIndexes: []uint64{1, 0}, BlockWasProposed: true, ShardId: 0, Epoch: 1, Timestamp: 10},
+		{Round: 2, SignersIndexes: []uint64{1, 0}, BlockWasProposed: false, ShardId: 0, Epoch: 1, Timestamp: 16},
+	}}
+	err := elasticProc.SaveRoundsInfo(rounds)
+	require.Nil(t, err)
+
+	require.Equal(t, 1, strings.Count(bulkBody, "ctx._source.proposedBlocks -= params.stats.proposedBlocks"))
+	require.Equal(t, 4, strings.Count(bulkBody, "ctx._source.proposedBlocks += params.stats.proposedBlocks"))
+	require.True(t, strings.Contains(bulkBody, `{ "index" : { "_index":"statscontributions", "_id" : "consensusstats_round_0_1" } }`))
+	require.True(t, strings.Contains(bulkBody, `{ "index" : { "_index":"statscontributions", "_id" : "consensusstats_round_0_2" } }`))
+	// the contributions of the rounds have the shard and the timestamp, so they are removed when they expire
+	require.True(t, strings.Contains(bulkBody, `{"index":"consensusstats","key":"round_0_2","shardID":0,"timestamp":16,`))
+}
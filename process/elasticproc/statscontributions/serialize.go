@@ -0,0 +1,52 @@
+package statscontributions
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"github.com/kalyan3104/k-chain-es-indexer-go/data"
+	"github.com/kalyan3104/k-chain-es-indexer-go/process/elasticproc/converters"
+)
+
+// SerializeStatsContributions will serialize the provided contributions in a way that Elasticsearch expects a bulk
+// request. A contribution with the same key is overwritten
+func (scp *statsContributionsProcessor) SerializeStatsContributions(contributions []*data.StatsContribution, buffSlice *data.BufferSlice, index string) error {
+	for _, contribution := range contributions {
+		contributionSerialized, err := json.Marshal(contribution)
+		if err != nil {
+			return err
+		}
+
+		id := ComputeStatsContributionID(contribution.Index, contribution.Key)
+		meta := []byte(fmt.Sprintf(`{ "index" : { "_index":"%s", "_id" : "%s" } }%s`, index, converters.JsonEscape(id), "\n"))
+		err = buffSlice.PutData(meta, contributionSerialized)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// SerializeStatsContributionsRemoval will serialize the requests that delete the provided contributions
+func (scp *statsContributionsProcessor) SerializeStatsContributionsRemoval(contributions []*data.StatsContribution, buffSlice *data.BufferSlice, index string) error {
+	for _, contribution := range contributions {
+		id := ComputeStatsContributionID(contribution.Index, contribution.Key)
+		meta := []byte(fmt.Sprintf(`{ "delete" : { "_index": "%s", "_id" : "%s" } }%s`, index, converters.JsonEscape(id), "\n"))
+		err := buffSlice.PutData(meta, nil)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// PrepareExpiredStatsContributionsQuery will prepare the query that selects the contributions of the provided shard
+// older than the provided timestamp
+func (scp *statsContributionsProcessor) PrepareExpiredStatsContributionsQuery(shardID uint32, timestamp uint64) *bytes.Buffer {
+	query := fmt.Sprintf(`{"query": {"bool": {"filter": [{"term": {"shardID": %d}}, {"range": {"timestamp": {"lt": %d}}}]}}}`, shardID, timestamp)
+
+	return bytes.NewBuffer([]byte(query))
+}
@@ -0,0 +1,57 @@
+package statscontributions
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/kalyan3104/k-chain-es-indexer-go/data"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatsContributionsProcessor_SerializeStatsContributions(t *testing.T) {
+	t.Parallel()
+
+	contributions := []*data.StatsContribution{
+		{
+			Index:     "epochstats",
+			Key:       "hash",
+			ShardID:   1,
+			Timestamp: 5000,
+			Stats:     json.RawMessage(`{"numBlocks":1}`),
+		},
+	}
+
+	scp := NewStatsContributionsProcessor()
+	buffSlice := data.NewBufferSlice(data.DefaultMaxBulkSize)
+	err := scp.SerializeStatsContributions(contributions, buffSlice, "statscontributions")
+	require.Nil(t, err)
+
+	expectedRes := `{ "index" : { "_index":"statscontributions", "_id" : "epochstats_hash" } }
+{"index":"epochstats","key":"hash","shardID":1,"timestamp":5000,"stats":{"numBlocks":1}}
+`
+	require.Equal(t, expectedRes, buffSlice.Buffers()[0].String())
+}
+
+func TestStatsContributionsProcessor_SerializeStatsContributionsRemoval(t *testing.T) {
+	t.Parallel()
+
+	contributions := []*data.StatsContribution{
+		{Index: "epochstats", Key: "hash"},
+	}
+
+	scp := NewStatsContributionsProcessor()
+	buffSlice := data.NewBufferSlice(data.DefaultMaxBulkSize)
+	err := scp.SerializeStatsContributionsRemoval(contributions, buffSlice, "statscontributions")
+	require.Nil(t, err)
+
+	expectedRes := `{ "delete" : { "_index": "statscontributions", "_id" : "epochstats_hash" } }
+`
+	require.Equal(t, expectedRes, buffSlice.Buffers()[0].String())
+}
+
+func TestStatsContributionsProcessor_PrepareExpiredStatsContributionsQuery(t *testing.T) {
+	t.Parallel()
+
+	query := NewStatsContributionsProcessor().PrepareExpiredStatsContributionsQuery(1, 5000)
+	require.Equal(t, `{"query": {"bool": {"filter": [{"term": {"shardID": 1}}, {"range": {"timestamp": {"lt": 5000}}}]}}}`, query.String())
+}
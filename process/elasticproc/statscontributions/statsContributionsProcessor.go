@@ -0,0 +1,64 @@
+package statscontributions
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/kalyan3104/k-chain-es-indexer-go/data"
+)
+
+type statsContributionsProcessor struct{}
+
+// NewStatsContributionsProcessor creates a new instance of the statistics contributions processor, which keeps what every
+// block added to the statistics indices, so the same values can be subtracted when the block is reverted or indexed again
+func NewStatsContributionsProcessor() *statsContributionsProcessor {
+	return &statsContributionsProcessor{}
+}
+
+// PrepareStatsContribution will prepare the contribution to the provided statistics index, identified by the provided key
+func (scp *statsContributionsProcessor) PrepareStatsContribution(
+	index string,
+	key string,
+	shardID uint32,
+	timestamp uint64,
+	stats interface{},
+) (*data.StatsContribution, error) {
+	statsSerialized, err := json.Marshal(stats)
+	if err != nil {
+		return nil, err
+	}
+
+	return &data.StatsContribution{
+		Index:     index,
+		Key:       key,
+		ShardID:   shardID,
+		Timestamp: time.Duration(timestamp),
+		Stats:     statsSerialized,
+	}, nil
+}
+
+// ExtractStatsContributions will return the contributions found in the provided multi get response, mapped by their ids
+func (scp *statsContributionsProcessor) ExtractStatsContributions(response *data.ResponseRawDocuments) (map[string]*data.StatsContribution, error) {
+	contributions := make(map[string]*data.StatsContribution)
+	for _, doc := range response.Docs {
+		if !doc.Found {
+			continue
+		}
+
+		contribution := &data.StatsContribution{}
+		err := json.Unmarshal(doc.Source, contribution)
+		if err != nil {
+			return nil, err
+		}
+
+		contributions[doc.ID] = contribution
+	}
+
+	return contributions, nil
+}
+
+// ComputeStatsContributionID will compute the id of the contribution identified by the provided key to a statistics index
+func ComputeStatsContributionID(index string, key string) string {
+	return fmt.Sprintf("%s_%s", index, key)
+}
@@ -0,0 +1,57 @@
+package statscontributions
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/kalyan3104/k-chain-es-indexer-go/data"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatsContributionsProcessor_PrepareStatsContribution(t *testing.T) {
+	t.Parallel()
+
+	scp := NewStatsContributionsProcessor()
+	contribution, err := scp.PrepareStatsContribution("epochstats", "hash", 1, 5000, &data.EpochStats{Epoch: 2, ShardID: 1, NumBlocks: 1})
+	require.Nil(t, err)
+	require.Equal(t, "epochstats", contribution.Index)
+	require.Equal(t, "hash", contribution.Key)
+	require.Equal(t, uint32(1), contribution.ShardID)
+	require.Equal(t, 5000, int(contribution.Timestamp))
+
+	stats := &data.EpochStats{}
+	err = json.Unmarshal(contribution.Stats, stats)
+	require.Nil(t, err)
+	require.Equal(t, &data.EpochStats{Epoch: 2, ShardID: 1, NumBlocks: 1}, stats)
+
+	_, err = scp.PrepareStatsContribution("epochstats", "hash", 1, 5000, make(chan int))
+	require.NotNil(t, err)
+}
+
+func TestStatsContributionsProcessor_ExtractStatsContributions(t *testing.T) {
+	t.Parallel()
+
+	response := &data.ResponseRawDocuments{
+		Docs: []data.ResponseRawDocument{
+			{Found: true, ID: "epochstats_hash", Source: json.RawMessage(`{"index":"epochstats","key":"hash","shardID":1,"timestamp":5000,"stats":{"numBlocks":1}}`)},
+			{Found: false, ID: "contractstats_hash"},
+		},
+	}
+
+	scp := NewStatsContributionsProcessor()
+	contributions, err := scp.ExtractStatsContributions(response)
+	require.Nil(t, err)
+	require.Equal(t, map[string]*data.StatsContribution{
+		"epochstats_hash": {
+			Index:     "epochstats",
+			Key:       "hash",
+			ShardID:   1,
+			Timestamp: 5000,
+			Stats:     json.RawMessage(`{"numBlocks":1}`),
+		},
+	}, contributions)
+
+	response.Docs[0].Source = json.RawMessage(`not json`)
+	_, err = scp.ExtractStatsContributions(response)
+	require.NotNil(t, err)
+}
@@ -42,6 +42,17 @@ func (tr *templatesAndPolicyReaderNoKibana) GetElasticTemplatesAndPolicies() (ma
 	indexTemplates[indexer.DCDTsIndex] = noKibana.DCDTs.ToBuffer()
 	indexTemplates[indexer.ValuesIndex] = noKibana.Values.ToBuffer()
 	indexTemplates[indexer.EventsIndex] = noKibana.Events.ToBuffer()
+	indexTemplates[indexer.EpochStatsIndex] = noKibana.EpochStats.ToBuffer()
+	indexTemplates[indexer.ContractStatsIndex] = noKibana.ContractStats.ToBuffer()
+	indexTemplates[indexer.DelegationProvidersIndex] = noKibana.DelegationProviders.ToBuffer()
+	indexTemplates[indexer.RatingHistoryIndex] = noKibana.RatingHistory.ToBuffer()
+	indexTemplates[indexer.ValidatorsHistoryIndex] = noKibana.ValidatorsHistory.ToBuffer()
+	indexTemplates[indexer.ConsensusStatsIndex] = noKibana.ConsensusStats.ToBuffer()
+	indexTemplates[indexer.TxLifecycleIndex] = noKibana.TxLifecycle.ToBuffer()
+	indexTemplates[indexer.CallTreeIndex] = noKibana.CallTree.ToBuffer()
+	indexTemplates[indexer.JournalIndex] = noKibana.Journal.ToBuffer()
+	indexTemplates[indexer.IncompleteBlocksIndex] = noKibana.IncompleteBlocks.ToBuffer()
+	indexTemplates[indexer.StatsContributionsIndex] = noKibana.StatsContributions.ToBuffer()
 
 	return indexTemplates, indexPolicies, nil
 }
@@ -46,6 +46,17 @@ func getTemplatesKibana() map[string]*bytes.Buffer {
 	indexTemplates[indexer.DelegatorsIndex] = withKibana.Delegators.ToBuffer()
 	indexTemplates[indexer.OperationsIndex] = withKibana.Operations.ToBuffer()
 	indexTemplates[indexer.DCDTsIndex] = withKibana.DCDTs.ToBuffer()
+	indexTemplates[indexer.EpochStatsIndex] = withKibana.EpochStats.ToBuffer()
+	indexTemplates[indexer.ContractStatsIndex] = withKibana.ContractStats.ToBuffer()
+	indexTemplates[indexer.DelegationProvidersIndex] = withKibana.DelegationProviders.ToBuffer()
+	indexTemplates[indexer.RatingHistoryIndex] = withKibana.RatingHistory.ToBuffer()
+	indexTemplates[indexer.ValidatorsHistoryIndex] = withKibana.ValidatorsHistory.ToBuffer()
+	indexTemplates[indexer.ConsensusStatsIndex] = withKibana.ConsensusStats.ToBuffer()
+	indexTemplates[indexer.TxLifecycleIndex] = withKibana.TxLifecycle.ToBuffer()
+	indexTemplates[indexer.CallTreeIndex] = withKibana.CallTree.ToBuffer()
+	indexTemplates[indexer.JournalIndex] = withKibana.Journal.ToBuffer()
+	indexTemplates[indexer.IncompleteBlocksIndex] = withKibana.IncompleteBlocks.ToBuffer()
+	indexTemplates[indexer.StatsContributionsIndex] = withKibana.StatsContributions.ToBuffer()
 
 	return indexTemplates
 }
@@ -0,0 +1,114 @@
+package txlifecycle
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"github.com/kalyan3104/k-chain-es-indexer-go/data"
+	"github.com/kalyan3104/k-chain-es-indexer-go/process/elasticproc/converters"
+)
+
+// computeSummaryCode recomputes the final status and the end-to-end latency from the stored stages. A failed status
+// is final, a pending status never overwrites another one and otherwise the status of the latest stage is kept
+const computeSummaryCode = `
+	String status = '';
+	long statusTimestamp = -1;
+	long startTimestamp = Long.MAX_VALUE;
+	long endTimestamp = 0;
+	for (def stage : ctx._source.stages) {
+		long timestamp = stage.timestamp;
+		startTimestamp = Math.min(startTimestamp, timestamp);
+		endTimestamp = Math.max(endTimestamp, timestamp);
+		if (stage.status == null || stage.status.isEmpty() || status == 'fail') {
+			continue;
+		}
+		if (stage.status == 'pending' && !status.isEmpty()) {
+			continue;
+		}
+		if (stage.status == 'fail' || status.isEmpty() || status == 'pending' || timestamp >= statusTimestamp) {
+			status = stage.status;
+			statusTimestamp = timestamp;
+		}
+	}
+	ctx._source.status = status;
+	ctx._source.startTimestamp = startTimestamp;
+	ctx._source.endTimestamp = endTimestamp;
+	ctx._source.latency = endTimestamp - startTimestamp;
+`
+
+// SerializeTxLifecycles will serialize the provided lifecycles in a way that Elasticsearch expects a bulk request.
+// The stages are merged with the ones indexed by the other shards
+func (tlp *txLifecycleProcessor) SerializeTxLifecycles(lifecycles map[string]*data.TxLifecycle, buffSlice *data.BufferSlice, index string) error {
+	codeToExecute := `
+		if ('create' == ctx.op) {
+			ctx._source = params.lifecycle;
+		} else {
+			for (def stage : params.lifecycle.stages) {
+				ctx._source.stages.removeIf(s -> s.type == stage.type && s.hash == stage.hash && s.shardID == stage.shardID);
+				ctx._source.stages.add(stage);
+			}
+			for (String key : ['sender', 'receiver', 'senderShard', 'receiverShard']) {
+				if (params.lifecycle.containsKey(key)) {
+					ctx._source[key] = params.lifecycle[key];
+				}
+			}
+		}
+` + computeSummaryCode
+
+	for txHash, lifecycle := range lifecycles {
+		lifecycleSerialized, err := json.Marshal(lifecycle)
+		if err != nil {
+			return err
+		}
+
+		serializedDataStr := fmt.Sprintf(`{"scripted_upsert": true, "script": {`+
+			`"source": "%s",`+
+			`"lang": "painless",`+
+			`"params": { "lifecycle": %s }},`+
+			`"upsert": {}}`,
+			converters.FormatPainlessSource(codeToExecute), string(lifecycleSerialized),
+		)
+
+		meta := []byte(fmt.Sprintf(`{ "update" : { "_index":"%s", "_id" : "%s" } }%s`, index, converters.JsonEscape(txHash), "\n"))
+		err = buffSlice.PutData(meta, []byte(serializedDataStr))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// PrepareTxLifecyclesQueryInCaseOfRevert will prepare the query that removes the stages of the reverted block. The
+// documents that remain without stages are deleted
+func (tlp *txLifecycleProcessor) PrepareTxLifecyclesQueryInCaseOfRevert(blockHash string) *bytes.Buffer {
+	codeToExecute := `
+		ctx._source.stages.removeIf(s -> s.blockHash == params.blockHash);
+		if (ctx._source.stages.isEmpty()) {
+			ctx.op = 'delete';
+			return;
+		}
+` + computeSummaryCode
+
+	query := fmt.Sprintf(`
+	{
+	  "query": {
+		"nested": {
+		  "path": "stages",
+		  "query": {
+			"match": {
+			  "stages.blockHash": "%s"
+			}
+		  }
+		}
+	  },
+	  "script": {
+		"source": "%s",
+		"lang": "painless",
+		"params": {"blockHash": "%s"}
+	  }
+	}`, converters.JsonEscape(blockHash), converters.FormatPainlessSource(codeToExecute), converters.JsonEscape(blockHash))
+
+	return bytes.NewBuffer([]byte(query))
+}
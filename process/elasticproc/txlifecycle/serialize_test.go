@@ -0,0 +1,48 @@
+package txlifecycle
+
+import (
+	"testing"
+
+	"github.com/kalyan3104/k-chain-es-indexer-go/data"
+	"github.com/stretchr/testify/require"
+)
+
+const expectedSummaryCode = `String status = '';long statusTimestamp = -1;long startTimestamp = Long.MAX_VALUE;long endTimestamp = 0;for (def stage : ctx._source.stages) {long timestamp = stage.timestamp;startTimestamp = Math.min(startTimestamp, timestamp);endTimestamp = Math.max(endTimestamp, timestamp);if (stage.status == null || stage.status.isEmpty() || status == 'fail') {continue;}if (stage.status == 'pending' && !status.isEmpty()) {continue;}if (stage.status == 'fail' || status.isEmpty() || status == 'pending' || timestamp >= statusTimestamp) {status = stage.status;statusTimestamp = timestamp;}}ctx._source.status = status;ctx._source.startTimestamp = startTimestamp;ctx._source.endTimestamp = endTimestamp;ctx._source.latency = endTimestamp - startTimestamp;`
+
+func TestTxLifecycleProcessor_SerializeTxLifecycles(t *testing.T) {
+	t.Parallel()
+
+	tlp := NewTxLifecycleProcessor()
+
+	lifecycles := map[string]*data.TxLifecycle{
+		"h1": {
+			Stages: []*data.TxLifecycleStage{{
+				Type:      scResultStage,
+				Hash:      "scr1",
+				ShardID:   1,
+				BlockHash: "bh",
+				Timestamp: 100,
+			}},
+		},
+	}
+
+	buffSlice := data.NewBufferSlice(data.DefaultMaxBulkSize)
+	err := tlp.SerializeTxLifecycles(lifecycles, buffSlice, "txlifecycle")
+	require.Nil(t, err)
+
+	expectedRes := `{ "update" : { "_index":"txlifecycle", "_id" : "h1" } }
+{"scripted_upsert": true, "script": {"source": "if ('create' == ctx.op) {ctx._source = params.lifecycle;} else {for (def stage : params.lifecycle.stages) {ctx._source.stages.removeIf(s -> s.type == stage.type && s.hash == stage.hash && s.shardID == stage.shardID);ctx._source.stages.add(stage);}for (String key : ['sender', 'receiver', 'senderShard', 'receiverShard']) {if (params.lifecycle.containsKey(key)) {ctx._source[key] = params.lifecycle[key];}}}` + expectedSummaryCode + `","lang": "painless","params": { "lifecycle": {"stages":[{"type":"scr","hash":"scr1","shardID":1,"blockHash":"bh","blockNonce":0,"round":0,"timestamp":100}],"status":"","startTimestamp":0,"endTimestamp":0,"latency":0} }},"upsert": {}}
+`
+	require.Equal(t, expectedRes, buffSlice.Buffers()[0].String())
+}
+
+func TestTxLifecycleProcessor_PrepareTxLifecyclesQueryInCaseOfRevert(t *testing.T) {
+	t.Parallel()
+
+	tlp := NewTxLifecycleProcessor()
+
+	query := tlp.PrepareTxLifecyclesQueryInCaseOfRevert("bh")
+	require.Contains(t, query.String(), `"stages.blockHash": "bh"`)
+	require.Contains(t, query.String(), `"source": "ctx._source.stages.removeIf(s -> s.blockHash == params.blockHash);if (ctx._source.stages.isEmpty()) {ctx.op = 'delete';return;}`+expectedSummaryCode+`"`)
+	require.Contains(t, query.String(), `"params": {"blockHash": "bh"}`)
+}
@@ -0,0 +1,104 @@
+package txlifecycle
+
+import (
+	"time"
+
+	coreData "github.com/kalyan3104/k-chain-core-go/data"
+	"github.com/kalyan3104/k-chain-core-go/data/outport"
+	"github.com/kalyan3104/k-chain-es-indexer-go/data"
+)
+
+const (
+	sourceStage      = "source"
+	destinationStage = "destination"
+	scResultStage    = "scr"
+)
+
+type txLifecycleProcessor struct{}
+
+// NewTxLifecycleProcessor will create a new instance of txLifecycleProcessor
+func NewTxLifecycleProcessor() *txLifecycleProcessor {
+	return &txLifecycleProcessor{}
+}
+
+// PrepareTxLifecycles will prepare the execution stages that took place in the provided block, grouped by the hash of
+// the original transaction. A transaction has a source stage on the sender shard and, if it is cross-shard, a
+// destination stage on the receiver shard. Every smart contract result adds a stage on the shard where it is executed
+func (tlp *txLifecycleProcessor) PrepareTxLifecycles(
+	header coreData.HeaderHandler,
+	blockHash string,
+	txs []*data.Transaction,
+	scrs []*data.ScResult,
+	txHashStatusInfo map[string]*outport.StatusInfo,
+) map[string]*data.TxLifecycle {
+	shardID := header.GetShardID()
+	lifecycles := make(map[string]*data.TxLifecycle)
+
+	for _, tx := range txs {
+		stageType := sourceStage
+		if tx.SenderShard != shardID {
+			stageType = destinationStage
+		}
+
+		lifecycle := getOrCreateLifecycle(lifecycles, tx.Hash)
+		lifecycle.Sender = tx.Sender
+		lifecycle.Receiver = tx.Receiver
+		senderShard, receiverShard := tx.SenderShard, tx.ReceiverShard
+		lifecycle.SenderShard = &senderShard
+		lifecycle.ReceiverShard = &receiverShard
+
+		stage := createStage(header, blockHash, stageType, tx.Hash, tx.MBHash)
+		stage.Status = getStatus(tx.Hash, tx.Status, txHashStatusInfo)
+		lifecycle.Stages = append(lifecycle.Stages, stage)
+	}
+
+	for _, scr := range scrs {
+		// a smart contract result is a stage only on the shard where it is executed
+		if scr.OriginalTxHash == "" || scr.ReceiverShard != shardID {
+			continue
+		}
+
+		lifecycle := getOrCreateLifecycle(lifecycles, scr.OriginalTxHash)
+		stage := createStage(header, blockHash, scResultStage, scr.Hash, scr.MBHash)
+		stage.Status = getStatus(scr.OriginalTxHash, "", txHashStatusInfo)
+		lifecycle.Stages = append(lifecycle.Stages, stage)
+	}
+
+	return lifecycles
+}
+
+func getOrCreateLifecycle(lifecycles map[string]*data.TxLifecycle, txHash string) *data.TxLifecycle {
+	lifecycle, found := lifecycles[txHash]
+	if found {
+		return lifecycle
+	}
+
+	lifecycle = &data.TxLifecycle{
+		Stages: make([]*data.TxLifecycleStage, 0),
+	}
+	lifecycles[txHash] = lifecycle
+
+	return lifecycle
+}
+
+func createStage(header coreData.HeaderHandler, blockHash string, stageType string, hash string, mbHash string) *data.TxLifecycleStage {
+	return &data.TxLifecycleStage{
+		Type:          stageType,
+		Hash:          hash,
+		ShardID:       header.GetShardID(),
+		BlockHash:     blockHash,
+		BlockNonce:    header.GetNonce(),
+		Round:         header.GetRound(),
+		MiniBlockHash: mbHash,
+		Timestamp:     time.Duration(header.GetTimeStamp()),
+	}
+}
+
+func getStatus(txHash string, defaultStatus string, txHashStatusInfo map[string]*outport.StatusInfo) string {
+	statusInfo, found := txHashStatusInfo[txHash]
+	if found && statusInfo.Status != "" {
+		return statusInfo.Status
+	}
+
+	return defaultStatus
+}
@@ -0,0 +1,90 @@
+package txlifecycle
+
+import (
+	"testing"
+
+	"github.com/kalyan3104/k-chain-core-go/data/block"
+	"github.com/kalyan3104/k-chain-core-go/data/outport"
+	"github.com/kalyan3104/k-chain-core-go/data/transaction"
+	"github.com/kalyan3104/k-chain-es-indexer-go/data"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTxLifecycleProcessor_PrepareTxLifecyclesSourceShard(t *testing.T) {
+	t.Parallel()
+
+	tlp := NewTxLifecycleProcessor()
+
+	header := &block.Header{ShardID: 0, Nonce: 10, Round: 11, TimeStamp: 100}
+	txs := []*data.Transaction{
+		{Hash: "h1", Sender: "s", Receiver: "r", SenderShard: 0, ReceiverShard: 1, MBHash: "mb", Status: transaction.TxStatusPending.String()},
+	}
+
+	lifecycles := tlp.PrepareTxLifecycles(header, "bh", txs, nil, nil)
+	require.Len(t, lifecycles, 1)
+
+	lifecycle := lifecycles["h1"]
+	require.Equal(t, "s", lifecycle.Sender)
+	require.Equal(t, uint32(1), *lifecycle.ReceiverShard)
+	require.Equal(t, []*data.TxLifecycleStage{{
+		Type:          sourceStage,
+		Hash:          "h1",
+		ShardID:       0,
+		BlockHash:     "bh",
+		BlockNonce:    10,
+		Round:         11,
+		MiniBlockHash: "mb",
+		Status:        transaction.TxStatusPending.String(),
+		Timestamp:     100,
+	}}, lifecycle.Stages)
+}
+
+func TestTxLifecycleProcessor_PrepareTxLifecyclesDestinationShard(t *testing.T) {
+	t.Parallel()
+
+	tlp := NewTxLifecycleProcessor()
+
+	header := &block.Header{ShardID: 1, Nonce: 20, Round: 21, TimeStamp: 112}
+	txs := []*data.Transaction{
+		{Hash: "h1", SenderShard: 0, ReceiverShard: 1, Status: transaction.TxStatusSuccess.String()},
+	}
+	scrs := []*data.ScResult{
+		{Hash: "scr1", OriginalTxHash: "h1", SenderShard: 1, ReceiverShard: 1},
+		{Hash: "scr2", OriginalTxHash: "h1", SenderShard: 1, ReceiverShard: 0},
+		{Hash: "scr3", SenderShard: 1, ReceiverShard: 1},
+	}
+	txHashStatusInfo := map[string]*outport.StatusInfo{
+		"h1": {Status: transaction.TxStatusFail.String()},
+	}
+
+	lifecycles := tlp.PrepareTxLifecycles(header, "bh", txs, scrs, txHashStatusInfo)
+	require.Len(t, lifecycles, 1)
+
+	stages := lifecycles["h1"].Stages
+	require.Len(t, stages, 2)
+	require.Equal(t, destinationStage, stages[0].Type)
+	require.Equal(t, transaction.TxStatusFail.String(), stages[0].Status)
+	require.Equal(t, scResultStage, stages[1].Type)
+	require.Equal(t, "scr1", stages[1].Hash)
+	require.Equal(t, transaction.TxStatusFail.String(), stages[1].Status)
+}
+
+func TestTxLifecycleProcessor_PrepareTxLifecyclesScResultFromOtherShard(t *testing.T) {
+	t.Parallel()
+
+	tlp := NewTxLifecycleProcessor()
+
+	header := &block.Header{ShardID: 0, TimeStamp: 118}
+	scrs := []*data.ScResult{
+		{Hash: "scr2", OriginalTxHash: "h1", SenderShard: 1, ReceiverShard: 0},
+	}
+
+	lifecycles := tlp.PrepareTxLifecycles(header, "bh", nil, scrs, nil)
+	require.Len(t, lifecycles, 1)
+
+	lifecycle := lifecycles["h1"]
+	require.Nil(t, lifecycle.SenderShard)
+	require.Equal(t, "", lifecycle.Sender)
+	require.Len(t, lifecycle.Stages, 1)
+	require.Equal(t, "", lifecycle.Stages[0].Status)
+}
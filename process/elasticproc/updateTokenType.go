@@ -10,9 +10,16 @@ import (
 	"github.com/kalyan3104/k-chain-es-indexer-go/core/request"
 	"github.com/kalyan3104/k-chain-es-indexer-go/data"
 	elasticIndexer "github.com/kalyan3104/k-chain-es-indexer-go/process/dataindexer"
+	"github.com/kalyan3104/k-chain-es-indexer-go/process/elasticproc/stagetimings"
 )
 
-func (ei *elasticProcessor) indexTokens(tokensData []*data.TokenInfo, updateNFTData []*data.NFTDataUpdate, buffSlice *data.BufferSlice, shardID uint32) error {
+func (ei *elasticProcessor) indexTokens(
+	tokensData []*data.TokenInfo,
+	updateNFTData []*data.NFTDataUpdate,
+	buffSlice *data.BufferSlice,
+	shardID uint32,
+	timer *stagetimings.BlockTimer,
+) error {
 	err := ei.prepareAndAddSerializedDataForTokens(tokensData, updateNFTData, buffSlice, elasticIndexer.DCDTsIndex)
 	if err != nil {
 		return err
@@ -22,6 +29,9 @@ func (ei *elasticProcessor) indexTokens(tokensData []*data.TokenInfo, updateNFTD
 		return err
 	}
 
+	stopStage := timer.StartStage(stagetimings.TokenTypeScrollsStage)
+	defer stopStage()
+
 	err = ei.addTokenType(tokensData, elasticIndexer.AccountsDCDTIndex, shardID)
 	if err != nil {
 		return err
@@ -7,6 +7,7 @@ import (
 
 	"github.com/kalyan3104/k-chain-core-go/data/outport"
 	"github.com/kalyan3104/k-chain-es-indexer-go/data"
+	"github.com/kalyan3104/k-chain-es-indexer-go/process/elasticproc/converters"
 )
 
 // SerializeValidatorsRating will serialize validators rating
@@ -34,3 +35,53 @@ func (vp *validatorsProcessor) SerializeValidatorsRating(ratingData *outport.Val
 
 	return buffSlice.Buffers(), nil
 }
+
+// SerializeValidatorsRatingHistory will serialize validators rating in a way that every epoch is kept in a separate document
+func (vp *validatorsProcessor) SerializeValidatorsRatingHistory(ratingData *outport.ValidatorsRating) ([]*bytes.Buffer, error) {
+	buffSlice := data.NewBufferSlice(vp.bulkSizeMaxSize)
+
+	for _, ratingInfo := range ratingData.ValidatorsRatingInfo {
+		id := fmt.Sprintf("%s_%d_%d", ratingInfo.PublicKey, ratingData.ShardID, ratingData.Epoch)
+		meta := []byte(fmt.Sprintf(`{ "index" : { "_id" : "%s" } }%s`, converters.JsonEscape(id), "\n"))
+
+		ratingHistory := &data.ValidatorRatingHistory{
+			PublicKey: ratingInfo.PublicKey,
+			ShardID:   ratingData.ShardID,
+			Epoch:     ratingData.Epoch,
+			Rating:    ratingInfo.Rating,
+		}
+		serializedData, err := json.Marshal(ratingHistory)
+		if err != nil {
+			continue
+		}
+
+		err = buffSlice.PutData(meta, serializedData)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return buffSlice.Buffers(), nil
+}
+
+// SerializeValidatorsHistory will serialize the validators that joined or left the list of a shard
+func (vp *validatorsProcessor) SerializeValidatorsHistory(validatorsHistory []*data.ValidatorHistory) ([]*bytes.Buffer, error) {
+	buffSlice := data.NewBufferSlice(vp.bulkSizeMaxSize)
+
+	for _, validatorHistory := range validatorsHistory {
+		id := fmt.Sprintf("%s_%d_%d", validatorHistory.PublicKey, validatorHistory.ShardID, validatorHistory.Epoch)
+		meta := []byte(fmt.Sprintf(`{ "index" : { "_id" : "%s" } }%s`, converters.JsonEscape(id), "\n"))
+
+		serializedData, err := json.Marshal(validatorHistory)
+		if err != nil {
+			return nil, err
+		}
+
+		err = buffSlice.PutData(meta, serializedData)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return buffSlice.Buffers(), nil
+}
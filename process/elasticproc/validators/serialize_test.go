@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/kalyan3104/k-chain-core-go/data/outport"
+	"github.com/kalyan3104/k-chain-es-indexer-go/data"
 	"github.com/stretchr/testify/require"
 )
 
@@ -33,3 +34,41 @@ func TestValidatorsProcessor_SerializeValidatorsRating(t *testing.T) {
 `
 	require.Equal(t, expected, buff[0].String())
 }
+
+func TestValidatorsProcessor_SerializeValidatorsRatingHistory(t *testing.T) {
+	t.Parallel()
+
+	ratingInfo := &outport.ValidatorsRating{
+		ShardID: 1,
+		Epoch:   5,
+		ValidatorsRatingInfo: []*outport.ValidatorRatingInfo{
+			{
+				PublicKey: "bls1",
+				Rating:    50.1,
+			},
+		},
+	}
+	buff, err := (&validatorsProcessor{}).SerializeValidatorsRatingHistory(ratingInfo)
+	require.Nil(t, err)
+	expected := `{ "index" : { "_id" : "bls1_1_5" } }
+{"publicKey":"bls1","shardID":1,"epoch":5,"rating":50.1}
+`
+	require.Equal(t, expected, buff[0].String())
+}
+
+func TestValidatorsProcessor_SerializeValidatorsHistory(t *testing.T) {
+	t.Parallel()
+
+	validatorsHistory := []*data.ValidatorHistory{
+		{PublicKey: "bls1", ShardID: 0, Epoch: 3, Action: "joined"},
+		{PublicKey: "bls2", ShardID: 0, Epoch: 3, Action: "left"},
+	}
+	buff, err := (&validatorsProcessor{}).SerializeValidatorsHistory(validatorsHistory)
+	require.Nil(t, err)
+	expected := `{ "index" : { "_id" : "bls1_0_3" } }
+{"publicKey":"bls1","shardID":0,"epoch":3,"action":"joined"}
+{ "index" : { "_id" : "bls2_0_3" } }
+{"publicKey":"bls2","shardID":0,"epoch":3,"action":"left"}
+`
+	require.Equal(t, expected, buff[0].String())
+}
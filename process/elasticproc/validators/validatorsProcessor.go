@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"sort"
 
 	"github.com/kalyan3104/k-chain-core-go/core"
 	"github.com/kalyan3104/k-chain-core-go/core/check"
@@ -12,6 +13,19 @@ import (
 	"github.com/kalyan3104/k-chain-es-indexer-go/process/dataindexer"
 )
 
+const (
+	joinedAction  = "joined"
+	leftAction    = "left"
+	presentAction = "present"
+)
+
+// actionsPriority decides which action is the latest one when a public key has more actions recorded in the same epoch
+var actionsPriority = map[string]int{
+	presentAction: 0,
+	joinedAction:  1,
+	leftAction:    2,
+}
+
 type validatorsProcessor struct {
 	bulkSizeMaxSize          int
 	validatorPubkeyConverter core.PubkeyConverter
@@ -69,3 +83,109 @@ func (vp *validatorsProcessor) prepareAndSerializeValidatorsKeysForShard(shardID
 
 	return nil
 }
+
+// PrepareValidatorsHistory will compare the validators public keys of every shard with the ones from the previous epoch
+// and will return the keys that joined or left the list of the shard. For a shard without a previous list, all the keys
+// are recorded as joined in the first epoch and as present in a later one, when the history starts for that shard
+func (vp *validatorsProcessor) PrepareValidatorsHistory(validatorsPubKeys *outport.ValidatorsPubKeys, previousPubKeys map[uint32][]string) []*data.ValidatorHistory {
+	validatorsHistory := make([]*data.ValidatorHistory, 0)
+
+	for shardID, validatorPk := range validatorsPubKeys.ShardValidatorsPubKeys {
+		currentKeys := make(map[string]struct{}, len(validatorPk.Keys))
+		for _, key := range validatorPk.Keys {
+			strValidatorPk, _ := vp.validatorPubkeyConverter.Encode(key)
+			currentKeys[strValidatorPk] = struct{}{}
+		}
+
+		previousKeys, found := previousPubKeys[shardID]
+		if !found && validatorsPubKeys.Epoch > 0 {
+			for key := range currentKeys {
+				validatorsHistory = append(validatorsHistory, newValidatorHistory(key, shardID, validatorsPubKeys.Epoch, presentAction))
+			}
+			continue
+		}
+
+		previousKeysMap := make(map[string]struct{}, len(previousKeys))
+		for _, key := range previousKeys {
+			previousKeysMap[key] = struct{}{}
+			if _, ok := currentKeys[key]; !ok {
+				validatorsHistory = append(validatorsHistory, newValidatorHistory(key, shardID, validatorsPubKeys.Epoch, leftAction))
+			}
+		}
+
+		for key := range currentKeys {
+			if _, ok := previousKeysMap[key]; !ok {
+				validatorsHistory = append(validatorsHistory, newValidatorHistory(key, shardID, validatorsPubKeys.Epoch, joinedAction))
+			}
+		}
+	}
+
+	sort.Slice(validatorsHistory, func(i, j int) bool {
+		if validatorsHistory[i].ShardID != validatorsHistory[j].ShardID {
+			return validatorsHistory[i].ShardID < validatorsHistory[j].ShardID
+		}
+		return validatorsHistory[i].PublicKey < validatorsHistory[j].PublicKey
+	})
+
+	return validatorsHistory
+}
+
+// PrepareValidatorsHistoryQuery will prepare the query that selects the history of the provided shards from before the
+// provided epoch
+func (vp *validatorsProcessor) PrepareValidatorsHistoryQuery(shardIDs []uint32, epoch uint32) *bytes.Buffer {
+	serializedShardIDs, _ := json.Marshal(shardIDs)
+	query := fmt.Sprintf(`{"query": {"bool": {"filter": [{"terms": {"shardID": %s}}, {"range": {"epoch": {"lt": %d}}}]}}}`, serializedShardIDs, epoch)
+
+	return bytes.NewBuffer([]byte(query))
+}
+
+// ComputePubKeysFromHistory will compute the list of every shard from the provided history: a public key is in the list
+// of a shard if the latest action recorded for it in that shard is not a leave. The actions from the same epoch are
+// ordered by their priority, so the result does not depend on the order of the provided history
+func (vp *validatorsProcessor) ComputePubKeysFromHistory(validatorsHistory []*data.ValidatorHistory) map[uint32][]string {
+	latestActions := make(map[uint32]map[string]*data.ValidatorHistory)
+	for _, validatorHistory := range validatorsHistory {
+		shardActions, found := latestActions[validatorHistory.ShardID]
+		if !found {
+			shardActions = make(map[string]*data.ValidatorHistory)
+			latestActions[validatorHistory.ShardID] = shardActions
+		}
+
+		latest, found := shardActions[validatorHistory.PublicKey]
+		if !found || isLaterAction(latest, validatorHistory) {
+			shardActions[validatorHistory.PublicKey] = validatorHistory
+		}
+	}
+
+	pubKeys := make(map[uint32][]string, len(latestActions))
+	for shardID, shardActions := range latestActions {
+		keys := make([]string, 0, len(shardActions))
+		for key, latest := range shardActions {
+			if latest.Action != leftAction {
+				keys = append(keys, key)
+			}
+		}
+		sort.Strings(keys)
+
+		pubKeys[shardID] = keys
+	}
+
+	return pubKeys
+}
+
+func isLaterAction(latest *data.ValidatorHistory, candidate *data.ValidatorHistory) bool {
+	if latest.Epoch != candidate.Epoch {
+		return latest.Epoch < candidate.Epoch
+	}
+
+	return actionsPriority[latest.Action] < actionsPriority[candidate.Action]
+}
+
+func newValidatorHistory(publicKey string, shardID uint32, epoch uint32, action string) *data.ValidatorHistory {
+	return &data.ValidatorHistory{
+		PublicKey: publicKey,
+		ShardID:   shardID,
+		Epoch:     epoch,
+		Action:    action,
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/kalyan3104/k-chain-core-go/data/outport"
+	"github.com/kalyan3104/k-chain-es-indexer-go/data"
 	"github.com/kalyan3104/k-chain-es-indexer-go/mock"
 	"github.com/kalyan3104/k-chain-es-indexer-go/process/dataindexer"
 	"github.com/stretchr/testify/require"
@@ -36,3 +37,101 @@ func TestValidatorsProcessor_PrepareAnSerializeValidatorsPubKeys(t *testing.T) {
 {"publicKeys":["6b31","6b32"]}
 `, res[0].String())
 }
+
+func TestValidatorsProcessor_PrepareValidatorsHistory(t *testing.T) {
+	t.Parallel()
+
+	vp, _ := NewValidatorsProcessor(&mock.PubkeyConverterMock{}, 0)
+
+	validators := &outport.ValidatorsPubKeys{
+		Epoch: 31,
+		ShardValidatorsPubKeys: map[uint32]*outport.PubKeys{
+			0: {Keys: [][]byte{[]byte("k1"), []byte("k3")}},
+			1: {Keys: [][]byte{[]byte("k2")}},
+			2: {Keys: [][]byte{[]byte("k4")}},
+		},
+	}
+	previousPubKeys := map[uint32][]string{
+		0: {"6b31", "6b32"},
+		1: {"6b33"},
+	}
+
+	res := vp.PrepareValidatorsHistory(validators, previousPubKeys)
+	require.Equal(t, []*data.ValidatorHistory{
+		{PublicKey: "6b32", ShardID: 0, Epoch: 31, Action: leftAction},
+		{PublicKey: "6b33", ShardID: 0, Epoch: 31, Action: joinedAction},
+		{PublicKey: "6b32", ShardID: 1, Epoch: 31, Action: joinedAction},
+		{PublicKey: "6b33", ShardID: 1, Epoch: 31, Action: leftAction},
+		{PublicKey: "6b34", ShardID: 2, Epoch: 31, Action: presentAction},
+	}, res)
+}
+
+func TestValidatorsProcessor_PrepareValidatorsHistoryFirstEpoch(t *testing.T) {
+	t.Parallel()
+
+	vp, _ := NewValidatorsProcessor(&mock.PubkeyConverterMock{}, 0)
+
+	validators := &outport.ValidatorsPubKeys{
+		Epoch: 0,
+		ShardValidatorsPubKeys: map[uint32]*outport.PubKeys{
+			0: {Keys: [][]byte{[]byte("k1")}},
+		},
+	}
+
+	res := vp.PrepareValidatorsHistory(validators, map[uint32][]string{})
+	require.Equal(t, []*data.ValidatorHistory{
+		{PublicKey: "6b31", ShardID: 0, Epoch: 0, Action: joinedAction},
+	}, res)
+}
+
+func TestValidatorsProcessor_PrepareValidatorsHistoryQuery(t *testing.T) {
+	t.Parallel()
+
+	vp, _ := NewValidatorsProcessor(&mock.PubkeyConverterMock{}, 0)
+
+	query := vp.PrepareValidatorsHistoryQuery([]uint32{0, 2}, 31)
+	require.Equal(t, `{"query": {"bool": {"filter": [{"terms": {"shardID": [0,2]}}, {"range": {"epoch": {"lt": 31}}}]}}}`, query.String())
+}
+
+func TestValidatorsProcessor_ComputePubKeysFromHistory(t *testing.T) {
+	t.Parallel()
+
+	vp, _ := NewValidatorsProcessor(&mock.PubkeyConverterMock{}, 0)
+
+	validatorsHistory := []*data.ValidatorHistory{
+		{PublicKey: "6b31", ShardID: 0, Epoch: 30, Action: leftAction},
+		{PublicKey: "6b31", ShardID: 0, Epoch: 10, Action: joinedAction},
+		{PublicKey: "6b32", ShardID: 0, Epoch: 10, Action: presentAction},
+		{PublicKey: "6b33", ShardID: 0, Epoch: 20, Action: leftAction},
+		{PublicKey: "6b33", ShardID: 0, Epoch: 25, Action: joinedAction},
+		{PublicKey: "6b31", ShardID: 1, Epoch: 30, Action: joinedAction},
+	}
+
+	res := vp.ComputePubKeysFromHistory(validatorsHistory)
+	require.Equal(t, map[uint32][]string{
+		0: {"6b32", "6b33"},
+		1: {"6b31"},
+	}, res)
+}
+
+func TestValidatorsProcessor_ComputePubKeysFromHistorySameEpochShouldNotDependOnOrder(t *testing.T) {
+	t.Parallel()
+
+	vp, _ := NewValidatorsProcessor(&mock.PubkeyConverterMock{}, 0)
+
+	validatorsHistory := []*data.ValidatorHistory{
+		{PublicKey: "6b31", ShardID: 0, Epoch: 30, Action: leftAction},
+		{PublicKey: "6b31", ShardID: 0, Epoch: 30, Action: joinedAction},
+		{PublicKey: "6b32", ShardID: 0, Epoch: 30, Action: joinedAction},
+		{PublicKey: "6b32", ShardID: 0, Epoch: 30, Action: presentAction},
+	}
+	reversedHistory := []*data.ValidatorHistory{
+		validatorsHistory[3], validatorsHistory[2], validatorsHistory[1], validatorsHistory[0],
+	}
+
+	expected := map[uint32][]string{
+		0: {"6b32"},
+	}
+	require.Equal(t, expected, vp.ComputePubKeysFromHistory(validatorsHistory))
+	require.Equal(t, expected, vp.ComputePubKeysFromHistory(reversedHistory))
+}
@@ -19,6 +19,7 @@ import (
 	"github.com/kalyan3104/k-chain-es-indexer-go/process/dataindexer"
 	"github.com/kalyan3104/k-chain-es-indexer-go/process/elasticproc"
 	"github.com/kalyan3104/k-chain-es-indexer-go/process/elasticproc/factory"
+	"github.com/kalyan3104/k-chain-es-indexer-go/process/elasticproc/stagetimings"
 	logger "github.com/kalyan3104/k-chain-logger-go"
 )
 
@@ -44,6 +45,10 @@ type ArgsIndexerFactory struct {
 	AddressPubkeyConverter   core.PubkeyConverter
 	ValidatorPubkeyConverter core.PubkeyConverter
 	StatusMetrics            indexerCore.StatusMetricsHandler
+	SlowBlockThreshold       time.Duration
+	StagesTraceFilePath      string
+	ContributionsRetention   time.Duration
+	BlockRecorder            dataindexer.BlockRecorder
 }
 
 // NewIndexer will create a new instance of Indexer
@@ -68,6 +73,12 @@ func NewIndexer(args ArgsIndexerFactory) (dataindexer.Indexer, error) {
 		ElasticProcessor: elasticProcessor,
 		BlockContainer:   blockContainer,
 	}
+	if !check.IfNil(args.StatusMetrics) {
+		arguments.IndexingStatus = args.StatusMetrics
+	}
+	if !check.IfNil(args.BlockRecorder) {
+		arguments.BlockRecorder = args.BlockRecorder
+	}
 
 	return dataindexer.NewDataIndexer(arguments)
 }
@@ -85,6 +96,11 @@ func createElasticProcessor(args ArgsIndexerFactory) (dataindexer.ElasticProcess
 		return nil, err
 	}
 
+	stagesRecorder, err := createStagesRecorder(args)
+	if err != nil {
+		return nil, err
+	}
+
 	argsElasticProcFac := factory.ArgElasticProcessorFactory{
 		Marshalizer:              args.Marshalizer,
 		Hasher:                   args.Hasher,
@@ -92,16 +108,31 @@ func createElasticProcessor(args ArgsIndexerFactory) (dataindexer.ElasticProcess
 		ValidatorPubkeyConverter: args.ValidatorPubkeyConverter,
 		UseKibana:                args.UseKibana,
 		DBClient:                 databaseClient,
+		StagesRecorder:           stagesRecorder,
 		Denomination:             args.Denomination,
 		EnabledIndexes:           args.EnabledIndexes,
 		BulkRequestMaxSize:       args.BulkRequestMaxSize,
 		ImportDB:                 args.ImportDB,
 		Version:                  args.Version,
+		ContributionsRetention:   args.ContributionsRetention,
 	}
 
 	return factory.CreateElasticProcessor(argsElasticProcFac)
 }
 
+// createStagesRecorder returns nil when no metrics handler is provided, in which case the stage timings are not recorded
+func createStagesRecorder(args ArgsIndexerFactory) (elasticproc.BlockStagesRecorder, error) {
+	if check.IfNil(args.StatusMetrics) {
+		return nil, nil
+	}
+
+	return stagetimings.NewStagesRecorder(stagetimings.ArgsStagesRecorder{
+		MetricsHandler:     args.StatusMetrics,
+		SlowBlockThreshold: args.SlowBlockThreshold,
+		TraceFilePath:      args.StagesTraceFilePath,
+	})
+}
+
 func createElasticClient(args ArgsIndexerFactory) (elasticproc.DatabaseClientHandler, error) {
 	argsEsClient := elasticsearch.Config{
 		Addresses:     []string{args.Url},
@@ -122,7 +153,17 @@ func createElasticClient(args ArgsIndexerFactory) (elasticproc.DatabaseClientHan
 	}
 	argsEsClient.Transport = transportMetrics
 
-	return client.NewElasticClient(argsEsClient)
+	esClient, err := client.NewElasticClient(argsEsClient)
+	if err != nil {
+		return nil, err
+	}
+
+	err = esClient.SetWrittenDocumentsHandler(args.StatusMetrics)
+	if err != nil {
+		return nil, err
+	}
+
+	return esClient, nil
 }
 
 func checkDataIndexerParams(arguments ArgsIndexerFactory) error {
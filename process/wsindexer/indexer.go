@@ -3,12 +3,15 @@ package wsindexer
 import (
 	"errors"
 	"fmt"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/kalyan3104/k-chain-core-go/core/check"
 	"github.com/kalyan3104/k-chain-core-go/data/outport"
 	"github.com/kalyan3104/k-chain-core-go/marshal"
 	"github.com/kalyan3104/k-chain-es-indexer-go/core"
+	"github.com/kalyan3104/k-chain-es-indexer-go/core/request"
 	"github.com/kalyan3104/k-chain-es-indexer-go/metrics"
 	"github.com/kalyan3104/k-chain-es-indexer-go/process/dataindexer"
 	logger "github.com/kalyan3104/k-chain-logger-go"
@@ -17,6 +20,7 @@ import (
 var (
 	log               = logger.GetOrCreate("process/wsindexer")
 	errNilDataIndexer = errors.New("nil data indexer")
+	errIndexerClosed  = errors.New("indexer is closed")
 )
 
 // ArgsIndexer holds all the components needed to create a new instance of indexer
@@ -31,6 +35,17 @@ type indexer struct {
 	di            DataIndexer
 	statusMetrics core.StatusMetricsHandler
 	actions       map[string]func(marshalledData []byte) error
+
+	// mutState guards the changes of the paused and closed flags, the payloads waiting on processingCond while the
+	// processing is paused
+	mutState       sync.Mutex
+	processingCond *sync.Cond
+	paused         atomic.Bool
+	closed         atomic.Bool
+
+	// mutAdmin is held for reading while a payload is processed and for writing by the revert and the rollback
+	// triggered by the operators, so they do not run concurrently with the processing of the payloads
+	mutAdmin sync.RWMutex
 }
 
 // NewIndexer will create a new instance of *indexer
@@ -50,6 +65,7 @@ func NewIndexer(args ArgsIndexer) (*indexer, error) {
 		di:            args.DataIndexer,
 		statusMetrics: args.StatusMetrics,
 	}
+	payloadIndexer.processingCond = sync.NewCond(&payloadIndexer.mutState)
 	payloadIndexer.initActionsMap()
 
 	return payloadIndexer, nil
@@ -86,7 +102,18 @@ func (i *indexer) ProcessPayload(payload []byte, topic string, version uint32) e
 		log.Warn("indexer.ProcessPayload: cannot get shardID from payload", "error", err)
 	}
 
+	i.statusMetrics.IncrementPendingPayloads()
+	defer i.statusMetrics.DecrementPendingPayloads()
+
+	// while the processing is paused the payload is held, so no acknowledge is sent back to the observer
+	err = i.acquireProcessing()
+	if err != nil {
+		return err
+	}
+	defer i.mutAdmin.RUnlock()
+
 	start := time.Now()
+	i.statusMetrics.SetLastPayloadTime(start)
 	err = payloadTypeAction(payload)
 	duration := time.Since(start)
 
@@ -161,8 +188,14 @@ func (i *indexer) saveAccounts(marshalledData []byte) error {
 	return i.di.SaveAccounts(accounts)
 }
 
-func (i *indexer) finalizedBlock(_ []byte) error {
-	return nil
+func (i *indexer) finalizedBlock(marshalledData []byte) error {
+	finalizedBlock := &outport.FinalizedBlock{}
+	err := i.marshaller.Unmarshal(finalizedBlock, marshalledData)
+	if err != nil {
+		return err
+	}
+
+	return i.di.FinalizedBlock(finalizedBlock)
 }
 
 func (i *indexer) setSettings(marshalledData []byte) error {
@@ -175,8 +208,115 @@ func (i *indexer) setSettings(marshalledData []byte) error {
 	return i.di.SetCurrentSettings(settings)
 }
 
-// Close will close the indexer
+// acquireProcessing waits while the processing is paused and returns holding mutAdmin for reading. The flags are
+// checked again once mutAdmin is held, since the processing might have been paused or closed in the meantime
+func (i *indexer) acquireProcessing() error {
+	for {
+		err := i.waitWhilePaused()
+		if err != nil {
+			return err
+		}
+
+		i.mutAdmin.RLock()
+		if !i.paused.Load() && !i.closed.Load() {
+			return nil
+		}
+		i.mutAdmin.RUnlock()
+	}
+}
+
+func (i *indexer) waitWhilePaused() error {
+	i.mutState.Lock()
+	defer i.mutState.Unlock()
+
+	if i.paused.Load() && !i.closed.Load() {
+		log.Debug("indexer: payload processing is paused, waiting to be resumed")
+	}
+
+	for i.paused.Load() && !i.closed.Load() {
+		i.processingCond.Wait()
+	}
+
+	if i.closed.Load() {
+		return errIndexerClosed
+	}
+
+	return nil
+}
+
+// PauseProcessing will hold the received payloads until the processing is resumed. It returns after the payload in
+// progress, if any, was processed, so no payload is processed after it returns
+func (i *indexer) PauseProcessing() {
+	i.mutState.Lock()
+	wasPaused := i.paused.Swap(true)
+	i.mutState.Unlock()
+
+	// the payloads in progress hold mutAdmin for reading, so acquiring it waits for them to be processed
+	i.mutAdmin.Lock()
+	i.mutAdmin.Unlock()
+
+	if !wasPaused {
+		log.Info("indexer: payload processing was paused")
+	}
+}
+
+// ResumeProcessing will resume the processing of the payloads
+func (i *indexer) ResumeProcessing() {
+	i.mutState.Lock()
+	defer i.mutState.Unlock()
+
+	if i.paused.Load() {
+		i.paused.Store(false)
+		i.processingCond.Broadcast()
+		log.Info("indexer: payload processing was resumed")
+	}
+}
+
+// IsProcessingPaused returns true if the processing of the payloads is paused
+func (i *indexer) IsProcessingPaused() bool {
+	return i.paused.Load()
+}
+
+// RevertBlocksInRange will remove the data indexed for the blocks of the provided shard with the nonce in the provided
+// range. No payload is processed while the revert is in progress
+func (i *indexer) RevertBlocksInRange(shardID uint32, fromNonce uint64, toNonce uint64) (uint64, error) {
+	i.mutAdmin.Lock()
+	defer i.mutAdmin.Unlock()
+
+	return i.di.RevertBlocksInRange(shardID, fromNonce, toNonce)
+}
+
+// RollbackToNonce will remove the data indexed for the blocks of the provided shard with a nonce greater than the provided
+// one. No payload is processed while the rollback is in progress
+func (i *indexer) RollbackToNonce(shardID uint32, nonce uint64) (*request.RollbackResponse, error) {
+	i.mutAdmin.Lock()
+	defer i.mutAdmin.Unlock()
+
+	return i.di.RollbackToNonce(shardID, nonce)
+}
+
+// EnableIndex will enable the indexing of the provided index
+func (i *indexer) EnableIndex(index string) error {
+	return i.di.EnableIndex(index)
+}
+
+// DisableIndex will disable the indexing of the provided index
+func (i *indexer) DisableIndex(index string) error {
+	return i.di.DisableIndex(index)
+}
+
+// GetEnabledIndexes returns the indexes that are currently enabled
+func (i *indexer) GetEnabledIndexes() []string {
+	return i.di.GetEnabledIndexes()
+}
+
+// Close will close the indexer. It does not wait for the payload in progress, the paused payloads being released
 func (i *indexer) Close() error {
+	i.mutState.Lock()
+	i.closed.Store(true)
+	i.processingCond.Broadcast()
+	i.mutState.Unlock()
+
 	return i.di.Close()
 }
 
@@ -0,0 +1,279 @@
+package wsindexer
+
+import (
+	"encoding/json"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/kalyan3104/k-chain-core-go/core/check"
+	"github.com/kalyan3104/k-chain-core-go/data/outport"
+	"github.com/kalyan3104/k-chain-es-indexer-go/metrics"
+	"github.com/kalyan3104/k-chain-es-indexer-go/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const waitTimeout = time.Second
+
+func createMockArgsIndexer() ArgsIndexer {
+	return ArgsIndexer{
+		Marshaller:    &mock.MarshalizerMock{},
+		DataIndexer:   &mock.DataIndexerStub{},
+		StatusMetrics: metrics.NewStatusMetrics(),
+	}
+}
+
+func createSaveBlockPayload(t *testing.T) []byte {
+	payload, err := json.Marshal(&outport.OutportBlock{ShardID: 1})
+	require.Nil(t, err)
+
+	return payload
+}
+
+func processPayloadAsync(wsIndexer *indexer, payload []byte) chan error {
+	errChan := make(chan error, 1)
+	go func() {
+		errChan <- wsIndexer.ProcessPayload(payload, outport.TopicSaveBlock, 1)
+	}()
+
+	return errChan
+}
+
+func TestNewIndexer(t *testing.T) {
+	t.Parallel()
+
+	args := createMockArgsIndexer()
+	args.DataIndexer = nil
+	wsIndexer, err := NewIndexer(args)
+	require.Nil(t, wsIndexer)
+	require.Equal(t, errNilDataIndexer, err)
+
+	wsIndexer, err = NewIndexer(createMockArgsIndexer())
+	require.Nil(t, err)
+	require.False(t, check.IfNil(wsIndexer))
+	require.False(t, wsIndexer.IsProcessingPaused())
+}
+
+func TestIndexer_ProcessPayloadShouldWaitWhilePaused(t *testing.T) {
+	t.Parallel()
+
+	savedBlocks := uint32(0)
+	args := createMockArgsIndexer()
+	args.DataIndexer = &mock.DataIndexerStub{
+		SaveBlockCalled: func(outportBlock *outport.OutportBlock) error {
+			atomic.AddUint32(&savedBlocks, 1)
+			return nil
+		},
+	}
+	wsIndexer, _ := NewIndexer(args)
+
+	wsIndexer.PauseProcessing()
+	require.True(t, wsIndexer.IsProcessingPaused())
+
+	errChan := processPayloadAsync(wsIndexer, createSaveBlockPayload(t))
+	select {
+	case <-errChan:
+		require.Fail(t, "the payload should not be processed while paused")
+	case <-time.After(100 * time.Millisecond):
+	}
+	require.Equal(t, uint32(0), atomic.LoadUint32(&savedBlocks))
+
+	wsIndexer.ResumeProcessing()
+	require.False(t, wsIndexer.IsProcessingPaused())
+
+	select {
+	case err := <-errChan:
+		require.Nil(t, err)
+	case <-time.After(waitTimeout):
+		require.Fail(t, "the payload should be processed after resume")
+	}
+	require.Equal(t, uint32(1), atomic.LoadUint32(&savedBlocks))
+}
+
+func TestIndexer_PauseProcessingShouldWaitForThePayloadInProgress(t *testing.T) {
+	t.Parallel()
+
+	savingStarted := make(chan struct{})
+	finishSaving := make(chan struct{})
+	savedBlocks := uint32(0)
+	args := createMockArgsIndexer()
+	args.DataIndexer = &mock.DataIndexerStub{
+		SaveBlockCalled: func(outportBlock *outport.OutportBlock) error {
+			if atomic.AddUint32(&savedBlocks, 1) == 1 {
+				close(savingStarted)
+				<-finishSaving
+			}
+			return nil
+		},
+	}
+	wsIndexer, _ := NewIndexer(args)
+
+	firstErrChan := processPayloadAsync(wsIndexer, createSaveBlockPayload(t))
+	<-savingStarted
+
+	paused := make(chan struct{})
+	go func() {
+		wsIndexer.PauseProcessing()
+		close(paused)
+	}()
+
+	select {
+	case <-paused:
+		require.Fail(t, "pause should wait for the payload in progress")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	close(finishSaving)
+	select {
+	case <-paused:
+	case <-time.After(waitTimeout):
+		require.Fail(t, "pause should return after the payload in progress was processed")
+	}
+	require.Nil(t, <-firstErrChan)
+
+	secondErrChan := processPayloadAsync(wsIndexer, createSaveBlockPayload(t))
+	select {
+	case <-secondErrChan:
+		require.Fail(t, "no payload should be processed after pause returned")
+	case <-time.After(100 * time.Millisecond):
+	}
+	require.Equal(t, uint32(1), atomic.LoadUint32(&savedBlocks))
+
+	wsIndexer.ResumeProcessing()
+	select {
+	case err := <-secondErrChan:
+		require.Nil(t, err)
+	case <-time.After(waitTimeout):
+		require.Fail(t, "the payload should be processed after resume")
+	}
+	require.Equal(t, uint32(2), atomic.LoadUint32(&savedBlocks))
+}
+
+func TestIndexer_CloseShouldReleaseThePausedPayloads(t *testing.T) {
+	t.Parallel()
+
+	args := createMockArgsIndexer()
+	args.DataIndexer = &mock.DataIndexerStub{
+		SaveBlockCalled: func(outportBlock *outport.OutportBlock) error {
+			require.Fail(t, "the payload should not be processed after close")
+			return nil
+		},
+	}
+	wsIndexer, _ := NewIndexer(args)
+
+	wsIndexer.PauseProcessing()
+	errChan := processPayloadAsync(wsIndexer, createSaveBlockPayload(t))
+
+	err := wsIndexer.Close()
+	require.Nil(t, err)
+
+	select {
+	case err = <-errChan:
+		require.Equal(t, errIndexerClosed, err)
+	case <-time.After(waitTimeout):
+		require.Fail(t, "the paused payload should be released on close")
+	}
+}
+
+func TestIndexer_CloseShouldNotWaitForThePayloadInProgress(t *testing.T) {
+	t.Parallel()
+
+	savingStarted := make(chan struct{})
+	finishSaving := make(chan struct{})
+	args := createMockArgsIndexer()
+	args.DataIndexer = &mock.DataIndexerStub{
+		SaveBlockCalled: func(outportBlock *outport.OutportBlock) error {
+			close(savingStarted)
+			<-finishSaving
+			return nil
+		},
+	}
+	wsIndexer, _ := NewIndexer(args)
+
+	errChan := processPayloadAsync(wsIndexer, createSaveBlockPayload(t))
+	<-savingStarted
+
+	closed := make(chan error, 1)
+	go func() {
+		closed <- wsIndexer.Close()
+	}()
+
+	select {
+	case err := <-closed:
+		require.Nil(t, err)
+	case <-time.After(waitTimeout):
+		require.Fail(t, "close should not wait for the payload in progress")
+	}
+
+	close(finishSaving)
+	require.Nil(t, <-errChan)
+
+	err := wsIndexer.ProcessPayload(createSaveBlockPayload(t), outport.TopicSaveBlock, 1)
+	require.Equal(t, errIndexerClosed, err)
+}
+
+func TestIndexer_RevertBlocksInRangeShouldWaitForThePayloadInProgress(t *testing.T) {
+	t.Parallel()
+
+	savingStarted := make(chan struct{})
+	finishSaving := make(chan struct{})
+	reverted := uint32(0)
+	args := createMockArgsIndexer()
+	args.DataIndexer = &mock.DataIndexerStub{
+		SaveBlockCalled: func(outportBlock *outport.OutportBlock) error {
+			close(savingStarted)
+			<-finishSaving
+			return nil
+		},
+		RevertBlocksInRangeCalled: func(shardID uint32, fromNonce uint64, toNonce uint64) (uint64, error) {
+			atomic.AddUint32(&reverted, 1)
+			return 0, nil
+		},
+	}
+	wsIndexer, _ := NewIndexer(args)
+
+	errChan := processPayloadAsync(wsIndexer, createSaveBlockPayload(t))
+	<-savingStarted
+
+	revertDone := make(chan struct{})
+	go func() {
+		_, _ = wsIndexer.RevertBlocksInRange(1, 10, 20)
+		close(revertDone)
+	}()
+
+	select {
+	case <-revertDone:
+		require.Fail(t, "the revert should wait for the payload in progress")
+	case <-time.After(100 * time.Millisecond):
+	}
+	require.Equal(t, uint32(0), atomic.LoadUint32(&reverted))
+
+	close(finishSaving)
+	require.Nil(t, <-errChan)
+	select {
+	case <-revertDone:
+	case <-time.After(waitTimeout):
+		require.Fail(t, "the revert should be done after the payload in progress was processed")
+	}
+	require.Equal(t, uint32(1), atomic.LoadUint32(&reverted))
+}
+
+func TestIndexer_PauseAndResumeShouldBeIdempotent(t *testing.T) {
+	t.Parallel()
+
+	wsIndexer, _ := NewIndexer(createMockArgsIndexer())
+
+	wsIndexer.ResumeProcessing()
+	require.False(t, wsIndexer.IsProcessingPaused())
+
+	wsIndexer.PauseProcessing()
+	wsIndexer.PauseProcessing()
+	require.True(t, wsIndexer.IsProcessingPaused())
+
+	wsIndexer.ResumeProcessing()
+	wsIndexer.ResumeProcessing()
+	require.False(t, wsIndexer.IsProcessingPaused())
+
+	err := wsIndexer.ProcessPayload(createSaveBlockPayload(t), outport.TopicSaveBlock, 1)
+	require.Nil(t, err)
+}
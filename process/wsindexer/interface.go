@@ -2,6 +2,7 @@ package wsindexer
 
 import (
 	"github.com/kalyan3104/k-chain-core-go/data/outport"
+	"github.com/kalyan3104/k-chain-es-indexer-go/core/request"
 )
 
 // WSClient defines what a websocket client should do
@@ -20,6 +21,11 @@ type DataIndexer interface {
 	SaveAccounts(accountsData *outport.Accounts) error
 	FinalizedBlock(finalizedBlock *outport.FinalizedBlock) error
 	SetCurrentSettings(settings outport.OutportConfig) error
+	RevertBlocksInRange(shardID uint32, fromNonce uint64, toNonce uint64) (uint64, error)
+	RollbackToNonce(shardID uint32, nonce uint64) (*request.RollbackResponse, error)
+	EnableIndex(index string) error
+	DisableIndex(index string) error
+	GetEnabledIndexes() []string
 	Close() error
 	IsInterfaceNil() bool
 }
@@ -29,6 +29,33 @@ stop() {
   docker stop "${IMAGE_NAME}"
 }
 
+SNAPSHOTS_CONTAINER_PATH=/usr/share/elasticsearch/snapshots
+
+# starts a node with a shared filesystem snapshot repository, mounted from the snapshots folder
+start_with_snapshot_repository() {
+  ES_VERSION=$1
+  if [ -z "${ES_VERSION}" ]; then
+    ES_VERSION=${DEFAULT_ES_VERSION}
+  fi
+
+  SNAPSHOTS_FOLDER=$(pwd)/snapshots
+  mkdir -p "${SNAPSHOTS_FOLDER}"
+  # the folder is written by the elasticsearch user of the container
+  chmod 777 "${SNAPSHOTS_FOLDER}"
+
+  docker pull docker.elastic.co/elasticsearch/elasticsearch:${ES_VERSION}
+
+  docker rm ${IMAGE_NAME} 2> /dev/null
+  docker run -d --name "${IMAGE_NAME}" -p 9200:9200  -p 9300:9300 \
+   -e "discovery.type=single-node" -e "xpack.security.enabled=false" -e "ES_JAVA_OPTS=-Xms512m -Xmx512m" \
+   -e "path.repo=${SNAPSHOTS_CONTAINER_PATH}" -v "${SNAPSHOTS_FOLDER}":${SNAPSHOTS_CONTAINER_PATH} \
+    docker.elastic.co/elasticsearch/elasticsearch:${ES_VERSION}
+
+  # Wait elastic cluster to start
+  echo "Waiting Elasticsearch cluster to start..."
+  sleep 30s
+}
+
 delete() {
    for str in ${INDICES_LIST[@]}; do
       curl -XDELETE http://localhost:9200/$str-000001
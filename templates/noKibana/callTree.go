@@ -0,0 +1,75 @@
+package noKibana
+
+// CallTree will hold the configuration for the calltree index
+var CallTree = Object{
+	"index_patterns": Array{
+		"calltree-*",
+	},
+	"settings": Object{
+		"number_of_shards":   3,
+		"number_of_replicas": 0,
+	},
+	"mappings": Object{
+		"properties": Object{
+			"originalTxHash": Object{
+				"type": "keyword",
+			},
+			"numCalls": Object{
+				"type": "long",
+			},
+			"status": Object{
+				"type": "keyword",
+			},
+			"timestamp": Object{
+				"type":   "date",
+				"format": "epoch_second",
+			},
+			"tree": Object{
+				"type":    "object",
+				"enabled": false,
+			},
+			"nodes": Object{
+				"type": "nested",
+				"properties": Object{
+					"hash": Object{
+						"type": "keyword",
+					},
+					"prevTxHash": Object{
+						"type": "keyword",
+					},
+					"shardID": Object{
+						"type": "long",
+					},
+					"sender": Object{
+						"type": "keyword",
+					},
+					"receiver": Object{
+						"type": "keyword",
+					},
+					"value": Object{
+						"type": "keyword",
+					},
+					"function": Object{
+						"type": "keyword",
+					},
+					"callType": Object{
+						"type": "keyword",
+					},
+					"returnMessage": Object{
+						"type": "text",
+					},
+					"status": Object{
+						"type": "keyword",
+					},
+					"blockHash": Object{
+						"type": "keyword",
+					},
+					"timestamp": Object{
+						"type":   "date",
+						"format": "epoch_second",
+					},
+				},
+			},
+		},
+	},
+}
@@ -0,0 +1,48 @@
+package noKibana
+
+// ConsensusStats will hold the configuration for the consensusstats index
+var ConsensusStats = Object{
+	"index_patterns": Array{
+		"consensusstats-*",
+	},
+	"settings": Object{
+		"number_of_shards":   3,
+		"number_of_replicas": 0,
+	},
+	"mappings": Object{
+		"properties": Object{
+			"publicKey": Object{
+				"type": "keyword",
+			},
+			"shardID": Object{
+				"type": "long",
+			},
+			"bucket": Object{
+				"type": "keyword",
+			},
+			"epoch": Object{
+				"type": "long",
+			},
+			"day": Object{
+				"type":   "date",
+				"format": "epoch_second",
+			},
+			"proposedBlocks": Object{
+				"type": "long",
+			},
+			"missedProposals": Object{
+				"type": "long",
+			},
+			"signedBlocks": Object{
+				"type": "long",
+			},
+			"missedSignatures": Object{
+				"type": "long",
+			},
+			"timestamp": Object{
+				"type":   "date",
+				"format": "epoch_second",
+			},
+		},
+	},
+}
@@ -0,0 +1,54 @@
+package noKibana
+
+// IncompleteBlocks will hold the configuration for the incompleteblocks index
+var IncompleteBlocks = Object{
+	"index_patterns": Array{
+		"incompleteblocks-*",
+	},
+	"settings": Object{
+		"number_of_shards":   1,
+		"number_of_replicas": 0,
+	},
+	"mappings": Object{
+		"properties": Object{
+			"shardId": Object{
+				"type": "long",
+			},
+			"nonce": Object{
+				"type": "double",
+			},
+			"timestamp": Object{
+				"type":   "date",
+				"format": "epoch_second",
+			},
+			"txCount": Object{
+				"type": "long",
+			},
+			"numMiniblocksTxs": Object{
+				"type": "long",
+			},
+			"issues": Object{
+				"type": "keyword",
+			},
+			"missingMiniblocks": Object{
+				"type": "keyword",
+			},
+			"missingTransactions": Object{
+				"type": "keyword",
+			},
+			"missingScResults": Object{
+				"type": "keyword",
+			},
+			"missingReceipts": Object{
+				"type": "keyword",
+			},
+			"missingLogs": Object{
+				"type": "keyword",
+			},
+			"verifiedAt": Object{
+				"type":   "date",
+				"format": "epoch_second",
+			},
+		},
+	},
+}
@@ -0,0 +1,28 @@
+package noKibana
+
+// RatingHistory will hold the configuration for the ratinghistory index
+var RatingHistory = Object{
+	"index_patterns": Array{
+		"ratinghistory-*",
+	},
+	"settings": Object{
+		"number_of_shards":   1,
+		"number_of_replicas": 0,
+	},
+	"mappings": Object{
+		"properties": Object{
+			"publicKey": Object{
+				"type": "keyword",
+			},
+			"shardID": Object{
+				"type": "long",
+			},
+			"epoch": Object{
+				"type": "long",
+			},
+			"rating": Object{
+				"type": "double",
+			},
+		},
+	},
+}
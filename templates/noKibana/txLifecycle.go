@@ -0,0 +1,75 @@
+package noKibana
+
+// TxLifecycle will hold the configuration for the txlifecycle index
+var TxLifecycle = Object{
+	"index_patterns": Array{
+		"txlifecycle-*",
+	},
+	"settings": Object{
+		"number_of_shards":   3,
+		"number_of_replicas": 0,
+	},
+	"mappings": Object{
+		"properties": Object{
+			"sender": Object{
+				"type": "keyword",
+			},
+			"receiver": Object{
+				"type": "keyword",
+			},
+			"senderShard": Object{
+				"type": "long",
+			},
+			"receiverShard": Object{
+				"type": "long",
+			},
+			"status": Object{
+				"type": "keyword",
+			},
+			"startTimestamp": Object{
+				"type":   "date",
+				"format": "epoch_second",
+			},
+			"endTimestamp": Object{
+				"type":   "date",
+				"format": "epoch_second",
+			},
+			"latency": Object{
+				"type": "long",
+			},
+			"stages": Object{
+				"type": "nested",
+				"properties": Object{
+					"type": Object{
+						"type": "keyword",
+					},
+					"hash": Object{
+						"type": "keyword",
+					},
+					"shardID": Object{
+						"type": "long",
+					},
+					"blockHash": Object{
+						"type": "keyword",
+					},
+					"blockNonce": Object{
+						"type": "long",
+					},
+					"round": Object{
+						"type": "long",
+					},
+					"miniBlockHash": Object{
+						"type": "keyword",
+					},
+					"status": Object{
+						"type": "keyword",
+					},
+					"timestamp": Object{
+						"type":   "date",
+						"format": "epoch_second",
+					},
+				},
+			},
+		},
+	},
+}
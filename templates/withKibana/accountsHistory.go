@@ -18,12 +18,22 @@ var AccountsHistory = Object{
 			"balance": Object{
 				"type": "keyword",
 			},
+			"created": Object{
+				"type": "boolean",
+			},
 			"isSender": Object{
 				"type": "boolean",
 			},
 			"isSmartContract": Object{
 				"type": "boolean",
 			},
+			"nonce": Object{
+				"type": "long",
+			},
+			"previousState": Object{
+				"type":    "object",
+				"enabled": false,
+			},
 			"shardID": Object{
 				"type": "long",
 			},
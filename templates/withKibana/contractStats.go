@@ -0,0 +1,49 @@
+package withKibana
+
+// ContractStats will hold the configuration for the contractstats index
+var ContractStats = Object{
+	"index_patterns": Array{
+		"contractstats-*",
+	},
+	"settings": Object{
+		"number_of_shards":   3,
+		"number_of_replicas": 0,
+	},
+	"mappings": Object{
+		"properties": Object{
+			"address": Object{
+				"type": "keyword",
+			},
+			"day": Object{
+				"type":   "date",
+				"format": "epoch_second",
+			},
+			"calls": Object{
+				"type": "long",
+			},
+			"callsByFunction": Object{
+				"type":    "object",
+				"enabled": false,
+			},
+			"uniqueCallers": Object{
+				"type": "long",
+			},
+			"gasUsed": Object{
+				"type": "long",
+			},
+			"fees": Object{
+				"type": "keyword",
+			},
+			"feesNum": Object{
+				"type": "double",
+			},
+			"failedCalls": Object{
+				"type": "long",
+			},
+			"lastCalled": Object{
+				"type":   "date",
+				"format": "epoch_second",
+			},
+		},
+	},
+}
@@ -0,0 +1,48 @@
+package withKibana
+
+// DelegationProviders will hold the configuration for the delegationproviders index
+var DelegationProviders = Object{
+	"index_patterns": Array{
+		"delegationproviders-*",
+	},
+	"settings": Object{
+		"number_of_shards":   3,
+		"number_of_replicas": 0,
+	},
+	"mappings": Object{
+		"properties": Object{
+			"contract": Object{
+				"type": "keyword",
+			},
+			"activeStake": Object{
+				"type": "keyword",
+			},
+			"activeStakeNum": Object{
+				"type": "double",
+			},
+			"numDelegators": Object{
+				"type": "long",
+			},
+			"unDelegatedPending": Object{
+				"type": "keyword",
+			},
+			"unDelegatedPendingNum": Object{
+				"type": "double",
+			},
+			"claimedRewards": Object{
+				"type": "keyword",
+			},
+			"claimedRewardsNum": Object{
+				"type": "double",
+			},
+			"timestamp": Object{
+				"type":   "date",
+				"format": "epoch_second",
+			},
+			"changes": Object{
+				"type":    "object",
+				"enabled": false,
+			},
+		},
+	},
+}
@@ -0,0 +1,78 @@
+package withKibana
+
+// EpochStats will hold the configuration for the epochstats index
+var EpochStats = Object{
+	"index_patterns": Array{
+		"epochstats-*",
+	},
+	"settings": Object{
+		"number_of_shards":   3,
+		"number_of_replicas": 0,
+	},
+	"mappings": Object{
+		"properties": Object{
+			"epoch": Object{
+				"type": "long",
+			},
+			"shardID": Object{
+				"type": "long",
+			},
+			"numBlocks": Object{
+				"type": "long",
+			},
+			"txCount": Object{
+				"type": "long",
+			},
+			"txsByOperation": Object{
+				"type": "object",
+			},
+			"txsByStatus": Object{
+				"type": "object",
+			},
+			"scResultsCount": Object{
+				"type": "long",
+			},
+			"fees": Object{
+				"type": "keyword",
+			},
+			"feesNum": Object{
+				"type": "double",
+			},
+			"developerFees": Object{
+				"type": "keyword",
+			},
+			"developerFeesNum": Object{
+				"type": "double",
+			},
+			"gasUsed": Object{
+				"type": "long",
+			},
+			"activeSenders": Object{
+				"type": "long",
+			},
+			"activeReceivers": Object{
+				"type": "long",
+			},
+			"newContracts": Object{
+				"type": "long",
+			},
+			"newAccounts": Object{
+				"type": "long",
+			},
+			"tokenTransfers": Object{
+				"type": "long",
+			},
+			"finalized": Object{
+				"type": "boolean",
+			},
+			"startTimestamp": Object{
+				"type":   "date",
+				"format": "epoch_second",
+			},
+			"timestamp": Object{
+				"type":   "date",
+				"format": "epoch_second",
+			},
+		},
+	},
+}
@@ -0,0 +1,47 @@
+package withKibana
+
+// Journal will hold the configuration for the journal index
+var Journal = Object{
+	"index_patterns": Array{
+		"journal-*",
+	},
+	"settings": Object{
+		"number_of_shards":   3,
+		"number_of_replicas": 0,
+	},
+	"mappings": Object{
+		"properties": Object{
+			"blockHash": Object{
+				"type": "keyword",
+			},
+			"shardID": Object{
+				"type": "long",
+			},
+			"timestamp": Object{
+				"type":   "date",
+				"format": "epoch_second",
+			},
+			"index": Object{
+				"type": "keyword",
+			},
+			"docID": Object{
+				"type": "keyword",
+			},
+			"existed": Object{
+				"type": "boolean",
+			},
+			"source": Object{
+				"type":    "object",
+				"enabled": false,
+			},
+			"written": Object{
+				"type":    "object",
+				"enabled": false,
+			},
+			"increments": Object{
+				"type":    "object",
+				"enabled": false,
+			},
+		},
+	},
+}
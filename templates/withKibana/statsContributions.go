@@ -0,0 +1,33 @@
+package withKibana
+
+// StatsContributions will hold the configuration for the statscontributions index
+var StatsContributions = Object{
+	"index_patterns": Array{
+		"statscontributions-*",
+	},
+	"settings": Object{
+		"number_of_shards":   3,
+		"number_of_replicas": 0,
+	},
+	"mappings": Object{
+		"properties": Object{
+			"index": Object{
+				"type": "keyword",
+			},
+			"key": Object{
+				"type": "keyword",
+			},
+			"shardID": Object{
+				"type": "long",
+			},
+			"timestamp": Object{
+				"type":   "date",
+				"format": "epoch_second",
+			},
+			"stats": Object{
+				"type":    "object",
+				"enabled": false,
+			},
+		},
+	},
+}
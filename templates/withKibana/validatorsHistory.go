@@ -0,0 +1,28 @@
+package withKibana
+
+// ValidatorsHistory will hold the configuration for the validatorshistory index
+var ValidatorsHistory = Object{
+	"index_patterns": Array{
+		"validatorshistory-*",
+	},
+	"settings": Object{
+		"number_of_shards":   1,
+		"number_of_replicas": 0,
+	},
+	"mappings": Object{
+		"properties": Object{
+			"publicKey": Object{
+				"type": "keyword",
+			},
+			"shardID": Object{
+				"type": "long",
+			},
+			"epoch": Object{
+				"type": "long",
+			},
+			"action": Object{
+				"type": "keyword",
+			},
+		},
+	},
+}
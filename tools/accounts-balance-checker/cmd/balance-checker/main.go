@@ -10,7 +10,9 @@ import (
 	checkNil "github.com/kalyan3104/k-chain-core-go/core/check"
 	"github.com/kalyan3104/k-chain-core-go/core/closing"
 	"github.com/kalyan3104/k-chain-es-indexer-go/tools/accounts-balance-checker/pkg/check"
+	"github.com/kalyan3104/k-chain-es-indexer-go/tools/accounts-balance-checker/pkg/checkpoint"
 	"github.com/kalyan3104/k-chain-es-indexer-go/tools/accounts-balance-checker/pkg/config"
+	"github.com/kalyan3104/k-chain-es-indexer-go/tools/accounts-balance-checker/pkg/report"
 	logger "github.com/kalyan3104/k-chain-logger-go"
 	"github.com/kalyan3104/k-chain-logger-go/file"
 	"github.com/urfave/cli"
@@ -38,8 +40,9 @@ var (
 		Usage: "If set, the checker wil verify all the balance value of the accounts with DCDT",
 	}
 	repairFlag = cli.BoolFlag{
-		Name:  "repair",
-		Usage: "If set, the checker will also repair the wrong balances",
+		Name: "repair",
+		Usage: "If set, the checker will not check the balances, but will repair the wrong balances saved in the report " +
+			"file by a previous check",
 	}
 
 	logLevel = cli.StringFlag{
@@ -96,6 +99,7 @@ func startCheck(ctx *cli.Context) {
 		log.Error("cannot initialize logger", "error", err)
 		return
 	}
+	defer closeFileLogging(fileLogging)
 
 	cfg, err := readConfig(ctx)
 	if err != nil {
@@ -103,9 +107,26 @@ func startCheck(ctx *cli.Context) {
 		return
 	}
 
-	repair := ctx.Bool(repairFlag.Name)
+	shouldRepair := ctx.Bool(repairFlag.Name)
+	if shouldRepair {
+		repairFromReport(cfg)
+		return
+	}
+
+	checkpointHandler, err := checkpoint.NewCheckpointHandler(cfg.Checkpoint.FilePath)
+	if err != nil {
+		log.Error("cannot create checkpoint handler", "error", err)
+		return
+	}
+
+	reporter, err := createReportWriter(cfg, checkpointHandler.HasProgress())
+	if err != nil {
+		log.Error("cannot create report writer", "error", err)
+		return
+	}
+	defer closeReportWriter(reporter)
 
-	balanceChecker, err := check.CreateBalanceChecker(cfg, repair)
+	balanceChecker, err := check.CreateBalanceChecker(cfg, reporter, checkpointHandler)
 	if err != nil {
 		log.Error("cannot create balance checker", "error", err)
 		return
@@ -134,13 +155,48 @@ func startCheck(ctx *cli.Context) {
 	if !shouldCheckBalanceREWA && !shouldCheckBalanceDCDT {
 		log.Error("no flag has been provided")
 	}
+}
 
-	if checkNil.IfNilReflect(fileLogging) {
-		err = fileLogging.Close()
-		log.LogIfError(err)
+func repairFromReport(cfg *config.Config) {
+	reportRepairer, err := check.CreateReportRepairer(cfg)
+	if err != nil {
+		log.Error("cannot create report repairer", "error", err)
+		return
 	}
 
-	return
+	err = reportRepairer.RepairFromReport(cfg.Report.FilePath)
+	if err != nil {
+		log.Error("cannot repair balances from report", "error", err)
+	}
+}
+
+type reportWriterHandler interface {
+	check.ReportWriter
+	NumRecords() uint64
+	Close() error
+}
+
+// createReportWriter keeps the records of the previous run when the check resumes from a checkpoint
+func createReportWriter(cfg *config.Config, appendRecords bool) (reportWriterHandler, error) {
+	if cfg.Report.FilePath == "" {
+		return report.NewDisabledWriter(), nil
+	}
+
+	return report.NewReportWriter(report.ArgsReportWriter{
+		FilePath: cfg.Report.FilePath,
+		Append:   appendRecords,
+	})
+}
+
+func closeReportWriter(reporter reportWriterHandler) {
+	log.Info("report", "num records", reporter.NumRecords())
+	log.LogIfError(reporter.Close())
+}
+
+func closeFileLogging(fileLogging closing.Closer) {
+	if !checkNil.IfNilReflect(fileLogging) {
+		log.LogIfError(fileLogging.Close())
+	}
 }
 
 func readConfig(ctx *cli.Context) (*config.Config, error) {
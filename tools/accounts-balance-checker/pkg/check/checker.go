@@ -4,12 +4,13 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/kalyan3104/k-chain-core-go/core"
 	"github.com/kalyan3104/k-chain-core-go/core/check"
 	indexerData "github.com/kalyan3104/k-chain-es-indexer-go/data"
-	indexer "github.com/kalyan3104/k-chain-es-indexer-go/process/dataindexer"
+	"github.com/kalyan3104/k-chain-es-indexer-go/tools/accounts-balance-checker/pkg/report"
 	"github.com/kalyan3104/k-chain-es-indexer-go/tools/accounts-balance-checker/pkg/utils"
 	logger "github.com/kalyan3104/k-chain-logger-go"
 )
@@ -17,18 +18,20 @@ import (
 const (
 	accountsIndex          = "accounts"
 	addressBalanceEndpoint = "/address/%s/balance"
+
+	rewaCheckName = "rewa"
+	dcdtCheckName = "dcdt"
 )
 
 var log = logger.GetOrCreate("checker")
 
 type balanceChecker struct {
-	balanceToFloat              indexer.BalanceConverter
 	pubKeyConverter             core.PubkeyConverter
 	esClient                    ESClientHandler
 	restClient                  RestClientHandler
+	reporter                    ReportWriter
+	checkpoint                  CheckpointHandler
 	maxNumberOfParallelRequests int
-
-	doRepair bool
 }
 
 // NewBalanceChecker will create a new instance of balanceChecker
@@ -36,8 +39,8 @@ func NewBalanceChecker(
 	esClient ESClientHandler,
 	restClient RestClientHandler,
 	pubKeyConverter core.PubkeyConverter,
-	balanceToFloat indexer.BalanceConverter,
-	repair bool,
+	reporter ReportWriter,
+	checkpoint CheckpointHandler,
 	maxNumberOfRequestsInParallel int,
 ) (*balanceChecker, error) {
 	if check.IfNilReflect(esClient) {
@@ -49,25 +52,34 @@ func NewBalanceChecker(
 	if check.IfNil(pubKeyConverter) {
 		return nil, errors.New("nil pub key converter")
 	}
-	if check.IfNilReflect(balanceToFloat) {
-		return nil, errors.New("nil balance converter")
+	if check.IfNil(reporter) {
+		return nil, errors.New("nil report writer")
+	}
+	if check.IfNil(checkpoint) {
+		return nil, errors.New("nil checkpoint handler")
 	}
 
 	return &balanceChecker{
 		esClient:                    esClient,
 		restClient:                  restClient,
 		pubKeyConverter:             pubKeyConverter,
-		balanceToFloat:              balanceToFloat,
-		doRepair:                    repair,
+		reporter:                    reporter,
+		checkpoint:                  checkpoint,
 		maxNumberOfParallelRequests: maxNumberOfRequestsInParallel,
 	}, nil
 }
 
-// CheckREWABalances will compare the REWA balance from the Elasticsearch database with the results from gateway
+// CheckREWABalances will compare the REWA balance from the Elasticsearch database with the results from gateway. The
+// accounts are read in the order of their addresses, starting after the last account saved in the checkpoint
 func (bc *balanceChecker) CheckREWABalances() error {
+	lastAddress := bc.checkpoint.LastAddress(rewaCheckName)
+	if lastAddress != "" {
+		log.Info("resuming REWA check", "after address", lastAddress)
+	}
+
 	return bc.esClient.DoScrollRequestAllDocuments(
 		accountsIndex,
-		[]byte(matchAllQuery),
+		getAccountsAfterAddressQuery(lastAddress),
 		bc.handlerFuncScrollAccountREWA,
 	)
 }
@@ -84,21 +96,31 @@ func (bc *balanceChecker) handlerFuncScrollAccountREWA(responseBytes []byte) err
 
 	defer utils.LogExecutionTime(log, time.Now(), fmt.Sprintf("checked bulk of accounts %d", countCheck))
 
+	hits := accountsRes.Hits.Hits
+	if len(hits) == 0 {
+		return nil
+	}
+
 	maxGoroutines := bc.maxNumberOfParallelRequests
-	done := make(chan struct{}, maxGoroutines)
-	for _, acct := range accountsRes.Hits.Hits {
+	done, wg := make(chan struct{}, maxGoroutines), &sync.WaitGroup{}
+	for _, acct := range hits {
 		done <- struct{}{}
-		go bc.checkBalance(acct.Source, done)
+		wg.Add(1)
+		go bc.checkBalance(acct.Source, done, wg)
 	}
 
+	// the bulk is saved in the checkpoint only after all its accounts were checked
+	wg.Wait()
+
 	log.Info("comparing", "bulk count", countCheck)
 
-	return nil
+	return bc.checkpoint.SaveLastAddress(rewaCheckName, hits[len(hits)-1].Source.Address)
 }
 
-func (bc *balanceChecker) checkBalance(acct indexerData.AccountInfo, done chan struct{}) {
+func (bc *balanceChecker) checkBalance(acct indexerData.AccountInfo, done chan struct{}, wg *sync.WaitGroup) {
 	defer func() {
 		<-done
+		wg.Done()
 	}()
 
 	gatewayBalance, errGetBalance := bc.getAccountBalance(acct.Address)
@@ -119,10 +141,10 @@ func (bc *balanceChecker) checkBalance(acct indexerData.AccountInfo, done chan s
 			timestampLast, _ := bc.getLasTimeWhenBalanceWasChanged("", acct.Address)
 			timestampString := formatTimestamp(int64(timestampLast))
 
-			err = bc.fixWrongBalance(acct.Address, "", uint64(timestampLast), gatewayBalance, accountsIndex)
-			if err != nil {
-				log.Warn("cannot update balance from es", "addr", acct.Address, "data", timestampString)
-			}
+			record := bc.newRecord(acct.Address, "", report.MismatchDifferent, uint64(timestampLast))
+			record.BalanceES = newBalance
+			record.BalanceNode = gatewayBalance
+			bc.writeRecord(record)
 
 			log.Warn("balance mismatch",
 				"address", acct.Address,
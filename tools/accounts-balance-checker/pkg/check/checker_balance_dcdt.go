@@ -4,18 +4,21 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"sort"
 	"sync"
 	"sync/atomic"
 	"time"
 
 	"github.com/kalyan3104/k-chain-core-go/core"
+	"github.com/kalyan3104/k-chain-es-indexer-go/tools/accounts-balance-checker/pkg/report"
 	"github.com/kalyan3104/k-chain-es-indexer-go/tools/accounts-balance-checker/pkg/utils"
 )
 
 const (
-	maxDocumentsFromES = 9999
-	accountsdcdtIndex  = "accountsdcdt"
-	operationsIndex    = "operations"
+	maxDocumentsFromES  = 9999
+	checkpointBatchSize = 1000
+	accountsdcdtIndex   = "accountsdcdt"
+	operationsIndex     = "operations"
 
 	allTokensEndpoint    = "/address/%s/dcdt"
 	specificDCDTEndpoint = allTokensEndpoint + "/%s"
@@ -24,30 +27,59 @@ const (
 
 var countTotalCompared uint64 = 0
 
-// CheckDCDTBalances will compare all the DCDT balances from the Elasticsearch with the results from gateway
+// CheckDCDTBalances will compare all the DCDT balances from the Elasticsearch with the results from gateway. The
+// accounts are checked in batches, in the order of their addresses, starting after the last account saved in the
+// checkpoint
 func (bc *balanceChecker) CheckDCDTBalances() error {
-	balancesFromEs, err := bc.getAccountsByQuery(matchAllQuery)
+	lastAddress := bc.checkpoint.LastAddress(dcdtCheckName)
+	if lastAddress != "" {
+		log.Info("resuming DCDT check", "after address", lastAddress)
+	}
+
+	balancesFromEs, err := bc.getAccountsByQuery(string(getAccountsAfterAddressQuery(lastAddress)))
 	if err != nil {
 		return err
 	}
 
 	log.Info("total accounts with DCDT tokens ", "count", len(balancesFromEs))
 
+	addresses := make([]string, 0, len(balancesFromEs))
+	for addr := range balancesFromEs {
+		addresses = append(addresses, addr)
+	}
+	sort.Strings(addresses)
+
+	for start := 0; start < len(addresses); start += checkpointBatchSize {
+		end := start + checkpointBatchSize
+		if end > len(addresses) {
+			end = len(addresses)
+		}
+
+		bc.compareBatchOfAddresses(addresses[start:end], balancesFromEs)
+
+		err = bc.checkpoint.SaveLastAddress(dcdtCheckName, addresses[end-1])
+		if err != nil {
+			return err
+		}
+	}
+
+	log.Info("done", "total compared", countTotalCompared)
+
+	return nil
+}
+
+func (bc *balanceChecker) compareBatchOfAddresses(addresses []string, balancesFromEs balancesDCDT) {
 	maxGoroutines := bc.maxNumberOfParallelRequests
 	done, wg := make(chan struct{}, maxGoroutines), &sync.WaitGroup{}
-	for addr, tokenBalanceMap := range balancesFromEs {
+	for _, addr := range addresses {
 		done <- struct{}{}
 		wg.Add(1)
 
 		atomic.AddUint64(&countTotalCompared, 1)
-		go bc.compareBalancesFromES(addr, tokenBalanceMap, done, wg)
+		go bc.compareBalancesFromES(addr, balancesFromEs.getBalancesForAddress(addr), done, wg)
 	}
 
 	wg.Wait()
-
-	log.Info("done", "total compared", countTotalCompared)
-
-	return nil
 }
 
 func (bc *balanceChecker) compareBalancesFromES(addr string, tokenBalanceMap map[string]string, done chan struct{}, wg *sync.WaitGroup) {
@@ -136,11 +168,9 @@ func (bc *balanceChecker) compareBalances(balancesFromES, balancesFromProxy map[
 				"data", timestampString,
 				"id", id)
 
-			err := bc.deleteExtraBalance(address, tokenIdentifier, uint64(timestampLast), accountsdcdtIndex)
-			if err != nil {
-				log.Warn("cannot remove balance from es",
-					"addr", address, "identifier", tokenIdentifier, "error", err)
-			}
+			record := bc.newRecord(address, tokenIdentifier, report.MismatchExtraInES, uint64(timestampLast))
+			record.BalanceES = balanceES
+			bc.writeRecord(record)
 
 			continue
 		}
@@ -155,10 +185,10 @@ func (bc *balanceChecker) compareBalances(balancesFromES, balancesFromProxy map[
 			timestampLast, id := bc.getLasTimeWhenBalanceWasChanged(tokenIdentifier, address)
 			timestampString := formatTimestamp(int64(timestampLast))
 
-			err := bc.fixWrongBalance(address, tokenIdentifier, uint64(timestampLast), balanceProxy, accountsdcdtIndex)
-			if err != nil {
-				log.Warn("cannot update balance from es", "addr", address, "identifier", tokenIdentifier)
-			}
+			record := bc.newRecord(address, tokenIdentifier, report.MismatchDifferent, uint64(timestampLast))
+			record.BalanceES = balanceES
+			record.BalanceNode = balanceProxy
+			bc.writeRecord(record)
 
 			log.Warn("different balance", "address", address,
 				"token identifier", tokenIdentifier,
@@ -184,6 +214,10 @@ func (bc *balanceChecker) compareBalances(balancesFromES, balancesFromProxy map[
 		log.Warn("missing balance from ES", "address", address,
 			"token identifier", tokenIdentifier, "balance", balance,
 		)
+
+		record := bc.newRecord(address, tokenIdentifier, report.MismatchMissingFromES, 0)
+		record.BalanceNode = balance
+		bc.writeRecord(record)
 	}
 
 	return false
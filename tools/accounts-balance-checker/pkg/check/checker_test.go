@@ -0,0 +1,270 @@
+package check
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/kalyan3104/k-chain-core-go/core/pubkeyConverter"
+	"github.com/kalyan3104/k-chain-es-indexer-go/tools/accounts-balance-checker/pkg/checkpoint"
+	"github.com/kalyan3104/k-chain-es-indexer-go/tools/accounts-balance-checker/pkg/report"
+	"github.com/kalyan3104/k-chain-es-indexer-go/tools/accounts-balance-checker/pkg/rest"
+)
+
+// esClientStub serves the accounts index from memory: the scroll honors the address range of the checkpoint query
+type esClientStub struct {
+	mut             sync.Mutex
+	balances        map[string]string
+	scrolledAfter   []string
+	numBulkRequests int
+}
+
+func (ecs *esClientStub) DoScrollRequestAllDocuments(_ string, body []byte, handlerFunc func(responseBytes []byte) error) error {
+	query := struct {
+		Query struct {
+			Range struct {
+				Address struct {
+					Gt string `json:"gt"`
+				} `json:"address"`
+			} `json:"range"`
+		} `json:"query"`
+	}{}
+	err := json.Unmarshal(body, &query)
+	if err != nil {
+		return err
+	}
+
+	lastAddress := query.Query.Range.Address.Gt
+	ecs.mut.Lock()
+	ecs.scrolledAfter = append(ecs.scrolledAfter, lastAddress)
+	ecs.mut.Unlock()
+
+	addresses := make([]string, 0, len(ecs.balances))
+	for address := range ecs.balances {
+		if address > lastAddress {
+			addresses = append(addresses, address)
+		}
+	}
+	sort.Strings(addresses)
+
+	return handlerFunc(ecs.accountsResponse(addresses))
+}
+
+func (ecs *esClientStub) DoGetRequest(buff *bytes.Buffer, index string, response interface{}, _ int) error {
+	if index != accountsIndex {
+		return json.Unmarshal([]byte(`{"hits":{"hits":[]}}`), response)
+	}
+
+	query := struct {
+		Query struct {
+			IDs struct {
+				Values []string `json:"values"`
+			} `json:"ids"`
+		} `json:"query"`
+	}{}
+	err := json.Unmarshal(buff.Bytes(), &query)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(ecs.accountsResponse(query.Query.IDs.Values), response)
+}
+
+func (ecs *esClientStub) DoBulkRequest(_ *bytes.Buffer, _ string) error {
+	ecs.mut.Lock()
+	ecs.numBulkRequests++
+	ecs.mut.Unlock()
+
+	return nil
+}
+
+func (ecs *esClientStub) accountsResponse(addresses []string) []byte {
+	response := &ResponseAccounts{}
+	for _, address := range addresses {
+		hitBytes := fmt.Sprintf(`{"_id":"%s","_source":{"address":"%s","balance":"%s"}}`, address, address, ecs.balances[address])
+		hitsBytes := []byte(`{"hits":{"hits":[` + hitBytes + `]}}`)
+
+		hitResponse := &ResponseAccounts{}
+		_ = json.Unmarshal(hitsBytes, hitResponse)
+		response.Hits.Hits = append(response.Hits.Hits, hitResponse.Hits.Hits...)
+	}
+
+	responseBytes, _ := json.Marshal(response)
+
+	return responseBytes
+}
+
+// gatewayStub answers with the balances of the node and counts the calls
+type gatewayStub struct {
+	mut      sync.Mutex
+	balances map[string]string
+	numCalls int
+}
+
+func (gs *gatewayStub) CallGetRestEndPoint(path string, value interface{}) error {
+	gs.mut.Lock()
+	gs.numCalls++
+	gs.mut.Unlock()
+
+	for address, balance := range gs.balances {
+		if path == "/address/"+address+"/balance" {
+			return json.Unmarshal([]byte(`{"data":{"balance":"`+balance+`"},"code":"successful"}`), value)
+		}
+	}
+
+	return json.Unmarshal([]byte(`{"error":"unknown endpoint","code":"internal_issue"}`), value)
+}
+
+func createTestBalanceChecker(t *testing.T, esClient ESClientHandler, restClient RestClientHandler, reportPath string, checkpointPath string) *balanceChecker {
+	reportWriter, err := report.NewReportWriter(report.ArgsReportWriter{FilePath: reportPath})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		_ = reportWriter.Close()
+	})
+
+	checkpointHandler, err := checkpoint.NewCheckpointHandler(checkpointPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	converter, err := pubkeyConverter.NewBech32PubkeyConverter(32, log)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	checker, err := NewBalanceChecker(esClient, restClient, converter, reportWriter, checkpointHandler, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return checker
+}
+
+func readReport(t *testing.T, reportPath string) []*report.Record {
+	records := make([]*report.Record, 0)
+	err := report.ReadRecords(reportPath, func(record *report.Record) error {
+		records = append(records, record)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return records
+}
+
+func requireMismatchReport(t *testing.T, records []*report.Record) {
+	if len(records) != 1 {
+		t.Fatalf("expected one mismatch, got %d", len(records))
+	}
+
+	expectedRecord := report.Record{
+		Type:        report.TypeREWA,
+		Mismatch:    report.MismatchDifferent,
+		Address:     "addr2",
+		Index:       accountsIndex,
+		BalanceES:   "200",
+		BalanceNode: "250",
+	}
+	if *records[0] != expectedRecord {
+		t.Fatalf("unexpected report record %+v", *records[0])
+	}
+}
+
+func TestBalanceChecker_CheckREWABalancesRecordedAndReplayedFromFixtures(t *testing.T) {
+	dirPath := t.TempDir()
+	fixturesPath := filepath.Join(dirPath, "fixtures")
+	esClient := &esClientStub{
+		balances: map[string]string{"addr1": "100", "addr2": "200", "addr3": "7"},
+	}
+	gateway := &gatewayStub{
+		balances: map[string]string{"addr1": "100", "addr2": "250", "addr3": "7"},
+	}
+
+	recordingClient, err := rest.NewRecordingClient(gateway, fixturesPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	recordReportPath := filepath.Join(dirPath, "record-report.jsonl")
+	checker := createTestBalanceChecker(t, esClient, recordingClient, recordReportPath, "")
+	err = checker.CheckREWABalances()
+	if err != nil {
+		t.Fatal(err)
+	}
+	requireMismatchReport(t, readReport(t, recordReportPath))
+
+	numGatewayCalls := gateway.numCalls
+	if numGatewayCalls != 3 {
+		t.Fatalf("expected 3 gateway calls, got %d", numGatewayCalls)
+	}
+
+	// the replay runs without the gateway, from the recorded responses only
+	fixtureClient, err := rest.NewFixtureClient(fixturesPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	replayReportPath := filepath.Join(dirPath, "replay-report.jsonl")
+	checkpointPath := filepath.Join(dirPath, "checkpoint.json")
+	checker = createTestBalanceChecker(t, esClient, fixtureClient, replayReportPath, checkpointPath)
+	err = checker.CheckREWABalances()
+	if err != nil {
+		t.Fatal(err)
+	}
+	requireMismatchReport(t, readReport(t, replayReportPath))
+	if gateway.numCalls != numGatewayCalls {
+		t.Fatal("the replay should not call the gateway")
+	}
+
+	// a check resumed from the checkpoint skips the accounts already compared
+	resumedReportPath := filepath.Join(dirPath, "resumed-report.jsonl")
+	checker = createTestBalanceChecker(t, esClient, fixtureClient, resumedReportPath, checkpointPath)
+	err = checker.CheckREWABalances()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(readReport(t, resumedReportPath)) != 0 {
+		t.Fatal("the resumed check should not report the accounts already compared")
+	}
+
+	expectedScrolls := []string{"", "", "addr3"}
+	if len(esClient.scrolledAfter) != len(expectedScrolls) {
+		t.Fatalf("unexpected scrolls %v", esClient.scrolledAfter)
+	}
+	for idx := range expectedScrolls {
+		if esClient.scrolledAfter[idx] != expectedScrolls[idx] {
+			t.Fatalf("unexpected scrolls %v", esClient.scrolledAfter)
+		}
+	}
+	if esClient.numBulkRequests != 0 {
+		t.Fatal("the check should not write to Elasticsearch")
+	}
+}
+
+func TestBalanceChecker_CheckREWABalancesWithMissingFixtureShouldNotReport(t *testing.T) {
+	dirPath := t.TempDir()
+	esClient := &esClientStub{
+		balances: map[string]string{"addr1": "100"},
+	}
+
+	fixtureClient, err := rest.NewFixtureClient(filepath.Join(dirPath, "fixtures"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	reportPath := filepath.Join(dirPath, "report.jsonl")
+	checker := createTestBalanceChecker(t, esClient, fixtureClient, reportPath, "")
+	err = checker.CheckREWABalances()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// an account whose node balance cannot be found is logged, not reported as a mismatch
+	if len(readReport(t, reportPath)) != 0 {
+		t.Fatal("no mismatch should be reported without the node balance")
+	}
+}
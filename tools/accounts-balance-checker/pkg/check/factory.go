@@ -1,6 +1,7 @@
 package check
 
 import (
+	"fmt"
 	"math"
 	"time"
 
@@ -13,27 +14,19 @@ import (
 	"github.com/kalyan3104/k-chain-es-indexer-go/tools/accounts-balance-checker/pkg/rest"
 )
 
+const (
+	fixturesModeRecord = "record"
+	fixturesModeReplay = "replay"
+)
+
 // CreateBalanceChecker will create a new instance of balanceChecker
-func CreateBalanceChecker(cfg *config.Config, repair bool) (*balanceChecker, error) {
-	esClient, err := esclient.NewElasticClient(elasticsearch.Config{
-		Addresses: []string{cfg.Elasticsearch.URL},
-		Username:  cfg.Elasticsearch.Username,
-		Password:  cfg.Elasticsearch.Password,
-		Logger:    &logging.CustomLogger{},
-		RetryBackoff: func(i int) time.Duration {
-			// A simple exponential delay
-			d := time.Duration(math.Exp2(float64(i))) * time.Second
-			log.Info("elastic: retry backoff", "attempt", i, "sleep duration", d)
-			return d
-		},
-		MaxRetries:    5,
-		RetryOnStatus: []int{429, 502, 503, 504},
-	})
+func CreateBalanceChecker(cfg *config.Config, reporter ReportWriter, checkpoint CheckpointHandler) (*balanceChecker, error) {
+	esClient, err := createElasticClient(cfg)
 	if err != nil {
 		return nil, err
 	}
 
-	restClient, err := rest.NewRestClient(cfg.Proxy.URL)
+	restClient, err := createRestClient(cfg)
 	if err != nil {
 		return nil, err
 	}
@@ -43,10 +36,57 @@ func CreateBalanceChecker(cfg *config.Config, repair bool) (*balanceChecker, err
 		return nil, err
 	}
 
+	return NewBalanceChecker(esClient, restClient, pubKeyConverter, reporter, checkpoint, cfg.Proxy.MaxNumberOfParallelRequests)
+}
+
+// CreateReportRepairer will create a new instance of reportRepairer
+func CreateReportRepairer(cfg *config.Config) (*reportRepairer, error) {
+	esClient, err := createElasticClient(cfg)
+	if err != nil {
+		return nil, err
+	}
+
 	balanceToFloat, err := converters.NewBalanceConverter(18)
 	if err != nil {
 		return nil, err
 	}
 
-	return NewBalanceChecker(esClient, restClient, pubKeyConverter, balanceToFloat, repair, cfg.Proxy.MaxNumberOfParallelRequests)
+	return NewReportRepairer(esClient, balanceToFloat)
+}
+
+func createElasticClient(cfg *config.Config) (ESClientHandler, error) {
+	return esclient.NewElasticClient(elasticsearch.Config{
+		Addresses: []string{cfg.Elasticsearch.URL},
+		Username:  cfg.Elasticsearch.Username,
+		Password:  cfg.Elasticsearch.Password,
+		Logger:    &logging.CustomLogger{},
+		RetryBackoff: func(i int) time.Duration {
+			// A simple exponential delay
+			d := time.Duration(math.Exp2(float64(i))) * time.Second
+			log.Info("elastic: retry backoff", "attempt", i, "sleep duration", d)
+			return d
+		},
+		MaxRetries:    5,
+		RetryOnStatus: []int{429, 502, 503, 504},
+	})
+}
+
+// createRestClient creates the client for the gateway, which can also record its responses, or a client which
+// replays the recorded responses without a gateway
+func createRestClient(cfg *config.Config) (RestClientHandler, error) {
+	switch cfg.Proxy.FixturesMode {
+	case "":
+		return rest.NewRestClient(cfg.Proxy.URL)
+	case fixturesModeReplay:
+		return rest.NewFixtureClient(cfg.Proxy.FixturesPath)
+	case fixturesModeRecord:
+		restClient, err := rest.NewRestClient(cfg.Proxy.URL)
+		if err != nil {
+			return nil, err
+		}
+
+		return rest.NewRecordingClient(restClient, cfg.Proxy.FixturesPath)
+	default:
+		return nil, fmt.Errorf("unknown fixtures mode %s", cfg.Proxy.FixturesMode)
+	}
 }
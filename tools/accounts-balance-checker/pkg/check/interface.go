@@ -1,6 +1,10 @@
 package check
 
-import "bytes"
+import (
+	"bytes"
+
+	"github.com/kalyan3104/k-chain-es-indexer-go/tools/accounts-balance-checker/pkg/report"
+)
 
 // ESClientHandler -
 type ESClientHandler interface {
@@ -20,3 +24,16 @@ type RestClientHandler interface {
 		value interface{},
 	) error
 }
+
+// ReportWriter defines what a component that saves the balance mismatches should do
+type ReportWriter interface {
+	WriteRecord(record *report.Record) error
+	IsInterfaceNil() bool
+}
+
+// CheckpointHandler defines what a component that remembers the last checked account should do
+type CheckpointHandler interface {
+	LastAddress(checkName string) string
+	SaveLastAddress(checkName string, address string) error
+	IsInterfaceNil() bool
+}
@@ -8,8 +8,6 @@ import (
 
 type object = map[string]interface{}
 
-const matchAllQuery = `{ "query": { "match_all": { } } }`
-
 func encodeQuery(query object) (bytes.Buffer, error) {
 	var buff bytes.Buffer
 	if err := json.NewEncoder(&buff).Encode(query); err != nil {
@@ -19,6 +17,36 @@ func encodeQuery(query object) (bytes.Buffer, error) {
 	return buff, nil
 }
 
+// getAccountsAfterAddressQuery returns the accounts sorted by address, the ones until the provided address included
+// being skipped
+func getAccountsAfterAddressQuery(lastAddress string) []byte {
+	query := object{
+		"query": object{
+			"match_all": object{},
+		},
+		"sort": []interface{}{
+			object{
+				"address": object{
+					"order": "asc",
+				},
+			},
+		},
+	}
+	if lastAddress != "" {
+		query["query"] = object{
+			"range": object{
+				"address": object{
+					"gt": lastAddress,
+				},
+			},
+		}
+	}
+
+	queryBytes, _ := json.Marshal(query)
+
+	return queryBytes
+}
+
 func getDocumentsByIDsQuery(hashes []string, withSource bool) object {
 	interfaceSlice := make([]string, 0, len(hashes))
 	for idx := range hashes {
@@ -0,0 +1,53 @@
+package check
+
+import (
+	"strings"
+
+	"github.com/kalyan3104/k-chain-core-go/core"
+	"github.com/kalyan3104/k-chain-es-indexer-go/tools/accounts-balance-checker/pkg/report"
+)
+
+// newRecord creates the report record of a mismatch, the type of the balance being found from the token identifier
+func (bc *balanceChecker) newRecord(address string, tokenIdentifier string, mismatch string, timestamp uint64) *report.Record {
+	record := &report.Record{
+		Type:            report.TypeREWA,
+		Mismatch:        mismatch,
+		Address:         address,
+		TokenIdentifier: tokenIdentifier,
+		SCOwned:         bc.isSmartContract(address),
+		Index:           accountsIndex,
+		Timestamp:       timestamp,
+	}
+	if tokenIdentifier == "" {
+		return record
+	}
+
+	record.Index = accountsdcdtIndex
+	record.Type = report.TypeDCDT
+	if isNFTIdentifier(tokenIdentifier) {
+		record.Type = report.TypeNFT
+	}
+
+	return record
+}
+
+func (bc *balanceChecker) writeRecord(record *report.Record) {
+	err := bc.reporter.WriteRecord(record)
+	if err != nil {
+		log.Error("cannot write report record", "address", record.Address, "token identifier", record.TokenIdentifier, "error", err)
+	}
+}
+
+func (bc *balanceChecker) isSmartContract(address string) bool {
+	decoded, err := bc.pubKeyConverter.Decode(address)
+	if err != nil {
+		return false
+	}
+
+	return core.IsSmartContractAddress(decoded)
+}
+
+// isNFTIdentifier returns true for the identifiers which hold a nonce, like TOKEN-abcdef-01
+func isNFTIdentifier(tokenIdentifier string) bool {
+	return len(strings.Split(tokenIdentifier, "-")) == 3
+}
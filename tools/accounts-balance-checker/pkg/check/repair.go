@@ -1,18 +1,65 @@
 package check
 
 import (
+	"errors"
 	"fmt"
 	"math/big"
 	"strings"
 
+	"github.com/kalyan3104/k-chain-core-go/core/check"
 	"github.com/kalyan3104/k-chain-es-indexer-go/data"
+	indexer "github.com/kalyan3104/k-chain-es-indexer-go/process/dataindexer"
+	"github.com/kalyan3104/k-chain-es-indexer-go/tools/accounts-balance-checker/pkg/report"
 )
 
-func (bc *balanceChecker) deleteExtraBalance(addr, identifier string, timestamp uint64, index string) error {
-	if !bc.doRepair {
-		return nil
+type reportRepairer struct {
+	esClient       ESClientHandler
+	balanceToFloat indexer.BalanceConverter
+}
+
+// NewReportRepairer will create a component that repairs the balances from Elasticsearch with the values from the
+// node saved in a report by a previous check
+func NewReportRepairer(esClient ESClientHandler, balanceToFloat indexer.BalanceConverter) (*reportRepairer, error) {
+	if check.IfNilReflect(esClient) {
+		return nil, errors.New("nil elastic client")
 	}
+	if check.IfNilReflect(balanceToFloat) {
+		return nil, errors.New("nil balance converter")
+	}
+
+	return &reportRepairer{
+		esClient:       esClient,
+		balanceToFloat: balanceToFloat,
+	}, nil
+}
+
+// RepairFromReport will fix the different balances and delete the extra balances from the report file. A balance is
+// changed only if it was not updated after the operation found by the check, so the newer balances are not overwritten.
+// The balances missing from Elasticsearch cannot be repaired, the indexer being the one that writes the whole documents
+func (rr *reportRepairer) RepairFromReport(filePath string) error {
+	numRepaired, numSkipped := 0, 0
+	err := report.ReadRecords(filePath, func(record *report.Record) error {
+		switch record.Mismatch {
+		case report.MismatchDifferent:
+			numRepaired++
+			return rr.fixWrongBalance(record.Address, record.TokenIdentifier, record.Timestamp, record.BalanceNode, record.Index)
+		case report.MismatchExtraInES:
+			numRepaired++
+			return rr.deleteExtraBalance(record.Address, record.TokenIdentifier, record.Timestamp, record.Index)
+		default:
+			numSkipped++
+			log.Warn("cannot repair balance", "mismatch", record.Mismatch,
+				"address", record.Address, "token identifier", record.TokenIdentifier)
+			return nil
+		}
+	})
+
+	log.Info("repair from report", "num repaired", numRepaired, "num skipped", numSkipped)
+
+	return err
+}
 
+func (rr *reportRepairer) deleteExtraBalance(addr, identifier string, timestamp uint64, index string) error {
 	id := prepareID(addr, identifier)
 	meta := []byte(fmt.Sprintf(`{ "update" : {"_index":"%s", "_id" : "%s" } }%s`, index, id, "\n"))
 	serializedDataStr := fmt.Sprintf(`{"scripted_upsert": true, "script": {`+
@@ -33,7 +80,7 @@ func (bc *balanceChecker) deleteExtraBalance(addr, identifier string, timestamp
 	buffSlice := data.NewBufferSlice(0)
 	_ = buffSlice.PutData(meta, []byte(serializedDataStr))
 
-	err := bc.esClient.DoBulkRequest(buffSlice.Buffers()[0], index)
+	err := rr.esClient.DoBulkRequest(buffSlice.Buffers()[0], index)
 	if err != nil {
 		return err
 	}
@@ -43,15 +90,15 @@ func (bc *balanceChecker) deleteExtraBalance(addr, identifier string, timestamp
 	return nil
 }
 
-func (bc *balanceChecker) fixWrongBalance(addr, identifier string, timestamp uint64, balanceFromProxy string, index string) error {
-	if !bc.doRepair {
-		return nil
+func (rr *reportRepairer) fixWrongBalance(addr, identifier string, timestamp uint64, balanceFromProxy string, index string) error {
+	balanceBig, ok := big.NewInt(0).SetString(balanceFromProxy, 10)
+	if !ok {
+		return fmt.Errorf("invalid balance %s for address %s", balanceFromProxy, addr)
 	}
 
-	balanceBig, _ := big.NewInt(0).SetString(balanceFromProxy, 10)
-	balanceFloat := bc.balanceToFloat.ComputeDCDTBalanceAsFloat(balanceBig)
+	balanceFloat := rr.balanceToFloat.ComputeDCDTBalanceAsFloat(balanceBig)
 	if identifier == "" {
-		balanceFloat = bc.balanceToFloat.ComputeBalanceAsFloat(balanceBig)
+		balanceFloat = rr.balanceToFloat.ComputeBalanceAsFloat(balanceBig)
 	}
 
 	id := prepareID(addr, identifier)
@@ -67,7 +114,7 @@ func (bc *balanceChecker) fixWrongBalance(addr, identifier string, timestamp uin
 	buffSlice := data.NewBufferSlice(0)
 	_ = buffSlice.PutData(meta, []byte(serializedDataStr))
 
-	err := bc.esClient.DoBulkRequest(buffSlice.Buffers()[0], index)
+	err := rr.esClient.DoBulkRequest(buffSlice.Buffers()[0], index)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,101 @@
+package checkpoint
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"sync"
+)
+
+const checkpointFilePermissions = 0644
+
+// Checkpoint holds, for every check, the last account whose balances were compared
+type Checkpoint struct {
+	LastAddress map[string]string `json:"lastAddress"`
+}
+
+type checkpointHandler struct {
+	mut        sync.Mutex
+	filePath   string
+	checkpoint *Checkpoint
+}
+
+// NewCheckpointHandler creates a component that remembers the last account checked, so an interrupted check can resume
+// from the next account. The accounts are checked in the order of their addresses. With an empty file path the
+// progress is kept only in memory
+func NewCheckpointHandler(filePath string) (*checkpointHandler, error) {
+	ch := &checkpointHandler{
+		filePath: filePath,
+		checkpoint: &Checkpoint{
+			LastAddress: make(map[string]string),
+		},
+	}
+	if filePath == "" {
+		return ch, nil
+	}
+
+	checkpointBytes, err := ioutil.ReadFile(filePath)
+	if errors.Is(err, os.ErrNotExist) {
+		return ch, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	err = json.Unmarshal(checkpointBytes, ch.checkpoint)
+	if err != nil {
+		return nil, err
+	}
+	if ch.checkpoint.LastAddress == nil {
+		ch.checkpoint.LastAddress = make(map[string]string)
+	}
+
+	return ch, nil
+}
+
+// LastAddress returns the last account checked by the provided check, or an empty string if the check did not start
+func (ch *checkpointHandler) LastAddress(checkName string) string {
+	ch.mut.Lock()
+	defer ch.mut.Unlock()
+
+	return ch.checkpoint.LastAddress[checkName]
+}
+
+// HasProgress returns true if any check already compared accounts
+func (ch *checkpointHandler) HasProgress() bool {
+	ch.mut.Lock()
+	defer ch.mut.Unlock()
+
+	return len(ch.checkpoint.LastAddress) > 0
+}
+
+// SaveLastAddress will remember the last account checked by the provided check and save the checkpoint
+func (ch *checkpointHandler) SaveLastAddress(checkName string, address string) error {
+	ch.mut.Lock()
+	defer ch.mut.Unlock()
+
+	ch.checkpoint.LastAddress[checkName] = address
+	if ch.filePath == "" {
+		return nil
+	}
+
+	checkpointBytes, err := json.Marshal(ch.checkpoint)
+	if err != nil {
+		return err
+	}
+
+	// the checkpoint replaces the old one only after it is fully written
+	tmpFilePath := ch.filePath + ".tmp"
+	err = ioutil.WriteFile(tmpFilePath, checkpointBytes, checkpointFilePermissions)
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(tmpFilePath, ch.filePath)
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (ch *checkpointHandler) IsInterfaceNil() bool {
+	return ch == nil
+}
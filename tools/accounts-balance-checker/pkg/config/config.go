@@ -9,5 +9,15 @@ type Config struct {
 	Proxy struct {
 		URL                         string `json:"url"`
 		MaxNumberOfParallelRequests int    `json:"parallel-requests"`
+		// FixturesMode is "record" to save the responses of the gateway, "replay" to use the saved responses instead of
+		// the gateway, or empty
+		FixturesMode string `json:"fixtures-mode"`
+		FixturesPath string `json:"fixtures-path"`
 	} `json:"proxy"`
+	Report struct {
+		FilePath string `json:"file-path"`
+	} `json:"report"`
+	Checkpoint struct {
+		FilePath string `json:"file-path"`
+	} `json:"checkpoint"`
 }
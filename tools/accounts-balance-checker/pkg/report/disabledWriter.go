@@ -0,0 +1,28 @@
+package report
+
+type disabledWriter struct{}
+
+// NewDisabledWriter creates a writer that drops the records, used when no report was configured
+func NewDisabledWriter() *disabledWriter {
+	return &disabledWriter{}
+}
+
+// WriteRecord does nothing
+func (dw *disabledWriter) WriteRecord(_ *Record) error {
+	return nil
+}
+
+// NumRecords returns 0
+func (dw *disabledWriter) NumRecords() uint64 {
+	return 0
+}
+
+// Close does nothing
+func (dw *disabledWriter) Close() error {
+	return nil
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (dw *disabledWriter) IsInterfaceNil() bool {
+	return dw == nil
+}
@@ -0,0 +1,6 @@
+package report
+
+import "errors"
+
+// ErrEmptyReportFilePath signals that an empty report file path was provided
+var ErrEmptyReportFilePath = errors.New("empty report file path")
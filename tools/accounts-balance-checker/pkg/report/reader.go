@@ -0,0 +1,49 @@
+package report
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+const maxRecordSize = 1024 * 1024
+
+// ReadRecords will call the handler for every record from the report file, in the order in which they were written
+func ReadRecords(filePath string, handler func(record *Record) error) error {
+	if filePath == "" {
+		return ErrEmptyReportFilePath
+	}
+
+	file, err := os.Open(filePath)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxRecordSize)
+
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+
+		record := &Record{}
+		err = json.Unmarshal(scanner.Bytes(), record)
+		if err != nil {
+			return fmt.Errorf("%w on line %d of the report", err, lineNumber)
+		}
+
+		err = handler(record)
+		if err != nil {
+			return err
+		}
+	}
+
+	return scanner.Err()
+}
@@ -0,0 +1,31 @@
+package report
+
+const (
+	// TypeREWA is the type of the records for the REWA balances
+	TypeREWA = "rewa"
+	// TypeDCDT is the type of the records for the fungible DCDT balances
+	TypeDCDT = "dcdt"
+	// TypeNFT is the type of the records for the NFT, SFT and meta DCDT balances
+	TypeNFT = "nft"
+
+	// MismatchDifferent is the mismatch of a balance which is not the same in Elasticsearch and on the node
+	MismatchDifferent = "different"
+	// MismatchExtraInES is the mismatch of a balance found in Elasticsearch, but not on the node
+	MismatchExtraInES = "extra-in-es"
+	// MismatchMissingFromES is the mismatch of a balance found on the node, but not in Elasticsearch
+	MismatchMissingFromES = "missing-from-es"
+)
+
+// Record holds a balance which is not the same in Elasticsearch and on the node
+type Record struct {
+	Type            string `json:"type"`
+	Mismatch        string `json:"mismatch"`
+	Address         string `json:"address"`
+	TokenIdentifier string `json:"tokenIdentifier,omitempty"`
+	SCOwned         bool   `json:"scOwned"`
+	Index           string `json:"index"`
+	BalanceES       string `json:"balanceES,omitempty"`
+	BalanceNode     string `json:"balanceNode,omitempty"`
+	// Timestamp is the timestamp of the last operation which changed the balance, found when the check was made
+	Timestamp uint64 `json:"timestamp"`
+}
@@ -0,0 +1,81 @@
+package report
+
+import (
+	"encoding/json"
+	"os"
+	"sync"
+)
+
+const reportFilePermissions = 0644
+
+// ArgsReportWriter holds the arguments needed to create a report writer
+type ArgsReportWriter struct {
+	FilePath string
+	// Append keeps the records of a previous run, used when a check is resumed
+	Append bool
+}
+
+type reportWriter struct {
+	mut        sync.Mutex
+	file       *os.File
+	numRecords uint64
+}
+
+// NewReportWriter creates a writer that saves every record as a JSON object on a separate line. The writer is
+// concurrent safe
+func NewReportWriter(args ArgsReportWriter) (*reportWriter, error) {
+	if args.FilePath == "" {
+		return nil, ErrEmptyReportFilePath
+	}
+
+	flags := os.O_CREATE | os.O_TRUNC | os.O_WRONLY
+	if args.Append {
+		flags = os.O_CREATE | os.O_APPEND | os.O_WRONLY
+	}
+
+	file, err := os.OpenFile(args.FilePath, flags, reportFilePermissions)
+	if err != nil {
+		return nil, err
+	}
+
+	return &reportWriter{
+		file: file,
+	}, nil
+}
+
+// WriteRecord will append the provided record to the report file
+func (rw *reportWriter) WriteRecord(record *Record) error {
+	recordBytes, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+
+	rw.mut.Lock()
+	defer rw.mut.Unlock()
+
+	rw.numRecords++
+	_, err = rw.file.Write(append(recordBytes, '\n'))
+
+	return err
+}
+
+// NumRecords returns the number of records written so far
+func (rw *reportWriter) NumRecords() uint64 {
+	rw.mut.Lock()
+	defer rw.mut.Unlock()
+
+	return rw.numRecords
+}
+
+// Close will close the report file
+func (rw *reportWriter) Close() error {
+	rw.mut.Lock()
+	defer rw.mut.Unlock()
+
+	return rw.file.Close()
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (rw *reportWriter) IsInterfaceNil() bool {
+	return rw == nil
+}
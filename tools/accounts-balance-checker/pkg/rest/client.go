@@ -46,24 +46,9 @@ func (rc *restClient) CallGetRestEndPoint(
 	req.Header.Set("Accept", "application/json")
 	req.Header.Set("User-Agent", userAgent)
 
-	var count = 0
-TryAgain:
-	resp, err := rc.httpClient.Do(req)
-	if err != nil && count < maxNumOfRetries {
-		log.Warn("rc.httpClient.Do", "error", err)
-		count++
-		sleep(count)
-		goto TryAgain
-	}
+	resp, err := rc.doRequestWithRetries(req)
 	if err != nil {
-		return fmt.Errorf("too many retries, error: %w", err)
-	}
-
-	if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusRequestTimeout {
-		_ = resp.Body.Close()
-		count++
-		sleep(count)
-		goto TryAgain
+		return err
 	}
 
 	defer func() {
@@ -81,6 +66,34 @@ TryAgain:
 	return nil
 }
 
+// doRequestWithRetries retries the request when it fails or when the gateway asks for it, with an exponential delay
+func (rc *restClient) doRequestWithRetries(req *http.Request) (*http.Response, error) {
+	for count := 1; ; count++ {
+		resp, err := rc.httpClient.Do(req)
+		if err != nil {
+			if count > maxNumOfRetries {
+				return nil, fmt.Errorf("too many retries, error: %w", err)
+			}
+
+			log.Warn("rc.httpClient.Do", "error", err)
+			sleep(count)
+			continue
+		}
+
+		shouldRetry := resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusRequestTimeout
+		if !shouldRetry {
+			return resp, nil
+		}
+
+		_ = resp.Body.Close()
+		if count > maxNumOfRetries {
+			return nil, fmt.Errorf("too many retries, status code: %d", resp.StatusCode)
+		}
+
+		sleep(count)
+	}
+}
+
 func sleep(count int) {
 	delay := time.Duration(math.Exp2(float64(count))) * time.Second
 	time.Sleep(delay)
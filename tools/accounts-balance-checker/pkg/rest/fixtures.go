@@ -0,0 +1,99 @@
+package rest
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const fixtureFilePermissions = 0644
+
+// ErrFixtureNotFound signals that no response was recorded for the requested endpoint
+var ErrFixtureNotFound = errors.New("fixture not found")
+
+type getClient interface {
+	CallGetRestEndPoint(path string, value interface{}) error
+}
+
+type fixtureClient struct {
+	dirPath string
+}
+
+// NewFixtureClient creates a client that answers with the responses recorded in the provided directory, so the checker
+// can run without a gateway
+func NewFixtureClient(dirPath string) (*fixtureClient, error) {
+	if dirPath == "" {
+		return nil, errors.New("empty fixtures path")
+	}
+
+	return &fixtureClient{
+		dirPath: dirPath,
+	}, nil
+}
+
+// CallGetRestEndPoint reads the response recorded for the endpoint
+func (fc *fixtureClient) CallGetRestEndPoint(pathEndpoint string, value interface{}) error {
+	responseBytes, err := ioutil.ReadFile(fixtureFilePath(fc.dirPath, pathEndpoint))
+	if errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("%w for endpoint %s", ErrFixtureNotFound, pathEndpoint)
+	}
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(responseBytes, value)
+}
+
+type recordingClient struct {
+	client  getClient
+	dirPath string
+}
+
+// NewRecordingClient creates a client that saves in the provided directory every response of the wrapped client, to
+// be replayed later by a fixture client
+func NewRecordingClient(client getClient, dirPath string) (*recordingClient, error) {
+	if client == nil {
+		return nil, errors.New("nil rest client")
+	}
+	if dirPath == "" {
+		return nil, errors.New("empty fixtures path")
+	}
+
+	err := os.MkdirAll(dirPath, os.ModePerm)
+	if err != nil {
+		return nil, err
+	}
+
+	return &recordingClient{
+		client:  client,
+		dirPath: dirPath,
+	}, nil
+}
+
+// CallGetRestEndPoint calls the wrapped client and records the response
+func (rc *recordingClient) CallGetRestEndPoint(pathEndpoint string, value interface{}) error {
+	response := json.RawMessage{}
+	err := rc.client.CallGetRestEndPoint(pathEndpoint, &response)
+	if err != nil {
+		return err
+	}
+
+	err = ioutil.WriteFile(fixtureFilePath(rc.dirPath, pathEndpoint), response, fixtureFilePermissions)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(response, value)
+}
+
+// fixtureFilePath returns the file of an endpoint, the path separators being replaced so every endpoint has a file in
+// the same directory
+func fixtureFilePath(dirPath string, pathEndpoint string) string {
+	fileName := strings.ReplaceAll(strings.Trim(pathEndpoint, "/"), "/", "_")
+
+	return filepath.Join(dirPath, fileName+".json")
+}
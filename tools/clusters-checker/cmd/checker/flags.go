@@ -30,6 +30,15 @@ var (
 		Name:  "check-only-ids",
 		Usage: "If set, the checker will verify only the ids",
 	}
+	repairFlag = cli.BoolFlag{
+		Name: "repair",
+		Usage: "If set, the checker will also copy the missing and the different documents in the cluster chosen by the " +
+			"repair direction from the config file",
+	}
+	dryRun = cli.BoolFlag{
+		Name:  "dry-run",
+		Usage: "If set together with the repair flag, the checker will only log the documents it would copy or delete",
+	}
 	logLevel = cli.StringFlag{
 		Name: "log-level",
 		Usage: "This flag specifies the logger `level(s)`. It can contain multiple comma-separated value. For example" +
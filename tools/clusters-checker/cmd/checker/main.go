@@ -5,13 +5,15 @@ import (
 	"io/ioutil"
 	"os"
 	"path"
-	"sync"
 	"time"
 
 	"github.com/kalyan3104/k-chain-core-go/core/check"
 	"github.com/kalyan3104/k-chain-core-go/core/closing"
 	"github.com/kalyan3104/k-chain-es-indexer-go/tools/clusters-checker/pkg/checkers"
+	"github.com/kalyan3104/k-chain-es-indexer-go/tools/clusters-checker/pkg/checkpoint"
 	"github.com/kalyan3104/k-chain-es-indexer-go/tools/clusters-checker/pkg/config"
+	"github.com/kalyan3104/k-chain-es-indexer-go/tools/clusters-checker/pkg/repair"
+	"github.com/kalyan3104/k-chain-es-indexer-go/tools/clusters-checker/pkg/report"
 	logger "github.com/kalyan3104/k-chain-logger-go"
 	"github.com/kalyan3104/k-chain-logger-go/file"
 	"github.com/pelletier/go-toml"
@@ -48,6 +50,8 @@ func main() {
 		checkNoTimestamp,
 		checkWithTimestamp,
 		checkOnlyIds,
+		repairFlag,
+		dryRun,
 		logLevel,
 		logSaveFile,
 		enableAnsiColor,
@@ -86,9 +90,44 @@ func checkClusters(ctx *cli.Context) {
 	}
 
 	checkOnlyIDs := ctx.Bool(checkOnlyIds.Name)
+	repairEnabled := ctx.Bool(repairFlag.Name)
+	if repairEnabled && checkOnlyIDs {
+		log.Error("the repair flag cannot be used together with the check only ids flag, the documents are needed to repair them")
+		return
+	}
+
+	checkWithTimestampFlag := ctx.Bool(checkWithTimestamp.Name)
+	intervalsCheckpoint, err := createCheckpointHandler(cfg, checkWithTimestampFlag)
+	if err != nil {
+		log.Error("cannot create checkpoint handler", "error", err.Error())
+		return
+	}
+
+	reporter, err := createReportWriter(cfg, intervalsCheckpoint.HasProgress())
+	if err != nil {
+		log.Error("cannot create report writer", "error", err.Error())
+		return
+	}
+	defer closeReportWriter(reporter)
+
+	repairer, err := createRepairer(cfg, repairEnabled, ctx.Bool(dryRun.Name))
+	if err != nil {
+		log.Error("cannot create repairer", "error", err.Error())
+		return
+	}
+	defer logRepairResult(repairer, repairEnabled)
+
+	args := checkers.ArgsClusterChecker{
+		Config:     cfg,
+		OnlyIDs:    checkOnlyIDs,
+		Reporter:   reporter,
+		Repairer:   repairer,
+		Checkpoint: intervalsCheckpoint,
+	}
+
 	checkCountsFlag := ctx.Bool(checkCounts.Name)
 	if checkCountsFlag {
-		clusterChecker, errC := checkers.CreateClusterChecker(cfg, &checkers.Interval{}, "instance_0", checkOnlyIDs)
+		clusterChecker, errC := checkers.CreateClusterChecker(args, "instance_0")
 		if errC != nil {
 			log.Error("cannot create cluster checker", "error", errC.Error())
 			return
@@ -105,7 +144,7 @@ func checkClusters(ctx *cli.Context) {
 
 	checkIndicesNoTimestampFlag := ctx.Bool(checkNoTimestamp.Name)
 	if checkIndicesNoTimestampFlag {
-		clusterChecker, errC := checkers.CreateClusterChecker(cfg, &checkers.Interval{}, "instance_0", checkOnlyIDs)
+		clusterChecker, errC := checkers.CreateClusterChecker(args, "instance_0")
 		if errC != nil {
 			log.Error("cannot create cluster checker", "error", errC.Error())
 			return
@@ -120,9 +159,8 @@ func checkClusters(ctx *cli.Context) {
 		return
 	}
 
-	checkWithTimestampFlag := ctx.Bool(checkWithTimestamp.Name)
 	if checkWithTimestampFlag {
-		checkClustersIndexesWithInterval(cfg, checkOnlyIDs)
+		checkClustersIndexesWithInterval(args)
 		return
 	}
 
@@ -133,26 +171,73 @@ func checkClusters(ctx *cli.Context) {
 	log.Error("no flag has been provided")
 }
 
-func checkClustersIndexesWithInterval(cfg *config.Config, checkOnlyIDs bool) {
-	wg := sync.WaitGroup{}
-	ccs, err := checkers.CreateMultipleCheckers(cfg, checkOnlyIDs)
+func checkClustersIndexesWithInterval(args checkers.ArgsClusterChecker) {
+	comparer, err := checkers.CreateSlicedComparer(args)
 	if err != nil {
 		log.Error("cannot create cluster checker", "error", err.Error())
+		return
+	}
+
+	err = comparer.CompareIndicesWithTimestamp()
+	if err != nil {
+		log.Error("cannot check indices", "error", err.Error())
 	}
+}
+
+type reportWriterHandler interface {
+	checkers.ReportWriter
+	NumRecords() uint64
+	Close() error
+}
 
-	wg.Add(len(ccs))
-	for _, c := range ccs {
-		go func(che checkers.Checker) {
-			errC := che.CompareIndicesWithTimestamp()
-			if errC != nil {
-				log.Error("cannot check indices", "error", errC.Error())
-			}
-			wg.Done()
-		}(c)
+func createReportWriter(cfg *config.Config, appendRecords bool) (reportWriterHandler, error) {
+	if cfg.Report.FilePath == "" {
+		return report.NewDisabledWriter(), nil
 	}
 
-	wg.Wait()
+	return report.NewReportWriter(report.ArgsReportWriter{
+		FilePath: cfg.Report.FilePath,
+		Formats:  cfg.Report.Formats,
+		Append:   appendRecords,
+	})
+}
+
+func closeReportWriter(reporter reportWriterHandler) {
+	log.Info("report", "num records", reporter.NumRecords())
+	log.LogIfError(reporter.Close())
+}
+
+type checkpointHandler interface {
+	checkers.CheckpointHandler
+	HasProgress() bool
+}
+
+// createCheckpointHandler will load the saved checkpoint only for the comparison of the indices with timestamp, the
+// only one split in slices
+func createCheckpointHandler(cfg *config.Config, withTimestamp bool) (checkpointHandler, error) {
+	filePath := ""
+	if withTimestamp {
+		filePath = cfg.Compare.CheckpointFile
+	}
+
+	return checkpoint.NewCheckpointHandler(filePath, cfg.Compare.BlockchainStartTime, time.Now().Unix(), cfg.Compare.NumSlices)
+}
+
+func createRepairer(cfg *config.Config, repairEnabled bool, dryRunEnabled bool) (checkers.Repairer, error) {
+	if !repairEnabled {
+		return repair.NewDisabledRepairer(), nil
+	}
+
+	return checkers.CreateRepairer(cfg, dryRunEnabled)
+}
+
+func logRepairResult(repairer checkers.Repairer, repairEnabled bool) {
+	if !repairEnabled {
+		return
+	}
 
+	log.LogIfError(repairer.Flush())
+	log.Info("repair", "num copied", repairer.NumCopied(), "num deleted", repairer.NumDeleted())
 }
 
 func loadConfigFile(pathStr string) (*config.Config, error) {
@@ -3,16 +3,19 @@ package checkers
 import "encoding/json"
 
 type generalElasticResponse struct {
-	Hits struct {
+	PitID string `json:"pit_id"`
+	Hits  struct {
 		Hits []struct {
 			ID     string          `json:"_id"`
 			Source json.RawMessage `json:"_source"`
+			Sort   json.RawMessage `json:"sort"`
 		} `json:"hits"`
 	} `json:"hits"`
 }
 
 // Interval defines the structure for a search interval
 type Interval struct {
+	idx   int
 	start int64
 	stop  int64
 }
@@ -5,33 +5,38 @@ import (
 	"errors"
 	"fmt"
 	"strconv"
-	"time"
 
 	"github.com/elastic/go-elasticsearch/v7"
+	"github.com/kalyan3104/k-chain-core-go/core/check"
 	"github.com/kalyan3104/k-chain-es-indexer-go/tools/clusters-checker/pkg/client"
 	"github.com/kalyan3104/k-chain-es-indexer-go/tools/clusters-checker/pkg/config"
+	"github.com/kalyan3104/k-chain-es-indexer-go/tools/clusters-checker/pkg/repair"
 )
 
+// ArgsClusterChecker holds the arguments needed to create a cluster checker
+type ArgsClusterChecker struct {
+	Config     *config.Config
+	OnlyIDs    bool
+	Reporter   ReportWriter
+	Repairer   Repairer
+	Checkpoint CheckpointHandler
+}
+
 // CreateClusterChecker will create a new instance of clusterChecker structure
-func CreateClusterChecker(cfg *config.Config, interval *Interval, logPrefix string, onlyIDs bool) (*clusterChecker, error) {
-	clientSource, err := client.NewElasticClient(elasticsearch.Config{
-		Addresses: []string{cfg.SourceCluster.URL},
-		Username:  cfg.SourceCluster.User,
-		Password:  cfg.SourceCluster.Password,
-	})
-	if err != nil {
-		return nil, fmt.Errorf("cannot create source client %s", err.Error())
+func CreateClusterChecker(args ArgsClusterChecker, logPrefix string) (*clusterChecker, error) {
+	if check.IfNil(args.Reporter) {
+		return nil, errors.New("nil report writer")
+	}
+	if check.IfNil(args.Repairer) {
+		return nil, errors.New("nil repairer")
 	}
 
-	clientDestination, err := client.NewElasticClient(elasticsearch.Config{
-		Addresses: []string{cfg.DestinationCluster.URL},
-		Username:  cfg.DestinationCluster.User,
-		Password:  cfg.DestinationCluster.Password,
-	})
+	clientSource, clientDestination, err := createClients(args.Config)
 	if err != nil {
-		return nil, fmt.Errorf("cannot create destination client %s", err.Error())
+		return nil, err
 	}
 
+	cfg := args.Config
 	return &clusterChecker{
 		clientSource:         clientSource,
 		clientDestination:    clientDestination,
@@ -41,34 +46,104 @@ func CreateClusterChecker(cfg *config.Config, interval *Interval, logPrefix stri
 		missingFromSource:      map[string]json.RawMessage{},
 		missingFromDestination: map[string]json.RawMessage{},
 
-		startTimestamp: int(interval.start),
-		stopTimestamp:  int(interval.stop),
-		logPrefix:      logPrefix,
-		onlyIDs:        onlyIDs,
+		logPrefix:     logPrefix,
+		onlyIDs:       args.OnlyIDs,
+		ignoredFields: cfg.Compare.IgnoredFields,
+		reporter:      args.Reporter,
+		repairer:      args.Repairer,
 	}, nil
 }
 
-// CreateMultipleCheckers will create multiple instances of clusterChecker structure
-func CreateMultipleCheckers(cfg *config.Config, onlyIDs bool) ([]*clusterChecker, error) {
-	currentTimestampUnix := time.Now().Unix()
-	intervals, err := computeIntervals(cfg.Compare.BlockchainStartTime, currentTimestampUnix, int64(cfg.Compare.NumParallelReads))
+// CreateSlicedComparer will create the component that compares the indices with timestamp in slices, with a pool of
+// cluster checkers which share the same repairer. The records of a slice are written in the report once the slice is
+// compared. The last slice ends at the end timestamp of the checkpoint, so a resumed comparison uses the same slices as
+// the interrupted one
+func CreateSlicedComparer(args ArgsClusterChecker) (*slicedComparer, error) {
+	if check.IfNil(args.Checkpoint) {
+		return nil, errors.New("nil checkpoint handler")
+	}
+	if check.IfNil(args.Reporter) {
+		return nil, errors.New("nil report writer")
+	}
+	if check.IfNil(args.Repairer) {
+		return nil, errors.New("nil repairer")
+	}
+
+	cfg := args.Config
+	// the slices do not include their stop timestamp, so the last one ends right after the end timestamp
+	intervals, err := computeIntervals(cfg.Compare.BlockchainStartTime, args.Checkpoint.EndTimestamp()+1, int64(cfg.Compare.NumSlices))
 	if err != nil {
 		return nil, err
 	}
 
-	checkers := make([]*clusterChecker, 0, cfg.Compare.NumParallelReads)
+	numWorkers := cfg.Compare.NumParallelReads
+	if numWorkers < 1 {
+		numWorkers = 1
+	}
+
+	workers := make([]*sliceWorker, 0, numWorkers)
+	for idx := 0; idx < numWorkers; idx++ {
+		records := &sliceRecords{}
+		workerArgs := args
+		workerArgs.Reporter = records
 
-	for idx := 0; idx < cfg.Compare.NumParallelReads; idx++ {
 		logPrefix := "instance_" + strconv.FormatUint(uint64(idx), 10)
-		cc, errC := CreateClusterChecker(cfg, intervals[idx], logPrefix, onlyIDs)
+		cc, errC := CreateClusterChecker(workerArgs, logPrefix)
 		if errC != nil {
 			return nil, errC
 		}
 
-		checkers = append(checkers, cc)
+		workers = append(workers, &sliceWorker{checker: cc, records: records})
+	}
+
+	return &slicedComparer{
+		workers:    workers,
+		indices:    cfg.Compare.IndicesWithTimestamp,
+		intervals:  intervals,
+		reporter:   args.Reporter,
+		repairer:   args.Repairer,
+		checkpoint: args.Checkpoint,
+	}, nil
+}
+
+// CreateRepairer will create the component that writes the repaired documents in the cluster chosen by the direction
+func CreateRepairer(cfg *config.Config, dryRun bool) (Repairer, error) {
+	clientSource, clientDestination, err := createClients(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return repair.NewRepairer(repair.ArgsRepairer{
+		SourceClient:          clientSource,
+		DestinationClient:     clientDestination,
+		Direction:             cfg.Repair.Direction,
+		DeleteExtraDocuments:  cfg.Repair.DeleteExtraDocuments,
+		DryRun:                dryRun,
+		BulkSize:              cfg.Repair.BulkSize,
+		MaxDocumentsPerSecond: cfg.Repair.MaxDocumentsPerSecond,
+	})
+}
+
+func createClients(cfg *config.Config) (ESClient, ESClient, error) {
+	clientSource, err := client.NewElasticClient(elasticsearch.Config{
+		Addresses: []string{cfg.SourceCluster.URL},
+		Username:  cfg.SourceCluster.User,
+		Password:  cfg.SourceCluster.Password,
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("cannot create source client %s", err.Error())
+	}
+
+	clientDestination, err := client.NewElasticClient(elasticsearch.Config{
+		Addresses: []string{cfg.DestinationCluster.URL},
+		Username:  cfg.DestinationCluster.User,
+		Password:  cfg.DestinationCluster.Password,
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("cannot create destination client %s", err.Error())
 	}
 
-	return checkers, nil
+	return clientSource, clientDestination, nil
 }
 
 func computeIntervals(startTime, endTime int64, numIntervals int64) ([]*Interval, error) {
@@ -95,6 +170,7 @@ func computeIntervals(startTime, endTime int64, numIntervals int64) ([]*Interval
 		}
 
 		intervals = append(intervals, &Interval{
+			idx:   int(idx),
 			start: start,
 			stop:  stop,
 		})
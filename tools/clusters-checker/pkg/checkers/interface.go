@@ -1,9 +1,17 @@
 package checkers
 
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/kalyan3104/k-chain-es-indexer-go/tools/clusters-checker/pkg/report"
+)
+
 // ESClient defines what an ES client should do
 type ESClient interface {
-	InitializeScroll(index string, body []byte, response interface{}) (string, bool, error)
-	DoScrollRequestV2(scrollID string, response interface{}) (string, bool, error)
+	OpenPointInTime(index string, keepAlive string) (string, error)
+	ClosePointInTime(id string) error
+	DoSearchRequest(body []byte, response interface{}) error
 
 	DoCountRequest(index string, body []byte) (uint64, error)
 	DoGetRequest(index string, body []byte, response interface{}, size int) error
@@ -13,9 +21,30 @@ type ESClient interface {
 		handlerFunc func(responseBytes []byte) error,
 		size int,
 	) error
+	DoBulkRequest(buff *bytes.Buffer, index string) error
+}
+
+// ReportWriter defines what a component that saves the documents which are not the same in the two clusters should do
+type ReportWriter interface {
+	WriteRecord(record *report.Record) error
+	Flush() error
+	IsInterfaceNil() bool
+}
+
+// Repairer defines what a component that brings the clusters in sync should do
+type Repairer interface {
+	RepairMissingDocument(index string, id string, kind string, rawData json.RawMessage) error
+	RepairDifferentDocument(index string, id string, rawDataSource, rawDataDestination json.RawMessage) error
+	Flush() error
+	NumCopied() uint64
+	NumDeleted() uint64
+	IsInterfaceNil() bool
 }
 
-type Checker interface {
-	CompareIndicesNoTimestamp() error
-	CompareIndicesWithTimestamp() error
+// CheckpointHandler defines what a component that remembers the compared timestamp intervals should do
+type CheckpointHandler interface {
+	EndTimestamp() int64
+	IsDone(index string, intervalIdx int) bool
+	MarkDone(index string, intervalIdx int) error
+	IsInterfaceNil() bool
 }
@@ -2,6 +2,8 @@ package checkers
 
 import (
 	"encoding/json"
+
+	"github.com/kalyan3104/k-chain-es-indexer-go/tools/clusters-checker/pkg/report"
 )
 
 const (
@@ -15,9 +17,12 @@ func (cc *clusterChecker) CompareIndicesNoTimestamp() error {
 		if err != nil {
 			return err
 		}
+		if cc.repairErr != nil {
+			return cc.repairErr
+		}
 	}
 
-	return nil
+	return cc.repairer.Flush()
 }
 
 func (cc *clusterChecker) compareIndex(index string) error {
@@ -64,18 +69,11 @@ func (cc *clusterChecker) compareResultsNo(index string, sourceRes, destinationR
 		rawDataDestination, found := destinationRes[id]
 		if !found {
 			log.Warn(cc.logPrefix+": cannot find document", "index", index, "id", id)
+			cc.reportMissingDocument(index, id, report.MissingInDestination, rawDataSource)
 			continue
 		}
 
-		equal, err := areEqualJSON(rawDataSource, rawDataDestination)
-		if err != nil {
-			log.Error(cc.logPrefix+": cannot compare json", "error", err.Error(), "index", index, "id", id)
-			continue
-		}
-
-		if !equal {
-			log.Warn(cc.logPrefix+": different documents", "index", index, "id", id)
-		}
+		cc.compareDocuments(index, id, rawDataSource, rawDataDestination)
 	}
 }
 
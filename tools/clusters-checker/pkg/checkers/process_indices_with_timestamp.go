@@ -2,7 +2,9 @@ package checkers
 
 import (
 	"encoding/json"
+	"sync"
 
+	"github.com/kalyan3104/k-chain-es-indexer-go/tools/clusters-checker/pkg/report"
 	logger "github.com/kalyan3104/k-chain-logger-go"
 )
 
@@ -23,110 +25,69 @@ type clusterChecker struct {
 	indicesNoTimestamp   []string
 	indicesWithTimestamp []string
 
-	startTimestamp, stopTimestamp int
-
-	logPrefix string
-	onlyIDs   bool
+	logPrefix     string
+	onlyIDs       bool
+	ignoredFields map[string][]string
+	reporter      ReportWriter
+	repairer      Repairer
+	repairErr     error
 }
 
-func (cc *clusterChecker) CompareIndicesWithTimestamp() error {
-	for _, index := range cc.indicesWithTimestamp {
-		err := cc.compareIndexWithTimestamp(index)
-		if err != nil {
-			return err
-		}
-	}
-
-	return nil
-}
-
-func (cc *clusterChecker) compareIndexWithTimestamp(index string) error {
-	rspSource := &generalElasticResponse{}
-
+// compareSlice compares the documents of the index from the provided timestamp interval and returns the number of
+// documents read from the source cluster
+func (cc *clusterChecker) compareSlice(index string, interval *Interval) (int, error) {
 	withSource := !cc.onlyIDs
 
-	nextScrollIDSource, doneSource, err := cc.clientSource.InitializeScroll(
-		index,
-		getAllSortTimestampASC(withSource, cc.startTimestamp, cc.stopTimestamp),
-		rspSource,
-	)
+	readerSource, err := newSliceReader(cc.clientSource, index, interval, withSource)
 	if err != nil {
-		return err
+		return 0, err
 	}
+	defer readerSource.close()
 
-	rspDestination := &generalElasticResponse{}
-	nextScrollIDDestination, doneDestination, err := cc.clientDestination.InitializeScroll(
-		index,
-		getAllSortTimestampASC(withSource, cc.startTimestamp, cc.stopTimestamp),
-		rspDestination,
-	)
+	readerDestination, err := newSliceReader(cc.clientDestination, index, interval, withSource)
 	if err != nil {
-		return err
+		return 0, err
 	}
-
-	cc.compareResults(index, rspSource, rspDestination)
-	if doneSource && doneDestination {
-		return nil
-	}
-
-	cc.continueReading(index, nextScrollIDSource, nextScrollIDDestination)
-
-	return nil
-}
-
-func (cc *clusterChecker) continueReading(index string, scrollIDSource, scrollIDDestination string) {
-	sourceID := scrollIDSource
-	destinationID := scrollIDDestination
-	var errSource, errDestination error
-	var doneSource, doneDestination bool
+	defer readerDestination.close()
 
 	count := 0
-	for {
+	for !readerSource.done || !readerDestination.done {
 		count++
 
-		chanResponseSource := make(chan *generalElasticResponse)
-		chanResponseDestination := make(chan *generalElasticResponse)
+		var rspFromSource, rspFromDestination *generalElasticResponse
+		var errSource, errDestination error
 
+		wg := sync.WaitGroup{}
+		wg.Add(2)
 		go func() {
-			responseS := &generalElasticResponse{}
-			var nextScroll string
-			if !doneSource {
-				nextScroll, doneSource, errSource = cc.clientSource.DoScrollRequestV2(sourceID, responseS)
-				if errSource != nil {
-					log.Error(cc.logPrefix+": cannot read from source", "index", index, "error", errSource.Error())
-				}
-			}
-			sourceID = nextScroll
-			chanResponseSource <- responseS
+			rspFromSource, errSource = readerSource.nextPage()
+			wg.Done()
 		}()
-
 		go func() {
-			var nextScroll string
-			responseD := &generalElasticResponse{}
-			if !doneDestination {
-				nextScroll, doneDestination, errDestination = cc.clientDestination.DoScrollRequestV2(destinationID, responseD)
-				if errDestination != nil {
-					log.Error(cc.logPrefix+": cannot read from destination", "index", index, "error", errDestination.Error())
-				}
-			}
-			destinationID = nextScroll
-			chanResponseDestination <- responseD
+			rspFromDestination, errDestination = readerDestination.nextPage()
+			wg.Done()
 		}()
+		wg.Wait()
 
-		rspFromSource := <-chanResponseSource
-		rspFromDestination := <-chanResponseDestination
+		if errSource != nil {
+			log.Error(cc.logPrefix+": cannot read from source", "index", index, "error", errSource.Error())
+			return 0, errSource
+		}
+		if errDestination != nil {
+			log.Error(cc.logPrefix+": cannot read from destination", "index", index, "error", errDestination.Error())
+			return 0, errDestination
+		}
 
 		cc.compareResults(index, rspFromSource, rspFromDestination)
-		log.Info(cc.logPrefix+": comparing results", "index", index, "count", count)
+		log.Debug(cc.logPrefix+": comparing results", "index", index, "slice", interval.idx, "count", count)
 		if count%checkAccumulateInterval == 0 {
 			cc.checkMaps(index, false)
 		}
-
-		if doneSource && doneDestination {
-			cc.checkMaps(index, true)
-			return
-		}
 	}
+
+	cc.checkMaps(index, true)
+
+	return readerSource.numRead, cc.repairErr
 }
 
 func (cc *clusterChecker) compareResults(index string, respSource, respDestination *generalElasticResponse) {
@@ -145,16 +106,7 @@ func (cc *clusterChecker) compareResults(index string, respSource, respDestinati
 			continue
 		}
 
-		equal, err := areEqualJSON(rawDataSource, rawDataDestination)
-		if err != nil {
-			log.Error(cc.logPrefix+": cannot compare json", "error", err.Error(), "index", index, "id", id)
-			continue
-		}
-
-		if !equal {
-			log.Warn(cc.logPrefix+": different documents", "index", index, "id", id)
-			continue
-		}
+		cc.compareDocuments(index, id, rawDataSource, rawDataDestination)
 	}
 
 	for id, rawDataSource := range mapDestination {
@@ -172,6 +124,7 @@ func (cc *clusterChecker) checkMaps(index string, finish bool) {
 		if !found {
 			if finish {
 				log.Warn(cc.logPrefix+": cannot find document destination", "index", index, "id", id)
+				cc.reportMissingDocument(index, id, report.MissingInDestination, rawDataSource)
 			}
 			continue
 		}
@@ -183,20 +136,12 @@ func (cc *clusterChecker) checkMaps(index string, finish bool) {
 			continue
 		}
 
-		equal, err := areEqualJSON(rawDataSource, rawDataDestination)
-		if err != nil {
-			log.Error(cc.logPrefix+": cannot compare json", "error", err.Error(), "index", index, "id", id)
-			continue
-		}
-
-		if !equal {
-			log.Warn(cc.logPrefix+": different documents", "index", index, "id", id)
-			continue
-		}
+		cc.compareDocuments(index, id, rawDataSource, rawDataDestination)
 	}
 	if finish {
-		for id := range cc.missingFromDestination {
+		for id, rawDataDestination := range cc.missingFromDestination {
 			log.Warn(cc.logPrefix+": cannot find document source", "index", index, "id", id)
+			cc.reportMissingDocument(index, id, report.MissingInSource, rawDataDestination)
 		}
 	}
 
@@ -0,0 +1,69 @@
+package checkers
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// progress tracks the compared slices and estimates the time needed for the remaining ones
+type progress struct {
+	mut              sync.Mutex
+	startTime        time.Time
+	numSlices        int
+	numDone          int
+	numDoneAtStart   int
+	numDocumentsRead uint64
+}
+
+func newProgress(numSlices int, numDoneAtStart int) *progress {
+	return &progress{
+		startTime:      time.Now(),
+		numSlices:      numSlices,
+		numDone:        numDoneAtStart,
+		numDoneAtStart: numDoneAtStart,
+	}
+}
+
+// sliceDone marks a slice as compared and logs the progress
+func (p *progress) sliceDone(index string, interval *Interval, numDocuments int) {
+	p.mut.Lock()
+	defer p.mut.Unlock()
+
+	p.numDone++
+	p.numDocumentsRead += uint64(numDocuments)
+
+	elapsed := time.Since(p.startTime)
+	log.Info("slice compared",
+		"index", index,
+		"slice", interval.idx,
+		"num documents", numDocuments,
+		"progress", fmt.Sprintf("%d/%d (%.2f%%)", p.numDone, p.numSlices, float64(p.numDone)*100/float64(p.numSlices)),
+		"elapsed", elapsed.Truncate(time.Second),
+		"eta", p.estimateRemainingTime(elapsed).Truncate(time.Second),
+	)
+}
+
+// estimateRemainingTime considers only the slices compared in this run, the ones loaded from the checkpoint taking no time
+func (p *progress) estimateRemainingTime(elapsed time.Duration) time.Duration {
+	numDoneInThisRun := p.numDone - p.numDoneAtStart
+	if numDoneInThisRun == 0 {
+		return 0
+	}
+
+	numRemaining := p.numSlices - p.numDone
+
+	return elapsed / time.Duration(numDoneInThisRun) * time.Duration(numRemaining)
+}
+
+func (p *progress) logSummary() {
+	p.mut.Lock()
+	defer p.mut.Unlock()
+
+	log.Info("comparison of the indices with timestamp finished",
+		"num slices compared", p.numDone-p.numDoneAtStart,
+		"num slices from checkpoint", p.numDoneAtStart,
+		"num documents read", p.numDocumentsRead,
+		"duration", time.Since(p.startTime).Truncate(time.Second),
+	)
+}
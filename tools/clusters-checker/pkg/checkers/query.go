@@ -31,25 +31,36 @@ func queryMultipleObj(ids []string, withSource bool) []byte {
 	return buff.Bytes()
 }
 
-func getAllSortTimestampASC(withSource bool, start, stop int) []byte {
+// getSlicePageSortTimestampASC returns the query for a page of the documents with the timestamp in [start, stop), read
+// in a point in time after the sort values of the last document from the previous page. The point in time adds the
+// shard document as implicit tiebreaker, so the documents with the same timestamp are not skipped between pages
+func getSlicePageSortTimestampASC(withSource bool, start, stop int64, pitID string, searchAfter json.RawMessage, size int) []byte {
 	obj := object{
 		"query": object{
 			"range": object{
 				"timestamp": object{
 					"gte": fmt.Sprintf("%d", start),
-					"lte": fmt.Sprintf("%d", stop),
+					"lt":  fmt.Sprintf("%d", stop),
 				},
 			},
 		},
 		"_source": withSource,
+		"size":    size,
+		"pit": object{
+			"id":         pitID,
+			"keep_alive": pointInTimeKeepAlive,
+		},
 		"sort": []interface{}{
 			object{
 				"timestamp": object{
-					"order": "desc",
+					"order": "asc",
 				},
 			},
 		},
 	}
+	if len(searchAfter) > 0 {
+		obj["search_after"] = searchAfter
+	}
 
 	var buff bytes.Buffer
 	_ = json.NewEncoder(&buff).Encode(obj)
@@ -0,0 +1,63 @@
+package checkers
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/kalyan3104/k-chain-es-indexer-go/tools/clusters-checker/pkg/report"
+)
+
+// compareDocuments compares the versions of a document from the two clusters, reports the paths that differ and
+// repairs the document
+func (cc *clusterChecker) compareDocuments(index string, id string, rawDataSource, rawDataDestination json.RawMessage) {
+	paths, err := differentPaths(rawDataSource, rawDataDestination, cc.ignoredFields[index])
+	if err != nil {
+		log.Error(cc.logPrefix+": cannot compare json", "error", err.Error(), "index", index, "id", id)
+		return
+	}
+	if len(paths) == 0 {
+		return
+	}
+
+	log.Warn(cc.logPrefix+": different documents", "index", index, "id", id, "paths", strings.Join(paths, ","))
+	cc.writeRecord(&report.Record{
+		Index: index,
+		ID:    id,
+		Kind:  report.Different,
+		Paths: paths,
+	})
+
+	err = cc.repairer.RepairDifferentDocument(index, id, rawDataSource, rawDataDestination)
+	cc.handleRepairError(index, id, err)
+}
+
+func (cc *clusterChecker) reportMissingDocument(index string, id string, kind string, rawData json.RawMessage) {
+	cc.writeRecord(&report.Record{
+		Index: index,
+		ID:    id,
+		Kind:  kind,
+	})
+
+	err := cc.repairer.RepairMissingDocument(index, id, kind, rawData)
+	cc.handleRepairError(index, id, err)
+}
+
+// handleRepairError logs the error and keeps the first one, so the comparison stops after the current index and the
+// interval is not saved as compared
+func (cc *clusterChecker) handleRepairError(index string, id string, err error) {
+	if err == nil {
+		return
+	}
+
+	log.Error(cc.logPrefix+": cannot repair document", "index", index, "id", id, "error", err.Error())
+	if cc.repairErr == nil {
+		cc.repairErr = err
+	}
+}
+
+func (cc *clusterChecker) writeRecord(record *report.Record) {
+	err := cc.reporter.WriteRecord(record)
+	if err != nil {
+		log.Error(cc.logPrefix+": cannot write report record", "index", record.Index, "id", record.ID, "error", err.Error())
+	}
+}
@@ -0,0 +1,67 @@
+package checkers
+
+import "encoding/json"
+
+const (
+	pointInTimeKeepAlive = "10m"
+	slicePageSize        = 9000
+)
+
+// sliceReader reads page by page, in a point in time, the documents of an index from a timestamp interval
+type sliceReader struct {
+	client      ESClient
+	withSource  bool
+	interval    *Interval
+	pitID       string
+	searchAfter json.RawMessage
+	done        bool
+	numRead     int
+}
+
+func newSliceReader(client ESClient, index string, interval *Interval, withSource bool) (*sliceReader, error) {
+	pitID, err := client.OpenPointInTime(index, pointInTimeKeepAlive)
+	if err != nil {
+		return nil, err
+	}
+
+	return &sliceReader{
+		client:     client,
+		withSource: withSource,
+		interval:   interval,
+		pitID:      pitID,
+	}, nil
+}
+
+// nextPage returns the next page of documents, or an empty page if all the documents were read
+func (sr *sliceReader) nextPage() (*generalElasticResponse, error) {
+	response := &generalElasticResponse{}
+	if sr.done {
+		return response, nil
+	}
+
+	query := getSlicePageSortTimestampASC(sr.withSource, sr.interval.start, sr.interval.stop, sr.pitID, sr.searchAfter, slicePageSize)
+	err := sr.client.DoSearchRequest(query, response)
+	if err != nil {
+		return nil, err
+	}
+
+	if response.PitID != "" {
+		sr.pitID = response.PitID
+	}
+
+	hits := response.Hits.Hits
+	sr.numRead += len(hits)
+	sr.done = len(hits) < slicePageSize
+	if len(hits) > 0 {
+		sr.searchAfter = hits[len(hits)-1].Sort
+	}
+
+	return response, nil
+}
+
+func (sr *sliceReader) close() {
+	err := sr.client.ClosePointInTime(sr.pitID)
+	if err != nil {
+		log.Warn("cannot close point in time", "error", err.Error())
+	}
+}
@@ -0,0 +1,46 @@
+package checkers
+
+import (
+	"sync"
+
+	"github.com/kalyan3104/k-chain-es-indexer-go/tools/clusters-checker/pkg/report"
+)
+
+// sliceRecords keeps in memory the records of the slice compared by a worker, so they are written in the report only
+// after the whole slice was compared. The records of a slice interrupted by an error or by a crash are never written,
+// and the resumed comparison does not repeat them
+type sliceRecords struct {
+	mut     sync.Mutex
+	records []*report.Record
+}
+
+// WriteRecord will keep the record until the slice is compared
+func (sr *sliceRecords) WriteRecord(record *report.Record) error {
+	sr.mut.Lock()
+	defer sr.mut.Unlock()
+
+	sr.records = append(sr.records, record)
+
+	return nil
+}
+
+// Flush does nothing, the records are written by the sliced comparer
+func (sr *sliceRecords) Flush() error {
+	return nil
+}
+
+// popRecords returns the kept records and forgets them
+func (sr *sliceRecords) popRecords() []*report.Record {
+	sr.mut.Lock()
+	defer sr.mut.Unlock()
+
+	records := sr.records
+	sr.records = nil
+
+	return records
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (sr *sliceRecords) IsInterfaceNil() bool {
+	return sr == nil
+}
@@ -0,0 +1,141 @@
+package checkers
+
+import (
+	"sync"
+
+	"github.com/kalyan3104/k-chain-es-indexer-go/tools/clusters-checker/pkg/report"
+)
+
+type sliceJob struct {
+	index    string
+	interval *Interval
+}
+
+// sliceWorker is a cluster checker that keeps the records of the slice it compares
+type sliceWorker struct {
+	checker *clusterChecker
+	records *sliceRecords
+}
+
+// slicedComparer compares the indices with timestamp slice by slice, using a pool of cluster checkers
+type slicedComparer struct {
+	workers    []*sliceWorker
+	indices    []string
+	intervals  []*Interval
+	reporter   ReportWriter
+	repairer   Repairer
+	checkpoint CheckpointHandler
+}
+
+// CompareIndicesWithTimestamp will compare all the slices of the indices with timestamp which are not already saved
+// in the checkpoint. Every worker compares a slice at a time and the comparison stops at the first error
+func (sc *slicedComparer) CompareIndicesWithTimestamp() error {
+	jobs := make([]*sliceJob, 0, len(sc.indices)*len(sc.intervals))
+	for _, index := range sc.indices {
+		for _, interval := range sc.intervals {
+			if sc.checkpoint.IsDone(index, interval.idx) {
+				continue
+			}
+
+			jobs = append(jobs, &sliceJob{index: index, interval: interval})
+		}
+	}
+
+	numSlices := len(sc.indices) * len(sc.intervals)
+	progressTracker := newProgress(numSlices, numSlices-len(jobs))
+	log.Info("comparing the indices with timestamp",
+		"num slices", numSlices,
+		"num slices from checkpoint", numSlices-len(jobs),
+		"num workers", len(sc.workers),
+	)
+
+	chanJobs := make(chan *sliceJob)
+	chanStop := make(chan struct{})
+	var firstErr error
+	var mutErr sync.Mutex
+	var stopOnce sync.Once
+
+	wg := sync.WaitGroup{}
+	wg.Add(len(sc.workers))
+	for _, worker := range sc.workers {
+		go func(worker *sliceWorker) {
+			defer wg.Done()
+
+			for job := range chanJobs {
+				err := sc.compareSlice(worker, job, progressTracker)
+				if err == nil {
+					continue
+				}
+
+				log.Error(worker.checker.logPrefix+": cannot compare slice", "index", job.index, "slice", job.interval.idx, "error", err.Error())
+				mutErr.Lock()
+				if firstErr == nil {
+					firstErr = err
+				}
+				mutErr.Unlock()
+				stopOnce.Do(func() { close(chanStop) })
+				return
+			}
+		}(worker)
+	}
+
+	sc.sendJobs(jobs, chanJobs, chanStop)
+	wg.Wait()
+
+	progressTracker.logSummary()
+
+	return firstErr
+}
+
+func (sc *slicedComparer) sendJobs(jobs []*sliceJob, chanJobs chan<- *sliceJob, chanStop <-chan struct{}) {
+	defer close(chanJobs)
+
+	for _, job := range jobs {
+		select {
+		case chanJobs <- job:
+		case <-chanStop:
+			return
+		}
+	}
+}
+
+// compareSlice compares a slice and saves it in the checkpoint after its repairs and its report records are written
+func (sc *slicedComparer) compareSlice(worker *sliceWorker, job *sliceJob, progressTracker *progress) error {
+	numDocuments, err := worker.checker.compareSlice(job.index, job.interval)
+	records := worker.records.popRecords()
+	if err != nil {
+		return err
+	}
+
+	err = sc.repairer.Flush()
+	if err != nil {
+		return err
+	}
+
+	err = sc.writeRecords(records)
+	if err != nil {
+		return err
+	}
+
+	err = sc.checkpoint.MarkDone(job.index, job.interval.idx)
+	if err != nil {
+		return err
+	}
+
+	progressTracker.sliceDone(job.index, job.interval, numDocuments)
+
+	return nil
+}
+
+// writeRecords writes the records of a compared slice and syncs the report, so they are on disk before the slice is
+// saved in the checkpoint
+func (sc *slicedComparer) writeRecords(records []*report.Record) error {
+	for _, record := range records {
+		err := sc.reporter.WriteRecord(record)
+		if err != nil {
+			return err
+		}
+	}
+
+	return sc.reporter.Flush()
+}
@@ -0,0 +1,310 @@
+package checkers
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/kalyan3104/k-chain-es-indexer-go/tools/clusters-checker/pkg/checkpoint"
+	"github.com/kalyan3104/k-chain-es-indexer-go/tools/clusters-checker/pkg/report"
+)
+
+const testIndex = "transactions"
+
+var errRepair = errors.New("repair error")
+
+type testDocument struct {
+	id        string
+	timestamp int64
+	source    string
+}
+
+// esClientStub serves the documents of an index from memory, the point in time id being the index name
+type esClientStub struct {
+	mut           sync.Mutex
+	documents     map[string][]testDocument
+	searchedSlice []string
+}
+
+func (ecs *esClientStub) OpenPointInTime(index string, _ string) (string, error) {
+	return index, nil
+}
+
+func (ecs *esClientStub) ClosePointInTime(_ string) error {
+	return nil
+}
+
+func (ecs *esClientStub) DoSearchRequest(body []byte, response interface{}) error {
+	query := struct {
+		Query struct {
+			Range struct {
+				Timestamp struct {
+					Gte string `json:"gte"`
+					Lt  string `json:"lt"`
+				} `json:"timestamp"`
+			} `json:"range"`
+		} `json:"query"`
+		Pit struct {
+			ID string `json:"id"`
+		} `json:"pit"`
+	}{}
+	err := json.Unmarshal(body, &query)
+	if err != nil {
+		return err
+	}
+
+	ecs.mut.Lock()
+	ecs.searchedSlice = append(ecs.searchedSlice, query.Query.Range.Timestamp.Gte)
+	ecs.mut.Unlock()
+
+	hits := make([]string, 0)
+	for _, document := range ecs.documents[query.Pit.ID] {
+		if !inInterval(document.timestamp, query.Query.Range.Timestamp.Gte, query.Query.Range.Timestamp.Lt) {
+			continue
+		}
+
+		hits = append(hits, fmt.Sprintf(`{"_id":"%s","_source":%s,"sort":[%d]}`, document.id, document.source, document.timestamp))
+	}
+
+	return json.Unmarshal([]byte(`{"hits":{"hits":[`+strings.Join(hits, ",")+`]}}`), response)
+}
+
+func inInterval(timestamp int64, gte string, lt string) bool {
+	var start, stop int64
+	_, _ = fmt.Sscan(gte, &start)
+	_, _ = fmt.Sscan(lt, &stop)
+
+	return timestamp >= start && timestamp < stop
+}
+
+func (ecs *esClientStub) DoCountRequest(_ string, _ []byte) (uint64, error) {
+	return 0, nil
+}
+
+func (ecs *esClientStub) DoGetRequest(_ string, _ []byte, _ interface{}, _ int) error {
+	return nil
+}
+
+func (ecs *esClientStub) DoScrollRequestAllDocuments(_ string, _ []byte, _ func(responseBytes []byte) error, _ int) error {
+	return nil
+}
+
+func (ecs *esClientStub) DoBulkRequest(_ *bytes.Buffer, _ string) error {
+	return nil
+}
+
+// repairerStub fails to repair the document with the provided id
+type repairerStub struct {
+	failID     string
+	numFlushes int
+}
+
+func (rs *repairerStub) RepairMissingDocument(_ string, id string, _ string, _ json.RawMessage) error {
+	if id == rs.failID {
+		return errRepair
+	}
+
+	return nil
+}
+
+func (rs *repairerStub) RepairDifferentDocument(_ string, id string, _, _ json.RawMessage) error {
+	if id == rs.failID {
+		return errRepair
+	}
+
+	return nil
+}
+
+func (rs *repairerStub) Flush() error {
+	rs.numFlushes++
+	return nil
+}
+
+func (rs *repairerStub) NumCopied() uint64 {
+	return 0
+}
+
+func (rs *repairerStub) NumDeleted() uint64 {
+	return 0
+}
+
+func (rs *repairerStub) IsInterfaceNil() bool {
+	return rs == nil
+}
+
+func createTestSlicedComparer(source, destination ESClient, reporter ReportWriter, repairer Repairer, checkpointHandler CheckpointHandler) *slicedComparer {
+	records := &sliceRecords{}
+
+	return &slicedComparer{
+		workers: []*sliceWorker{{
+			checker: &clusterChecker{
+				clientSource:           source,
+				clientDestination:      destination,
+				missingFromSource:      map[string]json.RawMessage{},
+				missingFromDestination: map[string]json.RawMessage{},
+				logPrefix:              "instance_0",
+				reporter:               records,
+				repairer:               repairer,
+			},
+			records: records,
+		}},
+		indices: []string{testIndex},
+		intervals: []*Interval{
+			{idx: 0, start: 0, stop: 100},
+			{idx: 1, start: 100, stop: 200},
+		},
+		reporter:   reporter,
+		repairer:   repairer,
+		checkpoint: checkpointHandler,
+	}
+}
+
+type closableReportWriter interface {
+	ReportWriter
+	Close() error
+}
+
+func createTestReportWriter(t *testing.T, filePath string, appendRecords bool) closableReportWriter {
+	reportWriter, err := report.NewReportWriter(report.ArgsReportWriter{
+		FilePath: filePath,
+		Formats:  []string{report.FormatJSONLines, report.FormatCSV},
+		Append:   appendRecords,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return reportWriter
+}
+
+func readReportIDs(t *testing.T, filePath string) []string {
+	file, err := os.Open(filePath + "." + report.FormatJSONLines)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	ids := make([]string, 0)
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		record := &report.Record{}
+		err = json.Unmarshal(scanner.Bytes(), record)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		ids = append(ids, record.ID+":"+record.Kind)
+	}
+	sort.Strings(ids)
+
+	return ids
+}
+
+func readCSVLines(t *testing.T, filePath string) []string {
+	csvBytes, err := os.ReadFile(filePath + "." + report.FormatCSV)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return strings.Split(strings.TrimSpace(string(csvBytes)), "\n")
+}
+
+func requireStrings(t *testing.T, expected []string, actual []string) {
+	t.Helper()
+
+	if strings.Join(expected, "|") != strings.Join(actual, "|") {
+		t.Fatalf("expected %v, got %v", expected, actual)
+	}
+}
+
+func TestSlicedComparer_ResumeShouldNeitherLoseNorRepeatRecords(t *testing.T) {
+	dirPath := t.TempDir()
+	reportPath := filepath.Join(dirPath, "report")
+	checkpointPath := filepath.Join(dirPath, "checkpoint.json")
+
+	source := &esClientStub{documents: map[string][]testDocument{
+		testIndex: {
+			{id: "a", timestamp: 10, source: `{"value":1}`},
+			{id: "c", timestamp: 20, source: `{"value":5}`},
+			{id: "b", timestamp: 150, source: `{"value":2}`},
+			{id: "d", timestamp: 160, source: `{"value":4}`},
+		},
+	}}
+	destination := &esClientStub{documents: map[string][]testDocument{
+		testIndex: {
+			{id: "a", timestamp: 10, source: `{"value":1}`},
+			{id: "b", timestamp: 150, source: `{"value":3}`},
+			{id: "d", timestamp: 160, source: `{"value":6}`},
+		},
+	}}
+
+	// the first run compares the first slice and fails in the second one, after a record of it was kept
+	checkpointHandler, err := checkpoint.NewCheckpointHandler(checkpointPath, 0, 199, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	reporter := createTestReportWriter(t, reportPath, checkpointHandler.HasProgress())
+	repairer := &repairerStub{failID: "d"}
+	err = createTestSlicedComparer(source, destination, reporter, repairer, checkpointHandler).CompareIndicesWithTimestamp()
+	if !errors.Is(err, errRepair) {
+		t.Fatalf("expected the repair error, got %v", err)
+	}
+	if repairer.numFlushes != 1 {
+		t.Fatalf("expected the repairs of the first slice only to be flushed, got %d flushes", repairer.numFlushes)
+	}
+
+	// the records of the compared slice are on disk before the report is closed, the ones of the failed slice are not
+	requireStrings(t, []string{"c:" + report.MissingInDestination}, readReportIDs(t, reportPath))
+	requireStrings(t, []string{"index,id,kind,paths", testIndex + ",c," + report.MissingInDestination + ","}, readCSVLines(t, reportPath))
+	err = reporter.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the resumed run compares only the second slice and appends its records
+	checkpointHandler, err = checkpoint.NewCheckpointHandler(checkpointPath, 0, 299, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !checkpointHandler.HasProgress() || !checkpointHandler.IsDone(testIndex, 0) || checkpointHandler.IsDone(testIndex, 1) {
+		t.Fatal("the checkpoint should hold only the first slice")
+	}
+	if checkpointHandler.EndTimestamp() != 199 {
+		t.Fatalf("the resumed checkpoint should keep the end timestamp, got %d", checkpointHandler.EndTimestamp())
+	}
+
+	source.searchedSlice = nil
+	reporter = createTestReportWriter(t, reportPath, checkpointHandler.HasProgress())
+	err = createTestSlicedComparer(source, destination, reporter, &repairerStub{}, checkpointHandler).CompareIndicesWithTimestamp()
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = reporter.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	requireStrings(t, []string{"100"}, source.searchedSlice)
+	requireStrings(t, []string{
+		"b:" + report.Different,
+		"c:" + report.MissingInDestination,
+		"d:" + report.Different,
+	}, readReportIDs(t, reportPath))
+	csvLines := readCSVLines(t, reportPath)
+	if len(csvLines) != 4 || csvLines[0] != "index,id,kind,paths" {
+		t.Fatalf("expected the header and a line for every record, got %v", csvLines)
+	}
+	if !checkpointHandler.IsDone(testIndex, 1) {
+		t.Fatal("the second slice should be saved in the checkpoint")
+	}
+}
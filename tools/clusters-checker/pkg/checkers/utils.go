@@ -4,21 +4,122 @@ import (
 	"encoding/json"
 	"fmt"
 	"reflect"
+	"sort"
+	"strconv"
+	"strings"
 )
 
-func areEqualJSON(s1, s2 json.RawMessage) (bool, error) {
+const (
+	pathSeparator = "."
+	// rootPath is reported when the documents themselves differ in type, as the root has no path in the gjson syntax
+	rootPath = "$"
+)
+
+// differentPaths returns the paths of the values that are not the same in the two documents, written in the gjson path
+// syntax (a field of an object is separated by a dot, and an array element is addressed by its index). The values under
+// the ignored paths, or under a path which starts with an ignored one, are not compared. When the two documents are not
+// of the same type, the root path is reported
+func differentPaths(s1, s2 json.RawMessage, ignoredPaths []string) ([]string, error) {
 	var o1 interface{}
 	var o2 interface{}
 
 	var err error
 	err = json.Unmarshal(s1, &o1)
 	if err != nil {
-		return false, fmt.Errorf("error unmashalling s1: %s", err.Error())
+		return nil, fmt.Errorf("error unmashalling s1: %s", err.Error())
 	}
 	err = json.Unmarshal(s2, &o2)
 	if err != nil {
-		return false, fmt.Errorf("error unmashalling s2: %s", err.Error())
+		return nil, fmt.Errorf("error unmashalling s2: %s", err.Error())
+	}
+
+	paths := make([]string, 0)
+	collectDifferentPaths("", o1, o2, ignoredPaths, &paths)
+
+	return paths, nil
+}
+
+func collectDifferentPaths(path string, o1, o2 interface{}, ignoredPaths []string, paths *[]string) {
+	if isIgnoredPath(path, ignoredPaths) {
+		return
+	}
+
+	map1, isMap1 := o1.(map[string]interface{})
+	map2, isMap2 := o2.(map[string]interface{})
+	if isMap1 && isMap2 {
+		for _, key := range unionOfKeys(map1, map2) {
+			collectDifferentPaths(joinPath(path, escapePathComponent(key)), map1[key], map2[key], ignoredPaths, paths)
+		}
+		return
+	}
+
+	slice1, isSlice1 := o1.([]interface{})
+	slice2, isSlice2 := o2.([]interface{})
+	if isSlice1 && isSlice2 {
+		maxLen := len(slice1)
+		if len(slice2) > maxLen {
+			maxLen = len(slice2)
+		}
+
+		for idx := 0; idx < maxLen; idx++ {
+			collectDifferentPaths(joinPath(path, strconv.Itoa(idx)), elementAt(slice1, idx), elementAt(slice2, idx), ignoredPaths, paths)
+		}
+		return
+	}
+
+	if reflect.DeepEqual(o1, o2) {
+		return
+	}
+	if path == "" {
+		path = rootPath
+	}
+	*paths = append(*paths, path)
+}
+
+func isIgnoredPath(path string, ignoredPaths []string) bool {
+	for _, ignoredPath := range ignoredPaths {
+		if path == ignoredPath || strings.HasPrefix(path, ignoredPath+pathSeparator) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func unionOfKeys(map1, map2 map[string]interface{}) []string {
+	keys := make([]string, 0, len(map1)+len(map2))
+	for key := range map1 {
+		keys = append(keys, key)
 	}
+	for key := range map2 {
+		if _, found := map1[key]; !found {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+func elementAt(slice []interface{}, idx int) interface{} {
+	if idx < len(slice) {
+		return slice[idx]
+	}
+
+	return nil
+}
+
+func joinPath(path string, component string) string {
+	if path == "" {
+		return component
+	}
+
+	return path + pathSeparator + component
+}
+
+// escapePathComponent escapes the characters with a special meaning in the gjson path syntax
+func escapePathComponent(component string) string {
+	replacer := strings.NewReplacer(".", `\.`, "*", `\*`, "?", `\?`)
 
-	return reflect.DeepEqual(o1, o2), nil
+	return replacer.Replace(component)
 }
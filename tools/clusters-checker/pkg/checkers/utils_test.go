@@ -0,0 +1,116 @@
+package checkers
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestDifferentPaths(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		source        string
+		destination   string
+		ignoredPaths  []string
+		expectedPaths []string
+	}{
+		{
+			name:          "same documents",
+			source:        `{"a":1,"b":{"c":[1,2]}}`,
+			destination:   `{"b":{"c":[1,2]},"a":1}`,
+			expectedPaths: []string{},
+		},
+		{
+			name:          "nested objects",
+			source:        `{"a":{"b":{"c":1,"d":"x"},"e":true}}`,
+			destination:   `{"a":{"b":{"c":2,"d":"x"},"e":false}}`,
+			expectedPaths: []string{"a.b.c", "a.e"},
+		},
+		{
+			name:          "field missing in one of the nested objects",
+			source:        `{"a":{"b":1}}`,
+			destination:   `{"a":{"b":1,"c":2}}`,
+			expectedPaths: []string{"a.c"},
+		},
+		{
+			name:          "longer array in the destination",
+			source:        `{"a":[1,2]}`,
+			destination:   `{"a":[1,2,3,4]}`,
+			expectedPaths: []string{"a.2", "a.3"},
+		},
+		{
+			name:          "longer array in the source",
+			source:        `{"a":[{"b":1},{"b":2}]}`,
+			destination:   `{"a":[{"b":5}]}`,
+			expectedPaths: []string{"a.0.b", "a.1"},
+		},
+		{
+			name:          "field of a different type",
+			source:        `{"a":{"b":1}}`,
+			destination:   `{"a":[1]}`,
+			expectedPaths: []string{"a"},
+		},
+		{
+			name:          "object and array documents",
+			source:        `{"a":1}`,
+			destination:   `[1]`,
+			expectedPaths: []string{rootPath},
+		},
+		{
+			name:          "object and number documents",
+			source:        `{"a":1}`,
+			destination:   `7`,
+			expectedPaths: []string{rootPath},
+		},
+		{
+			name:          "different numbers as documents",
+			source:        `1`,
+			destination:   `2`,
+			expectedPaths: []string{rootPath},
+		},
+		{
+			name:          "ignored paths",
+			source:        `{"a":{"b":1,"c":2},"d":[1],"e":1}`,
+			destination:   `{"a":{"b":3,"c":4},"d":[2],"e":2}`,
+			ignoredPaths:  []string{"a", "d.0"},
+			expectedPaths: []string{"e"},
+		},
+		{
+			name:          "keys with special characters are escaped",
+			source:        `{"a.b":1,"c*":1}`,
+			destination:   `{"a.b":2,"c*":2}`,
+			expectedPaths: []string{`a\.b`, `c\*`},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			paths, err := differentPaths(json.RawMessage(tt.source), json.RawMessage(tt.destination), tt.ignoredPaths)
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+			if !reflect.DeepEqual(tt.expectedPaths, paths) {
+				t.Fatalf("expected the paths %v, got %v", tt.expectedPaths, paths)
+			}
+		})
+	}
+}
+
+func TestDifferentPaths_InvalidDocumentsShouldErr(t *testing.T) {
+	t.Parallel()
+
+	_, err := differentPaths(json.RawMessage(`{"a":`), json.RawMessage(`{}`), nil)
+	if err == nil {
+		t.Fatal("expected an error for the source document")
+	}
+
+	_, err = differentPaths(json.RawMessage(`{}`), json.RawMessage(`not json`), nil)
+	if err == nil {
+		t.Fatal("expected an error for the destination document")
+	}
+}
@@ -0,0 +1,156 @@
+package checkpoint
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"sync"
+
+	logger "github.com/kalyan3104/k-chain-logger-go"
+)
+
+const checkpointFilePermissions = 0644
+
+var log = logger.GetOrCreate("clusters-checker/pkg/checkpoint")
+
+// Checkpoint holds the intervals of every index that were already compared
+type Checkpoint struct {
+	StartTimestamp int64            `json:"startTimestamp"`
+	EndTimestamp   int64            `json:"endTimestamp"`
+	NumIntervals   int              `json:"numIntervals"`
+	Completed      map[string][]int `json:"completed"`
+}
+
+type checkpointHandler struct {
+	mut        sync.Mutex
+	filePath   string
+	checkpoint *Checkpoint
+}
+
+// NewCheckpointHandler creates a component that remembers which timestamp intervals were compared, so a comparison
+// can resume after a crash. A saved checkpoint is reused only if it was made for the same start timestamp and the
+// same number of intervals, its end timestamp being kept so the intervals are the same. With an empty file path the
+// progress is kept only in memory
+func NewCheckpointHandler(filePath string, startTimestamp int64, endTimestamp int64, numIntervals int) (*checkpointHandler, error) {
+	ch := &checkpointHandler{
+		filePath: filePath,
+		checkpoint: &Checkpoint{
+			StartTimestamp: startTimestamp,
+			EndTimestamp:   endTimestamp,
+			NumIntervals:   numIntervals,
+			Completed:      make(map[string][]int),
+		},
+	}
+	if filePath == "" {
+		return ch, nil
+	}
+
+	savedCheckpoint, err := loadCheckpoint(filePath)
+	if err != nil {
+		return nil, err
+	}
+	if savedCheckpoint == nil {
+		return ch, nil
+	}
+
+	if savedCheckpoint.StartTimestamp != startTimestamp || savedCheckpoint.NumIntervals != numIntervals {
+		log.Warn("the saved checkpoint was made for other intervals and will be overwritten",
+			"file", filePath,
+			"saved start timestamp", savedCheckpoint.StartTimestamp,
+			"saved num intervals", savedCheckpoint.NumIntervals,
+		)
+		return ch, nil
+	}
+	if savedCheckpoint.Completed == nil {
+		savedCheckpoint.Completed = make(map[string][]int)
+	}
+
+	ch.checkpoint = savedCheckpoint
+	log.Info("resuming from checkpoint", "file", filePath, "end timestamp", savedCheckpoint.EndTimestamp)
+
+	return ch, nil
+}
+
+func loadCheckpoint(filePath string) (*Checkpoint, error) {
+	checkpointBytes, err := ioutil.ReadFile(filePath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	savedCheckpoint := &Checkpoint{}
+	err = json.Unmarshal(checkpointBytes, savedCheckpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	return savedCheckpoint, nil
+}
+
+// EndTimestamp returns the end timestamp of the last interval
+func (ch *checkpointHandler) EndTimestamp() int64 {
+	ch.mut.Lock()
+	defer ch.mut.Unlock()
+
+	return ch.checkpoint.EndTimestamp
+}
+
+// HasProgress returns true if at least an interval was already compared
+func (ch *checkpointHandler) HasProgress() bool {
+	ch.mut.Lock()
+	defer ch.mut.Unlock()
+
+	return len(ch.checkpoint.Completed) > 0
+}
+
+// IsDone returns true if the provided interval of the index was already compared
+func (ch *checkpointHandler) IsDone(index string, intervalIdx int) bool {
+	ch.mut.Lock()
+	defer ch.mut.Unlock()
+
+	for _, completedIdx := range ch.checkpoint.Completed[index] {
+		if completedIdx == intervalIdx {
+			return true
+		}
+	}
+
+	return false
+}
+
+// MarkDone will remember that the provided interval of the index was compared and save the checkpoint
+func (ch *checkpointHandler) MarkDone(index string, intervalIdx int) error {
+	ch.mut.Lock()
+	defer ch.mut.Unlock()
+
+	ch.checkpoint.Completed[index] = append(ch.checkpoint.Completed[index], intervalIdx)
+	if ch.filePath == "" {
+		return nil
+	}
+
+	return ch.saveUnprotected()
+}
+
+// saveUnprotected writes the checkpoint in a temporary file that replaces the old one, so a crash while saving
+// cannot leave a partially written checkpoint
+func (ch *checkpointHandler) saveUnprotected() error {
+	checkpointBytes, err := json.Marshal(ch.checkpoint)
+	if err != nil {
+		return err
+	}
+
+	tmpFilePath := ch.filePath + ".tmp"
+	err = ioutil.WriteFile(tmpFilePath, checkpointBytes, checkpointFilePermissions)
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(tmpFilePath, ch.filePath)
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (ch *checkpointHandler) IsInterfaceNil() bool {
+	return ch == nil
+}
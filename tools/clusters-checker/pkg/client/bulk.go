@@ -0,0 +1,59 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+type bulkResponse struct {
+	Errors bool `json:"errors"`
+	Items  []map[string]struct {
+		ID     string `json:"_id"`
+		Status int    `json:"status"`
+		Error  struct {
+			Type   string `json:"type"`
+			Reason string `json:"reason"`
+		} `json:"error"`
+	} `json:"items"`
+}
+
+// DoBulkRequest will do a bulk request with the provided operations and returns the first failed item, if any
+func (esc *esClient) DoBulkRequest(buff *bytes.Buffer, index string) error {
+	res, err := esc.client.Bulk(
+		buff,
+		esc.client.Bulk.WithIndex(index),
+	)
+	if err != nil {
+		return err
+	}
+
+	bodyBytes, err := getBytesFromResponse(res)
+	if err != nil {
+		return err
+	}
+
+	response := &bulkResponse{}
+	err = json.Unmarshal(bodyBytes, response)
+	if err != nil {
+		return err
+	}
+	if !response.Errors {
+		return nil
+	}
+
+	for _, item := range response.Items {
+		for action, result := range item {
+			// deleting a document that is already missing is not an error for a repair
+			if action == "delete" && result.Status == 404 {
+				continue
+			}
+			if result.Status >= 400 {
+				return fmt.Errorf("%s of document %s failed with status %d: %s, %s",
+					action, result.ID, result.Status, result.Error.Type, result.Error.Reason)
+			}
+		}
+	}
+
+	return nil
+}
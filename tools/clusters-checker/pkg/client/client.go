@@ -3,7 +3,6 @@ package client
 import (
 	"bytes"
 	"context"
-	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"math"
@@ -58,82 +57,6 @@ func NewElasticClient(cfg elasticsearch.Config) (*esClient, error) {
 	}, nil
 }
 
-func (esc *esClient) InitializeScroll(index string, body []byte, response interface{}) (string, bool, error) {
-	res, err := esc.client.Search(
-		esc.client.Search.WithSize(9000),
-		esc.client.Search.WithScroll(10*time.Minute+time.Duration(esc.updateAndGetCountScroll())*time.Millisecond),
-		esc.client.Search.WithIndex(index),
-		esc.client.Search.WithBody(bytes.NewBuffer(body)),
-	)
-	if err != nil {
-		return "", false, err
-	}
-	if res.IsError() || res.StatusCode >= 400 {
-		return "", false, fmt.Errorf("%s", res.String())
-	}
-
-	bodyBytes, err := getBytesFromResponse(res)
-	if err != nil {
-		return "", false, err
-	}
-	scrollID := gjson.Get(string(bodyBytes), "_scroll_id").String()
-	numberOfHits := gjson.Get(string(bodyBytes), "hits.hits.#")
-	isDone := numberOfHits.Int() == 0
-
-	if isDone {
-		defer func() {
-			errC := esc.clearScroll(scrollID)
-			if errC != nil {
-				log.Warn("cannot clear scroll", "error", errC)
-			}
-		}()
-	}
-
-	err = json.Unmarshal(bodyBytes, response)
-	if err != nil {
-		return "", false, err
-	}
-
-	return scrollID, isDone, nil
-}
-
-func (esc *esClient) DoScrollRequestV2(scrollID string, response interface{}) (string, bool, error) {
-	defer logExecutionTime(time.Now(), "esClient.DoScrollRequestV2")
-
-	res, err := esc.client.Scroll(
-		esc.client.Scroll.WithScrollID(scrollID),
-		esc.client.Scroll.WithScroll(2*time.Minute+time.Duration(esc.updateAndGetCountScroll())*time.Millisecond),
-	)
-	if err != nil {
-		return "", false, err
-	}
-
-	bodyBytes, err := getBytesFromResponse(res)
-	if err != nil {
-		return "", false, err
-	}
-
-	nextScrollID := gjson.Get(string(bodyBytes), "_scroll_id").String()
-	numberOfHits := gjson.Get(string(bodyBytes), "hits.hits.#")
-	isDone := numberOfHits.Int() == 0
-
-	if isDone {
-		defer func() {
-			errC := esc.clearScroll(scrollID)
-			if errC != nil {
-				log.Warn("cannot clear scroll", "error", errC)
-			}
-		}()
-	}
-
-	err = json.Unmarshal(bodyBytes, response)
-	if err != nil {
-		return "", false, err
-	}
-
-	return nextScrollID, isDone, nil
-}
-
 func logExecutionTime(start time.Time, message string) {
 	log.Debug(message, "duration in seconds", time.Since(start).Seconds())
 }
@@ -0,0 +1,81 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+type pointInTimeResponse struct {
+	ID string `json:"id"`
+}
+
+// OpenPointInTime will open a point in time for the provided index, so the index can be read page by page while it
+// is being written
+func (esc *esClient) OpenPointInTime(index string, keepAlive string) (string, error) {
+	res, err := esc.client.OpenPointInTime(
+		esc.client.OpenPointInTime.WithIndex(index),
+		esc.client.OpenPointInTime.WithKeepAlive(keepAlive),
+	)
+	if err != nil {
+		return "", err
+	}
+
+	bodyBytes, err := getBytesFromResponse(res)
+	if err != nil {
+		return "", err
+	}
+
+	response := &pointInTimeResponse{}
+	err = json.Unmarshal(bodyBytes, response)
+	if err != nil {
+		return "", err
+	}
+	if response.ID == "" {
+		return "", fmt.Errorf("empty point in time id for index %s", index)
+	}
+
+	return response.ID, nil
+}
+
+// ClosePointInTime will release the resources held by the provided point in time
+func (esc *esClient) ClosePointInTime(id string) error {
+	body, err := json.Marshal(&pointInTimeResponse{ID: id})
+	if err != nil {
+		return err
+	}
+
+	res, err := esc.client.ClosePointInTime(
+		esc.client.ClosePointInTime.WithBody(bytes.NewBuffer(body)),
+	)
+	if err != nil {
+		return err
+	}
+	defer closeBody(res)
+
+	if res.IsError() {
+		return fmt.Errorf("error response: %s", res)
+	}
+
+	return nil
+}
+
+// DoSearchRequest will do a search request without an index, used for the requests in a point in time
+func (esc *esClient) DoSearchRequest(body []byte, response interface{}) error {
+	defer logExecutionTime(time.Now(), "esClient.DoSearchRequest")
+
+	res, err := esc.client.Search(
+		esc.client.Search.WithBody(bytes.NewBuffer(body)),
+	)
+	if err != nil {
+		return err
+	}
+
+	bodyBytes, err := getBytesFromResponse(res)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(bodyBytes, response)
+}
@@ -14,9 +14,24 @@ type Config struct {
 	Compare struct {
 		BlockchainStartTime  int64    `toml:"blockchain-start-time"`
 		NumParallelReads     int      `toml:"num-parallel-reads"`
+		NumSlices            int      `toml:"num-slices"`
 		IndicesWithTimestamp []string `toml:"indices-with-timestamp"`
 		IndicesNoTimestamp   []string `toml:"indices-no-timestamp"`
+		// IgnoredFields holds, for every index, the paths of the fields that are not compared
+		IgnoredFields map[string][]string `toml:"ignored-fields"`
+		// CheckpointFile holds the path of the file where the compared intervals are saved
+		CheckpointFile string `toml:"checkpoint-file"`
 	} `toml:"compare"`
+	Repair struct {
+		Direction             string `toml:"direction"`
+		DeleteExtraDocuments  bool   `toml:"delete-extra-documents"`
+		BulkSize              int    `toml:"bulk-size"`
+		MaxDocumentsPerSecond int    `toml:"max-documents-per-second"`
+	} `toml:"repair"`
+	Report struct {
+		FilePath string   `toml:"file-path"`
+		Formats  []string `toml:"formats"`
+	} `toml:"report"`
 	Logs struct {
 		LogFileLifeSpanInMB  int    `toml:"log-file-life-span-in-mb"`
 		LogFileLifeSpanInSec int    `toml:"log-file-life-span-in-sec"`
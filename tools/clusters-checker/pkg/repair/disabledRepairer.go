@@ -0,0 +1,40 @@
+package repair
+
+import "encoding/json"
+
+type disabledRepairer struct{}
+
+// NewDisabledRepairer creates a repairer that leaves the clusters untouched, used when the repair mode is not enabled
+func NewDisabledRepairer() *disabledRepairer {
+	return &disabledRepairer{}
+}
+
+// RepairMissingDocument does nothing
+func (dr *disabledRepairer) RepairMissingDocument(_ string, _ string, _ string, _ json.RawMessage) error {
+	return nil
+}
+
+// RepairDifferentDocument does nothing
+func (dr *disabledRepairer) RepairDifferentDocument(_ string, _ string, _, _ json.RawMessage) error {
+	return nil
+}
+
+// Flush does nothing
+func (dr *disabledRepairer) Flush() error {
+	return nil
+}
+
+// NumCopied returns 0
+func (dr *disabledRepairer) NumCopied() uint64 {
+	return 0
+}
+
+// NumDeleted returns 0
+func (dr *disabledRepairer) NumDeleted() uint64 {
+	return 0
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (dr *disabledRepairer) IsInterfaceNil() bool {
+	return dr == nil
+}
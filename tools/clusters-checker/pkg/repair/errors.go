@@ -0,0 +1,12 @@
+package repair
+
+import "errors"
+
+// ErrNilElasticClient signals that a nil elastic client was provided
+var ErrNilElasticClient = errors.New("nil elastic client")
+
+// ErrUnknownDirection signals that an unknown repair direction was provided
+var ErrUnknownDirection = errors.New("unknown repair direction")
+
+// ErrInvalidBulkSize signals that the provided bulk size is not positive
+var ErrInvalidBulkSize = errors.New("invalid bulk size")
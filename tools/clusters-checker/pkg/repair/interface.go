@@ -0,0 +1,9 @@
+package repair
+
+import "bytes"
+
+// ElasticClient defines what a client used to read the documents to be deleted and to write the repaired documents should do
+type ElasticClient interface {
+	DoBulkRequest(buff *bytes.Buffer, index string) error
+	DoGetRequest(index string, body []byte, response interface{}, size int) error
+}
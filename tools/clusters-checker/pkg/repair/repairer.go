@@ -0,0 +1,309 @@
+package repair
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/kalyan3104/k-chain-es-indexer-go/tools/clusters-checker/pkg/report"
+	logger "github.com/kalyan3104/k-chain-logger-go"
+)
+
+const (
+	// SourceToDestination repairs the destination cluster with the documents from the source cluster
+	SourceToDestination = "source-to-destination"
+	// DestinationToSource repairs the source cluster with the documents from the destination cluster
+	DestinationToSource = "destination-to-source"
+)
+
+var log = logger.GetOrCreate("clusters-checker/pkg/repair")
+
+// ArgsRepairer holds the arguments needed to create a repairer
+type ArgsRepairer struct {
+	SourceClient          ElasticClient
+	DestinationClient     ElasticClient
+	Direction             string
+	DeleteExtraDocuments  bool
+	DryRun                bool
+	BulkSize              int
+	MaxDocumentsPerSecond int
+}
+
+type repairer struct {
+	mut                   sync.Mutex
+	originClient          ElasticClient
+	targetClient          ElasticClient
+	targetIsDestination   bool
+	deleteExtraDocuments  bool
+	dryRun                bool
+	bulkSize              int
+	maxDocumentsPerSecond int
+	pending               map[string]*pendingOperations
+	nextBulkTime          time.Time
+	numCopied             uint64
+	numDeleted            uint64
+}
+
+type pendingOperations struct {
+	copies    *bytes.Buffer
+	numCopies int
+	deletes   []string
+}
+
+type idsResponse struct {
+	Hits struct {
+		Hits []struct {
+			ID string `json:"_id"`
+		} `json:"hits"`
+	} `json:"hits"`
+}
+
+// NewRepairer creates a component that brings the target cluster, the one chosen by the direction, in sync with the
+// origin cluster. The documents are written in bulks of the provided size, the number of documents written every
+// second being limited when a maximum is provided. In dry run mode the operations are only logged
+func NewRepairer(args ArgsRepairer) (*repairer, error) {
+	if args.SourceClient == nil || args.DestinationClient == nil {
+		return nil, ErrNilElasticClient
+	}
+	if args.BulkSize < 1 {
+		return nil, ErrInvalidBulkSize
+	}
+
+	originClient, targetClient := args.SourceClient, args.DestinationClient
+	switch args.Direction {
+	case SourceToDestination:
+	case DestinationToSource:
+		originClient, targetClient = args.DestinationClient, args.SourceClient
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnknownDirection, args.Direction)
+	}
+
+	return &repairer{
+		originClient:          originClient,
+		targetClient:          targetClient,
+		targetIsDestination:   args.Direction == SourceToDestination,
+		deleteExtraDocuments:  args.DeleteExtraDocuments,
+		dryRun:                args.DryRun,
+		bulkSize:              args.BulkSize,
+		maxDocumentsPerSecond: args.MaxDocumentsPerSecond,
+		pending:               make(map[string]*pendingOperations),
+	}, nil
+}
+
+// RepairMissingDocument will copy the document to the target cluster if it lacks it, or delete it from the target
+// cluster if it exists only there and the extra documents should be deleted
+func (r *repairer) RepairMissingDocument(index string, id string, kind string, rawData json.RawMessage) error {
+	existsOnlyInTarget := (kind == report.MissingInSource) == r.targetIsDestination
+	if !existsOnlyInTarget {
+		return r.addCopy(index, id, rawData)
+	}
+	if !r.deleteExtraDocuments {
+		return nil
+	}
+
+	return r.addDelete(index, id)
+}
+
+// RepairDifferentDocument will overwrite the document from the target cluster with the one from the origin cluster
+func (r *repairer) RepairDifferentDocument(index string, id string, rawDataSource, rawDataDestination json.RawMessage) error {
+	if r.targetIsDestination {
+		return r.addCopy(index, id, rawDataSource)
+	}
+
+	return r.addCopy(index, id, rawDataDestination)
+}
+
+func (r *repairer) addCopy(index string, id string, rawData json.RawMessage) error {
+	r.mut.Lock()
+	defer r.mut.Unlock()
+
+	r.numCopied++
+	if r.dryRun {
+		log.Info("repair dry run: would copy document", "index", index, "id", id)
+		return nil
+	}
+
+	idBytes, err := json.Marshal(id)
+	if err != nil {
+		return err
+	}
+
+	operations := r.getPendingOperationsUnprotected(index)
+	operations.copies.WriteString(fmt.Sprintf(`{"index":{"_index":"%s","_id":%s}}`+"\n", index, idBytes))
+	operations.copies.Write(rawData)
+	operations.copies.WriteString("\n")
+	operations.numCopies++
+
+	return r.flushIfFullUnprotected(index, operations)
+}
+
+func (r *repairer) addDelete(index string, id string) error {
+	r.mut.Lock()
+	defer r.mut.Unlock()
+
+	operations := r.getPendingOperationsUnprotected(index)
+	operations.deletes = append(operations.deletes, id)
+
+	return r.flushIfFullUnprotected(index, operations)
+}
+
+func (r *repairer) getPendingOperationsUnprotected(index string) *pendingOperations {
+	operations, found := r.pending[index]
+	if !found {
+		operations = &pendingOperations{copies: &bytes.Buffer{}}
+		r.pending[index] = operations
+	}
+
+	return operations
+}
+
+func (r *repairer) flushIfFullUnprotected(index string, operations *pendingOperations) error {
+	if operations.numCopies+len(operations.deletes) < r.bulkSize {
+		return nil
+	}
+
+	return r.flushIndexUnprotected(index)
+}
+
+// Flush will write all the pending operations
+func (r *repairer) Flush() error {
+	r.mut.Lock()
+	defer r.mut.Unlock()
+
+	for index := range r.pending {
+		err := r.flushIndexUnprotected(index)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *repairer) flushIndexUnprotected(index string) error {
+	operations, found := r.pending[index]
+	if !found {
+		return nil
+	}
+
+	deletes, err := r.filterDocumentsMissingFromOrigin(index, operations.deletes)
+	if err != nil {
+		return err
+	}
+
+	r.numDeleted += uint64(len(deletes))
+	if r.dryRun {
+		for _, id := range deletes {
+			log.Info("repair dry run: would delete document", "index", index, "id", id)
+		}
+		delete(r.pending, index)
+		return nil
+	}
+
+	buff := operations.copies
+	for _, id := range deletes {
+		idBytes, errMarshal := json.Marshal(id)
+		if errMarshal != nil {
+			return errMarshal
+		}
+
+		buff.WriteString(fmt.Sprintf(`{"delete":{"_index":"%s","_id":%s}}`+"\n", index, idBytes))
+	}
+
+	numOperations := operations.numCopies + len(deletes)
+	delete(r.pending, index)
+	if numOperations == 0 {
+		return nil
+	}
+
+	r.waitForRateLimitUnprotected(numOperations)
+
+	err = r.targetClient.DoBulkRequest(buff, index)
+	if err != nil {
+		return err
+	}
+
+	log.Debug("repair: bulk written", "index", index, "num copied", operations.numCopies, "num deleted", len(deletes))
+
+	return nil
+}
+
+// filterDocumentsMissingFromOrigin keeps only the documents which are still missing from the origin cluster. The
+// intervals of the two clusters are read independently, so a document whose timestamp differs between the clusters is
+// reported as missing from both sides in different intervals and must not be deleted
+func (r *repairer) filterDocumentsMissingFromOrigin(index string, ids []string) ([]string, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	query, err := json.Marshal(map[string]interface{}{
+		"query": map[string]interface{}{
+			"ids": map[string]interface{}{
+				"values": ids,
+			},
+		},
+		"_source": false,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	response := &idsResponse{}
+	err = r.originClient.DoGetRequest(index, query, response, len(ids))
+	if err != nil {
+		return nil, err
+	}
+
+	foundInOrigin := make(map[string]struct{}, len(response.Hits.Hits))
+	for _, hit := range response.Hits.Hits {
+		foundInOrigin[hit.ID] = struct{}{}
+	}
+
+	missingIDs := make([]string, 0, len(ids))
+	for _, id := range ids {
+		_, found := foundInOrigin[id]
+		if !found {
+			missingIDs = append(missingIDs, id)
+		}
+	}
+
+	return missingIDs, nil
+}
+
+// waitForRateLimitUnprotected delays the bulk, so the documents written in average every second stay under the limit
+func (r *repairer) waitForRateLimitUnprotected(numDocuments int) {
+	if r.maxDocumentsPerSecond <= 0 {
+		return
+	}
+
+	now := time.Now()
+	if r.nextBulkTime.After(now) {
+		time.Sleep(r.nextBulkTime.Sub(now))
+		now = r.nextBulkTime
+	}
+
+	r.nextBulkTime = now.Add(time.Duration(numDocuments) * time.Second / time.Duration(r.maxDocumentsPerSecond))
+}
+
+// NumCopied returns the number of documents copied, or which would be copied in dry run mode
+func (r *repairer) NumCopied() uint64 {
+	r.mut.Lock()
+	defer r.mut.Unlock()
+
+	return r.numCopied
+}
+
+// NumDeleted returns the number of documents deleted, or which would be deleted in dry run mode
+func (r *repairer) NumDeleted() uint64 {
+	r.mut.Lock()
+	defer r.mut.Unlock()
+
+	return r.numDeleted
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (r *repairer) IsInterfaceNil() bool {
+	return r == nil
+}
@@ -0,0 +1,225 @@
+package repair
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/kalyan3104/k-chain-es-indexer-go/tools/clusters-checker/pkg/report"
+)
+
+// elasticClientStub records the bulks and answers the ids query with the documents it holds
+type elasticClientStub struct {
+	existingIDs []string
+	bulks       []string
+	idsQueries  []string
+}
+
+func (ecs *elasticClientStub) DoBulkRequest(buff *bytes.Buffer, _ string) error {
+	ecs.bulks = append(ecs.bulks, buff.String())
+	return nil
+}
+
+func (ecs *elasticClientStub) DoGetRequest(_ string, body []byte, response interface{}, _ int) error {
+	ecs.idsQueries = append(ecs.idsQueries, string(body))
+
+	hits := make([]string, 0, len(ecs.existingIDs))
+	for _, id := range ecs.existingIDs {
+		if strings.Contains(string(body), `"`+id+`"`) {
+			hits = append(hits, `{"_id":"`+id+`"}`)
+		}
+	}
+
+	return json.Unmarshal([]byte(`{"hits":{"hits":[`+strings.Join(hits, ",")+`]}}`), response)
+}
+
+func createMockArgsRepairer(source, destination *elasticClientStub) ArgsRepairer {
+	return ArgsRepairer{
+		SourceClient:      source,
+		DestinationClient: destination,
+		Direction:         SourceToDestination,
+		BulkSize:          100,
+	}
+}
+
+func TestNewRepairer(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		updateArgs  func(args *ArgsRepairer)
+		expectedErr error
+	}{
+		{name: "nil source client", updateArgs: func(args *ArgsRepairer) { args.SourceClient = nil }, expectedErr: ErrNilElasticClient},
+		{name: "nil destination client", updateArgs: func(args *ArgsRepairer) { args.DestinationClient = nil }, expectedErr: ErrNilElasticClient},
+		{name: "zero bulk size", updateArgs: func(args *ArgsRepairer) { args.BulkSize = 0 }, expectedErr: ErrInvalidBulkSize},
+		{name: "unknown direction", updateArgs: func(args *ArgsRepairer) { args.Direction = "both" }, expectedErr: ErrUnknownDirection},
+		{name: "should work", updateArgs: func(args *ArgsRepairer) {}},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			args := createMockArgsRepairer(&elasticClientStub{}, &elasticClientStub{})
+			tt.updateArgs(&args)
+
+			r, err := NewRepairer(args)
+			if !errors.Is(err, tt.expectedErr) {
+				t.Fatalf("expected error %v, got %v", tt.expectedErr, err)
+			}
+			if (err == nil) == r.IsInterfaceNil() {
+				t.Fatal("a repairer should be returned only without error")
+			}
+		})
+	}
+}
+
+func TestRepairer_SourceToDestinationShouldCopyToDestination(t *testing.T) {
+	t.Parallel()
+
+	source, destination := &elasticClientStub{}, &elasticClientStub{}
+	r, _ := NewRepairer(createMockArgsRepairer(source, destination))
+
+	err := r.RepairDifferentDocument("blocks", "h1", json.RawMessage(`{"nonce":1}`), json.RawMessage(`{"nonce":2}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = r.RepairMissingDocument("blocks", `h"2`, report.MissingInDestination, json.RawMessage(`{"nonce":3}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// without the delete flag, the documents found only in the destination are kept
+	err = r.RepairMissingDocument("blocks", "h3", report.MissingInSource, json.RawMessage(`{"nonce":4}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(destination.bulks) != 0 {
+		t.Fatal("nothing should be written before the flush")
+	}
+
+	err = r.Flush()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedBulk := `{"index":{"_index":"blocks","_id":"h1"}}` + "\n" + `{"nonce":1}` + "\n" +
+		`{"index":{"_index":"blocks","_id":"h\"2"}}` + "\n" + `{"nonce":3}` + "\n"
+	if len(destination.bulks) != 1 || destination.bulks[0] != expectedBulk {
+		t.Fatalf("unexpected bulks %q", destination.bulks)
+	}
+	if len(source.bulks) != 0 || len(source.idsQueries) != 0 {
+		t.Fatal("the source cluster should not be touched")
+	}
+	if r.NumCopied() != 2 || r.NumDeleted() != 0 {
+		t.Fatalf("unexpected counters: copied %d, deleted %d", r.NumCopied(), r.NumDeleted())
+	}
+}
+
+func TestRepairer_DestinationToSourceShouldCopyToSource(t *testing.T) {
+	t.Parallel()
+
+	source, destination := &elasticClientStub{}, &elasticClientStub{}
+	args := createMockArgsRepairer(source, destination)
+	args.Direction = DestinationToSource
+	r, _ := NewRepairer(args)
+
+	_ = r.RepairDifferentDocument("blocks", "h1", json.RawMessage(`{"nonce":1}`), json.RawMessage(`{"nonce":2}`))
+	_ = r.RepairMissingDocument("blocks", "h2", report.MissingInSource, json.RawMessage(`{"nonce":3}`))
+	_ = r.RepairMissingDocument("blocks", "h3", report.MissingInDestination, json.RawMessage(`{"nonce":4}`))
+	err := r.Flush()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedBulk := `{"index":{"_index":"blocks","_id":"h1"}}` + "\n" + `{"nonce":2}` + "\n" +
+		`{"index":{"_index":"blocks","_id":"h2"}}` + "\n" + `{"nonce":3}` + "\n"
+	if len(source.bulks) != 1 || source.bulks[0] != expectedBulk {
+		t.Fatalf("unexpected bulks %q", source.bulks)
+	}
+	if len(destination.bulks) != 0 {
+		t.Fatal("the destination cluster should not be written")
+	}
+}
+
+func TestRepairer_DeleteExtraDocumentsShouldDeleteOnlyTheDocumentsStillMissingFromTheOrigin(t *testing.T) {
+	t.Parallel()
+
+	// h2 was reported as missing from the source in a slice, but the source holds it with another timestamp
+	source := &elasticClientStub{existingIDs: []string{"h2"}}
+	destination := &elasticClientStub{}
+	args := createMockArgsRepairer(source, destination)
+	args.DeleteExtraDocuments = true
+	r, _ := NewRepairer(args)
+
+	_ = r.RepairMissingDocument("blocks", "h1", report.MissingInSource, json.RawMessage(`{"nonce":1}`))
+	_ = r.RepairMissingDocument("blocks", "h2", report.MissingInSource, json.RawMessage(`{"nonce":2}`))
+	err := r.Flush()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(source.idsQueries) != 1 || !strings.Contains(source.idsQueries[0], `"values":["h1","h2"]`) {
+		t.Fatalf("the origin should be asked for the documents to delete, got %v", source.idsQueries)
+	}
+	expectedBulk := `{"delete":{"_index":"blocks","_id":"h1"}}` + "\n"
+	if len(destination.bulks) != 1 || destination.bulks[0] != expectedBulk {
+		t.Fatalf("unexpected bulks %q", destination.bulks)
+	}
+	if r.NumDeleted() != 1 {
+		t.Fatalf("expected 1 deleted document, got %d", r.NumDeleted())
+	}
+}
+
+func TestRepairer_DryRunShouldNotWrite(t *testing.T) {
+	t.Parallel()
+
+	source, destination := &elasticClientStub{}, &elasticClientStub{}
+	args := createMockArgsRepairer(source, destination)
+	args.DeleteExtraDocuments = true
+	args.DryRun = true
+	r, _ := NewRepairer(args)
+
+	_ = r.RepairDifferentDocument("blocks", "h1", json.RawMessage(`{"nonce":1}`), json.RawMessage(`{"nonce":2}`))
+	_ = r.RepairMissingDocument("blocks", "h2", report.MissingInSource, json.RawMessage(`{"nonce":3}`))
+	err := r.Flush()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(source.bulks) != 0 || len(destination.bulks) != 0 {
+		t.Fatal("a dry run should not write")
+	}
+	if r.NumCopied() != 1 || r.NumDeleted() != 1 {
+		t.Fatalf("unexpected counters: copied %d, deleted %d", r.NumCopied(), r.NumDeleted())
+	}
+}
+
+func TestRepairer_FullBulkShouldBeWrittenBeforeTheFlush(t *testing.T) {
+	t.Parallel()
+
+	source, destination := &elasticClientStub{}, &elasticClientStub{}
+	args := createMockArgsRepairer(source, destination)
+	args.BulkSize = 2
+	r, _ := NewRepairer(args)
+
+	_ = r.RepairMissingDocument("blocks", "h1", report.MissingInDestination, json.RawMessage(`{"nonce":1}`))
+	if len(destination.bulks) != 0 {
+		t.Fatal("a bulk which is not full should wait for the flush")
+	}
+	_ = r.RepairMissingDocument("blocks", "h2", report.MissingInDestination, json.RawMessage(`{"nonce":2}`))
+	if len(destination.bulks) != 1 {
+		t.Fatalf("a full bulk should be written, got %d bulks", len(destination.bulks))
+	}
+
+	err := r.Flush()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(destination.bulks) != 1 {
+		t.Fatal("the flush should not write an empty bulk")
+	}
+}
@@ -0,0 +1,12 @@
+package report
+
+import "errors"
+
+// ErrEmptyReportFilePath signals that the path of the report files was not provided
+var ErrEmptyReportFilePath = errors.New("empty report file path")
+
+// ErrNoReportFormat signals that no report format was provided
+var ErrNoReportFormat = errors.New("no report format")
+
+// ErrUnknownReportFormat signals that an unknown report format was provided
+var ErrUnknownReportFormat = errors.New("unknown report format")
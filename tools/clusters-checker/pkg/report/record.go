@@ -0,0 +1,19 @@
+package report
+
+const (
+	// MissingInDestination is the kind of the records for the documents found only in the source cluster
+	MissingInDestination = "missing-in-destination"
+	// MissingInSource is the kind of the records for the documents found only in the destination cluster
+	MissingInSource = "missing-in-source"
+	// Different is the kind of the records for the documents found in both clusters, but with different content
+	Different = "different"
+)
+
+// Record holds a document that is not the same in the two clusters. The paths of the different values are written in
+// the gjson path syntax, "$" being the path of the whole document
+type Record struct {
+	Index string   `json:"index"`
+	ID    string   `json:"id"`
+	Kind  string   `json:"kind"`
+	Paths []string `json:"paths,omitempty"`
+}
@@ -0,0 +1,180 @@
+package report
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+)
+
+const (
+	// FormatJSONLines writes a JSON object per line
+	FormatJSONLines = "jsonl"
+	// FormatCSV writes a comma separated line per record, the changed paths being separated by semicolons
+	FormatCSV = "csv"
+
+	reportFilePermissions = 0644
+	pathsSeparator        = ";"
+)
+
+var csvHeader = []string{"index", "id", "kind", "paths"}
+
+// ArgsReportWriter holds the arguments needed to create a report writer
+type ArgsReportWriter struct {
+	FilePath string
+	Formats  []string
+	// Append keeps the records of a previous run, used when a comparison is resumed
+	Append bool
+}
+
+type reportWriter struct {
+	mut        sync.Mutex
+	files      []*os.File
+	jsonFile   *os.File
+	csvWriter  *csv.Writer
+	numRecords uint64
+}
+
+// NewReportWriter creates a writer that saves the records in a file for every one of the provided formats, named
+// after the file path and having the format as extension. The writer is concurrent safe
+func NewReportWriter(args ArgsReportWriter) (*reportWriter, error) {
+	if args.FilePath == "" {
+		return nil, ErrEmptyReportFilePath
+	}
+	if len(args.Formats) == 0 {
+		return nil, ErrNoReportFormat
+	}
+
+	rw := &reportWriter{}
+	for _, format := range args.Formats {
+		err := rw.openFile(args.FilePath, format, args.Append)
+		if err != nil {
+			_ = rw.Close()
+			return nil, err
+		}
+	}
+
+	return rw, nil
+}
+
+func (rw *reportWriter) openFile(filePath string, format string, appendRecords bool) error {
+	if format != FormatJSONLines && format != FormatCSV {
+		return fmt.Errorf("%w: %s", ErrUnknownReportFormat, format)
+	}
+
+	flags := os.O_CREATE | os.O_TRUNC | os.O_WRONLY
+	if appendRecords {
+		flags = os.O_CREATE | os.O_APPEND | os.O_WRONLY
+	}
+
+	file, err := os.OpenFile(filePath+"."+format, flags, reportFilePermissions)
+	if err != nil {
+		return err
+	}
+	rw.files = append(rw.files, file)
+
+	if format == FormatJSONLines {
+		rw.jsonFile = file
+		return nil
+	}
+
+	rw.csvWriter = csv.NewWriter(file)
+
+	fileInfo, err := file.Stat()
+	if err != nil {
+		return err
+	}
+	if fileInfo.Size() > 0 {
+		return nil
+	}
+
+	return rw.csvWriter.Write(csvHeader)
+}
+
+// WriteRecord will append the provided record to the report files
+func (rw *reportWriter) WriteRecord(record *Record) error {
+	rw.mut.Lock()
+	defer rw.mut.Unlock()
+
+	rw.numRecords++
+
+	if rw.jsonFile != nil {
+		recordBytes, err := json.Marshal(record)
+		if err != nil {
+			return err
+		}
+
+		_, err = rw.jsonFile.Write(append(recordBytes, '\n'))
+		if err != nil {
+			return err
+		}
+	}
+
+	if rw.csvWriter != nil {
+		err := rw.csvWriter.Write([]string{record.Index, record.ID, record.Kind, strings.Join(record.Paths, pathsSeparator)})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// NumRecords returns the number of records written so far
+func (rw *reportWriter) NumRecords() uint64 {
+	rw.mut.Lock()
+	defer rw.mut.Unlock()
+
+	return rw.numRecords
+}
+
+// Flush will write the buffered records and sync the report files, so the records written so far are kept after a crash
+func (rw *reportWriter) Flush() error {
+	rw.mut.Lock()
+	defer rw.mut.Unlock()
+
+	if rw.csvWriter != nil {
+		rw.csvWriter.Flush()
+		err := rw.csvWriter.Error()
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, file := range rw.files {
+		err := file.Sync()
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Close will flush the records and close the report files
+func (rw *reportWriter) Close() error {
+	rw.mut.Lock()
+	defer rw.mut.Unlock()
+
+	var lastErr error
+	if rw.csvWriter != nil {
+		rw.csvWriter.Flush()
+		lastErr = rw.csvWriter.Error()
+	}
+
+	for _, file := range rw.files {
+		err := file.Close()
+		if err != nil {
+			lastErr = err
+		}
+	}
+
+	return lastErr
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (rw *reportWriter) IsInterfaceNil() bool {
+	return rw == nil
+}
@@ -0,0 +1,65 @@
+package report
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+)
+
+func TestReportWriter_WriteRecord(t *testing.T) {
+	t.Parallel()
+
+	filePath := filepath.Join(t.TempDir(), "report")
+	writer, err := NewReportWriter(ArgsReportWriter{
+		FilePath: filePath,
+		Formats:  []string{FormatJSONLines, FormatCSV},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	records := []*Record{
+		{Index: "transactions", ID: "h1", Kind: MissingInDestination},
+		{Index: "transactions", ID: "h2", Kind: Different, Paths: []string{"nonce", `receivers.1`}},
+		{Index: "transactions", ID: "h3", Kind: Different, Paths: []string{"$"}},
+	}
+	for _, record := range records {
+		err = writer.WriteRecord(record)
+		if err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+	}
+	err = writer.Close()
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	expectedJSONLines := `{"index":"transactions","id":"h1","kind":"missing-in-destination"}
+{"index":"transactions","id":"h2","kind":"different","paths":["nonce","receivers.1"]}
+{"index":"transactions","id":"h3","kind":"different","paths":["$"]}
+`
+	requireFileContent(t, expectedJSONLines, filePath+"."+FormatJSONLines)
+
+	expectedCSV := `index,id,kind,paths
+transactions,h1,missing-in-destination,
+transactions,h2,different,nonce;receivers.1
+transactions,h3,different,$
+`
+	requireFileContent(t, expectedCSV, filePath+"."+FormatCSV)
+
+	if writer.NumRecords() != uint64(len(records)) {
+		t.Fatalf("expected %d records, got %d", len(records), writer.NumRecords())
+	}
+}
+
+func requireFileContent(t *testing.T, expected string, filePath string) {
+	t.Helper()
+
+	content, err := ioutil.ReadFile(filePath)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if string(content) != expected {
+		t.Fatalf("expected the content\n%s\ngot\n%s", expected, string(content))
+	}
+}
@@ -0,0 +1,276 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/kalyan3104/k-chain-es-indexer-go/tools/index-modifier/pkg/alterindex"
+	"github.com/kalyan3104/k-chain-es-indexer-go/tools/index-modifier/pkg/config"
+	"github.com/kalyan3104/k-chain-es-indexer-go/tools/index-modifier/pkg/modifiers"
+	logger "github.com/kalyan3104/k-chain-logger-go"
+	"github.com/urfave/cli"
+)
+
+var (
+	log = logger.GetOrCreate("main")
+
+	configFile = cli.StringFlag{
+		Name:  "config-file",
+		Value: "config.json",
+	}
+	sourceURL = cli.StringFlag{
+		Name:  "source-url",
+		Usage: "The URL of the cluster the documents are read from. Overrides the value from the config file",
+	}
+	sourceUsername = cli.StringFlag{
+		Name:  "source-username",
+		Usage: "The username of the source cluster. Overrides the value from the config file",
+	}
+	sourcePassword = cli.StringFlag{
+		Name:  "source-password",
+		Usage: "The password of the source cluster. Overrides the value from the config file",
+	}
+	destinationURL = cli.StringFlag{
+		Name: "destination-url",
+		Usage: "The URL of the cluster the modified documents are written to. Overrides the value from the config " +
+			"file. If empty, the source cluster is used",
+	}
+	destinationUsername = cli.StringFlag{
+		Name:  "destination-username",
+		Usage: "The username of the destination cluster. Overrides the value from the config file",
+	}
+	destinationPassword = cli.StringFlag{
+		Name:  "destination-password",
+		Usage: "The password of the destination cluster. Overrides the value from the config file",
+	}
+	readIndex = cli.StringFlag{
+		Name:  "read-index",
+		Usage: "The index the documents are read from. Overrides the value from the config file",
+	}
+	writeIndex = cli.StringFlag{
+		Name:  "write-index",
+		Usage: "The index the modified documents are written to. Overrides the value from the config file",
+	}
+	query = cli.StringFlag{
+		Name: "query",
+		Usage: "The JSON query which filters the documents to be modified, as the value of the \"query\" field of a " +
+			"search. Overrides the value from the config file",
+	}
+	modifierName = cli.StringFlag{
+		Name:  "modifier",
+		Usage: "The name of the modifier. Overrides the value from the config file",
+	}
+	listModifiers = cli.BoolFlag{
+		Name:  "list-modifiers",
+		Usage: "If set, the tool will only print the names of the available modifiers",
+	}
+	dryRun = cli.BoolFlag{
+		Name:  "dry-run",
+		Usage: "If set, the documents are modified, but not written in the destination cluster",
+	}
+	sampleSize = cli.IntFlag{
+		Name:  "sample",
+		Usage: "The number of modified documents printed, as bulk actions, to the standard output",
+	}
+	maxDocumentsPerSecond = cli.IntFlag{
+		Name:  "max-documents-per-second",
+		Usage: "The maximum number of documents modified per second. Overrides the value from the config file",
+	}
+	checkpointFile = cli.StringFlag{
+		Name: "checkpoint-file",
+		Usage: "The file where the progress is saved, so an interrupted modification can resume. Overrides the value " +
+			"from the config file",
+	}
+
+	logLevel = cli.StringFlag{
+		Name: "log-level",
+		Usage: "This flag specifies the logger `level(s)`. It can contain multiple comma-separated value. For example" +
+			", if set to *:INFO the logs for all packages will have the INFO level. However, if set to *:INFO,api:DEBUG" +
+			" the logs for all packages will have the INFO level, excepting the api package which will receive a DEBUG" +
+			" log level.",
+		Value: "*:" + logger.LogInfo.String(),
+	}
+)
+
+func main() {
+	app := cli.NewApp()
+	app.Name = "Elasticsearch index modifier"
+	app.Version = "v1.0.0"
+	app.Usage = "This tool reads the documents of an index, modifies them with a named modifier and writes them in an index"
+	app.Flags = []cli.Flag{
+		configFile,
+		sourceURL,
+		sourceUsername,
+		sourcePassword,
+		destinationURL,
+		destinationUsername,
+		destinationPassword,
+		readIndex,
+		writeIndex,
+		query,
+		modifierName,
+		listModifiers,
+		dryRun,
+		sampleSize,
+		maxDocumentsPerSecond,
+		checkpointFile,
+		logLevel,
+	}
+	app.Authors = []cli.Author{
+		{
+			Name:  "The kalyan Team",
+			Email: "contact@kalyan.com",
+		},
+	}
+
+	app.Action = modifyIndex
+	err := app.Run(os.Args)
+	if err != nil {
+		log.Error(err.Error())
+		os.Exit(1)
+	}
+}
+
+func modifyIndex(ctx *cli.Context) error {
+	err := logger.SetLogLevel(ctx.GlobalString(logLevel.Name))
+	if err != nil {
+		return err
+	}
+
+	cfg, err := readConfig(ctx)
+	if err != nil {
+		return fmt.Errorf("%w while reading the config", err)
+	}
+
+	registry, err := createRegistry(cfg)
+	if err != nil {
+		return err
+	}
+
+	if ctx.Bool(listModifiers.Name) {
+		for _, name := range registry.Names() {
+			fmt.Println(name)
+		}
+		return nil
+	}
+
+	modifier, err := registry.Create(cfg.Modifier)
+	if err != nil {
+		return fmt.Errorf("%w while creating the modifier", err)
+	}
+
+	indexModifier, err := alterindex.CreateIndexModifier(cfg.Source, cfg.Destination)
+	if err != nil {
+		return fmt.Errorf("%w while creating the index modifier", err)
+	}
+
+	log.Info("modifying index",
+		"read index", cfg.ReadIndex,
+		"write index", cfg.WriteIndex,
+		"modifier", cfg.Modifier,
+		"dry run", ctx.Bool(dryRun.Name),
+	)
+
+	err = indexModifier.AlterIndex(alterindex.ArgsAlterIndex{
+		ReadIndex:             cfg.ReadIndex,
+		WriteIndex:            cfg.WriteIndex,
+		Query:                 cfg.Query,
+		ModifierName:          cfg.Modifier,
+		Modifier:              modifier,
+		DryRun:                ctx.Bool(dryRun.Name),
+		SampleSize:            ctx.Int(sampleSize.Name),
+		SampleWriter:          os.Stdout,
+		MaxDocumentsPerSecond: cfg.MaxDocumentsPerSecond,
+		CheckpointFilePath:    cfg.Checkpoint.FilePath,
+		SortField:             cfg.Checkpoint.SortField,
+	})
+	if err != nil {
+		return fmt.Errorf("%w while modifying the index", err)
+	}
+
+	log.Info("done")
+
+	return nil
+}
+
+// createRegistry returns the registry of the modifiers implemented by the tool and of the scripted modifiers
+// defined in the config
+func createRegistry(cfg *config.Config) (modifiersRegistry, error) {
+	registry := modifiers.NewDefaultRegistry()
+	for name, scripted := range cfg.ScriptedModifiers {
+		err := registry.Register(name, modifiers.NewScriptedModifierCreator(scripted.Script, scripted.Params))
+		if err != nil {
+			return nil, fmt.Errorf("%w while registering the scripted modifiers", err)
+		}
+	}
+
+	return registry, nil
+}
+
+type modifiersRegistry interface {
+	Create(name string) (modifiers.Modifier, error)
+	Names() []string
+}
+
+func readConfig(ctx *cli.Context) (*config.Config, error) {
+	jsonFile, err := ioutil.ReadFile(ctx.String(configFile.Name))
+	if err != nil {
+		return nil, err
+	}
+	cfg := &config.Config{}
+	err = json.Unmarshal(jsonFile, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	err = applyFlags(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.Destination.URL == "" {
+		cfg.Destination = cfg.Source
+	}
+	if cfg.Source.URL == "" {
+		return nil, errors.New("empty source URL")
+	}
+
+	return cfg, nil
+}
+
+// applyFlags overrides the config values with the flags set in the command line
+func applyFlags(ctx *cli.Context, cfg *config.Config) error {
+	stringFlags := map[string]*string{
+		sourceURL.Name:           &cfg.Source.URL,
+		sourceUsername.Name:      &cfg.Source.Username,
+		sourcePassword.Name:      &cfg.Source.Password,
+		destinationURL.Name:      &cfg.Destination.URL,
+		destinationUsername.Name: &cfg.Destination.Username,
+		destinationPassword.Name: &cfg.Destination.Password,
+		readIndex.Name:           &cfg.ReadIndex,
+		writeIndex.Name:          &cfg.WriteIndex,
+		modifierName.Name:        &cfg.Modifier,
+		checkpointFile.Name:      &cfg.Checkpoint.FilePath,
+	}
+	for name, value := range stringFlags {
+		if ctx.IsSet(name) {
+			*value = ctx.String(name)
+		}
+	}
+
+	if ctx.IsSet(maxDocumentsPerSecond.Name) {
+		cfg.MaxDocumentsPerSecond = ctx.Int(maxDocumentsPerSecond.Name)
+	}
+	if ctx.IsSet(query.Name) {
+		queryBytes := []byte(ctx.String(query.Name))
+		if !json.Valid(queryBytes) {
+			return errors.New("the query is not a valid JSON")
+		}
+		cfg.Query = queryBytes
+	}
+
+	return nil
+}
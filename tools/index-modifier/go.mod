@@ -9,15 +9,18 @@ require (
 	github.com/kalyan3104/k-chain-logger-go v1.0.11
 	github.com/kalyan3104/k-chain-vm-common-go v1.3.34
 	github.com/tidwall/gjson v1.14.0
+	github.com/urfave/cli v1.22.10
 )
 
 require (
 	github.com/btcsuite/btcd/btcutil v1.1.3 // indirect
+	github.com/cpuguy83/go-md2man/v2 v2.0.2 // indirect
 	github.com/denisbrodbeck/machineid v1.0.1 // indirect
 	github.com/gogo/protobuf v1.3.2 // indirect
 	github.com/golang/protobuf v1.5.2 // indirect
 	github.com/mr-tron/base58 v1.2.0 // indirect
 	github.com/pelletier/go-toml v1.9.3 // indirect
+	github.com/russross/blackfriday/v2 v2.1.0 // indirect
 	github.com/tidwall/match v1.1.1 // indirect
 	github.com/tidwall/pretty v1.2.0 // indirect
 	golang.org/x/sys v0.2.0 // indirect
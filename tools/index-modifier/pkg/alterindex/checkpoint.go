@@ -0,0 +1,68 @@
+package alterindex
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+)
+
+const checkpointFilePermissions = 0644
+
+// checkpoint holds the progress of a modification, the documents being read in the order of the sort field
+type checkpoint struct {
+	ReadIndex    string          `json:"readIndex"`
+	WriteIndex   string          `json:"writeIndex"`
+	Modifier     string          `json:"modifier"`
+	SortField    string          `json:"sortField"`
+	LastValue    json.RawMessage `json:"lastValue"`
+	NumDocuments uint64          `json:"numDocuments"`
+}
+
+// loadCheckpoint reads the checkpoint from the provided file. A checkpoint of another modification is ignored, so the
+// modification starts from the beginning
+func loadCheckpoint(filePath string, expected *checkpoint) (*checkpoint, error) {
+	checkpointBytes, err := ioutil.ReadFile(filePath)
+	if errors.Is(err, os.ErrNotExist) {
+		return expected, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	saved := &checkpoint{}
+	err = json.Unmarshal(checkpointBytes, saved)
+	if err != nil {
+		return nil, err
+	}
+
+	sameModification := saved.ReadIndex == expected.ReadIndex && saved.WriteIndex == expected.WriteIndex &&
+		saved.Modifier == expected.Modifier && saved.SortField == expected.SortField
+	if !sameModification {
+		log.Warn("the checkpoint belongs to another modification, it will be ignored",
+			"read index", saved.ReadIndex,
+			"write index", saved.WriteIndex,
+			"modifier", saved.Modifier,
+			"sort field", saved.SortField,
+		)
+		return expected, nil
+	}
+
+	return saved, nil
+}
+
+func saveCheckpoint(filePath string, cp *checkpoint) error {
+	checkpointBytes, err := json.Marshal(cp)
+	if err != nil {
+		return err
+	}
+
+	// the checkpoint replaces the old one only after it is fully written
+	tmpFilePath := filePath + ".tmp"
+	err = ioutil.WriteFile(tmpFilePath, checkpointBytes, checkpointFilePermissions)
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(tmpFilePath, filePath)
+}
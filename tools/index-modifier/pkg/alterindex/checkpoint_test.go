@@ -0,0 +1,126 @@
+package alterindex
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func createTestCheckpoint() *checkpoint {
+	return &checkpoint{
+		ReadIndex:  "transactions-000001",
+		WriteIndex: "transactions-000002",
+		Modifier:   "transactions",
+		SortField:  "timestamp",
+	}
+}
+
+func TestCheckpoint_SaveAndLoad(t *testing.T) {
+	t.Parallel()
+
+	filePath := filepath.Join(t.TempDir(), "checkpoint.json")
+	saved := createTestCheckpoint()
+	saved.LastValue = json.RawMessage(`1700000005`)
+	saved.NumDocuments = 1500
+
+	err := saveCheckpoint(filePath, saved)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	loaded, err := loadCheckpoint(filePath, createTestCheckpoint())
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if !reflect.DeepEqual(saved, loaded) {
+		t.Fatalf("expected the checkpoint %+v, got %+v", saved, loaded)
+	}
+
+	// a second save replaces the first one
+	saved.LastValue = json.RawMessage(`"h2"`)
+	saved.NumDocuments = 3000
+	err = saveCheckpoint(filePath, saved)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	loaded, err = loadCheckpoint(filePath, createTestCheckpoint())
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if !reflect.DeepEqual(saved, loaded) {
+		t.Fatalf("expected the checkpoint %+v, got %+v", saved, loaded)
+	}
+}
+
+func TestCheckpoint_LoadMissingFileShouldStartFromTheBeginning(t *testing.T) {
+	t.Parallel()
+
+	expected := createTestCheckpoint()
+	loaded, err := loadCheckpoint(filepath.Join(t.TempDir(), "missing.json"), expected)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if loaded != expected {
+		t.Fatalf("expected the provided checkpoint, got %+v", loaded)
+	}
+}
+
+func TestCheckpoint_LoadOfAnotherModificationShouldStartFromTheBeginning(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		change func(cp *checkpoint)
+	}{
+		{name: "other read index", change: func(cp *checkpoint) { cp.ReadIndex = "scresults" }},
+		{name: "other write index", change: func(cp *checkpoint) { cp.WriteIndex = "transactions-000003" }},
+		{name: "other modifier", change: func(cp *checkpoint) { cp.Modifier = "scresults" }},
+		{name: "other sort field", change: func(cp *checkpoint) { cp.SortField = "nonce" }},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			filePath := filepath.Join(t.TempDir(), "checkpoint.json")
+			saved := createTestCheckpoint()
+			saved.LastValue = json.RawMessage(`1700000005`)
+			tt.change(saved)
+			err := saveCheckpoint(filePath, saved)
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+
+			expected := createTestCheckpoint()
+			loaded, err := loadCheckpoint(filePath, expected)
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+			if loaded != expected {
+				t.Fatalf("expected the provided checkpoint, got %+v", loaded)
+			}
+		})
+	}
+}
+
+func TestCheckpoint_LoadMalformedFileShouldErr(t *testing.T) {
+	t.Parallel()
+
+	filePath := filepath.Join(t.TempDir(), "checkpoint.json")
+	err := ioutil.WriteFile(filePath, []byte("not json"), checkpointFilePermissions)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	loaded, err := loadCheckpoint(filePath, createTestCheckpoint())
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	if loaded != nil {
+		t.Fatal("expected no checkpoint")
+	}
+}
@@ -15,3 +15,8 @@ type ScrollClient interface {
 type BulkClient interface {
 	DoBulkRequest(buff *bytes.Buffer, index string) error
 }
+
+// Modifier defines what a documents modifier should do
+type Modifier interface {
+	Modify(responseBody []byte) ([]*bytes.Buffer, error)
+}
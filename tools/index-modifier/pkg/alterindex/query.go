@@ -0,0 +1,36 @@
+package alterindex
+
+import "encoding/json"
+
+type object = map[string]interface{}
+
+// prepareQuery returns the body of the search which reads the documents to be modified. When a sort field is provided
+// the documents are read in the ascending order of the field, starting with the last value saved in the checkpoint
+func prepareQuery(filter json.RawMessage, sortField string, lastValue json.RawMessage) ([]byte, error) {
+	var query interface{} = object{"match_all": object{}}
+	if len(filter) > 0 {
+		query = filter
+	}
+	if sortField == "" {
+		return json.Marshal(object{"query": query})
+	}
+
+	if len(lastValue) > 0 {
+		// the documents with the last saved value are read again, as they might not have been all modified
+		query = object{
+			"bool": object{
+				"filter": []interface{}{
+					query,
+					object{"range": object{sortField: object{"gte": lastValue}}},
+				},
+			},
+		}
+	}
+
+	return json.Marshal(object{
+		"query": query,
+		"sort": []interface{}{
+			object{sortField: object{"order": "asc"}},
+		},
+	})
+}
@@ -0,0 +1,55 @@
+package alterindex
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestPrepareQuery(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		filter        json.RawMessage
+		sortField     string
+		lastValue     json.RawMessage
+		expectedQuery string
+	}{
+		{
+			name:          "no filter and no sort field",
+			expectedQuery: `{"query":{"match_all":{}}}`,
+		},
+		{
+			name:          "filter without sort field",
+			filter:        json.RawMessage(`{"term":{"status":"fail"}}`),
+			expectedQuery: `{"query":{"term":{"status":"fail"}}}`,
+		},
+		{
+			name:          "sort field without checkpoint",
+			sortField:     "timestamp",
+			expectedQuery: `{"query":{"match_all":{}},"sort":[{"timestamp":{"order":"asc"}}]}`,
+		},
+		{
+			name:          "sort field with checkpoint",
+			filter:        json.RawMessage(`{"term":{"status":"fail"}}`),
+			sortField:     "timestamp",
+			lastValue:     json.RawMessage(`1700000005`),
+			expectedQuery: `{"query":{"bool":{"filter":[{"term":{"status":"fail"}},{"range":{"timestamp":{"gte":1700000005}}}]}},"sort":[{"timestamp":{"order":"asc"}}]}`,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			query, err := prepareQuery(tt.filter, tt.sortField, tt.lastValue)
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+			if string(query) != tt.expectedQuery {
+				t.Fatalf("expected the query\n%s\ngot\n%s", tt.expectedQuery, string(query))
+			}
+		})
+	}
+}
@@ -2,22 +2,42 @@ package alterindex
 
 import (
 	"bytes"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"math"
 	"time"
 
 	"github.com/elastic/go-elasticsearch/v7"
 	indexerClient "github.com/kalyan3104/k-chain-es-indexer-go/client"
 	"github.com/kalyan3104/k-chain-es-indexer-go/tools/index-modifier/pkg/client"
+	"github.com/kalyan3104/k-chain-es-indexer-go/tools/index-modifier/pkg/config"
 	logger "github.com/kalyan3104/k-chain-logger-go"
-)
-
-const (
-	queryMatchAll = `{"query":{"match_all": {}}}`
+	"github.com/tidwall/gjson"
 )
 
 var log = logger.GetOrCreate("index-modifier/pkg/alterindex")
 
+// ArgsAlterIndex holds the arguments needed to alter an index
+type ArgsAlterIndex struct {
+	ReadIndex    string
+	WriteIndex   string
+	Query        json.RawMessage
+	ModifierName string
+	Modifier     Modifier
+	// DryRun modifies the documents without writing them in the destination cluster
+	DryRun bool
+	// SampleSize is the number of modified documents written to the SampleWriter
+	SampleSize            int
+	SampleWriter          io.Writer
+	MaxDocumentsPerSecond int
+	// CheckpointFilePath, when not empty, saves the progress after every bulk, so an interrupted modification can
+	// resume. The documents are then read in the order of the SortField
+	CheckpointFilePath string
+	SortField          string
+}
+
 type indexModifier struct {
 	scrollClient ScrollClient
 	bulkClient   BulkClient
@@ -31,9 +51,11 @@ func backOff(i int) time.Duration {
 }
 
 // CreateIndexModifier will create a new instance of indexModifier
-func CreateIndexModifier(scrollClientAddress, bulkClientAddress string) (*indexModifier, error) {
+func CreateIndexModifier(source, destination config.ClusterConfig) (*indexModifier, error) {
 	cfg := elasticsearch.Config{
-		Addresses:     []string{scrollClientAddress},
+		Addresses:     []string{source.URL},
+		Username:      source.Username,
+		Password:      source.Password,
 		MaxRetries:    0,
 		RetryBackoff:  backOff,
 		RetryOnStatus: []int{429, 502, 503, 504},
@@ -43,7 +65,9 @@ func CreateIndexModifier(scrollClientAddress, bulkClientAddress string) (*indexM
 		return nil, err
 	}
 
-	cfg.Addresses = []string{bulkClientAddress}
+	cfg.Addresses = []string{destination.URL}
+	cfg.Username = destination.Username
+	cfg.Password = destination.Password
 	bulkClient, err := indexerClient.NewElasticClient(cfg)
 	if err != nil {
 		return nil, err
@@ -55,32 +79,145 @@ func CreateIndexModifier(scrollClientAddress, bulkClientAddress string) (*indexM
 	}, nil
 }
 
-// AlterIndex will alter provided index based on the modifier function
-func (im *indexModifier) AlterIndex(indexRead, indexWrite string, modifier func(responseBytes []byte) ([]*bytes.Buffer, error)) error {
+// AlterIndex will alter the documents of the read index which match the query, based on the provided modifier
+func (im *indexModifier) AlterIndex(args ArgsAlterIndex) error {
+	err := checkArgs(args)
+	if err != nil {
+		return err
+	}
+
+	progress := &checkpoint{
+		ReadIndex:  args.ReadIndex,
+		WriteIndex: args.WriteIndex,
+		Modifier:   args.ModifierName,
+		SortField:  args.SortField,
+	}
+	if args.CheckpointFilePath != "" {
+		progress, err = loadCheckpoint(args.CheckpointFilePath, progress)
+		if err != nil {
+			return fmt.Errorf("%w while loading the checkpoint", err)
+		}
+		if len(progress.LastValue) > 0 {
+			log.Info("resuming from checkpoint", "last value", string(progress.LastValue), "num documents", progress.NumDocuments)
+		}
+	}
+
+	query, err := prepareQuery(args.Query, args.SortField, progress.LastValue)
+	if err != nil {
+		return err
+	}
+
 	count := 0
+	numSampled := 0
+	nextBulkTime := time.Now()
 	handlerFunc := func(responseBytes []byte) error {
 		count++
-		dataBuffers, err := modifier(responseBytes)
+		numDocuments := int(gjson.GetBytes(responseBytes, "hits.hits.#").Int())
+		if numDocuments == 0 {
+			return nil
+		}
+
+		nextBulkTime = waitForRateLimit(nextBulkTime, numDocuments, args.MaxDocumentsPerSecond)
+
+		dataBuffers, err := args.Modifier.Modify(responseBytes)
 		if err != nil {
 			return fmt.Errorf("%w while preparing data for indexing", err)
 		}
 
+		numSampled, err = writeSample(args.SampleWriter, dataBuffers, numSampled, args.SampleSize)
+		if err != nil {
+			return fmt.Errorf("%w while writing the sample", err)
+		}
+
+		progress.NumDocuments += uint64(numDocuments)
+		if args.DryRun {
+			log.Info("dry run, bulk request skipped", "count", count, "num documents", progress.NumDocuments)
+			return nil
+		}
+
 		for i := 0; i < len(dataBuffers); i++ {
-			err = im.bulkClient.DoBulkRequest(dataBuffers[i], indexWrite)
+			err = im.bulkClient.DoBulkRequest(dataBuffers[i], args.WriteIndex)
 			if err != nil {
 				return fmt.Errorf("%w while r.destinationElastic.DoBulkRequest", err)
 			}
 		}
 
-		log.Info("Do bulk request...", "count", count)
+		log.Info("Do bulk request...", "count", count, "num documents", progress.NumDocuments)
 
-		return nil
+		return im.saveProgress(args, progress, responseBytes, numDocuments)
 	}
 
-	err := im.scrollClient.DoScrollRequestAllDocuments(indexRead, []byte(queryMatchAll), handlerFunc)
+	err = im.scrollClient.DoScrollRequestAllDocuments(args.ReadIndex, query, handlerFunc)
 	if err != nil {
 		return fmt.Errorf("%w while r.sourceElastic.DoScrollRequestAllDocuments", err)
 	}
 
 	return nil
 }
+
+func checkArgs(args ArgsAlterIndex) error {
+	if args.Modifier == nil {
+		return errors.New("nil modifier")
+	}
+	if args.ReadIndex == "" || args.WriteIndex == "" {
+		return errors.New("empty read or write index")
+	}
+	if args.CheckpointFilePath != "" && args.SortField == "" {
+		return errors.New("a checkpoint needs a sort field")
+	}
+	if args.SampleSize > 0 && args.SampleWriter == nil {
+		return errors.New("nil sample writer")
+	}
+
+	return nil
+}
+
+// saveProgress saves in the checkpoint the sort value of the last document written
+func (im *indexModifier) saveProgress(args ArgsAlterIndex, progress *checkpoint, responseBytes []byte, numDocuments int) error {
+	if args.CheckpointFilePath == "" {
+		return nil
+	}
+
+	lastValue := gjson.GetBytes(responseBytes, fmt.Sprintf("hits.hits.%d.sort.0", numDocuments-1))
+	if !lastValue.Exists() {
+		return errors.New("the documents are not sorted, cannot save the checkpoint")
+	}
+
+	progress.LastValue = json.RawMessage(lastValue.Raw)
+
+	return saveCheckpoint(args.CheckpointFilePath, progress)
+}
+
+// waitForRateLimit sleeps until the provided documents can be modified without exceeding the maximum number of
+// documents per second and returns the time when the next documents can be modified
+func waitForRateLimit(nextBulkTime time.Time, numDocuments int, maxDocumentsPerSecond int) time.Time {
+	if maxDocumentsPerSecond <= 0 {
+		return nextBulkTime
+	}
+
+	now := time.Now()
+	if nextBulkTime.After(now) {
+		time.Sleep(nextBulkTime.Sub(now))
+	} else {
+		nextBulkTime = now
+	}
+
+	return nextBulkTime.Add(time.Duration(numDocuments) * time.Second / time.Duration(maxDocumentsPerSecond))
+}
+
+// writeSample writes the modified documents, each as its bulk action followed by its body, until the sample size is
+// reached. It returns the number of documents written so far
+func writeSample(writer io.Writer, dataBuffers []*bytes.Buffer, numSampled int, sampleSize int) (int, error) {
+	for _, buff := range dataBuffers {
+		lines := bytes.Split(bytes.TrimRight(buff.Bytes(), "\n"), []byte("\n"))
+		for i := 0; i+1 < len(lines) && numSampled < sampleSize; i += 2 {
+			_, err := fmt.Fprintf(writer, "%s\n%s\n", lines[i], lines[i+1])
+			if err != nil {
+				return numSampled, err
+			}
+			numSampled++
+		}
+	}
+
+	return numSampled, nil
+}
@@ -0,0 +1,34 @@
+package config
+
+import "encoding/json"
+
+// ClusterConfig holds the address and the credentials of a cluster
+type ClusterConfig struct {
+	URL      string `json:"url"`
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+// ScriptedModifierConfig holds a modifier which updates every document with a painless script
+type ScriptedModifierConfig struct {
+	Script string                 `json:"script"`
+	Params map[string]interface{} `json:"params"`
+}
+
+// Config holds the configuration of the index modifier
+type Config struct {
+	Source      ClusterConfig `json:"source"`
+	Destination ClusterConfig `json:"destination"`
+	ReadIndex   string        `json:"read-index"`
+	WriteIndex  string        `json:"write-index"`
+	// Query filters the documents read from the source cluster, being the value of the "query" field of a search
+	Query    json.RawMessage `json:"query"`
+	Modifier string          `json:"modifier"`
+	// ScriptedModifiers holds, by name, the modifiers defined only in the config
+	ScriptedModifiers     map[string]ScriptedModifierConfig `json:"scripted-modifiers"`
+	MaxDocumentsPerSecond int                               `json:"max-documents-per-second"`
+	Checkpoint            struct {
+		FilePath  string `json:"file-path"`
+		SortField string `json:"sort-field"`
+	} `json:"checkpoint"`
+}
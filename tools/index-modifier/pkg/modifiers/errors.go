@@ -0,0 +1,18 @@
+package modifiers
+
+import "errors"
+
+// ErrUnknownModifier signals that no modifier was registered with the provided name
+var ErrUnknownModifier = errors.New("unknown modifier")
+
+// ErrModifierAlreadyRegistered signals that a modifier was already registered with the provided name
+var ErrModifierAlreadyRegistered = errors.New("modifier already registered")
+
+// ErrEmptyModifierName signals that an empty modifier name was provided
+var ErrEmptyModifierName = errors.New("empty modifier name")
+
+// ErrNilModifierCreator signals that a nil modifier creator was provided
+var ErrNilModifierCreator = errors.New("nil modifier creator")
+
+// ErrEmptyScript signals that a scripted modifier has no script
+var ErrEmptyScript = errors.New("empty script")
@@ -0,0 +1,11 @@
+package modifiers
+
+import "bytes"
+
+// Modifier defines what a documents modifier should do
+type Modifier interface {
+	Modify(responseBody []byte) ([]*bytes.Buffer, error)
+}
+
+// ModifierCreator defines the function that creates a modifier registered by name
+type ModifierCreator func() (Modifier, error)
@@ -0,0 +1,75 @@
+package modifiers
+
+import (
+	"fmt"
+	"sort"
+)
+
+const (
+	// TransactionsModifierName is the name of the modifier for the transactions index
+	TransactionsModifierName = "transactions"
+	// SCRsModifierName is the name of the modifier for the smart contract results index
+	SCRsModifierName = "scresults"
+)
+
+type registry struct {
+	creators map[string]ModifierCreator
+}
+
+// NewRegistry creates an empty registry of modifiers
+func NewRegistry() *registry {
+	return &registry{
+		creators: make(map[string]ModifierCreator),
+	}
+}
+
+// NewDefaultRegistry creates a registry holding the modifiers implemented in this package
+func NewDefaultRegistry() *registry {
+	r := NewRegistry()
+	r.creators[TransactionsModifierName] = func() (Modifier, error) {
+		return NewTxsModifier()
+	}
+	r.creators[SCRsModifierName] = func() (Modifier, error) {
+		return NewSCRsModifier()
+	}
+
+	return r
+}
+
+// Register will add the creator of a modifier under the provided name
+func (r *registry) Register(name string, creator ModifierCreator) error {
+	if name == "" {
+		return ErrEmptyModifierName
+	}
+	if creator == nil {
+		return ErrNilModifierCreator
+	}
+	if _, found := r.creators[name]; found {
+		return fmt.Errorf("%w: %s", ErrModifierAlreadyRegistered, name)
+	}
+
+	r.creators[name] = creator
+
+	return nil
+}
+
+// Create will create the modifier registered under the provided name
+func (r *registry) Create(name string) (Modifier, error) {
+	creator, found := r.creators[name]
+	if !found {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownModifier, name)
+	}
+
+	return creator()
+}
+
+// Names returns the sorted names of the registered modifiers
+func (r *registry) Names() []string {
+	names := make([]string, 0, len(r.creators))
+	for name := range r.creators {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
@@ -0,0 +1,109 @@
+package modifiers
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+type modifierStub struct {
+	name string
+}
+
+func (ms *modifierStub) Modify(_ []byte) ([]*bytes.Buffer, error) {
+	return nil, nil
+}
+
+func createModifierStubCreator(name string) ModifierCreator {
+	return func() (Modifier, error) {
+		return &modifierStub{name: name}, nil
+	}
+}
+
+func TestRegistry_Create(t *testing.T) {
+	t.Parallel()
+
+	errCreate := errors.New("create error")
+	r := NewRegistry()
+	_ = r.Register("first", createModifierStubCreator("first"))
+	_ = r.Register("second", createModifierStubCreator("second"))
+	_ = r.Register("failing", func() (Modifier, error) {
+		return nil, errCreate
+	})
+
+	tests := []struct {
+		name         string
+		modifierName string
+		expectedName string
+		expectedErr  error
+	}{
+		{name: "first modifier", modifierName: "first", expectedName: "first"},
+		{name: "second modifier", modifierName: "second", expectedName: "second"},
+		{name: "unknown modifier", modifierName: "third", expectedErr: ErrUnknownModifier},
+		{name: "empty name", modifierName: "", expectedErr: ErrUnknownModifier},
+		{name: "names are case sensitive", modifierName: "First", expectedErr: ErrUnknownModifier},
+		{name: "creator error", modifierName: "failing", expectedErr: errCreate},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			modifier, err := r.Create(tt.modifierName)
+			if !errors.Is(err, tt.expectedErr) {
+				t.Fatalf("expected error %v, got %v", tt.expectedErr, err)
+			}
+			if tt.expectedErr != nil {
+				return
+			}
+
+			stub, ok := modifier.(*modifierStub)
+			if !ok || stub.name != tt.expectedName {
+				t.Fatalf("expected the modifier %s, got %v", tt.expectedName, modifier)
+			}
+		})
+	}
+}
+
+func TestRegistry_Register(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		modifierName string
+		creator      ModifierCreator
+		expectedErr  error
+	}{
+		{name: "new modifier", modifierName: "new", creator: createModifierStubCreator("new")},
+		{name: "empty name", modifierName: "", creator: createModifierStubCreator(""), expectedErr: ErrEmptyModifierName},
+		{name: "nil creator", modifierName: "nil", creator: nil, expectedErr: ErrNilModifierCreator},
+		{name: "name of a default modifier", modifierName: TransactionsModifierName, creator: createModifierStubCreator("txs"), expectedErr: ErrModifierAlreadyRegistered},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			r := NewDefaultRegistry()
+			err := r.Register(tt.modifierName, tt.creator)
+			if !errors.Is(err, tt.expectedErr) {
+				t.Fatalf("expected error %v, got %v", tt.expectedErr, err)
+			}
+		})
+	}
+}
+
+func TestRegistry_Names(t *testing.T) {
+	t.Parallel()
+
+	r := NewDefaultRegistry()
+	_ = r.Register("addressesFix", createModifierStubCreator("addressesFix"))
+
+	expectedNames := []string{"addressesFix", SCRsModifierName, TransactionsModifierName}
+	if names := r.Names(); !reflect.DeepEqual(expectedNames, names) {
+		t.Fatalf("expected the names %v, got %v", expectedNames, names)
+	}
+}
@@ -0,0 +1,72 @@
+package modifiers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"github.com/kalyan3104/k-chain-es-indexer-go/data"
+)
+
+type responseDocumentsBulk struct {
+	Hits struct {
+		Hits []struct {
+			ID     string          `json:"_id"`
+			Source json.RawMessage `json:"_source"`
+		} `json:"hits"`
+	} `json:"hits"`
+}
+
+type scriptedModifier struct {
+	serializedScript []byte
+}
+
+// NewScriptedModifier will create a modifier which updates every document with the provided painless script. The
+// update is a scripted upsert of the read document, so the write index can also be a different index
+func NewScriptedModifier(script string, params map[string]interface{}) (*scriptedModifier, error) {
+	if script == "" {
+		return nil, ErrEmptyScript
+	}
+
+	serializedScript, err := json.Marshal(map[string]interface{}{
+		"source": script,
+		"lang":   "painless",
+		"params": params,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &scriptedModifier{
+		serializedScript: serializedScript,
+	}, nil
+}
+
+// NewScriptedModifierCreator returns the creator of a scripted modifier, to be registered by name
+func NewScriptedModifierCreator(script string, params map[string]interface{}) ModifierCreator {
+	return func() (Modifier, error) {
+		return NewScriptedModifier(script, params)
+	}
+}
+
+// Modify will prepare a scripted update for every document from the provided responseBody
+func (sm *scriptedModifier) Modify(responseBody []byte) ([]*bytes.Buffer, error) {
+	response := &responseDocumentsBulk{}
+	err := json.Unmarshal(responseBody, response)
+	if err != nil {
+		return nil, err
+	}
+
+	buffSlice := data.NewBufferSlice(0)
+	for _, hit := range response.Hits.Hits {
+		meta := []byte(fmt.Sprintf(`{ "update" : { "_id" : "%s" } }%s`, hit.ID, "\n"))
+		serializedData := []byte(fmt.Sprintf(`{"scripted_upsert":true,"script":%s,"upsert":%s}`, sm.serializedScript, hit.Source))
+
+		err = buffSlice.PutData(meta, serializedData)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return buffSlice.Buffers(), nil
+}
@@ -0,0 +1,99 @@
+package modifiers
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNewScriptedModifier_EmptyScriptShouldErr(t *testing.T) {
+	t.Parallel()
+
+	modifier, err := NewScriptedModifier("", nil)
+	if !errors.Is(err, ErrEmptyScript) {
+		t.Fatalf("expected error %v, got %v", ErrEmptyScript, err)
+	}
+	if modifier != nil {
+		t.Fatal("expected no modifier")
+	}
+}
+
+func TestScriptedModifier_Modify(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		script       string
+		params       map[string]interface{}
+		responseBody string
+		expectedBody string
+	}{
+		{
+			name:         "no documents",
+			script:       "ctx._source.fixed = true",
+			responseBody: `{"hits":{"hits":[]}}`,
+			expectedBody: "",
+		},
+		{
+			name:         "script without params",
+			script:       "ctx._source.fixed = true",
+			responseBody: `{"hits":{"hits":[{"_id":"h1","_source":{"nonce":1}}]}}`,
+			expectedBody: `{ "update" : { "_id" : "h1" } }` + "\n" +
+				`{"scripted_upsert":true,"script":{"lang":"painless","params":null,"source":"ctx._source.fixed = true"},"upsert":{"nonce":1}}` + "\n",
+		},
+		{
+			name:         "script with params",
+			script:       "ctx._source.status = params.status",
+			params:       map[string]interface{}{"status": "success", "round": 7},
+			responseBody: `{"hits":{"hits":[{"_id":"h1","_source":{"nonce":1}},{"_id":"h2","_source":{"nonce":2}}]}}`,
+			expectedBody: `{ "update" : { "_id" : "h1" } }` + "\n" +
+				`{"scripted_upsert":true,"script":{"lang":"painless","params":{"round":7,"status":"success"},"source":"ctx._source.status = params.status"},"upsert":{"nonce":1}}` + "\n" +
+				`{ "update" : { "_id" : "h2" } }` + "\n" +
+				`{"scripted_upsert":true,"script":{"lang":"painless","params":{"round":7,"status":"success"},"source":"ctx._source.status = params.status"},"upsert":{"nonce":2}}` + "\n",
+		},
+		{
+			name:         "script with quotes and new lines should be escaped",
+			script:       "if (ctx._source.status == \"fail\") {\n\tctx._source.status = 'failed'\n}",
+			responseBody: `{"hits":{"hits":[{"_id":"h1","_source":{}}]}}`,
+			expectedBody: `{ "update" : { "_id" : "h1" } }` + "\n" +
+				`{"scripted_upsert":true,"script":{"lang":"painless","params":null,"source":"if (ctx._source.status == \"fail\") {\n\tctx._source.status = 'failed'\n}"},"upsert":{}}` + "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			modifier, err := NewScriptedModifier(tt.script, tt.params)
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+
+			buffers, err := modifier.Modify([]byte(tt.responseBody))
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+
+			body := ""
+			for _, buff := range buffers {
+				body += buff.String()
+			}
+			if body != tt.expectedBody {
+				t.Fatalf("expected the body\n%s\ngot\n%s", tt.expectedBody, body)
+			}
+		})
+	}
+}
+
+func TestScriptedModifier_ModifyInvalidResponseShouldErr(t *testing.T) {
+	t.Parallel()
+
+	modifier, _ := NewScriptedModifier("ctx._source.fixed = true", nil)
+	buffers, err := modifier.Modify([]byte("not json"))
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	if buffers != nil {
+		t.Fatal("expected no buffers")
+	}
+}
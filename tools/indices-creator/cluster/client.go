@@ -0,0 +1,218 @@
+package cluster
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/elastic/go-elasticsearch/v7"
+	"github.com/elastic/go-elasticsearch/v7/esapi"
+)
+
+const policiesRoute = "/_opendistro/_ism/policies/"
+
+// AliasAction holds an action of an aliases update, which adds or removes an alias of an index
+type AliasAction struct {
+	Remove bool
+	Alias  string
+	Index  string
+}
+
+type elasticClient struct {
+	client *elasticsearch.Client
+}
+
+// NewElasticClient creates a client that reads and changes the templates, policies, indices and aliases of a cluster
+func NewElasticClient(cfg elasticsearch.Config) (*elasticClient, error) {
+	client, err := elasticsearch.NewClient(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return &elasticClient{
+		client: client,
+	}, nil
+}
+
+// GetTemplate returns the provided index template, or false if the template does not exist
+func (ec *elasticClient) GetTemplate(name string) (map[string]interface{}, bool, error) {
+	res, err := ec.client.Indices.GetTemplate(ec.client.Indices.GetTemplate.WithName(name))
+	if err != nil {
+		return nil, false, err
+	}
+	defer closeBody(res)
+
+	if res.StatusCode == http.StatusNotFound {
+		return nil, false, nil
+	}
+
+	templates := make(map[string]map[string]interface{})
+	err = decodeResponse(res, &templates)
+	if err != nil {
+		return nil, false, err
+	}
+
+	template, found := templates[name]
+
+	return template, found, nil
+}
+
+// GetIndicesAliases returns all the open indices of the cluster, with their aliases
+func (ec *elasticClient) GetIndicesAliases() (map[string][]string, error) {
+	res, err := ec.client.Indices.GetAlias()
+	if err != nil {
+		return nil, err
+	}
+	defer closeBody(res)
+
+	response := make(map[string]struct {
+		Aliases map[string]interface{} `json:"aliases"`
+	})
+	err = decodeResponse(res, &response)
+	if err != nil {
+		return nil, err
+	}
+
+	indicesAliases := make(map[string][]string, len(response))
+	for index, indexAliases := range response {
+		aliases := make([]string, 0, len(indexAliases.Aliases))
+		for alias := range indexAliases.Aliases {
+			aliases = append(aliases, alias)
+		}
+		indicesAliases[index] = aliases
+	}
+
+	return indicesAliases, nil
+}
+
+// PolicyExists returns true if the provided opendistro policy exists
+func (ec *elasticClient) PolicyExists(name string) (bool, error) {
+	res, err := ec.performRequest(http.MethodGet, policiesRoute+name, nil)
+	if err != nil {
+		return false, err
+	}
+	defer closeBody(res)
+
+	if res.StatusCode == http.StatusNotFound {
+		return false, nil
+	}
+
+	return true, decodeResponse(res, nil)
+}
+
+// PutTemplate creates or replaces the provided index template
+func (ec *elasticClient) PutTemplate(name string, template []byte) error {
+	res, err := ec.client.Indices.PutTemplate(name, bytes.NewReader(template))
+	if err != nil {
+		return err
+	}
+	defer closeBody(res)
+
+	return decodeResponse(res, nil)
+}
+
+// PutPolicy creates the provided opendistro policy
+func (ec *elasticClient) PutPolicy(name string, policy []byte) error {
+	res, err := ec.performRequest(http.MethodPut, policiesRoute+name, policy)
+	if err != nil {
+		return err
+	}
+	defer closeBody(res)
+
+	return decodeResponse(res, nil)
+}
+
+// CreateIndex creates the provided index, its settings and mappings being the ones of the matching templates
+func (ec *elasticClient) CreateIndex(index string) error {
+	res, err := ec.client.Indices.Create(index)
+	if err != nil {
+		return err
+	}
+	defer closeBody(res)
+
+	return decodeResponse(res, nil)
+}
+
+// DeleteIndex deletes the provided index together with its documents
+func (ec *elasticClient) DeleteIndex(index string) error {
+	res, err := ec.client.Indices.Delete([]string{index})
+	if err != nil {
+		return err
+	}
+	defer closeBody(res)
+
+	return decodeResponse(res, nil)
+}
+
+// UpdateAliases applies all the provided actions atomically
+func (ec *elasticClient) UpdateAliases(actions []AliasAction) error {
+	requestActions := make([]map[string]interface{}, 0, len(actions))
+	for _, action := range actions {
+		actionName := "add"
+		if action.Remove {
+			actionName = "remove"
+		}
+
+		requestActions = append(requestActions, map[string]interface{}{
+			actionName: map[string]string{
+				"index": action.Index,
+				"alias": action.Alias,
+			},
+		})
+	}
+
+	body, err := json.Marshal(map[string]interface{}{"actions": requestActions})
+	if err != nil {
+		return err
+	}
+
+	res, err := ec.client.Indices.UpdateAliases(bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer closeBody(res)
+
+	return decodeResponse(res, nil)
+}
+
+func (ec *elasticClient) performRequest(method string, path string, body []byte) (*esapi.Response, error) {
+	req, err := http.NewRequest(method, path, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := ec.client.Transport.Perform(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return &esapi.Response{
+		StatusCode: res.StatusCode,
+		Body:       res.Body,
+		Header:     res.Header,
+	}, nil
+}
+
+func decodeResponse(res *esapi.Response, value interface{}) error {
+	bodyBytes, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return err
+	}
+	if res.IsError() {
+		return fmt.Errorf("error response, status code: %d, body: %s", res.StatusCode, bodyBytes)
+	}
+	if value == nil {
+		return nil
+	}
+
+	return json.Unmarshal(bodyBytes, value)
+}
+
+func closeBody(res *esapi.Response) {
+	if res != nil && res.Body != nil {
+		_ = res.Body.Close()
+	}
+}
@@ -2,29 +2,40 @@ package main
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"os"
 	"path"
 
 	"github.com/elastic/go-elasticsearch/v7"
-	"github.com/kalyan3104/k-chain-es-indexer-go/client"
 	"github.com/kalyan3104/k-chain-es-indexer-go/client/logging"
+	"github.com/kalyan3104/k-chain-es-indexer-go/tools/indexes-creator/cluster"
+	"github.com/kalyan3104/k-chain-es-indexer-go/tools/indexes-creator/plan"
 	"github.com/kalyan3104/k-chain-es-indexer-go/tools/indexes-creator/reader"
 	logger "github.com/kalyan3104/k-chain-logger-go"
 	"github.com/pelletier/go-toml"
 	"github.com/urfave/cli"
 )
 
-const configFileName = "cluster.toml"
+const (
+	configFileName = "cluster.toml"
+
+	templatesSourceFiles    = "files"
+	templatesSourceEmbedded = "embedded"
+	openDistroTemplate      = "opendistro"
+)
 
 type config struct {
 	ClusterConfig struct {
-		URL            string   `toml:"url"`
-		Username       string   `toml:"username"`
-		Password       string   `toml:"password"`
-		UseKibana      bool     `toml:"use-kibana"`
-		EnabledIndices []string `toml:"enabled-indices"`
+		URL       string `toml:"url"`
+		Username  string `toml:"username"`
+		Password  string `toml:"password"`
+		UseKibana bool   `toml:"use-kibana"`
+		// TemplatesSource is "files" for the templates from the config folder or "embedded" for the ones used by the
+		// indexer
+		TemplatesSource string   `toml:"templates-source"`
+		EnabledIndices  []string `toml:"enabled-indices"`
 	} `toml:"config"`
 }
 
@@ -37,16 +48,37 @@ var (
 		Usage: "The path to the config folder",
 		Value: "./config",
 	}
+	recreateIndices = cli.BoolFlag{
+		Name: "recreate-indices",
+		Usage: "If set, the indices created with an older version of their template are deleted, together with " +
+			"their documents, and created again",
+	}
+	newGenerations = cli.BoolFlag{
+		Name: "new-generations",
+		Usage: "If set, a new generation is created for the indices created with an older version of their " +
+			"template and the aliases are moved to it",
+	}
+	moveAliases = cli.BoolFlag{
+		Name:  "move-aliases",
+		Usage: "If set, the aliases which do not target the latest generation of their index are moved to it",
+	}
+	deleteExtraIndices = cli.BoolFlag{
+		Name:  "delete-extra-indices",
+		Usage: "If set, the indices which do not belong to any enabled index are deleted",
+	}
 )
 
 const helpTemplate = `NAME:
    {{.Name}} - {{.Usage}}
 USAGE:
-   {{.HelpName}} {{if .VisibleFlags}}[global options]{{end}}
+   {{.HelpName}} {{if .VisibleFlags}}[global options]{{end}}{{if .Commands}} command [command options]{{end}}
    {{if len .Authors}}
 AUTHOR:
    {{range .Authors}}{{ . }}{{end}}
    {{end}}{{if .Commands}}
+COMMANDS:
+   {{range .Commands}}{{join .Names ", "}}{{ "\t" }}{{.Usage}}
+   {{end}}
 GLOBAL OPTIONS:
    {{range .VisibleFlags}}{{.}}
    {{end}}
@@ -64,6 +96,26 @@ func main() {
 	app.Flags = []cli.Flag{
 		configPath,
 	}
+	applyFlags := []cli.Flag{
+		recreateIndices,
+		newGenerations,
+		moveAliases,
+		deleteExtraIndices,
+	}
+	app.Commands = []cli.Command{
+		{
+			Name:   "plan",
+			Usage:  "prints the changes needed for the cluster to match the templates, without changing the cluster",
+			Flags:  applyFlags,
+			Action: printPlan,
+		},
+		{
+			Name:   "apply",
+			Usage:  "applies the changes needed for the cluster to match the templates",
+			Flags:  applyFlags,
+			Action: applyPlan,
+		},
+	}
 	app.Authors = []cli.Author{
 		{
 			Name:  "The kalyan Team",
@@ -73,7 +125,8 @@ func main() {
 
 	_ = logger.SetLogLevel("*:DEBUG")
 
-	app.Action = createIndexesAndMappings
+	// without a command, the missing templates, indices and aliases are created, as apply does without options
+	app.Action = applyPlan
 
 	err := app.Run(os.Args)
 	if err != nil {
@@ -83,63 +136,143 @@ func main() {
 
 }
 
-func createIndexesAndMappings(ctx *cli.Context) {
-	cfgPath := ctx.String(configPath.Name)
-	cfg, err := loadConfigFile(cfgPath)
+func printPlan(ctx *cli.Context) error {
+	changes, _, err := createPlan(ctx)
 	if err != nil {
-		log.Error("cannot load config file", "error", err.Error())
+		return err
 	}
 
-	pathToMappings := path.Join(cfgPath, "noKibana")
-	if cfg.ClusterConfig.UseKibana {
-		pathToMappings = path.Join(cfgPath, "withKibana")
+	if len(changes) == 0 {
+		fmt.Println("the cluster matches the templates")
+		return nil
 	}
 
-	indexesMappings, _, err := reader.GetElasticTemplatesAndPolicies(pathToMappings, cfg.ClusterConfig.EnabledIndices)
+	for _, change := range changes {
+		fmt.Println(change.String())
+		for _, detail := range change.Details {
+			fmt.Println("    " + detail)
+		}
+	}
+	fmt.Println("changes marked with ~ are not applied with the provided options")
+
+	return nil
+}
+
+func applyPlan(ctx *cli.Context) error {
+	changes, clusterClient, err := createPlan(ctx)
 	if err != nil {
-		log.Error("cannot load templates", "error", err.Error())
-		return
+		return err
 	}
 
-	err = createTemplates(cfg, indexesMappings)
+	applier, err := plan.NewApplier(clusterClient)
 	if err != nil {
-		log.Error("cannot create templates", "error", err.Error())
-		return
+		return err
 	}
 
-	log.Info("all indices were created")
+	err = applier.Apply(changes)
+	if err != nil {
+		return err
+	}
+
+	for _, change := range changes {
+		if change.Skipped {
+			log.Warn("change not applied with the provided options", "change", change.String())
+		}
+	}
+
+	log.Info("all changes were applied")
+
+	return nil
+}
+
+type clusterClientHandler interface {
+	plan.ClusterReader
+	plan.ClusterWriter
 }
 
-func createTemplates(cfg *config, indexesMappings map[string]*bytes.Buffer) error {
-	databaseClient, err := client.NewElasticClient(elasticsearch.Config{
+func createPlan(ctx *cli.Context) ([]*plan.Change, clusterClientHandler, error) {
+	cfgPath := ctx.GlobalString(configPath.Name)
+	cfg, err := loadConfigFile(cfgPath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w while loading the config file", err)
+	}
+
+	templates, policies, err := readTemplatesAndPolicies(cfgPath, cfg)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w while loading the templates", err)
+	}
+
+	clusterClient, err := cluster.NewElasticClient(elasticsearch.Config{
 		Addresses: []string{cfg.ClusterConfig.URL},
 		Username:  cfg.ClusterConfig.Username,
 		Password:  cfg.ClusterConfig.Password,
 		Logger:    &logging.CustomLogger{},
 	})
 	if err != nil {
-		return err
+		return nil, nil, err
 	}
 
-	for index, indexData := range indexesMappings {
-		errCheck := databaseClient.CheckAndCreateTemplate(index, indexData)
-		if errCheck != nil {
-			return fmt.Errorf("index: %s, error: %w", index, errCheck)
+	indices := make([]string, 0, len(cfg.ClusterConfig.EnabledIndices))
+	for _, index := range cfg.ClusterConfig.EnabledIndices {
+		if index != openDistroTemplate {
+			indices = append(indices, index)
 		}
+	}
 
-		indexName := fmt.Sprintf("%s-%s", index, "000001")
-		errCreate := databaseClient.CheckAndCreateIndex(indexName)
-		if errCreate != nil {
-			return fmt.Errorf("index: %s, error: %w", index, errCreate)
-		}
+	planner, err := plan.NewPlanner(plan.ArgsPlanner{
+		Client:    clusterClient,
+		Templates: templates,
+		Policies:  policies,
+		Indices:   indices,
+		Options: plan.Options{
+			RecreateIndices:    ctx.Bool(recreateIndices.Name),
+			NewGenerations:     ctx.Bool(newGenerations.Name),
+			MoveAliases:        ctx.Bool(moveAliases.Name),
+			DeleteExtraIndices: ctx.Bool(deleteExtraIndices.Name),
+		},
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	changes, err := planner.Plan()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return changes, clusterClient, nil
+}
+
+func readTemplatesAndPolicies(cfgPath string, cfg *config) (map[string][]byte, map[string][]byte, error) {
+	var templates, policies map[string]*bytes.Buffer
+	var err error
 
-		errAlias := databaseClient.CheckAndCreateAlias(index, indexName)
-		if err != nil {
-			return errAlias
+	switch cfg.ClusterConfig.TemplatesSource {
+	case "", templatesSourceFiles:
+		pathToMappings := path.Join(cfgPath, "noKibana")
+		if cfg.ClusterConfig.UseKibana {
+			pathToMappings = path.Join(cfgPath, "withKibana")
 		}
+		templates, policies, err = reader.GetElasticTemplatesAndPolicies(pathToMappings, cfg.ClusterConfig.EnabledIndices)
+	case templatesSourceEmbedded:
+		templates, policies, err = reader.GetEmbeddedTemplatesAndPolicies(cfg.ClusterConfig.UseKibana, cfg.ClusterConfig.EnabledIndices)
+	default:
+		err = errors.New("unknown templates source " + cfg.ClusterConfig.TemplatesSource)
+	}
+	if err != nil {
+		return nil, nil, err
 	}
 
-	return nil
+	return toBytes(templates), toBytes(policies), nil
+}
+
+func toBytes(buffers map[string]*bytes.Buffer) map[string][]byte {
+	result := make(map[string][]byte, len(buffers))
+	for name, buff := range buffers {
+		result[name] = buff.Bytes()
+	}
+
+	return result
 }
 
 func loadConfigFile(pathStr string) (*config, error) {
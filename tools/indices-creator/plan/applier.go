@@ -0,0 +1,73 @@
+package plan
+
+import (
+	"fmt"
+
+	"github.com/kalyan3104/k-chain-es-indexer-go/tools/indexes-creator/cluster"
+	logger "github.com/kalyan3104/k-chain-logger-go"
+)
+
+var log = logger.GetOrCreate("plan")
+
+type applier struct {
+	client ClusterWriter
+}
+
+// NewApplier creates a component that applies the changes of a plan on the cluster
+func NewApplier(client ClusterWriter) (*applier, error) {
+	if client == nil {
+		return nil, ErrNilClusterClient
+	}
+
+	return &applier{
+		client: client,
+	}, nil
+}
+
+// Apply will apply, in order, the changes which are not skipped. It stops at the first error
+func (a *applier) Apply(changes []*Change) error {
+	for _, change := range changes {
+		if change.Skipped {
+			continue
+		}
+
+		err := a.applyChange(change)
+		if err != nil {
+			return fmt.Errorf("%w while applying %s", err, change.String())
+		}
+
+		log.Info("applied", "change", change.String())
+	}
+
+	return nil
+}
+
+func (a *applier) applyChange(change *Change) error {
+	switch change.Type {
+	case CreatePolicy:
+		return a.client.PutPolicy(change.Name, change.body)
+	case CreateTemplate, UpdateTemplate:
+		return a.client.PutTemplate(change.Name, change.body)
+	case CreateIndex, NewGeneration:
+		return a.client.CreateIndex(change.Index)
+	case RecreateIndex:
+		err := a.client.DeleteIndex(change.Index)
+		if err != nil {
+			return err
+		}
+
+		return a.client.CreateIndex(change.Index)
+	case CreateAlias, MoveAlias:
+		actions := make([]cluster.AliasAction, 0, len(change.From)+1)
+		for _, index := range change.From {
+			actions = append(actions, cluster.AliasAction{Remove: true, Alias: change.Name, Index: index})
+		}
+		actions = append(actions, cluster.AliasAction{Alias: change.Name, Index: change.Index})
+
+		return a.client.UpdateAliases(actions)
+	case DeleteIndex:
+		return a.client.DeleteIndex(change.Index)
+	default:
+		return fmt.Errorf("unknown change type %s", change.Type)
+	}
+}
@@ -0,0 +1,67 @@
+package plan
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ChangeType defines the kind of change needed for the cluster to match the templates
+type ChangeType string
+
+const (
+	// CreatePolicy creates a missing opendistro policy
+	CreatePolicy ChangeType = "create-policy"
+	// CreateTemplate creates a missing index template
+	CreateTemplate ChangeType = "create-template"
+	// UpdateTemplate replaces an index template which differs from the one in the config
+	UpdateTemplate ChangeType = "update-template"
+	// CreateIndex creates the first generation of a missing index
+	CreateIndex ChangeType = "create-index"
+	// OutdatedIndex reports an index created with an older version of its template
+	OutdatedIndex ChangeType = "outdated-index"
+	// RecreateIndex deletes an outdated index, together with its documents, and creates it again
+	RecreateIndex ChangeType = "recreate-index"
+	// NewGeneration creates the next generation of an outdated index
+	NewGeneration ChangeType = "new-generation"
+	// CreateAlias creates a missing alias
+	CreateAlias ChangeType = "create-alias"
+	// MoveAlias moves an alias to the latest generation of its index
+	MoveAlias ChangeType = "move-alias"
+	// DeleteIndex deletes an index which does not belong to any enabled index
+	DeleteIndex ChangeType = "delete-index"
+)
+
+// Change holds a difference between the cluster and the templates, together with the action which removes it
+type Change struct {
+	Type ChangeType
+	// Name is the name of the policy, template or alias
+	Name string
+	// Index is the index created, deleted or targeted by the alias
+	Index string
+	// From holds the indices an alias is moved from
+	From []string
+	// Details holds the differences of a template
+	Details []string
+	// Skipped is true for the changes which are only reported, the apply options not allowing them
+	Skipped bool
+	body    []byte
+}
+
+// String returns the change as a line of the plan
+func (c *Change) String() string {
+	prefix := "+"
+	if c.Skipped {
+		prefix = "~"
+	}
+
+	switch c.Type {
+	case CreatePolicy, CreateTemplate, UpdateTemplate:
+		return fmt.Sprintf("%s %s %s", prefix, c.Type, c.Name)
+	case CreateAlias:
+		return fmt.Sprintf("%s %s %s -> %s", prefix, c.Type, c.Name, c.Index)
+	case MoveAlias:
+		return fmt.Sprintf("%s %s %s: [%s] -> %s", prefix, c.Type, c.Name, strings.Join(c.From, ", "), c.Index)
+	default:
+		return fmt.Sprintf("%s %s %s", prefix, c.Type, c.Index)
+	}
+}
@@ -0,0 +1,9 @@
+package plan
+
+import "errors"
+
+// ErrNilClusterClient signals that a nil cluster client was provided
+var ErrNilClusterClient = errors.New("nil cluster client")
+
+// ErrConflictingOptions signals that an outdated index can be either recreated or replaced by a new generation
+var ErrConflictingOptions = errors.New("the indices can be either recreated or replaced by a new generation")
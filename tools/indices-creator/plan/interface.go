@@ -0,0 +1,19 @@
+package plan
+
+import "github.com/kalyan3104/k-chain-es-indexer-go/tools/indexes-creator/cluster"
+
+// ClusterReader defines what a component that reads the state of the cluster should do
+type ClusterReader interface {
+	GetTemplate(name string) (map[string]interface{}, bool, error)
+	GetIndicesAliases() (map[string][]string, error)
+	PolicyExists(name string) (bool, error)
+}
+
+// ClusterWriter defines what a component that changes the cluster should do
+type ClusterWriter interface {
+	PutTemplate(name string, template []byte) error
+	PutPolicy(name string, policy []byte) error
+	CreateIndex(index string) error
+	DeleteIndex(index string) error
+	UpdateAliases(actions []cluster.AliasAction) error
+}
@@ -0,0 +1,302 @@
+package plan
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const generationFormat = "%s-%06d"
+
+var generationRegex = regexp.MustCompile(`^(.+)-(\d{6})$`)
+
+// Options holds the changes of existing indices and aliases the apply is allowed to do. The missing policies,
+// templates, indices and aliases are always created and the templates are always updated
+type Options struct {
+	// RecreateIndices deletes the outdated indices, with their documents, and creates them again
+	RecreateIndices bool
+	// NewGenerations creates a new generation of the outdated indices and moves their aliases to it
+	NewGenerations bool
+	// MoveAliases moves the aliases which do not target the latest generation of their index
+	MoveAliases bool
+	// DeleteExtraIndices deletes the indices which do not belong to any enabled index
+	DeleteExtraIndices bool
+}
+
+// ArgsPlanner holds the arguments needed to create a planner
+type ArgsPlanner struct {
+	Client ClusterReader
+	// Templates holds the templates by name. A template which is not an enabled index, like the opendistro one, has
+	// no index and alias
+	Templates map[string][]byte
+	Policies  map[string][]byte
+	// Indices holds the enabled indices, each index having an alias and generations named <alias>-000001, ...
+	Indices []string
+	Options Options
+}
+
+type planner struct {
+	client    ClusterReader
+	templates map[string][]byte
+	policies  map[string][]byte
+	indices   []string
+	options   Options
+}
+
+// NewPlanner creates a component that compares the cluster with the templates
+func NewPlanner(args ArgsPlanner) (*planner, error) {
+	if args.Client == nil {
+		return nil, ErrNilClusterClient
+	}
+	if args.Options.RecreateIndices && args.Options.NewGenerations {
+		return nil, ErrConflictingOptions
+	}
+
+	indices := make([]string, len(args.Indices))
+	copy(indices, args.Indices)
+	sort.Strings(indices)
+
+	return &planner{
+		client:    args.Client,
+		templates: args.Templates,
+		policies:  args.Policies,
+		indices:   indices,
+		options:   args.Options,
+	}, nil
+}
+
+// Plan returns the changes needed for the cluster to match the templates, in the order they have to be applied
+func (p *planner) Plan() ([]*Change, error) {
+	changes, err := p.planPolicies()
+	if err != nil {
+		return nil, err
+	}
+
+	templateChanges, outdated, err := p.planTemplates()
+	if err != nil {
+		return nil, err
+	}
+	changes = append(changes, templateChanges...)
+
+	indicesAliases, err := p.client.GetIndicesAliases()
+	if err != nil {
+		return nil, fmt.Errorf("%w while reading the indices", err)
+	}
+
+	changes = append(changes, p.planIndices(indicesAliases, outdated)...)
+	changes = append(changes, p.planExtraIndices(indicesAliases)...)
+
+	return changes, nil
+}
+
+func (p *planner) planPolicies() ([]*Change, error) {
+	changes := make([]*Change, 0)
+	for _, name := range sortedNames(p.policies) {
+		exists, err := p.client.PolicyExists(name)
+		if err != nil {
+			return nil, fmt.Errorf("%w while reading the policy %s", err, name)
+		}
+		if !exists {
+			changes = append(changes, &Change{Type: CreatePolicy, Name: name, body: p.policies[name]})
+		}
+	}
+
+	return changes, nil
+}
+
+// planTemplates returns the templates changes and the templates which are missing or different in the cluster, so the
+// existing indices created from them are outdated
+func (p *planner) planTemplates() ([]*Change, map[string]bool, error) {
+	changes := make([]*Change, 0)
+	outdated := make(map[string]bool)
+	for _, name := range sortedNames(p.templates) {
+		actual, found, err := p.client.GetTemplate(name)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%w while reading the template %s", err, name)
+		}
+		if !found {
+			changes = append(changes, &Change{Type: CreateTemplate, Name: name, body: p.templates[name]})
+			outdated[name] = true
+			continue
+		}
+
+		desired := make(map[string]interface{})
+		err = json.Unmarshal(p.templates[name], &desired)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%w while decoding the template %s", err, name)
+		}
+
+		differences := compareTemplates(desired, actual)
+		if len(differences) > 0 {
+			changes = append(changes, &Change{Type: UpdateTemplate, Name: name, Details: differences, body: p.templates[name]})
+			outdated[name] = true
+		}
+	}
+
+	return changes, outdated, nil
+}
+
+func (p *planner) planIndices(indicesAliases map[string][]string, outdated map[string]bool) []*Change {
+	aliasTargets := make(map[string][]string)
+	for index, aliases := range indicesAliases {
+		for _, alias := range aliases {
+			aliasTargets[alias] = append(aliasTargets[alias], index)
+		}
+	}
+
+	changes := make([]*Change, 0)
+	for _, name := range p.indices {
+		targets := aliasTargets[name]
+		sort.Strings(targets)
+
+		generations := getGenerations(name, indicesAliases)
+		if len(generations) == 0 {
+			index := fmt.Sprintf(generationFormat, name, 1)
+			changes = append(changes, &Change{Type: CreateIndex, Name: name, Index: index})
+			changes = append(changes, p.planAlias(name, index, targets))
+			continue
+		}
+
+		latest := generations[len(generations)-1]
+		if outdated[name] {
+			switch {
+			case p.options.NewGenerations:
+				next := fmt.Sprintf(generationFormat, name, generationNumber(latest)+1)
+				changes = append(changes, &Change{Type: NewGeneration, Name: name, Index: next})
+				changes = append(changes, p.planAliasToNewGeneration(name, next, targets))
+				continue
+			case p.options.RecreateIndices:
+				// deleting the index also removes its alias
+				changes = append(changes, &Change{Type: RecreateIndex, Name: name, Index: latest})
+				targets = removeIndex(targets, latest)
+			default:
+				changes = append(changes, &Change{Type: OutdatedIndex, Name: name, Index: latest, Skipped: true})
+			}
+		}
+
+		if len(targets) == 1 && targets[0] == latest {
+			continue
+		}
+		changes = append(changes, p.planAlias(name, latest, targets))
+	}
+
+	return changes
+}
+
+// planAlias returns the change which makes the alias target only the provided index
+func (p *planner) planAlias(alias string, index string, targets []string) *Change {
+	if len(targets) == 0 {
+		return &Change{Type: CreateAlias, Name: alias, Index: index}
+	}
+
+	return &Change{
+		Type:    MoveAlias,
+		Name:    alias,
+		Index:   index,
+		From:    removeIndex(targets, index),
+		Skipped: !p.options.MoveAliases,
+	}
+}
+
+// planAliasToNewGeneration moves the alias to the new generation, as the new generation is not used otherwise
+func (p *planner) planAliasToNewGeneration(alias string, index string, targets []string) *Change {
+	change := p.planAlias(alias, index, targets)
+	change.Skipped = false
+
+	return change
+}
+
+// planExtraIndices returns the indices which are neither generations nor targets of the enabled indices. The hidden
+// indices, whose names start with a dot, are not reported
+func (p *planner) planExtraIndices(indicesAliases map[string][]string) []*Change {
+	enabled := make(map[string]bool, len(p.indices))
+	for _, name := range p.indices {
+		enabled[name] = true
+	}
+
+	changes := make([]*Change, 0)
+	for _, index := range sortedIndices(indicesAliases) {
+		if strings.HasPrefix(index, ".") || isEnabledGeneration(index, enabled) || hasEnabledAlias(indicesAliases[index], enabled) {
+			continue
+		}
+
+		changes = append(changes, &Change{Type: DeleteIndex, Index: index, Skipped: !p.options.DeleteExtraIndices})
+	}
+
+	return changes
+}
+
+// getGenerations returns the generations of an index, sorted from the oldest one
+func getGenerations(name string, indicesAliases map[string][]string) []string {
+	generations := make([]string, 0)
+	for index := range indicesAliases {
+		matches := generationRegex.FindStringSubmatch(index)
+		if len(matches) == 3 && matches[1] == name {
+			generations = append(generations, index)
+		}
+	}
+	sort.Strings(generations)
+
+	return generations
+}
+
+func generationNumber(index string) int {
+	matches := generationRegex.FindStringSubmatch(index)
+	if len(matches) != 3 {
+		return 0
+	}
+
+	number, _ := strconv.Atoi(matches[2])
+
+	return number
+}
+
+func isEnabledGeneration(index string, enabled map[string]bool) bool {
+	matches := generationRegex.FindStringSubmatch(index)
+
+	return len(matches) == 3 && enabled[matches[1]]
+}
+
+func hasEnabledAlias(aliases []string, enabled map[string]bool) bool {
+	for _, alias := range aliases {
+		if enabled[alias] {
+			return true
+		}
+	}
+
+	return false
+}
+
+func removeIndex(indices []string, index string) []string {
+	remaining := make([]string, 0, len(indices))
+	for _, idx := range indices {
+		if idx != index {
+			remaining = append(remaining, idx)
+		}
+	}
+
+	return remaining
+}
+
+func sortedNames(bodies map[string][]byte) []string {
+	names := make([]string, 0, len(bodies))
+	for name := range bodies {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+func sortedIndices(indicesAliases map[string][]string) []string {
+	indices := make([]string, 0, len(indicesAliases))
+	for index := range indicesAliases {
+		indices = append(indices, index)
+	}
+	sort.Strings(indices)
+
+	return indices
+}
@@ -0,0 +1,126 @@
+package plan
+
+import (
+	"reflect"
+	"testing"
+)
+
+type clusterReaderStub struct {
+	templates      map[string]map[string]interface{}
+	indicesAliases map[string][]string
+}
+
+func (crs *clusterReaderStub) GetTemplate(name string) (map[string]interface{}, bool, error) {
+	template, found := crs.templates[name]
+
+	return template, found, nil
+}
+
+func (crs *clusterReaderStub) GetIndicesAliases() (map[string][]string, error) {
+	return crs.indicesAliases, nil
+}
+
+func (crs *clusterReaderStub) PolicyExists(_ string) (bool, error) {
+	return true, nil
+}
+
+func changesAsStrings(changes []*Change) []string {
+	lines := make([]string, 0, len(changes))
+	for _, change := range changes {
+		lines = append(lines, change.String())
+	}
+
+	return lines
+}
+
+func TestPlanner_PlanTemplatesMappings(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name            string
+		clusterTemplate string
+		expectedChanges []string
+		expectedDetails []string
+	}{
+		{
+			name:            "no changes",
+			clusterTemplate: `{"index_patterns": ["transactions-*"], "settings": {"index": {"number_of_shards": "3"}}, "mappings": {"properties": {"nonce": {"type": "double"}, "sender": {"type": "keyword"}}}}`,
+			expectedChanges: []string{},
+		},
+		{
+			name:            "added mapping field",
+			clusterTemplate: `{"index_patterns": ["transactions-*"], "settings": {"index": {"number_of_shards": "3"}}, "mappings": {"properties": {"sender": {"type": "keyword"}}}}`,
+			expectedChanges: []string{"+ update-template transactions", "~ outdated-index transactions-000001"},
+			expectedDetails: []string{"mappings.properties.nonce.type: cluster <missing>, template double"},
+		},
+		{
+			name:            "removed mapping field",
+			clusterTemplate: `{"index_patterns": ["transactions-*"], "settings": {"index": {"number_of_shards": "3"}}, "mappings": {"properties": {"nonce": {"type": "double"}, "sender": {"type": "keyword"}, "data": {"type": "text"}}}}`,
+			expectedChanges: []string{"+ update-template transactions", "~ outdated-index transactions-000001"},
+			expectedDetails: []string{"mappings.properties.data.type: cluster text, template <missing>"},
+		},
+		{
+			name:            "changed mapping field",
+			clusterTemplate: `{"index_patterns": ["transactions-*"], "settings": {"index": {"number_of_shards": "3"}}, "mappings": {"properties": {"nonce": {"type": "long"}, "sender": {"type": "keyword"}}}}`,
+			expectedChanges: []string{"+ update-template transactions", "~ outdated-index transactions-000001"},
+			expectedDetails: []string{"mappings.properties.nonce.type: cluster long, template double"},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			p, err := NewPlanner(ArgsPlanner{
+				Client: &clusterReaderStub{
+					templates:      map[string]map[string]interface{}{"transactions": decodeTestTemplate(t, tt.clusterTemplate)},
+					indicesAliases: map[string][]string{"transactions-000001": {"transactions"}},
+				},
+				Templates: map[string][]byte{"transactions": []byte(testTemplate)},
+				Indices:   []string{"transactions"},
+			})
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+
+			changes, err := p.Plan()
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+			if lines := changesAsStrings(changes); !reflect.DeepEqual(tt.expectedChanges, lines) {
+				t.Fatalf("expected the changes %v, got %v", tt.expectedChanges, lines)
+			}
+			if len(tt.expectedDetails) > 0 && !reflect.DeepEqual(tt.expectedDetails, changes[0].Details) {
+				t.Fatalf("expected the details %v, got %v", tt.expectedDetails, changes[0].Details)
+			}
+		})
+	}
+}
+
+func TestPlanner_PlanMissingTemplateAndIndex(t *testing.T) {
+	t.Parallel()
+
+	p, _ := NewPlanner(ArgsPlanner{
+		Client: &clusterReaderStub{
+			templates:      map[string]map[string]interface{}{},
+			indicesAliases: map[string][]string{},
+		},
+		Templates: map[string][]byte{"transactions": []byte(testTemplate)},
+		Indices:   []string{"transactions"},
+	})
+
+	changes, err := p.Plan()
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	expectedChanges := []string{
+		"+ create-template transactions",
+		"+ create-index transactions-000001",
+		"+ create-alias transactions -> transactions-000001",
+	}
+	if lines := changesAsStrings(changes); !reflect.DeepEqual(expectedChanges, lines) {
+		t.Fatalf("expected the changes %v, got %v", expectedChanges, lines)
+	}
+}
@@ -0,0 +1,79 @@
+package plan
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const missingValue = "<missing>"
+
+// comparedTemplateFields holds the fields of a template compared with the cluster. The order and the version are set
+// by the cluster when they are missing from the template
+var comparedTemplateFields = []string{"index_patterns", "settings", "mappings", "aliases"}
+
+// compareTemplates returns, sorted by path, the values of the template stored in the cluster which differ from the
+// values of the template from the config
+func compareTemplates(desired map[string]interface{}, actual map[string]interface{}) []string {
+	desiredValues := flattenTemplate(desired)
+	actualValues := flattenTemplate(actual)
+
+	differences := make([]string, 0)
+	for path, desiredValue := range desiredValues {
+		actualValue, found := actualValues[path]
+		if !found {
+			actualValue = missingValue
+		}
+		if actualValue != desiredValue {
+			differences = append(differences, fmt.Sprintf("%s: cluster %s, template %s", path, actualValue, desiredValue))
+		}
+	}
+	for path, actualValue := range actualValues {
+		if _, found := desiredValues[path]; !found {
+			differences = append(differences, fmt.Sprintf("%s: cluster %s, template %s", path, actualValue, missingValue))
+		}
+	}
+
+	sort.Strings(differences)
+
+	return differences
+}
+
+// flattenTemplate returns the compared values of a template by their path. The cluster returns the settings nested
+// under the "index" object and as strings, so the settings are normalized in the same way
+func flattenTemplate(template map[string]interface{}) map[string]string {
+	values := make(map[string]string)
+	for _, field := range comparedTemplateFields {
+		fieldValues := make(map[string]string)
+		flatten(field, template[field], fieldValues)
+
+		for path, value := range fieldValues {
+			if field == "settings" && !strings.HasPrefix(path, "settings.index.") {
+				path = "settings.index." + strings.TrimPrefix(path, "settings.")
+			}
+			values[path] = value
+		}
+	}
+
+	return values
+}
+
+func flatten(path string, value interface{}, values map[string]string) {
+	switch typedValue := value.(type) {
+	case nil:
+		return
+	case map[string]interface{}:
+		for key, child := range typedValue {
+			flatten(path+"."+key, child, values)
+		}
+	case []interface{}:
+		for idx, child := range typedValue {
+			flatten(path+"."+strconv.Itoa(idx), child, values)
+		}
+	case float64:
+		values[path] = strconv.FormatFloat(typedValue, 'f', -1, 64)
+	default:
+		values[path] = fmt.Sprintf("%v", typedValue)
+	}
+}
@@ -0,0 +1,109 @@
+package plan
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+const testTemplate = `{
+	"index_patterns": ["transactions-*"],
+	"settings": {"number_of_shards": 3},
+	"mappings": {
+		"properties": {
+			"nonce": {"type": "double"},
+			"sender": {"type": "keyword"}
+		}
+	},
+	"aliases": {}
+}`
+
+func decodeTestTemplate(t *testing.T, template string) map[string]interface{} {
+	decoded := make(map[string]interface{})
+	err := json.Unmarshal([]byte(template), &decoded)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	return decoded
+}
+
+func TestCompareTemplates(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name                string
+		actual              string
+		expectedDifferences []string
+	}{
+		{
+			name: "no changes",
+			actual: `{
+				"order": 0,
+				"version": 2,
+				"index_patterns": ["transactions-*"],
+				"settings": {"index": {"number_of_shards": "3"}},
+				"mappings": {"properties": {"sender": {"type": "keyword"}, "nonce": {"type": "double"}}},
+				"aliases": {}
+			}`,
+			expectedDifferences: []string{},
+		},
+		{
+			name: "field added to the template",
+			actual: `{
+				"index_patterns": ["transactions-*"],
+				"settings": {"index": {"number_of_shards": "3"}},
+				"mappings": {"properties": {"sender": {"type": "keyword"}}}
+			}`,
+			expectedDifferences: []string{
+				"mappings.properties.nonce.type: cluster <missing>, template double",
+			},
+		},
+		{
+			name: "field removed from the template",
+			actual: `{
+				"index_patterns": ["transactions-*"],
+				"settings": {"index": {"number_of_shards": "3"}},
+				"mappings": {"properties": {"sender": {"type": "keyword"}, "nonce": {"type": "double"}, "round": {"type": "long"}}}
+			}`,
+			expectedDifferences: []string{
+				"mappings.properties.round.type: cluster long, template <missing>",
+			},
+		},
+		{
+			name: "changed field",
+			actual: `{
+				"index_patterns": ["transactions-*"],
+				"settings": {"index": {"number_of_shards": "3"}},
+				"mappings": {"properties": {"sender": {"type": "text"}, "nonce": {"type": "double"}}}
+			}`,
+			expectedDifferences: []string{
+				"mappings.properties.sender.type: cluster text, template keyword",
+			},
+		},
+		{
+			name: "changed setting and index pattern",
+			actual: `{
+				"index_patterns": ["txs-*"],
+				"settings": {"index": {"number_of_shards": "5"}},
+				"mappings": {"properties": {"sender": {"type": "keyword"}, "nonce": {"type": "double"}}}
+			}`,
+			expectedDifferences: []string{
+				"index_patterns.0: cluster txs-*, template transactions-*",
+				"settings.index.number_of_shards: cluster 5, template 3",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			differences := compareTemplates(decodeTestTemplate(t, testTemplate), decodeTestTemplate(t, tt.actual))
+			if !reflect.DeepEqual(tt.expectedDifferences, differences) {
+				t.Fatalf("expected the differences %v, got %v", tt.expectedDifferences, differences)
+			}
+		})
+	}
+}
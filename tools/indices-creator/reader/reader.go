@@ -5,8 +5,13 @@ import (
 	"fmt"
 	"io/ioutil"
 	"path/filepath"
+
+	"github.com/kalyan3104/k-chain-es-indexer-go/process/dataindexer"
+	"github.com/kalyan3104/k-chain-es-indexer-go/process/elasticproc/templatesAndPolicies"
 )
 
+const policySuffix = "_policy"
+
 // GetElasticTemplatesAndPolicies will return elastic templates and policies
 // TODO implement policies when will start to use it again
 func GetElasticTemplatesAndPolicies(path string, indexes []string) (map[string]*bytes.Buffer, map[string]*bytes.Buffer, error) {
@@ -42,3 +47,34 @@ func getTemplateByIndex(path string, index string) (*bytes.Buffer, error) {
 
 	return indexTemplate, nil
 }
+
+// GetEmbeddedTemplatesAndPolicies will return the templates and policies used by the indexer, the opendistro set when
+// kibana is used and the noKibana set otherwise. Only the enabled indices, their policies and the opendistro template
+// are returned
+func GetEmbeddedTemplatesAndPolicies(useKibana bool, indexes []string) (map[string]*bytes.Buffer, map[string]*bytes.Buffer, error) {
+	allTemplates, allPolicies, err := templatesAndPolicies.CreateTemplatesAndPoliciesReader(useKibana).GetElasticTemplatesAndPolicies()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	indexTemplates := make(map[string]*bytes.Buffer)
+	indexPolicies := make(map[string]*bytes.Buffer)
+	if openDistroTemplate, found := allTemplates[dataindexer.OpenDistroIndex]; found {
+		indexTemplates[dataindexer.OpenDistroIndex] = openDistroTemplate
+	}
+
+	for _, index := range indexes {
+		template, found := allTemplates[index]
+		if !found {
+			return nil, nil, fmt.Errorf("GetEmbeddedTemplatesAndPolicies: no template for index %s", index)
+		}
+		indexTemplates[index] = template
+
+		policy, found := allPolicies[index+policySuffix]
+		if found {
+			indexPolicies[index+policySuffix] = policy
+		}
+	}
+
+	return indexTemplates, indexPolicies, nil
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	logger "github.com/kalyan3104/k-chain-logger-go"
+	"github.com/urfave/cli"
+)
+
+var (
+	log = logger.GetOrCreate("main")
+
+	// defines the path to the config folder
+	configPath = cli.StringFlag{
+		Name:  "config-path",
+		Usage: "The path to the config folder",
+		Value: "./",
+	}
+	dryRun = cli.BoolFlag{
+		Name:  "dry-run",
+		Usage: "If set, the snapshots which are not kept by the retention are only printed",
+	}
+	snapshotName = cli.StringFlag{
+		Name:  "snapshot",
+		Usage: "The name of the snapshot to be restored",
+	}
+	swapAliases = cli.BoolFlag{
+		Name:  "swap-aliases",
+		Usage: "If set, the aliases are moved to the restored indices after all the indices are restored",
+	}
+	replaceIndices = cli.BoolFlag{
+		Name: "replace-indices",
+		Usage: "If set together with the swap aliases flag, the existing indices saved without an alias, like the " +
+			"values index, are deleted and replaced by aliases of the restored indices",
+	}
+	logLevel = cli.StringFlag{
+		Name: "log-level",
+		Usage: "This flag specifies the logger `level(s)`. It can contain multiple comma-separated value. For example" +
+			", if set to *:INFO the logs for all packages will have the INFO level. However, if set to *:INFO,api:DEBUG" +
+			" the logs for all packages will have the INFO level, excepting the api package which will receive a DEBUG" +
+			" log level.",
+		Value: "*:" + logger.LogInfo.String(),
+	}
+)
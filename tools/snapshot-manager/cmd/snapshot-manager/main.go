@@ -0,0 +1,268 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"sort"
+	"time"
+
+	"github.com/elastic/go-elasticsearch/v7"
+	"github.com/kalyan3104/k-chain-es-indexer-go/tools/snapshot-manager/pkg/client"
+	"github.com/kalyan3104/k-chain-es-indexer-go/tools/snapshot-manager/pkg/config"
+	"github.com/kalyan3104/k-chain-es-indexer-go/tools/snapshot-manager/pkg/snapshot"
+	logger "github.com/kalyan3104/k-chain-logger-go"
+	"github.com/pelletier/go-toml"
+	"github.com/urfave/cli"
+)
+
+const (
+	configFileName = "config.toml"
+	// valuesIndex holds the indexer checkpoint, so it is saved in every snapshot together with the indices
+	valuesIndex = "values"
+)
+
+const helpTemplate = `NAME:
+   {{.Name}} - {{.Usage}}
+USAGE:
+   {{.HelpName}} {{if .VisibleFlags}}[global options]{{end}}{{if .Commands}} command [command options]{{end}}
+   {{if len .Authors}}
+AUTHOR:
+   {{range .Authors}}{{ . }}{{end}}
+   {{end}}{{if .Commands}}
+COMMANDS:
+   {{range .Commands}}{{join .Names ", "}}{{ "\t" }}{{.Usage}}
+   {{end}}
+GLOBAL OPTIONS:
+   {{range .VisibleFlags}}{{.}}
+   {{end}}
+VERSION:
+   {{.Version}}
+   {{end}}
+`
+
+func main() {
+	app := cli.NewApp()
+	cli.AppHelpTemplate = helpTemplate
+	app.Name = "Snapshot manager"
+	app.Version = "v1.0.0"
+	app.Usage = "Takes, prunes and restores the snapshots of the indexer indices"
+	app.Flags = []cli.Flag{
+		configPath,
+		logLevel,
+	}
+	app.Commands = []cli.Command{
+		{
+			Name:   "register-repository",
+			Usage:  "registers the shared filesystem repository of the snapshots",
+			Action: registerRepository,
+		},
+		{
+			Name:   "snapshot",
+			Usage:  "takes a snapshot of all the configured indices, the values index included",
+			Action: createSnapshot,
+		},
+		{
+			Name:   "list",
+			Usage:  "lists the snapshots taken by this tool",
+			Action: listSnapshots,
+		},
+		{
+			Name:   "prune",
+			Usage:  "deletes the snapshots which are not kept by the retention",
+			Flags:  []cli.Flag{dryRun},
+			Action: pruneSnapshots,
+		},
+		{
+			Name:   "restore",
+			Usage:  "restores a snapshot into new indices, named with the snapshot name as suffix",
+			Flags:  []cli.Flag{snapshotName, swapAliases, replaceIndices},
+			Action: restoreSnapshot,
+		},
+	}
+	app.Authors = []cli.Author{
+		{
+			Name:  "The kalyan Team",
+			Email: "contact@kalyan.com",
+		},
+	}
+
+	err := app.Run(os.Args)
+	if err != nil {
+		log.Error(err.Error())
+		os.Exit(1)
+	}
+}
+
+func registerRepository(ctx *cli.Context) error {
+	manager, cfg, err := createSnapshotManager(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = manager.RegisterRepository()
+	if err != nil {
+		return fmt.Errorf("%w while registering the repository", err)
+	}
+
+	log.Info("repository registered", "name", cfg.Repository.Name, "location", cfg.Repository.Location)
+
+	return nil
+}
+
+func createSnapshot(ctx *cli.Context) error {
+	manager, _, err := createSnapshotManager(ctx)
+	if err != nil {
+		return err
+	}
+
+	name, err := manager.CreateSnapshot()
+	if err != nil {
+		return fmt.Errorf("%w while taking the snapshot", err)
+	}
+
+	log.Info("snapshot taken", "snapshot", name)
+
+	return nil
+}
+
+func listSnapshots(ctx *cli.Context) error {
+	manager, _, err := createSnapshotManager(ctx)
+	if err != nil {
+		return err
+	}
+
+	snapshots, err := manager.ListSnapshots()
+	if err != nil {
+		return err
+	}
+
+	for _, info := range snapshots {
+		duration := time.Duration(info.EndTimeInMillis-info.StartTimeInMillis) * time.Millisecond
+		fmt.Printf("%s\t%s\t%s\t%d indices\t%s\n",
+			info.Name, info.State, info.StartTime().UTC().Format(time.RFC3339), len(info.Indices), duration)
+	}
+
+	return nil
+}
+
+func pruneSnapshots(ctx *cli.Context) error {
+	manager, _, err := createSnapshotManager(ctx)
+	if err != nil {
+		return err
+	}
+
+	isDryRun := ctx.Bool(dryRun.Name)
+	deleted, err := manager.Prune(isDryRun)
+	for _, name := range deleted {
+		log.Info("snapshot not kept by the retention", "snapshot", name, "deleted", !isDryRun)
+	}
+
+	return err
+}
+
+func restoreSnapshot(ctx *cli.Context) error {
+	name := ctx.String(snapshotName.Name)
+	if name == "" {
+		return fmt.Errorf("the %s flag is required", snapshotName.Name)
+	}
+
+	manager, _, err := createSnapshotManager(ctx)
+	if err != nil {
+		return err
+	}
+
+	restored, err := manager.Restore(snapshot.ArgsRestore{
+		Snapshot:       name,
+		SwapAliases:    ctx.Bool(swapAliases.Name),
+		ReplaceIndices: ctx.Bool(replaceIndices.Name),
+	})
+	if err != nil {
+		return fmt.Errorf("%w while restoring the snapshot", err)
+	}
+
+	indices := make([]string, 0, len(restored))
+	for index := range restored {
+		indices = append(indices, index)
+	}
+	sort.Strings(indices)
+	for _, index := range indices {
+		log.Info("index restored", "index", index, "restored index", restored[index])
+	}
+
+	return nil
+}
+
+type snapshotManagerHandler interface {
+	RegisterRepository() error
+	CreateSnapshot() (string, error)
+	ListSnapshots() ([]*snapshot.Info, error)
+	Prune(dryRun bool) ([]string, error)
+	Restore(args snapshot.ArgsRestore) (map[string]string, error)
+}
+
+func createSnapshotManager(ctx *cli.Context) (snapshotManagerHandler, *config.Config, error) {
+	err := logger.SetLogLevel(ctx.GlobalString(logLevel.Name))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	cfg, err := loadConfigFile(ctx.GlobalString(configPath.Name))
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w while loading the config file", err)
+	}
+
+	esClient, err := client.NewElasticClient(elasticsearch.Config{
+		Addresses: []string{cfg.Cluster.URL},
+		Username:  cfg.Cluster.User,
+		Password:  cfg.Cluster.Password,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	manager, err := snapshot.NewSnapshotManager(snapshot.ArgsSnapshotManager{
+		Client:      esClient,
+		Repository:  cfg.Repository.Name,
+		Location:    cfg.Repository.Location,
+		Compress:    cfg.Repository.Compress,
+		NamePrefix:  cfg.Snapshot.NamePrefix,
+		Indices:     withValuesIndex(cfg.Snapshot.Indices),
+		BlockWrites: cfg.Snapshot.BlockWrites,
+		KeepLast:    cfg.Retention.KeepLast,
+		MaxAge:      time.Duration(cfg.Retention.MaxAgeInDays) * 24 * time.Hour,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return manager, cfg, nil
+}
+
+// withValuesIndex adds the values index to the saved indices, as a restore without the indexer checkpoint would be
+// inconsistent
+func withValuesIndex(indices []string) []string {
+	for _, index := range indices {
+		if index == valuesIndex {
+			return indices
+		}
+	}
+
+	return append(indices, valuesIndex)
+}
+
+func loadConfigFile(pathStr string) (*config.Config, error) {
+	tomlBytes, err := ioutil.ReadFile(path.Join(pathStr, configFileName))
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := &config.Config{}
+	err = toml.Unmarshal(tomlBytes, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
@@ -0,0 +1,26 @@
+module github.com/kalyan3104/k-chain-es-indexer-go/tools/snapshot-manager
+
+go 1.17
+
+require (
+	github.com/elastic/go-elasticsearch/v7 v7.12.0
+	github.com/kalyan3104/k-chain-core-go v1.1.30
+	github.com/kalyan3104/k-chain-logger-go v1.0.11
+	github.com/pelletier/go-toml v1.9.3
+	github.com/tidwall/gjson v1.14.0
+	github.com/urfave/cli v1.22.5
+)
+
+require (
+	github.com/cpuguy83/go-md2man/v2 v2.0.0-20190314233015-f79a8a8ca69d // indirect
+	github.com/denisbrodbeck/machineid v1.0.1 // indirect
+	github.com/gogo/protobuf v1.3.2 // indirect
+	github.com/golang/protobuf v1.5.2 // indirect
+	github.com/mr-tron/base58 v1.2.0 // indirect
+	github.com/russross/blackfriday/v2 v2.0.1 // indirect
+	github.com/shurcooL/sanitized_anchor_name v1.0.0 // indirect
+	github.com/tidwall/match v1.1.1 // indirect
+	github.com/tidwall/pretty v1.2.0 // indirect
+	golang.org/x/sys v0.2.0 // indirect
+	google.golang.org/protobuf v1.26.0 // indirect
+)
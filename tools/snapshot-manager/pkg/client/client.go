@@ -0,0 +1,186 @@
+package client
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/elastic/go-elasticsearch/v7"
+	"github.com/elastic/go-elasticsearch/v7/esapi"
+	logger "github.com/kalyan3104/k-chain-logger-go"
+	"github.com/tidwall/gjson"
+)
+
+const snapshotStateSuccess = "SUCCESS"
+
+var log = logger.GetOrCreate("snapshot-manager/pkg/client")
+
+type esClient struct {
+	client *elasticsearch.Client
+}
+
+// NewElasticClient will create a new instance of an esClient
+func NewElasticClient(cfg elasticsearch.Config) (*esClient, error) {
+	elasticClient, err := elasticsearch.NewClient(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return &esClient{
+		client: elasticClient,
+	}, nil
+}
+
+// CreateRepository will register, or update, the provided snapshot repository
+func (esc *esClient) CreateRepository(repository string, body []byte) error {
+	res, err := esc.client.Snapshot.CreateRepository(repository, bytes.NewBuffer(body))
+	if err != nil {
+		return err
+	}
+
+	_, err = getBytesFromResponse(res)
+
+	return err
+}
+
+// CreateSnapshot will take the provided snapshot and wait for it to complete. A snapshot which does not save all the
+// shards returns an error
+func (esc *esClient) CreateSnapshot(repository string, snapshot string, body []byte) error {
+	res, err := esc.client.Snapshot.Create(
+		repository,
+		snapshot,
+		esc.client.Snapshot.Create.WithBody(bytes.NewBuffer(body)),
+		esc.client.Snapshot.Create.WithWaitForCompletion(true),
+	)
+	if err != nil {
+		return err
+	}
+
+	bodyBytes, err := getBytesFromResponse(res)
+	if err != nil {
+		return err
+	}
+
+	state := gjson.GetBytes(bodyBytes, "snapshot.state").String()
+	if state != snapshotStateSuccess {
+		return fmt.Errorf("snapshot %s ended with state %s, failures: %s",
+			snapshot, state, gjson.GetBytes(bodyBytes, "snapshot.failures").Raw)
+	}
+
+	return nil
+}
+
+// GetSnapshots will return the response with the provided snapshots of the repository
+func (esc *esClient) GetSnapshots(repository string, snapshots []string) ([]byte, error) {
+	res, err := esc.client.Snapshot.Get(repository, snapshots)
+	if err != nil {
+		return nil, err
+	}
+
+	return getBytesFromResponse(res)
+}
+
+// DeleteSnapshot will delete the provided snapshot from the repository
+func (esc *esClient) DeleteSnapshot(repository string, snapshot string) error {
+	res, err := esc.client.Snapshot.Delete(repository, snapshot)
+	if err != nil {
+		return err
+	}
+
+	_, err = getBytesFromResponse(res)
+
+	return err
+}
+
+// RestoreSnapshot will restore the provided snapshot and wait for the restored shards to recover
+func (esc *esClient) RestoreSnapshot(repository string, snapshot string, body []byte) error {
+	res, err := esc.client.Snapshot.Restore(
+		repository,
+		snapshot,
+		esc.client.Snapshot.Restore.WithBody(bytes.NewBuffer(body)),
+		esc.client.Snapshot.Restore.WithWaitForCompletion(true),
+	)
+	if err != nil {
+		return err
+	}
+
+	_, err = getBytesFromResponse(res)
+
+	return err
+}
+
+// GetAliases will return the response with the aliases of the indices resolved from the provided names, which can be
+// indices or aliases. The missing names are ignored
+func (esc *esClient) GetAliases(names []string) ([]byte, error) {
+	res, err := esc.client.Indices.GetAlias(
+		esc.client.Indices.GetAlias.WithIndex(names...),
+		esc.client.Indices.GetAlias.WithIgnoreUnavailable(true),
+	)
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode == http.StatusNotFound {
+		closeBody(res)
+		return []byte("{}"), nil
+	}
+
+	return getBytesFromResponse(res)
+}
+
+// SetWriteBlock will block or allow the writes in the provided indices
+func (esc *esClient) SetWriteBlock(indices []string, blocked bool) error {
+	body := fmt.Sprintf(`{"index":{"blocks":{"write":%t}}}`, blocked)
+	res, err := esc.client.Indices.PutSettings(
+		strings.NewReader(body),
+		esc.client.Indices.PutSettings.WithIndex(indices...),
+	)
+	if err != nil {
+		return err
+	}
+
+	_, err = getBytesFromResponse(res)
+
+	return err
+}
+
+// UpdateAliases will apply atomically the provided aliases actions
+func (esc *esClient) UpdateAliases(body []byte) error {
+	res, err := esc.client.Indices.UpdateAliases(bytes.NewBuffer(body))
+	if err != nil {
+		return err
+	}
+
+	_, err = getBytesFromResponse(res)
+
+	return err
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (esc *esClient) IsInterfaceNil() bool {
+	return esc == nil
+}
+
+func getBytesFromResponse(res *esapi.Response) ([]byte, error) {
+	defer closeBody(res)
+
+	bodyBytes, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+	if res.IsError() {
+		return nil, fmt.Errorf("error response: %s", bodyBytes)
+	}
+
+	return bodyBytes, nil
+}
+
+func closeBody(res *esapi.Response) {
+	if res != nil && res.Body != nil {
+		err := res.Body.Close()
+		if err != nil {
+			log.Warn("cannot close response body", "error", err)
+		}
+	}
+}
@@ -0,0 +1,28 @@
+package config
+
+// Config holds the configuration of the snapshot manager
+type Config struct {
+	Cluster struct {
+		URL      string `toml:"url"`
+		User     string `toml:"user"`
+		Password string `toml:"password"`
+	} `toml:"cluster"`
+	Repository struct {
+		Name string `toml:"name"`
+		// Location is the directory of the repository on every node, which has to be listed in the path.repo setting
+		Location string `toml:"location"`
+		Compress bool   `toml:"compress"`
+	} `toml:"repository"`
+	Snapshot struct {
+		NamePrefix string `toml:"name-prefix"`
+		// Indices holds the aliases or indices saved in every snapshot
+		Indices []string `toml:"indices"`
+		// BlockWrites blocks the writes in the indices while the snapshot is taken, so all the indices are saved at
+		// the same moment
+		BlockWrites bool `toml:"block-writes"`
+	} `toml:"snapshot"`
+	Retention struct {
+		KeepLast     int `toml:"keep-last"`
+		MaxAgeInDays int `toml:"max-age-in-days"`
+	} `toml:"retention"`
+}
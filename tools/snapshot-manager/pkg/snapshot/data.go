@@ -0,0 +1,77 @@
+package snapshot
+
+import (
+	"sort"
+	"time"
+)
+
+// NameMetadata holds the indices saved in a snapshot for an alias or an index from the config
+type NameMetadata struct {
+	IsAlias bool     `json:"isAlias"`
+	Indices []string `json:"indices"`
+	// WriteIndex is the index where the alias writes, when the alias has more indices
+	WriteIndex string `json:"writeIndex,omitempty"`
+}
+
+// Metadata is saved together with a snapshot so the aliases can be created again when the snapshot is restored
+type Metadata struct {
+	Names map[string]*NameMetadata `json:"names"`
+}
+
+// Info holds the details of a snapshot from the repository
+type Info struct {
+	Name              string    `json:"snapshot"`
+	State             string    `json:"state"`
+	StartTimeInMillis int64     `json:"start_time_in_millis"`
+	EndTimeInMillis   int64     `json:"end_time_in_millis"`
+	Indices           []string  `json:"indices"`
+	Metadata          *Metadata `json:"metadata"`
+}
+
+// StartTime returns the moment the snapshot started
+func (i *Info) StartTime() time.Time {
+	return time.Unix(0, i.StartTimeInMillis*int64(time.Millisecond))
+}
+
+type snapshotsResponse struct {
+	Snapshots []*Info `json:"snapshots"`
+}
+
+type aliasProperties struct {
+	IsWriteIndex *bool `json:"is_write_index"`
+}
+
+// aliasesResponse holds, by index, the aliases of every index
+type aliasesResponse map[string]struct {
+	Aliases map[string]aliasProperties `json:"aliases"`
+}
+
+// isIndex returns true if the provided name is an index and not an alias
+func (ar aliasesResponse) isIndex(name string) bool {
+	_, found := ar[name]
+	return found
+}
+
+// aliasIndices returns the sorted indices of the provided alias and the index where the alias writes
+func (ar aliasesResponse) aliasIndices(alias string) ([]string, string) {
+	indices := make([]string, 0)
+	writeIndex := ""
+	for index, indexAliases := range ar {
+		properties, found := indexAliases.Aliases[alias]
+		if !found {
+			continue
+		}
+
+		indices = append(indices, index)
+		if properties.IsWriteIndex != nil && *properties.IsWriteIndex {
+			writeIndex = index
+		}
+	}
+	sort.Strings(indices)
+
+	if writeIndex == "" && len(indices) == 1 {
+		writeIndex = indices[0]
+	}
+
+	return indices, writeIndex
+}
@@ -0,0 +1,27 @@
+package snapshot
+
+import "errors"
+
+// ErrNilElasticClient signals that a nil elastic client was provided
+var ErrNilElasticClient = errors.New("nil elastic client")
+
+// ErrEmptyRepository signals that an empty repository name or location was provided
+var ErrEmptyRepository = errors.New("empty repository name or location")
+
+// ErrNoIndices signals that no index was provided to be saved in the snapshots
+var ErrNoIndices = errors.New("no indices")
+
+// ErrInvalidRetention signals that the retention would delete all the snapshots
+var ErrInvalidRetention = errors.New("the retention has to keep at least the last snapshot")
+
+// ErrIndexNotFound signals that an index or alias to be saved does not exist
+var ErrIndexNotFound = errors.New("index or alias not found")
+
+// ErrSnapshotNotFound signals that the requested snapshot does not exist in the repository
+var ErrSnapshotNotFound = errors.New("snapshot not found")
+
+// ErrMissingSnapshotMetadata signals that a snapshot was not taken by this tool, so the aliases cannot be restored
+var ErrMissingSnapshotMetadata = errors.New("the snapshot has no indices metadata")
+
+// ErrIndexAlreadyExists signals that an index to be restored already exists
+var ErrIndexAlreadyExists = errors.New("the restored index already exists")
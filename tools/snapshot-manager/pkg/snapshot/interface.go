@@ -0,0 +1,14 @@
+package snapshot
+
+// ElasticClient defines what an elastic client should do
+type ElasticClient interface {
+	CreateRepository(repository string, body []byte) error
+	CreateSnapshot(repository string, snapshot string, body []byte) error
+	GetSnapshots(repository string, snapshots []string) ([]byte, error)
+	DeleteSnapshot(repository string, snapshot string) error
+	RestoreSnapshot(repository string, snapshot string, body []byte) error
+	GetAliases(names []string) ([]byte, error)
+	SetWriteBlock(indices []string, blocked bool) error
+	UpdateAliases(body []byte) error
+	IsInterfaceNil() bool
+}
@@ -0,0 +1,234 @@
+package snapshot
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/kalyan3104/k-chain-core-go/core/check"
+	logger "github.com/kalyan3104/k-chain-logger-go"
+)
+
+const snapshotTimeFormat = "20060102-150405"
+
+var log = logger.GetOrCreate("snapshot-manager/pkg/snapshot")
+
+// ArgsSnapshotManager holds the arguments needed to create a snapshot manager
+type ArgsSnapshotManager struct {
+	Client     ElasticClient
+	Repository string
+	// Location is the directory of the filesystem repository on every node
+	Location   string
+	Compress   bool
+	NamePrefix string
+	// Indices holds the aliases or indices saved in every snapshot
+	Indices     []string
+	BlockWrites bool
+	KeepLast    int
+	MaxAge      time.Duration
+}
+
+type snapshotManager struct {
+	client      ElasticClient
+	repository  string
+	location    string
+	compress    bool
+	namePrefix  string
+	indices     []string
+	blockWrites bool
+	keepLast    int
+	maxAge      time.Duration
+}
+
+// NewSnapshotManager creates a component that takes, prunes and restores the snapshots of the indexer indices
+func NewSnapshotManager(args ArgsSnapshotManager) (*snapshotManager, error) {
+	if check.IfNil(args.Client) {
+		return nil, ErrNilElasticClient
+	}
+	if args.Repository == "" || args.Location == "" {
+		return nil, ErrEmptyRepository
+	}
+	if len(args.Indices) == 0 {
+		return nil, ErrNoIndices
+	}
+	if args.KeepLast < 1 {
+		return nil, ErrInvalidRetention
+	}
+
+	return &snapshotManager{
+		client:      args.Client,
+		repository:  args.Repository,
+		location:    args.Location,
+		compress:    args.Compress,
+		namePrefix:  args.NamePrefix,
+		indices:     args.Indices,
+		blockWrites: args.BlockWrites,
+		keepLast:    args.KeepLast,
+		maxAge:      args.MaxAge,
+	}, nil
+}
+
+// RegisterRepository will register the shared filesystem repository, or update its settings
+func (sm *snapshotManager) RegisterRepository() error {
+	body, err := json.Marshal(object{
+		"type": "fs",
+		"settings": object{
+			"location": sm.location,
+			"compress": sm.compress,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	return sm.client.CreateRepository(sm.repository, body)
+}
+
+// CreateSnapshot will take a snapshot of the indices of all the configured aliases and indices and returns its name.
+// The aliases are saved in the snapshot metadata, so they can be restored together with the indices
+func (sm *snapshotManager) CreateSnapshot() (string, error) {
+	names, err := sm.resolveNames()
+	if err != nil {
+		return "", err
+	}
+
+	indices := make([]string, 0)
+	for _, nameMetadata := range names {
+		indices = append(indices, nameMetadata.Indices...)
+	}
+	sort.Strings(indices)
+
+	if sm.blockWrites {
+		err = sm.client.SetWriteBlock(indices, true)
+		if err != nil {
+			return "", fmt.Errorf("%w while blocking the writes", err)
+		}
+		defer sm.allowWrites(indices)
+	}
+
+	body, err := json.Marshal(object{
+		"indices":              strings.Join(indices, ","),
+		"ignore_unavailable":   false,
+		"include_global_state": false,
+		"metadata":             &Metadata{Names: names},
+	})
+	if err != nil {
+		return "", err
+	}
+
+	snapshotName := fmt.Sprintf("%s-%s", sm.namePrefix, time.Now().UTC().Format(snapshotTimeFormat))
+	log.Info("taking snapshot", "snapshot", snapshotName, "num indices", len(indices), "block writes", sm.blockWrites)
+
+	err = sm.client.CreateSnapshot(sm.repository, snapshotName, body)
+	if err != nil {
+		return "", err
+	}
+
+	return snapshotName, nil
+}
+
+func (sm *snapshotManager) allowWrites(indices []string) {
+	err := sm.client.SetWriteBlock(indices, false)
+	if err != nil {
+		log.Error("cannot allow the writes, the write block has to be removed manually",
+			"indices", strings.Join(indices, ","),
+			"error", err.Error(),
+		)
+	}
+}
+
+// resolveNames returns the indices of every configured alias or index
+func (sm *snapshotManager) resolveNames() (map[string]*NameMetadata, error) {
+	aliases, err := sm.getAliases(sm.indices)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make(map[string]*NameMetadata, len(sm.indices))
+	for _, name := range sm.indices {
+		if aliases.isIndex(name) {
+			names[name] = &NameMetadata{Indices: []string{name}, WriteIndex: name}
+			continue
+		}
+
+		indices, writeIndex := aliases.aliasIndices(name)
+		if len(indices) == 0 {
+			return nil, fmt.Errorf("%w: %s", ErrIndexNotFound, name)
+		}
+
+		names[name] = &NameMetadata{IsAlias: true, Indices: indices, WriteIndex: writeIndex}
+	}
+
+	return names, nil
+}
+
+// ListSnapshots returns the snapshots taken by this tool, sorted from the oldest one
+func (sm *snapshotManager) ListSnapshots() ([]*Info, error) {
+	responseBytes, err := sm.client.GetSnapshots(sm.repository, []string{"_all"})
+	if err != nil {
+		return nil, err
+	}
+
+	response := &snapshotsResponse{}
+	err = json.Unmarshal(responseBytes, response)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshots := make([]*Info, 0, len(response.Snapshots))
+	for _, snapshot := range response.Snapshots {
+		if strings.HasPrefix(snapshot.Name, sm.namePrefix+"-") {
+			snapshots = append(snapshots, snapshot)
+		}
+	}
+	sort.Slice(snapshots, func(i, j int) bool {
+		return snapshots[i].StartTimeInMillis < snapshots[j].StartTimeInMillis
+	})
+
+	return snapshots, nil
+}
+
+// Prune will delete the snapshots which are not kept by the retention and returns their names. With dry run the
+// snapshots are only returned
+func (sm *snapshotManager) Prune(dryRun bool) ([]string, error) {
+	snapshots, err := sm.ListSnapshots()
+	if err != nil {
+		return nil, err
+	}
+
+	toDelete := selectSnapshotsToDelete(snapshots, time.Now(), sm.keepLast, sm.maxAge)
+	deleted := make([]string, 0, len(toDelete))
+	for _, snapshot := range toDelete {
+		if !dryRun {
+			err = sm.client.DeleteSnapshot(sm.repository, snapshot.Name)
+			if err != nil {
+				return deleted, fmt.Errorf("%w while deleting snapshot %s", err, snapshot.Name)
+			}
+		}
+
+		deleted = append(deleted, snapshot.Name)
+	}
+
+	return deleted, nil
+}
+
+func (sm *snapshotManager) getAliases(names []string) (aliasesResponse, error) {
+	responseBytes, err := sm.client.GetAliases(names)
+	if err != nil {
+		return nil, err
+	}
+
+	response := make(aliasesResponse)
+	err = json.Unmarshal(responseBytes, &response)
+
+	return response, err
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (sm *snapshotManager) IsInterfaceNil() bool {
+	return sm == nil
+}
+
+type object = map[string]interface{}
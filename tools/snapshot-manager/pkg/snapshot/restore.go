@@ -0,0 +1,164 @@
+package snapshot
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ArgsRestore holds the arguments of a restore
+type ArgsRestore struct {
+	Snapshot string
+	// SwapAliases moves the aliases to the restored indices
+	SwapAliases bool
+	// ReplaceIndices deletes the existing indices saved without an alias, like the values index, and creates aliases
+	// with their names for the restored indices. It is used only together with SwapAliases
+	ReplaceIndices bool
+}
+
+// Restore will restore the indices of the provided snapshot with new names, every index having the snapshot name as
+// suffix, and returns the restored index of every saved index. The aliases are moved atomically after all the indices
+// are restored
+func (sm *snapshotManager) Restore(args ArgsRestore) (map[string]string, error) {
+	info, err := sm.getSnapshot(args.Snapshot)
+	if err != nil {
+		return nil, err
+	}
+	if info.Metadata == nil || len(info.Metadata.Names) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrMissingSnapshotMetadata, args.Snapshot)
+	}
+
+	suffix := "-" + args.Snapshot
+	restored := make(map[string]string)
+	for _, nameMetadata := range info.Metadata.Names {
+		for _, index := range nameMetadata.Indices {
+			restored[index] = index + suffix
+		}
+	}
+
+	err = sm.checkRestoredIndicesAreMissing(restored)
+	if err != nil {
+		return nil, err
+	}
+
+	indices := make([]string, 0, len(restored))
+	for index := range restored {
+		indices = append(indices, index)
+	}
+	sort.Strings(indices)
+
+	body, err := json.Marshal(object{
+		"indices":              strings.Join(indices, ","),
+		"ignore_unavailable":   false,
+		"include_global_state": false,
+		"include_aliases":      false,
+		"rename_pattern":       "(.+)",
+		"rename_replacement":   "$1" + suffix,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	log.Info("restoring snapshot", "snapshot", args.Snapshot, "num indices", len(indices))
+	err = sm.client.RestoreSnapshot(sm.repository, args.Snapshot, body)
+	if err != nil {
+		return nil, err
+	}
+
+	if !args.SwapAliases {
+		return restored, nil
+	}
+
+	err = sm.swapAliases(info.Metadata, restored, args.ReplaceIndices)
+	if err != nil {
+		return nil, fmt.Errorf("%w while swapping the aliases, the indices were restored", err)
+	}
+
+	return restored, nil
+}
+
+func (sm *snapshotManager) getSnapshot(snapshot string) (*Info, error) {
+	responseBytes, err := sm.client.GetSnapshots(sm.repository, []string{snapshot})
+	if err != nil {
+		return nil, err
+	}
+
+	response := &snapshotsResponse{}
+	err = json.Unmarshal(responseBytes, response)
+	if err != nil {
+		return nil, err
+	}
+	if len(response.Snapshots) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrSnapshotNotFound, snapshot)
+	}
+
+	return response.Snapshots[0], nil
+}
+
+func (sm *snapshotManager) checkRestoredIndicesAreMissing(restored map[string]string) error {
+	restoredIndices := make([]string, 0, len(restored))
+	for _, restoredIndex := range restored {
+		restoredIndices = append(restoredIndices, restoredIndex)
+	}
+
+	existing, err := sm.getAliases(restoredIndices)
+	if err != nil {
+		return err
+	}
+
+	for _, restoredIndex := range restoredIndices {
+		if existing.isIndex(restoredIndex) {
+			return fmt.Errorf("%w: %s", ErrIndexAlreadyExists, restoredIndex)
+		}
+	}
+
+	return nil
+}
+
+// swapAliases moves, in a single request, every saved alias from its current indices to the restored ones. A saved
+// index whose name is now an index is replaced only if allowed, as its documents are deleted
+func (sm *snapshotManager) swapAliases(metadata *Metadata, restored map[string]string, replaceIndices bool) error {
+	names := make([]string, 0, len(metadata.Names))
+	for name := range metadata.Names {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	current, err := sm.getAliases(names)
+	if err != nil {
+		return err
+	}
+
+	actions := make([]object, 0)
+	for _, name := range names {
+		if current.isIndex(name) {
+			if !replaceIndices {
+				log.Warn("the restored index did not replace the existing index with the same name", "index", name)
+				continue
+			}
+			actions = append(actions, object{"remove_index": object{"index": name}})
+		}
+
+		currentIndices, _ := current.aliasIndices(name)
+		for _, index := range currentIndices {
+			actions = append(actions, object{"remove": object{"index": index, "alias": name}})
+		}
+
+		nameMetadata := metadata.Names[name]
+		for _, index := range nameMetadata.Indices {
+			add := object{"index": restored[index], "alias": name}
+			if len(nameMetadata.Indices) > 1 {
+				add["is_write_index"] = index == nameMetadata.WriteIndex
+			}
+			actions = append(actions, object{"add": add})
+		}
+	}
+
+	body, err := json.Marshal(object{"actions": actions})
+	if err != nil {
+		return err
+	}
+
+	return sm.client.UpdateAliases(body)
+}
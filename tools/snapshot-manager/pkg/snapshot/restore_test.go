@@ -0,0 +1,288 @@
+package snapshot
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+)
+
+// elasticClientStub holds the aliases of every index of the cluster and the snapshots of the repository
+type elasticClientStub struct {
+	clusterAliases    map[string][]string
+	writeIndices      map[string]string
+	snapshots         []*Info
+	restoreBody       []byte
+	updateAliasesBody []byte
+}
+
+func (ecs *elasticClientStub) CreateRepository(_ string, _ []byte) error {
+	return nil
+}
+
+func (ecs *elasticClientStub) CreateSnapshot(_ string, _ string, _ []byte) error {
+	return nil
+}
+
+func (ecs *elasticClientStub) GetSnapshots(_ string, names []string) ([]byte, error) {
+	snapshots := make([]*Info, 0)
+	for _, snapshot := range ecs.snapshots {
+		if names[0] == "_all" || snapshot.Name == names[0] {
+			snapshots = append(snapshots, snapshot)
+		}
+	}
+
+	return json.Marshal(&snapshotsResponse{Snapshots: snapshots})
+}
+
+func (ecs *elasticClientStub) DeleteSnapshot(_ string, _ string) error {
+	return nil
+}
+
+func (ecs *elasticClientStub) RestoreSnapshot(_ string, _ string, body []byte) error {
+	ecs.restoreBody = body
+	return nil
+}
+
+// GetAliases returns, as Elasticsearch does, the indices matching the names and the indices of the matching aliases
+func (ecs *elasticClientStub) GetAliases(names []string) ([]byte, error) {
+	response := make(map[string]object)
+	for index, aliases := range ecs.clusterAliases {
+		isMatching := false
+		indexAliases := make(object)
+		for _, alias := range aliases {
+			aliasProperties := object{}
+			if ecs.writeIndices[alias] != "" {
+				aliasProperties["is_write_index"] = ecs.writeIndices[alias] == index
+			}
+			indexAliases[alias] = aliasProperties
+			isMatching = isMatching || containsString(names, alias)
+		}
+		if isMatching || containsString(names, index) {
+			response[index] = object{"aliases": indexAliases}
+		}
+	}
+
+	return json.Marshal(response)
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (ecs *elasticClientStub) SetWriteBlock(_ []string, _ bool) error {
+	return nil
+}
+
+func (ecs *elasticClientStub) UpdateAliases(body []byte) error {
+	ecs.updateAliasesBody = body
+	return nil
+}
+
+func (ecs *elasticClientStub) IsInterfaceNil() bool {
+	return ecs == nil
+}
+
+const testSnapshot = "indexer-20240310-120000"
+
+func createTestClusterAndSnapshot() *elasticClientStub {
+	return &elasticClientStub{
+		clusterAliases: map[string][]string{
+			"blocks-000001":       {"blocks"},
+			"transactions-000001": {"transactions"},
+			"transactions-000002": {"transactions"},
+			"values":              {},
+		},
+		writeIndices: map[string]string{
+			"transactions": "transactions-000002",
+		},
+		snapshots: []*Info{{
+			Name:  testSnapshot,
+			State: stateSuccess,
+			Metadata: &Metadata{Names: map[string]*NameMetadata{
+				"blocks":       {IsAlias: true, Indices: []string{"blocks-000001"}, WriteIndex: "blocks-000001"},
+				"transactions": {IsAlias: true, Indices: []string{"transactions-000001", "transactions-000002"}, WriteIndex: "transactions-000002"},
+				"values":       {Indices: []string{"values"}, WriteIndex: "values"},
+			}},
+		}},
+	}
+}
+
+func createTestSnapshotManager(t *testing.T, client ElasticClient) *snapshotManager {
+	sm, err := NewSnapshotManager(ArgsSnapshotManager{
+		Client:     client,
+		Repository: "indexer-backups",
+		Location:   "/snapshots",
+		NamePrefix: "indexer",
+		Indices:    []string{"blocks", "transactions", "values"},
+		KeepLast:   1,
+		MaxAge:     time.Hour,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return sm
+}
+
+func requireJSONEqual(t *testing.T, expected string, actual []byte) {
+	t.Helper()
+
+	var expectedObj, actualObj interface{}
+	err := json.Unmarshal([]byte(expected), &expectedObj)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = json.Unmarshal(actual, &actualObj)
+	if err != nil {
+		t.Fatalf("invalid json %s: %v", actual, err)
+	}
+
+	expectedBytes, _ := json.Marshal(expectedObj)
+	actualBytes, _ := json.Marshal(actualObj)
+	if string(expectedBytes) != string(actualBytes) {
+		t.Fatalf("expected %s, got %s", expectedBytes, actualBytes)
+	}
+}
+
+func TestSnapshotManager_RestoreShouldRenameTheIndices(t *testing.T) {
+	t.Parallel()
+
+	client := createTestClusterAndSnapshot()
+	sm := createTestSnapshotManager(t, client)
+
+	restored, err := sm.Restore(ArgsRestore{Snapshot: testSnapshot})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	suffix := "-" + testSnapshot
+	expectedRestored := map[string]string{
+		"blocks-000001":       "blocks-000001" + suffix,
+		"transactions-000001": "transactions-000001" + suffix,
+		"transactions-000002": "transactions-000002" + suffix,
+		"values":              "values" + suffix,
+	}
+	if len(restored) != len(expectedRestored) {
+		t.Fatalf("unexpected restored indices %v", restored)
+	}
+	for index, restoredIndex := range expectedRestored {
+		if restored[index] != restoredIndex {
+			t.Fatalf("unexpected restored indices %v", restored)
+		}
+	}
+
+	requireJSONEqual(t, `{
+		"indices": "blocks-000001,transactions-000001,transactions-000002,values",
+		"ignore_unavailable": false,
+		"include_global_state": false,
+		"include_aliases": false,
+		"rename_pattern": "(.+)",
+		"rename_replacement": "$1`+suffix+`"
+	}`, client.restoreBody)
+	if client.updateAliasesBody != nil {
+		t.Fatal("the aliases should not be changed without the swap")
+	}
+}
+
+func TestSnapshotManager_RestoreAliasesSwap(t *testing.T) {
+	t.Parallel()
+
+	suffix := "-" + testSnapshot
+	swapAliasesActions := `
+		{"remove": {"index": "blocks-000001", "alias": "blocks"}},
+		{"add": {"index": "blocks-000001` + suffix + `", "alias": "blocks"}},
+		{"remove": {"index": "transactions-000001", "alias": "transactions"}},
+		{"remove": {"index": "transactions-000002", "alias": "transactions"}},
+		{"add": {"index": "transactions-000001` + suffix + `", "alias": "transactions", "is_write_index": false}},
+		{"add": {"index": "transactions-000002` + suffix + `", "alias": "transactions", "is_write_index": true}}`
+
+	tests := []struct {
+		name            string
+		replaceIndices  bool
+		expectedActions string
+	}{
+		{
+			name:            "the index with the same name is kept",
+			replaceIndices:  false,
+			expectedActions: `{"actions": [` + swapAliasesActions + `]}`,
+		},
+		{
+			name:           "the index with the same name is replaced by an alias",
+			replaceIndices: true,
+			expectedActions: `{"actions": [` + swapAliasesActions + `,
+				{"remove_index": {"index": "values"}},
+				{"add": {"index": "values` + suffix + `", "alias": "values"}}
+			]}`,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			client := createTestClusterAndSnapshot()
+			sm := createTestSnapshotManager(t, client)
+
+			_, err := sm.Restore(ArgsRestore{Snapshot: testSnapshot, SwapAliases: true, ReplaceIndices: tt.replaceIndices})
+			if err != nil {
+				t.Fatal(err)
+			}
+			requireJSONEqual(t, tt.expectedActions, client.updateAliasesBody)
+		})
+	}
+}
+
+func TestSnapshotManager_RestoreShouldErr(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		updateClient func(client *elasticClientStub)
+		expectedErr  error
+	}{
+		{
+			name:         "unknown snapshot",
+			updateClient: func(client *elasticClientStub) { client.snapshots = nil },
+			expectedErr:  ErrSnapshotNotFound,
+		},
+		{
+			name:         "snapshot without metadata",
+			updateClient: func(client *elasticClientStub) { client.snapshots[0].Metadata = nil },
+			expectedErr:  ErrMissingSnapshotMetadata,
+		},
+		{
+			name: "restored index already exists",
+			updateClient: func(client *elasticClientStub) {
+				client.clusterAliases["values-"+testSnapshot] = []string{}
+			},
+			expectedErr: ErrIndexAlreadyExists,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			client := createTestClusterAndSnapshot()
+			tt.updateClient(client)
+			sm := createTestSnapshotManager(t, client)
+
+			_, err := sm.Restore(ArgsRestore{Snapshot: testSnapshot, SwapAliases: true})
+			if !errors.Is(err, tt.expectedErr) {
+				t.Fatalf("expected error %v, got %v", tt.expectedErr, err)
+			}
+			if client.restoreBody != nil || client.updateAliasesBody != nil {
+				t.Fatal("nothing should be restored")
+			}
+		})
+	}
+}
@@ -0,0 +1,33 @@
+package snapshot
+
+import "time"
+
+const (
+	stateSuccess    = "SUCCESS"
+	stateInProgress = "IN_PROGRESS"
+)
+
+// selectSnapshotsToDelete returns the snapshots which are not kept by the retention. The newest keepLast successful
+// snapshots are always kept, while the other snapshots are kept only if they are newer than the maximum age. A
+// maximum age of 0 keeps only the newest snapshots. The snapshots are expected sorted from the oldest one
+func selectSnapshotsToDelete(snapshots []*Info, now time.Time, keepLast int, maxAge time.Duration) []*Info {
+	toDelete := make([]*Info, 0)
+	numKept := 0
+	for i := len(snapshots) - 1; i >= 0; i-- {
+		snapshot := snapshots[i]
+		if snapshot.State == stateInProgress {
+			continue
+		}
+		if snapshot.State == stateSuccess && numKept < keepLast {
+			numKept++
+			continue
+		}
+		if maxAge > 0 && now.Sub(snapshot.StartTime()) <= maxAge {
+			continue
+		}
+
+		toDelete = append(toDelete, snapshot)
+	}
+
+	return toDelete
+}
@@ -0,0 +1,138 @@
+package snapshot
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+const stateFailed = "FAILED"
+
+var testNow = time.Date(2024, time.March, 10, 12, 0, 0, 0, time.UTC)
+
+func createTestSnapshot(name string, state string, age time.Duration) *Info {
+	return &Info{
+		Name:              name,
+		State:             state,
+		StartTimeInMillis: testNow.Add(-age).UnixNano() / int64(time.Millisecond),
+	}
+}
+
+func snapshotNames(snapshots []*Info) string {
+	names := make([]string, 0, len(snapshots))
+	for _, snapshot := range snapshots {
+		names = append(names, snapshot.Name)
+	}
+
+	return strings.Join(names, ",")
+}
+
+func TestSelectSnapshotsToDelete(t *testing.T) {
+	t.Parallel()
+
+	day := 24 * time.Hour
+	tests := []struct {
+		name             string
+		snapshots        []*Info
+		keepLast         int
+		maxAge           time.Duration
+		expectedToDelete string
+	}{
+		{
+			name:             "no snapshots",
+			keepLast:         2,
+			maxAge:           30 * day,
+			expectedToDelete: "",
+		},
+		{
+			name: "the newest snapshots are kept, the old ones deleted from the newest one",
+			snapshots: []*Info{
+				createTestSnapshot("s1", stateSuccess, 40*day),
+				createTestSnapshot("s2", stateSuccess, 35*day),
+				createTestSnapshot("s3", stateSuccess, 31*day),
+				createTestSnapshot("s4", stateSuccess, 10*day),
+			},
+			keepLast:         1,
+			maxAge:           30 * day,
+			expectedToDelete: "s3,s2,s1",
+		},
+		{
+			name: "the newest snapshots are kept even if they are old",
+			snapshots: []*Info{
+				createTestSnapshot("s1", stateSuccess, 50*day),
+				createTestSnapshot("s2", stateSuccess, 40*day),
+				createTestSnapshot("s3", stateSuccess, 35*day),
+			},
+			keepLast:         2,
+			maxAge:           30 * day,
+			expectedToDelete: "s1",
+		},
+		{
+			name: "snapshots newer than the maximum age are kept",
+			snapshots: []*Info{
+				createTestSnapshot("s1", stateSuccess, 31*day),
+				createTestSnapshot("s2", stateSuccess, 30*day),
+				createTestSnapshot("s3", stateSuccess, 2*day),
+				createTestSnapshot("s4", stateSuccess, day),
+			},
+			keepLast:         1,
+			maxAge:           30 * day,
+			expectedToDelete: "s1",
+		},
+		{
+			name: "zero maximum age keeps only the newest snapshots",
+			snapshots: []*Info{
+				createTestSnapshot("s1", stateSuccess, 3*day),
+				createTestSnapshot("s2", stateSuccess, 2*day),
+				createTestSnapshot("s3", stateSuccess, day),
+			},
+			keepLast:         1,
+			maxAge:           0,
+			expectedToDelete: "s2,s1",
+		},
+		{
+			name: "failed snapshots are not counted in the newest ones",
+			snapshots: []*Info{
+				createTestSnapshot("s1", stateSuccess, 40*day),
+				createTestSnapshot("s2", stateFailed, 35*day),
+				createTestSnapshot("s3", stateFailed, day),
+			},
+			keepLast:         1,
+			maxAge:           30 * day,
+			expectedToDelete: "s2",
+		},
+		{
+			name: "failed snapshots are deleted without maximum age",
+			snapshots: []*Info{
+				createTestSnapshot("s1", stateSuccess, 2*day),
+				createTestSnapshot("s2", stateFailed, day),
+			},
+			keepLast:         1,
+			maxAge:           0,
+			expectedToDelete: "s2",
+		},
+		{
+			name: "snapshots in progress are never deleted",
+			snapshots: []*Info{
+				createTestSnapshot("s1", stateInProgress, 40*day),
+				createTestSnapshot("s2", stateSuccess, 35*day),
+				createTestSnapshot("s3", stateInProgress, 0),
+			},
+			keepLast:         1,
+			maxAge:           0,
+			expectedToDelete: "",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			toDelete := selectSnapshotsToDelete(tt.snapshots, testNow, tt.keepLast, tt.maxAge)
+			if snapshotNames(toDelete) != tt.expectedToDelete {
+				t.Fatalf("expected to delete %q, got %q", tt.expectedToDelete, snapshotNames(toDelete))
+			}
+		})
+	}
+}